@@ -0,0 +1,11 @@
+package main
+
+import (
+	"os"
+
+	"github.com/local-mcps/dev-mcps/internal/cli"
+)
+
+func main() {
+	os.Exit(cli.Main(os.Args[1:], os.Stdout, os.Stderr))
+}
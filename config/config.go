@@ -5,11 +5,10 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-
-	"gopkg.in/yaml.v3"
 )
 
 type Config struct {
+	Include     []string          `yaml:"include,omitempty"`
 	Global      GlobalConfig      `yaml:"global"`
 	Filesystem  FilesystemConfig  `yaml:"filesystem"`
 	Command     CommandConfig     `yaml:"command"`
@@ -17,13 +16,53 @@ type Config struct {
 	Environment EnvironmentConfig `yaml:"environment"`
 	Git         GitConfig         `yaml:"git"`
 	Process     ProcessConfig     `yaml:"process"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Docker      DockerConfig      `yaml:"docker"`
+	Kubernetes  KubernetesConfig  `yaml:"kubernetes"`
+	SSH         SSHConfig         `yaml:"ssh"`
+	Packages    PackagesConfig    `yaml:"packages"`
+	Build       BuildConfig       `yaml:"build"`
+	Admin       AdminConfig       `yaml:"admin"`
+	Audit       AuditConfig       `yaml:"audit"`
+
+	// Sources lists every file that contributed to this config, includes
+	// first.
+	Sources []string `yaml:"-"`
 }
 
 type GlobalConfig struct {
-	LogLevel  string `yaml:"log_level"`
-	LogFormat string `yaml:"log_format"`
-	Transport string `yaml:"transport"`
-	HTTPPort  int    `yaml:"http_port"`
+	LogLevel        string `yaml:"log_level" enum:"debug,info,warn,warning,error"`
+	LogFormat       string `yaml:"log_format" enum:"json,text"`
+	Transport       string `yaml:"transport" enum:"stdio,http"`
+	StdioFraming    string `yaml:"stdio_framing" enum:"auto,newline,content-length"`
+	HTTPHost        string `yaml:"http_host"`
+	HTTPPort        int    `yaml:"http_port"`
+	HTTPPingSeconds int    `yaml:"http_ping_seconds"`
+	// HTTPAllowedHosts are host names, besides localhost and http_host,
+	// that HTTP requests may name in their Host and Origin headers.
+	HTTPAllowedHosts []string `yaml:"http_allowed_hosts"`
+	WatchConfig      bool     `yaml:"watch_config"`
+	NamespaceTools   bool     `yaml:"namespace_tools"`
+	StrictParams     bool     `yaml:"strict_params"`
+
+	ToolTimeoutSeconds     int            `yaml:"tool_timeout_seconds"`
+	ToolTimeouts           map[string]int `yaml:"tool_timeouts"`
+	ShutdownTimeoutSeconds int            `yaml:"shutdown_timeout_seconds"`
+	// RateLimits are keyed by tool name or, to limit all of a server's
+	// tools together, by server name such as "web". Tool entries win.
+	RateLimits map[string]RateLimit `yaml:"rate_limits"`
+
+	LogFile        string `yaml:"log_file"`
+	LogMaxSizeMB   int    `yaml:"log_max_size_mb"`
+	LogRotateHours int    `yaml:"log_rotate_hours"`
+	LogMaxBackups  int    `yaml:"log_max_backups"`
+	LogMaxAgeDays  int    `yaml:"log_max_age_days"`
+}
+
+type RateLimit struct {
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+	// Burst is how many calls may be made at once; at least 1.
+	Burst int `yaml:"burst"`
 }
 
 type FilesystemConfig struct {
@@ -32,6 +71,24 @@ type FilesystemConfig struct {
 	DeniedPaths    []string `yaml:"denied_paths"`
 	MaxFileSizeMB  int      `yaml:"max_file_size_mb"`
 	FollowSymlinks bool     `yaml:"follow_symlinks"`
+	// MaxArchiveSizeMB bounds the uncompressed size of an archive created
+	// or extracted.
+	MaxArchiveSizeMB int `yaml:"max_archive_size_mb"`
+	// DefaultExcludes are gitignore-style patterns that searches skip unless
+	// asked to include ignored files.
+	DefaultExcludes []string `yaml:"default_excludes"`
+	// RespectGitignore makes searches skip what .gitignore and .ignore
+	// files exclude.
+	RespectGitignore bool `yaml:"respect_gitignore"`
+	// TempDir holds the files create_temp_file and create_temp_dir make,
+	// one directory per client, removed when the client disconnects. It
+	// must be inside the allowed paths.
+	TempDir string `yaml:"temp_dir"`
+	// MaxWalkFiles and MaxWalkSeconds bound how many entries and how long
+	// search_files, grep and a recursive list_directory walk before they
+	// return what they found as truncated; 0 means no limit.
+	MaxWalkFiles   int `yaml:"max_walk_files"`
+	MaxWalkSeconds int `yaml:"max_walk_seconds"`
 }
 
 type CommandConfig struct {
@@ -42,26 +99,65 @@ type CommandConfig struct {
 	AllowedCommands       []string `yaml:"allowed_commands"`
 	DeniedCommands        []string `yaml:"denied_commands"`
 	WorkingDirectory      string   `yaml:"working_directory"`
+
+	// Policies are checked in order after denied_commands; the first one
+	// that matches a command decides. Commands no policy matches fall back
+	// to allowed_commands and then DefaultAction.
+	Policies      []CommandPolicy `yaml:"policies"`
+	DefaultAction string          `yaml:"default_action" enum:"allow,deny"`
+}
+
+type CommandPolicy struct {
+	Name   string `yaml:"name"`
+	Action string `yaml:"action" enum:"allow,deny"`
+	// Binary is a glob matched against the command's base name, or against
+	// the full path when it contains a slash.
+	Binary string `yaml:"binary"`
+	// Match is a regular expression matched against the whole command line.
+	Match string `yaml:"match"`
+	// AllowArgs and DenyArgs are regular expressions checked against each
+	// argument of allowed commands. AllowArgs must match the whole argument.
+	AllowArgs []string `yaml:"allow_args"`
+	DenyArgs  []string `yaml:"deny_args"`
+	MaxArgs   int      `yaml:"max_args"`
+	// Resolve is "absolute" to require an absolute command path, or "path"
+	// to require a bare name and match Binary against where PATH resolves it.
+	Resolve string `yaml:"resolve" enum:"any,absolute,path"`
 }
 
 type WebConfig struct {
-	Enabled              bool     `yaml:"enabled"`
-	UserAgent            string   `yaml:"user_agent"`
+	Enabled               bool     `yaml:"enabled"`
+	UserAgent             string   `yaml:"user_agent"`
 	DefaultTimeoutSeconds int      `yaml:"default_timeout_seconds"`
-	MaxResponseSizeBytes int      `yaml:"max_response_size_bytes"`
-	FollowRedirects      bool     `yaml:"follow_redirects"`
-	MaxRedirects         int      `yaml:"max_redirects"`
-	ProxyURL             string   `yaml:"proxy_url"`
-	AllowedDomains       []string `yaml:"allowed_domains"`
-	DeniedDomains        []string `yaml:"denied_domains"`
-	EnableJavascript     bool     `yaml:"enable_javascript"`
+	MaxResponseSizeBytes  int      `yaml:"max_response_size_bytes"`
+	FollowRedirects       bool     `yaml:"follow_redirects"`
+	MaxRedirects          int      `yaml:"max_redirects"`
+	ProxyURL              string   `yaml:"proxy_url" sensitive:"url"`
+	AllowedDomains        []string `yaml:"allowed_domains"`
+	DeniedDomains         []string `yaml:"denied_domains"`
+	EnableJavascript      bool     `yaml:"enable_javascript"`
 }
 
 type EnvironmentConfig struct {
-	Enabled            bool     `yaml:"enabled"`
-	ExposeAllEnv       bool     `yaml:"expose_all_env"`
-	AllowedEnvPrefixes []string `yaml:"allowed_env_prefixes"`
-	DeniedEnvPatterns  []string `yaml:"denied_env_patterns"`
+	Enabled            bool          `yaml:"enabled"`
+	ExposeAllEnv       bool          `yaml:"expose_all_env"`
+	AllowedEnvPrefixes []string      `yaml:"allowed_env_prefixes"`
+	DeniedEnvPatterns  []string      `yaml:"denied_env_patterns"`
+	ProtectSensitive   bool          `yaml:"protect_sensitive"`
+	MaxValueSizeBytes  int           `yaml:"max_value_size_bytes"`
+	PersistSession     bool          `yaml:"persist_session"`
+	SessionFile        string        `yaml:"session_file"`
+	AllowedExportPaths []string      `yaml:"allowed_export_paths"`
+	Secrets            SecretsConfig `yaml:"secrets"`
+}
+
+type SecretsConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	Backend        string   `yaml:"backend" enum:"keychain,vault"`
+	Service        string   `yaml:"service"`
+	VaultAddress   string   `yaml:"vault_address" sensitive:"url"`
+	VaultTokenEnv  string   `yaml:"vault_token_env"`
+	AllowedSecrets []string `yaml:"allowed_secrets"`
 }
 
 type GitConfig struct {
@@ -69,6 +165,7 @@ type GitConfig struct {
 	AllowedRepositories []string `yaml:"allowed_repositories"`
 	AllowPush           bool     `yaml:"allow_push"`
 	AllowForcePush      bool     `yaml:"allow_force_push"`
+	AllowDestructive    bool     `yaml:"allow_destructive"`
 	DefaultAuthorName   string   `yaml:"default_author_name"`
 	DefaultAuthorEmail  string   `yaml:"default_author_email"`
 	SignCommits         bool     `yaml:"sign_commits"`
@@ -82,22 +179,140 @@ type ProcessConfig struct {
 	MaxListResults     int      `yaml:"max_list_results"`
 }
 
+type DatabaseConfig struct {
+	Enabled             bool                 `yaml:"enabled"`
+	Connections         []DatabaseConnection `yaml:"connections"`
+	MaxRows             int                  `yaml:"max_rows"`
+	MaxResultBytes      int                  `yaml:"max_result_bytes"`
+	QueryTimeoutSeconds int                  `yaml:"query_timeout_seconds"`
+}
+
+type DatabaseConnection struct {
+	Name   string `yaml:"name"`
+	Driver string `yaml:"driver" enum:"sqlite,postgres"`
+	// Path is the database file for sqlite; DSN the connection URL for
+	// postgres.
+	Path        string `yaml:"path"`
+	DSN         string `yaml:"dsn" sensitive:"url"`
+	AllowWrites bool   `yaml:"allow_writes"`
+}
+
+type DockerConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Host is the Docker Engine API endpoint: unix:///path or tcp://host:port.
+	Host string `yaml:"host"`
+	// AllowedProjectPaths bounds compose project directories and build
+	// contexts.
+	AllowedProjectPaths []string `yaml:"allowed_project_paths"`
+	AllowExec           bool     `yaml:"allow_exec"`
+	MaxLogLines         int      `yaml:"max_log_lines"`
+	MaxOutputSizeBytes  int      `yaml:"max_output_size_bytes"`
+	TimeoutSeconds      int      `yaml:"timeout_seconds"`
+}
+
+type KubernetesConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Kubeconfig is passed to kubectl; empty uses kubectl's own default.
+	Kubeconfig string `yaml:"kubeconfig"`
+	// AllowedContexts and AllowedNamespaces are empty to allow any. When
+	// set, the first entry is the default.
+	AllowedContexts   []string `yaml:"allowed_contexts"`
+	AllowedNamespaces []string `yaml:"allowed_namespaces"`
+	AllowPortForward  bool     `yaml:"allow_port_forward"`
+	MaxLogLines       int      `yaml:"max_log_lines"`
+	TimeoutSeconds    int      `yaml:"timeout_seconds"`
+}
+
+type SSHConfig struct {
+	Enabled bool      `yaml:"enabled"`
+	Hosts   []SSHHost `yaml:"hosts"`
+	// Policies and DefaultAction decide which remote commands may run, as
+	// for the command server. resolve: path is not supported remotely.
+	Policies      []CommandPolicy `yaml:"policies"`
+	DefaultAction string          `yaml:"default_action" enum:"allow,deny"`
+	// AllowedLocalPaths bounds the local side of file transfers.
+	AllowedLocalPaths     []string `yaml:"allowed_local_paths"`
+	StrictHostKeyChecking string   `yaml:"strict_host_key_checking" enum:"yes,accept-new"`
+	ConnectTimeoutSeconds int      `yaml:"connect_timeout_seconds"`
+	DefaultTimeoutSeconds int      `yaml:"default_timeout_seconds"`
+	MaxOutputSizeBytes    int      `yaml:"max_output_size_bytes"`
+}
+
+type SSHHost struct {
+	// Name is the alias tools refer to the host by.
+	Name     string `yaml:"name"`
+	Hostname string `yaml:"hostname"`
+	User     string `yaml:"user"`
+	Port     int    `yaml:"port"`
+	KeyPath  string `yaml:"key_path"`
+}
+
+type PackagesConfig struct {
+	Enabled             bool     `yaml:"enabled"`
+	AllowedProjectPaths []string `yaml:"allowed_project_paths"`
+	// AllowModify enables add_dependency and upgrade_dependency.
+	AllowModify    bool `yaml:"allow_modify"`
+	TimeoutSeconds int  `yaml:"timeout_seconds"`
+}
+
+type BuildConfig struct {
+	Enabled             bool     `yaml:"enabled"`
+	AllowedProjectPaths []string `yaml:"allowed_project_paths"`
+	TimeoutSeconds      int      `yaml:"timeout_seconds"`
+	// MaxOutputSizeBytes limits the output kept for each failure and for
+	// runs that fail before any test does.
+	MaxOutputSizeBytes int `yaml:"max_output_size_bytes"`
+}
+
+type AuditConfig struct {
+	Enabled    bool   `yaml:"enabled"`
+	File       string `yaml:"file"`
+	MaxSizeMB  int    `yaml:"max_size_mb"`
+	MaxBackups int    `yaml:"max_backups"`
+	// AllCalls records read-only tool calls too, not just mutating ones.
+	// It is on by default so the trail covers everything an agent did.
+	AllCalls bool `yaml:"all_calls"`
+}
+
+type AdminConfig struct {
+	Enabled         bool     `yaml:"enabled"`
+	TokenEnv        string   `yaml:"token_env"`
+	AllowedSettings []string `yaml:"allowed_settings"`
+	OverridesFile   string   `yaml:"overrides_file"`
+}
+
 func DefaultConfig() *Config {
 	homeDir, _ := os.UserHomeDir()
 
 	return &Config{
 		Global: GlobalConfig{
-			LogLevel:  "info",
-			LogFormat: "json",
-			Transport: "stdio",
-			HTTPPort:  8080,
+			LogLevel:     "info",
+			LogFormat:    "json",
+			Transport:    "stdio",
+			StdioFraming: "auto",
+			HTTPHost:     "127.0.0.1",
+			HTTPPort:     8080,
+			WatchConfig:  true,
+
+			ToolTimeoutSeconds:     1800,
+			ShutdownTimeoutSeconds: 10,
+
+			LogMaxSizeMB:  10,
+			LogMaxBackups: 5,
+			LogMaxAgeDays: 30,
 		},
 		Filesystem: FilesystemConfig{
-			Enabled:        true,
-			AllowedPaths:   []string{homeDir},
-			DeniedPaths:    []string{filepath.Join(homeDir, ".ssh"), filepath.Join(homeDir, ".gnupg")},
-			MaxFileSizeMB:  50,
-			FollowSymlinks: false,
+			Enabled:          true,
+			AllowedPaths:     []string{homeDir},
+			DeniedPaths:      []string{filepath.Join(homeDir, ".ssh"), filepath.Join(homeDir, ".gnupg")},
+			MaxFileSizeMB:    50,
+			FollowSymlinks:   false,
+			MaxArchiveSizeMB: 1024,
+			DefaultExcludes:  []string{".git/", "node_modules/", "__pycache__/", ".venv/", "dist/", "build/", "target/"},
+			RespectGitignore: true,
+			TempDir:          filepath.Join(homeDir, ".cache", "local-mcps", "tmp"),
+			MaxWalkFiles:     200000,
+			MaxWalkSeconds:   30,
 		},
 		Command: CommandConfig{
 			Enabled:               true,
@@ -106,30 +321,44 @@ func DefaultConfig() *Config {
 			MaxOutputSizeBytes:    10485760,
 			AllowedCommands:       []string{},
 			DeniedCommands:        []string{"rm -rf /", "sudo"},
+			DefaultAction:         "allow",
 			WorkingDirectory:      homeDir,
 		},
 		Web: WebConfig{
-			Enabled:              true,
-			UserAgent:            "LocalMCP-WebBrowser/1.0",
+			Enabled:               true,
+			UserAgent:             "LocalMCP-WebBrowser/1.0",
 			DefaultTimeoutSeconds: 30,
-			MaxResponseSizeBytes: 52428800,
-			FollowRedirects:      true,
-			MaxRedirects:         10,
-			AllowedDomains:       []string{},
-			DeniedDomains:        []string{},
-			EnableJavascript:     false,
+			MaxResponseSizeBytes:  52428800,
+			FollowRedirects:       true,
+			MaxRedirects:          10,
+			AllowedDomains:        []string{},
+			DeniedDomains:         []string{},
+			EnableJavascript:      false,
 		},
 		Environment: EnvironmentConfig{
 			Enabled:            true,
 			ExposeAllEnv:       false,
 			AllowedEnvPrefixes: []string{"PATH", "HOME", "USER", "GOPATH", "NODE_", "NPM_"},
 			DeniedEnvPatterns:  []string{".*_KEY$", ".*_SECRET$", ".*_TOKEN$", ".*_PASSWORD$"},
+			ProtectSensitive:   true,
+			MaxValueSizeBytes:  32768,
+			PersistSession:     false,
+			SessionFile:        filepath.Join(ConfigDir(), "session-env.json"),
+			AllowedExportPaths: []string{},
+			Secrets: SecretsConfig{
+				Enabled:        false,
+				Backend:        "keychain",
+				Service:        "local-mcps",
+				VaultTokenEnv:  "VAULT_TOKEN",
+				AllowedSecrets: []string{},
+			},
 		},
 		Git: GitConfig{
 			Enabled:             true,
 			AllowedRepositories: []string{homeDir},
 			AllowPush:           true,
 			AllowForcePush:      false,
+			AllowDestructive:    false,
 			DefaultAuthorName:   "MCP Agent",
 			DefaultAuthorEmail:  "mcp@localhost",
 			SignCommits:         false,
@@ -141,33 +370,119 @@ func DefaultConfig() *Config {
 			DeniedProcessNames: []string{"init", "systemd", "launchd"},
 			MaxListResults:     1000,
 		},
+		Database: DatabaseConfig{
+			Enabled:             false,
+			Connections:         []DatabaseConnection{},
+			MaxRows:             1000,
+			MaxResultBytes:      1048576,
+			QueryTimeoutSeconds: 30,
+		},
+		Docker: DockerConfig{
+			Enabled:             false,
+			Host:                "unix:///var/run/docker.sock",
+			AllowedProjectPaths: []string{homeDir},
+			AllowExec:           false,
+			MaxLogLines:         1000,
+			MaxOutputSizeBytes:  1048576,
+			TimeoutSeconds:      600,
+		},
+		Kubernetes: KubernetesConfig{
+			Enabled:           false,
+			AllowedContexts:   []string{},
+			AllowedNamespaces: []string{"default"},
+			AllowPortForward:  false,
+			MaxLogLines:       1000,
+			TimeoutSeconds:    30,
+		},
+		SSH: SSHConfig{
+			Enabled:               false,
+			Hosts:                 []SSHHost{},
+			Policies:              []CommandPolicy{},
+			DefaultAction:         "allow",
+			AllowedLocalPaths:     []string{homeDir},
+			StrictHostKeyChecking: "yes",
+			ConnectTimeoutSeconds: 10,
+			DefaultTimeoutSeconds: 300,
+			MaxOutputSizeBytes:    10485760,
+		},
+		Packages: PackagesConfig{
+			Enabled:             false,
+			AllowedProjectPaths: []string{homeDir},
+			AllowModify:         false,
+			TimeoutSeconds:      300,
+		},
+		Build: BuildConfig{
+			Enabled:             false,
+			AllowedProjectPaths: []string{homeDir},
+			TimeoutSeconds:      900,
+			MaxOutputSizeBytes:  65536,
+		},
+		Admin: AdminConfig{
+			Enabled:  false,
+			TokenEnv: "LOCAL_MCP_ADMIN_TOKEN",
+			AllowedSettings: []string{
+				"filesystem.allowed_paths",
+				"filesystem.denied_paths",
+				"filesystem.max_file_size_mb",
+				"command.default_timeout_seconds",
+				"command.max_output_size_bytes",
+				"command.allowed_commands",
+				"command.denied_commands",
+				"web.default_timeout_seconds",
+				"web.allowed_domains",
+				"web.denied_domains",
+				"git.allowed_repositories",
+				"environment.allowed_env_prefixes",
+			},
+			OverridesFile: filepath.Join(ConfigDir(), "admin-overrides.yaml"),
+		},
+		Audit: AuditConfig{
+			Enabled:    true,
+			File:       filepath.Join(ConfigDir(), "audit.log"),
+			MaxSizeMB:  10,
+			MaxBackups: 5,
+			AllCalls:   true,
+		},
 	}
 }
 
 func LoadConfig(path string) (*Config, error) {
 	config := DefaultConfig()
 
-	if path == "" {
-		configDir, err := os.UserConfigDir()
-		if err == nil {
-			path = filepath.Join(configDir, "local-mcps", "config.yaml")
-		}
-	}
-
-	if path != "" {
-		data, err := os.ReadFile(path)
-		if err == nil {
-			if err := yaml.Unmarshal(data, config); err != nil {
+	if path = ResolvePath(path); path != "" {
+		if _, err := os.Stat(path); err == nil {
+			l := &loader{}
+			if err := l.load(config, path); err != nil {
 				return nil, err
 			}
+			config.Sources = l.sources
 		}
 	}
 
 	applyEnvOverrides(config)
+	config.ExpandPaths()
 
 	return config, nil
 }
 
+func ResolvePath(path string) string {
+	if path != "" {
+		return path
+	}
+	if configDir := ConfigDir(); configDir != "" {
+		return filepath.Join(configDir, "config.yaml")
+	}
+	return ""
+}
+
+func ConfigDir() string {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(configDir, "local-mcps")
+}
+
 func applyEnvOverrides(config *Config) {
 	if v := os.Getenv("LOCAL_MCP_LOG_LEVEL"); v != "" {
 		config.Global.LogLevel = v
@@ -175,9 +490,15 @@ func applyEnvOverrides(config *Config) {
 	if v := os.Getenv("LOCAL_MCP_LOG_FORMAT"); v != "" {
 		config.Global.LogFormat = v
 	}
+	if v := os.Getenv("LOCAL_MCP_LOG_FILE"); v != "" {
+		config.Global.LogFile = v
+	}
 	if v := os.Getenv("LOCAL_MCP_TRANSPORT"); v != "" {
 		config.Global.Transport = v
 	}
+	if v := os.Getenv("LOCAL_MCP_HTTP_HOST"); v != "" {
+		config.Global.HTTPHost = v
+	}
 	if v := os.Getenv("LOCAL_MCP_HTTP_PORT"); v != "" {
 		if port, err := strconv.Atoi(v); err == nil {
 			config.Global.HTTPPort = port
@@ -193,15 +514,56 @@ func applyEnvOverrides(config *Config) {
 	}
 }
 
+// ExpandPaths expands ~ and $VARS in path settings and makes them absolute
+// and clean, so validators compare like with like.
 func (c *Config) ExpandPaths() {
-	for i, p := range c.Filesystem.AllowedPaths {
-		c.Filesystem.AllowedPaths[i] = os.ExpandEnv(p)
+	c.Global.LogFile = normalizePath(c.Global.LogFile)
+	c.Filesystem.AllowedPaths = normalizePaths(c.Filesystem.AllowedPaths)
+	c.Filesystem.DeniedPaths = normalizePaths(c.Filesystem.DeniedPaths)
+	c.Filesystem.TempDir = normalizePath(c.Filesystem.TempDir)
+	c.Command.WorkingDirectory = normalizePath(c.Command.WorkingDirectory)
+	c.Environment.SessionFile = normalizePath(c.Environment.SessionFile)
+	c.Environment.AllowedExportPaths = normalizePaths(c.Environment.AllowedExportPaths)
+	c.Git.AllowedRepositories = normalizePaths(c.Git.AllowedRepositories)
+	c.Docker.AllowedProjectPaths = normalizePaths(c.Docker.AllowedProjectPaths)
+	c.Kubernetes.Kubeconfig = normalizePath(c.Kubernetes.Kubeconfig)
+	c.SSH.AllowedLocalPaths = normalizePaths(c.SSH.AllowedLocalPaths)
+	for i := range c.SSH.Hosts {
+		c.SSH.Hosts[i].KeyPath = normalizePath(c.SSH.Hosts[i].KeyPath)
 	}
-	for i, p := range c.Filesystem.DeniedPaths {
-		c.Filesystem.DeniedPaths[i] = os.ExpandEnv(p)
+	c.Packages.AllowedProjectPaths = normalizePaths(c.Packages.AllowedProjectPaths)
+	c.Build.AllowedProjectPaths = normalizePaths(c.Build.AllowedProjectPaths)
+	c.Admin.OverridesFile = normalizePath(c.Admin.OverridesFile)
+	c.Audit.File = normalizePath(c.Audit.File)
+	for i := range c.Database.Connections {
+		c.Database.Connections[i].Path = normalizePath(c.Database.Connections[i].Path)
+	}
+}
+
+// normalizePaths normalizes a list of allowed or denied paths. Relative
+// glob patterns, such as *.pem or **/.env, match at any depth and are kept
+// relative.
+func normalizePaths(paths []string) []string {
+	out := make([]string, 0, len(paths))
+	for _, p := range paths {
+		if expanded := expandPath(p); !filepath.IsAbs(expanded) && strings.ContainsAny(expanded, "*?[") {
+			out = append(out, filepath.Clean(expanded))
+			continue
+		}
+		if p = normalizePath(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
+
+func normalizePath(path string) string {
+	path = expandPath(path)
+	if path == "" {
+		return ""
 	}
-	c.Command.WorkingDirectory = os.ExpandEnv(c.Command.WorkingDirectory)
-	for i, p := range c.Git.AllowedRepositories {
-		c.Git.AllowedRepositories[i] = os.ExpandEnv(p)
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
 	}
+	return filepath.Clean(path)
 }
@@ -0,0 +1,42 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandPaths(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	require.NoError(t, err)
+	t.Setenv("MCP_TEST_ROOT", "/srv/projects")
+
+	cfg := DefaultConfig()
+	cfg.Filesystem.AllowedPaths = []string{"~/projects", "$MCP_TEST_ROOT/app/../lib", "", "~"}
+	cfg.Filesystem.DeniedPaths = []string{"**/.env", "*.pem", "~/projects/*/secrets"}
+	cfg.Command.WorkingDirectory = ""
+	cfg.ExpandPaths()
+
+	assert.Equal(t, []string{
+		filepath.Join(homeDir, "projects"),
+		"/srv/projects/lib",
+		homeDir,
+	}, cfg.Filesystem.AllowedPaths)
+	assert.Equal(t, []string{"**/.env", "*.pem", filepath.Join(homeDir, "projects/*/secrets")}, cfg.Filesystem.DeniedPaths)
+	assert.Equal(t, "", cfg.Command.WorkingDirectory)
+}
+
+func TestLoadConfigExpandsPaths(t *testing.T) {
+	homeDir, err := os.UserHomeDir()
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("filesystem:\n  allowed_paths: [\"~/code\"]\n"), 0600))
+
+	cfg, err := LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(homeDir, "code")}, cfg.Filesystem.AllowedPaths)
+}
@@ -0,0 +1,6 @@
+package config
+
+import _ "embed"
+
+//go:embed config.yaml.example
+var Example []byte
@@ -0,0 +1,21 @@
+//go:build ignore
+
+package main
+
+import (
+	"encoding/json"
+	"log"
+	"os"
+
+	"github.com/local-mcps/dev-mcps/config"
+)
+
+func main() {
+	data, err := json.MarshalIndent(config.GenerateSchema(), "", "  ")
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(config.SchemaFile, append(data, '\n'), 0644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const maxIncludeDepth = 16
+
+// loader applies a config file on top of config after first applying the
+// files it includes, in order, so later files win field by field.
+type loader struct {
+	strict  bool
+	stack   []string
+	sources []string
+}
+
+func (l *loader) load(config *Config, path string) error {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range l.stack {
+		if p == abs {
+			return fmt.Errorf("include cycle: %s", strings.Join(append(l.stack, abs), " -> "))
+		}
+	}
+	if len(l.stack) >= maxIncludeDepth {
+		return fmt.Errorf("includes nested deeper than %d levels at %s", maxIncludeDepth, abs)
+	}
+
+	data, err := os.ReadFile(abs)
+	if err != nil {
+		return err
+	}
+
+	var header struct {
+		Include []string `yaml:"include"`
+	}
+	if err := yaml.Unmarshal(data, &header); err != nil {
+		return fmt.Errorf("%s: %w", abs, err)
+	}
+
+	l.stack = append(l.stack, abs)
+	defer func() { l.stack = l.stack[:len(l.stack)-1] }()
+
+	for _, include := range header.Include {
+		target := expandPath(include)
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(filepath.Dir(abs), target)
+		}
+		if err := l.load(config, target); err != nil {
+			return fmt.Errorf("%s: include %s: %w", abs, include, err)
+		}
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(l.strict)
+	if err := decoder.Decode(config); err != nil && !errors.Is(err, io.EOF) {
+		if l.strict {
+			return fmt.Errorf("%s: %w (allowed keys are described by %s; print it with `config schema`)", abs, err, SchemaFile)
+		}
+		return fmt.Errorf("%s: %w", abs, err)
+	}
+	config.Include = header.Include
+
+	l.sources = append(l.sources, abs)
+	return nil
+}
+
+// expandPath expands a leading ~ and $VARS.
+func expandPath(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") || strings.HasPrefix(path, `~\`) {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			path = homeDir + path[1:]
+		}
+	}
+	return os.ExpandEnv(path)
+}
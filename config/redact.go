@@ -0,0 +1,82 @@
+package config
+
+import (
+	"net/url"
+	"reflect"
+)
+
+const redacted = "REDACTED"
+
+// Redact returns a copy of v, a Config or one of its sections, with every
+// string field tagged `sensitive` masked. `sensitive:"url"` keeps the URL but
+// hides an embedded password; any other value hides the whole field.
+func Redact(v interface{}) interface{} {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return v
+		}
+		out := reflect.New(rv.Elem().Type())
+		out.Elem().Set(redactValue(rv.Elem()))
+		return out.Interface()
+	}
+	return redactValue(rv).Interface()
+}
+
+func (c *Config) Redacted() *Config {
+	return Redact(c).(*Config)
+}
+
+func redactValue(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Struct {
+		return v
+	}
+
+	out := reflect.New(v.Type()).Elem()
+	out.Set(v)
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		dst := out.Field(i)
+		switch kind := field.Tag.Get("sensitive"); {
+		case kind != "" && dst.Kind() == reflect.String:
+			dst.SetString(RedactString(kind, dst.String()))
+		case dst.Kind() == reflect.Struct:
+			dst.Set(redactValue(dst))
+		case dst.Kind() == reflect.Slice && dst.Type().Elem().Kind() == reflect.Struct && !dst.IsNil():
+			items := reflect.MakeSlice(dst.Type(), dst.Len(), dst.Len())
+			for j := 0; j < dst.Len(); j++ {
+				items.Index(j).Set(redactValue(dst.Index(j)))
+			}
+			dst.Set(items)
+		}
+	}
+	return out
+}
+
+func RedactString(kind, value string) string {
+	if value == "" {
+		return ""
+	}
+	if kind == "url" {
+		return RedactURL(value)
+	}
+	return redacted
+}
+
+// RedactURL hides any password embedded in a URL.
+func RedactURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return redacted
+	}
+	if u.User == nil {
+		return raw
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redacted)
+	}
+	return u.String()
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSchemaUpToDate(t *testing.T) {
+	generated, err := json.MarshalIndent(GenerateSchema(), "", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, string(generated)+"\n", string(Schema), "config.schema.json is stale; run go generate ./config")
+}
+
+func TestSchemaCoversExample(t *testing.T) {
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(Schema, &schema))
+
+	properties := schema["properties"].(map[string]interface{})
+	for _, section := range []string{"include", "global", "filesystem", "command", "web", "environment", "git", "process"} {
+		assert.Contains(t, properties, section)
+	}
+
+	global := properties["global"].(map[string]interface{})["properties"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"stdio", "http"}, global["transport"].(map[string]interface{})["enum"])
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// Setting returns the current value of the field at a dotted yaml path such
+// as "filesystem.allowed_paths".
+func (c *Config) Setting(path string) (interface{}, error) {
+	field, err := c.settingField(path)
+	if err != nil {
+		return nil, err
+	}
+	return field.Interface(), nil
+}
+
+// ApplySetting changes the field at a dotted yaml path. op is "set" for any
+// field, or "add"/"remove" for list fields. Lists are always rebuilt so
+// copies of the config never share backing arrays.
+func (c *Config) ApplySetting(path, op string, value interface{}) error {
+	field, err := c.settingField(path)
+	if err != nil {
+		return err
+	}
+
+	switch field.Kind() {
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("%s: unsupported list type", path)
+		}
+		items, err := toStrings(value)
+		if err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		current := field.Interface().([]string)
+		var next []string
+		switch op {
+		case "set":
+			next = append([]string{}, items...)
+		case "add":
+			next = append([]string{}, current...)
+			for _, item := range items {
+				if !containsString(next, item) {
+					next = append(next, item)
+				}
+			}
+		case "remove":
+			next = []string{}
+			for _, existing := range current {
+				if !containsString(items, existing) {
+					next = append(next, existing)
+				}
+			}
+		default:
+			return fmt.Errorf("%s: unknown operation %q", path, op)
+		}
+		field.Set(reflect.ValueOf(next))
+		return nil
+	}
+
+	if op != "set" {
+		return fmt.Errorf("%s: %q only applies to lists", path, op)
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		s, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected a string", path)
+		}
+		field.SetString(s)
+	case reflect.Bool:
+		b, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("%s: expected a boolean", path)
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		var n int64
+		switch v := value.(type) {
+		case int:
+			n = int64(v)
+		case int64:
+			n = v
+		case float64:
+			if v != math.Trunc(v) {
+				return fmt.Errorf("%s: expected an integer", path)
+			}
+			n = int64(v)
+		default:
+			return fmt.Errorf("%s: expected an integer", path)
+		}
+		field.SetInt(n)
+	default:
+		return fmt.Errorf("%s: unsupported setting type", path)
+	}
+	return nil
+}
+
+func (c *Config) settingField(path string) (reflect.Value, error) {
+	v := reflect.ValueOf(c).Elem()
+	for _, part := range strings.Split(path, ".") {
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("unknown setting: %s", path)
+		}
+		found := false
+		for i := 0; i < v.NumField(); i++ {
+			name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+			if name == part && name != "-" {
+				v = v.Field(i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return reflect.Value{}, fmt.Errorf("unknown setting: %s", path)
+		}
+	}
+	if v.Kind() == reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("%s is a section, not a setting", path)
+	}
+	return v, nil
+}
+
+func toStrings(value interface{}) ([]string, error) {
+	switch v := value.(type) {
+	case string:
+		return []string{v}, nil
+	case []string:
+		return v, nil
+	case []interface{}:
+		out := make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("expected a list of strings")
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("expected a string or list of strings")
+	}
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
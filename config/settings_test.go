@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplySetting(t *testing.T) {
+	t.Run("add and remove list items", func(t *testing.T) {
+		cfg := DefaultConfig()
+		cfg.Filesystem.AllowedPaths = []string{"/a"}
+		original := cfg.Filesystem.AllowedPaths
+
+		require.NoError(t, cfg.ApplySetting("filesystem.allowed_paths", "add", []interface{}{"/b", "/a"}))
+		assert.Equal(t, []string{"/a", "/b"}, cfg.Filesystem.AllowedPaths)
+		assert.Equal(t, []string{"/a"}, original)
+
+		require.NoError(t, cfg.ApplySetting("filesystem.allowed_paths", "remove", "/a"))
+		assert.Equal(t, []string{"/b"}, cfg.Filesystem.AllowedPaths)
+	})
+
+	t.Run("set scalar values", func(t *testing.T) {
+		cfg := DefaultConfig()
+		require.NoError(t, cfg.ApplySetting("command.default_timeout_seconds", "set", float64(600)))
+		assert.Equal(t, 600, cfg.Command.DefaultTimeoutSeconds)
+
+		value, err := cfg.Setting("command.default_timeout_seconds")
+		require.NoError(t, err)
+		assert.Equal(t, 600, value)
+	})
+
+	t.Run("rejects bad input", func(t *testing.T) {
+		cfg := DefaultConfig()
+		assert.Error(t, cfg.ApplySetting("command.default_timeout_seconds", "set", 1.5))
+		assert.Error(t, cfg.ApplySetting("command.default_timeout_seconds", "add", float64(1)))
+		assert.Error(t, cfg.ApplySetting("command.nope", "set", "x"))
+		assert.Error(t, cfg.ApplySetting("command", "set", "x"))
+	})
+}
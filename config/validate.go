@@ -0,0 +1,345 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// LoadConfigStrict behaves like LoadConfig but rejects unknown keys and a
+// missing file, which is what `config validate` wants to surface.
+func LoadConfigStrict(path string) (*Config, error) {
+	config := DefaultConfig()
+
+	l := &loader{strict: true}
+	if err := l.load(config, ResolvePath(path)); err != nil {
+		return nil, err
+	}
+	config.Sources = l.sources
+
+	applyEnvOverrides(config)
+	config.ExpandPaths()
+
+	return config, nil
+}
+
+func (c *Config) Validate() error {
+	var errs []error
+	add := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	switch c.Global.LogLevel {
+	case "debug", "info", "warn", "warning", "error":
+	default:
+		add("global.log_level: unknown level %q", c.Global.LogLevel)
+	}
+	switch c.Global.LogFormat {
+	case "json", "text":
+	default:
+		add("global.log_format: unknown format %q", c.Global.LogFormat)
+	}
+	switch c.Global.Transport {
+	case "stdio", "http":
+	default:
+		add("global.transport: unknown transport %q", c.Global.Transport)
+	}
+	switch c.Global.StdioFraming {
+	case "auto", "newline", "content-length":
+	default:
+		add("global.stdio_framing: unknown framing %q", c.Global.StdioFraming)
+	}
+	if c.Global.Transport == "http" && c.Global.HTTPHost == "" {
+		add("global.http_host: must be set for the http transport")
+	}
+	if c.Global.HTTPPort < 1 || c.Global.HTTPPort > 65535 {
+		add("global.http_port: %d is out of range", c.Global.HTTPPort)
+	}
+	if c.Global.HTTPPingSeconds < 0 {
+		add("global.http_ping_seconds: must not be negative")
+	}
+	if c.Global.ToolTimeoutSeconds < 0 {
+		add("global.tool_timeout_seconds: must not be negative")
+	}
+	if c.Global.ShutdownTimeoutSeconds < 0 {
+		add("global.shutdown_timeout_seconds: must not be negative")
+	}
+	for _, tool := range sortedKeys(c.Global.ToolTimeouts) {
+		if c.Global.ToolTimeouts[tool] < 0 {
+			add("global.tool_timeouts.%s: must not be negative", tool)
+		}
+	}
+	for _, key := range sortedKeys(c.Global.RateLimits) {
+		limit := c.Global.RateLimits[key]
+		if limit.RequestsPerMinute < 1 {
+			add("global.rate_limits.%s.requests_per_minute: must be at least 1", key)
+		}
+		if limit.Burst < 0 {
+			add("global.rate_limits.%s.burst: must not be negative", key)
+		}
+	}
+	if c.Global.LogMaxSizeMB < 0 {
+		add("global.log_max_size_mb: must not be negative")
+	}
+	if c.Global.LogRotateHours < 0 {
+		add("global.log_rotate_hours: must not be negative")
+	}
+	if c.Global.LogMaxBackups < 0 {
+		add("global.log_max_backups: must not be negative")
+	}
+	if c.Global.LogMaxAgeDays < 0 {
+		add("global.log_max_age_days: must not be negative")
+	}
+
+	if c.Filesystem.MaxFileSizeMB <= 0 {
+		add("filesystem.max_file_size_mb: must be positive")
+	}
+	if c.Filesystem.MaxArchiveSizeMB <= 0 {
+		add("filesystem.max_archive_size_mb: must be positive")
+	}
+	if c.Filesystem.MaxWalkFiles < 0 {
+		add("filesystem.max_walk_files: must not be negative")
+	}
+	if c.Filesystem.MaxWalkSeconds < 0 {
+		add("filesystem.max_walk_seconds: must not be negative")
+	}
+	for _, p := range c.Filesystem.AllowedPaths {
+		if _, err := filepath.Match(p, ""); err != nil {
+			add("filesystem.allowed_paths: invalid pattern %q", p)
+		}
+	}
+	for _, p := range c.Filesystem.DeniedPaths {
+		if _, err := filepath.Match(p, ""); err != nil {
+			add("filesystem.denied_paths: invalid pattern %q", p)
+		}
+	}
+	for _, pattern := range c.Filesystem.DefaultExcludes {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			add("filesystem.default_excludes: invalid pattern %q", pattern)
+		}
+	}
+
+	if c.Command.DefaultTimeoutSeconds <= 0 {
+		add("command.default_timeout_seconds: must be positive")
+	}
+	if c.Command.MaxOutputSizeBytes <= 0 {
+		add("command.max_output_size_bytes: must be positive")
+	}
+	validatePolicies(add, "command", c.Command.Policies, c.Command.DefaultAction)
+
+	if c.Web.DefaultTimeoutSeconds <= 0 {
+		add("web.default_timeout_seconds: must be positive")
+	}
+	if c.Web.MaxResponseSizeBytes <= 0 {
+		add("web.max_response_size_bytes: must be positive")
+	}
+	if c.Web.MaxRedirects < 0 {
+		add("web.max_redirects: must not be negative")
+	}
+	if c.Web.ProxyURL != "" {
+		if u, err := url.Parse(c.Web.ProxyURL); err != nil || u.Scheme == "" || u.Host == "" {
+			add("web.proxy_url: %q is not an absolute URL", RedactURL(c.Web.ProxyURL))
+		}
+	}
+
+	for _, pattern := range c.Environment.DeniedEnvPatterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			add("environment.denied_env_patterns: %q: %v", pattern, err)
+		}
+	}
+	if c.Environment.MaxValueSizeBytes < 0 {
+		add("environment.max_value_size_bytes: must not be negative")
+	}
+	switch c.Environment.Secrets.Backend {
+	case "", "keychain", "vault":
+	default:
+		add("environment.secrets.backend: unknown backend %q", c.Environment.Secrets.Backend)
+	}
+
+	if c.Process.MaxListResults <= 0 {
+		add("process.max_list_results: must be positive")
+	}
+
+	if c.Database.MaxRows <= 0 {
+		add("database.max_rows: must be positive")
+	}
+	if c.Database.MaxResultBytes <= 0 {
+		add("database.max_result_bytes: must be positive")
+	}
+	if c.Database.QueryTimeoutSeconds <= 0 {
+		add("database.query_timeout_seconds: must be positive")
+	}
+	names := make(map[string]bool)
+	for i, conn := range c.Database.Connections {
+		field := fmt.Sprintf("database.connections[%d]", i)
+		switch {
+		case conn.Name == "":
+			add("%s.name: required", field)
+		case names[conn.Name]:
+			add("%s.name: duplicate connection %q", field, conn.Name)
+		}
+		names[conn.Name] = true
+		switch conn.Driver {
+		case "sqlite":
+			if conn.Path == "" {
+				add("%s.path: required for sqlite", field)
+			}
+		case "postgres":
+			if conn.DSN == "" {
+				add("%s.dsn: required for postgres", field)
+			}
+		default:
+			add("%s.driver: must be sqlite or postgres, got %q", field, conn.Driver)
+		}
+	}
+
+	if !strings.HasPrefix(c.Docker.Host, "unix://") && !strings.HasPrefix(c.Docker.Host, "tcp://") {
+		add("docker.host: must start with unix:// or tcp://, got %q", c.Docker.Host)
+	}
+	if c.Docker.MaxLogLines <= 0 {
+		add("docker.max_log_lines: must be positive")
+	}
+	if c.Docker.MaxOutputSizeBytes <= 0 {
+		add("docker.max_output_size_bytes: must be positive")
+	}
+	if c.Docker.TimeoutSeconds <= 0 {
+		add("docker.timeout_seconds: must be positive")
+	}
+
+	if c.Kubernetes.MaxLogLines <= 0 {
+		add("kubernetes.max_log_lines: must be positive")
+	}
+	if c.Kubernetes.TimeoutSeconds <= 0 {
+		add("kubernetes.timeout_seconds: must be positive")
+	}
+
+	validatePolicies(add, "ssh", c.SSH.Policies, c.SSH.DefaultAction)
+	for i, p := range c.SSH.Policies {
+		if p.Resolve == "path" {
+			add("ssh.policies[%d].resolve: path is not supported for remote commands", i)
+		}
+	}
+	switch c.SSH.StrictHostKeyChecking {
+	case "yes", "accept-new":
+	default:
+		add("ssh.strict_host_key_checking: must be yes or accept-new, got %q", c.SSH.StrictHostKeyChecking)
+	}
+	if c.SSH.ConnectTimeoutSeconds <= 0 {
+		add("ssh.connect_timeout_seconds: must be positive")
+	}
+	if c.SSH.DefaultTimeoutSeconds <= 0 {
+		add("ssh.default_timeout_seconds: must be positive")
+	}
+	if c.SSH.MaxOutputSizeBytes <= 0 {
+		add("ssh.max_output_size_bytes: must be positive")
+	}
+	hosts := make(map[string]bool)
+	for i, h := range c.SSH.Hosts {
+		field := fmt.Sprintf("ssh.hosts[%d]", i)
+		switch {
+		case h.Name == "":
+			add("%s.name: required", field)
+		case hosts[h.Name]:
+			add("%s.name: duplicate host %q", field, h.Name)
+		}
+		hosts[h.Name] = true
+		if h.Hostname == "" {
+			add("%s.hostname: required", field)
+		} else if strings.HasPrefix(h.Hostname, "-") {
+			add("%s.hostname: must not start with -", field)
+		}
+		if strings.HasPrefix(h.User, "-") || strings.ContainsAny(h.User, "@ ") {
+			add("%s.user: invalid user %q", field, h.User)
+		}
+		if h.Port < 0 || h.Port > 65535 {
+			add("%s.port: must be between 1 and 65535, or 0 for the default", field)
+		}
+	}
+
+	if c.Packages.TimeoutSeconds <= 0 {
+		add("packages.timeout_seconds: must be positive")
+	}
+
+	if c.Build.TimeoutSeconds <= 0 {
+		add("build.timeout_seconds: must be positive")
+	}
+	if c.Build.MaxOutputSizeBytes <= 0 {
+		add("build.max_output_size_bytes: must be positive")
+	}
+
+	for _, setting := range c.Admin.AllowedSettings {
+		if _, err := c.Setting(setting); err != nil {
+			add("admin.allowed_settings: %v", err)
+		}
+	}
+	if c.Admin.Enabled && c.Admin.TokenEnv == "" {
+		add("admin.token_env: required when admin is enabled")
+	}
+
+	if c.Audit.Enabled && c.Audit.File == "" {
+		add("audit.file: required when audit is enabled")
+	}
+	if c.Audit.MaxSizeMB < 0 {
+		add("audit.max_size_mb: must not be negative")
+	}
+	if c.Audit.MaxBackups < 0 {
+		add("audit.max_backups: must not be negative")
+	}
+
+	return errors.Join(errs...)
+}
+
+// validatePolicies checks a section's command policies and default_action.
+func validatePolicies(add func(string, ...interface{}), section string, policies []CommandPolicy, defaultAction string) {
+	switch defaultAction {
+	case "allow", "deny":
+	default:
+		add("%s.default_action: must be allow or deny, got %q", section, defaultAction)
+	}
+	for i, p := range policies {
+		field := fmt.Sprintf("%s.policies[%d]", section, i)
+		if p.Name != "" {
+			field = fmt.Sprintf("%s.policies[%d] (%s)", section, i, p.Name)
+		}
+		switch p.Action {
+		case "allow", "deny":
+		default:
+			add("%s.action: must be allow or deny, got %q", field, p.Action)
+		}
+		switch p.Resolve {
+		case "", "any", "absolute", "path":
+		default:
+			add("%s.resolve: must be any, absolute or path, got %q", field, p.Resolve)
+		}
+		if p.Binary == "" && p.Match == "" {
+			add("%s: binary or match is required", field)
+		}
+		if p.Binary != "" {
+			if _, err := filepath.Match(p.Binary, ""); err != nil {
+				add("%s.binary: %v", field, err)
+			}
+		}
+		patterns := append([]string{p.Match}, append(p.AllowArgs, p.DenyArgs...)...)
+		for _, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				add("%s: invalid pattern %q: %v", field, pattern, err)
+			}
+		}
+		if p.MaxArgs < 0 {
+			add("%s.max_args: must not be negative", field)
+		}
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,44 @@
+package admin
+
+import (
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/internal/reload"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type Server struct {
+	config   *config.AdminConfig
+	logger   *common.Logger
+	reloader *reload.Reloader
+}
+
+func NewServer(cfg *config.AdminConfig, reloader *reload.Reloader) *Server {
+	s := &Server{
+		config:   cfg,
+		logger:   common.NewServerLogger("admin"),
+		reloader: reloader,
+	}
+	common.RegisterConfigSection("admin", s.configSection)
+	return s
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		common.Audited("admin", s.updateConfigTool()),
+		s.listConfigOverridesTool(),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
+	}
+	return common.Instrument("admin", tools)
+}
+
+func (s *Server) RegisterTools(server *mcp.Server) {
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
+}
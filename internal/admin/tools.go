@@ -0,0 +1,175 @@
+package admin
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/internal/reload"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) updateConfigTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "update_config",
+		Description: "Change an allowlisted setting at runtime (e.g. add an allowed path or raise a timeout). Requires the admin token",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"token":       mcp.StringProperty("Admin token"),
+				"setting":     mcp.StringProperty("Dotted setting name, e.g. filesystem.allowed_paths"),
+				"op":          mcp.StringProperty("set, add or remove (lists), or reset to drop runtime changes to the setting (default: set)"),
+				"value":       map[string]interface{}{"description": "New value: string, number, boolean or list of strings"},
+				"ttl_seconds": mcp.IntProperty("Revert the change after this many seconds (default: never)"),
+				"persist":     mcp.BoolProperty("Keep the change across restarts"),
+			},
+			[]string{"token", "setting"},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleUpdateConfig),
+	}
+}
+
+type updateConfigArgs struct {
+	Token      string      `json:"token"`
+	Setting    string      `json:"setting" validate:"required"`
+	Op         string      `json:"op"`
+	Value      interface{} `json:"value"`
+	TTLSeconds int         `json:"ttl_seconds"`
+	Persist    bool        `json:"persist"`
+}
+
+func (s *Server) handleUpdateConfig(ctx context.Context, args updateConfigArgs) (*mcp.ToolResult, error) {
+	if err := s.authorize(args.Token); err != nil {
+		return nil, err
+	}
+
+	setting, op, value, ttl, persist := args.Setting, args.Op, args.Value, args.TTLSeconds, args.Persist
+	if op == "" {
+		op = "set"
+	}
+
+	if !s.isSettingAllowed(setting) {
+		return nil, fmt.Errorf("%w: %s is not in admin.allowed_settings", common.ErrPermissionDenied, setting)
+	}
+
+	audit := s.logger.WithFields(map[string]interface{}{
+		"audit":   true,
+		"action":  "update_config",
+		"setting": setting,
+		"op":      op,
+	})
+
+	if op == "reset" {
+		removed, err := s.reloader.ClearOverrides(setting)
+		if err != nil {
+			return nil, err
+		}
+		audit.WithField("removed", removed).Info("config overrides reset")
+		return mcp.JSONResult(map[string]interface{}{
+			"setting": setting,
+			"op":      op,
+			"removed": removed,
+		})
+	}
+
+	if value == nil {
+		return nil, fmt.Errorf("%w: value is required", common.ErrInvalidInput)
+	}
+
+	override := reload.Override{
+		Setting: setting,
+		Op:      op,
+		Value:   value,
+		Persist: persist,
+	}
+	if ttl > 0 {
+		override.Expires = time.Now().Add(time.Duration(ttl) * time.Second)
+	}
+
+	before, after, err := s.reloader.AddOverride(override)
+	if err != nil {
+		audit.WithField("error", err.Error()).Warn("config update rejected")
+		return nil, fmt.Errorf("%w: %v", common.ErrInvalidInput, err)
+	}
+
+	audit.WithFields(map[string]interface{}{
+		"value":       value,
+		"ttl_seconds": ttl,
+		"persist":     persist,
+	}).Info("config updated")
+
+	result := map[string]interface{}{
+		"setting":   setting,
+		"op":        op,
+		"previous":  before,
+		"current":   after,
+		"persisted": persist,
+	}
+	if !override.Expires.IsZero() {
+		result["expires_at"] = override.Expires.Format(time.RFC3339)
+	}
+
+	return mcp.JSONResult(result)
+}
+
+func (s *Server) listConfigOverridesTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_config_overrides",
+		Description: "List runtime config changes made with update_config. Requires the admin token",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"token": mcp.StringProperty("Admin token"),
+			},
+			[]string{"token"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleListConfigOverrides),
+	}
+}
+
+type listConfigOverridesArgs struct {
+	Token string `json:"token"`
+}
+
+func (s *Server) handleListConfigOverrides(ctx context.Context, args listConfigOverridesArgs) (*mcp.ToolResult, error) {
+	if err := s.authorize(args.Token); err != nil {
+		return nil, err
+	}
+
+	overrides := s.reloader.Overrides()
+	return mcp.JSONResult(map[string]interface{}{
+		"overrides":   overrides,
+		"total_count": len(overrides),
+	})
+}
+
+func (s *Server) authorize(token string) error {
+	expected := os.Getenv(s.config.TokenEnv)
+	if expected == "" {
+		return fmt.Errorf("%w: admin token is not configured (set %s)", common.ErrPermissionDenied, s.config.TokenEnv)
+	}
+	if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(expected)) != 1 {
+		s.logger.WithFields(map[string]interface{}{
+			"audit":  true,
+			"action": "admin_auth",
+		}).Warn("admin token rejected")
+		return fmt.Errorf("%w: invalid admin token", common.ErrPermissionDenied)
+	}
+	return nil
+}
+
+func (s *Server) isSettingAllowed(setting string) bool {
+	if setting == "admin" || strings.HasPrefix(setting, "admin.") {
+		return false
+	}
+	for _, allowed := range s.config.AllowedSettings {
+		if allowed == setting {
+			return true
+		}
+	}
+	return false
+}
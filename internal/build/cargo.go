@@ -0,0 +1,95 @@
+package build
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+type cargoRunner struct{}
+
+var (
+	// cargoTestRe matches libtest's result lines, e.g.
+	// "test parser::tests::empty ... FAILED" or "test x ... ignored, slow".
+	cargoTestRe = regexp.MustCompile(`^test (.+) \.\.\. (ok|FAILED|ignored)(?:,.*)?$`)
+	// cargoOutputRe starts the captured output of a failed test.
+	cargoOutputRe = regexp.MustCompile(`^---- (.+) stdout ----$`)
+)
+
+var cargoStatuses = map[string]string{
+	"ok":      statusPassed,
+	"FAILED":  statusFailed,
+	"ignored": statusSkipped,
+}
+
+// test runs cargo test. Stable libtest has no machine-readable output or
+// per-test timings, so this parses its text output.
+func (cargoRunner) test(ctx context.Context, p *project, opts testOptions) (*TestRun, error) {
+	if opts.coverage {
+		return nil, fmt.Errorf("%w: cargo test has no built-in coverage", common.ErrInvalidInput)
+	}
+	cmdline := []string{"cargo", "test", "--no-fail-fast"}
+	for _, pkg := range opts.targets {
+		cmdline = append(cmdline, "--package", pkg)
+	}
+	cmdline = append(cmdline, "--")
+	if opts.rerun != nil {
+		cmdline = append(cmdline, "--exact")
+		for _, c := range opts.rerun {
+			cmdline = append(cmdline, c.Name)
+		}
+	} else if opts.filter != "" {
+		cmdline = append(cmdline, opts.filter)
+	}
+
+	res, err := p.run(ctx, cmdline)
+	if err != nil {
+		return nil, err
+	}
+	return p.newTestRun(cmdline, res, parseCargoTest(res.stdout), string(res.stderr)), nil
+}
+
+func parseCargoTest(stdout []byte) []TestCase {
+	var cases []TestCase
+	index := map[string]int{}
+	var current string
+	var captured strings.Builder
+	flush := func() {
+		if i, ok := index[current]; ok && current != "" {
+			cases[i].Output = strings.TrimSpace(captured.String())
+		}
+		current = ""
+		captured.Reset()
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := cargoTestRe.FindStringSubmatch(line); m != nil {
+			index[m[1]] = len(cases)
+			cases = append(cases, TestCase{Name: m[1], Status: cargoStatuses[m[2]]})
+			continue
+		}
+		if m := cargoOutputRe.FindStringSubmatch(line); m != nil {
+			flush()
+			current = m[1]
+			continue
+		}
+		if current == "" {
+			continue
+		}
+		// The captured output ends at the list of failures.
+		if line == "failures:" {
+			flush()
+			continue
+		}
+		captured.WriteString(line + "\n")
+	}
+	flush()
+	return cases
+}
@@ -0,0 +1,194 @@
+package build
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+type goRunner struct{}
+
+// goEvent is a line of `go test -json` (see `go doc test2json`).
+type goEvent struct {
+	Action  string
+	Package string
+	Test    string
+	Elapsed float64
+	Output  string
+}
+
+var (
+	goCoverageRe = regexp.MustCompile(`coverage: ([\d.]+)% of statements`)
+	// goCoverTotalRe matches the last line of `go tool cover -func`.
+	goCoverTotalRe = regexp.MustCompile(`^total:.*?([\d.]+)%$`)
+)
+
+func (goRunner) test(ctx context.Context, p *project, opts testOptions) (*TestRun, error) {
+	cmdline := []string{"go", "test", "-json"}
+	targets := opts.targets
+	filter := opts.filter
+	if opts.rerun != nil {
+		targets, filter = goRerun(opts.rerun)
+	}
+	if filter != "" {
+		cmdline = append(cmdline, "-run="+filter)
+	}
+	var profile string
+	if opts.coverage {
+		var cleanup func()
+		var err error
+		if profile, cleanup, err = tempFile("coverage-*.out"); err != nil {
+			return nil, err
+		}
+		defer cleanup()
+		cmdline = append(cmdline, "-coverprofile="+profile)
+	}
+	if len(targets) == 0 {
+		targets = []string{"./..."}
+	}
+	cmdline = append(cmdline, targets...)
+
+	res, err := p.run(ctx, cmdline)
+	if err != nil {
+		return nil, err
+	}
+	cases, output, coverage := parseGoTest(res.stdout)
+	run := p.newTestRun(cmdline, res, cases, output+string(res.stderr))
+
+	if opts.coverage && len(coverage) > 0 {
+		run.Coverage = &Coverage{Suites: coverage}
+		// go tool cover reports the total over every package's statements.
+		if cov, err := p.run(ctx, []string{"go", "tool", "cover", "-func=" + profile}); err == nil && cov.exitCode == 0 {
+			lines := strings.Split(strings.TrimSpace(string(cov.stdout)), "\n")
+			if m := goCoverTotalRe.FindStringSubmatch(lines[len(lines)-1]); m != nil {
+				run.Coverage.Percent, _ = strconv.ParseFloat(m[1], 64)
+			}
+		}
+	}
+	return run, nil
+}
+
+// goRerun returns the packages and -run pattern for previously failed
+// tests. Subtests re-run through their top-level test.
+func goRerun(failed []TestCase) (packages []string, pattern string) {
+	pkgs := map[string]bool{}
+	names := map[string]bool{}
+	for _, c := range failed {
+		pkgs[c.Suite] = true
+		names[regexp.QuoteMeta(strings.SplitN(c.Name, "/", 2)[0])] = true
+	}
+	var nameList []string
+	for pkg := range pkgs {
+		packages = append(packages, pkg)
+	}
+	for name := range names {
+		nameList = append(nameList, name)
+	}
+	sort.Strings(packages)
+	sort.Strings(nameList)
+	return packages, "^(" + strings.Join(nameList, "|") + ")$"
+}
+
+// parseGoTest reads the events of `go test -json`. It returns the tests,
+// the package-level output of packages that failed, which holds build
+// errors and panics outside tests, and per-package coverage.
+func parseGoTest(stdout []byte) (cases []TestCase, output string, coverage map[string]float64) {
+	type key struct{ pkg, test string }
+	tests := map[key]*TestCase{}
+	var order []key
+	testOutput := map[key]*strings.Builder{}
+	pkgOutput := map[string]*strings.Builder{}
+	var failedPkgs []string
+	var buildOutput strings.Builder
+	coverage = map[string]float64{}
+
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e goEvent
+		if json.Unmarshal(scanner.Bytes(), &e) != nil {
+			continue
+		}
+		// Since Go 1.24 build errors arrive as build-output events.
+		if e.Action == "build-output" {
+			buildOutput.WriteString(e.Output)
+			continue
+		}
+		if e.Test == "" {
+			switch e.Action {
+			case "output":
+				if pkgOutput[e.Package] == nil {
+					pkgOutput[e.Package] = &strings.Builder{}
+				}
+				pkgOutput[e.Package].WriteString(e.Output)
+				if m := goCoverageRe.FindStringSubmatch(e.Output); m != nil {
+					coverage[e.Package], _ = strconv.ParseFloat(m[1], 64)
+				}
+			case "fail":
+				failedPkgs = append(failedPkgs, e.Package)
+			}
+			continue
+		}
+
+		k := key{e.Package, e.Test}
+		switch e.Action {
+		case "run":
+			if tests[k] == nil {
+				tests[k] = &TestCase{Name: e.Test, Suite: e.Package}
+				order = append(order, k)
+			}
+		case "output":
+			if strings.HasPrefix(e.Output, "=== ") {
+				continue
+			}
+			if testOutput[k] == nil {
+				testOutput[k] = &strings.Builder{}
+			}
+			testOutput[k].WriteString(e.Output)
+		case "pass", "fail", "skip":
+			if tests[k] == nil {
+				tests[k] = &TestCase{Name: e.Test, Suite: e.Package}
+				order = append(order, k)
+			}
+			tests[k].Status = map[string]string{"pass": statusPassed, "fail": statusFailed, "skip": statusSkipped}[e.Action]
+			tests[k].DurationMs = int64(e.Elapsed * 1000)
+		}
+	}
+
+	// Report subtests rather than the tests that contain them, unless the
+	// test itself failed while its subtests passed.
+	parents := map[key]bool{}
+	childFailed := map[key]bool{}
+	for _, k := range order {
+		if i := strings.LastIndex(k.test, "/"); i >= 0 {
+			parent := key{k.pkg, k.test[:i]}
+			parents[parent] = true
+			if tests[k].Status == statusFailed {
+				childFailed[parent] = true
+			}
+		}
+	}
+	for _, k := range order {
+		c := tests[k]
+		if c.Status == "" || (parents[k] && (c.Status != statusFailed || childFailed[k])) {
+			continue
+		}
+		if c.Status == statusFailed && testOutput[k] != nil {
+			c.Output = strings.TrimSpace(testOutput[k].String())
+		}
+		cases = append(cases, *c)
+	}
+
+	output = buildOutput.String()
+	for _, pkg := range failedPkgs {
+		if b := pkgOutput[pkg]; b != nil {
+			output += b.String()
+		}
+	}
+	return cases, output, coverage
+}
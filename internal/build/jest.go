@@ -0,0 +1,138 @@
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+type jestRunner struct{}
+
+// jestReport is the part of jest's --json output used here.
+type jestReport struct {
+	TestResults []struct {
+		Name             string `json:"name"`
+		Status           string `json:"status"`
+		Message          string `json:"message"`
+		AssertionResults []struct {
+			FullName        string   `json:"fullName"`
+			Status          string   `json:"status"`
+			Duration        float64  `json:"duration"`
+			FailureMessages []string `json:"failureMessages"`
+		} `json:"assertionResults"`
+	} `json:"testResults"`
+}
+
+var jestStatuses = map[string]string{
+	"passed":   statusPassed,
+	"failed":   statusFailed,
+	"pending":  statusSkipped,
+	"skipped":  statusSkipped,
+	"todo":     statusSkipped,
+	"disabled": statusSkipped,
+}
+
+func (jestRunner) test(ctx context.Context, p *project, opts testOptions) (*TestRun, error) {
+	report, cleanup, err := tempFile("jest-*.json")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	// --no-install keeps npx from fetching jest when the project lacks it.
+	cmdline := []string{"npx", "--no-install", "jest", "--ci", "--json", "--outputFile=" + report}
+	targets := opts.targets
+	filter := opts.filter
+	if opts.rerun != nil {
+		targets, filter = jestRerun(opts.rerun)
+	}
+	if filter != "" {
+		cmdline = append(cmdline, "--testNamePattern="+filter)
+	}
+	var coverageDir string
+	if opts.coverage {
+		if coverageDir, err = os.MkdirTemp("", "jest-coverage-*"); err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(coverageDir)
+		cmdline = append(cmdline, "--coverage", "--coverageReporters=json-summary", "--coverageDirectory="+coverageDir)
+	}
+	cmdline = append(cmdline, targets...)
+
+	res, err := p.run(ctx, cmdline)
+	if err != nil {
+		return nil, err
+	}
+	output := string(res.stdout) + string(res.stderr)
+
+	var r jestReport
+	data, _ := os.ReadFile(report)
+	if len(data) == 0 {
+		// jest never got to run the tests, e.g. it is not installed.
+		return p.newTestRun(cmdline, res, nil, output), nil
+	}
+	if err := json.Unmarshal(data, &r); err != nil {
+		return nil, fmt.Errorf("%w: parsing jest report: %v", common.ErrOperationFailed, err)
+	}
+
+	var cases []TestCase
+	var suiteErrors []string
+	for _, suite := range r.TestResults {
+		file := suite.Name
+		if rel, err := filepath.Rel(p.dir, file); err == nil {
+			file = rel
+		}
+		if suite.Status == "failed" && len(suite.AssertionResults) == 0 {
+			suiteErrors = append(suiteErrors, file+":\n"+suite.Message)
+		}
+		for _, a := range suite.AssertionResults {
+			cases = append(cases, TestCase{
+				Name:       a.FullName,
+				Suite:      file,
+				Status:     jestStatuses[a.Status],
+				DurationMs: int64(a.Duration),
+				Output:     strings.TrimSpace(strings.Join(a.FailureMessages, "\n")),
+			})
+		}
+	}
+	if len(suiteErrors) > 0 {
+		output = strings.Join(suiteErrors, "\n\n")
+	}
+	run := p.newTestRun(cmdline, res, cases, output)
+
+	if opts.coverage {
+		var summary map[string]struct {
+			Statements struct {
+				Pct float64 `json:"pct"`
+			} `json:"statements"`
+		}
+		data, err := os.ReadFile(filepath.Join(coverageDir, "coverage-summary.json"))
+		if err == nil && json.Unmarshal(data, &summary) == nil {
+			run.Coverage = &Coverage{Percent: summary["total"].Statements.Pct}
+		}
+	}
+	return run, nil
+}
+
+// jestRerun returns the test files and name pattern for previously failed
+// tests. jest reads both as regular expressions.
+func jestRerun(failed []TestCase) (files []string, pattern string) {
+	seen := map[string]bool{}
+	var names []string
+	for _, c := range failed {
+		if !seen[c.Suite] {
+			seen[c.Suite] = true
+			files = append(files, regexp.QuoteMeta(c.Suite))
+		}
+		names = append(names, regexp.QuoteMeta(c.Name))
+	}
+	sort.Strings(files)
+	return files, "^(" + strings.Join(names, "|") + ")$"
+}
@@ -0,0 +1,126 @@
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+type pytestRunner struct{}
+
+// junitCase is a <testcase> of the JUnit XML report pytest writes.
+type junitCase struct {
+	Classname string       `xml:"classname,attr"`
+	Name      string       `xml:"name,attr"`
+	Time      float64      `xml:"time,attr"`
+	Failure   *junitResult `xml:"failure"`
+	Error     *junitResult `xml:"error"`
+	Skipped   *junitResult `xml:"skipped"`
+}
+
+type junitResult struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",chardata"`
+}
+
+func (pytestRunner) test(ctx context.Context, p *project, opts testOptions) (*TestRun, error) {
+	report, cleanup, err := tempFile("pytest-*.xml")
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	cmdline := []string{python(p.dir), "-m", "pytest", "-q", "--junitxml=" + report}
+	if opts.rerun != nil {
+		// pytest keeps its own record of the last failures, with exact
+		// node IDs.
+		cmdline = append(cmdline, "--last-failed", "--last-failed-no-failures=none")
+	} else if opts.filter != "" {
+		cmdline = append(cmdline, "-k", opts.filter)
+	}
+	var coverageReport string
+	if opts.coverage {
+		var cleanupCoverage func()
+		if coverageReport, cleanupCoverage, err = tempFile("coverage-*.json"); err != nil {
+			return nil, err
+		}
+		defer cleanupCoverage()
+		// Needs the pytest-cov plugin.
+		cmdline = append(cmdline, "--cov=.", "--cov-report=json:"+coverageReport)
+	}
+	cmdline = append(cmdline, opts.targets...)
+
+	res, err := p.run(ctx, cmdline)
+	if err != nil {
+		return nil, err
+	}
+	output := string(res.stdout) + string(res.stderr)
+
+	f, err := os.Open(report)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	cases, err := parseJUnit(f)
+	if err != nil {
+		return nil, err
+	}
+	run := p.newTestRun(cmdline, res, cases, output)
+
+	if opts.coverage {
+		var summary struct {
+			Totals struct {
+				PercentCovered float64 `json:"percent_covered"`
+			} `json:"totals"`
+		}
+		data, err := os.ReadFile(coverageReport)
+		if err == nil && json.Unmarshal(data, &summary) == nil {
+			run.Coverage = &Coverage{Percent: summary.Totals.PercentCovered}
+		}
+	}
+	return run, nil
+}
+
+// parseJUnit reads every <testcase> in a JUnit XML report, whether the
+// root is <testsuites> or a single <testsuite>. The report is empty when
+// pytest failed to start.
+func parseJUnit(r io.Reader) ([]TestCase, error) {
+	var cases []TestCase
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			return cases, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: parsing JUnit report: %v", common.ErrOperationFailed, err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok || start.Name.Local != "testcase" {
+			continue
+		}
+		var jc junitCase
+		if err := dec.DecodeElement(&jc, &start); err != nil {
+			return nil, fmt.Errorf("%w: parsing JUnit report: %v", common.ErrOperationFailed, err)
+		}
+
+		c := TestCase{Name: jc.Name, Suite: jc.Classname, Status: statusPassed, DurationMs: int64(jc.Time * 1000)}
+		switch {
+		case jc.Failure != nil:
+			c.Status = statusFailed
+			c.Output = strings.TrimSpace(jc.Failure.Text)
+		case jc.Error != nil:
+			c.Status = statusFailed
+			c.Output = strings.TrimSpace(jc.Error.Message + "\n" + jc.Error.Text)
+		case jc.Skipped != nil:
+			c.Status = statusSkipped
+		}
+		cases = append(cases, c)
+	}
+}
@@ -0,0 +1,241 @@
+package build
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+// TestCase is one test from a run. Suite is the Go package, the jest test
+// file or the pytest class; cargo does not report one.
+type TestCase struct {
+	Name       string `json:"name"`
+	Suite      string `json:"suite,omitempty"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms,omitempty"`
+	// Output is only kept for failures.
+	Output string `json:"output,omitempty"`
+}
+
+const (
+	statusPassed  = "passed"
+	statusFailed  = "failed"
+	statusSkipped = "skipped"
+)
+
+type Summary struct {
+	Total   int `json:"total"`
+	Passed  int `json:"passed"`
+	Failed  int `json:"failed"`
+	Skipped int `json:"skipped"`
+}
+
+// Coverage is statement coverage in percent. Suites breaks it down per Go
+// package where the runner reports that.
+type Coverage struct {
+	Percent float64            `json:"percent"`
+	Suites  map[string]float64 `json:"suites,omitempty"`
+}
+
+type TestRun struct {
+	Runner     string     `json:"runner"`
+	ProjectDir string     `json:"project_dir"`
+	Command    []string   `json:"command"`
+	Success    bool       `json:"success"`
+	ExitCode   int        `json:"exit_code"`
+	Summary    Summary    `json:"summary"`
+	Failures   []TestCase `json:"failures"`
+	Slowest    []TestCase `json:"slowest,omitempty"`
+	Coverage   *Coverage  `json:"coverage,omitempty"`
+	DurationMs int64      `json:"duration_ms"`
+	// Output is the raw output of runs that failed without a failing
+	// test, such as compile errors.
+	Output string `json:"output,omitempty"`
+}
+
+type testOptions struct {
+	targets  []string
+	filter   string
+	coverage bool
+	// rerun, when set, limits the run to these previously failed tests.
+	rerun []TestCase
+}
+
+type runner interface {
+	test(ctx context.Context, p *project, opts testOptions) (*TestRun, error)
+}
+
+var runners = map[string]runner{
+	"go":     goRunner{},
+	"jest":   jestRunner{},
+	"pytest": pytestRunner{},
+	"cargo":  cargoRunner{},
+}
+
+// detectRunner picks the test runner from the project files, checked in
+// this order.
+func detectRunner(dir string) (string, error) {
+	exists := func(name string) bool {
+		_, err := os.Stat(filepath.Join(dir, name))
+		return err == nil
+	}
+	switch {
+	case exists("go.mod"):
+		return "go", nil
+	case exists("Cargo.toml"):
+		return "cargo", nil
+	case exists("package.json"):
+		data, _ := os.ReadFile(filepath.Join(dir, "package.json"))
+		if bytes.Contains(data, []byte(`"jest"`)) || exists("jest.config.js") || exists("jest.config.ts") {
+			return "jest", nil
+		}
+	}
+	for _, name := range []string{"pytest.ini", "conftest.py", "pyproject.toml", "setup.cfg", "tox.ini", "setup.py", "requirements.txt"} {
+		if exists(name) {
+			return "pytest", nil
+		}
+	}
+	return "", fmt.Errorf("%w: no go.mod, Cargo.toml, jest project or Python project in %s; pass runner explicitly",
+		common.ErrNotFound, dir)
+}
+
+func runnerNames() []string {
+	names := make([]string, 0, len(runners))
+	for name := range runners {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// project is a resolved project directory and the runner used in it.
+type project struct {
+	dir       string
+	name      string
+	timeout   time.Duration
+	maxOutput int
+}
+
+type execResult struct {
+	stdout   []byte
+	stderr   []byte
+	exitCode int
+	duration time.Duration
+}
+
+// run runs a command in the project directory. Failing tests make test
+// commands exit non-zero, so that is reported in the result rather than
+// as an error.
+func (p *project) run(ctx context.Context, cmdline []string) (*execResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, cmdline[0], cmdline[1:]...)
+	cmd.Dir = p.dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	result := &execResult{stdout: stdout.Bytes(), stderr: stderr.Bytes(), duration: time.Since(start)}
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("%w: %s did not finish within %s", common.ErrTimeout, cmdline[0], p.timeout)
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, fmt.Errorf("%w: %s is not installed", common.ErrNotFound, cmdline[0])
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("%w: running %s: %v", common.ErrOperationFailed, cmdline[0], err)
+		}
+		result.exitCode = exitErr.ExitCode()
+	}
+	return result, nil
+}
+
+// tempFile returns a path for a report file, removed by the returned
+// cleanup.
+func tempFile(pattern string) (string, func(), error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", nil, err
+	}
+	f.Close()
+	return f.Name(), func() { os.Remove(f.Name()) }, nil
+}
+
+func (p *project) truncate(s string) string {
+	if len(s) > p.maxOutput {
+		return s[:p.maxOutput] + "\n... (truncated)"
+	}
+	return s
+}
+
+// slowestCount is how many of the slowest tests a run reports.
+const slowestCount = 5
+
+// newTestRun summarizes the parsed test cases of a finished command.
+// output is shown when the command failed without a failing test.
+func (p *project) newTestRun(cmdline []string, res *execResult, cases []TestCase, output string) *TestRun {
+	run := &TestRun{
+		Runner:     p.name,
+		ProjectDir: p.dir,
+		Command:    cmdline,
+		ExitCode:   res.exitCode,
+		Failures:   []TestCase{},
+		DurationMs: res.duration.Milliseconds(),
+	}
+	var timed []TestCase
+	for _, c := range cases {
+		run.Summary.Total++
+		switch c.Status {
+		case statusPassed:
+			run.Summary.Passed++
+		case statusFailed:
+			run.Summary.Failed++
+			c.Output = p.truncate(c.Output)
+			run.Failures = append(run.Failures, c)
+		case statusSkipped:
+			run.Summary.Skipped++
+		}
+		if c.DurationMs > 0 {
+			c.Output = ""
+			timed = append(timed, c)
+		}
+	}
+	sort.SliceStable(timed, func(i, j int) bool {
+		return timed[i].DurationMs > timed[j].DurationMs
+	})
+	if len(timed) > slowestCount {
+		timed = timed[:slowestCount]
+	}
+	run.Slowest = timed
+
+	run.Success = res.exitCode == 0 && run.Summary.Failed == 0
+	if !run.Success && run.Summary.Failed == 0 {
+		run.Output = p.truncate(strings.TrimSpace(output))
+	}
+	return run
+}
+
+// python returns the project's virtualenv interpreter if it has one.
+func python(dir string) string {
+	for _, venv := range []string{".venv", "venv"} {
+		path := filepath.Join(dir, venv, "bin", "python")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return "python3"
+}
@@ -0,0 +1,53 @@
+package build
+
+import (
+	"sync"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type Server struct {
+	config    *config.BuildConfig
+	validator *common.PathValidator
+	// failures holds the failed tests of the last run per project and
+	// runner, for run_tests with failed_only.
+	failures sync.Map
+	logger   *common.Logger
+}
+
+func NewServer(cfg *config.BuildConfig) *Server {
+	s := &Server{
+		config: cfg,
+		logger: common.NewServerLogger("build"),
+	}
+	s.Reload()
+	common.RegisterConfigSection("build", s.configSection)
+	return s
+}
+
+func (s *Server) Reload() {
+	s.validator = common.NewPathValidator(s.config.AllowedProjectPaths, nil, true)
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		common.Audited("build", s.runTestsTool()),
+		common.Audited("build", s.runBuildTool()),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
+		common.AuditLogTool(),
+	}
+	return common.Instrument("build", tools)
+}
+
+func (s *Server) RegisterTools(server *mcp.Server) {
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
+}
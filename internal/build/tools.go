@@ -0,0 +1,233 @@
+package build
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// projectArgs are the arguments every build tool takes to pick the project
+// and its runner.
+type projectArgs struct {
+	ProjectDir string `json:"project_dir" validate:"required"`
+	Runner     string `json:"runner"`
+}
+
+func (s *Server) project(args projectArgs) (*project, error) {
+	name := args.Runner
+	dir, err := s.validator.ResolvePath(args.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		if name, err = detectRunner(dir); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := runners[name]; !ok {
+		return nil, fmt.Errorf("%w: runner must be one of %s", common.ErrInvalidInput, strings.Join(runnerNames(), ", "))
+	}
+	return &project{
+		dir:       dir,
+		name:      name,
+		timeout:   time.Duration(s.config.TimeoutSeconds) * time.Second,
+		maxOutput: s.config.MaxOutputSizeBytes,
+	}, nil
+}
+
+// validateTargets keeps targets inside the project and out of the
+// runners' option parsing.
+func validateTargets(targets []string) error {
+	for _, t := range targets {
+		if t == "" || strings.HasPrefix(t, "-") || filepath.IsAbs(t) {
+			return fmt.Errorf("%w: invalid target %q", common.ErrInvalidInput, t)
+		}
+		for _, part := range strings.Split(filepath.ToSlash(t), "/") {
+			if part == ".." {
+				return fmt.Errorf("%w: target %q leaves the project", common.ErrInvalidInput, t)
+			}
+		}
+	}
+	return nil
+}
+
+func (s *Server) runTestsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "run_tests",
+		Description: "Run a project's tests with go test, jest, pytest or cargo test and return pass/fail counts, failing tests with their output, the slowest tests and optionally coverage",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"project_dir": mcp.StringProperty("Project directory"),
+				"runner":      mcp.StringProperty("One of " + strings.Join(runnerNames(), ", ") + "; detected from the project files if omitted"),
+				"targets":     mcp.ArrayProperty("string", "Go packages, jest or pytest paths, or cargo packages, relative to the project; all tests if omitted"),
+				"filter":      mcp.StringProperty("Only run tests whose names match (go -run, jest -t, pytest -k, cargo test filter)"),
+				"coverage":    mcp.BoolProperty("Collect statement coverage (pytest needs pytest-cov; not available for cargo)"),
+				"failed_only": mcp.BoolProperty("Re-run only the tests that failed in the previous run_tests call for this project"),
+			},
+			[]string{"project_dir"},
+		),
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleRunTests),
+	}
+}
+
+type runTestsArgs struct {
+	projectArgs
+	Targets    []string `json:"targets"`
+	Filter     string   `json:"filter"`
+	Coverage   bool     `json:"coverage"`
+	FailedOnly bool     `json:"failed_only"`
+}
+
+func (s *Server) handleRunTests(ctx context.Context, args runTestsArgs) (*mcp.ToolResult, error) {
+	targets, filter, coverage, failedOnly := args.Targets, args.Filter, args.Coverage, args.FailedOnly
+	if err := validateTargets(targets); err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(filter, "-") {
+		return nil, fmt.Errorf("%w: invalid filter %q", common.ErrInvalidInput, filter)
+	}
+	p, err := s.project(args.projectArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := testOptions{targets: targets, filter: filter, coverage: coverage}
+	key := p.dir + "\x00" + p.name
+	if failedOnly {
+		v, ok := s.failures.Load(key)
+		if !ok {
+			return nil, fmt.Errorf("%w: no failed %s tests recorded for %s; run the tests first",
+				common.ErrInvalidInput, p.name, p.dir)
+		}
+		opts.rerun = v.([]TestCase)
+	}
+
+	run, err := runners[p.name].test(ctx, p, opts)
+	if err != nil {
+		return nil, common.WithDetails(err, "runner", p.name)
+	}
+	if len(run.Failures) > 0 {
+		s.failures.Store(key, run.Failures)
+	} else if run.Success {
+		s.failures.Delete(key)
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"runner":      p.name,
+		"project_dir": p.dir,
+		"passed":      run.Summary.Passed,
+		"failed":      run.Summary.Failed,
+	}).Info("Tests finished")
+	return mcp.JSONResult(run)
+}
+
+// Diagnostic is a compiler error or warning from run_build.
+type Diagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// diagnosticRe matches "file:line:col: message" as printed by the go
+// command and by cargo's short message format, which adds a severity such
+// as "error[E0425]:".
+var diagnosticRe = regexp.MustCompile(`^(\S[^:]*):(\d+):(\d+): (?:(error|warning)(?:\[\w+\])?: )?(.+)$`)
+
+func parseDiagnostics(output []byte) []Diagnostic {
+	diagnostics := []Diagnostic{}
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		m := diagnosticRe.FindStringSubmatch(scanner.Text())
+		if m == nil {
+			continue
+		}
+		line, _ := strconv.Atoi(m[2])
+		column, _ := strconv.Atoi(m[3])
+		severity := m[4]
+		if severity == "" {
+			severity = "error"
+		}
+		diagnostics = append(diagnostics, Diagnostic{File: m[1], Line: line, Column: column, Severity: severity, Message: m[5]})
+	}
+	return diagnostics
+}
+
+func (s *Server) runBuildTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "run_build",
+		Description: "Build a Go or Rust project with go build or cargo build and return the compiler errors and warnings with file positions",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"project_dir": mcp.StringProperty("Project directory"),
+				"runner":      mcp.StringProperty("go or cargo; detected from the project files if omitted"),
+				"targets":     mcp.ArrayProperty("string", "Go packages or cargo packages, relative to the project; everything if omitted"),
+			},
+			[]string{"project_dir"},
+		),
+		Annotations: mcp.Additive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleRunBuild),
+	}
+}
+
+type runBuildArgs struct {
+	projectArgs
+	Targets []string `json:"targets"`
+}
+
+func (s *Server) handleRunBuild(ctx context.Context, args runBuildArgs) (*mcp.ToolResult, error) {
+	targets := args.Targets
+	if err := validateTargets(targets); err != nil {
+		return nil, err
+	}
+	p, err := s.project(args.projectArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	var cmdline []string
+	switch p.name {
+	case "go":
+		if len(targets) == 0 {
+			targets = []string{"./..."}
+		}
+		cmdline = append([]string{"go", "build"}, targets...)
+	case "cargo":
+		cmdline = []string{"cargo", "build", "--message-format=short"}
+		for _, pkg := range targets {
+			cmdline = append(cmdline, "--package", pkg)
+		}
+	default:
+		return nil, fmt.Errorf("%w: run_build supports go and cargo, not %s", common.ErrInvalidInput, p.name)
+	}
+
+	res, err := p.run(ctx, cmdline)
+	if err != nil {
+		return nil, common.WithDetails(err, "runner", p.name)
+	}
+	diagnostics := parseDiagnostics(res.stderr)
+	result := map[string]interface{}{
+		"runner":      p.name,
+		"project_dir": p.dir,
+		"command":     cmdline,
+		"success":     res.exitCode == 0,
+		"exit_code":   res.exitCode,
+		"diagnostics": diagnostics,
+		"duration_ms": res.duration.Milliseconds(),
+	}
+	if res.exitCode != 0 && len(diagnostics) == 0 {
+		result["output"] = p.truncate(strings.TrimSpace(string(res.stderr)))
+	}
+	return mcp.JSONResult(result)
+}
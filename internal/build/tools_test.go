@@ -0,0 +1,194 @@
+package build
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+func newTestServer(t *testing.T) (*Server, string) {
+	cfg := config.DefaultConfig().Build
+	cfg.AllowedProjectPaths = []string{t.TempDir()}
+	cfg.TimeoutSeconds = 120
+	return NewServer(&cfg), cfg.AllowedProjectPaths[0]
+}
+
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+const mathGo = `package calc
+
+func Add(a, b int) int { return a + b }
+
+func Sub(a, b int) int { return a - b }
+`
+
+func runTests(t *testing.T, s *Server, params map[string]interface{}) TestRun {
+	t.Helper()
+	result, err := s.runTestsTool().Handler(context.Background(), params)
+	if err != nil {
+		t.Fatalf("handleRunTests: %v", err)
+	}
+	var run TestRun
+	json.Unmarshal([]byte(result.Content[0].Text), &run)
+	return run
+}
+
+func TestRunGoTests(t *testing.T) {
+	s, dir := newTestServer(t)
+	writeFiles(t, dir, map[string]string{
+		"go.mod":  "module example.com/calc\n\ngo 1.22\n",
+		"calc.go": mathGo,
+		"calc_test.go": `package calc
+
+import "testing"
+
+func TestAdd(t *testing.T) {
+	for _, name := range []string{"small", "large"} {
+		t.Run(name, func(t *testing.T) {
+			if Add(2, 2) != 4 {
+				t.Fatal("bad sum")
+			}
+		})
+	}
+}
+
+func TestSub(t *testing.T) {
+	if Sub(2, 2) != 1 {
+		t.Errorf("Sub(2, 2) = %d", Sub(2, 2))
+	}
+}
+
+func TestSkipped(t *testing.T) { t.Skip("later") }
+`,
+	})
+
+	run := runTests(t, s, map[string]interface{}{"project_dir": dir, "coverage": true})
+	if run.Runner != "go" || run.Success {
+		t.Fatalf("got %+v", run)
+	}
+	if run.Summary != (Summary{Total: 4, Passed: 2, Failed: 1, Skipped: 1}) {
+		t.Errorf("summary: %+v", run.Summary)
+	}
+	if len(run.Failures) != 1 || run.Failures[0].Name != "TestSub" || !strings.Contains(run.Failures[0].Output, "Sub(2, 2) = 0") {
+		t.Errorf("failures: %+v", run.Failures)
+	}
+	if run.Coverage == nil || run.Coverage.Percent != 100 || run.Coverage.Suites["example.com/calc"] != 100 {
+		t.Errorf("coverage: %+v", run.Coverage)
+	}
+
+	rerun := runTests(t, s, map[string]interface{}{"project_dir": dir, "failed_only": true})
+	if rerun.Summary.Total != 1 || rerun.Summary.Failed != 1 {
+		t.Errorf("rerun: %+v", rerun.Summary)
+	}
+
+	writeFiles(t, dir, map[string]string{"calc_test.go": "package calc\n\nfunc broken() { undefined() }\n"})
+	broken := runTests(t, s, map[string]interface{}{"project_dir": dir})
+	if broken.Success || broken.Summary.Total != 0 || !strings.Contains(broken.Output, "undefined") {
+		t.Errorf("build failure: %+v", broken)
+	}
+}
+
+func TestRunBuild(t *testing.T) {
+	s, dir := newTestServer(t)
+	writeFiles(t, dir, map[string]string{
+		"go.mod":  "module example.com/calc\n\ngo 1.22\n",
+		"calc.go": mathGo + "\nfunc Mul(a, b int) int { return a * c }\n",
+	})
+
+	result, err := s.runBuildTool().Handler(context.Background(), map[string]interface{}{"project_dir": dir})
+	if err != nil {
+		t.Fatalf("handleRunBuild: %v", err)
+	}
+	var out struct {
+		Success     bool         `json:"success"`
+		Diagnostics []Diagnostic `json:"diagnostics"`
+	}
+	json.Unmarshal([]byte(result.Content[0].Text), &out)
+	if out.Success || len(out.Diagnostics) != 1 {
+		t.Fatalf("got %s", result.Content[0].Text)
+	}
+	d := out.Diagnostics[0]
+	if d.File != "./calc.go" || d.Line != 7 || d.Severity != "error" || !strings.Contains(d.Message, "undefined: c") {
+		t.Errorf("got %+v", d)
+	}
+}
+
+func TestRunTestsValidation(t *testing.T) {
+	s, dir := newTestServer(t)
+	writeFiles(t, dir, map[string]string{"go.mod": "module example.com/calc\n"})
+
+	for _, targets := range [][]interface{}{{"-exec=sh"}, {"../other"}, {"/etc"}} {
+		if _, err := s.runTestsTool().Handler(context.Background(), map[string]interface{}{
+			"project_dir": dir, "targets": targets,
+		}); err == nil {
+			t.Errorf("%v: accepted", targets)
+		}
+	}
+	if _, err := s.runTestsTool().Handler(context.Background(), map[string]interface{}{
+		"project_dir": dir, "failed_only": true,
+	}); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("failed_only without a previous run: got %v", err)
+	}
+}
+
+func TestParseCargoTest(t *testing.T) {
+	cases := parseCargoTest([]byte(`
+running 3 tests
+test parser::tests::empty ... ok
+test parser::tests::nested ... FAILED
+test parser::tests::slow ... ignored, takes a minute
+
+failures:
+
+---- parser::tests::nested stdout ----
+thread 'parser::tests::nested' panicked at src/parser.rs:41:9:
+assertion failed: depth < 3
+
+failures:
+    parser::tests::nested
+
+test result: FAILED. 1 passed; 1 failed; 1 ignored; 0 measured; 0 filtered out; finished in 0.01s
+`))
+	if len(cases) != 3 {
+		t.Fatalf("got %+v", cases)
+	}
+	if cases[1].Status != statusFailed || !strings.HasSuffix(cases[1].Output, "assertion failed: depth < 3") {
+		t.Errorf("failed test: %+v", cases[1])
+	}
+	if cases[2].Status != statusSkipped {
+		t.Errorf("ignored test: %+v", cases[2])
+	}
+}
+
+func TestParseJUnit(t *testing.T) {
+	cases, err := parseJUnit(strings.NewReader(`<?xml version="1.0" encoding="utf-8"?>
+<testsuites><testsuite name="pytest" tests="3">
+<testcase classname="tests.test_calc" name="test_add" time="0.002"/>
+<testcase classname="tests.test_calc" name="test_sub" time="0.150"><failure message="assert 0 == 1">def test_sub():
+&gt;       assert sub(2, 2) == 1</failure></testcase>
+<testcase classname="tests.test_calc" name="test_later" time="0"><skipped message="later"/></testcase>
+</testsuite></testsuites>`))
+	if err != nil {
+		t.Fatalf("parseJUnit: %v", err)
+	}
+	if len(cases) != 3 || cases[1].Status != statusFailed || cases[1].DurationMs != 150 || cases[2].Status != statusSkipped {
+		t.Fatalf("got %+v", cases)
+	}
+	if !strings.Contains(cases[1].Output, "assert sub(2, 2) == 1") {
+		t.Errorf("failure output: %q", cases[1].Output)
+	}
+}
@@ -0,0 +1,27 @@
+package cli
+
+import (
+	"io"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+// SetupAudit opens the audit trail that mutating tools write to. It returns
+// a no-op closer when auditing is disabled.
+func SetupAudit(cfg *config.AuditConfig) (io.Closer, error) {
+	if !cfg.Enabled {
+		return io.NopCloser(nil), nil
+	}
+
+	auditLog, err := common.NewAuditLog(cfg.File, common.RotateOptions{
+		MaxSizeBytes: int64(cfg.MaxSizeMB) * 1024 * 1024,
+		MaxBackups:   cfg.MaxBackups,
+	})
+	if err != nil {
+		return nil, err
+	}
+	common.SetAuditLog(auditLog)
+	common.SetAuditAllCalls(cfg.AllCalls)
+	return auditLog, nil
+}
@@ -0,0 +1,134 @@
+package cli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/local-mcps/dev-mcps/config"
+)
+
+// RunConfig implements `dev-mcps config init|validate|schema` and returns the
+// process exit code.
+func RunConfig(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		configUsage(stderr)
+		return 2
+	}
+
+	switch args[0] {
+	case "init":
+		return runConfigInit(args[1:], stdout, stderr)
+	case "validate":
+		return runConfigValidate(args[1:], stdout, stderr)
+	case "schema":
+		stdout.Write(config.Schema)
+		return 0
+	case "-h", "-help", "--help", "help":
+		configUsage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown config command: %s\n", args[0])
+		configUsage(stderr)
+		return 2
+	}
+}
+
+func configUsage(w io.Writer) {
+	fmt.Fprintln(w, "usage:")
+	fmt.Fprintln(w, "  config init [-path file] [-force]   write a commented default config and its schema")
+	fmt.Fprintln(w, "  config validate [-config file] [-show-secrets]  check a config and print the effective settings")
+	fmt.Fprintln(w, "  config schema                       print the JSON Schema for the config file")
+}
+
+func runConfigInit(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("config init", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("path", "", "Where to write the config (default: standard location)")
+	force := fs.Bool("force", false, "Overwrite an existing file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	target := config.ResolvePath(*path)
+	if target == "" {
+		fmt.Fprintln(stderr, "cannot determine config directory; pass -path")
+		return 1
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0700); err != nil {
+		fmt.Fprintf(stderr, "failed to create config directory: %v\n", err)
+		return 1
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_EXCL
+	if *force {
+		flags = os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	}
+
+	f, err := os.OpenFile(target, flags, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			fmt.Fprintf(stderr, "%s already exists; use -force to overwrite\n", target)
+		} else {
+			fmt.Fprintf(stderr, "failed to write config: %v\n", err)
+		}
+		return 1
+	}
+	defer f.Close()
+
+	modeline := "# yaml-language-server: $schema=./" + config.SchemaFile + "\n"
+	if _, err := f.Write(append([]byte(modeline), config.Example...)); err != nil {
+		fmt.Fprintf(stderr, "failed to write config: %v\n", err)
+		return 1
+	}
+
+	schemaPath := filepath.Join(filepath.Dir(target), config.SchemaFile)
+	if err := os.WriteFile(schemaPath, config.Schema, 0644); err != nil {
+		fmt.Fprintf(stderr, "failed to write schema: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "wrote %s\n", target)
+	fmt.Fprintf(stdout, "wrote %s\n", schemaPath)
+	return 0
+}
+
+func runConfigValidate(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	path := fs.String("config", "", "Path to configuration file (default: standard location)")
+	showSecrets := fs.Bool("show-secrets", false, "Print sensitive values instead of redacting them")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	cfg, err := config.LoadConfigStrict(*path)
+	if err != nil {
+		fmt.Fprintf(stderr, "invalid config: %v\n", err)
+		return 1
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(stderr, "invalid config %s:\n%v\n", config.ResolvePath(*path), err)
+		return 1
+	}
+
+	if !*showSecrets {
+		cfg = cfg.Redacted()
+	}
+
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		fmt.Fprintf(stderr, "failed to render config: %v\n", err)
+		return 1
+	}
+
+	fmt.Fprintf(stdout, "# %s is valid; effective configuration:\n", config.ResolvePath(*path))
+	stdout.Write(out)
+	return 0
+}
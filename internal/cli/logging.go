@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+// SetupLogging applies the global log level and format to server loggers and
+// tees them to global.log_file, rotating it according to the log_* settings.
+// It returns a no-op closer when no log file is set.
+func SetupLogging(cfg *config.GlobalConfig) (io.Closer, error) {
+	level := common.ParseLogLevel(cfg.LogLevel)
+	format := common.ParseLogFormat(cfg.LogFormat)
+
+	if cfg.LogFile == "" {
+		common.ConfigureLogging(level, format, os.Stderr)
+		return io.NopCloser(nil), nil
+	}
+
+	file, err := common.NewRotatingFile(cfg.LogFile, common.RotateOptions{
+		MaxSizeBytes:   int64(cfg.LogMaxSizeMB) * 1024 * 1024,
+		RotateInterval: time.Duration(cfg.LogRotateHours) * time.Hour,
+		MaxBackups:     cfg.LogMaxBackups,
+		MaxAge:         time.Duration(cfg.LogMaxAgeDays) * 24 * time.Hour,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	output := io.MultiWriter(os.Stderr, file)
+	common.ConfigureLogging(level, format, output)
+	log.SetOutput(output)
+	return file, nil
+}
@@ -0,0 +1,443 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/admin"
+	"github.com/local-mcps/dev-mcps/internal/build"
+	"github.com/local-mcps/dev-mcps/internal/command"
+	"github.com/local-mcps/dev-mcps/internal/database"
+	"github.com/local-mcps/dev-mcps/internal/docker"
+	"github.com/local-mcps/dev-mcps/internal/environment"
+	"github.com/local-mcps/dev-mcps/internal/filesystem"
+	"github.com/local-mcps/dev-mcps/internal/git"
+	"github.com/local-mcps/dev-mcps/internal/kubernetes"
+	"github.com/local-mcps/dev-mcps/internal/packages"
+	"github.com/local-mcps/dev-mcps/internal/process"
+	"github.com/local-mcps/dev-mcps/internal/reload"
+	"github.com/local-mcps/dev-mcps/internal/ssh"
+	"github.com/local-mcps/dev-mcps/internal/web"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// Version is reported by `dev-mcps version` and to clients on initialize.
+// Release builds override it with -ldflags "-X ...cli.Version=...".
+var Version = "1.0.0"
+
+// component is a sub-server; namespace prefixes its tools when
+// global.namespace_tools is set.
+type component struct {
+	name      string
+	namespace string
+	enabled   func(*config.Config) bool
+	build     func(*config.Config, *reload.Reloader) reload.Component
+}
+
+// servers are the sub-servers `serve` can run on their own. The admin tools
+// are added to every one of them when admin.enabled is set.
+var servers = []component{
+	{
+		name:      "filesystem",
+		namespace: "fs",
+		enabled:   func(c *config.Config) bool { return c.Filesystem.Enabled },
+		build: func(c *config.Config, _ *reload.Reloader) reload.Component {
+			return filesystem.NewServer(&c.Filesystem)
+		},
+	},
+	{
+		name:      "command",
+		namespace: "cmd",
+		enabled:   func(c *config.Config) bool { return c.Command.Enabled },
+		build: func(c *config.Config, _ *reload.Reloader) reload.Component {
+			return command.NewServer(&c.Command)
+		},
+	},
+	{
+		name:      "environment",
+		namespace: "env",
+		enabled:   func(c *config.Config) bool { return c.Environment.Enabled },
+		build: func(c *config.Config, _ *reload.Reloader) reload.Component {
+			return environment.NewServer(&c.Environment)
+		},
+	},
+	{
+		name:      "git",
+		namespace: "git",
+		enabled:   func(c *config.Config) bool { return c.Git.Enabled },
+		build: func(c *config.Config, _ *reload.Reloader) reload.Component {
+			return git.NewServer(&c.Git, &c.Command)
+		},
+	},
+	{
+		name:      "process",
+		namespace: "proc",
+		enabled:   func(c *config.Config) bool { return c.Process.Enabled },
+		build: func(c *config.Config, _ *reload.Reloader) reload.Component {
+			return process.NewServer(&c.Process)
+		},
+	},
+	{
+		name:      "web",
+		namespace: "web",
+		enabled:   func(c *config.Config) bool { return c.Web.Enabled },
+		build: func(c *config.Config, _ *reload.Reloader) reload.Component {
+			return web.NewServer(&c.Web)
+		},
+	},
+	{
+		name:      "database",
+		namespace: "db",
+		enabled:   func(c *config.Config) bool { return c.Database.Enabled },
+		build: func(c *config.Config, _ *reload.Reloader) reload.Component {
+			return database.NewServer(&c.Database)
+		},
+	},
+	{
+		name:      "docker",
+		namespace: "docker",
+		enabled:   func(c *config.Config) bool { return c.Docker.Enabled },
+		build: func(c *config.Config, _ *reload.Reloader) reload.Component {
+			return docker.NewServer(&c.Docker)
+		},
+	},
+	{
+		name:      "kubernetes",
+		namespace: "k8s",
+		enabled:   func(c *config.Config) bool { return c.Kubernetes.Enabled },
+		build: func(c *config.Config, _ *reload.Reloader) reload.Component {
+			return kubernetes.NewServer(&c.Kubernetes)
+		},
+	},
+	{
+		name:      "ssh",
+		namespace: "ssh",
+		enabled:   func(c *config.Config) bool { return c.SSH.Enabled },
+		build: func(c *config.Config, _ *reload.Reloader) reload.Component {
+			return ssh.NewServer(&c.SSH)
+		},
+	},
+	{
+		name:      "packages",
+		namespace: "pkg",
+		enabled:   func(c *config.Config) bool { return c.Packages.Enabled },
+		build: func(c *config.Config, _ *reload.Reloader) reload.Component {
+			return packages.NewServer(&c.Packages)
+		},
+	},
+	{
+		name:      "build",
+		namespace: "build",
+		enabled:   func(c *config.Config) bool { return c.Build.Enabled },
+		build: func(c *config.Config, _ *reload.Reloader) reload.Component {
+			return build.NewServer(&c.Build)
+		},
+	},
+}
+
+var adminComponent = component{
+	name:      "admin",
+	namespace: "admin",
+	enabled:   func(c *config.Config) bool { return c.Admin.Enabled },
+	build: func(c *config.Config, r *reload.Reloader) reload.Component {
+		return admin.NewServer(&c.Admin, r)
+	},
+}
+
+// namespaces maps the components to their tool prefixes.
+func namespaces(components []component) map[string]string {
+	m := make(map[string]string, len(components))
+	for _, c := range components {
+		m[c.name] = c.namespace
+	}
+	return m
+}
+
+// Main implements the dev-mcps command line and returns the process exit
+// code.
+func Main(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	switch args[0] {
+	case "serve":
+		return runServe(args[1:], stderr)
+	case "list-tools":
+		return runListTools(args[1:], stdout, stderr)
+	case "config":
+		return RunConfig(args[1:], stdout, stderr)
+	case "version":
+		fmt.Fprintf(stdout, "dev-mcps %s\n", Version)
+		return 0
+	case "-h", "-help", "--help", "help":
+		usage(stdout)
+		return 0
+	default:
+		fmt.Fprintf(stderr, "unknown command: %s\n", args[0])
+		usage(stderr)
+		return 2
+	}
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: dev-mcps <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	fmt.Fprintf(w, "  serve [all|%s] [-config file]  run an MCP server on stdio or HTTP (global.transport)\n", strings.Join(serverNames(), "|"))
+	fmt.Fprintln(w, "  list-tools [server] [-config file] [-json]  list the tools a server exposes")
+	fmt.Fprintln(w, "  config init|validate|schema  manage the config file")
+	fmt.Fprintln(w, "  version                      print the version")
+}
+
+func serverNames() []string {
+	names := make([]string, len(servers))
+	for i, c := range servers {
+		names[i] = c.name
+	}
+	return names
+}
+
+// selectServers returns the components `serve name` runs, including admin.
+func selectServers(name string) ([]component, error) {
+	if name == "all" {
+		return append(append([]component{}, servers...), adminComponent), nil
+	}
+	for _, c := range servers {
+		if c.name == name {
+			return []component{c, adminComponent}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown server %q (available: all, %s)", name, strings.Join(serverNames(), ", "))
+}
+
+// parseServerArgs accepts the server name before or after the flags.
+func parseServerArgs(fs *flag.FlagSet, args []string) (string, error) {
+	name := "all"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	if err := fs.Parse(args); err != nil {
+		return "", err
+	}
+	if fs.NArg() > 1 || (fs.NArg() == 1 && name != "all") {
+		return "", fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	if fs.NArg() == 1 {
+		name = fs.Arg(0)
+	}
+	return name, nil
+}
+
+func runServe(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "Path to configuration file")
+	name, err := parseServerArgs(fs, args)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	selected, err := selectServers(name)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	if name != "all" && !selected[0].enabled(cfg) {
+		fmt.Fprintf(stderr, "%s server is disabled in configuration\n", name)
+		return 1
+	}
+
+	logFile, err := SetupLogging(&cfg.Global)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to open log file: %v\n", err)
+		return 1
+	}
+	defer logFile.Close()
+
+	auditLog, err := SetupAudit(&cfg.Audit)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to open audit log: %v\n", err)
+		return 1
+	}
+	defer auditLog.Close()
+
+	serverName := name + "-server"
+	if name == "all" {
+		serverName = "local-mcps-all"
+	}
+	server := mcp.NewServer(serverName, Version)
+
+	// cfg is updated in place on reload, so overrides apply to later calls.
+	server.SetToolTimeout(func(tool string) time.Duration {
+		seconds, ok := cfg.Global.ToolTimeouts[tool]
+		if !ok {
+			seconds = cfg.Global.ToolTimeoutSeconds
+		}
+		return time.Duration(seconds) * time.Second
+	})
+
+	server.SetFraming(mcp.Framing(cfg.Global.StdioFraming))
+	server.SetStrictParams(func() bool { return cfg.Global.StrictParams })
+	reloader := reload.New(server, cfg, *configPath)
+	reloader.SetNamespaces(namespaces(selected))
+	for _, c := range selected {
+		reloader.Add(c.name, c.build(cfg, reloader), c.enabled)
+	}
+	server.SetRateLimit(func(tool string) (string, mcp.RateLimit, bool) {
+		key := tool
+		limit, ok := cfg.Global.RateLimits[key]
+		if !ok {
+			key = reloader.Owner(tool)
+			limit, ok = cfg.Global.RateLimits[key]
+		}
+		return key, mcp.RateLimit{PerMinute: limit.RequestsPerMinute, Burst: limit.Burst}, ok
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go reloader.Run(ctx)
+
+	shutdown := func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(),
+			time.Duration(cfg.Global.ShutdownTimeoutSeconds)*time.Second)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Printf("Cancelled tool calls still running after %ds", cfg.Global.ShutdownTimeoutSeconds)
+		}
+	}
+	// A signal drains in-flight requests instead of cancelling them; a
+	// second one kills the process.
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		signal.Stop(signals)
+		log.Printf("Received %s, shutting down %s...", sig, serverName)
+		shutdown()
+	}()
+
+	if cfg.Global.Transport == "http" {
+		addr := net.JoinHostPort(cfg.Global.HTTPHost, strconv.Itoa(cfg.Global.HTTPPort))
+		server.SetPingInterval(time.Duration(cfg.Global.HTTPPingSeconds) * time.Second)
+		server.SetAllowedHosts(append([]string{cfg.Global.HTTPHost}, cfg.Global.HTTPAllowedHosts...))
+		log.Printf("Starting %s on http://%s/sse...", serverName, addr)
+		err = server.RunHTTP(ctx, addr)
+	} else {
+		log.Printf("Starting %s...", serverName)
+		err = server.Run(ctx)
+	}
+	// Input may end without a signal; the modules still need cleaning up.
+	shutdown()
+	if err != nil && err != context.Canceled {
+		fmt.Fprintf(stderr, "Server error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+type toolInfo struct {
+	Server       string                 `json:"server"`
+	Name         string                 `json:"name"`
+	Description  string                 `json:"description"`
+	InputSchema  map[string]interface{} `json:"inputSchema,omitempty"`
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	Annotations  *mcp.ToolAnnotations   `json:"annotations,omitempty"`
+}
+
+func runListTools(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("list-tools", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", "", "Path to configuration file")
+	asJSON := fs.Bool("json", false, "Print the tools with their input schemas as JSON")
+	name, err := parseServerArgs(fs, args)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	selected, err := selectServers(name)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 2
+	}
+
+	cfg, err := config.LoadConfig(*configPath)
+	if err != nil {
+		fmt.Fprintf(stderr, "Failed to load config: %v\n", err)
+		return 1
+	}
+
+	reloader := reload.New(mcp.NewServer("list-tools", Version), cfg, *configPath)
+
+	provided := make([][]*mcp.Tool, len(selected))
+	providers := make(map[string]int)
+	for i, c := range selected {
+		if c.enabled(cfg) {
+			provided[i] = c.build(cfg, reloader).Tools()
+		}
+		for _, tool := range provided[i] {
+			providers[tool.Name]++
+		}
+	}
+
+	var tools []toolInfo
+	seen := make(map[string]bool)
+	for i, c := range selected {
+		for _, tool := range provided[i] {
+			if seen[tool.Name] {
+				continue
+			}
+			seen[tool.Name] = true
+			tools = append(tools, toolInfo{
+				Server:       c.name,
+				Name:         reload.ToolName(cfg, c.namespace, tool.Name, providers[tool.Name] > 1),
+				Description:  tool.Description,
+				InputSchema:  tool.InputSchema,
+				OutputSchema: tool.OutputSchema,
+				Annotations:  tool.Annotations,
+			})
+		}
+	}
+
+	if *asJSON {
+		data, err := json.MarshalIndent(tools, "", "  ")
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return 1
+		}
+		fmt.Fprintln(stdout, string(data))
+		return 0
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "SERVER\tTOOL\tDESCRIPTION")
+	for _, t := range tools {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", t.Server, t.Name, t.Description)
+	}
+	tw.Flush()
+	return 0
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
 )
 
 type AsyncCommand struct {
@@ -21,6 +22,9 @@ type AsyncCommand struct {
 	Status    string
 	ExitCode  int
 	Cancel    context.CancelFunc
+	// Session is the HTTP client that started the command, which alone
+	// can see it; nil over stdio.
+	Session *mcp.Session
 }
 
 type Executor struct {
@@ -93,7 +97,9 @@ func (e *Executor) RunSync(ctx context.Context, command string, args []string, c
 	return result, nil
 }
 
-func (e *Executor) RunAsync(command string, args []string, cwd string, env map[string]string) (string, error) {
+// RunAsync starts command in the background for session, which may be nil.
+// The command is cancelled and forgotten when the session closes.
+func (e *Executor) RunAsync(session *mcp.Session, command string, args []string, cwd string, env map[string]string) (string, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	cmd := exec.CommandContext(ctx, command, args...)
@@ -123,6 +129,7 @@ func (e *Executor) RunAsync(command string, args []string, cwd string, env map[s
 		StartTime: time.Now(),
 		Status:    "running",
 		Cancel:    cancel,
+		Session:   session,
 	}
 
 	if err := cmd.Start(); err != nil {
@@ -131,6 +138,12 @@ func (e *Executor) RunAsync(command string, args []string, cwd string, env map[s
 	}
 
 	e.asyncCommands.Store(asyncCmd.ID, asyncCmd)
+	if session != nil {
+		session.OnClose(func() {
+			e.cancel(asyncCmd)
+			e.asyncCommands.Delete(asyncCmd.ID)
+		})
+	}
 
 	go func() {
 		err := cmd.Wait()
@@ -153,25 +166,45 @@ func (e *Executor) RunAsync(command string, args []string, cwd string, env map[s
 	return asyncCmd.ID, nil
 }
 
-func (e *Executor) GetStatus(commandID string) (*AsyncCommand, bool) {
+// GetStatus returns the async command commandID if session started it.
+func (e *Executor) GetStatus(session *mcp.Session, commandID string) (*AsyncCommand, bool) {
 	if v, ok := e.asyncCommands.Load(commandID); ok {
-		return v.(*AsyncCommand), true
+		if asyncCmd := v.(*AsyncCommand); asyncCmd.Session == session {
+			return asyncCmd, true
+		}
 	}
 	return nil, false
 }
 
-func (e *Executor) CancelCommand(commandID string) bool {
-	if v, ok := e.asyncCommands.Load(commandID); ok {
-		asyncCmd := v.(*AsyncCommand)
-		if asyncCmd.Status == "running" {
-			asyncCmd.Cancel()
-			asyncCmd.Status = "cancelled"
-			return true
-		}
+// CancelCommand cancels the async command commandID if session started it
+// and it is still running.
+func (e *Executor) CancelCommand(session *mcp.Session, commandID string) bool {
+	asyncCmd, ok := e.GetStatus(session, commandID)
+	return ok && e.cancel(asyncCmd)
+}
+
+func (e *Executor) cancel(asyncCmd *AsyncCommand) bool {
+	if asyncCmd.Status == "running" {
+		asyncCmd.Cancel()
+		asyncCmd.Status = "cancelled"
+		return true
 	}
 	return false
 }
 
+// CancelAll cancels every running async command and reports how many it
+// cancelled.
+func (e *Executor) CancelAll() int {
+	cancelled := 0
+	e.asyncCommands.Range(func(_, v interface{}) bool {
+		if e.cancel(v.(*AsyncCommand)) {
+			cancelled++
+		}
+		return true
+	})
+	return cancelled
+}
+
 type CommandResult struct {
 	ExitCode   int    `json:"exit_code"`
 	Stdout     string `json:"stdout"`
@@ -1,32 +1,78 @@
 package command
 
 import (
+	"context"
+
 	"github.com/local-mcps/dev-mcps/config"
 	"github.com/local-mcps/dev-mcps/internal/common"
 	"github.com/local-mcps/dev-mcps/pkg/mcp"
 )
 
 type Server struct {
-	config    *config.CommandConfig
-	validator *common.CommandValidator
-	logger    *common.Logger
-	executor  *Executor
+	config     *config.CommandConfig
+	validator  *common.CommandValidator
+	logger     *common.Logger
+	executor   *Executor
+	sessionEnv *common.SessionEnv
 }
 
 func NewServer(cfg *config.CommandConfig) *Server {
-	return &Server{
-		config:    cfg,
-		validator: common.NewCommandValidator(cfg.AllowedCommands, cfg.DeniedCommands),
-		logger:    common.NewLogger(common.LogLevelInfo, common.LogFormatJSON, nil, "command"),
-		executor:  NewExecutor(cfg),
+	s := &Server{
+		config:     cfg,
+		logger:     common.NewServerLogger("command"),
+		executor:   NewExecutor(cfg),
+		sessionEnv: common.DefaultSessionEnv(),
+	}
+	s.Reload()
+	common.RegisterConfigSection("command", s.configSection)
+	return s
+}
+
+// Reload rebuilds the command policies. An invalid policy denies every
+// command rather than silently dropping the rule.
+func (s *Server) Reload() {
+	validator, err := common.NewCommandValidatorFromConfig(s.config)
+	if err != nil {
+		s.logger.Errorf("invalid command policy, denying all commands: %v", err)
+		validator = &common.CommandValidator{DefaultDeny: true}
 	}
+	s.validator = validator
+}
+
+// Shutdown cancels the async commands still running, which would otherwise
+// outlive the server unobserved.
+func (s *Server) Shutdown(ctx context.Context) {
+	if n := s.executor.CancelAll(); n > 0 {
+		s.logger.Infof("cancelled %d running async commands", n)
+	}
+}
+
+// env returns the session environment of the client calling a tool.
+func (s *Server) env(ctx context.Context) *common.SessionEnv {
+	return common.SessionEnvFor(ctx, s.sessionEnv)
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		common.Audited("command", s.runCommandTool()),
+		common.Audited("command", s.runCommandAsyncTool()),
+		s.getCommandStatusTool(),
+		common.Audited("command", s.cancelCommandTool()),
+		common.Audited("command", s.runScriptTool()),
+		s.getShellInfoTool(),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
+		common.AuditLogTool(),
+	}
+	return common.Instrument("command", tools)
 }
 
 func (s *Server) RegisterTools(server *mcp.Server) {
-	server.RegisterTool(s.runCommandTool())
-	server.RegisterTool(s.runCommandAsyncTool())
-	server.RegisterTool(s.getCommandStatusTool())
-	server.RegisterTool(s.cancelCommandTool())
-	server.RegisterTool(s.runScriptTool())
-	server.RegisterTool(s.getShellInfoTool())
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
 }
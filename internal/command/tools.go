@@ -25,26 +25,27 @@ func (s *Server) runCommandTool() *mcp.Tool {
 			},
 			[]string{"command"},
 		),
-		Handler: s.handleRunCommand,
+		Annotations: mcp.Destructive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleRunCommand),
 	}
 }
 
-func (s *Server) handleRunCommand(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	command, err := mcp.GetStringParam(params, "command", true)
-	if err != nil {
-		return nil, err
-	}
+type runCommandArgs struct {
+	Command        string            `json:"command" validate:"required"`
+	Args           []string          `json:"args"`
+	Cwd            string            `json:"cwd"`
+	Env            map[string]string `json:"env"`
+	TimeoutSeconds int               `json:"timeout_seconds"`
+}
 
-	args, _ := mcp.GetStringArrayParam(params, "args", false)
-	cwd, _ := mcp.GetStringParam(params, "cwd", false)
-	env, _ := mcp.GetMapParam(params, "env", false)
-	timeout, _ := mcp.GetIntParam(params, "timeout_seconds", false, s.config.DefaultTimeoutSeconds)
+func (s *Server) handleRunCommand(ctx context.Context, args runCommandArgs) (*mcp.ToolResult, error) {
+	env := s.env(ctx).Merge(args.Env)
 
-	if err := s.validator.ValidateCommand(command, args); err != nil {
+	if err := s.validator.ValidateCommand(args.Command, args.Args); err != nil {
 		return nil, err
 	}
 
-	result, err := s.executor.RunSync(ctx, command, args, cwd, env, timeout)
+	result, err := s.executor.RunSync(ctx, args.Command, args.Args, args.Cwd, env, args.TimeoutSeconds)
 	if err != nil {
 		return nil, err
 	}
@@ -65,25 +66,26 @@ func (s *Server) runCommandAsyncTool() *mcp.Tool {
 			},
 			[]string{"command"},
 		),
-		Handler: s.handleRunCommandAsync,
+		Annotations: mcp.Destructive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleRunCommandAsync),
 	}
 }
 
-func (s *Server) handleRunCommandAsync(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	command, err := mcp.GetStringParam(params, "command", true)
-	if err != nil {
-		return nil, err
-	}
+type runCommandAsyncArgs struct {
+	Command string            `json:"command" validate:"required"`
+	Args    []string          `json:"args"`
+	Cwd     string            `json:"cwd"`
+	Env     map[string]string `json:"env"`
+}
 
-	args, _ := mcp.GetStringArrayParam(params, "args", false)
-	cwd, _ := mcp.GetStringParam(params, "cwd", false)
-	env, _ := mcp.GetMapParam(params, "env", false)
+func (s *Server) handleRunCommandAsync(ctx context.Context, args runCommandAsyncArgs) (*mcp.ToolResult, error) {
+	env := s.env(ctx).Merge(args.Env)
 
-	if err := s.validator.ValidateCommand(command, args); err != nil {
+	if err := s.validator.ValidateCommand(args.Command, args.Args); err != nil {
 		return nil, err
 	}
 
-	commandID, err := s.executor.RunAsync(command, args, cwd, env)
+	commandID, err := s.executor.RunAsync(mcp.SessionFromContext(ctx), args.Command, args.Args, args.Cwd, env)
 	if err != nil {
 		return nil, err
 	}
@@ -104,17 +106,19 @@ func (s *Server) getCommandStatusTool() *mcp.Tool {
 			},
 			[]string{"command_id"},
 		),
-		Handler: s.handleGetCommandStatus,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGetCommandStatus),
 	}
 }
 
-func (s *Server) handleGetCommandStatus(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	commandID, err := mcp.GetStringParam(params, "command_id", true)
-	if err != nil {
-		return nil, err
-	}
+type commandIDArgs struct {
+	CommandID string `json:"command_id" validate:"required"`
+}
 
-	asyncCmd, found := s.executor.GetStatus(commandID)
+func (s *Server) handleGetCommandStatus(ctx context.Context, args commandIDArgs) (*mcp.ToolResult, error) {
+	commandID := args.CommandID
+
+	asyncCmd, found := s.executor.GetStatus(mcp.SessionFromContext(ctx), commandID)
 	if !found {
 		return nil, fmt.Errorf("command not found: %s", commandID)
 	}
@@ -146,17 +150,15 @@ func (s *Server) cancelCommandTool() *mcp.Tool {
 			},
 			[]string{"command_id"},
 		),
-		Handler: s.handleCancelCommand,
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleCancelCommand),
 	}
 }
 
-func (s *Server) handleCancelCommand(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	commandID, err := mcp.GetStringParam(params, "command_id", true)
-	if err != nil {
-		return nil, err
-	}
+func (s *Server) handleCancelCommand(ctx context.Context, args commandIDArgs) (*mcp.ToolResult, error) {
+	commandID := args.CommandID
 
-	if s.executor.CancelCommand(commandID) {
+	if s.executor.CancelCommand(mcp.SessionFromContext(ctx), commandID) {
 		return mcp.TextResult(fmt.Sprintf("Command %s cancelled", commandID)), nil
 	}
 
@@ -176,19 +178,20 @@ func (s *Server) runScriptTool() *mcp.Tool {
 			},
 			[]string{"path"},
 		),
-		Handler: s.handleRunScript,
+		Annotations: mcp.Destructive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleRunScript),
 	}
 }
 
-func (s *Server) handleRunScript(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	path, err := mcp.GetStringParam(params, "path", true)
-	if err != nil {
-		return nil, err
-	}
+type runScriptArgs struct {
+	Path        string   `json:"path" validate:"required"`
+	Interpreter string   `json:"interpreter"`
+	Args        []string `json:"args"`
+	Cwd         string   `json:"cwd"`
+}
 
-	interpreter, _ := mcp.GetStringParam(params, "interpreter", false)
-	args, _ := mcp.GetStringArrayParam(params, "args", false)
-	cwd, _ := mcp.GetStringParam(params, "cwd", false)
+func (s *Server) handleRunScript(ctx context.Context, args runScriptArgs) (*mcp.ToolResult, error) {
+	path, interpreter := args.Path, args.Interpreter
 
 	if _, err := os.Stat(path); os.IsNotExist(err) {
 		return nil, fmt.Errorf("script not found: %s", path)
@@ -198,9 +201,13 @@ func (s *Server) handleRunScript(ctx context.Context, params map[string]interfac
 		interpreter = s.config.DefaultShell
 	}
 
-	scriptArgs := append([]string{path}, args...)
+	scriptArgs := append([]string{path}, args.Args...)
+
+	if err := s.validator.ValidateCommand(interpreter, scriptArgs); err != nil {
+		return nil, err
+	}
 
-	result, err := s.executor.RunSync(ctx, interpreter, scriptArgs, cwd, nil, s.config.DefaultTimeoutSeconds)
+	result, err := s.executor.RunSync(ctx, interpreter, scriptArgs, args.Cwd, s.env(ctx).Merge(nil), s.config.DefaultTimeoutSeconds)
 	if err != nil {
 		return nil, err
 	}
@@ -216,7 +223,8 @@ func (s *Server) getShellInfoTool() *mcp.Tool {
 			map[string]interface{}{},
 			[]string{},
 		),
-		Handler: s.handleGetShellInfo,
+		Annotations: mcp.ReadOnly(),
+		Handler:     s.handleGetShellInfo,
 	}
 }
 
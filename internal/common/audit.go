@@ -0,0 +1,390 @@
+package common
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxAuditValueLen bounds string values kept in audit details so file
+// contents and scripts are summarized rather than copied into the trail.
+const maxAuditValueLen = 256
+
+// AuditEntry is one line of the audit trail. Result summarizes a tool call's
+// result without its content. Hash covers every other field, including
+// PrevHash, so editing or dropping an entry breaks the chain.
+type AuditEntry struct {
+	Seq        int64                  `json:"seq"`
+	Time       time.Time              `json:"time"`
+	Server     string                 `json:"server"`
+	Action     string                 `json:"action"`
+	Target     string                 `json:"target,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	Success    bool                   `json:"success"`
+	Error      string                 `json:"error,omitempty"`
+	Result     string                 `json:"result,omitempty"`
+	DurationMS float64                `json:"duration_ms,omitempty"`
+	PrevHash   string                 `json:"prev_hash"`
+	Hash       string                 `json:"hash"`
+}
+
+// AuditRedactor edits an entry before it is hashed and written.
+type AuditRedactor func(*AuditEntry)
+
+// AuditLog is an append-only, hash-chained JSON lines file. It rotates like
+// the log file and the chain continues across rotated files.
+type AuditLog struct {
+	mu        sync.Mutex
+	path      string
+	file      *RotatingFile
+	seq       int64
+	lastHash  string
+	redactors []AuditRedactor
+}
+
+func NewAuditLog(path string, opts RotateOptions) (*AuditLog, error) {
+	a := &AuditLog{
+		path:      path,
+		redactors: []AuditRedactor{redactAuditDetails},
+	}
+
+	if last, ok := lastAuditEntry(path); ok {
+		a.seq = last.Seq
+		a.lastHash = last.Hash
+	}
+
+	file, err := NewRotatingFile(path, opts)
+	if err != nil {
+		return nil, err
+	}
+	a.file = file
+	return a, nil
+}
+
+func (a *AuditLog) AddRedactor(fn AuditRedactor) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.redactors = append(a.redactors, fn)
+}
+
+func (a *AuditLog) Record(entry AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	entry.Seq = a.seq + 1
+	entry.Time = time.Now().UTC()
+	for _, redact := range a.redactors {
+		redact(&entry)
+	}
+	entry.PrevHash = a.lastHash
+	entry.Hash = hashAuditEntry(entry)
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := a.file.Write(append(data, '\n')); err != nil {
+		return err
+	}
+
+	a.seq = entry.Seq
+	a.lastHash = entry.Hash
+	return nil
+}
+
+// Entries returns every entry still on disk, oldest first.
+func (a *AuditLog) Entries() ([]AuditEntry, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var entries []AuditEntry
+	for _, path := range auditFiles(a.path) {
+		fileEntries, err := readAuditFile(path)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+	return entries, nil
+}
+
+func (a *AuditLog) Close() error {
+	return a.file.Close()
+}
+
+// VerifyAuditChain checks that every entry's hash matches its contents and
+// links to the entry before it. The first entry may point at a pruned file.
+func VerifyAuditChain(entries []AuditEntry) error {
+	for i, entry := range entries {
+		if hashAuditEntry(entry) != entry.Hash {
+			return fmt.Errorf("entry %d: hash mismatch", entry.Seq)
+		}
+		if i > 0 && entry.PrevHash != entries[i-1].Hash {
+			return fmt.Errorf("entry %d: does not follow entry %d", entry.Seq, entries[i-1].Seq)
+		}
+	}
+	return nil
+}
+
+func hashAuditEntry(entry AuditEntry) string {
+	entry.Hash = ""
+	data, _ := json.Marshal(entry)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func redactAuditDetails(entry *AuditEntry) {
+	for k, v := range entry.Details {
+		v = redactField(k, v)
+		if s, ok := v.(string); ok && len(s) > maxAuditValueLen {
+			v = fmt.Sprintf("<%d bytes>", len(s))
+		}
+		entry.Details[k] = v
+	}
+}
+
+// auditFiles lists the rotated audit files followed by the active one.
+func auditFiles(path string) []string {
+	var paths []string
+	for _, b := range rotatedFiles(path) {
+		paths = append(paths, b.path)
+	}
+	return append(paths, path)
+}
+
+func lastAuditEntry(path string) (AuditEntry, bool) {
+	paths := auditFiles(path)
+	for i := len(paths) - 1; i >= 0; i-- {
+		entries, err := readAuditFile(paths[i])
+		if err == nil && len(entries) > 0 {
+			return entries[len(entries)-1], true
+		}
+	}
+	return AuditEntry{}, false
+}
+
+func readAuditFile(path string) ([]AuditEntry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s: malformed audit entry: %w", path, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, scanner.Err()
+}
+
+var (
+	auditMu  sync.RWMutex
+	auditLog *AuditLog
+)
+
+// SetAuditLog sets the trail written by RecordAudit and the Audited tool
+// wrapper. A nil log disables auditing.
+func SetAuditLog(log *AuditLog) {
+	auditMu.Lock()
+	defer auditMu.Unlock()
+	auditLog = log
+}
+
+func currentAuditLog() *AuditLog {
+	auditMu.RLock()
+	defer auditMu.RUnlock()
+	return auditLog
+}
+
+func RecordAudit(entry AuditEntry) {
+	log := currentAuditLog()
+	if log == nil {
+		return
+	}
+	if err := log.Record(entry); err != nil {
+		defaultLogger.WithField("action", entry.Action).Errorf("failed to write audit entry: %v", err)
+	}
+}
+
+var auditTargetKeys = []string{"path", "source", "file_path", "repo_path", "url", "command", "command_id", "pid", "setting"}
+
+// auditMutatingOnly is the inverse of audit.all_calls, so that its zero
+// value gives the default of recording every call.
+var auditMutatingOnly atomic.Bool
+
+// SetAuditAllCalls sets whether Instrument records every tool call in the
+// audit trail, the default, or only those of Audited tools.
+func SetAuditAllCalls(all bool) {
+	auditMutatingOnly.Store(!all)
+}
+
+// auditedKey marks a call's context with a flag Audited sets once it has
+// recorded the call, so Instrument does not record it twice.
+type auditedKey struct{}
+
+// Audited wraps a mutating tool so every call, successful or not, is
+// recorded in the audit trail along with its (redacted) parameters.
+func Audited(server string, tool *mcp.Tool) *mcp.Tool {
+	handler := tool.Handler
+	audited := *tool
+	audited.Handler = func(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
+		start := time.Now()
+		result, err := handler(ctx, params)
+		auditCall(server, tool.Name, params, result, err, time.Since(start))
+		if recorded, ok := ctx.Value(auditedKey{}).(*bool); ok {
+			*recorded = true
+		}
+		return result, err
+	}
+	return &audited
+}
+
+func auditCall(server, tool string, params map[string]interface{}, result *mcp.ToolResult, err error, duration time.Duration) {
+	entry := AuditEntry{
+		Server:     server,
+		Action:     tool,
+		Details:    make(map[string]interface{}, len(params)),
+		Success:    err == nil && (result == nil || !result.IsError),
+		Result:     summarizeResult(result),
+		DurationMS: millis(duration),
+	}
+	for k, v := range params {
+		entry.Details[k] = v
+	}
+	for _, key := range auditTargetKeys {
+		if v, ok := params[key]; ok {
+			entry.Target = fmt.Sprint(v)
+			break
+		}
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	RecordAudit(entry)
+}
+
+// summarizeResult describes a result by the size of its content, which may
+// hold file contents or secrets, and quotes the text of error results.
+func summarizeResult(result *mcp.ToolResult) string {
+	if result == nil {
+		return ""
+	}
+	size := 0
+	for _, block := range result.Content {
+		size += len(block.Text)
+		if block.Resource != nil {
+			size += len(block.Resource.Text) + len(block.Resource.Blob)
+		}
+	}
+	if result.IsError && len(result.Content) > 0 {
+		text := result.Content[0].Text
+		if len(text) > maxAuditValueLen {
+			text = text[:maxAuditValueLen] + "..."
+		}
+		return "error: " + text
+	}
+	return fmt.Sprintf("%d content blocks, %d bytes", len(result.Content), size)
+}
+
+// AuditLogTool returns the get_audit_log tool. Like get_server_config it is
+// shared by every sub-server that writes audit entries.
+func AuditLogTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "get_audit_log",
+		Description: "Review recent mutating actions (file writes, commands, git and process changes) from the audit trail, newest first, and check that it has not been tampered with",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"server": mcp.StringProperty("Only show entries from this server (e.g. filesystem)"),
+				"action": mcp.StringProperty("Only show this action (tool name, e.g. write_file)"),
+				"since":  mcp.StringProperty("Only show entries after this RFC3339 time or within this duration (e.g. 1h)"),
+				"limit":  mcp.IntProperty("Maximum number of entries to return (default: 50)"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(handleGetAuditLog),
+	}
+}
+
+type auditLogArgs struct {
+	Server string `json:"server"`
+	Action string `json:"action"`
+	Since  string `json:"since"`
+	Limit  int    `json:"limit" default:"50"`
+}
+
+func handleGetAuditLog(ctx context.Context, args auditLogArgs) (*mcp.ToolResult, error) {
+	log := currentAuditLog()
+	if log == nil {
+		return nil, fmt.Errorf("%w: audit trail is disabled (audit.enabled)", ErrNotFound)
+	}
+
+	server, action, sinceStr, limit := args.Server, args.Action, args.Since, args.Limit
+
+	var since time.Time
+	if sinceStr != "" {
+		if d, err := time.ParseDuration(sinceStr); err == nil {
+			since = time.Now().Add(-d)
+		} else if t, err := time.Parse(time.RFC3339, sinceStr); err == nil {
+			since = t
+		} else {
+			return nil, fmt.Errorf("%w: since must be an RFC3339 time or a duration", ErrInvalidInput)
+		}
+	}
+
+	entries, err := log.Entries()
+	if err != nil {
+		return nil, err
+	}
+
+	chainValid := true
+	chainError := ""
+	if err := VerifyAuditChain(entries); err != nil {
+		chainValid = false
+		chainError = err.Error()
+	}
+
+	matched := make([]AuditEntry, 0, limit)
+	for i := len(entries) - 1; i >= 0 && len(matched) < limit; i-- {
+		entry := entries[i]
+		if server != "" && entry.Server != server {
+			continue
+		}
+		if action != "" && entry.Action != action {
+			continue
+		}
+		if !since.IsZero() && entry.Time.Before(since) {
+			break
+		}
+		matched = append(matched, entry)
+	}
+
+	result := map[string]interface{}{
+		"entries":     matched,
+		"count":       len(matched),
+		"total_count": len(entries),
+		"chain_valid": chainValid,
+	}
+	if chainError != "" {
+		result["chain_error"] = chainError
+	}
+	return mcp.JSONResult(result)
+}
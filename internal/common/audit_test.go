@@ -0,0 +1,109 @@
+package common
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+
+	a, err := NewAuditLog(path, RotateOptions{})
+	require.NoError(t, err)
+	require.NoError(t, a.Record(AuditEntry{Server: "filesystem", Action: "write_file", Target: "/tmp/a", Success: true}))
+	require.NoError(t, a.Record(AuditEntry{
+		Server:  "command",
+		Action:  "run_command",
+		Details: map[string]interface{}{"api_token": "secret", "content": strings.Repeat("x", 1000)},
+		Success: true,
+	}))
+	require.NoError(t, a.Close())
+
+	t.Run("chain continues after reopening", func(t *testing.T) {
+		a, err := NewAuditLog(path, RotateOptions{})
+		require.NoError(t, err)
+		defer a.Close()
+		require.NoError(t, a.Record(AuditEntry{Server: "git", Action: "git_commit", Success: true}))
+
+		entries, err := a.Entries()
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, int64(3), entries[2].Seq)
+		assert.NoError(t, VerifyAuditChain(entries))
+	})
+
+	t.Run("details are redacted", func(t *testing.T) {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(data), "secret")
+		assert.Contains(t, string(data), "1000 bytes")
+	})
+
+	t.Run("tampering breaks the chain", func(t *testing.T) {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		tampered := strings.Replace(string(data), "/tmp/a", "/tmp/b", 1)
+		require.NoError(t, os.WriteFile(path, []byte(tampered), 0600))
+
+		entries, err := readAuditFile(path)
+		require.NoError(t, err)
+		assert.ErrorContains(t, VerifyAuditChain(entries), "entry 1: hash mismatch")
+
+		lines := strings.SplitAfter(string(data), "\n")
+		require.NoError(t, os.WriteFile(path, []byte(lines[0]+lines[2]), 0600))
+
+		entries, err = readAuditFile(path)
+		require.NoError(t, err)
+		assert.ErrorContains(t, VerifyAuditChain(entries), "does not follow")
+	})
+}
+
+func TestAuditAllCalls(t *testing.T) {
+	assert.True(t, config.DefaultConfig().Audit.AllCalls)
+	assert.False(t, auditMutatingOnly.Load(), "every call is recorded until SetAuditAllCalls(false)")
+
+	a, err := NewAuditLog(filepath.Join(t.TempDir(), "audit.log"), RotateOptions{})
+	require.NoError(t, err)
+	defer a.Close()
+	SetAuditLog(a)
+	defer SetAuditLog(nil)
+
+	handler := func(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
+		return mcp.TextResult("file contents"), nil
+	}
+	tools := Instrument("filesystem", []*mcp.Tool{
+		{Name: "read_file", Handler: handler},
+		Audited("filesystem", &mcp.Tool{Name: "write_file", Handler: handler}),
+	})
+	callAll := func() {
+		for _, tool := range tools {
+			_, err := tool.Handler(context.Background(), map[string]interface{}{"path": "/tmp/a"})
+			require.NoError(t, err)
+		}
+	}
+
+	SetAuditAllCalls(false)
+	callAll()
+	SetAuditAllCalls(true)
+	callAll()
+
+	entries, err := a.Entries()
+	require.NoError(t, err)
+	var actions []string
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	assert.Equal(t, []string{"write_file", "read_file", "write_file"}, actions)
+	assert.Equal(t, "/tmp/a", entries[1].Target)
+	assert.Equal(t, "1 content blocks, 13 bytes", entries[1].Result)
+	assert.NotContains(t, entries[1].Result, "file contents")
+}
@@ -0,0 +1,242 @@
+package common
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/config"
+)
+
+// CommandDeniedError reports which rule rejected a command. It wraps
+// ErrCommandDenied.
+type CommandDeniedError struct {
+	Rule    string `json:"rule"`
+	Reason  string `json:"reason"`
+	Command string `json:"command"`
+}
+
+func (e *CommandDeniedError) Error() string {
+	return fmt.Sprintf("%v: %s (rule: %s)", ErrCommandDenied, e.Reason, e.Rule)
+}
+
+func (e *CommandDeniedError) Unwrap() error {
+	return ErrCommandDenied
+}
+
+func (e *CommandDeniedError) ErrorDetails() map[string]interface{} {
+	details := ErrCommandDenied.ErrorDetails()
+	details["rule"] = e.Rule
+	details["reason"] = e.Reason
+	details["command"] = e.Command
+	return details
+}
+
+// CommandPolicy is a compiled config.CommandPolicy.
+type CommandPolicy struct {
+	Name      string
+	Deny      bool
+	Binary    string
+	Match     *regexp.Regexp
+	AllowArgs []*regexp.Regexp
+	DenyArgs  []*regexp.Regexp
+	MaxArgs   int
+	Resolve   string
+}
+
+func CompileCommandPolicy(name string, p config.CommandPolicy) (CommandPolicy, error) {
+	policy := CommandPolicy{
+		Name:    name,
+		Deny:    p.Action == "deny",
+		Binary:  p.Binary,
+		MaxArgs: p.MaxArgs,
+		Resolve: p.Resolve,
+	}
+	if p.Name != "" {
+		policy.Name = p.Name
+	}
+
+	var err error
+	if p.Match != "" {
+		if policy.Match, err = regexp.Compile(p.Match); err != nil {
+			return policy, fmt.Errorf("%s: %w", policy.Name, err)
+		}
+	}
+	for _, pattern := range p.AllowArgs {
+		re, err := regexp.Compile("^(?:" + pattern + ")$")
+		if err != nil {
+			return policy, fmt.Errorf("%s: %w", policy.Name, err)
+		}
+		policy.AllowArgs = append(policy.AllowArgs, re)
+	}
+	for _, pattern := range p.DenyArgs {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return policy, fmt.Errorf("%s: %w", policy.Name, err)
+		}
+		policy.DenyArgs = append(policy.DenyArgs, re)
+	}
+	return policy, nil
+}
+
+// target is the path Binary is matched against: the PATH-resolved command
+// when the policy requires resolution, otherwise the command as given.
+func (p *CommandPolicy) target(command string) string {
+	if p.Resolve == "path" && !strings.ContainsRune(command, '/') {
+		if resolved, err := exec.LookPath(command); err == nil {
+			return resolved
+		}
+	}
+	return command
+}
+
+func (p *CommandPolicy) matches(command, line string) bool {
+	if p.Binary != "" {
+		name := p.target(command)
+		if !strings.Contains(p.Binary, "/") {
+			name = filepath.Base(name)
+		}
+		if ok, _ := filepath.Match(p.Binary, name); !ok {
+			return false
+		}
+	}
+	return p.Match == nil || p.Match.MatchString(line)
+}
+
+// check returns why an allow policy still rejects the command, or "".
+func (p *CommandPolicy) check(command string, args []string) string {
+	switch p.Resolve {
+	case "absolute":
+		if !filepath.IsAbs(command) {
+			return "command must be given as an absolute path"
+		}
+	case "path":
+		if strings.ContainsRune(command, '/') {
+			return "command must be a bare name resolved through PATH"
+		}
+		if _, err := exec.LookPath(command); err != nil {
+			return "command not found in PATH"
+		}
+	}
+
+	if p.MaxArgs > 0 && len(args) > p.MaxArgs {
+		return fmt.Sprintf("too many arguments (%d, max %d)", len(args), p.MaxArgs)
+	}
+
+	for _, arg := range args {
+		for _, re := range p.DenyArgs {
+			if re.MatchString(arg) {
+				return fmt.Sprintf("argument %q is denied", arg)
+			}
+		}
+		if len(p.AllowArgs) > 0 && !matchesAny(p.AllowArgs, arg) {
+			return fmt.Sprintf("argument %q is not allowed", arg)
+		}
+	}
+	return ""
+}
+
+func matchesAny(patterns []*regexp.Regexp, s string) bool {
+	for _, re := range patterns {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// CommandValidator checks commands against an ordered list of policies. The
+// first matching policy decides; DefaultDeny applies when none matches.
+type CommandValidator struct {
+	Policies    []CommandPolicy
+	DefaultDeny bool
+}
+
+// NewCommandValidator builds a validator from the substring lists of older
+// configs: denied entries reject any command line containing them, and a
+// non-empty allowed list only admits commands starting with one of its
+// entries.
+func NewCommandValidator(allowed, denied []string) *CommandValidator {
+	v := &CommandValidator{DefaultDeny: len(allowed) > 0}
+	for i, d := range denied {
+		v.Policies = append(v.Policies, CommandPolicy{
+			Name:  fmt.Sprintf("denied_commands[%d] %q", i, d),
+			Deny:  true,
+			Match: regexp.MustCompile(regexp.QuoteMeta(d)),
+		})
+	}
+	for i, a := range allowed {
+		v.Policies = append(v.Policies, CommandPolicy{
+			Name:  fmt.Sprintf("allowed_commands[%d] %q", i, a),
+			Match: regexp.MustCompile("^" + regexp.QuoteMeta(a)),
+		})
+	}
+	return v
+}
+
+// NewPolicyValidator compiles policies in order. defaultAction "deny"
+// rejects commands that no policy matches. section prefixes rule names.
+func NewPolicyValidator(section string, policies []config.CommandPolicy, defaultAction string) (*CommandValidator, error) {
+	v := &CommandValidator{DefaultDeny: defaultAction == "deny"}
+	for i, p := range policies {
+		policy, err := CompileCommandPolicy(fmt.Sprintf("%s.policies[%d]", section, i), p)
+		if err != nil {
+			return nil, err
+		}
+		v.Policies = append(v.Policies, policy)
+	}
+	return v, nil
+}
+
+// NewCommandValidatorFromConfig orders denied_commands, then policies, then
+// allowed_commands.
+func NewCommandValidatorFromConfig(cfg *config.CommandConfig) (*CommandValidator, error) {
+	legacy := NewCommandValidator(cfg.AllowedCommands, cfg.DeniedCommands)
+	denied := legacy.Policies[:len(cfg.DeniedCommands)]
+	allowed := legacy.Policies[len(cfg.DeniedCommands):]
+
+	policies, err := NewPolicyValidator("command", cfg.Policies, cfg.DefaultAction)
+	if err != nil {
+		return nil, err
+	}
+
+	v := &CommandValidator{
+		DefaultDeny: policies.DefaultDeny || len(cfg.AllowedCommands) > 0,
+	}
+	v.Policies = append(v.Policies, denied...)
+	v.Policies = append(v.Policies, policies.Policies...)
+	v.Policies = append(v.Policies, allowed...)
+	return v, nil
+}
+
+func (v *CommandValidator) ValidateCommand(command string, args []string) error {
+	if command == "" {
+		return fmt.Errorf("%w: empty command", ErrInvalidInput)
+	}
+
+	line := command
+	if len(args) > 0 {
+		line = command + " " + strings.Join(args, " ")
+	}
+
+	for i := range v.Policies {
+		p := &v.Policies[i]
+		if !p.matches(command, line) {
+			continue
+		}
+		if p.Deny {
+			return &CommandDeniedError{Rule: p.Name, Reason: "command matches a deny rule", Command: line}
+		}
+		if reason := p.check(command, args); reason != "" {
+			return &CommandDeniedError{Rule: p.Name, Reason: reason, Command: line}
+		}
+		return nil
+	}
+
+	if v.DefaultDeny {
+		return &CommandDeniedError{Rule: "default_action", Reason: "no rule allows this command", Command: line}
+	}
+	return nil
+}
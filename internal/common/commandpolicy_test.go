@@ -0,0 +1,67 @@
+package common
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/config"
+)
+
+func TestCommandPolicies(t *testing.T) {
+	cfg := &config.CommandConfig{
+		DeniedCommands: []string{"sudo"},
+		DefaultAction:  "deny",
+		Policies: []config.CommandPolicy{
+			{Name: "no-force-push", Action: "deny", Binary: "git", Match: `\bpush\b.*--force`},
+			{Name: "git", Action: "allow", Binary: "git", AllowArgs: []string{"status", "log", "push", "--force", "-.*"}, DenyArgs: []string{"^--exec"}},
+			{Name: "ls", Action: "allow", Binary: "ls", MaxArgs: 2, Resolve: "path"},
+			{Name: "abs-only", Action: "allow", Binary: "/usr/bin/env", Resolve: "absolute"},
+		},
+	}
+	v, err := NewCommandValidatorFromConfig(cfg)
+	require.NoError(t, err)
+
+	denied := func(t *testing.T, err error, rule string) {
+		t.Helper()
+		var denied *CommandDeniedError
+		require.True(t, errors.As(err, &denied), "expected a denial, got %v", err)
+		assert.Equal(t, rule, denied.Rule)
+		assert.ErrorIs(t, err, ErrCommandDenied)
+	}
+
+	assert.NoError(t, v.ValidateCommand("git", []string{"status"}))
+	assert.NoError(t, v.ValidateCommand("/usr/local/bin/git", []string{"log", "-n"}))
+	assert.NoError(t, v.ValidateCommand("ls", []string{"-la"}))
+	assert.NoError(t, v.ValidateCommand("/usr/bin/env", nil))
+
+	denied(t, v.ValidateCommand("sudo", []string{"ls"}), `denied_commands[0] "sudo"`)
+	denied(t, v.ValidateCommand("git", []string{"push", "--force"}), "no-force-push")
+	denied(t, v.ValidateCommand("git", []string{"commit"}), "git")
+	denied(t, v.ValidateCommand("git", []string{"--exec-path"}), "git")
+	denied(t, v.ValidateCommand("ls", []string{"-l", "-a", "/"}), "ls")
+	denied(t, v.ValidateCommand("./ls", nil), "ls")
+	denied(t, v.ValidateCommand("env", nil), "default_action")
+	denied(t, v.ValidateCommand("cat", []string{"/etc/passwd"}), "default_action")
+}
+
+func TestCommandPolicyReasons(t *testing.T) {
+	v, err := NewCommandValidatorFromConfig(&config.CommandConfig{
+		DefaultAction: "allow",
+		Policies: []config.CommandPolicy{
+			{Name: "git", Action: "allow", Binary: "git", AllowArgs: []string{"status"}},
+		},
+	})
+	require.NoError(t, err)
+
+	err = v.ValidateCommand("git", []string{"statusx"})
+	assert.EqualError(t, err, `command denied: argument "statusx" is not allowed (rule: git)`)
+	assert.NoError(t, v.ValidateCommand("make", nil))
+
+	_, err = NewCommandValidatorFromConfig(&config.CommandConfig{
+		Policies: []config.CommandPolicy{{Action: "allow", Match: "("}},
+	})
+	assert.Error(t, err)
+}
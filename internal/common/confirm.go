@@ -0,0 +1,27 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// Confirm asks the user, through the client, to confirm a destructive
+// action, and returns ErrCancelled unless they do. Clients without
+// elicitation are not asked: the configuration flags that allowed the call
+// have to do.
+func Confirm(ctx context.Context, message string) error {
+	confirmed, err := mcp.Confirm(ctx, message)
+	if errors.Is(err, mcp.ErrClientUnsupported) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("%w: asking for confirmation: %v", ErrOperationFailed, err)
+	}
+	if !confirmed {
+		return fmt.Errorf("%w: %s", ErrCancelled, message)
+	}
+	return nil
+}
@@ -1,32 +1,89 @@
 package common
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"io/fs"
+)
+
+// ErrorCode is the machine-readable kind of a tool error, sent to clients in
+// the error details so they can tell "not allowed" from "not found". Codes
+// are stable: clients branch on them, so never rename one.
+type ErrorCode string
+
+const (
+	CodeNotFound          ErrorCode = "not_found"
+	CodePermissionDenied  ErrorCode = "permission_denied"
+	CodeInvalidPath       ErrorCode = "invalid_path"
+	CodePathNotAllowed    ErrorCode = "path_not_allowed"
+	CodeCommandDenied     ErrorCode = "command_denied"
+	CodeTimeout           ErrorCode = "timeout"
+	CodeFileTooLarge      ErrorCode = "file_too_large"
+	CodeInvalidInput      ErrorCode = "invalid_input"
+	CodeOperationFailed   ErrorCode = "operation_failed"
+	CodeNotImplemented    ErrorCode = "not_implemented"
+	CodeProcessNotFound   ErrorCode = "process_not_found"
+	CodeNotADirectory     ErrorCode = "not_a_directory"
+	CodeNotAFile          ErrorCode = "not_a_file"
+	CodeAlreadyExists     ErrorCode = "already_exists"
+	CodeDirectoryNotEmpty ErrorCode = "directory_not_empty"
+	CodeCancelled         ErrorCode = "cancelled"
+	// CodeInvalidParams is reported by mcp.ParamError and schema
+	// validation, for arguments that do not match the tool's schema.
+	CodeInvalidParams ErrorCode = "invalid_params"
 )
 
 var (
-	ErrNotFound          = errors.New("not found")
-	ErrPermissionDenied  = errors.New("permission denied")
-	ErrInvalidPath       = errors.New("invalid path")
-	ErrPathNotAllowed    = errors.New("path not allowed")
-	ErrCommandDenied     = errors.New("command denied")
-	ErrTimeout           = errors.New("operation timed out")
-	ErrFileTooLarge      = errors.New("file too large")
-	ErrInvalidInput      = errors.New("invalid input")
-	ErrOperationFailed   = errors.New("operation failed")
-	ErrNotImplemented    = errors.New("not implemented")
-	ErrProcessNotFound   = errors.New("process not found")
-	ErrNotADirectory     = errors.New("not a directory")
-	ErrNotAFile          = errors.New("not a file")
-	ErrAlreadyExists     = errors.New("already exists")
-	ErrDirectoryNotEmpty = errors.New("directory not empty")
+	ErrNotFound          = newCodedError(CodeNotFound, "not found", "")
+	ErrPermissionDenied  = newCodedError(CodePermissionDenied, "permission denied", "")
+	ErrInvalidPath       = newCodedError(CodeInvalidPath, "invalid path", "")
+	ErrPathNotAllowed    = newCodedError(CodePathNotAllowed, "path not allowed", "Use a path inside the allowed paths shown by get_server_config")
+	ErrCommandDenied     = newCodedError(CodeCommandDenied, "command denied", "Check the command policies shown by get_server_config")
+	ErrTimeout           = newCodedError(CodeTimeout, "operation timed out", "Retry with a larger timeout_seconds")
+	ErrFileTooLarge      = newCodedError(CodeFileTooLarge, "file too large", "Read the file in parts with read_file offset and length, or read_file_lines")
+	ErrInvalidInput      = newCodedError(CodeInvalidInput, "invalid input", "")
+	ErrOperationFailed   = newCodedError(CodeOperationFailed, "operation failed", "")
+	ErrNotImplemented    = newCodedError(CodeNotImplemented, "not implemented", "")
+	ErrProcessNotFound   = newCodedError(CodeProcessNotFound, "process not found", "")
+	ErrNotADirectory     = newCodedError(CodeNotADirectory, "not a directory", "")
+	ErrNotAFile          = newCodedError(CodeNotAFile, "not a file", "")
+	ErrAlreadyExists     = newCodedError(CodeAlreadyExists, "already exists", "")
+	ErrDirectoryNotEmpty = newCodedError(CodeDirectoryNotEmpty, "directory not empty", "")
+	ErrCancelled         = newCodedError(CodeCancelled, "cancelled by the user", "The user declined; do not retry without asking them")
 )
 
+// codedError is a sentinel that also reports its code, so any error wrapping
+// one with %w carries details through to the client.
+type codedError struct {
+	code        ErrorCode
+	msg         string
+	remediation string
+}
+
+func newCodedError(code ErrorCode, msg, remediation string) *codedError {
+	return &codedError{code: code, msg: msg, remediation: remediation}
+}
+
+func (e *codedError) Error() string {
+	return e.msg
+}
+
+func (e *codedError) ErrorDetails() map[string]interface{} {
+	details := map[string]interface{}{"code": e.code}
+	if e.remediation != "" {
+		details["remediation"] = e.remediation
+	}
+	return details
+}
+
 type MCPError struct {
-	Code    string
-	Message string
-	Cause   error
+	Code        ErrorCode
+	Message     string
+	Cause       error
+	Path        string
+	Command     string
+	Remediation string
 }
 
 func (e *MCPError) Error() string {
@@ -40,7 +97,27 @@ func (e *MCPError) Unwrap() error {
 	return e.Cause
 }
 
-func NewMCPError(code, message string, cause error) *MCPError {
+func (e *MCPError) ErrorDetails() map[string]interface{} {
+	details := ErrorDetails(e.Cause)
+	if details == nil {
+		details = make(map[string]interface{})
+	}
+	if e.Code != "" {
+		details["code"] = e.Code
+	}
+	if e.Path != "" {
+		details["path"] = e.Path
+	}
+	if e.Command != "" {
+		details["command"] = e.Command
+	}
+	if e.Remediation != "" {
+		details["remediation"] = e.Remediation
+	}
+	return details
+}
+
+func NewMCPError(code ErrorCode, message string, cause error) *MCPError {
 	return &MCPError{
 		Code:    code,
 		Message: message,
@@ -48,6 +125,81 @@ func NewMCPError(code, message string, cause error) *MCPError {
 	}
 }
 
+// detailedError attaches fields to an error without changing its message.
+type detailedError struct {
+	err    error
+	fields map[string]interface{}
+}
+
+func (e *detailedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *detailedError) Unwrap() error {
+	return e.err
+}
+
+func (e *detailedError) ErrorDetails() map[string]interface{} {
+	details := ErrorDetails(e.err)
+	if details == nil {
+		details = make(map[string]interface{})
+	}
+	for k, v := range e.fields {
+		details[k] = v
+	}
+	return details
+}
+
+// WithDetails adds machine-readable fields, such as the offending path, to
+// err's details. keyvals alternates keys and values.
+func WithDetails(err error, keyvals ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+	fields := make(map[string]interface{}, len(keyvals)/2)
+	for i := 0; i+1 < len(keyvals); i += 2 {
+		fields[fmt.Sprint(keyvals[i])] = keyvals[i+1]
+	}
+	return &detailedError{err: err, fields: fields}
+}
+
+// ErrorDetails returns the details of the outermost error in err's chain
+// that has any, or nil.
+func ErrorDetails(err error) map[string]interface{} {
+	var detailed interface {
+		ErrorDetails() map[string]interface{}
+	}
+	if errors.As(err, &detailed) {
+		return detailed.ErrorDetails()
+	}
+	return nil
+}
+
+// Classify gives an error that carries no code the code of the standard
+// error it wraps: os errors such as fs.ErrNotExist, context errors, and
+// otherwise operation_failed. Errors with details are returned unchanged.
+func Classify(err error) error {
+	if err == nil || ErrorDetails(err) != nil {
+		return err
+	}
+	var code *codedError
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		code = ErrNotFound
+	case errors.Is(err, fs.ErrPermission):
+		code = ErrPermissionDenied
+	case errors.Is(err, fs.ErrExist):
+		code = ErrAlreadyExists
+	case errors.Is(err, context.DeadlineExceeded):
+		code = ErrTimeout
+	case errors.Is(err, context.Canceled):
+		return WithDetails(err, "code", CodeCancelled)
+	default:
+		code = ErrOperationFailed
+	}
+	return &detailedError{err: err, fields: code.ErrorDetails()}
+}
+
 func WrapError(err error, message string) error {
 	if err == nil {
 		return nil
@@ -0,0 +1,70 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func TestErrorDetails(t *testing.T) {
+	t.Run("wrapped sentinel reports its code", func(t *testing.T) {
+		err := fmt.Errorf("%w: 80MB exceeds 50MB", ErrFileTooLarge)
+		details := ErrorDetails(err)
+		assert.Equal(t, CodeFileTooLarge, details["code"])
+		assert.NotEmpty(t, details["remediation"])
+	})
+
+	t.Run("fields are added without changing the message", func(t *testing.T) {
+		err := WithDetails(fmt.Errorf("%w: not a directory", ErrInvalidPath), "path", "/tmp/x")
+		assert.Equal(t, "invalid path: not a directory", err.Error())
+		assert.Equal(t, map[string]interface{}{"code": CodeInvalidPath, "path": "/tmp/x"}, ErrorDetails(err))
+		assert.ErrorIs(t, err, ErrInvalidPath)
+	})
+
+	t.Run("MCPError overrides the cause", func(t *testing.T) {
+		err := &MCPError{Code: CodeCommandDenied, Message: "blocked", Cause: ErrPermissionDenied, Command: "sudo ls"}
+		details := ErrorDetails(err)
+		assert.Equal(t, CodeCommandDenied, details["code"])
+		assert.Equal(t, "sudo ls", details["command"])
+	})
+
+	t.Run("path validator names the path", func(t *testing.T) {
+		v := NewPathValidator([]string{"/srv/allowed"}, nil, false)
+		details := ErrorDetails(v.ValidatePath("/srv/other/file"))
+		assert.Equal(t, CodePathNotAllowed, details["code"])
+		assert.Equal(t, "/srv/other/file", details["path"])
+	})
+
+	t.Run("plain errors have no details", func(t *testing.T) {
+		assert.Nil(t, ErrorDetails(fmt.Errorf("boom")))
+	})
+}
+
+func TestClassify(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		code ErrorCode
+	}{
+		{&fs.PathError{Op: "open", Path: "/x", Err: fs.ErrNotExist}, CodeNotFound},
+		{fmt.Errorf("reading: %w", fs.ErrPermission), CodePermissionDenied},
+		{fmt.Errorf("waiting: %w", context.DeadlineExceeded), CodeTimeout},
+		{context.Canceled, CodeCancelled},
+		{fmt.Errorf("boom"), CodeOperationFailed},
+		{fmt.Errorf("%w: bad pattern", ErrInvalidInput), CodeInvalidInput},
+	} {
+		err := Classify(tc.err)
+		assert.Equal(t, tc.code, ErrorDetails(err)["code"], tc.err.Error())
+		assert.Equal(t, tc.err.Error(), err.Error())
+		assert.ErrorIs(t, err, tc.err)
+	}
+	assert.Nil(t, Classify(nil))
+
+	// Argument errors from the mcp helpers keep their own code.
+	_, err := mcp.GetStringParam(map[string]interface{}{}, "path", true)
+	assert.Equal(t, "invalid_params", ErrorDetails(Classify(err))["code"])
+}
@@ -1,10 +1,13 @@
 package common
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 )
@@ -33,13 +36,26 @@ func (l LogLevel) String() string {
 	}
 }
 
+func (l LogLevel) slogLevel() slog.Level {
+	switch l {
+	case LogLevelDebug:
+		return slog.LevelDebug
+	case LogLevelWarn:
+		return slog.LevelWarn
+	case LogLevelError:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
 func ParseLogLevel(s string) LogLevel {
 	switch s {
 	case "debug":
 		return LogLevelDebug
 	case "info":
 		return LogLevelInfo
-	case "warn":
+	case "warn", "warning":
 		return LogLevelWarn
 	case "error":
 		return LogLevelError
@@ -55,48 +71,108 @@ const (
 	LogFormatText
 )
 
+func ParseLogFormat(s string) LogFormat {
+	if s == "text" {
+		return LogFormatText
+	}
+	return LogFormatJSON
+}
+
+// NewHandler returns the slog handler used for server logs: JSON or text
+// entries with timestamp, level and message keys.
+func NewHandler(format LogFormat, output io.Writer, level slog.Leveler) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: replaceAttr}
+	if format == LogFormatText {
+		return slog.NewTextHandler(output, opts)
+	}
+	return slog.NewJSONHandler(output, opts)
+}
+
+func replaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.TimeKey:
+		return slog.String("timestamp", a.Value.Time().UTC().Format(time.RFC3339))
+	case slog.LevelKey:
+		if level, ok := a.Value.Any().(slog.Level); ok {
+			return slog.String(slog.LevelKey, strings.ToLower(level.String()))
+		}
+	case slog.MessageKey:
+		a.Key = "message"
+	}
+	return a
+}
+
+var (
+	logLevel = new(slog.LevelVar)
+
+	handlerMu  sync.RWMutex
+	logHandler slog.Handler = NewHandler(LogFormatJSON, os.Stderr, logLevel)
+)
+
+// ConfigureLogging sets the level, format and output of every logger created
+// with NewServerLogger.
+func ConfigureLogging(level LogLevel, format LogFormat, output io.Writer) {
+	logLevel.Set(level.slogLevel())
+	SetLogHandler(NewHandler(format, output, logLevel))
+}
+
+// SetLogLevel changes the level of the built-in handler without replacing it,
+// so it can follow config reloads.
+func SetLogLevel(level LogLevel) {
+	logLevel.Set(level.slogLevel())
+}
+
+// SetLogHandler routes server logs to h. Loggers pick up the new handler on
+// their next entry, so it can be swapped at any time.
+func SetLogHandler(h slog.Handler) {
+	handlerMu.Lock()
+	defer handlerMu.Unlock()
+	logHandler = h
+}
+
+func LogHandler() slog.Handler {
+	handlerMu.RLock()
+	defer handlerMu.RUnlock()
+	return logHandler
+}
+
 type Logger struct {
-	mu       sync.Mutex
-	level    LogLevel
-	format   LogFormat
-	output   io.Writer
+	// handler is nil for server loggers, which use the process-wide handler.
+	handler  slog.Handler
 	fields   map[string]interface{}
 	serverID string
 }
 
+// NewServerLogger returns a logger that writes through the handler set by
+// ConfigureLogging or SetLogHandler.
+func NewServerLogger(serverID string) *Logger {
+	return &Logger{
+		fields:   make(map[string]interface{}),
+		serverID: serverID,
+	}
+}
+
 func NewLogger(level LogLevel, format LogFormat, output io.Writer, serverID string) *Logger {
 	if output == nil {
 		output = os.Stderr
 	}
 	return &Logger{
-		level:    level,
-		format:   format,
-		output:   output,
+		handler:  NewHandler(format, output, level.slogLevel()),
 		fields:   make(map[string]interface{}),
 		serverID: serverID,
 	}
 }
 
 func (l *Logger) WithField(key string, value interface{}) *Logger {
-	newLogger := &Logger{
-		level:    l.level,
-		format:   l.format,
-		output:   l.output,
-		fields:   make(map[string]interface{}),
-		serverID: l.serverID,
-	}
-	for k, v := range l.fields {
-		newLogger.fields[k] = v
-	}
-	newLogger.fields[key] = value
-	return newLogger
+	return l.WithFields(map[string]interface{}{key: value})
 }
 
 func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
 	newLogger := &Logger{
-		level:    l.level,
-		format:   l.format,
-		output:   l.output,
+		handler:  l.handler,
 		fields:   make(map[string]interface{}),
 		serverID: l.serverID,
 	}
@@ -110,34 +186,43 @@ func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
 }
 
 func (l *Logger) log(level LogLevel, msg string) {
-	if level < l.level {
+	h := l.handler
+	if h == nil {
+		h = LogHandler()
+	}
+
+	ctx := context.Background()
+	if !h.Enabled(ctx, level.slogLevel()) {
 		return
 	}
 
-	l.mu.Lock()
-	defer l.mu.Unlock()
+	r := slog.NewRecord(time.Now(), level.slogLevel(), msg, 0)
+	r.AddAttrs(slog.String("server", l.serverID))
 
-	entry := map[string]interface{}{
-		"timestamp": time.Now().UTC().Format(time.RFC3339),
-		"level":     level.String(),
-		"message":   msg,
-		"server":    l.serverID,
+	keys := make([]string, 0, len(l.fields))
+	for k := range l.fields {
+		keys = append(keys, k)
 	}
-
-	for k, v := range l.fields {
-		entry[k] = v
+	sort.Strings(keys)
+	for _, k := range keys {
+		r.AddAttrs(slog.Any(k, redactField(k, l.fields[k])))
 	}
 
-	if l.format == LogFormatJSON {
-		data, _ := json.Marshal(entry)
-		fmt.Fprintln(l.output, string(data))
-	} else {
-		fmt.Fprintf(l.output, "[%s] %s: %s", entry["timestamp"], level.String(), msg)
-		for k, v := range l.fields {
-			fmt.Fprintf(l.output, " %s=%v", k, v)
+	h.Handle(ctx, r)
+}
+
+var sensitiveFieldMarkers = []string{"password", "passwd", "token", "api_key", "apikey", "authorization", "cookie", "private_key", "credential"}
+
+// redactField masks values logged under keys that look like credentials so
+// they never reach log files.
+func redactField(key string, value interface{}) interface{} {
+	lower := strings.ToLower(key)
+	for _, marker := range sensitiveFieldMarkers {
+		if strings.Contains(lower, marker) {
+			return "REDACTED"
 		}
-		fmt.Fprintln(l.output)
 	}
+	return value
 }
 
 func (l *Logger) Debug(msg string) {
@@ -172,17 +257,17 @@ func (l *Logger) Errorf(format string, args ...interface{}) {
 	l.log(LogLevelError, fmt.Sprintf(format, args...))
 }
 
-var defaultLogger = NewLogger(LogLevelInfo, LogFormatText, os.Stderr, "default")
+var defaultLogger = NewServerLogger("default")
 
 func SetDefaultLogger(logger *Logger) {
 	defaultLogger = logger
 }
 
-func Debug(msg string)                            { defaultLogger.Debug(msg) }
-func Info(msg string)                             { defaultLogger.Info(msg) }
-func Warn(msg string)                             { defaultLogger.Warn(msg) }
-func Error(msg string)                            { defaultLogger.Error(msg) }
-func Debugf(format string, args ...interface{})   { defaultLogger.Debugf(format, args...) }
-func Infof(format string, args ...interface{})    { defaultLogger.Infof(format, args...) }
-func Warnf(format string, args ...interface{})    { defaultLogger.Warnf(format, args...) }
-func Errorf(format string, args ...interface{})   { defaultLogger.Errorf(format, args...) }
+func Debug(msg string)                          { defaultLogger.Debug(msg) }
+func Info(msg string)                           { defaultLogger.Info(msg) }
+func Warn(msg string)                           { defaultLogger.Warn(msg) }
+func Error(msg string)                          { defaultLogger.Error(msg) }
+func Debugf(format string, args ...interface{}) { defaultLogger.Debugf(format, args...) }
+func Infof(format string, args ...interface{})  { defaultLogger.Infof(format, args...) }
+func Warnf(format string, args ...interface{})  { defaultLogger.Warnf(format, args...) }
+func Errorf(format string, args ...interface{}) { defaultLogger.Errorf(format, args...) }
@@ -0,0 +1,44 @@
+package common
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggerRedactsSensitiveFields(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewLogger(LogLevelInfo, LogFormatJSON, &buf, "test")
+
+	logger.WithFields(map[string]interface{}{
+		"vault_token": "s.abc123",
+		"path":        "/tmp",
+	}).Info("reading")
+
+	assert.NotContains(t, buf.String(), "s.abc123")
+	assert.Contains(t, buf.String(), `"vault_token":"REDACTED"`)
+	assert.Contains(t, buf.String(), `"path":"/tmp"`)
+}
+
+func TestServerLoggerUsesConfiguredHandler(t *testing.T) {
+	previous := LogHandler()
+	defer SetLogHandler(previous)
+
+	var buf bytes.Buffer
+	ConfigureLogging(LogLevelWarn, LogFormatText, &buf)
+	defer SetLogLevel(LogLevelInfo)
+
+	logger := NewServerLogger("test").WithField("path", "/tmp")
+	logger.Info("skipped")
+	logger.Warn("kept")
+
+	assert.NotContains(t, buf.String(), "skipped")
+	assert.Contains(t, buf.String(), "level=warn")
+	assert.Contains(t, buf.String(), "message=kept server=test path=/tmp")
+
+	buf.Reset()
+	SetLogLevel(LogLevelDebug)
+	logger.Debug("now visible")
+	assert.Contains(t, buf.String(), "now visible")
+}
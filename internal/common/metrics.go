@@ -0,0 +1,209 @@
+package common
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// latencySamples is how many recent durations each tool keeps for
+// percentiles.
+const latencySamples = 1024
+
+type toolMetrics struct {
+	server  string
+	calls   int64
+	errors  int64
+	total   time.Duration
+	max     time.Duration
+	samples []time.Duration
+	next    int
+}
+
+type ToolStats struct {
+	Server    string  `json:"server"`
+	Tool      string  `json:"tool"`
+	Calls     int64   `json:"calls"`
+	Errors    int64   `json:"errors"`
+	ErrorRate float64 `json:"error_rate"`
+	AvgMs     float64 `json:"avg_ms"`
+	P95Ms     float64 `json:"p95_ms"`
+	MaxMs     float64 `json:"max_ms"`
+}
+
+// Metrics counts calls, errors and latencies per tool.
+type Metrics struct {
+	mu      sync.Mutex
+	started time.Time
+	tools   map[string]*toolMetrics
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		started: time.Now(),
+		tools:   make(map[string]*toolMetrics),
+	}
+}
+
+var defaultMetrics = NewMetrics()
+
+func DefaultMetrics() *Metrics {
+	return defaultMetrics
+}
+
+func (m *Metrics) Observe(server, tool string, d time.Duration, failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	t, ok := m.tools[tool]
+	if !ok {
+		t = &toolMetrics{server: server}
+		m.tools[tool] = t
+	}
+
+	t.calls++
+	if failed {
+		t.errors++
+	}
+	t.total += d
+	if d > t.max {
+		t.max = d
+	}
+	if len(t.samples) < latencySamples {
+		t.samples = append(t.samples, d)
+	} else {
+		t.samples[t.next] = d
+		t.next = (t.next + 1) % latencySamples
+	}
+}
+
+// Snapshot returns the stats of every tool called so far, sorted by server
+// and tool name.
+func (m *Metrics) Snapshot() []ToolStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stats := make([]ToolStats, 0, len(m.tools))
+	for name, t := range m.tools {
+		stats = append(stats, ToolStats{
+			Server:    t.server,
+			Tool:      name,
+			Calls:     t.calls,
+			Errors:    t.errors,
+			ErrorRate: float64(t.errors) / float64(t.calls),
+			AvgMs:     millis(t.total / time.Duration(t.calls)),
+			P95Ms:     millis(percentile(t.samples, 0.95)),
+			MaxMs:     millis(t.max),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Server != stats[j].Server {
+			return stats[i].Server < stats[j].Server
+		}
+		return stats[i].Tool < stats[j].Tool
+	})
+	return stats
+}
+
+func (m *Metrics) Uptime() time.Duration {
+	return time.Since(m.started)
+}
+
+func percentile(samples []time.Duration, p float64) time.Duration {
+	if len(samples) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration(nil), samples...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	idx := int(float64(len(sorted))*p+0.5) - 1
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= len(sorted) {
+		idx = len(sorted) - 1
+	}
+	return sorted[idx]
+}
+
+func millis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
+// Instrument wraps the handlers of a sub-server's tools so every call is
+// reported to DefaultMetrics and, unless SetAuditAllCalls(false), to the
+// audit trail, and every error has a code (see Classify).
+func Instrument(server string, tools []*mcp.Tool) []*mcp.Tool {
+	instrumented := make([]*mcp.Tool, len(tools))
+	for i, tool := range tools {
+		handler := tool.Handler
+		name := tool.Name
+		wrapped := *tool
+		wrapped.Handler = func(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
+			recorded := false
+			ctx = context.WithValue(ctx, auditedKey{}, &recorded)
+			start := time.Now()
+			result, err := handler(ctx, params)
+			err = Classify(err)
+			duration := time.Since(start)
+			failed := err != nil || (result != nil && result.IsError)
+			defaultMetrics.Observe(server, name, duration, failed)
+			if !auditMutatingOnly.Load() && !recorded {
+				auditCall(server, name, params, result, err, duration)
+			}
+			return result, err
+		}
+		instrumented[i] = &wrapped
+	}
+	return instrumented
+}
+
+// ServerStatsTool returns the get_server_stats tool, shared by every
+// sub-server.
+func ServerStatsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "get_server_stats",
+		Description: "Show per-tool call counts, error rates and latencies (average, p95, max) since the server started",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"server": mcp.StringProperty("Only show tools of this server (e.g. filesystem)"),
+				"tool":   mcp.StringProperty("Only show this tool"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(handleGetServerStats),
+	}
+}
+
+type serverStatsArgs struct {
+	Server string `json:"server"`
+	Tool   string `json:"tool"`
+}
+
+func handleGetServerStats(ctx context.Context, args serverStatsArgs) (*mcp.ToolResult, error) {
+	server, tool := args.Server, args.Tool
+
+	var totalCalls, totalErrors int64
+	stats := make([]ToolStats, 0)
+	for _, s := range defaultMetrics.Snapshot() {
+		if server != "" && s.Server != server {
+			continue
+		}
+		if tool != "" && s.Tool != tool {
+			continue
+		}
+		stats = append(stats, s)
+		totalCalls += s.Calls
+		totalErrors += s.Errors
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"uptime_seconds": int64(defaultMetrics.Uptime().Seconds()),
+		"tools":          stats,
+		"total_calls":    totalCalls,
+		"total_errors":   totalErrors,
+	})
+}
@@ -0,0 +1,53 @@
+package common
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func TestMetricsSnapshot(t *testing.T) {
+	m := NewMetrics()
+	for i := 1; i <= 100; i++ {
+		m.Observe("filesystem", "read_file", time.Duration(i)*time.Millisecond, i%10 == 0)
+	}
+
+	stats := m.Snapshot()
+	require.Len(t, stats, 1)
+	assert.Equal(t, int64(100), stats[0].Calls)
+	assert.Equal(t, int64(10), stats[0].Errors)
+	assert.InDelta(t, 0.1, stats[0].ErrorRate, 0.0001)
+	assert.Equal(t, 95.0, stats[0].P95Ms)
+	assert.Equal(t, 100.0, stats[0].MaxMs)
+}
+
+func TestInstrumentCountsErrors(t *testing.T) {
+	tools := Instrument("test", []*mcp.Tool{{
+		Name: "instrumented_tool",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
+			if params["fail"] == true {
+				return nil, errors.New("boom")
+			}
+			return mcp.TextResult("ok"), nil
+		},
+	}})
+
+	tools[0].Handler(context.Background(), map[string]interface{}{})
+	tools[0].Handler(context.Background(), map[string]interface{}{"fail": true})
+
+	for _, s := range DefaultMetrics().Snapshot() {
+		if s.Tool == "instrumented_tool" {
+			assert.Equal(t, "test", s.Server)
+			assert.Equal(t, int64(2), s.Calls)
+			assert.Equal(t, int64(1), s.Errors)
+			return
+		}
+	}
+	t.Fatal("instrumented_tool not recorded")
+}
@@ -0,0 +1,173 @@
+package common
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const backupTimeFormat = "20060102-150405.000"
+
+type RotateOptions struct {
+	MaxSizeBytes   int64
+	RotateInterval time.Duration
+	MaxBackups     int
+	MaxAge         time.Duration
+}
+
+// RotatingFile is an io.Writer that appends to path and moves it aside to
+// path.<timestamp> when it grows past MaxSizeBytes or is older than
+// RotateInterval. Backups beyond MaxBackups or older than MaxAge are removed.
+// Zero values disable the corresponding limit.
+type RotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	opts     RotateOptions
+	file     *os.File
+	size     int64
+	openedAt time.Time
+	now      func() time.Time
+}
+
+func NewRotatingFile(path string, opts RotateOptions) (*RotatingFile, error) {
+	r := &RotatingFile{
+		path: path,
+		opts: opts,
+		now:  time.Now,
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	if r.shouldRotate(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+func (r *RotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
+
+func (r *RotatingFile) shouldRotate(incoming int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.opts.MaxSizeBytes > 0 && r.size+incoming > r.opts.MaxSizeBytes {
+		return true
+	}
+	if r.opts.RotateInterval > 0 && r.now().Sub(r.openedAt) >= r.opts.RotateInterval {
+		return true
+	}
+	return false
+}
+
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	r.file = f
+	r.size = info.Size()
+	r.openedAt = info.ModTime()
+	if r.size == 0 {
+		r.openedAt = r.now()
+	}
+	return nil
+}
+
+func (r *RotatingFile) rotate() error {
+	if err := r.file.Close(); err != nil {
+		return err
+	}
+	r.file = nil
+
+	backup := fmt.Sprintf("%s.%s", r.path, r.now().Format(backupTimeFormat))
+	if err := os.Rename(r.path, backup); err != nil {
+		return err
+	}
+
+	if err := r.open(); err != nil {
+		return err
+	}
+	r.openedAt = r.now()
+
+	r.prune()
+	return nil
+}
+
+func (r *RotatingFile) prune() {
+	backups := rotatedFiles(r.path)
+	now := r.now()
+	for i := range backups {
+		b := backups[len(backups)-1-i]
+		tooMany := r.opts.MaxBackups > 0 && i >= r.opts.MaxBackups
+		tooOld := r.opts.MaxAge > 0 && now.Sub(b.at) > r.opts.MaxAge
+		if tooMany || tooOld {
+			os.Remove(b.path)
+		}
+	}
+}
+
+type rotatedFile struct {
+	path string
+	at   time.Time
+}
+
+// rotatedFiles lists the backups of path, oldest first.
+func rotatedFiles(path string) []rotatedFile {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil
+	}
+
+	var backups []rotatedFile
+	prefix := path + "."
+	for _, m := range matches {
+		at, err := time.ParseInLocation(backupTimeFormat, strings.TrimPrefix(m, prefix), time.Local)
+		if err != nil {
+			continue
+		}
+		backups = append(backups, rotatedFile{path: m, at: at})
+	}
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].at.Before(backups[j].at)
+	})
+	return backups
+}
@@ -0,0 +1,58 @@
+package common
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRotatingFile(t *testing.T) {
+	t.Run("rotates by size and keeps max backups", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "server.log")
+		r, err := NewRotatingFile(path, RotateOptions{MaxSizeBytes: 10, MaxBackups: 2})
+		require.NoError(t, err)
+		defer r.Close()
+
+		clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+		r.now = func() time.Time {
+			clock = clock.Add(time.Second)
+			return clock
+		}
+
+		for i := 0; i < 5; i++ {
+			_, err := r.Write([]byte("0123456789"))
+			require.NoError(t, err)
+		}
+
+		backups, _ := filepath.Glob(path + ".*")
+		assert.Len(t, backups, 2)
+
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, "0123456789", string(data))
+	})
+
+	t.Run("rotates by interval and prunes old backups", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "server.log")
+		r, err := NewRotatingFile(path, RotateOptions{RotateInterval: time.Hour, MaxAge: 30 * time.Minute})
+		require.NoError(t, err)
+		defer r.Close()
+
+		clock := time.Date(2026, 1, 1, 0, 0, 0, 0, time.Local)
+		r.now = func() time.Time { return clock }
+		r.openedAt = clock
+
+		for i := 0; i < 3; i++ {
+			_, err := r.Write([]byte("line\n"))
+			require.NoError(t, err)
+			clock = clock.Add(time.Hour)
+		}
+
+		backups, _ := filepath.Glob(path + ".*")
+		assert.Len(t, backups, 1, "the backup from an hour ago is past MaxAge")
+	})
+}
@@ -0,0 +1,103 @@
+package common
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+var (
+	configSectionsMu sync.RWMutex
+	configSections   = make(map[string]func() interface{})
+)
+
+// RegisterConfigSection makes a sub-server's effective configuration visible
+// to get_server_config. Fields tagged sensitive are redacted on output.
+func RegisterConfigSection(name string, fn func() interface{}) {
+	configSectionsMu.Lock()
+	defer configSectionsMu.Unlock()
+	configSections[name] = fn
+}
+
+// ServerConfigTool returns the get_server_config tool. Every sub-server
+// includes it; registering it more than once is harmless since all copies
+// read the same sections.
+func ServerConfigTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "get_server_config",
+		Description: "Show the effective (redacted) configuration of the running servers: allowed paths, limits and enabled features",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"server": mcp.StringProperty("Only show this server's section (e.g. filesystem)"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(handleGetServerConfig),
+	}
+}
+
+type serverConfigArgs struct {
+	Server string `json:"server"`
+}
+
+func handleGetServerConfig(ctx context.Context, args serverConfigArgs) (*mcp.ToolResult, error) {
+	server := args.Server
+
+	configSectionsMu.RLock()
+	defer configSectionsMu.RUnlock()
+
+	names := make([]string, 0, len(configSections))
+	for name := range configSections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if server != "" {
+		fn, ok := configSections[server]
+		if !ok {
+			return nil, fmt.Errorf("%w: server %s (available: %v)", ErrNotFound, server, names)
+		}
+		section, err := yamlView(config.Redact(fn()))
+		if err != nil {
+			return nil, err
+		}
+		return mcp.JSONResult(map[string]interface{}{
+			"server": server,
+			"config": section,
+		})
+	}
+
+	sections := make(map[string]interface{}, len(names))
+	for _, name := range names {
+		section, err := yamlView(config.Redact(configSections[name]()))
+		if err != nil {
+			return nil, err
+		}
+		sections[name] = section
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"servers": sections,
+	})
+}
+
+// yamlView re-keys a config struct by its yaml tags so the output uses the
+// same names as the config file.
+func yamlView(v interface{}) (interface{}, error) {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	if err := yaml.Unmarshal(data, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
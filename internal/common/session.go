@@ -0,0 +1,142 @@
+package common
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type SessionEnv struct {
+	mu   sync.RWMutex
+	vars map[string]string
+}
+
+func NewSessionEnv() *SessionEnv {
+	return &SessionEnv{
+		vars: make(map[string]string),
+	}
+}
+
+var defaultSessionEnv = NewSessionEnv()
+
+// DefaultSessionEnv returns the process-wide session environment shared by
+// every sub-server running in this process.
+func DefaultSessionEnv() *SessionEnv {
+	return defaultSessionEnv
+}
+
+type sessionEnvKey struct{}
+
+// SessionEnvFor returns the session environment of the client whose tool
+// call runs under ctx. Each client of an HTTP transport has its own, shared
+// by every sub-server; over stdio it is fallback.
+func SessionEnvFor(ctx context.Context, fallback *SessionEnv) *SessionEnv {
+	sess := mcp.SessionFromContext(ctx)
+	if sess == nil {
+		return fallback
+	}
+	return sess.Value(sessionEnvKey{}, func() interface{} { return NewSessionEnv() }).(*SessionEnv)
+}
+
+func (e *SessionEnv) Get(name string) (string, bool) {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	value, ok := e.vars[name]
+	return value, ok
+}
+
+func (e *SessionEnv) Set(name, value string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.vars[name] = value
+}
+
+func (e *SessionEnv) Unset(name string) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if _, ok := e.vars[name]; !ok {
+		return false
+	}
+	delete(e.vars, name)
+	return true
+}
+
+func (e *SessionEnv) Len() int {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return len(e.vars)
+}
+
+func (e *SessionEnv) Names() []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	names := make([]string, 0, len(e.vars))
+	for name := range e.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// All returns a copy of the session variables.
+func (e *SessionEnv) All() map[string]string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	out := make(map[string]string, len(e.vars))
+	for k, v := range e.vars {
+		out[k] = v
+	}
+	return out
+}
+
+// Replace swaps the whole session for vars and returns the previous contents.
+func (e *SessionEnv) Replace(vars map[string]string) map[string]string {
+	next := make(map[string]string, len(vars))
+	for k, v := range vars {
+		next[k] = v
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	prev := e.vars
+	e.vars = next
+	return prev
+}
+
+func (e *SessionEnv) Clear() int {
+	return len(e.Replace(nil))
+}
+
+// Merge returns the session variables with overrides applied on top, or nil
+// when both are empty so callers keep the inherited process environment.
+func (e *SessionEnv) Merge(overrides map[string]string) map[string]string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	if len(e.vars) == 0 && len(overrides) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(e.vars)+len(overrides))
+	for k, v := range e.vars {
+		out[k] = v
+	}
+	for k, v := range overrides {
+		out[k] = v
+	}
+	return out
+}
+
+// Environ returns base with the session variables appended in KEY=VALUE
+// form. exec.Cmd keeps the last value for duplicate keys, so session values
+// win over inherited ones.
+func (e *SessionEnv) Environ(base []string) []string {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	out := make([]string, 0, len(base)+len(e.vars))
+	out = append(out, base...)
+	for k, v := range e.vars {
+		out = append(out, k+"="+v)
+	}
+	return out
+}
@@ -0,0 +1,80 @@
+package common
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSessionEnv(t *testing.T) {
+	t.Run("set get unset", func(t *testing.T) {
+		e := NewSessionEnv()
+		e.Set("FOO", "bar")
+
+		value, ok := e.Get("FOO")
+		assert.True(t, ok)
+		assert.Equal(t, "bar", value)
+
+		assert.True(t, e.Unset("FOO"))
+		assert.False(t, e.Unset("FOO"))
+		assert.Equal(t, 0, e.Len())
+	})
+
+	t.Run("all returns a copy", func(t *testing.T) {
+		e := NewSessionEnv()
+		e.Set("FOO", "bar")
+
+		all := e.All()
+		all["FOO"] = "changed"
+
+		value, _ := e.Get("FOO")
+		assert.Equal(t, "bar", value)
+	})
+
+	t.Run("replace returns previous contents", func(t *testing.T) {
+		e := NewSessionEnv()
+		e.Set("OLD", "1")
+
+		prev := e.Replace(map[string]string{"NEW": "2"})
+		assert.Equal(t, map[string]string{"OLD": "1"}, prev)
+		assert.Equal(t, []string{"NEW"}, e.Names())
+		assert.Equal(t, 1, e.Clear())
+	})
+
+	t.Run("merge applies overrides on top", func(t *testing.T) {
+		e := NewSessionEnv()
+		assert.Nil(t, e.Merge(nil))
+
+		e.Set("A", "session")
+		e.Set("B", "session")
+		merged := e.Merge(map[string]string{"B": "override"})
+		assert.Equal(t, map[string]string{"A": "session", "B": "override"}, merged)
+	})
+
+	t.Run("environ appends session after base", func(t *testing.T) {
+		e := NewSessionEnv()
+		e.Set("A", "session")
+
+		env := e.Environ([]string{"A=base"})
+		assert.Equal(t, []string{"A=base", "A=session"}, env)
+	})
+
+	t.Run("concurrent access", func(t *testing.T) {
+		e := NewSessionEnv()
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				name := fmt.Sprintf("VAR_%d", i)
+				e.Set(name, "x")
+				e.Get(name)
+				e.All()
+			}(i)
+		}
+		wg.Wait()
+		assert.Equal(t, 50, e.Len())
+	})
+}
@@ -32,6 +32,12 @@ func NewPathValidator(allowed, denied []string, followSymlinks bool) *PathValida
 	}
 }
 
+// ValidatePath checks path against the denied and allowed lists. An entry
+// is a directory, covering everything under it, or a glob pattern, which
+// covers what it matches and everything under that: * and ? match within a
+// path component and ** matches any number of them. A relative pattern,
+// such as *.pem or **/.env, matches at any depth. Tools that write check the
+// path they write to, so the rules restrict writes as well as reads.
 func (v *PathValidator) ValidatePath(path string) error {
 	if path == "" {
 		return fmt.Errorf("%w: empty path", ErrInvalidPath)
@@ -47,13 +53,17 @@ func (v *PathValidator) ValidatePath(path string) error {
 	if !v.FollowSymlinks {
 		info, err := os.Lstat(cleanPath)
 		if err == nil && info.Mode()&os.ModeSymlink != 0 {
-			return fmt.Errorf("%w: symlinks not allowed", ErrPathNotAllowed)
+			return WithDetails(fmt.Errorf("%w: symlinks not allowed", ErrPathNotAllowed), "path", cleanPath)
 		}
 	}
 
+	// Check where the path really points as well as how it is spelled, so a
+	// symlinked parent cannot lead outside the allowed directories.
+	realPath := ResolveExisting(cleanPath)
+
 	for _, denied := range v.DeniedPaths {
-		if strings.HasPrefix(cleanPath, denied) {
-			return fmt.Errorf("%w: path is in denied list", ErrPathNotAllowed)
+		if matchRule(cleanPath, denied) || matchRule(realPath, resolveRule(denied)) {
+			return WithDetails(fmt.Errorf("%w: path is in denied list", ErrPathNotAllowed), "path", cleanPath, "denied_by", denied)
 		}
 	}
 
@@ -62,66 +72,153 @@ func (v *PathValidator) ValidatePath(path string) error {
 	}
 
 	for _, allowed := range v.AllowedPaths {
-		if strings.HasPrefix(cleanPath, allowed) {
-			return nil
+		resolved := resolveRule(allowed)
+		if !matchRule(realPath, resolved) {
+			continue
+		}
+		if !v.FollowSymlinks {
+			if link := symlinkBelow(cleanPath, allowed, resolved); link != "" {
+				return WithDetails(fmt.Errorf("%w: symlinks not allowed", ErrPathNotAllowed), "path", cleanPath, "symlink", link)
+			}
 		}
+		return nil
 	}
 
-	return fmt.Errorf("%w: path not in allowed list", ErrPathNotAllowed)
+	return WithDetails(fmt.Errorf("%w: path not in allowed list", ErrPathNotAllowed), "path", cleanPath)
 }
 
-func (v *PathValidator) ResolvePath(path string) (string, error) {
-	if err := v.ValidatePath(path); err != nil {
-		return "", err
+// IsWithin reports whether path is root or below it. Unlike a string prefix
+// check, /home/user2 is not within /home/user.
+func IsWithin(path, root string) bool {
+	rel, err := filepath.Rel(root, path)
+	if err != nil {
+		return false
 	}
+	return rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
 
-	absPath, err := filepath.Abs(path)
-	if err != nil {
-		return "", err
+func isPattern(rule string) bool {
+	return strings.ContainsAny(rule, "*?[")
+}
+
+// matchRule reports whether path is covered by a denied or allowed entry.
+func matchRule(path, rule string) bool {
+	if !isPattern(rule) {
+		return IsWithin(path, rule)
+	}
+	pattern := splitPath(rule)
+	if !filepath.IsAbs(rule) {
+		pattern = append([]string{"**"}, pattern...)
 	}
+	return matchComponents(pattern, splitPath(path))
+}
 
-	return filepath.Clean(absPath), nil
+// matchComponents reports whether pattern matches parts or one of its
+// prefixes.
+func matchComponents(pattern, parts []string) bool {
+	if len(pattern) == 0 {
+		return true
+	}
+	if pattern[0] == "**" {
+		for i := 0; i <= len(parts); i++ {
+			if matchComponents(pattern[1:], parts[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(parts) == 0 {
+		return false
+	}
+	ok, _ := filepath.Match(pattern[0], parts[0])
+	return ok && matchComponents(pattern[1:], parts[1:])
 }
 
-type CommandValidator struct {
-	AllowedCommands []string
-	DeniedCommands  []string
+func splitPath(p string) []string {
+	return strings.Split(filepath.ToSlash(filepath.Clean(p)), "/")
 }
 
-func NewCommandValidator(allowed, denied []string) *CommandValidator {
-	return &CommandValidator{
-		AllowedCommands: allowed,
-		DeniedCommands:  denied,
+// joinPath reverses splitPath.
+func joinPath(parts []string) string {
+	if len(parts) == 1 && parts[0] == "" {
+		return string(filepath.Separator)
 	}
+	return filepath.FromSlash(strings.Join(parts, "/"))
 }
 
-func (v *CommandValidator) ValidateCommand(command string, args []string) error {
-	if command == "" {
-		return fmt.Errorf("%w: empty command", ErrInvalidInput)
+// resolveRule evaluates the symlinks in an entry, or for a pattern in the
+// directories before its first wildcard, so it can be compared with
+// resolved paths.
+func resolveRule(rule string) string {
+	if !isPattern(rule) {
+		return ResolveExisting(rule)
+	}
+	if !filepath.IsAbs(rule) {
+		return rule
+	}
+	parts := splitPath(rule)
+	for i, part := range parts {
+		if isPattern(part) {
+			prefix := ResolveExisting(joinPath(parts[:i]))
+			return filepath.Join(append([]string{prefix}, parts[i:]...)...)
+		}
 	}
+	return rule
+}
 
-	fullCmd := command
-	if len(args) > 0 {
-		fullCmd = command + " " + strings.Join(args, " ")
+// symlinkBelow returns the first symlink among the parents of path below
+// the allowed entry it is in, spelled either as configured or resolved, or
+// "" if there is none.
+func symlinkBelow(path string, rules ...string) string {
+	parts := splitPath(path)
+	for _, rule := range rules {
+		below := false
+		for i := 1; i < len(parts); i++ {
+			dir := joinPath(parts[:i])
+			if below {
+				if info, err := os.Lstat(dir); err == nil && info.Mode()&os.ModeSymlink != 0 {
+					return dir
+				}
+			} else {
+				below = matchRule(dir, rule)
+			}
+		}
+		if below {
+			return ""
+		}
 	}
+	return ""
+}
 
-	for _, denied := range v.DeniedCommands {
-		if strings.Contains(fullCmd, denied) {
-			return fmt.Errorf("%w: command matches denied pattern: %s", ErrCommandDenied, denied)
+// ResolveExisting evaluates symlinks in the longest existing ancestor of
+// path and appends the components that do not exist yet, so paths about to
+// be created resolve too.
+func ResolveExisting(path string) string {
+	path = filepath.Clean(path)
+	var rest []string
+	for dir := path; ; dir = filepath.Dir(dir) {
+		if resolved, err := filepath.EvalSymlinks(dir); err == nil {
+			return filepath.Join(append([]string{resolved}, rest...)...)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return path
 		}
+		rest = append([]string{filepath.Base(dir)}, rest...)
 	}
+}
 
-	if len(v.AllowedCommands) == 0 {
-		return nil
+func (v *PathValidator) ResolvePath(path string) (string, error) {
+	if err := v.ValidatePath(path); err != nil {
+		return "", err
 	}
 
-	for _, allowed := range v.AllowedCommands {
-		if strings.HasPrefix(command, allowed) {
-			return nil
-		}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
 	}
 
-	return fmt.Errorf("%w: command not in allowed list", ErrCommandDenied)
+	return filepath.Clean(absPath), nil
 }
 
 var envVarNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
@@ -45,6 +45,101 @@ func TestPathValidator(t *testing.T) {
 	})
 }
 
+func TestPathValidatorEscapes(t *testing.T) {
+	root := t.TempDir()
+	allowed := filepath.Join(root, "user")
+	sibling := filepath.Join(root, "user2")
+	outside := filepath.Join(root, "outside")
+	for _, dir := range []string{allowed, sibling, outside} {
+		require.NoError(t, os.Mkdir(dir, 0755))
+	}
+	require.NoError(t, os.Symlink(outside, filepath.Join(allowed, "link")))
+
+	for _, follow := range []bool{false, true} {
+		v := NewPathValidator([]string{allowed}, nil, follow)
+
+		t.Run("sibling sharing a prefix", func(t *testing.T) {
+			assert.True(t, IsPathNotAllowed(v.ValidatePath(sibling)))
+			assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(sibling, "file"))))
+		})
+
+		t.Run("dot-dot traversal", func(t *testing.T) {
+			assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(allowed, "..", "outside"))))
+			assert.True(t, IsPathNotAllowed(v.ValidatePath(allowed+"/sub/../../user2")))
+		})
+
+		t.Run("through a symlinked directory", func(t *testing.T) {
+			assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(allowed, "link", "file"))))
+			assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(allowed, "link", "new", "file"))))
+		})
+
+		t.Run("paths inside are still allowed", func(t *testing.T) {
+			assert.NoError(t, v.ValidatePath(allowed))
+			assert.NoError(t, v.ValidatePath(filepath.Join(allowed, "new", "file")))
+		})
+	}
+
+	t.Run("denied directory reached through a symlink", func(t *testing.T) {
+		v := NewPathValidator([]string{root}, []string{outside}, true)
+		assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(allowed, "link", "file"))))
+		assert.NoError(t, v.ValidatePath(filepath.Join(sibling, "file")))
+	})
+
+	t.Run("allowed root behind a symlink", func(t *testing.T) {
+		alias := filepath.Join(root, "alias")
+		require.NoError(t, os.Symlink(allowed, alias))
+		v := NewPathValidator([]string{alias}, nil, false)
+		assert.NoError(t, v.ValidatePath(filepath.Join(alias, "file")))
+		assert.NoError(t, v.ValidatePath(filepath.Join(allowed, "file")))
+	})
+}
+
+func TestPathValidatorPatterns(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"alice/projects/app", "bob/projects/api", "bob/scratch"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, dir), 0755))
+	}
+
+	t.Run("allowed pattern", func(t *testing.T) {
+		v := NewPathValidator([]string{filepath.Join(root, "*/projects")}, nil, true)
+		assert.NoError(t, v.ValidatePath(filepath.Join(root, "alice/projects")))
+		assert.NoError(t, v.ValidatePath(filepath.Join(root, "bob/projects/api/main.go")))
+		assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(root, "bob/scratch"))))
+		assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(root, "bob"))))
+	})
+
+	t.Run("denied patterns", func(t *testing.T) {
+		v := NewPathValidator([]string{root}, []string{"**/.env", "*.pem", filepath.Join(root, "**/secrets")}, true)
+		assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(root, ".env"))))
+		assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(root, "alice/projects/app/.env"))))
+		assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(root, "bob/key.pem"))))
+		assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(root, "bob/projects/secrets/token"))))
+		assert.NoError(t, v.ValidatePath(filepath.Join(root, "alice/.env.example")))
+		assert.NoError(t, v.ValidatePath(filepath.Join(root, "bob/projects/api/main.go")))
+	})
+
+	t.Run("denied pattern reached through a symlink", func(t *testing.T) {
+		link := filepath.Join(root, "alice/link")
+		require.NoError(t, os.Symlink(filepath.Join(root, "bob/scratch"), link))
+		v := NewPathValidator([]string{root}, []string{filepath.Join(root, "*/scratch")}, true)
+		assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(link, "notes.txt"))))
+	})
+}
+
+func TestPathValidatorNoFollow(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(root, "real/sub"), 0755))
+	require.NoError(t, os.Symlink(filepath.Join(root, "real"), filepath.Join(root, "alias")))
+
+	v := NewPathValidator([]string{root}, nil, false)
+	assert.NoError(t, v.ValidatePath(filepath.Join(root, "real/sub/file")))
+	assert.True(t, IsPathNotAllowed(v.ValidatePath(filepath.Join(root, "alias/sub/file"))),
+		"a symlinked parent inside the allowed path is refused too")
+
+	v = NewPathValidator([]string{root}, nil, true)
+	assert.NoError(t, v.ValidatePath(filepath.Join(root, "alias/sub/file")))
+}
+
 func TestCommandValidator(t *testing.T) {
 	t.Run("allow any command with empty lists", func(t *testing.T) {
 		v := NewCommandValidator(nil, nil)
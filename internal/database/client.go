@@ -0,0 +1,245 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+// nullMarker is what the clients print for NULL so it can be told apart from
+// an empty string.
+const nullMarker = `\N`
+
+type queryResult struct {
+	Columns   []string        `json:"columns"`
+	Rows      [][]interface{} `json:"rows"`
+	RowCount  int             `json:"row_count"`
+	Truncated bool            `json:"truncated"`
+}
+
+// run executes sql, with $1, $2... bound to params, through the command line
+// client for conn's driver. Read-only runs open sqlite files read-only. On
+// postgres they are limited to a single query statement in a session with
+// default_transaction_read_only, which the statement cannot turn off.
+func (s *Server) run(ctx context.Context, conn *config.DatabaseConnection, sql string, params []interface{}, readOnly bool) (*queryResult, error) {
+	bound, err := bindParams(sql, params)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.config.QueryTimeoutSeconds)*time.Second)
+	defer cancel()
+
+	var cmd *exec.Cmd
+	switch conn.Driver {
+	case "sqlite":
+		if strings.HasPrefix(strings.TrimSpace(bound), ".") {
+			return nil, fmt.Errorf("%w: sqlite dot-commands are not allowed", common.ErrInvalidInput)
+		}
+		args := []string{"-safe", "-bail", "-csv", "-header", "-nullvalue", nullMarker}
+		if readOnly {
+			args = append(args, "-readonly")
+		}
+		cmd = exec.CommandContext(ctx, "sqlite3", append(args, conn.Path, bound)...)
+	case "postgres":
+		if strings.HasPrefix(strings.TrimSpace(bound), `\`) {
+			return nil, fmt.Errorf("%w: psql meta-commands are not allowed", common.ErrInvalidInput)
+		}
+		if readOnly {
+			if err := checkReadOnlyQuery(bound); err != nil {
+				return nil, err
+			}
+		}
+		cmd = exec.CommandContext(ctx, "psql", "-X", "-q", "--csv", "-v", "ON_ERROR_STOP=1",
+			"-P", "null="+nullMarker, "-d", conn.DSN, "-c", bound)
+		options := fmt.Sprintf("-c standard_conforming_strings=on -c statement_timeout=%d", s.config.QueryTimeoutSeconds*1000)
+		if readOnly {
+			options += " -c default_transaction_read_only=on"
+		}
+		cmd.Env = append(os.Environ(), "PGOPTIONS="+options)
+	default:
+		return nil, fmt.Errorf("%w: unsupported driver %q", common.ErrInvalidInput, conn.Driver)
+	}
+
+	stdout := &limitedBuffer{max: s.config.MaxResultBytes}
+	var stderr bytes.Buffer
+	cmd.Stdout = stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%w: query exceeded %ds", common.ErrTimeout, s.config.QueryTimeoutSeconds)
+		}
+		var execErr *exec.Error
+		if errors.As(err, &execErr) {
+			return nil, fmt.Errorf("%w: %s client not installed: %v", common.ErrOperationFailed, conn.Driver, err)
+		}
+		return nil, fmt.Errorf("%w: %s", common.ErrOperationFailed, strings.TrimSpace(stderr.String()))
+	}
+
+	return parseCSV(stdout.Bytes(), stdout.truncated, s.config.MaxRows), nil
+}
+
+func parseCSV(data []byte, truncated bool, maxRows int) *queryResult {
+	result := &queryResult{Columns: []string{}, Rows: [][]interface{}{}, Truncated: truncated}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.FieldsPerRecord = -1
+	var records [][]string
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Only a record cut off by the byte limit can be malformed.
+			break
+		}
+		records = append(records, record)
+	}
+	if truncated && len(records) > 0 {
+		records = records[:len(records)-1]
+	}
+
+	if len(records) == 0 {
+		return result
+	}
+	result.Columns = records[0]
+	for _, record := range records[1:] {
+		if len(result.Rows) >= maxRows {
+			result.Truncated = true
+			break
+		}
+		row := make([]interface{}, len(record))
+		for i, v := range record {
+			if v == nullMarker {
+				row[i] = nil
+			} else {
+				row[i] = v
+			}
+		}
+		result.Rows = append(result.Rows, row)
+	}
+	result.RowCount = len(result.Rows)
+	return result
+}
+
+// bindParams replaces $1, $2... outside string literals, quoted identifiers
+// and comments with params rendered as SQL literals.
+func bindParams(sql string, params []interface{}) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == '\'' || c == '"':
+			end := i + 1
+			for end < len(sql) {
+				if sql[end] == c {
+					if end+1 < len(sql) && sql[end+1] == c {
+						end += 2
+						continue
+					}
+					break
+				}
+				end++
+			}
+			if end >= len(sql) {
+				end = len(sql) - 1
+			}
+			b.WriteString(sql[i : end+1])
+			i = end
+		case c == '-' && i+1 < len(sql) && sql[i+1] == '-':
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i - 1
+			}
+			b.WriteString(sql[i : i+end+1])
+			i += end
+		case c == '/' && i+1 < len(sql) && sql[i+1] == '*':
+			end := strings.Index(sql[i+2:], "*/")
+			if end < 0 {
+				b.WriteString(sql[i:])
+				i = len(sql)
+				continue
+			}
+			b.WriteString(sql[i : i+2+end+2])
+			i += end + 3
+		case c == '$' && i+1 < len(sql) && sql[i+1] >= '0' && sql[i+1] <= '9':
+			end := i + 1
+			for end < len(sql) && sql[end] >= '0' && sql[end] <= '9' {
+				end++
+			}
+			n, _ := strconv.Atoi(sql[i+1 : end])
+			if n < 1 || n > len(params) {
+				return "", fmt.Errorf("%w: $%d has no matching parameter (%d given)", common.ErrInvalidInput, n, len(params))
+			}
+			literal, err := sqlLiteral(params[n-1])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(literal)
+			i = end - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+func sqlLiteral(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "NULL", nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case int:
+		return strconv.Itoa(v), nil
+	case string:
+		if strings.ContainsRune(v, 0) {
+			return "", fmt.Errorf("%w: parameters cannot contain NUL bytes", common.ErrInvalidInput)
+		}
+		return "'" + strings.ReplaceAll(v, "'", "''") + "'", nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return "", fmt.Errorf("%w: unsupported parameter %v", common.ErrInvalidInput, v)
+		}
+		return sqlLiteral(string(data))
+	}
+}
+
+// limitedBuffer keeps the first max bytes written to it and discards the
+// rest, so a runaway query cannot exhaust memory.
+type limitedBuffer struct {
+	bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
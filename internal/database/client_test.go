@@ -0,0 +1,94 @@
+package database
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+func TestBindParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		sql    string
+		params []interface{}
+		want   string
+	}{
+		{"string", "SELECT * FROM t WHERE a = $1", []interface{}{"x'; DROP TABLE t; --"}, "SELECT * FROM t WHERE a = 'x''; DROP TABLE t; --'"},
+		{"types", "VALUES ($1, $2, $3, $4)", []interface{}{float64(1.5), true, nil, "s"}, "VALUES (1.5, TRUE, NULL, 's')"},
+		{"reuse", "SELECT $1 + $1", []interface{}{float64(2)}, "SELECT 2 + 2"},
+		{"quoted", `SELECT '$1', "$1", $1`, []interface{}{"v"}, `SELECT '$1', "$1", 'v'`},
+		{"escaped quote", "SELECT 'it''s $1', $1", []interface{}{"v"}, "SELECT 'it''s $1', 'v'"},
+		{"comments", "SELECT $1 -- $2\n/* $2 */", []interface{}{"v"}, "SELECT 'v' -- $2\n/* $2 */"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := bindParams(tt.sql, tt.params)
+			if err != nil {
+				t.Fatalf("bindParams: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := bindParams("SELECT $2", []interface{}{"a"}); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("missing parameter: got %v, want ErrInvalidInput", err)
+	}
+}
+
+func TestParseCSVLimits(t *testing.T) {
+	data := []byte("id,name\n1,a\n2,\\N\n3,c\n")
+
+	result := parseCSV(data, false, 2)
+	if !result.Truncated || result.RowCount != 2 {
+		t.Fatalf("got %d rows, truncated=%v; want 2 rows, truncated", result.RowCount, result.Truncated)
+	}
+	if result.Rows[1][1] != nil {
+		t.Errorf("NULL marker parsed as %v, want nil", result.Rows[1][1])
+	}
+
+	// A byte limit can cut the last record short; it must be dropped.
+	result = parseCSV(data[:len(data)-3], true, 10)
+	if result.RowCount != 2 || !result.Truncated {
+		t.Errorf("got %d rows, truncated=%v; want 2 rows, truncated", result.RowCount, result.Truncated)
+	}
+}
+
+func TestCheckReadOnlyQuery(t *testing.T) {
+	allowed := []string{
+		"SELECT 1",
+		"select * from t;",
+		"  -- comment\n(SELECT 1) UNION (SELECT 2)",
+		"WITH x AS (SELECT 1) SELECT * FROM x",
+		"EXPLAIN SELECT 1",
+		"SHOW search_path",
+		"SELECT 'a;b', \"c;d\", $$e;f$$, $tag$g;$$;h$tag$ /* ; */",
+		"SELECT E'it\\'s; fine'",
+		"SELECT /* outer /* inner */ ; still comment */ 1",
+	}
+	for _, sql := range allowed {
+		if err := checkReadOnlyQuery(sql); err != nil {
+			t.Errorf("%q: %v", sql, err)
+		}
+	}
+
+	rejected := []string{
+		"",
+		"DELETE FROM t",
+		"SET transaction_read_only = off; DELETE FROM t",
+		"SELECT 1; COMMIT; BEGIN READ WRITE; DELETE FROM t",
+		"BEGIN READ WRITE",
+		"DO $$BEGIN COMMIT; END$$",
+		"CALL p()",
+		"SELECT E'\\''; DELETE FROM t; SELECT '",
+		"SELECT /* /* */ ' */ ; DELETE FROM t; -- '",
+		"SELECT 1 -- ;\n; DELETE FROM t",
+	}
+	for _, sql := range rejected {
+		if err := checkReadOnlyQuery(sql); !errors.Is(err, common.ErrInvalidInput) {
+			t.Errorf("%q: got %v, want ErrInvalidInput", sql, err)
+		}
+	}
+}
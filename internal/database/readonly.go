@@ -0,0 +1,126 @@
+package database
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+// readOnlyKeywords are the statements query_database runs on Postgres. The
+// session is read-only as well, but only by default: a SET, a transaction
+// statement or a procedure that commits could turn that off for what
+// follows, so query_database takes a single statement of these kinds.
+var readOnlyKeywords = []string{"select", "with", "values", "table", "show", "explain"}
+
+// checkReadOnlyQuery rejects sql unless it is a single statement starting
+// with one of readOnlyKeywords.
+func checkReadOnlyQuery(sql string) error {
+	statements := splitStatements(sql)
+	if len(statements) != 1 {
+		return fmt.Errorf("%w: query_database runs a single statement; got %d", common.ErrInvalidInput, len(statements))
+	}
+	keyword := strings.TrimLeft(statements[0], "( \t\r\n")
+	if end := strings.IndexFunc(keyword, func(r rune) bool { return !unicode.IsLetter(r) && r != '_' }); end >= 0 {
+		keyword = keyword[:end]
+	}
+	keyword = strings.ToLower(keyword)
+	for _, k := range readOnlyKeywords {
+		if keyword == k {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: query_database only runs %s statements; use execute_sql for %s",
+		common.ErrInvalidInput, strings.ToUpper(strings.Join(readOnlyKeywords, ", ")), strings.ToUpper(keyword))
+}
+
+// splitStatements splits sql at the semicolons that end its statements,
+// skipping string literals, including escape strings such as E'\n' and
+// dollar-quoted ones, quoted identifiers and nested comments the way
+// Postgres does. Comments are dropped and empty statements left out.
+func splitStatements(sql string) []string {
+	var statements []string
+	var b strings.Builder
+	flush := func() {
+		if s := strings.TrimSpace(b.String()); s != "" {
+			statements = append(statements, s)
+		}
+		b.Reset()
+	}
+	for i := 0; i < len(sql); i++ {
+		c := sql[i]
+		switch {
+		case c == ';':
+			flush()
+		case c == '-' && strings.HasPrefix(sql[i:], "--"):
+			end := strings.IndexByte(sql[i:], '\n')
+			if end < 0 {
+				end = len(sql) - i
+			}
+			b.WriteByte(' ')
+			i += end
+		case c == '/' && strings.HasPrefix(sql[i:], "/*"):
+			depth := 0
+			for ; i < len(sql); i++ {
+				if strings.HasPrefix(sql[i:], "/*") {
+					depth++
+					i++
+				} else if strings.HasPrefix(sql[i:], "*/") {
+					depth--
+					i++
+					if depth == 0 {
+						break
+					}
+				}
+			}
+			b.WriteByte(' ')
+		case c == '\'' || c == '"':
+			escapes := c == '\'' && i > 0 && (sql[i-1] == 'e' || sql[i-1] == 'E') && (i < 2 || !isIdentChar(sql[i-2]))
+			end := i + 1
+			for ; end < len(sql); end++ {
+				if escapes && sql[end] == '\\' {
+					end++
+				} else if sql[end] == c {
+					if end+1 < len(sql) && sql[end+1] == c {
+						end++
+						continue
+					}
+					break
+				}
+			}
+			if end >= len(sql) {
+				end = len(sql) - 1
+			}
+			b.WriteString(sql[i : end+1])
+			i = end
+		case c == '$' && (i == 0 || !isIdentChar(sql[i-1])):
+			tagEnd := i + 1
+			for tagEnd < len(sql) && isIdentChar(sql[tagEnd]) && sql[tagEnd] != '$' {
+				tagEnd++
+			}
+			if tagEnd >= len(sql) || sql[tagEnd] != '$' || (tagEnd > i+1 && sql[i+1] >= '0' && sql[i+1] <= '9') {
+				b.WriteByte(c)
+				continue
+			}
+			tag := sql[i : tagEnd+1]
+			end := strings.Index(sql[tagEnd+1:], tag)
+			if end < 0 {
+				b.WriteString(sql[i:])
+				i = len(sql)
+				continue
+			}
+			end += tagEnd + 1 + len(tag)
+			b.WriteString(sql[i:end])
+			i = end - 1
+		default:
+			b.WriteByte(c)
+		}
+	}
+	flush()
+	return statements
+}
+
+func isIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c >= 0x80
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"fmt"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type Server struct {
+	config *config.DatabaseConfig
+	logger *common.Logger
+}
+
+func NewServer(cfg *config.DatabaseConfig) *Server {
+	s := &Server{
+		config: cfg,
+		logger: common.NewServerLogger("database"),
+	}
+	common.RegisterConfigSection("database", s.configSection)
+	return s
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) connection(name string) (*config.DatabaseConnection, error) {
+	names := make([]string, 0, len(s.config.Connections))
+	for i := range s.config.Connections {
+		conn := &s.config.Connections[i]
+		if conn.Name == name {
+			return conn, nil
+		}
+		names = append(names, conn.Name)
+	}
+	return nil, fmt.Errorf("%w: connection %s (configured: %v)", common.ErrNotFound, name, names)
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		s.listConnectionsTool(),
+		s.listSchemasTool(),
+		s.listTablesTool(),
+		s.describeTableTool(),
+		s.queryTool(),
+		common.Audited("database", s.executeTool()),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
+		common.AuditLogTool(),
+	}
+	return common.Instrument("database", tools)
+}
+
+func (s *Server) RegisterTools(server *mcp.Server) {
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
+}
@@ -0,0 +1,249 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+var paramsProperty = map[string]interface{}{
+	"type":        "array",
+	"description": "Values bound to $1, $2... in the SQL",
+	"items":       map[string]interface{}{"type": []string{"string", "number", "boolean", "null"}},
+}
+
+func (s *Server) listConnectionsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_databases",
+		Description: "List the configured database connections",
+		InputSchema: mcp.BuildInputSchema(map[string]interface{}{}, []string{}),
+		Annotations: mcp.ReadOnly(),
+		Handler:     s.handleListConnections,
+	}
+}
+
+func (s *Server) handleListConnections(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
+	var connections []map[string]interface{}
+	for _, conn := range s.config.Connections {
+		connections = append(connections, map[string]interface{}{
+			"name":         conn.Name,
+			"driver":       conn.Driver,
+			"allow_writes": conn.AllowWrites,
+		})
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"connections": connections,
+		"count":       len(connections),
+	})
+}
+
+func (s *Server) listSchemasTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_schemas",
+		Description: "List the schemas (attached databases for SQLite) of a connection",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"connection": mcp.StringProperty("Connection name"),
+			},
+			[]string{"connection"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleListSchemas),
+	}
+}
+
+type listSchemasArgs struct {
+	Connection string `json:"connection" validate:"required"`
+}
+
+func (s *Server) handleListSchemas(ctx context.Context, args listSchemasArgs) (*mcp.ToolResult, error) {
+	conn, err := s.connection(args.Connection)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "SELECT name FROM pragma_database_list ORDER BY seq"
+	if conn.Driver == "postgres" {
+		query = `SELECT schema_name AS name FROM information_schema.schemata
+WHERE schema_name NOT IN ('information_schema', 'pg_catalog', 'pg_toast') AND schema_name NOT LIKE 'pg_temp_%'
+ORDER BY schema_name`
+	}
+	result, err := s.run(ctx, conn, query, nil, true)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.JSONResult(result)
+}
+
+func (s *Server) listTablesTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_tables",
+		Description: "List the tables and views of a connection",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"connection": mcp.StringProperty("Connection name"),
+				"schema":     mcp.StringProperty("Schema to list (default: main for SQLite, public for Postgres)"),
+			},
+			[]string{"connection"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleListTables),
+	}
+}
+
+type listTablesArgs struct {
+	Connection string `json:"connection" validate:"required"`
+	Schema     string `json:"schema"`
+}
+
+func (s *Server) handleListTables(ctx context.Context, args listTablesArgs) (*mcp.ToolResult, error) {
+	schema := args.Schema
+	conn, err := s.connection(args.Connection)
+	if err != nil {
+		return nil, err
+	}
+
+	var query string
+	if conn.Driver == "postgres" {
+		if schema == "" {
+			schema = "public"
+		}
+		query = `SELECT table_name AS name, lower(table_type) AS type FROM information_schema.tables
+WHERE table_schema = $1 ORDER BY table_name`
+	} else {
+		if schema == "" {
+			schema = "main"
+		}
+		query = `SELECT name, type FROM pragma_table_list WHERE schema = $1
+AND type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name`
+	}
+	result, err := s.run(ctx, conn, query, []interface{}{schema}, true)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.JSONResult(result)
+}
+
+func (s *Server) describeTableTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "describe_table",
+		Description: "Show the columns of a table",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"connection": mcp.StringProperty("Connection name"),
+				"table":      mcp.StringProperty("Table name"),
+				"schema":     mcp.StringProperty("Schema of the table (default: main for SQLite, public for Postgres)"),
+			},
+			[]string{"connection", "table"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleDescribeTable),
+	}
+}
+
+type describeTableArgs struct {
+	Connection string `json:"connection" validate:"required"`
+	Table      string `json:"table" validate:"required"`
+	Schema     string `json:"schema"`
+}
+
+func (s *Server) handleDescribeTable(ctx context.Context, args describeTableArgs) (*mcp.ToolResult, error) {
+	table, schema := args.Table, args.Schema
+	conn, err := s.connection(args.Connection)
+	if err != nil {
+		return nil, err
+	}
+
+	var query string
+	if conn.Driver == "postgres" {
+		if schema == "" {
+			schema = "public"
+		}
+		query = `SELECT column_name AS name, data_type AS type, is_nullable = 'NO' AS not_null,
+column_default AS default_value FROM information_schema.columns
+WHERE table_schema = $1 AND table_name = $2 ORDER BY ordinal_position`
+	} else {
+		if schema == "" {
+			schema = "main"
+		}
+		query = `SELECT name, type, "notnull" AS not_null, dflt_value AS default_value, pk AS primary_key
+FROM pragma_table_info($2, $1) ORDER BY cid`
+	}
+	result, err := s.run(ctx, conn, query, []interface{}{schema, table}, true)
+	if err != nil {
+		return nil, err
+	}
+	if result.RowCount == 0 {
+		return nil, fmt.Errorf("%w: table %s.%s", common.ErrNotFound, schema, table)
+	}
+	return mcp.JSONResult(result)
+}
+
+func (s *Server) queryTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "query_database",
+		Description: "Run a read-only SQL query and return the rows. On Postgres this is a single SELECT, WITH, VALUES, TABLE, SHOW or EXPLAIN statement",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"connection": mcp.StringProperty("Connection name"),
+				"sql":        mcp.StringProperty("SQL query, with $1, $2... for parameters"),
+				"params":     paramsProperty,
+			},
+			[]string{"connection", "sql"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleQuery),
+	}
+}
+
+type sqlArgs struct {
+	Connection string        `json:"connection" validate:"required"`
+	SQL        string        `json:"sql" validate:"required"`
+	Params     []interface{} `json:"params"`
+}
+
+func (s *Server) handleQuery(ctx context.Context, args sqlArgs) (*mcp.ToolResult, error) {
+	return s.handleSQL(ctx, args, true)
+}
+
+func (s *Server) executeTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "execute_sql",
+		Description: "Run a SQL statement that may modify data (only on connections with allow_writes)",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"connection": mcp.StringProperty("Connection name"),
+				"sql":        mcp.StringProperty("SQL statement, with $1, $2... for parameters"),
+				"params":     paramsProperty,
+			},
+			[]string{"connection", "sql"},
+		),
+		Annotations: mcp.Destructive(),
+		Handler:     mcp.TypedHandler(s.handleExecute),
+	}
+}
+
+func (s *Server) handleExecute(ctx context.Context, args sqlArgs) (*mcp.ToolResult, error) {
+	return s.handleSQL(ctx, args, false)
+}
+
+func (s *Server) handleSQL(ctx context.Context, args sqlArgs, readOnly bool) (*mcp.ToolResult, error) {
+	conn, err := s.connection(args.Connection)
+	if err != nil {
+		return nil, err
+	}
+	if !readOnly && !conn.AllowWrites {
+		return nil, common.WithDetails(
+			fmt.Errorf("%w: connection %s is read-only (set allow_writes to enable execute_sql)", common.ErrPermissionDenied, conn.Name),
+			"connection", conn.Name)
+	}
+
+	s.logger.WithFields(map[string]interface{}{"connection": conn.Name, "read_only": readOnly}).Debug("Running SQL")
+	result, err := s.run(ctx, conn, args.SQL, args.Params, readOnly)
+	if err != nil {
+		return nil, common.WithDetails(err, "connection", conn.Name)
+	}
+	return mcp.JSONResult(result)
+}
@@ -0,0 +1,317 @@
+package docker
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) buildImageTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "build_image",
+		Description: "Build a Docker image from a context directory, reporting progress as the build runs",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"context_dir": mcp.StringProperty("Build context directory"),
+				"dockerfile":  mcp.StringProperty("Dockerfile path relative to the context (default: Dockerfile)"),
+				"tag":         mcp.StringProperty("Name and optional tag for the image, e.g. myapp:dev"),
+				"build_args":  mcp.MapProperty("Build-time variables"),
+				"no_cache":    mcp.BoolProperty("Do not use the build cache"),
+				"pull":        mcp.BoolProperty("Always pull newer base images"),
+			},
+			[]string{"context_dir"},
+		),
+		Annotations: mcp.Additive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleBuildImage),
+	}
+}
+
+type buildMessage struct {
+	Stream string `json:"stream"`
+	Status string `json:"status"`
+	ID     string `json:"id"`
+	Error  string `json:"error"`
+	Aux    struct {
+		ID string `json:"ID"`
+	} `json:"aux"`
+}
+
+var buildStepRe = regexp.MustCompile(`^Step (\d+)/(\d+) :`)
+
+type buildImageArgs struct {
+	ContextDir string            `json:"context_dir" validate:"required"`
+	Dockerfile string            `json:"dockerfile"`
+	Tag        string            `json:"tag"`
+	BuildArgs  map[string]string `json:"build_args"`
+	NoCache    bool              `json:"no_cache"`
+	Pull       bool              `json:"pull"`
+}
+
+func (s *Server) handleBuildImage(ctx context.Context, args buildImageArgs) (*mcp.ToolResult, error) {
+	contextDir, dockerfile, tag, buildArgs := args.ContextDir, args.Dockerfile, args.Tag, args.BuildArgs
+	noCache, pull := args.NoCache, args.Pull
+	api, err := s.api()
+	if err != nil {
+		return nil, err
+	}
+
+	dir, err := s.validator.ResolvePath(contextDir)
+	if err != nil {
+		return nil, err
+	}
+	if dockerfile == "" {
+		dockerfile = "Dockerfile"
+	}
+	dockerfile = filepath.ToSlash(filepath.Clean(dockerfile))
+	if filepath.IsAbs(dockerfile) || strings.HasPrefix(dockerfile, "../") {
+		return nil, fmt.Errorf("%w: dockerfile must be inside the build context", common.ErrInvalidInput)
+	}
+	if _, err := os.Stat(filepath.Join(dir, dockerfile)); err != nil {
+		return nil, common.WithDetails(fmt.Errorf("%w: %s", common.ErrNotFound, dockerfile), "path", filepath.Join(dir, dockerfile))
+	}
+	ignore, err := readDockerignore(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{
+		"dockerfile": {dockerfile},
+		"nocache":    {strconv.FormatBool(noCache)},
+		"pull":       {strconv.FormatBool(pull)},
+		"rm":         {"true"},
+	}
+	if tag != "" {
+		query.Set("t", tag)
+	}
+	if len(buildArgs) > 0 {
+		data, _ := json.Marshal(buildArgs)
+		query.Set("buildargs", string(data))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.config.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	body, w := io.Pipe()
+	go func() {
+		w.CloseWithError(writeContext(w, dir, ignore, dockerfile))
+	}()
+	defer body.Close()
+
+	start := time.Now()
+	resp, err := api.do(ctx, http.MethodPost, "/build", query, body, "application/x-tar")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var (
+		lines   []string
+		imageID string
+		failure string
+		count   float64
+	)
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var msg buildMessage
+		if json.Unmarshal(scanner.Bytes(), &msg) != nil {
+			continue
+		}
+		if msg.Aux.ID != "" {
+			imageID = msg.Aux.ID
+		}
+		if msg.Error != "" {
+			failure = msg.Error
+		}
+
+		text := strings.TrimRight(msg.Stream, "\n")
+		if text == "" && msg.Status != "" {
+			text = strings.TrimSpace(msg.ID + " " + msg.Status)
+		}
+		if text == "" {
+			continue
+		}
+		lines = append(lines, text)
+		if len(lines) > s.config.MaxLogLines {
+			lines = lines[1:]
+		}
+
+		if m := buildStepRe.FindStringSubmatch(text); m != nil {
+			step, _ := strconv.Atoi(m[1])
+			total, _ := strconv.Atoi(m[2])
+			mcp.ReportProgress(ctx, float64(step), float64(total), text)
+		} else if msg.Stream != "" {
+			count++
+			mcp.ReportProgress(ctx, count, 0, text)
+		}
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("%w: build did not finish within %ds", common.ErrTimeout, s.config.TimeoutSeconds)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: reading build output: %v", common.ErrOperationFailed, err)
+	}
+
+	output := truncate(strings.Join(lines, "\n"), s.config.MaxOutputSizeBytes)
+	if failure != "" {
+		return nil, common.WithDetails(
+			fmt.Errorf("%w: build failed: %s\n%s", common.ErrOperationFailed, failure, output),
+			"context_dir", dir)
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"image_id":    shortID(imageID),
+		"tag":         tag,
+		"output":      output,
+		"duration_ms": time.Since(start).Milliseconds(),
+	})
+}
+
+// writeContext tars dir, skipping what .dockerignore excludes. The
+// Dockerfile and .dockerignore are always sent, as the docker CLI does.
+func writeContext(w io.Writer, dir string, ignore []ignorePattern, dockerfile string) error {
+	tw := tar.NewWriter(w)
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != dockerfile && rel != ".dockerignore" && ignored(ignore, rel) {
+			if info.IsDir() && !hasException(ignore) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		link := ""
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		}
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = rel
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return tw.Close()
+}
+
+type ignorePattern struct {
+	re     *regexp.Regexp
+	negate bool
+}
+
+func readDockerignore(dir string) ([]ignorePattern, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".dockerignore"))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return parseDockerignore(string(data)), nil
+}
+
+func parseDockerignore(data string) []ignorePattern {
+	var patterns []ignorePattern
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p := ignorePattern{}
+		if strings.HasPrefix(line, "!") {
+			p.negate = true
+			line = strings.TrimSpace(line[1:])
+		}
+		line = strings.TrimPrefix(filepath.ToSlash(filepath.Clean(line)), "/")
+		p.re = regexp.MustCompile("^" + globToRegexp(line) + "(/.*)?$")
+		patterns = append(patterns, p)
+	}
+	return patterns
+}
+
+// globToRegexp translates a .dockerignore pattern: * and ? stay within one
+// path element, ** spans any number of them.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		switch c := glob[i]; c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// ignored applies the patterns in order; the last one matching wins.
+func ignored(patterns []ignorePattern, rel string) bool {
+	result := false
+	for _, p := range patterns {
+		if p.re.MatchString(rel) {
+			result = !p.negate
+		}
+	}
+	return result
+}
+
+// hasException reports whether any exception exists, in which case an
+// ignored directory must still be walked for files it re-includes.
+func hasException(patterns []ignorePattern) bool {
+	for _, p := range patterns {
+		if p.negate {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+// client is a minimal Docker Engine API client. Paths are unversioned, so
+// the daemon serves its own API version.
+type client struct {
+	host string
+	base string
+	http *http.Client
+}
+
+func newClient(host string) (*client, error) {
+	switch {
+	case strings.HasPrefix(host, "unix://"):
+		socket := strings.TrimPrefix(host, "unix://")
+		transport := &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", socket)
+			},
+		}
+		return &client{host: host, base: "http://docker", http: &http.Client{Transport: transport}}, nil
+	case strings.HasPrefix(host, "tcp://"):
+		return &client{host: host, base: "http://" + strings.TrimPrefix(host, "tcp://"), http: &http.Client{}}, nil
+	default:
+		return nil, fmt.Errorf("%w: unsupported docker host %q", common.ErrInvalidInput, host)
+	}
+}
+
+// do sends a request and returns the response when the daemon answered with
+// a 2xx status. The caller closes the body.
+func (c *client) do(ctx context.Context, method, path string, query url.Values, body io.Reader, contentType string) (*http.Response, error) {
+	u := c.base + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%w: docker %s %s", common.ErrTimeout, method, path)
+		}
+		return nil, fmt.Errorf("%w: docker daemon not reachable at %s: %v", common.ErrOperationFailed, c.host, err)
+	}
+	if resp.StatusCode/100 == 2 {
+		return resp, nil
+	}
+	defer resp.Body.Close()
+
+	var apiErr struct {
+		Message string `json:"message"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if json.Unmarshal(data, &apiErr) != nil || apiErr.Message == "" {
+		apiErr.Message = strings.TrimSpace(string(data))
+	}
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", common.ErrNotFound, apiErr.Message)
+	case http.StatusBadRequest:
+		return nil, fmt.Errorf("%w: %s", common.ErrInvalidInput, apiErr.Message)
+	default:
+		return nil, fmt.Errorf("%w: docker: %s", common.ErrOperationFailed, apiErr.Message)
+	}
+}
+
+func (c *client) getJSON(ctx context.Context, path string, query url.Values, out interface{}) error {
+	resp, err := c.do(ctx, http.MethodGet, path, query, nil, "")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *client) postJSON(ctx context.Context, path string, in, out interface{}) error {
+	data, err := json.Marshal(in)
+	if err != nil {
+		return err
+	}
+	resp, err := c.do(ctx, http.MethodPost, path, nil, bytes.NewReader(data), "application/json")
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// demux splits a multiplexed attach/logs stream, where each frame starts
+// with an 8-byte header naming the stream and the payload length.
+func demux(r io.Reader, stdout, stderr io.Writer) error {
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return err
+		}
+		w := stdout
+		if header[0] == 2 {
+			w = stderr
+		}
+		size := int64(binary.BigEndian.Uint32(header[4:]))
+		if _, err := io.CopyN(w, r, size); err != nil {
+			return err
+		}
+	}
+}
+
+func filtersParam(filters map[string][]string) string {
+	data, _ := json.Marshal(filters)
+	return string(data)
+}
+
+// limitedBuffer keeps the first max bytes written to it and drops the rest.
+type limitedBuffer struct {
+	bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.max - b.Len(); room < len(p) {
+		b.truncated = true
+		if room > 0 {
+			b.Buffer.Write(p[:room])
+		}
+		return len(p), nil
+	}
+	return b.Buffer.Write(p)
+}
+
+func (b *limitedBuffer) String() string {
+	if b.truncated {
+		return b.Buffer.String() + "\n... (truncated)"
+	}
+	return b.Buffer.String()
+}
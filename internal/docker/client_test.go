@@ -0,0 +1,177 @@
+package docker
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/config"
+)
+
+func frame(stream byte, payload string) []byte {
+	header := make([]byte, 8)
+	header[0] = stream
+	binary.BigEndian.PutUint32(header[4:], uint32(len(payload)))
+	return append(header, payload...)
+}
+
+func TestDemux(t *testing.T) {
+	var data []byte
+	data = append(data, frame(1, "out1\n")...)
+	data = append(data, frame(2, "err\n")...)
+	data = append(data, frame(1, "out2\n")...)
+
+	var stdout, stderr bytes.Buffer
+	if err := demux(bytes.NewReader(data), &stdout, &stderr); err != nil {
+		t.Fatalf("demux: %v", err)
+	}
+	if stdout.String() != "out1\nout2\n" || stderr.String() != "err\n" {
+		t.Errorf("stdout %q, stderr %q", stdout.String(), stderr.String())
+	}
+}
+
+func TestDockerignore(t *testing.T) {
+	patterns := parseDockerignore("# comment\nnode_modules\n*.log\n**/secret.txt\n!keep.log\n/build\n")
+	tests := map[string]bool{
+		"node_modules":          true,
+		"node_modules/x/y.js":   true,
+		"app.log":               true,
+		"keep.log":              false,
+		"logs/app.log":          false,
+		"secret.txt":            true,
+		"config/dev/secret.txt": true,
+		"build/out":             true,
+		"src/build":             false,
+		"main.go":               false,
+	}
+	for path, want := range tests {
+		if got := ignored(patterns, path); got != want {
+			t.Errorf("ignored(%q) = %v, want %v", path, got, want)
+		}
+	}
+}
+
+func newTestServer(t *testing.T, handler http.Handler) *Server {
+	t.Helper()
+	ts := httptest.NewServer(handler)
+	t.Cleanup(ts.Close)
+
+	cfg := config.DefaultConfig().Docker
+	cfg.Host = "tcp://" + strings.TrimPrefix(ts.URL, "http://")
+	cfg.AllowExec = true
+	cfg.AllowedProjectPaths = []string{t.TempDir()}
+	return NewServer(&cfg)
+}
+
+func TestExecInContainer(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/containers/web/exec", func(w http.ResponseWriter, r *http.Request) {
+		var body struct{ Cmd []string }
+		json.NewDecoder(r.Body).Decode(&body)
+		if strings.Join(body.Cmd, " ") != "ls -la" {
+			t.Errorf("Cmd = %v", body.Cmd)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"Id":"e1"}`))
+	})
+	mux.HandleFunc("/exec/e1/start", func(w http.ResponseWriter, r *http.Request) {
+		w.Write(frame(1, "total 0\n"))
+		w.Write(frame(2, "warning\n"))
+	})
+	mux.HandleFunc("/exec/e1/json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"ExitCode":3}`))
+	})
+	s := newTestServer(t, mux)
+
+	result, err := s.execTool().Handler(context.Background(), map[string]interface{}{
+		"container": "web",
+		"command":   []interface{}{"ls", "-la"},
+	})
+	if err != nil {
+		t.Fatalf("handleExec: %v", err)
+	}
+	var out struct {
+		ExitCode int    `json:"exit_code"`
+		Stdout   string `json:"stdout"`
+		Stderr   string `json:"stderr"`
+	}
+	if err := json.Unmarshal([]byte(result.Content[0].Text), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ExitCode != 3 || out.Stdout != "total 0\n" || out.Stderr != "warning\n" {
+		t.Errorf("got %+v", out)
+	}
+
+	s.config.AllowExec = false
+	if _, err := s.execTool().Handler(context.Background(), map[string]interface{}{
+		"container": "web",
+		"command":   []interface{}{"ls"},
+	}); err == nil {
+		t.Error("exec allowed with allow_exec disabled")
+	}
+}
+
+func TestDaemonErrors(t *testing.T) {
+	s := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"message":"No such container: nope"}`))
+	}))
+
+	_, err := s.containerLogsTool().Handler(context.Background(), map[string]interface{}{"container": "nope"})
+	if err == nil || !strings.Contains(err.Error(), "not found: No such container: nope") {
+		t.Errorf("got %v", err)
+	}
+}
+
+func TestBuildImage(t *testing.T) {
+	var sent []string
+	s := newTestServer(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/build" || r.URL.Query().Get("t") != "app:dev" {
+			t.Errorf("unexpected request %s", r.URL)
+		}
+		tr := tar.NewReader(r.Body)
+		for {
+			h, err := tr.Next()
+			if err != nil {
+				break
+			}
+			sent = append(sent, h.Name)
+		}
+		w.Write([]byte(`{"stream":"Step 1/2 : FROM scratch\n"}` + "\n"))
+		w.Write([]byte(`{"stream":"Step 2/2 : COPY . /\n"}` + "\n"))
+		w.Write([]byte(`{"aux":{"ID":"sha256:0123456789abcdef"}}` + "\n"))
+	}))
+	dir := s.config.AllowedProjectPaths[0]
+	os.WriteFile(filepath.Join(dir, "Dockerfile"), []byte("FROM scratch\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ".dockerignore"), []byte("*.log\n"), 0644)
+	os.WriteFile(filepath.Join(dir, "app.log"), []byte("x"), 0644)
+	os.WriteFile(filepath.Join(dir, "main.go"), []byte("x"), 0644)
+
+	result, err := s.buildImageTool().Handler(context.Background(), map[string]interface{}{
+		"context_dir": dir,
+		"tag":         "app:dev",
+	})
+	if err != nil {
+		t.Fatalf("handleBuildImage: %v", err)
+	}
+	sort.Strings(sent)
+	if strings.Join(sent, ",") != ".dockerignore,Dockerfile,main.go" {
+		t.Errorf("context contained %v", sent)
+	}
+	if !strings.Contains(result.Content[0].Text, `"image_id": "0123456789ab"`) {
+		t.Errorf("result %s", result.Content[0].Text)
+	}
+
+	if _, err := s.buildImageTool().Handler(context.Background(), map[string]interface{}{"context_dir": "/"}); err == nil {
+		t.Error("build allowed outside allowed_project_paths")
+	}
+}
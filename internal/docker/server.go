@@ -0,0 +1,59 @@
+package docker
+
+import (
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type Server struct {
+	config    *config.DockerConfig
+	validator *common.PathValidator
+	client    *client
+	logger    *common.Logger
+}
+
+func NewServer(cfg *config.DockerConfig) *Server {
+	s := &Server{
+		config: cfg,
+		logger: common.NewServerLogger("docker"),
+	}
+	s.Reload()
+	common.RegisterConfigSection("docker", s.configSection)
+	return s
+}
+
+func (s *Server) Reload() {
+	s.validator = common.NewPathValidator(s.config.AllowedProjectPaths, nil, true)
+	c, err := newClient(s.config.Host)
+	if err != nil {
+		s.logger.Errorf("Invalid docker host: %v", err)
+	}
+	s.client = c
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		s.listContainersTool(),
+		s.listImagesTool(),
+		s.containerLogsTool(),
+		common.Audited("docker", s.execTool()),
+		common.Audited("docker", s.composeUpTool()),
+		common.Audited("docker", s.composeDownTool()),
+		common.Audited("docker", s.buildImageTool()),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
+		common.AuditLogTool(),
+	}
+	return common.Instrument("docker", tools)
+}
+
+func (s *Server) RegisterTools(server *mcp.Server) {
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
+}
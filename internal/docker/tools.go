@@ -0,0 +1,465 @@
+package docker
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) api() (*client, error) {
+	if s.client == nil {
+		return nil, fmt.Errorf("%w: invalid docker.host %q", common.ErrInvalidInput, s.config.Host)
+	}
+	return s.client, nil
+}
+
+func shortID(id string) string {
+	id = strings.TrimPrefix(id, "sha256:")
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
+
+func (s *Server) listContainersTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_containers",
+		Description: "List Docker containers",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"all":         mcp.BoolProperty("Include stopped containers (default: false)"),
+				"filter_name": mcp.StringProperty("Filter by container name"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleListContainers),
+	}
+}
+
+type listContainersArgs struct {
+	All        bool   `json:"all"`
+	FilterName string `json:"filter_name"`
+}
+
+func (s *Server) handleListContainers(ctx context.Context, args listContainersArgs) (*mcp.ToolResult, error) {
+	all, filterName := args.All, args.FilterName
+	api, err := s.api()
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{"all": {strconv.FormatBool(all)}}
+	if filterName != "" {
+		query.Set("filters", filtersParam(map[string][]string{"name": {filterName}}))
+	}
+	var containers []struct {
+		ID      string   `json:"Id"`
+		Names   []string `json:"Names"`
+		Image   string   `json:"Image"`
+		State   string   `json:"State"`
+		Status  string   `json:"Status"`
+		Created int64    `json:"Created"`
+		Ports   []struct {
+			IP          string `json:"IP"`
+			PrivatePort int    `json:"PrivatePort"`
+			PublicPort  int    `json:"PublicPort"`
+			Type        string `json:"Type"`
+		} `json:"Ports"`
+		Labels map[string]string `json:"Labels"`
+	}
+	if err := api.getJSON(ctx, "/containers/json", query, &containers); err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(containers))
+	for _, c := range containers {
+		names := make([]string, len(c.Names))
+		for i, n := range c.Names {
+			names[i] = strings.TrimPrefix(n, "/")
+		}
+		var ports []string
+		for _, p := range c.Ports {
+			if p.PublicPort != 0 {
+				ports = append(ports, fmt.Sprintf("%s:%d->%d/%s", p.IP, p.PublicPort, p.PrivatePort, p.Type))
+			} else {
+				ports = append(ports, fmt.Sprintf("%d/%s", p.PrivatePort, p.Type))
+			}
+		}
+		result = append(result, map[string]interface{}{
+			"id":              shortID(c.ID),
+			"names":           names,
+			"image":           c.Image,
+			"state":           c.State,
+			"status":          c.Status,
+			"ports":           ports,
+			"created":         time.Unix(c.Created, 0).Format(time.RFC3339),
+			"compose_project": c.Labels["com.docker.compose.project"],
+		})
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"containers": result,
+		"count":      len(result),
+	})
+}
+
+func (s *Server) listImagesTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_images",
+		Description: "List Docker images",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"reference": mcp.StringProperty("Filter by reference, e.g. \"nginx\" or \"myapp:*\""),
+			},
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleListImages),
+	}
+}
+
+type listImagesArgs struct {
+	Reference string `json:"reference"`
+}
+
+func (s *Server) handleListImages(ctx context.Context, args listImagesArgs) (*mcp.ToolResult, error) {
+	reference := args.Reference
+	api, err := s.api()
+	if err != nil {
+		return nil, err
+	}
+
+	query := url.Values{}
+	if reference != "" {
+		query.Set("filters", filtersParam(map[string][]string{"reference": {reference}}))
+	}
+	var images []struct {
+		ID       string   `json:"Id"`
+		RepoTags []string `json:"RepoTags"`
+		Created  int64    `json:"Created"`
+		Size     int64    `json:"Size"`
+	}
+	if err := api.getJSON(ctx, "/images/json", query, &images); err != nil {
+		return nil, err
+	}
+
+	result := make([]map[string]interface{}, 0, len(images))
+	for _, img := range images {
+		result = append(result, map[string]interface{}{
+			"id":      shortID(img.ID),
+			"tags":    img.RepoTags,
+			"size_mb": float64(img.Size) / (1024 * 1024),
+			"created": time.Unix(img.Created, 0).Format(time.RFC3339),
+		})
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"images": result,
+		"count":  len(result),
+	})
+}
+
+func (s *Server) containerLogsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "container_logs",
+		Description: "Get the logs of a container",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"container":  mcp.StringProperty("Container name or ID"),
+				"tail":       mcp.IntProperty("Number of lines from the end (default: 100)"),
+				"since":      mcp.StringProperty("Only logs since this time: RFC 3339 timestamp or duration such as \"10m\""),
+				"timestamps": mcp.BoolProperty("Prefix each line with its timestamp"),
+			},
+			[]string{"container"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleContainerLogs),
+	}
+}
+
+type containerLogsArgs struct {
+	Container  string `json:"container" validate:"required"`
+	Tail       int    `json:"tail" default:"100"`
+	Since      string `json:"since"`
+	Timestamps bool   `json:"timestamps"`
+}
+
+func (s *Server) handleContainerLogs(ctx context.Context, args containerLogsArgs) (*mcp.ToolResult, error) {
+	container, tail, since, timestamps := args.Container, args.Tail, args.Since, args.Timestamps
+	api, err := s.api()
+	if err != nil {
+		return nil, err
+	}
+
+	if tail <= 0 || tail > s.config.MaxLogLines {
+		tail = s.config.MaxLogLines
+	}
+	query := url.Values{
+		"stdout":     {"true"},
+		"stderr":     {"true"},
+		"tail":       {strconv.Itoa(tail)},
+		"timestamps": {strconv.FormatBool(timestamps)},
+	}
+	if since != "" {
+		if d, err := time.ParseDuration(since); err == nil {
+			query.Set("since", strconv.FormatInt(time.Now().Add(-d).Unix(), 10))
+		} else if t, err := time.Parse(time.RFC3339, since); err == nil {
+			query.Set("since", strconv.FormatInt(t.Unix(), 10))
+		} else {
+			return nil, fmt.Errorf("%w: since must be a duration or RFC 3339 time", common.ErrInvalidInput)
+		}
+	}
+
+	// Containers with a TTY stream raw output; others multiplex stdout
+	// and stderr.
+	var info struct {
+		Config struct {
+			Tty bool `json:"Tty"`
+		} `json:"Config"`
+	}
+	path := "/containers/" + url.PathEscape(container)
+	if err := api.getJSON(ctx, path+"/json", nil, &info); err != nil {
+		return nil, err
+	}
+
+	resp, err := api.do(ctx, http.MethodGet, path+"/logs", query, nil, "")
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	out := &limitedBuffer{max: s.config.MaxOutputSizeBytes}
+	if info.Config.Tty {
+		_, err = out.ReadFrom(resp.Body)
+	} else {
+		err = demux(resp.Body, out, out)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading logs: %v", common.ErrOperationFailed, err)
+	}
+
+	return mcp.TextResult(out.String()), nil
+}
+
+func (s *Server) execTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "exec_in_container",
+		Description: "Run a command inside a running container (requires docker.allow_exec)",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"container":       mcp.StringProperty("Container name or ID"),
+				"command":         mcp.ArrayProperty("string", "Command and arguments, e.g. [\"ls\", \"-la\"]"),
+				"working_dir":     mcp.StringProperty("Working directory inside the container"),
+				"env":             mcp.MapProperty("Additional environment variables"),
+				"user":            mcp.StringProperty("User to run as"),
+				"timeout_seconds": mcp.IntProperty("Timeout in seconds"),
+			},
+			[]string{"container", "command"},
+		),
+		Annotations: mcp.Destructive(),
+		Handler:     mcp.TypedHandler(s.handleExec),
+	}
+}
+
+type execArgs struct {
+	Container      string            `json:"container" validate:"required"`
+	Command        []string          `json:"command" validate:"required"`
+	WorkingDir     string            `json:"working_dir"`
+	Env            map[string]string `json:"env"`
+	User           string            `json:"user"`
+	TimeoutSeconds int               `json:"timeout_seconds"`
+}
+
+func (s *Server) handleExec(ctx context.Context, args execArgs) (*mcp.ToolResult, error) {
+	if !s.config.AllowExec {
+		return nil, fmt.Errorf("%w: exec_in_container is disabled (set docker.allow_exec)", common.ErrPermissionDenied)
+	}
+	container, command, workingDir, env, user := args.Container, args.Command, args.WorkingDir, args.Env, args.User
+	if len(command) == 0 {
+		return nil, fmt.Errorf("%w: empty command", common.ErrInvalidInput)
+	}
+	timeout := args.TimeoutSeconds
+	if timeout <= 0 {
+		timeout = s.config.TimeoutSeconds
+	}
+	api, err := s.api()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeout)*time.Second)
+	defer cancel()
+
+	var envList []string
+	for k, v := range env {
+		if err := common.ValidateEnvVarName(k); err != nil {
+			return nil, err
+		}
+		envList = append(envList, k+"="+v)
+	}
+	var created struct {
+		ID string `json:"Id"`
+	}
+	path := "/containers/" + url.PathEscape(container) + "/exec"
+	if err := api.postJSON(ctx, path, map[string]interface{}{
+		"Cmd":          command,
+		"Env":          envList,
+		"WorkingDir":   workingDir,
+		"User":         user,
+		"AttachStdout": true,
+		"AttachStderr": true,
+	}, &created); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := api.do(ctx, http.MethodPost, "/exec/"+created.ID+"/start", nil,
+		strings.NewReader(`{"Detach":false,"Tty":false}`), "application/json")
+	if err != nil {
+		return nil, err
+	}
+	stdout := &limitedBuffer{max: s.config.MaxOutputSizeBytes}
+	stderr := &limitedBuffer{max: s.config.MaxOutputSizeBytes}
+	err = demux(resp.Body, stdout, stderr)
+	resp.Body.Close()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("%w: command did not finish within %ds", common.ErrTimeout, timeout)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: reading exec output: %v", common.ErrOperationFailed, err)
+	}
+
+	var inspect struct {
+		ExitCode int `json:"ExitCode"`
+	}
+	if err := api.getJSON(ctx, "/exec/"+created.ID+"/json", nil, &inspect); err != nil {
+		return nil, err
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"exit_code":   inspect.ExitCode,
+		"stdout":      stdout.String(),
+		"stderr":      stderr.String(),
+		"duration_ms": time.Since(start).Milliseconds(),
+	})
+}
+
+func (s *Server) composeUpTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "compose_up",
+		Description: "Start a Docker Compose project in the background (docker compose up -d)",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"project_dir": mcp.StringProperty("Directory containing the compose file"),
+				"services":    mcp.ArrayProperty("string", "Services to start (default: all)"),
+				"build":       mcp.BoolProperty("Build images before starting"),
+			},
+			[]string{"project_dir"},
+		),
+		Annotations: mcp.Additive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleComposeUp),
+	}
+}
+
+type composeUpArgs struct {
+	ProjectDir string   `json:"project_dir" validate:"required"`
+	Services   []string `json:"services"`
+	Build      bool     `json:"build"`
+}
+
+func (s *Server) handleComposeUp(ctx context.Context, args composeUpArgs) (*mcp.ToolResult, error) {
+	composeArgs := []string{"up", "--detach", "--wait"}
+	if args.Build {
+		composeArgs = append(composeArgs, "--build")
+	}
+	composeArgs = append(composeArgs, "--")
+	return s.compose(ctx, args.ProjectDir, append(composeArgs, args.Services...))
+}
+
+func (s *Server) composeDownTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "compose_down",
+		Description: "Stop and remove a Docker Compose project (docker compose down)",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"project_dir": mcp.StringProperty("Directory containing the compose file"),
+				"volumes":     mcp.BoolProperty("Also remove named volumes"),
+			},
+			[]string{"project_dir"},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleComposeDown),
+	}
+}
+
+type composeDownArgs struct {
+	ProjectDir string `json:"project_dir" validate:"required"`
+	Volumes    bool   `json:"volumes"`
+}
+
+func (s *Server) handleComposeDown(ctx context.Context, args composeDownArgs) (*mcp.ToolResult, error) {
+	composeArgs := []string{"down"}
+	if args.Volumes {
+		composeArgs = append(composeArgs, "--volumes")
+	}
+	return s.compose(ctx, args.ProjectDir, composeArgs)
+}
+
+// compose runs `docker compose` against the project in projectDir.
+// Compose has no Engine API, so this uses the CLI plugin, pointed at the
+// configured host.
+func (s *Server) compose(ctx context.Context, projectDir string, args []string) (*mcp.ToolResult, error) {
+	dir, err := s.validator.ResolvePath(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.config.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "docker", append([]string{"compose", "--project-directory", dir}, args...)...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "DOCKER_HOST="+s.config.Host)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("%w: docker compose %s did not finish within %ds", common.ErrTimeout, args[0], s.config.TimeoutSeconds)
+	}
+	output := truncate(strings.TrimSpace(stdout.String()+"\n"+stderr.String()), s.config.MaxOutputSizeBytes)
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); !ok {
+			return nil, fmt.Errorf("%w: running docker compose: %v", common.ErrOperationFailed, err)
+		}
+		return nil, common.WithDetails(
+			fmt.Errorf("%w: docker compose %s: %s", common.ErrOperationFailed, args[0], output),
+			"project_dir", dir)
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"project_dir": dir,
+		"output":      output,
+		"duration_ms": time.Since(start).Milliseconds(),
+	})
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max] + "\n... (truncated)"
+	}
+	return s
+}
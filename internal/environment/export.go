@@ -0,0 +1,134 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) exportEnvTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "export_env",
+		Description: "Export session environment variables as a .env file or shell syntax. Variables with sensitive names are left out",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"format":    mcp.StringProperty("Output format: dotenv, shell, powershell (default: dotenv)"),
+				"path":      mcp.StringProperty("Write the output to this file instead of returning it; it must be in environment.allowed_export_paths"),
+				"overwrite": mcp.BoolProperty("Overwrite the file if it already exists"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleExportEnv),
+	}
+}
+
+type exportEnvArgs struct {
+	Format    string `json:"format" default:"dotenv"`
+	Path      string `json:"path"`
+	Overwrite bool   `json:"overwrite"`
+}
+
+func (s *Server) handleExportEnv(ctx context.Context, args exportEnvArgs) (*mcp.ToolResult, error) {
+	format, path, overwrite := args.Format, args.Path, args.Overwrite
+
+	env := s.env(ctx).All()
+	filteredCount := 0
+	for name := range env {
+		if s.isSensitive(name) {
+			delete(env, name)
+			filteredCount++
+		}
+	}
+	content, err := formatEnv(env, format)
+	if err != nil {
+		return nil, err
+	}
+
+	if path == "" {
+		return mcp.TextResult(content), nil
+	}
+
+	if len(s.config.AllowedExportPaths) == 0 {
+		return nil, fmt.Errorf("%w: writing files is disabled; set environment.allowed_export_paths", common.ErrPermissionDenied)
+	}
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.exportValidator.ValidatePath(absPath); err != nil {
+		return nil, err
+	}
+
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !overwrite {
+		flags |= os.O_EXCL
+	}
+
+	file, err := os.OpenFile(absPath, flags, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return nil, fmt.Errorf("%w: %s (set overwrite to replace it)", common.ErrAlreadyExists, absPath)
+		}
+		return nil, err
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(content); err != nil {
+		return nil, err
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"path":           absPath,
+		"format":         format,
+		"count":          len(env),
+		"filtered_count": filteredCount,
+		"exported":       true,
+	})
+}
+
+func formatEnv(env map[string]string, format string) (string, error) {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		value := env[name]
+		switch format {
+		case "dotenv":
+			fmt.Fprintf(&b, "%s=%s\n", name, quoteDotenv(value))
+		case "shell", "bash", "sh":
+			fmt.Fprintf(&b, "export %s=%s\n", name, quoteShell(value))
+		case "powershell", "pwsh":
+			fmt.Fprintf(&b, "$env:%s = %s\n", name, quotePowerShell(value))
+		default:
+			return "", fmt.Errorf("%w: unsupported format: %s (must be dotenv, shell, or powershell)", common.ErrInvalidInput, format)
+		}
+	}
+	return b.String(), nil
+}
+
+func quoteDotenv(value string) string {
+	if value != "" && !strings.ContainsAny(value, " \t\n\r\"'\\#$=") {
+		return value
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, `$`, `\$`)
+	return `"` + r.Replace(value) + `"`
+}
+
+func quoteShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func quotePowerShell(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
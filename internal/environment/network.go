@@ -0,0 +1,183 @@
+package environment
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"net"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type NetworkInterface struct {
+	Name         string   `json:"name"`
+	Index        int      `json:"index"`
+	MTU          int      `json:"mtu"`
+	HardwareAddr string   `json:"hardware_addr,omitempty"`
+	Flags        []string `json:"flags"`
+	IsUp         bool     `json:"is_up"`
+	IsLoopback   bool     `json:"is_loopback"`
+	IPv4         []string `json:"ipv4"`
+	IPv6         []string `json:"ipv6"`
+}
+
+func (s *Server) getNetworkInfoTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "get_network_info",
+		Description: "List network interfaces, IP addresses, default gateway and primary outbound IP",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"include_loopback": mcp.BoolProperty("Include loopback interfaces (default: true)"),
+				"include_down":     mcp.BoolProperty("Include interfaces that are down"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGetNetworkInfo),
+	}
+}
+
+type networkInfoArgs struct {
+	IncludeLoopback bool `json:"include_loopback" default:"true"`
+	IncludeDown     bool `json:"include_down"`
+}
+
+func (s *Server) handleGetNetworkInfo(ctx context.Context, args networkInfoArgs) (*mcp.ToolResult, error) {
+	includeLoopback, includeDown := args.IncludeLoopback, args.IncludeDown
+
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return nil, err
+	}
+
+	interfaces := []NetworkInterface{}
+	for _, iface := range ifaces {
+		isUp := iface.Flags&net.FlagUp != 0
+		isLoopback := iface.Flags&net.FlagLoopback != 0
+		if (!includeLoopback && isLoopback) || (!includeDown && !isUp) {
+			continue
+		}
+
+		entry := NetworkInterface{
+			Name:         iface.Name,
+			Index:        iface.Index,
+			MTU:          iface.MTU,
+			HardwareAddr: iface.HardwareAddr.String(),
+			Flags:        strings.Split(iface.Flags.String(), "|"),
+			IsUp:         isUp,
+			IsLoopback:   isLoopback,
+			IPv4:         []string{},
+			IPv6:         []string{},
+		}
+
+		addrs, _ := iface.Addrs()
+		for _, addr := range addrs {
+			ipNet, ok := addr.(*net.IPNet)
+			if !ok {
+				continue
+			}
+			if ipNet.IP.To4() != nil {
+				entry.IPv4 = append(entry.IPv4, ipNet.String())
+			} else {
+				entry.IPv6 = append(entry.IPv6, ipNet.String())
+			}
+		}
+
+		interfaces = append(interfaces, entry)
+	}
+
+	result := map[string]interface{}{
+		"interfaces": interfaces,
+	}
+
+	if gateway, iface := defaultGateway(); gateway != "" {
+		result["default_gateway"] = gateway
+		result["default_interface"] = iface
+	}
+	if ip := outboundIP("udp4", "8.8.8.8:80"); ip != "" {
+		result["primary_ipv4"] = ip
+	}
+	if ip := outboundIP("udp6", "[2001:4860:4860::8888]:80"); ip != "" {
+		result["primary_ipv6"] = ip
+	}
+
+	return mcp.JSONResult(result)
+}
+
+// outboundIP reports the local address the kernel would route through to
+// reach target. Dialing UDP does not send any packets.
+func outboundIP(network, target string) string {
+	conn, err := net.Dial(network, target)
+	if err != nil {
+		return ""
+	}
+	defer conn.Close()
+
+	if addr, ok := conn.LocalAddr().(*net.UDPAddr); ok {
+		return addr.IP.String()
+	}
+	return ""
+}
+
+func defaultGateway() (string, string) {
+	switch runtime.GOOS {
+	case "linux":
+		return linuxDefaultGateway()
+	case "darwin", "freebsd", "openbsd", "netbsd":
+		return bsdDefaultGateway()
+	default:
+		return "", ""
+	}
+}
+
+func linuxDefaultGateway() (string, string) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return "", ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Scan()
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 3 || fields[1] != "00000000" {
+			continue
+		}
+		raw, err := hex.DecodeString(fields[2])
+		if err != nil || len(raw) != 4 {
+			continue
+		}
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, binary.LittleEndian.Uint32(raw))
+		return ip.String(), fields[0]
+	}
+	return "", ""
+}
+
+func bsdDefaultGateway() (string, string) {
+	output, err := exec.Command("route", "-n", "get", "default").Output()
+	if err != nil {
+		return "", ""
+	}
+
+	var gateway, iface string
+	for _, line := range strings.Split(string(output), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "gateway":
+			gateway = strings.TrimSpace(value)
+		case "interface":
+			iface = strings.TrimSpace(value)
+		}
+	}
+	return gateway, iface
+}
@@ -0,0 +1,98 @@
+package environment
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) loadSession() {
+	if !s.config.PersistSession || s.config.SessionFile == "" {
+		return
+	}
+
+	data, err := os.ReadFile(s.config.SessionFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.logger.Warnf("failed to read session file: %v", err)
+		}
+		return
+	}
+
+	var env map[string]string
+	if err := json.Unmarshal(data, &env); err != nil {
+		s.logger.Warnf("ignoring corrupt session file %s: %v", s.config.SessionFile, err)
+		return
+	}
+
+	for name, value := range env {
+		s.sessionEnv.Set(name, value)
+	}
+}
+
+// saveSession persists the session environment. Only the stdio client's
+// is persisted; those of HTTP clients end with their sessions.
+func (s *Server) saveSession(ctx context.Context) error {
+	if !s.config.PersistSession || s.config.SessionFile == "" || mcp.SessionFromContext(ctx) != nil {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.config.SessionFile), 0700); err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(s.sessionEnv.All(), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.config.SessionFile), ".session-env-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), s.config.SessionFile)
+}
+
+func (s *Server) clearSessionEnvTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "clear_session_env",
+		Description: "Remove all session environment variables (including persisted ones)",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{},
+			[]string{},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     s.handleClearSessionEnv,
+	}
+}
+
+func (s *Server) handleClearSessionEnv(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
+	cleared := s.env(ctx).Clear()
+
+	// Like saveSession, leave the stdio client's persisted environment
+	// alone when an HTTP client clears its own.
+	persisted := s.config.PersistSession && mcp.SessionFromContext(ctx) == nil
+	if persisted && s.config.SessionFile != "" {
+		if err := os.Remove(s.config.SessionFile); err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"cleared":   cleared,
+		"persisted": persisted,
+	})
+}
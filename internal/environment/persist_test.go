@@ -0,0 +1,52 @@
+package environment
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClearSessionEnvOverHTTPKeepsSessionFile(t *testing.T) {
+	cfg := config.DefaultConfig().Environment
+	cfg.PersistSession = true
+	cfg.SessionFile = filepath.Join(t.TempDir(), "session-env.json")
+	require.NoError(t, os.WriteFile(cfg.SessionFile, []byte(`{"FOO":"bar"}`), 0600))
+
+	server := mcp.NewServer("environment", "test")
+	NewServer(&cfg).RegisterTools(server)
+	ts := httptest.NewServer(server.HTTPHandler())
+	defer ts.Close()
+
+	session := ""
+	post := func(body string) (*http.Response, string) {
+		req, err := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if session != "" {
+			req.Header.Set("Mcp-Session-Id", session)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp, string(data)
+	}
+
+	resp, _ := post(`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`)
+	session = resp.Header.Get("Mcp-Session-Id")
+	require.NotEmpty(t, session)
+
+	_, out := post(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"clear_session_env"}}`)
+	assert.Contains(t, out, `\"persisted\": false`)
+	assert.FileExists(t, cfg.SessionFile)
+}
@@ -0,0 +1,336 @@
+package environment
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type RuntimeEnv struct {
+	Kind        string `json:"kind"`
+	Name        string `json:"name,omitempty"`
+	Source      string `json:"source"`
+	Path        string `json:"path,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Interpreter string `json:"interpreter,omitempty"`
+	Active      bool   `json:"active"`
+}
+
+func (s *Server) detectRuntimeEnvsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "detect_runtime_envs",
+		Description: "Detect active and project-local virtualenv, conda, nvm and version-manager environments",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"directory": mcp.StringProperty("Project directory to inspect (default: current directory)"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleDetectRuntimeEnvs),
+	}
+}
+
+type detectRuntimeEnvsArgs struct {
+	Directory string `json:"directory"`
+}
+
+func (s *Server) handleDetectRuntimeEnvs(ctx context.Context, args detectRuntimeEnvsArgs) (*mcp.ToolResult, error) {
+	directory := args.Directory
+	if directory == "" {
+		directory, _ = os.Getwd()
+	}
+
+	absDir, err := filepath.Abs(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	envs := s.activeRuntimeEnvs(ctx)
+	envs = append(envs, s.projectRuntimeEnvs(ctx, absDir)...)
+
+	return mcp.JSONResult(map[string]interface{}{
+		"directory":    absDir,
+		"environments": envs,
+		"count":        len(envs),
+	})
+}
+
+func (s *Server) lookupEnv(ctx context.Context, name string) (string, bool) {
+	if value, ok := s.env(ctx).Get(name); ok {
+		return value, true
+	}
+	return os.LookupEnv(name)
+}
+
+func (s *Server) activeRuntimeEnvs(ctx context.Context) []RuntimeEnv {
+	envs := []RuntimeEnv{}
+
+	if venv, ok := s.lookupEnv(ctx, "VIRTUAL_ENV"); ok && venv != "" {
+		envs = append(envs, RuntimeEnv{
+			Kind:        "virtualenv",
+			Name:        filepath.Base(venv),
+			Source:      "VIRTUAL_ENV",
+			Path:        venv,
+			Interpreter: venvInterpreter(venv),
+			Active:      true,
+		})
+	}
+
+	if prefix, ok := s.lookupEnv(ctx, "CONDA_PREFIX"); ok && prefix != "" {
+		name, _ := s.lookupEnv(ctx, "CONDA_DEFAULT_ENV")
+		envs = append(envs, RuntimeEnv{
+			Kind:        "conda",
+			Name:        name,
+			Source:      "CONDA_PREFIX",
+			Path:        prefix,
+			Interpreter: venvInterpreter(prefix),
+			Active:      true,
+		})
+	}
+
+	if nvmBin, ok := s.lookupEnv(ctx, "NVM_BIN"); ok && nvmBin != "" {
+		envs = append(envs, RuntimeEnv{
+			Kind:        "nvm",
+			Source:      "NVM_BIN",
+			Path:        filepath.Dir(nvmBin),
+			Version:     filepath.Base(filepath.Dir(nvmBin)),
+			Interpreter: filepath.Join(nvmBin, "node"),
+			Active:      true,
+		})
+	}
+
+	return envs
+}
+
+func (s *Server) projectRuntimeEnvs(ctx context.Context, dir string) []RuntimeEnv {
+	var envs []RuntimeEnv
+	homeDir, _ := os.UserHomeDir()
+
+	for current := dir; ; current = filepath.Dir(current) {
+		for _, name := range []string{".venv", "venv", "env"} {
+			venv := filepath.Join(current, name)
+			if _, err := os.Stat(filepath.Join(venv, "pyvenv.cfg")); err == nil {
+				envs = append(envs, RuntimeEnv{
+					Kind:        "virtualenv",
+					Name:        name,
+					Source:      filepath.Join(venv, "pyvenv.cfg"),
+					Path:        venv,
+					Version:     readPyvenvVersion(filepath.Join(venv, "pyvenv.cfg")),
+					Interpreter: venvInterpreter(venv),
+				})
+			}
+		}
+
+		for _, name := range []string{"environment.yml", "environment.yaml"} {
+			file := filepath.Join(current, name)
+			if envName := readCondaEnvName(file); envName != "" {
+				envs = append(envs, RuntimeEnv{
+					Kind:        "conda",
+					Name:        envName,
+					Source:      file,
+					Interpreter: s.condaInterpreter(ctx, envName),
+				})
+			}
+		}
+
+		for _, name := range []string{".nvmrc", ".node-version"} {
+			file := filepath.Join(current, name)
+			if version := readFirstLine(file); version != "" {
+				envs = append(envs, RuntimeEnv{
+					Kind:        "node",
+					Source:      file,
+					Version:     version,
+					Interpreter: s.nvmInterpreter(ctx, version),
+				})
+			}
+		}
+
+		if version := readFirstLine(filepath.Join(current, ".python-version")); version != "" {
+			envs = append(envs, RuntimeEnv{
+				Kind:        "pyenv",
+				Source:      filepath.Join(current, ".python-version"),
+				Version:     version,
+				Interpreter: s.pyenvInterpreter(ctx, version),
+			})
+		}
+
+		envs = append(envs, s.readToolVersions(ctx, filepath.Join(current, ".tool-versions"))...)
+
+		if len(envs) > 0 || current == homeDir || filepath.Dir(current) == current {
+			break
+		}
+	}
+
+	return envs
+}
+
+func venvInterpreter(prefix string) string {
+	candidates := []string{filepath.Join(prefix, "bin", "python"), filepath.Join(prefix, "bin", "python3")}
+	if runtime.GOOS == "windows" {
+		candidates = []string{filepath.Join(prefix, "Scripts", "python.exe"), filepath.Join(prefix, "python.exe")}
+	}
+	for _, c := range candidates {
+		if _, err := os.Stat(c); err == nil {
+			return c
+		}
+	}
+	return ""
+}
+
+func (s *Server) condaInterpreter(ctx context.Context, envName string) string {
+	var roots []string
+	if exe, ok := s.lookupEnv(ctx, "CONDA_EXE"); ok && exe != "" {
+		roots = append(roots, filepath.Dir(filepath.Dir(exe)))
+	}
+	homeDir, _ := os.UserHomeDir()
+	for _, name := range []string{"miniconda3", "miniconda", "anaconda3", "miniforge3", "mambaforge"} {
+		roots = append(roots, filepath.Join(homeDir, name))
+	}
+	for _, root := range roots {
+		if interp := venvInterpreter(filepath.Join(root, "envs", envName)); interp != "" {
+			return interp
+		}
+	}
+	return ""
+}
+
+func (s *Server) nvmInterpreter(ctx context.Context, version string) string {
+	nvmDir, ok := s.lookupEnv(ctx, "NVM_DIR")
+	if !ok || nvmDir == "" {
+		homeDir, _ := os.UserHomeDir()
+		nvmDir = filepath.Join(homeDir, ".nvm")
+	}
+
+	version = strings.TrimPrefix(version, "v")
+	entries, err := os.ReadDir(filepath.Join(nvmDir, "versions", "node"))
+	if err != nil {
+		return ""
+	}
+
+	best := ""
+	for _, entry := range entries {
+		installed := strings.TrimPrefix(entry.Name(), "v")
+		if installed == version || strings.HasPrefix(installed, version+".") {
+			if best == "" || compareVersions(installed, strings.TrimPrefix(best, "v")) > 0 {
+				best = entry.Name()
+			}
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return filepath.Join(nvmDir, "versions", "node", best, "bin", "node")
+}
+
+func (s *Server) pyenvInterpreter(ctx context.Context, version string) string {
+	root, ok := s.lookupEnv(ctx, "PYENV_ROOT")
+	if !ok || root == "" {
+		homeDir, _ := os.UserHomeDir()
+		root = filepath.Join(homeDir, ".pyenv")
+	}
+	return venvInterpreter(filepath.Join(root, "versions", version))
+}
+
+func (s *Server) readToolVersions(ctx context.Context, file string) []RuntimeEnv {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var envs []RuntimeEnv
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+
+		env := RuntimeEnv{
+			Kind:    fields[0],
+			Source:  file,
+			Version: fields[1],
+		}
+		switch fields[0] {
+		case "python":
+			env.Interpreter = s.pyenvInterpreter(ctx, fields[1])
+		case "nodejs", "node":
+			env.Interpreter = s.nvmInterpreter(ctx, fields[1])
+		}
+		envs = append(envs, env)
+	}
+	return envs
+}
+
+func readPyvenvVersion(file string) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), "=")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		if key == "version" || key == "version_info" {
+			return strings.TrimSpace(value)
+		}
+	}
+	return ""
+}
+
+func readCondaEnvName(file string) string {
+	f, err := os.Open(file)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if name, ok := strings.CutPrefix(scanner.Text(), "name:"); ok {
+			return strings.Trim(strings.TrimSpace(name), `"'`)
+		}
+	}
+	return ""
+}
+
+func readFirstLine(file string) string {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	line, _, _ := strings.Cut(string(data), "\n")
+	return strings.TrimSpace(line)
+}
+
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x > y {
+				return 1
+			}
+			return -1
+		}
+	}
+	return 0
+}
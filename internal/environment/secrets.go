@@ -0,0 +1,228 @@
+package environment
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"path"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type secretBackend interface {
+	Name() string
+	Get(ctx context.Context, name string) (string, error)
+}
+
+func newSecretBackend(cfg *config.SecretsConfig) (secretBackend, error) {
+	switch cfg.Backend {
+	case "", "keychain":
+		return &keychainBackend{service: cfg.Service}, nil
+	case "vault":
+		addr := cfg.VaultAddress
+		if addr == "" {
+			addr = os.Getenv("VAULT_ADDR")
+		}
+		if addr == "" {
+			return nil, fmt.Errorf("%w: vault backend requires vault_address or VAULT_ADDR", common.ErrInvalidInput)
+		}
+		tokenEnv := cfg.VaultTokenEnv
+		if tokenEnv == "" {
+			tokenEnv = "VAULT_TOKEN"
+		}
+		return &vaultBackend{
+			address:  strings.TrimRight(addr, "/"),
+			tokenEnv: tokenEnv,
+			client:   &http.Client{Timeout: 10 * time.Second},
+		}, nil
+	default:
+		return nil, fmt.Errorf("%w: unknown secrets backend: %s", common.ErrInvalidInput, cfg.Backend)
+	}
+}
+
+type keychainBackend struct {
+	service string
+}
+
+func (b *keychainBackend) Name() string {
+	return "keychain"
+}
+
+func (b *keychainBackend) Get(ctx context.Context, name string) (string, error) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "security", "find-generic-password", "-s", b.service, "-a", name, "-w")
+	case "windows":
+		cmd = exec.CommandContext(ctx, "powershell.exe", "-NoProfile", "-NonInteractive", "-Command", windowsCredReadScript)
+		cmd.Env = append(os.Environ(), "MCP_CRED_TARGET="+b.service+":"+name)
+	default:
+		cmd = exec.CommandContext(ctx, "secret-tool", "lookup", "service", b.service, "account", name)
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("%w: secret %s", common.ErrNotFound, name)
+		}
+		return "", fmt.Errorf("keychain lookup failed: %v: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+const windowsCredReadScript = `
+$sig = @'
+[DllImport("advapi32.dll", SetLastError=true, CharSet=CharSet.Unicode)]
+public static extern bool CredRead(string target, int type, int flags, out IntPtr cred);
+[DllImport("advapi32.dll")]
+public static extern void CredFree(IntPtr cred);
+[StructLayout(LayoutKind.Sequential, CharSet=CharSet.Unicode)]
+public struct CREDENTIAL { public int Flags; public int Type; public string TargetName; public string Comment;
+  public long LastWritten; public int CredentialBlobSize; public IntPtr CredentialBlob; public int Persist;
+  public int AttributeCount; public IntPtr Attributes; public string TargetAlias; public string UserName; }
+'@
+Add-Type -MemberDefinition $sig -Name Cred -Namespace Mcp | Out-Null
+$p = [IntPtr]::Zero
+if (-not [Mcp.Cred]::CredRead($env:MCP_CRED_TARGET, 1, 0, [ref]$p)) { exit 1 }
+$c = [Runtime.InteropServices.Marshal]::PtrToStructure($p, [type][Mcp.Cred+CREDENTIAL])
+[Console]::Out.Write([Runtime.InteropServices.Marshal]::PtrToStringUni($c.CredentialBlob, $c.CredentialBlobSize / 2))
+[Mcp.Cred]::CredFree($p)
+`
+
+type vaultBackend struct {
+	address  string
+	tokenEnv string
+	client   *http.Client
+}
+
+func (b *vaultBackend) Name() string {
+	return "vault"
+}
+
+func (b *vaultBackend) Get(ctx context.Context, name string) (string, error) {
+	secretPath, field, ok := strings.Cut(name, "#")
+	if !ok || secretPath == "" || field == "" {
+		return "", fmt.Errorf("%w: vault secret names must be path#field", common.ErrInvalidInput)
+	}
+
+	token := os.Getenv(b.tokenEnv)
+	if token == "" {
+		return "", fmt.Errorf("%w: vault token not set in %s", common.ErrPermissionDenied, b.tokenEnv)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", b.address+"/v1/"+strings.TrimLeft(secretPath, "/"), nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return "", fmt.Errorf("%w: secret %s", common.ErrNotFound, secretPath)
+	case http.StatusForbidden, http.StatusUnauthorized:
+		return "", fmt.Errorf("%w: vault rejected token for %s", common.ErrPermissionDenied, secretPath)
+	default:
+		return "", fmt.Errorf("vault returned status %d for %s", resp.StatusCode, secretPath)
+	}
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("failed to parse vault response: %w", err)
+	}
+
+	data := body.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested
+	}
+
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("%w: field %s in %s", common.ErrNotFound, field, secretPath)
+	}
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	return fmt.Sprint(value), nil
+}
+
+func (s *Server) getSecretTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "get_secret",
+		Description: "Read an allowlisted secret from the configured keychain or Vault backend",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"name": mcp.StringProperty("Secret name (Vault: path#field)"),
+			},
+			[]string{"name"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGetSecret),
+	}
+}
+
+type getSecretArgs struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func (s *Server) handleGetSecret(ctx context.Context, args getSecretArgs) (*mcp.ToolResult, error) {
+	name := args.Name
+
+	if !s.isSecretAllowed(name) {
+		return nil, fmt.Errorf("%w: secret %s is not in allowed_secrets", common.ErrPermissionDenied, name)
+	}
+
+	backend, err := newSecretBackend(&s.config.Secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := backend.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.WithFields(map[string]interface{}{
+		"secret":  name,
+		"backend": backend.Name(),
+	}).Info("secret read")
+
+	return mcp.JSONResult(map[string]interface{}{
+		"name":    name,
+		"backend": backend.Name(),
+		"value":   value,
+	})
+}
+
+func (s *Server) isSecretAllowed(name string) bool {
+	for _, allowed := range s.config.Secrets.AllowedSecrets {
+		if allowed == name {
+			return true
+		}
+		if matched, _ := path.Match(allowed, name); matched {
+			return true
+		}
+	}
+	return false
+}
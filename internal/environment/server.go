@@ -1,6 +1,8 @@
 package environment
 
 import (
+	"context"
+
 	"github.com/local-mcps/dev-mcps/config"
 	"github.com/local-mcps/dev-mcps/internal/common"
 	"github.com/local-mcps/dev-mcps/pkg/mcp"
@@ -9,24 +11,74 @@ import (
 type Server struct {
 	config     *config.EnvironmentConfig
 	logger     *common.Logger
-	sessionEnv map[string]string
+	sessionEnv *common.SessionEnv
+	// snapshots are the stdio client's; HTTP clients keep theirs in their
+	// session.
+	snapshots *envSnapshots
+
+	// exportValidator limits the files export_env writes.
+	exportValidator *common.PathValidator
 }
 
 func NewServer(cfg *config.EnvironmentConfig) *Server {
-	return &Server{
+	s := &Server{
 		config:     cfg,
-		logger:     common.NewLogger(common.LogLevelInfo, common.LogFormatJSON, nil, "environment"),
-		sessionEnv: make(map[string]string),
+		logger:     common.NewServerLogger("environment"),
+		sessionEnv: common.DefaultSessionEnv(),
+		snapshots:  newEnvSnapshots(),
+	}
+	s.Reload()
+	s.loadSession()
+	common.RegisterConfigSection("environment", s.configSection)
+	return s
+}
+
+func (s *Server) Reload() {
+	s.exportValidator = common.NewPathValidator(s.config.AllowedExportPaths, nil, false)
+}
+
+// env returns the session environment of the client calling a tool.
+func (s *Server) env(ctx context.Context) *common.SessionEnv {
+	return common.SessionEnvFor(ctx, s.sessionEnv)
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		s.getEnvTool(),
+		s.getEnvsTool(),
+		s.setEnvTool(),
+		s.listEnvTool(),
+		s.unsetEnvTool(),
+		s.getSystemInfoTool(),
+		s.getUserInfoTool(),
+		s.getPathInfoTool(),
+		s.expandPathTool(),
+		s.exportEnvTool(),
+		s.clearSessionEnvTool(),
+		s.snapshotEnvTool(),
+		s.restoreEnvTool(),
+		s.listEnvSnapshotsTool(),
+		s.whichTool(),
+		s.detectToolchainsTool(),
+		s.detectRuntimeEnvsTool(),
+		s.getNetworkInfoTool(),
+		s.detectVirtualizationTool(),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
 	}
+
+	if s.config.Secrets.Enabled {
+		tools = append(tools, s.getSecretTool())
+	}
+	return common.Instrument("environment", tools)
 }
 
 func (s *Server) RegisterTools(server *mcp.Server) {
-	server.RegisterTool(s.getEnvTool())
-	server.RegisterTool(s.setEnvTool())
-	server.RegisterTool(s.listEnvTool())
-	server.RegisterTool(s.unsetEnvTool())
-	server.RegisterTool(s.getSystemInfoTool())
-	server.RegisterTool(s.getUserInfoTool())
-	server.RegisterTool(s.getPathInfoTool())
-	server.RegisterTool(s.expandPathTool())
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
 }
@@ -0,0 +1,203 @@
+package environment
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type envSnapshot struct {
+	Name      string
+	CreatedAt time.Time
+	Env       map[string]string
+}
+
+// envSnapshots are one client's snapshots. Tool calls run concurrently, so
+// access is locked.
+type envSnapshots struct {
+	mu     sync.Mutex
+	seq    int
+	byName map[string]*envSnapshot
+}
+
+func newEnvSnapshots() *envSnapshots {
+	return &envSnapshots{byName: make(map[string]*envSnapshot)}
+}
+
+type envSnapshotsKey struct{}
+
+// snapshotsFor returns the snapshots of the client whose tool call runs
+// under ctx, so that HTTP clients cannot see or restore each other's.
+func (s *Server) snapshotsFor(ctx context.Context) *envSnapshots {
+	sess := mcp.SessionFromContext(ctx)
+	if sess == nil {
+		return s.snapshots
+	}
+	return sess.Value(envSnapshotsKey{}, func() interface{} { return newEnvSnapshots() }).(*envSnapshots)
+}
+
+// save stores env under name, or a generated name if it is empty, and
+// returns the name and whether it replaced an earlier snapshot.
+func (ss *envSnapshots) save(name string, env map[string]string) (string, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	if name == "" {
+		ss.seq++
+		name = fmt.Sprintf("snapshot-%d", ss.seq)
+	}
+	_, replaced := ss.byName[name]
+	ss.byName[name] = &envSnapshot{Name: name, CreatedAt: time.Now(), Env: env}
+	return name, replaced
+}
+
+// get returns the snapshot called name, removing it if remove is set.
+func (ss *envSnapshots) get(name string, remove bool) (*envSnapshot, bool) {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	snap, ok := ss.byName[name]
+	if ok && remove {
+		delete(ss.byName, name)
+	}
+	return snap, ok
+}
+
+// list returns the snapshots oldest first.
+func (ss *envSnapshots) list() []*envSnapshot {
+	ss.mu.Lock()
+	defer ss.mu.Unlock()
+	ordered := make([]*envSnapshot, 0, len(ss.byName))
+	for _, snap := range ss.byName {
+		ordered = append(ordered, snap)
+	}
+	sort.Slice(ordered, func(i, j int) bool {
+		return ordered[i].CreatedAt.Before(ordered[j].CreatedAt)
+	})
+	return ordered
+}
+
+func (s *Server) snapshotEnvTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "snapshot_env",
+		Description: "Capture the current session environment so it can be restored later",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"name": mcp.StringProperty("Snapshot name (default: generated)"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleSnapshotEnv),
+	}
+}
+
+type snapshotEnvArgs struct {
+	Name string `json:"name"`
+}
+
+func (s *Server) handleSnapshotEnv(ctx context.Context, args snapshotEnvArgs) (*mcp.ToolResult, error) {
+	env := s.env(ctx).All()
+	name, replaced := s.snapshotsFor(ctx).save(args.Name, env)
+
+	return mcp.JSONResult(map[string]interface{}{
+		"name":     name,
+		"count":    len(env),
+		"replaced": replaced,
+	})
+}
+
+func (s *Server) restoreEnvTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "restore_env",
+		Description: "Restore the session environment from a snapshot",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"name":   mcp.StringProperty("Snapshot name"),
+				"delete": mcp.BoolProperty("Delete the snapshot after restoring"),
+			},
+			[]string{"name"},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleRestoreEnv),
+	}
+}
+
+type restoreEnvArgs struct {
+	Name   string `json:"name" validate:"required"`
+	Delete bool   `json:"delete"`
+}
+
+func (s *Server) handleRestoreEnv(ctx context.Context, args restoreEnvArgs) (*mcp.ToolResult, error) {
+	name, deleteAfter := args.Name, args.Delete
+
+	snapshot, ok := s.snapshotsFor(ctx).get(name, deleteAfter)
+	if !ok {
+		return nil, fmt.Errorf("%w: snapshot %s", common.ErrNotFound, name)
+	}
+
+	current := s.env(ctx).Replace(snapshot.Env)
+
+	added, removed, changed := []string{}, []string{}, []string{}
+	for k, v := range snapshot.Env {
+		if value, ok := current[k]; !ok {
+			added = append(added, k)
+		} else if value != v {
+			changed = append(changed, k)
+		}
+	}
+	for k := range current {
+		if _, ok := snapshot.Env[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	sort.Strings(changed)
+
+	if err := s.saveSession(ctx); err != nil {
+		return nil, err
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"name":     name,
+		"restored": true,
+		"added":    added,
+		"removed":  removed,
+		"changed":  changed,
+	})
+}
+
+func (s *Server) listEnvSnapshotsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_env_snapshots",
+		Description: "List saved session environment snapshots",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{},
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     s.handleListEnvSnapshots,
+	}
+}
+
+func (s *Server) handleListEnvSnapshots(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
+	ordered := s.snapshotsFor(ctx).list()
+
+	snapshots := make([]map[string]interface{}, 0, len(ordered))
+	for _, snap := range ordered {
+		snapshots = append(snapshots, map[string]interface{}{
+			"name":       snap.Name,
+			"created_at": snap.CreatedAt.Format(time.RFC3339),
+			"count":      len(snap.Env),
+		})
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"snapshots":   snapshots,
+		"total_count": len(snapshots),
+	})
+}
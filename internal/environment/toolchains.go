@@ -0,0 +1,143 @@
+package environment
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type toolchainProbe struct {
+	Name        string
+	Executables []string
+	VersionArgs []string
+}
+
+var toolchainProbes = []toolchainProbe{
+	{Name: "go", Executables: []string{"go"}, VersionArgs: []string{"version"}},
+	{Name: "node", Executables: []string{"node"}, VersionArgs: []string{"--version"}},
+	{Name: "npm", Executables: []string{"npm"}, VersionArgs: []string{"--version"}},
+	{Name: "python", Executables: []string{"python3", "python"}, VersionArgs: []string{"--version"}},
+	{Name: "pip", Executables: []string{"pip3", "pip"}, VersionArgs: []string{"--version"}},
+	{Name: "java", Executables: []string{"java"}, VersionArgs: []string{"-version"}},
+	{Name: "docker", Executables: []string{"docker"}, VersionArgs: []string{"--version"}},
+	{Name: "rustc", Executables: []string{"rustc"}, VersionArgs: []string{"--version"}},
+	{Name: "cargo", Executables: []string{"cargo"}, VersionArgs: []string{"--version"}},
+	{Name: "gcc", Executables: []string{"gcc"}, VersionArgs: []string{"--version"}},
+	{Name: "make", Executables: []string{"make"}, VersionArgs: []string{"--version"}},
+}
+
+var versionRegex = regexp.MustCompile(`\d+\.\d+(\.\d+)?([-+.][0-9A-Za-z.]+)?`)
+
+type ToolchainInfo struct {
+	Name       string `json:"name"`
+	Found      bool   `json:"found"`
+	Path       string `json:"path,omitempty"`
+	Version    string `json:"version,omitempty"`
+	RawVersion string `json:"raw_version,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+func (s *Server) detectToolchainsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "detect_toolchains",
+		Description: "Detect installed developer toolchains and their versions",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"tools": mcp.ArrayProperty("string", "Limit detection to these tools (default: all known)"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleDetectToolchains),
+	}
+}
+
+type detectToolchainsArgs struct {
+	Tools []string `json:"tools"`
+}
+
+func (s *Server) handleDetectToolchains(ctx context.Context, args detectToolchainsArgs) (*mcp.ToolResult, error) {
+	wanted := make(map[string]bool)
+	for _, name := range args.Tools {
+		wanted[name] = true
+	}
+
+	pathEnv, ok := s.env(ctx).Get("PATH")
+	if !ok {
+		pathEnv = os.Getenv("PATH")
+	}
+
+	var probes []toolchainProbe
+	for _, probe := range toolchainProbes {
+		if len(wanted) == 0 || wanted[probe.Name] {
+			probes = append(probes, probe)
+		}
+	}
+
+	results := make([]ToolchainInfo, len(probes))
+	var wg sync.WaitGroup
+	for i, probe := range probes {
+		wg.Add(1)
+		go func(i int, probe toolchainProbe) {
+			defer wg.Done()
+			results[i] = probeToolchain(ctx, probe, pathEnv)
+		}(i, probe)
+	}
+	wg.Wait()
+
+	found := 0
+	for _, r := range results {
+		if r.Found {
+			found++
+		}
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"toolchains":  results,
+		"found_count": found,
+	})
+}
+
+func probeToolchain(ctx context.Context, probe toolchainProbe, pathEnv string) ToolchainInfo {
+	info := ToolchainInfo{Name: probe.Name}
+
+	for _, exe := range probe.Executables {
+		if matches := findExecutables(exe, pathEnv); len(matches) > 0 {
+			info.Found = true
+			info.Path = matches[0].Path
+			break
+		}
+	}
+	if !info.Found {
+		return info
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, info.Path, probe.VersionArgs...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil && output.Len() == 0 {
+		info.Error = err.Error()
+		return info
+	}
+
+	raw := strings.TrimSpace(output.String())
+	if idx := strings.IndexByte(raw, '\n'); idx >= 0 {
+		raw = strings.TrimSpace(raw[:idx])
+	}
+	info.RawVersion = raw
+	info.Version = versionRegex.FindString(raw)
+
+	return info
+}
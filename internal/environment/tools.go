@@ -2,14 +2,20 @@ package environment
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"os/user"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/shirou/gopsutil/v3/host"
 	"github.com/shirou/gopsutil/v3/mem"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
 	"github.com/local-mcps/dev-mcps/pkg/mcp"
 )
 
@@ -23,15 +29,17 @@ func (s *Server) getEnvTool() *mcp.Tool {
 			},
 			[]string{"name"},
 		),
-		Handler: s.handleGetEnv,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGetEnv),
 	}
 }
 
-func (s *Server) handleGetEnv(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	name, err := mcp.GetStringParam(params, "name", true)
-	if err != nil {
-		return nil, err
-	}
+type getEnvArgs struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func (s *Server) handleGetEnv(ctx context.Context, args getEnvArgs) (*mcp.ToolResult, error) {
+	name := args.Name
 
 	if s.isSensitive(name) {
 		return mcp.JSONResult(map[string]interface{}{
@@ -51,7 +59,7 @@ func (s *Server) handleGetEnv(ctx context.Context, params map[string]interface{}
 		})
 	}
 
-	if value, ok := s.sessionEnv[name]; ok {
+	if value, ok := s.env(ctx).Get(name); ok {
 		return mcp.JSONResult(map[string]interface{}{
 			"name":   name,
 			"value":  value,
@@ -67,6 +75,114 @@ func (s *Server) handleGetEnv(ctx context.Context, params map[string]interface{}
 	})
 }
 
+func (s *Server) getEnvsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "get_envs",
+		Description: "Get multiple environment variables by name, glob (e.g. DATABASE_*) or regular expression",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"names":   mcp.ArrayProperty("string", "Exact variable names to fetch"),
+				"pattern": mcp.StringProperty("Glob pattern matched against variable names"),
+				"regex":   mcp.BoolProperty("Treat pattern as a regular expression instead of a glob"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGetEnvs),
+	}
+}
+
+type getEnvsArgs struct {
+	Names   []string `json:"names"`
+	Pattern string   `json:"pattern"`
+	Regex   bool     `json:"regex"`
+}
+
+func (s *Server) handleGetEnvs(ctx context.Context, args getEnvsArgs) (*mcp.ToolResult, error) {
+	names, pattern := args.Names, args.Pattern
+
+	if len(names) == 0 && pattern == "" {
+		return nil, fmt.Errorf("%w: names or pattern is required", common.ErrInvalidInput)
+	}
+
+	var re *regexp.Regexp
+	if pattern != "" {
+		var err error
+		if args.Regex {
+			re, err = regexp.Compile(pattern)
+		} else {
+			_, err = filepath.Match(pattern, "")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid pattern: %v", common.ErrInvalidInput, err)
+		}
+	}
+
+	env := make(map[string]string)
+	for _, entry := range os.Environ() {
+		if name, value, ok := strings.Cut(entry, "="); ok {
+			env[name] = value
+		}
+	}
+	for name, value := range s.env(ctx).All() {
+		env[name] = value
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	matches := func(name string) bool {
+		if wanted[name] {
+			return true
+		}
+		switch {
+		case re != nil:
+			return re.MatchString(name)
+		case pattern != "":
+			matched, _ := filepath.Match(pattern, name)
+			return matched
+		}
+		return false
+	}
+
+	var matched []string
+	for name := range env {
+		if matches(name) {
+			matched = append(matched, name)
+		}
+	}
+	sort.Strings(matched)
+
+	variables := []map[string]string{}
+	filteredCount := 0
+	for _, name := range matched {
+		if s.isSensitive(name) || !s.isAllowed(name) {
+			filteredCount++
+			continue
+		}
+		variables = append(variables, map[string]string{
+			"name":  name,
+			"value": env[name],
+		})
+	}
+
+	missing := []string{}
+	for _, name := range names {
+		if _, ok := env[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"variables":      variables,
+		"total_count":    len(variables),
+		"filtered_count": filteredCount,
+		"missing":        missing,
+	})
+}
+
 func (s *Server) setEnvTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "set_env",
@@ -78,23 +194,38 @@ func (s *Server) setEnvTool() *mcp.Tool {
 			},
 			[]string{"name", "value"},
 		),
-		Handler: s.handleSetEnv,
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleSetEnv),
 	}
 }
 
-func (s *Server) handleSetEnv(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	name, err := mcp.GetStringParam(params, "name", true)
-	if err != nil {
-		return nil, err
+type setEnvArgs struct {
+	Name  string `json:"name" validate:"required"`
+	Value string `json:"value" validate:"required"`
+}
+
+func (s *Server) handleSetEnv(ctx context.Context, args setEnvArgs) (*mcp.ToolResult, error) {
+	name, value := args.Name, args.Value
+
+	if violations := s.validateSetEnv(name, value); len(violations) > 0 {
+		result, err := mcp.JSONResult(map[string]interface{}{
+			"name":   name,
+			"set":    false,
+			"errors": violations,
+		})
+		if err != nil {
+			return nil, err
+		}
+		result.IsError = true
+		return result, nil
 	}
 
-	value, err := mcp.GetStringParam(params, "value", true)
-	if err != nil {
+	s.env(ctx).Set(name, value)
+
+	if err := s.saveSession(ctx); err != nil {
 		return nil, err
 	}
 
-	s.sessionEnv[name] = value
-
 	return mcp.JSONResult(map[string]interface{}{
 		"name":  name,
 		"value": value,
@@ -112,12 +243,17 @@ func (s *Server) listEnvTool() *mcp.Tool {
 			},
 			[]string{},
 		),
-		Handler: s.handleListEnv,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleListEnv),
 	}
 }
 
-func (s *Server) handleListEnv(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	filterPrefix, _ := mcp.GetStringParam(params, "filter_prefix", false)
+type listEnvArgs struct {
+	FilterPrefix string `json:"filter_prefix"`
+}
+
+func (s *Server) handleListEnv(ctx context.Context, args listEnvArgs) (*mcp.ToolResult, error) {
+	filterPrefix := args.FilterPrefix
 
 	var variables []map[string]string
 	filteredCount := 0
@@ -150,7 +286,7 @@ func (s *Server) handleListEnv(ctx context.Context, params map[string]interface{
 		})
 	}
 
-	for name, value := range s.sessionEnv {
+	for name, value := range s.env(ctx).All() {
 		if filterPrefix != "" && !strings.HasPrefix(name, filterPrefix) {
 			continue
 		}
@@ -177,18 +313,22 @@ func (s *Server) unsetEnvTool() *mcp.Tool {
 			},
 			[]string{"name"},
 		),
-		Handler: s.handleUnsetEnv,
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleUnsetEnv),
 	}
 }
 
-func (s *Server) handleUnsetEnv(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	name, err := mcp.GetStringParam(params, "name", true)
-	if err != nil {
-		return nil, err
-	}
+type unsetEnvArgs struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func (s *Server) handleUnsetEnv(ctx context.Context, args unsetEnvArgs) (*mcp.ToolResult, error) {
+	name := args.Name
 
-	if _, ok := s.sessionEnv[name]; ok {
-		delete(s.sessionEnv, name)
+	if s.env(ctx).Unset(name) {
+		if err := s.saveSession(ctx); err != nil {
+			return nil, err
+		}
 		return mcp.JSONResult(map[string]interface{}{
 			"name":  name,
 			"unset": true,
@@ -205,12 +345,13 @@ func (s *Server) handleUnsetEnv(ctx context.Context, params map[string]interface
 func (s *Server) getSystemInfoTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "get_system_info",
-		Description: "Get system information",
+		Description: "Get system information (OS, CPU, memory, kernel, uptime, locale, timezone)",
 		InputSchema: mcp.BuildInputSchema(
 			map[string]interface{}{},
 			[]string{},
 		),
-		Handler: s.handleGetSystemInfo,
+		Annotations: mcp.ReadOnly(),
+		Handler:     s.handleGetSystemInfo,
 	}
 }
 
@@ -234,9 +375,76 @@ func (s *Server) handleGetSystemInfo(ctx context.Context, params map[string]inte
 		result["available_memory_gb"] = float64(memInfo.Available) / (1024 * 1024 * 1024)
 	}
 
+	if kernel, err := host.KernelVersion(); err == nil {
+		result["kernel_version"] = kernel
+	}
+	if platform, _, version, err := host.PlatformInformation(); err == nil {
+		result["platform"] = platform
+		result["platform_version"] = version
+	}
+
+	if bootTime, err := host.BootTime(); err == nil {
+		boot := time.Unix(int64(bootTime), 0)
+		result["boot_time"] = boot.Format(time.RFC3339)
+		result["uptime_seconds"] = int64(time.Since(boot).Seconds())
+	}
+
+	zoneAbbr, offset := time.Now().Zone()
+	result["timezone"] = map[string]interface{}{
+		"name":           timezoneName(),
+		"abbreviation":   zoneAbbr,
+		"offset_seconds": offset,
+		"offset":         time.Now().Format("-07:00"),
+	}
+
+	result["locale"] = s.locale(ctx)
+
 	return mcp.JSONResult(result)
 }
 
+func timezoneName() string {
+	if tz := os.Getenv("TZ"); tz != "" {
+		return strings.TrimPrefix(tz, ":")
+	}
+	if name := time.Local.String(); name != "Local" {
+		return name
+	}
+	if target, err := filepath.EvalSymlinks("/etc/localtime"); err == nil {
+		if idx := strings.Index(target, "zoneinfo/"); idx >= 0 {
+			return target[idx+len("zoneinfo/"):]
+		}
+	}
+	if data, err := os.ReadFile("/etc/timezone"); err == nil {
+		return strings.TrimSpace(string(data))
+	}
+	return "Local"
+}
+
+func (s *Server) locale(ctx context.Context) map[string]string {
+	locale := make(map[string]string)
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LC_MESSAGES", "LANG", "LANGUAGE"} {
+		if value, ok := s.env(ctx).Get(name); ok && value != "" {
+			locale[name] = value
+		} else if value := os.Getenv(name); value != "" {
+			locale[name] = value
+		}
+	}
+
+	effective := ""
+	for _, name := range []string{"LC_ALL", "LC_CTYPE", "LANG"} {
+		if value := locale[name]; value != "" {
+			effective = value
+			break
+		}
+	}
+	if effective == "" {
+		effective = "C"
+	}
+	locale["effective"] = effective
+
+	return locale
+}
+
 func (s *Server) getUserInfoTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "get_user_info",
@@ -245,7 +453,8 @@ func (s *Server) getUserInfoTool() *mcp.Tool {
 			map[string]interface{}{},
 			[]string{},
 		),
-		Handler: s.handleGetUserInfo,
+		Annotations: mcp.ReadOnly(),
+		Handler:     s.handleGetUserInfo,
 	}
 }
 
@@ -281,7 +490,8 @@ func (s *Server) getPathInfoTool() *mcp.Tool {
 			map[string]interface{}{},
 			[]string{},
 		),
-		Handler: s.handleGetPathInfo,
+		Annotations: mcp.ReadOnly(),
+		Handler:     s.handleGetPathInfo,
 	}
 }
 
@@ -312,31 +522,67 @@ func (s *Server) handleGetPathInfo(ctx context.Context, params map[string]interf
 func (s *Server) expandPathTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "expand_path",
-		Description: "Expand path with variables (e.g., ~, $HOME)",
+		Description: "Expand path with variables (e.g., ~, ~user, $HOME, ${VAR}, %VAR%), using session variables first",
 		InputSchema: mcp.BuildInputSchema(
 			map[string]interface{}{
-				"path": mcp.StringProperty("Path to expand"),
+				"path":   mcp.StringProperty("Path to expand"),
+				"strict": mcp.BoolProperty("Fail on undefined variables or unknown users instead of leaving them unexpanded"),
 			},
 			[]string{"path"},
 		),
-		Handler: s.handleExpandPath,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleExpandPath),
 	}
 }
 
-func (s *Server) handleExpandPath(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	path, err := mcp.GetStringParam(params, "path", true)
-	if err != nil {
-		return nil, err
-	}
+var windowsVarRegex = regexp.MustCompile(`%([A-Za-z_][A-Za-z0-9_()]*)%`)
+
+type expandPathArgs struct {
+	Path   string `json:"path" validate:"required"`
+	Strict bool   `json:"strict"`
+}
+
+func (s *Server) handleExpandPath(ctx context.Context, args expandPathArgs) (*mcp.ToolResult, error) {
+	path, strict := args.Path, args.Strict
 
+	var undefined []string
 	expanded := path
 
 	if strings.HasPrefix(expanded, "~") {
-		homeDir, _ := os.UserHomeDir()
-		expanded = homeDir + expanded[1:]
+		home, rest, err := expandTilde(expanded)
+		if err != nil {
+			if strict {
+				return nil, err
+			}
+			undefined = append(undefined, expanded[:len(expanded)-len(rest)])
+		} else {
+			expanded = home + rest
+		}
 	}
 
-	expanded = os.ExpandEnv(expanded)
+	expanded = windowsVarRegex.ReplaceAllStringFunc(expanded, func(match string) string {
+		name := match[1 : len(match)-1]
+		if value, ok := s.lookupEnv(ctx, name); ok {
+			return value
+		}
+		undefined = append(undefined, name)
+		return match
+	})
+
+	expanded = os.Expand(expanded, func(name string) string {
+		if value, ok := s.lookupEnv(ctx, name); ok {
+			return value
+		}
+		undefined = append(undefined, name)
+		if name == "$" {
+			return "$"
+		}
+		return "${" + name + "}"
+	})
+
+	if strict && len(undefined) > 0 {
+		return nil, fmt.Errorf("%w: undefined in path: %s", common.ErrInvalidInput, strings.Join(undefined, ", "))
+	}
 
 	absPath, _ := filepath.Abs(expanded)
 	expanded = absPath
@@ -345,12 +591,83 @@ func (s *Server) handleExpandPath(ctx context.Context, params map[string]interfa
 	exists := err == nil
 	isDir := exists && info.IsDir()
 
-	return mcp.JSONResult(map[string]interface{}{
+	result := map[string]interface{}{
 		"original":     path,
 		"expanded":     expanded,
 		"exists":       exists,
 		"is_directory": isDir,
-	})
+	}
+	if len(undefined) > 0 {
+		result["undefined"] = undefined
+	}
+
+	return mcp.JSONResult(result)
+}
+
+// expandTilde resolves a leading ~ or ~user and returns the home directory
+// along with the remainder of the path.
+func expandTilde(path string) (string, string, error) {
+	end := strings.IndexAny(path, `/\`)
+	if end < 0 {
+		end = len(path)
+	}
+	name, rest := path[1:end], path[end:]
+
+	if name == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", rest, fmt.Errorf("%w: home directory: %v", common.ErrNotFound, err)
+		}
+		return homeDir, rest, nil
+	}
+
+	u, err := user.Lookup(name)
+	if err != nil {
+		return "", rest, fmt.Errorf("%w: user %s", common.ErrNotFound, name)
+	}
+	return u.HomeDir, rest, nil
+}
+
+type validationError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (s *Server) validateSetEnv(name, value string) []validationError {
+	var violations []validationError
+
+	if err := common.ValidateEnvVarName(name); err != nil {
+		violations = append(violations, validationError{
+			Field:   "name",
+			Rule:    "invalid_name",
+			Message: err.Error(),
+		})
+	} else if s.config.ProtectSensitive && s.isSensitive(name) {
+		violations = append(violations, validationError{
+			Field:   "name",
+			Rule:    "sensitive_name",
+			Message: fmt.Sprintf("%s matches a denied_env_pattern and cannot be set", name),
+		})
+	}
+
+	if max := s.config.MaxValueSizeBytes; max > 0 && len(value) > max {
+		violations = append(violations, validationError{
+			Field:   "value",
+			Rule:    "max_size",
+			Message: fmt.Sprintf("value is %d bytes, limit is %d", len(value), max),
+		})
+	}
+
+	if strings.ContainsRune(value, 0) {
+		violations = append(violations, validationError{
+			Field:   "value",
+			Rule:    "nul_byte",
+			Message: "value must not contain NUL bytes",
+		})
+	}
+
+	return violations
 }
 
 func (s *Server) isSensitive(name string) bool {
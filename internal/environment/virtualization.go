@@ -0,0 +1,208 @@
+package environment
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/shirou/gopsutil/v3/host"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type detection struct {
+	Detected bool     `json:"detected"`
+	Kind     string   `json:"kind,omitempty"`
+	Version  string   `json:"version,omitempty"`
+	Name     string   `json:"name,omitempty"`
+	Evidence []string `json:"evidence"`
+}
+
+func (d *detection) add(kind, evidence string) {
+	d.Detected = true
+	if d.Kind == "" {
+		d.Kind = kind
+	}
+	d.Evidence = append(d.Evidence, evidence)
+}
+
+func (s *Server) detectVirtualizationTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "detect_virtualization",
+		Description: "Detect whether the server runs inside a container (Docker, Podman, LXC, Kubernetes), WSL, or a virtual machine",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{},
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     s.handleDetectVirtualization,
+	}
+}
+
+func (s *Server) handleDetectVirtualization(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
+	container := detectContainer()
+	wsl := detectWSL()
+	vm := detectVM()
+
+	summary := "bare-metal"
+	switch {
+	case container.Detected:
+		summary = container.Kind
+	case wsl.Detected:
+		summary = "wsl"
+	case vm.Detected:
+		summary = "vm"
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"summary":   summary,
+		"container": container,
+		"wsl":       wsl,
+		"vm":        vm,
+	})
+}
+
+func detectContainer() detection {
+	d := detection{Evidence: []string{}}
+	if runtime.GOOS != "linux" {
+		return d
+	}
+
+	if _, err := os.Stat("/run/.containerenv"); err == nil {
+		d.add("podman", "/run/.containerenv exists")
+	}
+	if _, err := os.Stat("/.dockerenv"); err == nil {
+		d.add("docker", "/.dockerenv exists")
+	}
+
+	switch env := os.Getenv("container"); env {
+	case "":
+	case "podman", "docker", "lxc", "systemd-nspawn":
+		d.add(env, "container="+env)
+	default:
+		d.add("container", "container="+env)
+	}
+
+	if data, err := os.ReadFile("/proc/1/cgroup"); err == nil {
+		cgroup := string(data)
+		for _, marker := range []string{"docker", "kubepods", "libpod", "lxc", "containerd"} {
+			if strings.Contains(cgroup, marker) {
+				kind := marker
+				switch marker {
+				case "libpod":
+					kind = "podman"
+				case "kubepods":
+					kind = "kubernetes"
+				}
+				d.add(kind, "/proc/1/cgroup mentions "+marker)
+			}
+		}
+	}
+
+	if os.Getenv("KUBERNETES_SERVICE_HOST") != "" {
+		d.add("kubernetes", "KUBERNETES_SERVICE_HOST is set")
+		d.Name = "kubernetes"
+	}
+
+	return d
+}
+
+func detectWSL() detection {
+	d := detection{Evidence: []string{}}
+	if runtime.GOOS != "linux" {
+		return d
+	}
+
+	if data, err := os.ReadFile("/proc/sys/kernel/osrelease"); err == nil {
+		release := strings.ToLower(string(data))
+		if strings.Contains(release, "microsoft") || strings.Contains(release, "wsl") {
+			d.add("wsl", "kernel release: "+strings.TrimSpace(string(data)))
+			d.Version = "1"
+			if strings.Contains(release, "wsl2") || strings.Contains(release, "microsoft-standard") {
+				d.Version = "2"
+			}
+		}
+	}
+
+	if distro := os.Getenv("WSL_DISTRO_NAME"); distro != "" {
+		d.add("wsl", "WSL_DISTRO_NAME="+distro)
+		d.Name = distro
+	}
+	if os.Getenv("WSL_INTEROP") != "" && d.Version == "" {
+		d.Version = "2"
+	}
+
+	return d
+}
+
+func detectVM() detection {
+	d := detection{Evidence: []string{}}
+
+	if system, role, err := host.Virtualization(); err == nil && role == "guest" && !containerSystems[system] {
+		d.add(system, "gopsutil reports guest of "+system)
+		d.Name = system
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		for _, file := range []string{"/sys/class/dmi/id/sys_vendor", "/sys/class/dmi/id/product_name"} {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			value := strings.TrimSpace(string(data))
+			if hv := matchHypervisor(value); hv != "" {
+				d.add(hv, file+": "+value)
+				if d.Name == "" {
+					d.Name = hv
+				}
+			}
+		}
+		if data, err := os.ReadFile("/proc/cpuinfo"); err == nil && strings.Contains(string(data), " hypervisor") {
+			d.add("vm", "cpu reports hypervisor flag")
+		}
+	case "darwin":
+		if out, err := exec.Command("sysctl", "-n", "kern.hv_vmm_present").Output(); err == nil && strings.TrimSpace(string(out)) == "1" {
+			d.add("vm", "kern.hv_vmm_present=1")
+		}
+	}
+
+	return d
+}
+
+var containerSystems = map[string]bool{
+	"":              true,
+	"docker":        true,
+	"lxc":           true,
+	"podman":        true,
+	"rkt":           true,
+	"openvz":        true,
+	"linux-vserver": true,
+	"wsl":           true,
+}
+
+func matchHypervisor(value string) string {
+	lower := strings.ToLower(value)
+	hypervisors := []struct{ marker, name string }{
+		{"qemu", "qemu"},
+		{"kvm", "kvm"},
+		{"vmware", "vmware"},
+		{"virtualbox", "virtualbox"},
+		{"innotek", "virtualbox"},
+		{"xen", "xen"},
+		{"parallels", "parallels"},
+		{"amazon ec2", "aws"},
+		{"google compute engine", "gce"},
+		{"microsoft corporation", "hyper-v"},
+		{"firecracker", "firecracker"},
+		{"bochs", "bochs"},
+	}
+	for _, h := range hypervisors {
+		if strings.Contains(lower, h.marker) {
+			return h.name
+		}
+	}
+	return ""
+}
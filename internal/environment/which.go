@@ -0,0 +1,138 @@
+package environment
+
+import (
+	"bufio"
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type ExecutableMatch struct {
+	Path          string `json:"path"`
+	IsSymlink     bool   `json:"is_symlink"`
+	ResolvedPath  string `json:"resolved_path,omitempty"`
+	IsScript      bool   `json:"is_script"`
+	Interpreter   string `json:"interpreter,omitempty"`
+	PathEntry     string `json:"path_entry"`
+	PathEntryRank int    `json:"path_entry_rank"`
+}
+
+func (s *Server) whichTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "which",
+		Description: "Locate an executable on PATH (honoring session PATH overrides)",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"name": mcp.StringProperty("Executable name"),
+			},
+			[]string{"name"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleWhich),
+	}
+}
+
+type whichArgs struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func (s *Server) handleWhich(ctx context.Context, args whichArgs) (*mcp.ToolResult, error) {
+	name := args.Name
+
+	pathEnv, fromSession := s.env(ctx).Get("PATH")
+	if !fromSession {
+		pathEnv = os.Getenv("PATH")
+	}
+
+	matches := findExecutables(name, pathEnv)
+
+	result := map[string]interface{}{
+		"name":              name,
+		"found":             len(matches) > 0,
+		"matches":           matches,
+		"session_path_used": fromSession,
+	}
+	if len(matches) > 0 {
+		result["path"] = matches[0].Path
+	}
+
+	return mcp.JSONResult(result)
+}
+
+func findExecutables(name, pathEnv string) []ExecutableMatch {
+	matches := []ExecutableMatch{}
+
+	candidates := []string{name}
+	if runtime.GOOS == "windows" && filepath.Ext(name) == "" {
+		exts := os.Getenv("PATHEXT")
+		if exts == "" {
+			exts = ".COM;.EXE;.BAT;.CMD"
+		}
+		candidates = nil
+		for _, ext := range strings.Split(exts, ";") {
+			candidates = append(candidates, name+strings.ToLower(ext))
+		}
+	}
+
+	if strings.ContainsRune(name, os.PathSeparator) {
+		if m, ok := inspectExecutable(name); ok {
+			matches = append(matches, m)
+		}
+		return matches
+	}
+
+	seen := make(map[string]bool)
+	for rank, dir := range filepath.SplitList(pathEnv) {
+		if dir == "" {
+			dir = "."
+		}
+		for _, candidate := range candidates {
+			full := filepath.Join(dir, candidate)
+			if seen[full] {
+				continue
+			}
+			seen[full] = true
+
+			if m, ok := inspectExecutable(full); ok {
+				m.PathEntry = dir
+				m.PathEntryRank = rank
+				matches = append(matches, m)
+			}
+		}
+	}
+	return matches
+}
+
+func inspectExecutable(path string) (ExecutableMatch, bool) {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return ExecutableMatch{}, false
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm()&0111 == 0 {
+		return ExecutableMatch{}, false
+	}
+
+	m := ExecutableMatch{Path: path}
+
+	if linfo, err := os.Lstat(path); err == nil && linfo.Mode()&os.ModeSymlink != 0 {
+		m.IsSymlink = true
+		if resolved, err := filepath.EvalSymlinks(path); err == nil {
+			m.ResolvedPath = resolved
+		}
+	}
+
+	if f, err := os.Open(path); err == nil {
+		defer f.Close()
+		line, _ := bufio.NewReader(f).ReadString('\n')
+		if strings.HasPrefix(line, "#!") {
+			m.IsScript = true
+			m.Interpreter = strings.TrimSpace(strings.TrimPrefix(line, "#!"))
+		}
+	}
+
+	return m, true
+}
@@ -0,0 +1,512 @@
+package filesystem
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxArchiveEntries bounds how many entries an archive may have.
+const maxArchiveEntries = 100000
+
+func (s *Server) createArchiveTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "create_archive",
+		Description: "Pack a file or directory into a zip or tar.gz archive. Entries are named from the source's base name down, and paths outside the allowed paths are left out",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"source":      mcp.StringProperty("Absolute path to the file or directory to pack"),
+				"destination": mcp.StringProperty("Absolute path of the archive to create"),
+				"format":      mcp.StringProperty("zip or tar.gz (default: from the destination's extension)"),
+				"overwrite":   mcp.BoolProperty("Replace the destination if it exists"),
+			},
+			[]string{"source", "destination"},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleCreateArchive),
+	}
+}
+
+type createArchiveArgs struct {
+	Source      string `json:"source" validate:"required"`
+	Destination string `json:"destination" validate:"required"`
+	Format      string `json:"format" validate:"oneof=zip tar.gz"`
+	Overwrite   bool   `json:"overwrite"`
+}
+
+func (s *Server) handleCreateArchive(ctx context.Context, args createArchiveArgs) (*mcp.ToolResult, error) {
+	srcPath, err := s.validator.ResolvePath(args.Source)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := statExisting(srcPath, args.Source); err != nil {
+		return nil, err
+	}
+	dstPath, err := filepath.Abs(args.Destination)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.ValidatePath(dstPath); err != nil {
+		return nil, err
+	}
+	format, err := archiveFormat(dstPath, args.Format)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := os.Lstat(dstPath); err == nil && !args.Overwrite {
+		return nil, fmt.Errorf("%w: %s; set overwrite to replace it", common.ErrAlreadyExists, args.Destination)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return nil, err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dstPath), "."+filepath.Base(dstPath)+".*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := newArchiveWriter(tmp, format)
+	limit := int64(s.config.MaxArchiveSizeMB) * 1024 * 1024
+	var entries int
+	var total int64
+	base := filepath.Dir(srcPath)
+	err = filepath.WalkDir(srcPath, func(p string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			return err
+		}
+		if p == dstPath || p == tmp.Name() {
+			return nil
+		}
+		if s.validator.ValidatePath(p) != nil {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() && !info.IsDir() && info.Mode()&fs.ModeSymlink == 0 {
+			return nil // sockets, devices and pipes
+		}
+		if entries++; entries > maxArchiveEntries {
+			return fmt.Errorf("%w: more than %d entries", common.ErrInvalidInput, maxArchiveEntries)
+		}
+		if info.Mode().IsRegular() {
+			if total += info.Size(); total > limit {
+				return fmt.Errorf("%w: contents exceed the archive limit of %d MB", common.ErrFileTooLarge, s.config.MaxArchiveSizeMB)
+			}
+		}
+		rel, _ := filepath.Rel(base, p)
+		return w.add(p, filepath.ToSlash(rel), info)
+	})
+	if closeErr := w.close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), dstPath); err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(dstPath)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"archive":     dstPath,
+		"format":      format,
+		"entries":     entries,
+		"total_bytes": total,
+		"size_bytes":  info.Size(),
+	})
+}
+
+func (s *Server) extractArchiveTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "extract_archive",
+		Description: "Extract a zip or tar.gz archive into a directory. The whole archive is checked first: entries that would land outside the destination, links pointing out of it and archives over the size limit are rejected before anything is written",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"archive":     mcp.StringProperty("Absolute path to the archive"),
+				"destination": mcp.StringProperty("Absolute path of the directory to extract into; created if missing"),
+				"format":      mcp.StringProperty("zip or tar.gz (default: from the archive's extension)"),
+				"overwrite":   mcp.BoolProperty("Replace files that already exist"),
+			},
+			[]string{"archive", "destination"},
+		),
+		Annotations: mcp.Destructive(),
+		Handler:     mcp.TypedHandler(s.handleExtractArchive),
+	}
+}
+
+type extractArchiveArgs struct {
+	Archive     string `json:"archive" validate:"required"`
+	Destination string `json:"destination" validate:"required"`
+	Format      string `json:"format" validate:"oneof=zip tar.gz"`
+	Overwrite   bool   `json:"overwrite"`
+}
+
+// archiveEntry is an entry of an archive being extracted.
+type archiveEntry struct {
+	name     string
+	mode     fs.FileMode
+	size     int64
+	linkname string
+	open     func() (io.ReadCloser, error)
+}
+
+func (s *Server) handleExtractArchive(ctx context.Context, args extractArchiveArgs) (*mcp.ToolResult, error) {
+	archivePath, err := s.validator.ResolvePath(args.Archive)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := statExisting(archivePath, args.Archive); err != nil {
+		return nil, err
+	}
+	dstPath, err := filepath.Abs(args.Destination)
+	if err != nil {
+		return nil, err
+	}
+	dstPath = filepath.Clean(dstPath)
+	if err := s.validator.ValidatePath(dstPath); err != nil {
+		return nil, err
+	}
+	format, err := archiveFormat(archivePath, args.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check every entry before writing any.
+	limit := int64(s.config.MaxArchiveSizeMB) * 1024 * 1024
+	var total int64
+	var skipped []string
+	entries := 0
+	err = walkArchive(archivePath, format, func(e archiveEntry) error {
+		if entries++; entries > maxArchiveEntries {
+			return fmt.Errorf("%w: more than %d entries", common.ErrInvalidInput, maxArchiveEntries)
+		}
+		target, err := s.extractTarget(dstPath, e)
+		if err != nil {
+			return err
+		}
+		if target == "" {
+			skipped = append(skipped, e.name)
+			return nil
+		}
+		if total += e.size; total > limit {
+			return fmt.Errorf("%w: contents exceed the archive limit of %d MB", common.ErrFileTooLarge, s.config.MaxArchiveSizeMB)
+		}
+		if !args.Overwrite && !e.mode.IsDir() {
+			if _, err := os.Lstat(target); err == nil {
+				return fmt.Errorf("%w: %s; set overwrite to replace it", common.ErrAlreadyExists, target)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Extract, holding each file to the size its header declared.
+	var written int64
+	extracted := 0
+	err = walkArchive(archivePath, format, func(e archiveEntry) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		target, err := s.extractTarget(dstPath, e)
+		if err != nil || target == "" {
+			return err
+		}
+		n, err := extractEntry(target, e, limit-written)
+		if err != nil {
+			return fmt.Errorf("extracting %s: %w", e.name, err)
+		}
+		written += n
+		extracted++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"archive":     archivePath,
+		"destination": dstPath,
+		"format":      format,
+		"entries":     extracted,
+		"total_bytes": written,
+	}
+	if len(skipped) > 0 {
+		result["skipped"] = skipped
+	}
+	return mcp.JSONResult(result)
+}
+
+// extractTarget returns where e goes under dst, "" for entries that are
+// skipped, or an error for entries that would escape dst.
+func (s *Server) extractTarget(dst string, e archiveEntry) (string, error) {
+	name := strings.ReplaceAll(e.name, `\`, "/")
+	if path.IsAbs(name) || filepath.VolumeName(name) != "" {
+		return "", fmt.Errorf("%w: archive entry %q has an absolute path", common.ErrInvalidPath, e.name)
+	}
+	clean := path.Clean(name)
+	if clean == ".." || strings.HasPrefix(clean, "../") {
+		return "", fmt.Errorf("%w: archive entry %q escapes the destination", common.ErrInvalidPath, e.name)
+	}
+	if clean == "." {
+		return "", nil
+	}
+	target := filepath.Join(dst, filepath.FromSlash(clean))
+
+	switch {
+	case e.mode.IsDir(), e.mode.IsRegular():
+	case e.mode&fs.ModeSymlink != 0:
+		link := filepath.FromSlash(e.linkname)
+		if filepath.IsAbs(link) || !common.IsWithin(filepath.Join(common.ResolveExisting(filepath.Dir(target)), link), common.ResolveExisting(dst)) {
+			return "", fmt.Errorf("%w: archive entry %q links outside the destination", common.ErrInvalidPath, e.name)
+		}
+	default:
+		return "", nil // devices, pipes and hard links are not extracted
+	}
+
+	// A link extracted earlier must not lead the entry out of dst either.
+	if err := s.validator.ValidatePath(target); err != nil {
+		return "", err
+	}
+	if !common.IsWithin(common.ResolveExisting(target), common.ResolveExisting(dst)) {
+		return "", fmt.Errorf("%w: archive entry %q escapes the destination", common.ErrInvalidPath, e.name)
+	}
+	return target, nil
+}
+
+// extractEntry writes e at target, copying at most limit bytes, and returns
+// the bytes written.
+func extractEntry(target string, e archiveEntry, limit int64) (int64, error) {
+	switch {
+	case e.mode.IsDir():
+		return 0, os.MkdirAll(target, 0755)
+	case e.mode&fs.ModeSymlink != 0:
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return 0, err
+		}
+		os.Remove(target)
+		return 0, os.Symlink(e.linkname, target)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return 0, err
+	}
+	r, err := e.open()
+	if err != nil {
+		return 0, err
+	}
+	defer r.Close()
+	// Replace rather than write through whatever is at target, which may
+	// be a link.
+	os.Remove(target)
+	f, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, e.mode.Perm()|0200)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, io.LimitReader(r, min(e.size, limit)+1))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && n > e.size {
+		err = fmt.Errorf("%w: entry is larger than its header says", common.ErrInvalidInput)
+	}
+	if err == nil && n > limit {
+		err = fmt.Errorf("%w: contents exceed the archive limit", common.ErrFileTooLarge)
+	}
+	return n, err
+}
+
+// walkArchive calls fn for each entry of the archive at p.
+func walkArchive(p, format string, fn func(archiveEntry) error) error {
+	if format == "zip" {
+		zr, err := zip.OpenReader(p)
+		if err != nil {
+			return fmt.Errorf("%w: not a zip archive: %v", common.ErrInvalidInput, err)
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			e := archiveEntry{name: f.Name, mode: f.Mode(), size: int64(f.UncompressedSize64), open: f.Open}
+			if e.mode&fs.ModeSymlink != 0 {
+				link, err := readZipLink(f)
+				if err != nil {
+					return err
+				}
+				e.linkname = link
+			}
+			if strings.HasSuffix(f.Name, "/") {
+				e.mode |= fs.ModeDir
+			}
+			if err := fn(e); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	file, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("%w: not a gzip archive: %v", common.ErrInvalidInput, err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: reading tar archive: %v", common.ErrInvalidInput, err)
+		}
+		e := archiveEntry{
+			name:     hdr.Name,
+			mode:     hdr.FileInfo().Mode(),
+			size:     hdr.Size,
+			linkname: hdr.Linkname,
+			open:     func() (io.ReadCloser, error) { return io.NopCloser(tr), nil },
+		}
+		if hdr.Typeflag == tar.TypeLink {
+			e.mode = fs.ModeIrregular // hard links are skipped
+		}
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+}
+
+func readZipLink(f *zip.File) (string, error) {
+	r, err := f.Open()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	link, err := io.ReadAll(io.LimitReader(r, 4096))
+	return string(link), err
+}
+
+// archiveFormat returns format, or the format named by p's extension.
+func archiveFormat(p, format string) (string, error) {
+	if format != "" {
+		return format, nil
+	}
+	lower := strings.ToLower(p)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip", nil
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tar.gz", nil
+	}
+	return "", fmt.Errorf("%w: cannot tell the archive format of %s; set format to zip or tar.gz", common.ErrInvalidInput, p)
+}
+
+// archiveWriter writes entries in one of the supported formats.
+type archiveWriter struct {
+	zw *zip.Writer
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func newArchiveWriter(w io.Writer, format string) *archiveWriter {
+	if format == "zip" {
+		return &archiveWriter{zw: zip.NewWriter(w)}
+	}
+	gz := gzip.NewWriter(w)
+	return &archiveWriter{gz: gz, tw: tar.NewWriter(gz)}
+}
+
+func (w *archiveWriter) add(p, name string, info fs.FileInfo) error {
+	var link string
+	if info.Mode()&fs.ModeSymlink != 0 {
+		var err error
+		if link, err = os.Readlink(p); err != nil {
+			return err
+		}
+	}
+
+	var dst io.Writer
+	if w.zw != nil {
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if info.IsDir() {
+			hdr.Name += "/"
+		} else if info.Mode().IsRegular() {
+			hdr.Method = zip.Deflate
+		}
+		if dst, err = w.zw.CreateHeader(hdr); err != nil {
+			return err
+		}
+		if link != "" {
+			_, err = io.WriteString(dst, link)
+			return err
+		}
+	} else {
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = name
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		hdr.Uname, hdr.Gname = "", ""
+		if err := w.tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		dst = w.tw
+	}
+
+	if !info.Mode().IsRegular() {
+		return nil
+	}
+	f, err := os.Open(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(dst, io.LimitReader(f, info.Size()))
+	return err
+}
+
+func (w *archiveWriter) close() error {
+	if w.zw != nil {
+		return w.zw.Close()
+	}
+	if err := w.tw.Close(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
@@ -0,0 +1,138 @@
+package filesystem
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+func TestArchiveRoundTrip(t *testing.T) {
+	for _, ext := range []string{".zip", ".tar.gz"} {
+		t.Run(ext, func(t *testing.T) {
+			tempDir := t.TempDir()
+			server := newTestServer(t, tempDir)
+			src := filepath.Join(tempDir, "project")
+			require.NoError(t, os.MkdirAll(filepath.Join(src, "sub"), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(src, "a.txt"), []byte("alpha"), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(src, "sub", "run.sh"), []byte("#!/bin/sh\n"), 0755))
+			require.NoError(t, os.Symlink("a.txt", filepath.Join(src, "link")))
+
+			archive := filepath.Join(tempDir, "out", "project"+ext)
+			result, err := server.createArchiveTool().Handler(context.Background(), map[string]interface{}{
+				"source": src, "destination": archive,
+			})
+			require.NoError(t, err)
+			var created map[string]interface{}
+			require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &created))
+			assert.EqualValues(t, 5, created["entries"])
+			assert.EqualValues(t, 15, created["total_bytes"])
+
+			_, err = server.createArchiveTool().Handler(context.Background(), map[string]interface{}{
+				"source": src, "destination": archive,
+			})
+			assert.ErrorIs(t, err, common.ErrAlreadyExists)
+
+			dst := filepath.Join(tempDir, "extracted")
+			_, err = server.extractArchiveTool().Handler(context.Background(), map[string]interface{}{
+				"archive": archive, "destination": dst,
+			})
+			require.NoError(t, err)
+			content, err := os.ReadFile(filepath.Join(dst, "project", "a.txt"))
+			require.NoError(t, err)
+			assert.Equal(t, "alpha", string(content))
+			info, err := os.Stat(filepath.Join(dst, "project", "sub", "run.sh"))
+			require.NoError(t, err)
+			assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+			link, err := os.Readlink(filepath.Join(dst, "project", "link"))
+			require.NoError(t, err)
+			assert.Equal(t, "a.txt", link)
+
+			_, err = server.extractArchiveTool().Handler(context.Background(), map[string]interface{}{
+				"archive": archive, "destination": dst,
+			})
+			assert.ErrorIs(t, err, common.ErrAlreadyExists)
+			_, err = server.extractArchiveTool().Handler(context.Background(), map[string]interface{}{
+				"archive": archive, "destination": dst, "overwrite": true,
+			})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func writeTarGz(t *testing.T, path string, headers ...*tar.Header) {
+	f, err := os.Create(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	for _, hdr := range headers {
+		require.NoError(t, tw.WriteHeader(hdr))
+		if hdr.Typeflag == tar.TypeReg {
+			_, err := tw.Write(make([]byte, hdr.Size))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+}
+
+func TestExtractArchiveRejectsUnsafeEntries(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	dst := filepath.Join(tempDir, "dst")
+	extract := func(archive string) error {
+		_, err := server.extractArchiveTool().Handler(context.Background(), map[string]interface{}{
+			"archive": archive, "destination": dst,
+		})
+		return err
+	}
+
+	t.Run("path traversal", func(t *testing.T) {
+		archive := filepath.Join(tempDir, "evil.tar.gz")
+		writeTarGz(t, archive,
+			&tar.Header{Name: "ok.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: 1},
+			&tar.Header{Name: "../escape.txt", Typeflag: tar.TypeReg, Mode: 0644, Size: 1})
+		assert.ErrorIs(t, extract(archive), common.ErrInvalidPath)
+		_, err := os.Stat(filepath.Join(dst, "ok.txt"))
+		assert.True(t, os.IsNotExist(err), "nothing is written when an entry is rejected")
+		_, err = os.Stat(filepath.Join(tempDir, "escape.txt"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("absolute path in zip", func(t *testing.T) {
+		archive := filepath.Join(tempDir, "evil.zip")
+		f, err := os.Create(archive)
+		require.NoError(t, err)
+		zw := zip.NewWriter(f)
+		_, err = zw.Create("/tmp/escape.txt")
+		require.NoError(t, err)
+		require.NoError(t, zw.Close())
+		require.NoError(t, f.Close())
+		assert.ErrorIs(t, extract(archive), common.ErrInvalidPath)
+	})
+
+	t.Run("symlink out of destination", func(t *testing.T) {
+		archive := filepath.Join(tempDir, "link.tar.gz")
+		writeTarGz(t, archive,
+			&tar.Header{Name: "etc", Typeflag: tar.TypeSymlink, Linkname: "../../etc"},
+			&tar.Header{Name: "etc/passwd", Typeflag: tar.TypeReg, Mode: 0644, Size: 1})
+		assert.ErrorIs(t, extract(archive), common.ErrInvalidPath)
+	})
+
+	t.Run("over the size limit", func(t *testing.T) {
+		archive := filepath.Join(tempDir, "big.tar.gz")
+		writeTarGz(t, archive,
+			&tar.Header{Name: "big.bin", Typeflag: tar.TypeReg, Mode: 0644, Size: 11 * 1024 * 1024})
+		assert.ErrorIs(t, extract(archive), common.ErrFileTooLarge)
+	})
+}
@@ -0,0 +1,318 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) batchTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "batch",
+		Description: "Run a list of filesystem operations (copy, move, delete, mkdir, write) in order with a result for each. By default every operation is tried; fail_fast stops at the first failure, and transactional also undoes the operations already done",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"operations": mcp.ObjectArrayProperty("Operations to run in order", map[string]interface{}{
+					"op":          mcp.StringProperty("copy, move, delete, mkdir or write"),
+					"path":        mcp.StringProperty("Absolute path operated on; the source for copy and move"),
+					"destination": mcp.StringProperty("Destination path for copy and move"),
+					"content":     mcp.StringProperty("Content for write"),
+					"encoding":    mcp.StringProperty("Encoding for write, as write_file takes it (default: text)"),
+					"recursive":   mcp.BoolProperty("For delete, delete a directory and its contents"),
+				}),
+				"mode": mcp.StringProperty("continue, fail_fast or transactional (default: continue)"),
+			},
+			[]string{"operations"},
+		),
+		Annotations: mcp.Destructive(),
+		Handler:     mcp.TypedHandler(s.handleBatch),
+	}
+}
+
+type batchOperation struct {
+	Op          string `json:"op"`
+	Path        string `json:"path"`
+	Destination string `json:"destination"`
+	Content     string `json:"content"`
+	Encoding    string `json:"encoding"`
+	Recursive   bool   `json:"recursive"`
+}
+
+type batchArgs struct {
+	Operations []batchOperation `json:"operations" validate:"required,min=1,max=1000"`
+	Mode       string           `json:"mode" default:"continue" validate:"oneof=continue fail_fast transactional"`
+}
+
+type BatchResult struct {
+	Index   int    `json:"index"`
+	Op      string `json:"op"`
+	Path    string `json:"path"`
+	Status  string `json:"status"` // ok, failed, skipped, rolled_back
+	Message string `json:"message,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+func (s *Server) handleBatch(ctx context.Context, args batchArgs) (*mcp.ToolResult, error) {
+	for i, op := range args.Operations {
+		if err := checkBatchOperation(op, args.Mode); err != nil {
+			return nil, fmt.Errorf("%w: operations[%d]: %v", common.ErrInvalidInput, i, err)
+		}
+	}
+
+	var journal *batchJournal
+	if args.Mode == "transactional" {
+		journal = &batchJournal{}
+	}
+	results := make([]BatchResult, len(args.Operations))
+	failed := -1
+	for i, op := range args.Operations {
+		results[i] = BatchResult{Index: i, Op: op.Op, Path: op.Path}
+		if failed >= 0 && args.Mode != "continue" {
+			results[i].Status = "skipped"
+			continue
+		}
+		message, err := s.runBatchOperation(ctx, op, journal)
+		if err != nil {
+			results[i].Status, results[i].Error = "failed", err.Error()
+			if failed < 0 {
+				failed = i
+			}
+			continue
+		}
+		results[i].Status, results[i].Message = "ok", message
+	}
+
+	rolledBack := false
+	var rollbackErr error
+	if journal != nil {
+		if failed >= 0 {
+			rollbackErr = journal.rollback()
+			for i := 0; i < failed; i++ {
+				results[i].Status = "rolled_back"
+			}
+			rolledBack = rollbackErr == nil
+		} else {
+			journal.commit()
+		}
+	}
+
+	counts := map[string]int{"ok": 0, "failed": 0, "skipped": 0, "rolled_back": 0}
+	for _, r := range results {
+		counts[r.Status]++
+	}
+	result := map[string]interface{}{
+		"mode":        args.Mode,
+		"results":     results,
+		"summary":     counts,
+		"success":     failed < 0,
+		"rolled_back": rolledBack,
+	}
+	if rollbackErr != nil {
+		result["rollback_error"] = rollbackErr.Error()
+	}
+	return mcp.JSONResult(result)
+}
+
+func checkBatchOperation(op batchOperation, mode string) error {
+	if op.Path == "" {
+		return errors.New("path is required")
+	}
+	switch op.Op {
+	case "copy", "move":
+		if op.Destination == "" {
+			return fmt.Errorf("destination is required for %s", op.Op)
+		}
+	case "delete":
+		if op.Recursive && mode == "transactional" {
+			return errors.New("recursive delete cannot be undone, so it is not allowed in transactional mode")
+		}
+	case "mkdir", "write":
+	default:
+		return fmt.Errorf("unknown op %q; use copy, move, delete, mkdir or write", op.Op)
+	}
+	return nil
+}
+
+// runBatchOperation runs op through the tool that does the same thing, so
+// it is validated and reported exactly as a single call would be. With a
+// journal, whatever the operation overwrites or removes is saved first.
+func (s *Server) runBatchOperation(ctx context.Context, op batchOperation, journal *batchJournal) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+
+	var handler mcp.ToolHandler
+	params := map[string]interface{}{"path": op.Path}
+	target := op.Path
+	switch op.Op {
+	case "copy", "move":
+		params = map[string]interface{}{"source": op.Path, "destination": op.Destination}
+		target = op.Destination
+		handler = mcp.TypedHandler(s.handleCopyFile)
+		if op.Op == "move" {
+			handler = mcp.TypedHandler(s.handleMoveFile)
+		}
+	case "write":
+		params["content"] = op.Content
+		if op.Encoding != "" {
+			params["encoding"] = op.Encoding
+		}
+		handler = mcp.TypedHandler(s.handleWriteFile)
+	case "mkdir":
+		handler = mcp.TypedHandler(s.handleCreateDirectory)
+	case "delete":
+		handler = mcp.TypedHandler(s.handleDeleteFile)
+		if info, err := os.Stat(op.Path); err == nil && info.IsDir() {
+			params["recursive"] = op.Recursive
+			handler = mcp.TypedHandler(s.handleDeleteDirectory)
+		}
+	}
+
+	var undo func(done bool) error
+	if journal != nil {
+		var err error
+		if undo, err = s.prepareUndo(op, target, journal); err != nil {
+			return "", err
+		}
+	}
+	result, err := handler(ctx, params)
+	if err != nil {
+		if undo != nil {
+			undo(false)
+		}
+		return "", err
+	}
+	if undo != nil {
+		journal.undos = append(journal.undos, func() error { return undo(true) })
+	}
+	return result.Content[0].Text, nil
+}
+
+// batchJournal records how to undo the operations of a transactional batch.
+type batchJournal struct {
+	undos []func() error
+	// backups are copies of files that were overwritten or deleted, removed
+	// once the batch succeeds.
+	backups []string
+}
+
+func (j *batchJournal) rollback() error {
+	var errs error
+	for i := len(j.undos) - 1; i >= 0; i-- {
+		errs = errors.Join(errs, j.undos[i]())
+	}
+	j.commit()
+	return errs
+}
+
+func (j *batchJournal) commit() {
+	for _, b := range j.backups {
+		os.Remove(b)
+	}
+}
+
+// prepareUndo saves what op is about to change and returns the function
+// that puts it back: after op is done, or to clean up if op fails part way.
+func (s *Server) prepareUndo(op batchOperation, target string, journal *batchJournal) (func(done bool) error, error) {
+	absTarget, err := filepath.Abs(target)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.ValidatePath(absTarget); err != nil {
+		return nil, err
+	}
+	created := firstMissingDir(absTarget)
+	if op.Op != "mkdir" {
+		created = firstMissingDir(filepath.Dir(absTarget))
+	}
+	removeCreated := func() error {
+		if created == "" {
+			return nil
+		}
+		return os.RemoveAll(created)
+	}
+
+	info, statErr := os.Lstat(absTarget)
+	existed := statErr == nil
+	var backup string
+	switch {
+	case op.Op == "mkdir" || !existed:
+	case info.Mode().IsRegular():
+		if backup, err = backupFile(absTarget, info.Mode().Perm()); err != nil {
+			return nil, fmt.Errorf("%w: saving %s for rollback: %v", common.ErrOperationFailed, absTarget, err)
+		}
+		journal.backups = append(journal.backups, backup)
+	case op.Op == "delete" && info.IsDir():
+		// Only empty directories can be deleted in a transaction.
+	default:
+		return nil, fmt.Errorf("%w: %s cannot be saved for rollback", common.ErrOperationFailed, absTarget)
+	}
+
+	return func(done bool) error {
+		var errs error
+		if op.Op == "move" && done {
+			source, _ := filepath.Abs(op.Path)
+			if _, err := os.Lstat(absTarget); err == nil {
+				errs = errors.Join(errs, os.Rename(absTarget, source))
+			}
+		}
+		switch {
+		case backup != "":
+			errs = errors.Join(errs, os.Rename(backup, absTarget))
+		case op.Op == "delete" && existed:
+			errs = errors.Join(errs, os.Mkdir(absTarget, info.Mode().Perm()))
+		case op.Op != "delete" && op.Op != "move" && op.Op != "mkdir":
+			if err := os.Remove(absTarget); err != nil && !os.IsNotExist(err) {
+				errs = errors.Join(errs, err)
+			}
+		}
+		return errors.Join(errs, removeCreated())
+	}, nil
+}
+
+// firstMissingDir returns the outermost directory of dir that does not
+// exist yet, or "" if dir exists.
+func firstMissingDir(dir string) string {
+	missing := ""
+	for {
+		if _, err := os.Lstat(dir); err == nil {
+			return missing
+		}
+		missing = dir
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return missing
+		}
+		dir = parent
+	}
+}
+
+func backupFile(path string, perm os.FileMode) (string, error) {
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return "", err
+	}
+	_, err = io.Copy(tmp, src)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), perm)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
@@ -0,0 +1,113 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+
+	batch := func(params map[string]interface{}) map[string]interface{} {
+		result, err := server.batchTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out
+	}
+	statuses := func(out map[string]interface{}) []string {
+		var s []string
+		for _, r := range out["results"].([]interface{}) {
+			s = append(s, r.(map[string]interface{})["status"].(string))
+		}
+		return s
+	}
+
+	t.Run("scaffold", func(t *testing.T) {
+		dir := filepath.Join(tempDir, "scaffold")
+		out := batch(map[string]interface{}{"operations": []interface{}{
+			map[string]interface{}{"op": "mkdir", "path": filepath.Join(dir, "src")},
+			map[string]interface{}{"op": "write", "path": filepath.Join(dir, "src", "main.go"), "content": "package main\n"},
+			map[string]interface{}{"op": "copy", "path": filepath.Join(dir, "src", "main.go"), "destination": filepath.Join(dir, "src", "copy.go")},
+			map[string]interface{}{"op": "move", "path": filepath.Join(dir, "src", "copy.go"), "destination": filepath.Join(dir, "moved.go")},
+			map[string]interface{}{"op": "write", "path": filepath.Join(dir, "tmp.txt"), "content": "x"},
+			map[string]interface{}{"op": "delete", "path": filepath.Join(dir, "tmp.txt")},
+		}})
+		assert.Equal(t, true, out["success"])
+		assert.Equal(t, []string{"ok", "ok", "ok", "ok", "ok", "ok"}, statuses(out))
+		data, err := os.ReadFile(filepath.Join(dir, "moved.go"))
+		require.NoError(t, err)
+		assert.Equal(t, "package main\n", string(data))
+		assert.NoFileExists(t, filepath.Join(dir, "tmp.txt"))
+	})
+
+	t.Run("continue and fail fast", func(t *testing.T) {
+		ops := []interface{}{
+			map[string]interface{}{"op": "delete", "path": filepath.Join(tempDir, "missing")},
+			map[string]interface{}{"op": "write", "path": filepath.Join(tempDir, "after.txt"), "content": "x"},
+		}
+		out := batch(map[string]interface{}{"operations": ops})
+		assert.Equal(t, false, out["success"])
+		assert.Equal(t, []string{"failed", "ok"}, statuses(out))
+
+		require.NoError(t, os.Remove(filepath.Join(tempDir, "after.txt")))
+		out = batch(map[string]interface{}{"operations": ops, "mode": "fail_fast"})
+		assert.Equal(t, []string{"failed", "skipped"}, statuses(out))
+		assert.NoFileExists(t, filepath.Join(tempDir, "after.txt"))
+	})
+
+	t.Run("transactional rollback", func(t *testing.T) {
+		dir := filepath.Join(tempDir, "tx")
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		existing := filepath.Join(dir, "existing.txt")
+		doomed := filepath.Join(dir, "doomed.txt")
+		source := filepath.Join(dir, "source.txt")
+		require.NoError(t, os.WriteFile(existing, []byte("original"), 0644))
+		require.NoError(t, os.WriteFile(doomed, []byte("keep me"), 0644))
+		require.NoError(t, os.WriteFile(source, []byte("source"), 0644))
+
+		out := batch(map[string]interface{}{"mode": "transactional", "operations": []interface{}{
+			map[string]interface{}{"op": "write", "path": existing, "content": "changed"},
+			map[string]interface{}{"op": "write", "path": filepath.Join(dir, "new", "deep", "file.txt"), "content": "new"},
+			map[string]interface{}{"op": "delete", "path": doomed},
+			map[string]interface{}{"op": "move", "path": source, "destination": filepath.Join(dir, "moved.txt")},
+			map[string]interface{}{"op": "copy", "path": filepath.Join(dir, "missing"), "destination": filepath.Join(dir, "x")},
+			map[string]interface{}{"op": "mkdir", "path": filepath.Join(dir, "never")},
+		}})
+		assert.Equal(t, false, out["success"])
+		assert.Equal(t, true, out["rolled_back"])
+		assert.Equal(t, []string{"rolled_back", "rolled_back", "rolled_back", "rolled_back", "failed", "skipped"}, statuses(out))
+
+		data, _ := os.ReadFile(existing)
+		assert.Equal(t, "original", string(data))
+		data, _ = os.ReadFile(doomed)
+		assert.Equal(t, "keep me", string(data))
+		data, _ = os.ReadFile(source)
+		assert.Equal(t, "source", string(data))
+		assert.NoDirExists(t, filepath.Join(dir, "new"))
+		assert.NoFileExists(t, filepath.Join(dir, "moved.txt"))
+		entries, _ := os.ReadDir(dir)
+		assert.Len(t, entries, 3, "backups are cleaned up")
+	})
+
+	t.Run("invalid operations", func(t *testing.T) {
+		for _, op := range []map[string]interface{}{
+			{"op": "chmod", "path": tempDir},
+			{"op": "copy", "path": tempDir},
+			{"op": "write"},
+		} {
+			_, err := server.batchTool().Handler(context.Background(), map[string]interface{}{"operations": []interface{}{op}})
+			assert.Error(t, err, op)
+		}
+		_, err := server.batchTool().Handler(context.Background(), map[string]interface{}{"mode": "transactional",
+			"operations": []interface{}{map[string]interface{}{"op": "delete", "path": tempDir, "recursive": true}}})
+		assert.Error(t, err)
+	})
+}
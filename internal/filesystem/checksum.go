@@ -0,0 +1,136 @@
+package filesystem
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxChecksumFiles bounds how many files of a directory file_checksum hashes.
+const maxChecksumFiles = 10000
+
+type FileDigest struct {
+	Path      string `json:"path"`
+	Digest    string `json:"digest,omitempty"`
+	SizeBytes int64  `json:"size_bytes"`
+	Error     string `json:"error,omitempty"`
+}
+
+func (s *Server) fileChecksumTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "file_checksum",
+		Description: "Compute the MD5, SHA-1 or SHA-256 digest of a file, or of every regular file under a directory, to verify downloads or compare build artifacts",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":      mcp.StringProperty("Absolute path to a file or directory"),
+				"algorithm": mcp.StringProperty("md5, sha1 or sha256 (default: sha256)"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleFileChecksum),
+	}
+}
+
+type fileChecksumArgs struct {
+	Path      string `json:"path" validate:"required"`
+	Algorithm string `json:"algorithm" default:"sha256" validate:"oneof=md5 sha1 sha256"`
+}
+
+func (s *Server) handleFileChecksum(ctx context.Context, args fileChecksumArgs) (*mcp.ToolResult, error) {
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", common.ErrNotFound, args.Path)
+		}
+		return nil, err
+	}
+
+	if !info.IsDir() {
+		digest, err := checksumFile(absPath, args.Algorithm)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.JSONResult(map[string]interface{}{
+			"path":       absPath,
+			"algorithm":  args.Algorithm,
+			"digest":     digest,
+			"size_bytes": info.Size(),
+		})
+	}
+
+	files := []FileDigest{}
+	truncated := false
+	err = filepath.WalkDir(absPath, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		if s.validator.ValidatePath(path) != nil {
+			return nil
+		}
+		if len(files) >= maxChecksumFiles {
+			truncated = true
+			return filepath.SkipAll
+		}
+		rel, _ := filepath.Rel(absPath, path)
+		entry := FileDigest{Path: filepath.ToSlash(rel)}
+		if info, err := d.Info(); err == nil {
+			entry.SizeBytes = info.Size()
+		}
+		if entry.Digest, err = checksumFile(path, args.Algorithm); err != nil {
+			entry.Error = err.Error()
+		}
+		files = append(files, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"path":      absPath,
+		"algorithm": args.Algorithm,
+		"files":     files,
+		"count":     len(files),
+		"truncated": truncated,
+	})
+}
+
+func checksumFile(path, algorithm string) (string, error) {
+	var h hash.Hash
+	switch algorithm {
+	case "md5":
+		h = md5.New()
+	case "sha1":
+		h = sha1.New()
+	default:
+		h = sha256.New()
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,56 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileChecksum(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	file := filepath.Join(tempDir, "hello.txt")
+	require.NoError(t, os.WriteFile(file, []byte("hello\n"), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "sub"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "sub", "empty"), nil, 0644))
+
+	checksum := func(params map[string]interface{}) map[string]interface{} {
+		result, err := server.fileChecksumTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out
+	}
+
+	t.Run("algorithms", func(t *testing.T) {
+		for algorithm, want := range map[string]string{
+			"md5":    "b1946ac92492d2347c6235b4d2611184",
+			"sha1":   "f572d396fae9206628714fb2ce00f72e94f2258f",
+			"sha256": "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03",
+		} {
+			out := checksum(map[string]interface{}{"path": file, "algorithm": algorithm})
+			assert.Equal(t, want, out["digest"], algorithm)
+		}
+		assert.Equal(t, "5891b5b522d5df086d0ff0b110fbd9d21bb4fc7163af34d08286a2e846f6be03",
+			checksum(map[string]interface{}{"path": file})["digest"])
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		out := checksum(map[string]interface{}{"path": tempDir, "algorithm": "md5"})
+		assert.EqualValues(t, 2, out["count"])
+		files := out["files"].([]interface{})
+		assert.Equal(t, "hello.txt", files[0].(map[string]interface{})["path"])
+		assert.Equal(t, "sub/empty", files[1].(map[string]interface{})["path"])
+		assert.Equal(t, "d41d8cd98f00b204e9800998ecf8427e", files[1].(map[string]interface{})["digest"])
+	})
+
+	t.Run("unknown algorithm", func(t *testing.T) {
+		_, err := server.fileChecksumTool().Handler(context.Background(), map[string]interface{}{"path": file, "algorithm": "crc32"})
+		assert.Error(t, err)
+	})
+}
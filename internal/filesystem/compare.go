@@ -0,0 +1,252 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"unicode/utf8"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxCompareEntries bounds how many paths a directory comparison reports.
+const maxCompareEntries = 10000
+
+type FileComparison struct {
+	Path   string `json:"path"`
+	Status string `json:"status"` // added, removed, modified, type_changed
+	Diff   string `json:"diff,omitempty"`
+}
+
+func (s *Server) compareFilesTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "compare_files",
+		Description: "Compare two files as a unified diff, or two directory trees as a list of added, removed and modified paths with a diff for each modified text file",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"old_path":     mcp.StringProperty("Absolute path to the original file or directory"),
+				"new_path":     mcp.StringProperty("Absolute path to the changed file or directory"),
+				"context":      mcp.IntProperty("Unchanged lines shown around each change (default: 3)"),
+				"include_diff": mcp.BoolProperty("For directories, include the diff of each modified file (default: true)"),
+			},
+			[]string{"old_path", "new_path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleCompareFiles),
+	}
+}
+
+type compareFilesArgs struct {
+	OldPath     string `json:"old_path" validate:"required"`
+	NewPath     string `json:"new_path" validate:"required"`
+	Context     int    `json:"context" default:"3" validate:"min=0,max=100"`
+	IncludeDiff bool   `json:"include_diff" default:"true"`
+}
+
+func (s *Server) handleCompareFiles(ctx context.Context, args compareFilesArgs) (*mcp.ToolResult, error) {
+	oldPath, err := s.validator.ResolvePath(args.OldPath)
+	if err != nil {
+		return nil, err
+	}
+	newPath, err := s.validator.ResolvePath(args.NewPath)
+	if err != nil {
+		return nil, err
+	}
+	oldInfo, err := statExisting(oldPath, args.OldPath)
+	if err != nil {
+		return nil, err
+	}
+	newInfo, err := statExisting(newPath, args.NewPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if oldInfo.IsDir() != newInfo.IsDir() {
+		return nil, fmt.Errorf("%w: cannot compare a file with a directory", common.ErrInvalidInput)
+	}
+	if !oldInfo.IsDir() {
+		diff, identical, err := s.diffFiles(oldPath, newPath, args.OldPath, args.NewPath, args.Context)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.JSONResult(map[string]interface{}{
+			"old_path":  oldPath,
+			"new_path":  newPath,
+			"identical": identical,
+			"diff":      diff,
+		})
+	}
+
+	oldFiles, err := s.listTree(ctx, oldPath)
+	if err != nil {
+		return nil, err
+	}
+	newFiles, err := s.listTree(ctx, newPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var paths []string
+	for p := range oldFiles {
+		paths = append(paths, p)
+	}
+	for p := range newFiles {
+		if _, ok := oldFiles[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	changes := []FileComparison{}
+	counts := map[string]int{"added": 0, "removed": 0, "modified": 0, "type_changed": 0, "identical": 0}
+	truncated := false
+	for _, p := range paths {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		oldType, inOld := oldFiles[p]
+		newType, inNew := newFiles[p]
+		change := FileComparison{Path: p}
+		switch {
+		case !inOld:
+			change.Status = "added"
+		case !inNew:
+			change.Status = "removed"
+		case oldType != newType:
+			change.Status = "type_changed"
+		case oldType.IsDir():
+			continue
+		case oldType&fs.ModeSymlink != 0:
+			oldTarget, _ := os.Readlink(filepath.Join(oldPath, p))
+			newTarget, _ := os.Readlink(filepath.Join(newPath, p))
+			if oldTarget == newTarget {
+				counts["identical"]++
+				continue
+			}
+			change.Status = "modified"
+			if args.IncludeDiff {
+				change.Diff = fmt.Sprintf("Symbolic link %s changed from %s to %s\n", p, oldTarget, newTarget)
+			}
+		case !oldType.IsRegular():
+			continue
+		default:
+			a, b := filepath.Join(oldPath, p), filepath.Join(newPath, p)
+			diff, identical, err := s.diffFiles(a, b, filepath.Join(args.OldPath, p), filepath.Join(args.NewPath, p), args.Context)
+			if err != nil {
+				return nil, err
+			}
+			if identical {
+				counts["identical"]++
+				continue
+			}
+			change.Status = "modified"
+			if args.IncludeDiff {
+				change.Diff = diff
+			}
+		}
+		counts[change.Status]++
+		if len(changes) >= maxCompareEntries {
+			truncated = true
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"old_path":  oldPath,
+		"new_path":  newPath,
+		"identical": len(changes) == 0 && !truncated,
+		"changes":   changes,
+		"summary":   counts,
+		"truncated": truncated,
+	})
+}
+
+func statExisting(absPath, path string) (os.FileInfo, error) {
+	info, err := os.Stat(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", common.ErrNotFound, path)
+		}
+		return nil, err
+	}
+	return info, nil
+}
+
+// diffFiles returns the unified diff between two files, labelled with the
+// names given. Binary files and files over the size limit are compared by
+// content only, with a one-line note as the diff.
+func (s *Server) diffFiles(a, b, nameA, nameB string, context int) (string, bool, error) {
+	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return "", false, err
+	}
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return "", false, err
+	}
+	if infoA.Size() > maxSize || infoB.Size() > maxSize {
+		if infoA.Size() != infoB.Size() {
+			return fmt.Sprintf("Files %s and %s differ\n", nameA, nameB), false, nil
+		}
+		sumA, err := checksumFile(a, "sha256")
+		if err != nil {
+			return "", false, err
+		}
+		sumB, err := checksumFile(b, "sha256")
+		if err != nil {
+			return "", false, err
+		}
+		if sumA == sumB {
+			return "", true, nil
+		}
+		return fmt.Sprintf("Files %s and %s differ\n", nameA, nameB), false, nil
+	}
+
+	dataA, err := os.ReadFile(a)
+	if err != nil {
+		return "", false, err
+	}
+	dataB, err := os.ReadFile(b)
+	if err != nil {
+		return "", false, err
+	}
+	if bytes.Equal(dataA, dataB) {
+		return "", true, nil
+	}
+	if !utf8.Valid(dataA) || !utf8.Valid(dataB) {
+		return fmt.Sprintf("Binary files %s and %s differ\n", nameA, nameB), false, nil
+	}
+	hunks := makeHunks(diffLines(splitLines(string(dataA)), splitLines(string(dataB))), context)
+	return formatUnified(nameA, nameB, hunks), false, nil
+}
+
+// listTree maps the slash-separated relative path of everything under root
+// to its type. Symlinks are not followed.
+func (s *Server) listTree(ctx context.Context, root string) (map[string]fs.FileMode, error) {
+	entries := make(map[string]fs.FileMode)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil || path == root {
+			return nil
+		}
+		if s.validator.ValidatePath(path) != nil {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(root, path)
+		entries[filepath.ToSlash(rel)] = d.Type()
+		return nil
+	})
+	return entries, err
+}
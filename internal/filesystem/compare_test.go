@@ -0,0 +1,94 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+func TestCompareFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	write := func(rel, content string) string {
+		path := filepath.Join(tempDir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	compare := func(params map[string]interface{}) map[string]interface{} {
+		result, err := server.compareFilesTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out
+	}
+
+	t.Run("files", func(t *testing.T) {
+		a := write("a.txt", "one\ntwo\nthree\n")
+		b := write("b.txt", "one\n2\nthree\n")
+		out := compare(map[string]interface{}{"old_path": a, "new_path": b})
+		assert.Equal(t, false, out["identical"])
+		assert.Equal(t, "--- "+a+"\n+++ "+b+"\n@@ -1,3 +1,3 @@\n one\n-two\n+2\n three\n", out["diff"])
+
+		out = compare(map[string]interface{}{"old_path": a, "new_path": b, "context": 0})
+		assert.Equal(t, "--- "+a+"\n+++ "+b+"\n@@ -2 +2 @@\n-two\n+2\n", out["diff"])
+
+		out = compare(map[string]interface{}{"old_path": a, "new_path": a})
+		assert.Equal(t, true, out["identical"])
+		assert.Equal(t, "", out["diff"])
+	})
+
+	t.Run("binary files", func(t *testing.T) {
+		a := write("a.bin", "\xff\x00")
+		b := write("b.bin", "\xff\x01")
+		out := compare(map[string]interface{}{"old_path": a, "new_path": b})
+		assert.Contains(t, out["diff"], "Binary files")
+	})
+
+	t.Run("directories", func(t *testing.T) {
+		write("old/same.txt", "same\n")
+		write("old/changed.txt", "before\n")
+		write("old/removed.txt", "gone\n")
+		write("old/kind", "file\n")
+		write("new/same.txt", "same\n")
+		write("new/changed.txt", "after\n")
+		write("new/sub/added.txt", "new\n")
+		require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "new", "kind"), 0755))
+
+		out := compare(map[string]interface{}{
+			"old_path": filepath.Join(tempDir, "old"),
+			"new_path": filepath.Join(tempDir, "new"),
+		})
+		var statuses = map[string]string{}
+		for _, c := range out["changes"].([]interface{}) {
+			change := c.(map[string]interface{})
+			statuses[change["path"].(string)] = change["status"].(string)
+			if change["path"] == "changed.txt" {
+				assert.Contains(t, change["diff"], "-before\n+after\n")
+			}
+		}
+		assert.Equal(t, map[string]string{
+			"changed.txt":   "modified",
+			"removed.txt":   "removed",
+			"kind":          "type_changed",
+			"sub":           "added",
+			"sub/added.txt": "added",
+		}, statuses)
+		assert.EqualValues(t, 1, out["summary"].(map[string]interface{})["identical"])
+	})
+
+	t.Run("file against directory", func(t *testing.T) {
+		_, err := server.compareFilesTool().Handler(context.Background(), map[string]interface{}{
+			"old_path": filepath.Join(tempDir, "a.txt"),
+			"new_path": filepath.Join(tempDir, "old"),
+		})
+		assert.ErrorIs(t, err, common.ErrInvalidInput)
+	})
+}
@@ -0,0 +1,233 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// copyProgressInterval is how often copy_directory reports progress.
+const copyProgressInterval = 500 * time.Millisecond
+
+func (s *Server) copyDirectoryTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "copy_directory",
+		Description: "Copy a directory and everything under it, keeping file permissions. Symbolic links are copied as links by default, or followed or skipped. Sends progress notifications as files are copied",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"source":      mcp.StringProperty("Absolute path to the directory to copy"),
+				"destination": mcp.StringProperty("Absolute path of the copy"),
+				"symlinks":    mcp.StringProperty("preserve to copy links as links, follow to copy what they point to if it is inside the allowed paths, or skip (default: preserve)"),
+				"exclude":     mcp.ArrayProperty("string", "Patterns of paths not to copy, in .gitignore syntax, e.g. node_modules/ or *.log"),
+				"overwrite":   mcp.BoolProperty("Copy into an existing destination, replacing files with the same names"),
+			},
+			[]string{"source", "destination"},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleCopyDirectory),
+	}
+}
+
+type copyDirectoryArgs struct {
+	Source      string   `json:"source" validate:"required"`
+	Destination string   `json:"destination" validate:"required"`
+	Symlinks    string   `json:"symlinks" default:"preserve" validate:"oneof=preserve follow skip"`
+	Exclude     []string `json:"exclude"`
+	Overwrite   bool     `json:"overwrite"`
+}
+
+// directoryCopy is the state of one copy_directory call.
+type directoryCopy struct {
+	s      *Server
+	args   copyDirectoryArgs
+	ignore *ignoreMatcher
+	// visited holds the resolved directories copied, so that following
+	// links cannot loop.
+	visited  map[string]bool
+	reported time.Time
+
+	files, directories, symlinks int
+	excluded, skipped            int
+	bytes                        int64
+}
+
+func (s *Server) handleCopyDirectory(ctx context.Context, args copyDirectoryArgs) (*mcp.ToolResult, error) {
+	if args.Symlinks == "follow" && !s.config.FollowSymlinks {
+		return nil, fmt.Errorf("%w: following symlinks is disabled by filesystem.follow_symlinks", common.ErrPathNotAllowed)
+	}
+	srcPath, err := s.validator.ResolvePath(args.Source)
+	if err != nil {
+		return nil, err
+	}
+	info, err := statExisting(srcPath, args.Source)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", common.ErrNotADirectory, args.Source)
+	}
+	dstPath, err := filepath.Abs(args.Destination)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.ValidatePath(dstPath); err != nil {
+		return nil, err
+	}
+	if resolved, _ := filepath.EvalSymlinks(srcPath); common.IsWithin(common.ResolveExisting(dstPath), resolved) {
+		return nil, &mcp.ParamError{Param: "destination", Message: "destination must not be inside source"}
+	}
+	if _, err := os.Lstat(dstPath); err == nil && !args.Overwrite {
+		return nil, fmt.Errorf("%w: %s; set overwrite to copy into it", common.ErrAlreadyExists, args.Destination)
+	}
+	ignore, err := s.newIgnoreMatcher(srcPath, args.Exclude, true)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &directoryCopy{s: s, args: args, ignore: ignore, visited: make(map[string]bool), reported: time.Now()}
+	if err := c.copyDir(ctx, srcPath, dstPath, info.Mode()); err != nil {
+		return nil, fmt.Errorf("%w (copied %d files before stopping)", err, c.files)
+	}
+	mcp.ReportProgress(ctx, float64(c.files), float64(c.files), c.progress())
+	return mcp.JSONResult(map[string]interface{}{
+		"source":      srcPath,
+		"destination": dstPath,
+		"files":       c.files,
+		"directories": c.directories,
+		"symlinks":    c.symlinks,
+		"bytes":       c.bytes,
+		"excluded":    c.excluded,
+		"skipped":     c.skipped,
+	})
+}
+
+func (c *directoryCopy) progress() string {
+	return fmt.Sprintf("copied %d files, %s", c.files, formatSize(c.bytes))
+}
+
+// copyDir copies the directory src, whose path as walked is under the
+// source root even when it was reached through a link, to dst.
+func (c *directoryCopy) copyDir(ctx context.Context, src, dst string, mode fs.FileMode) error {
+	if resolved, err := filepath.EvalSymlinks(src); err == nil {
+		if c.visited[resolved] {
+			c.skipped++
+			return nil
+		}
+		c.visited[resolved] = true
+	}
+	if err := os.MkdirAll(dst, mode.Perm()|0700); err != nil {
+		return err
+	}
+	c.directories++
+
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := c.copyEntry(ctx, filepath.Join(src, entry.Name()), filepath.Join(dst, entry.Name())); err != nil {
+			return err
+		}
+		if time.Since(c.reported) >= copyProgressInterval {
+			c.reported = time.Now()
+			mcp.ReportProgress(ctx, float64(c.files), 0, c.progress())
+		}
+	}
+	return os.Chmod(dst, mode.Perm())
+}
+
+func (c *directoryCopy) copyEntry(ctx context.Context, src, dst string) error {
+	info, err := os.Lstat(src)
+	if err != nil {
+		return err
+	}
+	if c.ignore.ignored(src, info.IsDir()) {
+		c.excluded++
+		return nil
+	}
+	if c.s.validator.ValidatePath(dst) != nil {
+		// A denied name such as **/.env is not written in the copy either.
+		c.skipped++
+		return nil
+	}
+
+	if info.Mode()&fs.ModeSymlink != 0 {
+		switch c.args.Symlinks {
+		case "skip":
+			c.skipped++
+			return nil
+		case "preserve":
+			target, err := os.Readlink(src)
+			if err != nil {
+				return err
+			}
+			os.Remove(dst)
+			if err := os.Symlink(target, dst); err != nil {
+				return err
+			}
+			c.symlinks++
+			return nil
+		}
+		resolved, err := filepath.EvalSymlinks(src)
+		if err != nil || c.s.validator.ValidatePath(resolved) != nil {
+			// Dangling, or pointing outside the allowed paths.
+			c.skipped++
+			return nil
+		}
+		if info, err = os.Stat(resolved); err != nil {
+			return err
+		}
+	} else if c.s.validator.ValidatePath(src) != nil {
+		c.skipped++
+		return nil
+	}
+
+	switch {
+	case info.IsDir():
+		return c.copyDir(ctx, src, dst, info.Mode())
+	case info.Mode().IsRegular():
+		n, err := copyRegularFile(src, dst, info.Mode())
+		if err != nil {
+			return err
+		}
+		c.files++
+		c.bytes += n
+	default:
+		// Devices, sockets and pipes.
+		c.skipped++
+	}
+	return nil
+}
+
+// copyRegularFile copies src to dst with mode, replacing whatever is at
+// dst rather than writing through it.
+func copyRegularFile(src, dst string, mode fs.FileMode) (int64, error) {
+	in, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer in.Close()
+	os.Remove(dst)
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm()|0200)
+	if err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(out, in)
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(dst, mode.Perm())
+	}
+	return n, err
+}
@@ -0,0 +1,125 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCopyDirectory(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	src := filepath.Join(tempDir, "src")
+	shared := filepath.Join(tempDir, "shared")
+	for path, content := range map[string]string{
+		"src/main.go":             "package main\n",
+		"src/bin/run.sh":          "#!/bin/sh\n",
+		"src/node_modules/x.js":   "x",
+		"src/logs/debug.log":      "log",
+		"shared/config/app.yaml":  "a: 1\n",
+		"shared/config/more.yaml": "b: 2\n",
+	} {
+		full := filepath.Join(tempDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+	require.NoError(t, os.Chmod(filepath.Join(src, "bin/run.sh"), 0755))
+	require.NoError(t, os.Symlink("main.go", filepath.Join(src, "link.go")))
+	require.NoError(t, os.Symlink(filepath.Join(shared, "config"), filepath.Join(src, "config")))
+	// A loop back to the source.
+	require.NoError(t, os.Symlink("..", filepath.Join(src, "bin/up")))
+
+	copyDir := func(params map[string]interface{}) (map[string]interface{}, error) {
+		params["source"] = src
+		result, err := server.copyDirectoryTool().Handler(context.Background(), params)
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out, nil
+	}
+
+	t.Run("preserve links", func(t *testing.T) {
+		dst := filepath.Join(tempDir, "preserved")
+		out, err := copyDir(map[string]interface{}{"destination": dst, "exclude": []interface{}{"node_modules/", "*.log"}})
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, out["files"])
+		assert.EqualValues(t, 3, out["symlinks"])
+		assert.EqualValues(t, 2, out["excluded"])
+
+		content, err := os.ReadFile(filepath.Join(dst, "main.go"))
+		require.NoError(t, err)
+		assert.Equal(t, "package main\n", string(content))
+		info, err := os.Stat(filepath.Join(dst, "bin/run.sh"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0755), info.Mode().Perm())
+		target, err := os.Readlink(filepath.Join(dst, "link.go"))
+		require.NoError(t, err)
+		assert.Equal(t, "main.go", target)
+		assert.NoDirExists(t, filepath.Join(dst, "node_modules"))
+		assert.NoFileExists(t, filepath.Join(dst, "logs/debug.log"))
+	})
+
+	t.Run("follow links", func(t *testing.T) {
+		dst := filepath.Join(tempDir, "followed")
+		out, err := copyDir(map[string]interface{}{"destination": dst, "symlinks": "follow"})
+		require.NoError(t, err)
+		// main.go, link.go, run.sh, x.js, debug.log and the two linked configs.
+		assert.EqualValues(t, 7, out["files"])
+		assert.EqualValues(t, 0, out["symlinks"])
+		assert.EqualValues(t, 1, out["skipped"], "the loop is copied once")
+
+		info, err := os.Lstat(filepath.Join(dst, "config/app.yaml"))
+		require.NoError(t, err)
+		assert.True(t, info.Mode().IsRegular())
+		info, err = os.Lstat(filepath.Join(dst, "link.go"))
+		require.NoError(t, err)
+		assert.True(t, info.Mode().IsRegular())
+	})
+
+	t.Run("skip links", func(t *testing.T) {
+		dst := filepath.Join(tempDir, "skipped")
+		out, err := copyDir(map[string]interface{}{"destination": dst, "symlinks": "skip"})
+		require.NoError(t, err)
+		assert.EqualValues(t, 4, out["files"])
+		assert.EqualValues(t, 3, out["skipped"])
+		assert.NoFileExists(t, filepath.Join(dst, "link.go"))
+	})
+
+	t.Run("existing destination", func(t *testing.T) {
+		dst := filepath.Join(tempDir, "existing")
+		require.NoError(t, os.MkdirAll(dst, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dst, "main.go"), []byte("old"), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(dst, "keep.txt"), []byte("keep"), 0644))
+
+		_, err := copyDir(map[string]interface{}{"destination": dst})
+		assert.ErrorIs(t, err, common.ErrAlreadyExists)
+
+		_, err = copyDir(map[string]interface{}{"destination": dst, "overwrite": true})
+		require.NoError(t, err)
+		content, _ := os.ReadFile(filepath.Join(dst, "main.go"))
+		assert.Equal(t, "package main\n", string(content))
+		assert.FileExists(t, filepath.Join(dst, "keep.txt"))
+	})
+
+	t.Run("destination inside source", func(t *testing.T) {
+		_, err := copyDir(map[string]interface{}{"destination": filepath.Join(src, "copy")})
+		assert.Error(t, err)
+		assert.NoDirExists(t, filepath.Join(src, "copy"))
+	})
+
+	t.Run("source is a file", func(t *testing.T) {
+		_, err := server.copyDirectoryTool().Handler(context.Background(), map[string]interface{}{
+			"source":      filepath.Join(src, "main.go"),
+			"destination": filepath.Join(tempDir, "file-copy"),
+		})
+		assert.ErrorIs(t, err, common.ErrNotADirectory)
+	})
+}
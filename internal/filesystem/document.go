@@ -0,0 +1,270 @@
+package filesystem
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type DocumentPage struct {
+	Page int    `json:"page"`
+	Text string `json:"text"`
+}
+
+func (s *Server) extractDocumentTextTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "extract_document_text",
+		Description: "Extract the text of a PDF or Word (.docx) document, page by page. PDF text comes out in drawing order, so layout such as columns and tables may be lost; scanned PDFs have no text. Word documents are split into pages at explicit page breaks only",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":      mcp.StringProperty("Absolute path to the document"),
+				"pages":     mcp.StringProperty("Pages to extract, e.g. 1-3,7 or 10- (default: all)"),
+				"max_chars": mcp.IntProperty("Stop after this many characters of text (default: 100000, max: 1000000)"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleExtractDocumentText),
+	}
+}
+
+type extractDocumentTextArgs struct {
+	Path     string `json:"path" validate:"required"`
+	Pages    string `json:"pages"`
+	MaxChars int    `json:"max_chars" default:"100000" validate:"min=1,max=1000000"`
+}
+
+func (s *Server) handleExtractDocumentText(ctx context.Context, args extractDocumentTextArgs) (*mcp.ToolResult, error) {
+	selected, err := parsePageRanges(args.Pages)
+	if err != nil {
+		return nil, err
+	}
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := statExisting(absPath, args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", common.ErrNotAFile, args.Path)
+	}
+	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("%w: file size %d exceeds limit %d", common.ErrFileTooLarge, info.Size(), maxSize)
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, err
+	}
+	// Decompressed content is held to the archive limit.
+	maxDecoded := int64(s.config.MaxArchiveSizeMB) * 1024 * 1024
+
+	var format string
+	var texts []string
+	switch {
+	case bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-")):
+		format = "pdf"
+		doc, err := parsePDF(data, maxDecoded)
+		if err != nil {
+			return nil, err
+		}
+		pages := doc.pages()
+		texts = make([]string, len(pages))
+		for i, page := range pages {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			if selected.contains(i + 1) {
+				if texts[i], err = doc.pageText(page); err != nil {
+					return nil, err
+				}
+			}
+		}
+	case bytes.HasPrefix(data, []byte("PK\x03\x04")) && strings.EqualFold(filepath.Ext(absPath), ".docx"):
+		format = "docx"
+		if texts, err = docxPages(data, maxDecoded); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s is not a PDF or .docx document", common.ErrInvalidInput, args.Path)
+	}
+
+	var pages []DocumentPage
+	chars := 0
+	truncated := false
+	for i, text := range texts {
+		if !selected.contains(i + 1) {
+			continue
+		}
+		if n := utf8.RuneCountInString(text); chars+n > args.MaxChars {
+			text = string([]rune(text)[:args.MaxChars-chars])
+			truncated = true
+		}
+		chars += utf8.RuneCountInString(text)
+		pages = append(pages, DocumentPage{Page: i + 1, Text: text})
+		if truncated {
+			break
+		}
+	}
+	if pages == nil {
+		pages = []DocumentPage{}
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"path":       absPath,
+		"format":     format,
+		"page_count": len(texts),
+		"pages":      pages,
+		"characters": chars,
+		"truncated":  truncated,
+	})
+}
+
+// pageRanges is a set of 1-based page ranges; nil means every page.
+type pageRanges [][2]int
+
+func (r pageRanges) contains(page int) bool {
+	if r == nil {
+		return true
+	}
+	for _, pr := range r {
+		if page >= pr[0] && (pr[1] == 0 || page <= pr[1]) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePageRanges parses a list like "1-3,7,10-"; an open range has an end
+// of 0.
+func parsePageRanges(spec string) (pageRanges, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	bad := &mcp.ParamError{Param: "pages", Message: fmt.Sprintf("invalid page range %q; use a list like 1-3,7,10-", spec)}
+	var ranges pageRanges
+	for _, part := range strings.Split(spec, ",") {
+		from, to, isRange := strings.Cut(strings.TrimSpace(part), "-")
+		first, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil || first < 1 {
+			return nil, bad
+		}
+		last := first
+		if isRange {
+			last = 0
+			if to = strings.TrimSpace(to); to != "" {
+				if last, err = strconv.Atoi(to); err != nil || last < first {
+					return nil, bad
+				}
+			}
+		}
+		ranges = append(ranges, [2]int{first, last})
+	}
+	return ranges, nil
+}
+
+// docxPages extracts the text of a Word document, split at page breaks.
+// maxDecoded bounds the size of the document XML.
+func docxPages(data []byte, maxDecoded int64) ([]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: not a valid .docx file: %v", common.ErrInvalidInput, err)
+	}
+	var body io.ReadCloser
+	for _, f := range zr.File {
+		if f.Name != "word/document.xml" {
+			continue
+		}
+		if int64(f.UncompressedSize64) > maxDecoded {
+			return nil, fmt.Errorf("%w: document content exceeds the limit of %d bytes", common.ErrFileTooLarge, maxDecoded)
+		}
+		if body, err = f.Open(); err != nil {
+			return nil, err
+		}
+		defer body.Close()
+	}
+	if body == nil {
+		return nil, fmt.Errorf("%w: .docx file has no word/document.xml", common.ErrInvalidInput)
+	}
+
+	pages := []string{""}
+	var text strings.Builder
+	inText := false
+	// Paragraphs in table cells run together; rows end lines.
+	cells := 0
+	decoder := xml.NewDecoder(io.LimitReader(body, maxDecoded))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: reading document XML: %v", common.ErrInvalidInput, err)
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tc":
+				cells++
+			case "tab":
+				// Tab stops in paragraph properties have a position.
+				if attrValue(t, "pos") == "" {
+					text.WriteByte('\t')
+				}
+			case "br", "cr":
+				if attrValue(t, "type") == "page" {
+					pages[len(pages)-1] = strings.TrimSpace(text.String())
+					pages = append(pages, "")
+					text.Reset()
+				} else {
+					text.WriteByte('\n')
+				}
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if cells > 0 {
+					text.WriteByte(' ')
+				} else {
+					text.WriteByte('\n')
+				}
+			case "tc":
+				cells--
+				text.WriteByte('\t')
+			case "tr":
+				text.WriteByte('\n')
+			}
+		case xml.CharData:
+			if inText {
+				text.Write(t)
+			}
+		}
+	}
+	pages[len(pages)-1] = strings.TrimSpace(text.String())
+	return pages, nil
+}
+
+func attrValue(e xml.StartElement, name string) string {
+	for _, a := range e.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,142 @@
+package filesystem
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildPDF lays out a PDF with a page for each content stream. A stream
+// prefixed with "flate:" is compressed.
+func buildPDF(pages ...string) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.7\n")
+	kids := ""
+	for i := range pages {
+		kids += fmt.Sprintf("%d 0 R ", 10+2*i)
+	}
+	fmt.Fprintf(&buf, "1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	fmt.Fprintf(&buf, "2 0 obj\n<< /Type /Pages /Kids [%s] /Count %d /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> >>\nendobj\n", kids, len(pages))
+	fmt.Fprintf(&buf, "3 0 obj\n<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica >>\nendobj\n")
+	cmap := "/CIDInit /ProcSet findresource begin\n1 begincodespacerange <0000> <FFFF> endcodespacerange\n" +
+		"2 beginbfchar <0001> <0048> <0002> <00E9> endbfchar\n1 beginbfrange <0010> <0012> <0061> endbfrange\nendcmap"
+	fmt.Fprintf(&buf, "4 0 obj\n<< /Type /Font /Subtype /Type0 /BaseFont /X /Encoding /Identity-H /ToUnicode 5 0 R >>\nendobj\n")
+	fmt.Fprintf(&buf, "5 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", len(cmap), cmap)
+	for i, content := range pages {
+		fmt.Fprintf(&buf, "%d 0 obj\n<< /Type /Page /Parent 2 0 R /Contents %d 0 R >>\nendobj\n", 10+2*i, 11+2*i)
+		if rest, ok := bytes.CutPrefix([]byte(content), []byte("flate:")); ok {
+			var z bytes.Buffer
+			w := zlib.NewWriter(&z)
+			w.Write(rest)
+			w.Close()
+			fmt.Fprintf(&buf, "%d 0 obj\n<< /Length 99 0 R /Filter /FlateDecode >>\nstream\n%s\nendstream\nendobj\n", 11+2*i, z.Bytes())
+		} else {
+			fmt.Fprintf(&buf, "%d 0 obj\n<< /Length %d >>\nstream\n%s\nendstream\nendobj\n", 11+2*i, len(content), content)
+		}
+	}
+	buf.WriteString("trailer\n<< /Root 1 0 R >>\n%%EOF\n")
+	return buf.Bytes()
+}
+
+func buildDocx(t *testing.T, body string) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("word/document.xml")
+	require.NoError(t, err)
+	_, err = w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?>` +
+		`<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body>` + body + `</w:body></w:document>`))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestExtractDocumentText(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+
+	extract := func(name string, content []byte, params map[string]interface{}) (map[string]interface{}, error) {
+		path := filepath.Join(tempDir, name)
+		require.NoError(t, os.WriteFile(path, content, 0644))
+		if params == nil {
+			params = map[string]interface{}{}
+		}
+		params["path"] = path
+		result, err := server.extractDocumentTextTool().Handler(context.Background(), params)
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out, nil
+	}
+	pageTexts := func(out map[string]interface{}) []string {
+		var texts []string
+		for _, p := range out["pages"].([]interface{}) {
+			texts = append(texts, p.(map[string]interface{})["text"].(string))
+		}
+		return texts
+	}
+
+	pdf := buildPDF(
+		"BT /F1 12 Tf 72 720 Td (Hello \\(PDF\\)) Tj 0 -14 Td [(Second) -300 (line)] TJ ET",
+		"flate:BT /F2 12 Tf <000100020010> Tj T* <00110012> Tj ET",
+		"BT /F1 12 Tf (Third page) Tj ET",
+	)
+
+	t.Run("pdf", func(t *testing.T) {
+		out, err := extract("doc.pdf", pdf, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "pdf", out["format"])
+		assert.EqualValues(t, 3, out["page_count"])
+		assert.Equal(t, []string{"Hello (PDF)\nSecond line", "Héa\nbc", "Third page"}, pageTexts(out))
+		assert.Equal(t, false, out["truncated"])
+	})
+
+	t.Run("page ranges", func(t *testing.T) {
+		out, err := extract("doc.pdf", pdf, map[string]interface{}{"pages": "1,3-"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Hello (PDF)\nSecond line", "Third page"}, pageTexts(out))
+
+		_, err = extract("doc.pdf", pdf, map[string]interface{}{"pages": "3-1"})
+		assert.Error(t, err)
+	})
+
+	t.Run("max chars", func(t *testing.T) {
+		out, err := extract("doc.pdf", pdf, map[string]interface{}{"max_chars": 5})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"Hello"}, pageTexts(out))
+		assert.Equal(t, true, out["truncated"])
+	})
+
+	t.Run("encrypted pdf", func(t *testing.T) {
+		encrypted := bytes.Replace(pdf, []byte("<< /Root 1 0 R >>"), []byte("<< /Root 1 0 R /Encrypt 9 0 R >>"), 1)
+		_, err := extract("secret.pdf", encrypted, nil)
+		assert.ErrorContains(t, err, "encrypted")
+	})
+
+	t.Run("docx", func(t *testing.T) {
+		docx := buildDocx(t, `<w:p><w:pPr><w:tabs><w:tab w:val="left" w:pos="720"/></w:tabs></w:pPr><w:r><w:t>Title</w:t></w:r></w:p>`+
+			`<w:p><w:r><w:t xml:space="preserve">Name: </w:t></w:r><w:r><w:tab/><w:t>value</w:t></w:r></w:p>`+
+			`<w:tbl><w:tr><w:tc><w:p><w:r><w:t>a</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>b</w:t></w:r></w:p></w:tc></w:tr></w:tbl>`+
+			`<w:p><w:r><w:br w:type="page"/><w:t>Appendix</w:t></w:r></w:p>`)
+		out, err := extract("report.docx", docx, nil)
+		require.NoError(t, err)
+		assert.Equal(t, "docx", out["format"])
+		assert.EqualValues(t, 2, out["page_count"])
+		assert.Equal(t, []string{"Title\nName: \tvalue\na \tb", "Appendix"}, pageTexts(out))
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		_, err := extract("notes.txt", []byte("plain text"), nil)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,133 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type DiskUsageEntry struct {
+	Path        string `json:"path"`
+	SizeBytes   int64  `json:"size_bytes"`
+	Files       int    `json:"files"`
+	IsDirectory bool   `json:"is_directory"`
+	Depth       int    `json:"depth"`
+}
+
+func (s *Server) diskUsageTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "disk_usage",
+		Description: "Total the size of everything under a directory, like du: reports the entries down to max_depth with the size of all they contain, largest first",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":      mcp.StringProperty("Absolute path to the directory"),
+				"max_depth": mcp.IntProperty("Deepest level of entries to report; 1 is the directory's own children (default: 1)"),
+				"top":       mcp.IntProperty("Number of largest entries to return (default: 20, max: 1000)"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleDiskUsage),
+	}
+}
+
+type diskUsageArgs struct {
+	Path     string `json:"path" validate:"required"`
+	MaxDepth int    `json:"max_depth" default:"1" validate:"min=1,max=100"`
+	Top      int    `json:"top" default:"20" validate:"min=1,max=1000"`
+}
+
+func (s *Server) handleDiskUsage(ctx context.Context, args diskUsageArgs) (*mcp.ToolResult, error) {
+	root, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := statExisting(root, args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", common.ErrNotADirectory, args.Path)
+	}
+
+	entries := make(map[string]*DiskUsageEntry)
+	var totalBytes int64
+	totalFiles, skipped := 0, 0
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			skipped++
+			return nil
+		}
+		if p == root {
+			return nil
+		}
+		if s.validator.ValidatePath(p) != nil {
+			skipped++
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, _ := filepath.Rel(root, p)
+		parts := strings.Split(rel, string(filepath.Separator))
+		if len(parts) <= args.MaxDepth {
+			entries[p] = &DiskUsageEntry{Path: p, IsDirectory: d.IsDir(), Depth: len(parts)}
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			skipped++
+			return nil
+		}
+		// Symlinks count as their own small size, not their target's.
+		size := info.Size()
+		totalBytes += size
+		totalFiles++
+		for depth := 1; depth <= len(parts) && depth <= args.MaxDepth; depth++ {
+			e := entries[filepath.Join(root, filepath.Join(parts[:depth]...))]
+			e.SizeBytes += size
+			e.Files++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]DiskUsageEntry, 0, len(entries))
+	for _, e := range entries {
+		list = append(list, *e)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].SizeBytes != list[j].SizeBytes {
+			return list[i].SizeBytes > list[j].SizeBytes
+		}
+		return list[i].Path < list[j].Path
+	})
+	truncated := len(list) > args.Top
+	if truncated {
+		list = list[:args.Top]
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"path":        root,
+		"total_bytes": totalBytes,
+		"total_files": totalFiles,
+		"entries":     list,
+		"truncated":   truncated,
+		"skipped":     skipped,
+	})
+}
@@ -0,0 +1,62 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiskUsage(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "big", "nested"), 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "small"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "big", "a.bin"), []byte(strings.Repeat("a", 1000)), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "big", "nested", "b.bin"), []byte(strings.Repeat("b", 500)), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "small", "c.txt"), []byte("hello"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "top.txt"), []byte(strings.Repeat("t", 100)), 0644))
+
+	usage := func(params map[string]interface{}) map[string]interface{} {
+		result, err := server.diskUsageTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out
+	}
+
+	t.Run("children", func(t *testing.T) {
+		out := usage(map[string]interface{}{"path": tempDir})
+		assert.EqualValues(t, 1605, out["total_bytes"])
+		assert.EqualValues(t, 4, out["total_files"])
+		entries := out["entries"].([]interface{})
+		require.Len(t, entries, 3)
+		first := entries[0].(map[string]interface{})
+		assert.Equal(t, filepath.Join(tempDir, "big"), first["path"])
+		assert.EqualValues(t, 1500, first["size_bytes"])
+		assert.EqualValues(t, 2, first["files"])
+		assert.Equal(t, true, first["is_directory"])
+		assert.Equal(t, filepath.Join(tempDir, "top.txt"), entries[1].(map[string]interface{})["path"])
+		assert.Equal(t, filepath.Join(tempDir, "small"), entries[2].(map[string]interface{})["path"])
+	})
+
+	t.Run("depth and top", func(t *testing.T) {
+		out := usage(map[string]interface{}{"path": tempDir, "max_depth": 3, "top": 3})
+		assert.Equal(t, true, out["truncated"])
+		entries := out["entries"].([]interface{})
+		require.Len(t, entries, 3)
+		assert.Equal(t, filepath.Join(tempDir, "big", "a.bin"), entries[1].(map[string]interface{})["path"])
+		assert.Equal(t, filepath.Join(tempDir, "big", "nested"), entries[2].(map[string]interface{})["path"])
+		assert.EqualValues(t, 2, entries[2].(map[string]interface{})["depth"])
+	})
+
+	t.Run("not a directory", func(t *testing.T) {
+		_, err := server.diskUsageTool().Handler(context.Background(), map[string]interface{}{"path": filepath.Join(tempDir, "top.txt")})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,367 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) editFileTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "edit_file",
+		Description: "Edit a file in place with exact text replacements and/or a unified diff patch, without rewriting it whole. Replacements are applied first, then the patch; nothing is written unless every step applies. Returns the resulting hunks",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path": mcp.StringProperty("Absolute path to the file"),
+				"edits": mcp.ObjectArrayProperty("Replacements applied in order", map[string]interface{}{
+					"old_text":             mcp.StringProperty("Exact text to replace"),
+					"new_text":             mcp.StringProperty("Replacement text"),
+					"expected_occurrences": mcp.IntProperty("How many times old_text must occur; all of them are replaced (default: 1)"),
+				}),
+				"patch":                 mcp.StringProperty("Unified diff to apply, as produced by diff -u or git diff, for this file only"),
+				"dry_run":               mcp.BoolProperty("Report the hunks without writing the file"),
+				"preserve_line_endings": mcp.BoolProperty("If the file uses CRLF line endings, match edits and the patch written with LF against it and keep CRLF in the result"),
+			},
+			[]string{"path"},
+		),
+		OutputSchema: mcp.BuildOutputSchema(
+			map[string]interface{}{
+				"path":         mcp.StringProperty("Absolute path of the file"),
+				"replacements": mcp.IntProperty("Occurrences replaced by edits"),
+				"hunks":        mcp.ObjectArrayProperty("Changed regions of the file", hunkProperties()),
+				"diff":         mcp.StringProperty("The change as a unified diff"),
+				"dry_run":      mcp.BoolProperty("Whether the file was left unchanged"),
+			},
+			[]string{"path", "replacements", "hunks", "diff", "dry_run"},
+		),
+		Annotations: mcp.Destructive(),
+		Handler:     mcp.TypedHandler(s.handleEditFile),
+	}
+}
+
+type textEdit struct {
+	OldText             string `json:"old_text"`
+	NewText             string `json:"new_text"`
+	ExpectedOccurrences int    `json:"expected_occurrences"`
+}
+
+type editFileArgs struct {
+	Path                string     `json:"path" validate:"required"`
+	Edits               []textEdit `json:"edits"`
+	Patch               string     `json:"patch"`
+	DryRun              bool       `json:"dry_run"`
+	PreserveLineEndings bool       `json:"preserve_line_endings"`
+}
+
+func (s *Server) handleEditFile(ctx context.Context, args editFileArgs) (*mcp.ToolResult, error) {
+	if len(args.Edits) == 0 && args.Patch == "" {
+		return nil, fmt.Errorf("%w: give edits, a patch or both", common.ErrInvalidInput)
+	}
+
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	before, info, err := s.readForEdit(absPath, args.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	apply := applyEdits
+	if args.PreserveLineEndings {
+		apply = applyEditsKeepingEndings
+	}
+	after, replacements, err := apply(before, args.Edits, args.Patch)
+	if err != nil {
+		return nil, err
+	}
+
+	hunks := makeHunks(diffLines(splitLines(before), splitLines(after)), diffContext)
+	if !args.DryRun && after != before {
+		if err := writeFileAtomic(absPath, []byte(after), info.Mode().Perm()); err != nil {
+			return nil, err
+		}
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"path":         absPath,
+		"replacements": replacements,
+		"hunks":        nonNilHunks(hunks),
+		"diff":         formatUnified(absPath, absPath, hunks),
+		"dry_run":      args.DryRun,
+	})
+}
+
+// readForEdit reads a regular file within the size limit.
+func (s *Server) readForEdit(absPath, path string) (string, os.FileInfo, error) {
+	info, err := os.Stat(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", nil, fmt.Errorf("%w: %s", common.ErrNotFound, path)
+		}
+		return "", nil, err
+	}
+	if info.IsDir() {
+		return "", nil, fmt.Errorf("%w: %s", common.ErrNotAFile, path)
+	}
+	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
+	if info.Size() > maxSize {
+		return "", nil, fmt.Errorf("%w: file size %d exceeds limit %d", common.ErrFileTooLarge, info.Size(), maxSize)
+	}
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return "", nil, err
+	}
+	return string(content), info, nil
+}
+
+// applyEdits applies the replacements and then the patch to content,
+// returning the result and the number of occurrences replaced.
+func applyEdits(content string, edits []textEdit, patch string) (string, int, error) {
+	replacements := 0
+	for i, edit := range edits {
+		if edit.OldText == "" {
+			return "", 0, fmt.Errorf("%w: edit %d: old_text is empty", common.ErrInvalidInput, i+1)
+		}
+		expected := edit.ExpectedOccurrences
+		if expected <= 0 {
+			expected = 1
+		}
+		count := strings.Count(content, edit.OldText)
+		if count == 0 {
+			return "", 0, fmt.Errorf("%w: edit %d: old_text not found", common.ErrInvalidInput, i+1)
+		}
+		if count != expected {
+			return "", 0, fmt.Errorf("%w: edit %d: old_text occurs %d times, expected %d; include more surrounding text or set expected_occurrences",
+				common.ErrInvalidInput, i+1, count, expected)
+		}
+		content = strings.ReplaceAll(content, edit.OldText, edit.NewText)
+		replacements += count
+	}
+
+	if patch != "" {
+		hunks, err := parsePatch(patch)
+		if err != nil {
+			return "", 0, err
+		}
+		content, err = applyPatch(content, hunks)
+		if err != nil {
+			return "", 0, err
+		}
+	}
+	return content, replacements, nil
+}
+
+// patchHunk is a hunk of a unified diff: the lines it expects and the lines
+// that replace them, each with its newline.
+type patchHunk struct {
+	oldStart int
+	old, new []string
+}
+
+func parsePatch(patch string) ([]patchHunk, error) {
+	lines := strings.Split(patch, "\n")
+	var hunks []patchHunk
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.HasPrefix(line, "--- ") && len(hunks) > 0 {
+			return nil, fmt.Errorf("%w: patch changes more than one file", common.ErrInvalidInput)
+		}
+		if !strings.HasPrefix(line, "@@ ") {
+			continue // file headers and anything else outside hunks
+		}
+
+		h, oldCount, newCount, err := parseHunkHeader(line)
+		if err != nil {
+			return nil, err
+		}
+		var last *[]string
+		for oldCount > 0 || newCount > 0 || (i+1 < len(lines) && strings.HasPrefix(lines[i+1], `\`)) {
+			i++
+			if i >= len(lines) {
+				return nil, fmt.Errorf("%w: patch hunk %d is truncated", common.ErrInvalidInput, len(hunks)+1)
+			}
+			body := lines[i]
+			kind, text := byte(' '), ""
+			if body != "" {
+				kind, text = body[0], body[1:]
+			}
+			switch kind {
+			case ' ':
+				h.old = append(h.old, text+"\n")
+				h.new = append(h.new, text+"\n")
+				oldCount--
+				newCount--
+				last = nil
+			case '-':
+				h.old = append(h.old, text+"\n")
+				oldCount--
+				last = &h.old
+			case '+':
+				h.new = append(h.new, text+"\n")
+				newCount--
+				last = &h.new
+			case '\\':
+				// "\ No newline at end of file" applies to the line before.
+				if last == nil {
+					trimLast(h.old)
+					trimLast(h.new)
+				} else {
+					trimLast(*last)
+				}
+			default:
+				return nil, fmt.Errorf("%w: patch hunk %d: unexpected line %q", common.ErrInvalidInput, len(hunks)+1, body)
+			}
+			if oldCount < 0 || newCount < 0 {
+				return nil, fmt.Errorf("%w: patch hunk %d has more lines than its header says", common.ErrInvalidInput, len(hunks)+1)
+			}
+		}
+		hunks = append(hunks, h)
+	}
+	if len(hunks) == 0 {
+		return nil, fmt.Errorf("%w: patch has no hunks", common.ErrInvalidInput)
+	}
+	return hunks, nil
+}
+
+func trimLast(lines []string) {
+	if len(lines) > 0 {
+		lines[len(lines)-1] = strings.TrimSuffix(lines[len(lines)-1], "\n")
+	}
+}
+
+// parseHunkHeader parses "@@ -start,count +start,count @@".
+func parseHunkHeader(line string) (patchHunk, int, int, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 4 || fields[3] != "@@" || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return patchHunk{}, 0, 0, fmt.Errorf("%w: malformed hunk header %q", common.ErrInvalidInput, line)
+	}
+	oldStart, oldCount, err1 := parseRange(fields[1][1:])
+	_, newCount, err2 := parseRange(fields[2][1:])
+	if err1 != nil || err2 != nil {
+		return patchHunk{}, 0, 0, fmt.Errorf("%w: malformed hunk header %q", common.ErrInvalidInput, line)
+	}
+	return patchHunk{oldStart: oldStart}, oldCount, newCount, nil
+}
+
+func parseRange(r string) (int, int, error) {
+	startText, countText, hasCount := strings.Cut(r, ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, err
+	}
+	count := 1
+	if hasCount {
+		if count, err = strconv.Atoi(countText); err != nil {
+			return 0, 0, err
+		}
+	}
+	return start, count, nil
+}
+
+// applyPatch applies hunks in order. A hunk whose lines are not at the
+// position its header gives is looked for nearby, so patches made against a
+// slightly different version still apply.
+func applyPatch(content string, hunks []patchHunk) (string, error) {
+	lines := splitLines(content)
+	delta, floor := 0, 0
+	for i, h := range hunks {
+		want := h.oldStart - 1
+		if len(h.old) == 0 {
+			want = h.oldStart // an insertion goes after the line it names
+		}
+		pos := findLines(lines, h.old, want+delta, floor)
+		if pos < 0 {
+			return "", fmt.Errorf("%w: patch hunk %d (@@ -%d) does not match the file", common.ErrInvalidInput, i+1, h.oldStart)
+		}
+		rest := append([]string(nil), lines[pos+len(h.old):]...)
+		lines = append(append(lines[:pos], h.new...), rest...)
+		delta += len(h.new) - len(h.old)
+		floor = pos + len(h.new)
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// findLines returns the position of want in lines nearest to near and not
+// before floor, or -1.
+func findLines(lines, want []string, near, floor int) int {
+	near = min(max(near, floor), len(lines))
+	matches := func(pos int) bool {
+		if pos < floor || pos+len(want) > len(lines) {
+			return false
+		}
+		for i, line := range want {
+			if lines[pos+i] != line {
+				return false
+			}
+		}
+		return true
+	}
+	for dist := 0; near-dist >= floor || near+dist <= len(lines); dist++ {
+		if matches(near - dist) {
+			return near - dist
+		}
+		if dist > 0 && matches(near+dist) {
+			return near + dist
+		}
+	}
+	return -1
+}
+
+// writeFileAtomic replaces path with data through a temporary file in the
+// same directory, so readers never see a partly written file.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := stageFile(path, data, perm)
+	if err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// stageFile writes data to a temporary file next to path, ready to be
+// renamed over it.
+func stageFile(path string, data []byte, perm os.FileMode) (string, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return "", err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmp.Name(), perm)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return "", err
+	}
+	return tmp.Name(), nil
+}
+
+func hunkProperties() map[string]interface{} {
+	return map[string]interface{}{
+		"old_start": mcp.IntProperty("First line of the region in the old file"),
+		"old_lines": mcp.IntProperty("Lines of the region in the old file"),
+		"new_start": mcp.IntProperty("First line of the region in the new file"),
+		"new_lines": mcp.IntProperty("Lines of the region in the new file"),
+		"lines":     mcp.ArrayProperty("string", "Diff lines, prefixed with ' ', '-' or '+'"),
+	}
+}
+
+func nonNilHunks(hunks []Hunk) []Hunk {
+	if hunks == nil {
+		return []Hunk{}
+	}
+	return hunks
+}
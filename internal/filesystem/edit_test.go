@@ -0,0 +1,254 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+func editResult(t *testing.T, server *Server, params map[string]interface{}) (map[string]interface{}, error) {
+	result, err := server.editFileTool().Handler(context.Background(), params)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+	return out, nil
+}
+
+func TestEditFile(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	path := filepath.Join(tempDir, "main.go")
+	original := "package main\n\nfunc main() {\n\tprintln(\"hello\")\n\tprintln(\"hello\")\n}\n"
+	reset := func() { require.NoError(t, os.WriteFile(path, []byte(original), 0600)) }
+
+	t.Run("replacement", func(t *testing.T) {
+		reset()
+		out, err := editResult(t, server, map[string]interface{}{
+			"path":  path,
+			"edits": []interface{}{map[string]interface{}{"old_text": "package main", "new_text": "package app"}},
+		})
+		require.NoError(t, err)
+		assert.EqualValues(t, 1, out["replacements"])
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, "package app\n\nfunc main() {\n\tprintln(\"hello\")\n\tprintln(\"hello\")\n}\n", string(content))
+		hunks := out["hunks"].([]interface{})
+		require.Len(t, hunks, 1)
+		assert.Equal(t, []interface{}{"-package main", "+package app", " ", " func main() {", " \tprintln(\"hello\")"}, hunks[0].(map[string]interface{})["lines"])
+		assert.Contains(t, out["diff"], "@@ -1,4 +1,4 @@")
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	})
+
+	t.Run("occurrence count must match", func(t *testing.T) {
+		reset()
+		_, err := editResult(t, server, map[string]interface{}{
+			"path":  path,
+			"edits": []interface{}{map[string]interface{}{"old_text": "println(\"hello\")", "new_text": "println(\"bye\")"}},
+		})
+		assert.ErrorIs(t, err, common.ErrInvalidInput)
+		assert.Contains(t, err.Error(), "occurs 2 times, expected 1")
+
+		out, err := editResult(t, server, map[string]interface{}{
+			"path": path,
+			"edits": []interface{}{map[string]interface{}{
+				"old_text": "println(\"hello\")", "new_text": "println(\"bye\")", "expected_occurrences": 2,
+			}},
+		})
+		require.NoError(t, err)
+		assert.EqualValues(t, 2, out["replacements"])
+	})
+
+	t.Run("failed edit leaves file alone", func(t *testing.T) {
+		reset()
+		_, err := editResult(t, server, map[string]interface{}{
+			"path": path,
+			"edits": []interface{}{
+				map[string]interface{}{"old_text": "package main", "new_text": "package app"},
+				map[string]interface{}{"old_text": "missing", "new_text": "x"},
+			},
+		})
+		assert.ErrorIs(t, err, common.ErrInvalidInput)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, original, string(content))
+	})
+
+	t.Run("patch", func(t *testing.T) {
+		reset()
+		patch := "--- a/main.go\n+++ b/main.go\n@@ -3,4 +3,4 @@\n func main() {\n-\tprintln(\"hello\")\n \tprintln(\"hello\")\n+\tos.Exit(0)\n-}\n+}\n"
+		out, err := editResult(t, server, map[string]interface{}{"path": path, "patch": patch})
+		require.NoError(t, err)
+		assert.EqualValues(t, 0, out["replacements"])
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, "package main\n\nfunc main() {\n\tprintln(\"hello\")\n\tos.Exit(0)\n}\n", string(content))
+	})
+
+	t.Run("patch with offset", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("// header\n// more\n"+original), 0600))
+		patch := "@@ -1,1 +1,1 @@\n-package main\n+package app\n"
+		_, err := editResult(t, server, map[string]interface{}{"path": path, "patch": patch})
+		require.NoError(t, err)
+		content, _ := os.ReadFile(path)
+		assert.Contains(t, string(content), "// more\npackage app\n")
+	})
+
+	t.Run("patch that does not match", func(t *testing.T) {
+		reset()
+		_, err := editResult(t, server, map[string]interface{}{"path": path, "patch": "@@ -1 +1 @@\n-package other\n+package app\n"})
+		assert.ErrorIs(t, err, common.ErrInvalidInput)
+	})
+
+	t.Run("missing newline at end of file", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(path, []byte("a\nb"), 0600))
+		patch := "@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+b\n"
+		out, err := editResult(t, server, map[string]interface{}{"path": path, "patch": patch})
+		require.NoError(t, err)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, "a\nb\n", string(content))
+		assert.Contains(t, out["diff"], "-b\n\\ No newline at end of file\n+b\n")
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		reset()
+		out, err := editResult(t, server, map[string]interface{}{
+			"path":    path,
+			"edits":   []interface{}{map[string]interface{}{"old_text": "main()", "new_text": "run()"}},
+			"dry_run": true,
+		})
+		require.NoError(t, err)
+		assert.Len(t, out["hunks"], 1)
+		content, _ := os.ReadFile(path)
+		assert.Equal(t, original, string(content))
+	})
+
+	t.Run("needs edits or patch", func(t *testing.T) {
+		_, err := editResult(t, server, map[string]interface{}{"path": path})
+		assert.ErrorIs(t, err, common.ErrInvalidInput)
+	})
+
+	t.Run("outside allowed paths", func(t *testing.T) {
+		_, err := editResult(t, server, map[string]interface{}{
+			"path":  "/etc/hostname",
+			"edits": []interface{}{map[string]interface{}{"old_text": "a", "new_text": "b"}},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestMakeHunks(t *testing.T) {
+	var a, b []string
+	for i := 0; i < 20; i++ {
+		a = append(a, string(rune('a'+i))+"\n")
+	}
+	b = append(b, a...)
+	b[2] = "C\n"
+	b = append(b[:15], b[16:]...)
+
+	hunks := makeHunks(diffLines(a, b), diffContext)
+	require.Len(t, hunks, 2)
+	assert.Equal(t, Hunk{OldStart: 1, OldLines: 6, NewStart: 1, NewLines: 6,
+		Lines: []string{" a", " b", "-c", "+C", " d", " e", " f"}}, hunks[0])
+	assert.Equal(t, 13, hunks[1].OldStart)
+	assert.Equal(t, 7, hunks[1].OldLines)
+	assert.Equal(t, 6, hunks[1].NewLines)
+	assert.Equal(t, []string{" m", " n", " o", "-p", " q", " r", " s"}, hunks[1].Lines)
+
+	// The hunks turn a into b when applied as a patch.
+	patch, err := parsePatch(formatUnified("a", "b", hunks))
+	require.NoError(t, err)
+	out, err := applyPatch(strings.Join(a, ""), patch)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Join(b, ""), out)
+}
+
+func TestMultiEdit(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	a := filepath.Join(tempDir, "a.txt")
+	b := filepath.Join(tempDir, "b.txt")
+	reset := func() {
+		require.NoError(t, os.WriteFile(a, []byte("alpha\nbeta\n"), 0644))
+		require.NoError(t, os.WriteFile(b, []byte("gamma\n"), 0644))
+	}
+	edit := func(path, oldText, newText string) map[string]interface{} {
+		return map[string]interface{}{
+			"path":  path,
+			"edits": []interface{}{map[string]interface{}{"old_text": oldText, "new_text": newText}},
+		}
+	}
+	read := func(path string) string {
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(content)
+	}
+
+	t.Run("edits several files", func(t *testing.T) {
+		reset()
+		result, err := server.multiEditTool().Handler(context.Background(), map[string]interface{}{
+			"files": []interface{}{edit(a, "alpha", "ALPHA"), edit(b, "gamma", "GAMMA"), edit(a, "beta", "BETA")},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "ALPHA\nBETA\n", read(a))
+		assert.Equal(t, "GAMMA\n", read(b))
+
+		var out struct {
+			Files []struct {
+				Path         string `json:"path"`
+				Replacements int    `json:"replacements"`
+			} `json:"files"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		require.Len(t, out.Files, 2)
+		assert.Equal(t, a, out.Files[0].Path)
+		assert.Equal(t, 2, out.Files[0].Replacements)
+	})
+
+	t.Run("one failing edit changes nothing", func(t *testing.T) {
+		reset()
+		_, err := server.multiEditTool().Handler(context.Background(), map[string]interface{}{
+			"files": []interface{}{edit(a, "alpha", "ALPHA"), edit(b, "missing", "x")},
+		})
+		assert.ErrorIs(t, err, common.ErrInvalidInput)
+		assert.Contains(t, err.Error(), "files[1]")
+		assert.Equal(t, "alpha\nbeta\n", read(a))
+		assert.Equal(t, "gamma\n", read(b))
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		reset()
+		_, err := server.multiEditTool().Handler(context.Background(), map[string]interface{}{
+			"files":   []interface{}{edit(a, "alpha", "ALPHA")},
+			"dry_run": true,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "alpha\nbeta\n", read(a))
+	})
+
+	t.Run("rolls back when a write fails", func(t *testing.T) {
+		reset()
+		dir := filepath.Join(tempDir, "dir")
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, "child"), 0755))
+		err := commitEdits([]*pendingEdit{
+			{path: a, before: "alpha\nbeta\n", after: "changed\n", perm: 0644},
+			{path: dir, before: "", after: "not a directory\n", perm: 0644},
+		})
+		assert.ErrorIs(t, err, common.ErrOperationFailed)
+		assert.Contains(t, err.Error(), "rolled back")
+		assert.Equal(t, "alpha\nbeta\n", read(a))
+
+		entries, err := os.ReadDir(tempDir)
+		require.NoError(t, err)
+		assert.Len(t, entries, 3, "temporary files are cleaned up")
+	})
+}
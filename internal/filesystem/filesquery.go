@@ -0,0 +1,219 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxFilesQueryScan bounds how many entries one files_query walks.
+const maxFilesQueryScan = 500000
+
+type FileQueryMatch struct {
+	Path       string    `json:"path"`
+	SizeBytes  int64     `json:"size_bytes"`
+	ModifiedAt time.Time `json:"modified_at"`
+}
+
+type ExtensionSummary struct {
+	Extension  string `json:"extension"`
+	Files      int    `json:"files"`
+	TotalBytes int64  `json:"total_bytes"`
+}
+
+func (s *Server) filesQueryTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "files_query",
+		Description: "Find the top files under a directory by size or modification time, e.g. the largest build artifacts or what changed in the last hour, or total the files by extension. Filters narrow the files by name, extension, size and modification time; ignored files are skipped",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"directory":       mcp.StringProperty("Absolute path to the directory"),
+				"sort_by":         mcp.StringProperty("size, modified, or extension to total files and bytes per extension (default: size)"),
+				"order":           mcp.StringProperty("desc for largest or newest first, asc for smallest or oldest first (default: desc)"),
+				"limit":           mcp.IntProperty("Number of results to return (default: 20, max: 1000)"),
+				"pattern":         mcp.StringProperty("Only files whose name matches this glob, e.g. *.log"),
+				"extensions":      mcp.ArrayProperty("string", "Only files with these extensions, e.g. [\".go\", \".md\"]"),
+				"min_size":        mcp.IntProperty("Only files of at least this many bytes"),
+				"max_size":        mcp.IntProperty("Only files of at most this many bytes"),
+				"modified_after":  mcp.StringProperty("Only files modified after this RFC 3339 time, or within this long before now, e.g. 1h, 30m or 7d"),
+				"modified_before": mcp.StringProperty("Only files modified before this RFC 3339 time, or more than this long before now"),
+				"exclude":         mcp.ArrayProperty("string", "Extra patterns to skip, in .gitignore syntax, e.g. *.min.js or docs/"),
+				"include_ignored": mcp.BoolProperty("Also include ignored files and directories"),
+			},
+			[]string{"directory"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleFilesQuery),
+	}
+}
+
+type filesQueryArgs struct {
+	Directory      string   `json:"directory" validate:"required"`
+	SortBy         string   `json:"sort_by" default:"size" validate:"oneof=size modified extension"`
+	Order          string   `json:"order" default:"desc" validate:"oneof=asc desc"`
+	Limit          int      `json:"limit" default:"20" validate:"min=1,max=1000"`
+	Pattern        string   `json:"pattern"`
+	Extensions     []string `json:"extensions"`
+	MinSize        int64    `json:"min_size" validate:"min=0"`
+	MaxSize        int64    `json:"max_size" validate:"min=0"`
+	ModifiedAfter  string   `json:"modified_after"`
+	ModifiedBefore string   `json:"modified_before"`
+	Exclude        []string `json:"exclude"`
+	IncludeIgnored bool     `json:"include_ignored"`
+}
+
+func (s *Server) handleFilesQuery(ctx context.Context, args filesQueryArgs) (*mcp.ToolResult, error) {
+	var after, before time.Time
+	var err error
+	if args.ModifiedAfter != "" {
+		if after, err = parseTimeBound("modified_after", args.ModifiedAfter); err != nil {
+			return nil, err
+		}
+	}
+	if args.ModifiedBefore != "" {
+		if before, err = parseTimeBound("modified_before", args.ModifiedBefore); err != nil {
+			return nil, err
+		}
+	}
+	if args.Pattern != "" {
+		if _, err := filepath.Match(args.Pattern, ""); err != nil {
+			return nil, &mcp.ParamError{Param: "pattern", Message: fmt.Sprintf("invalid glob %q", args.Pattern)}
+		}
+	}
+	extensions := make(map[string]bool, len(args.Extensions))
+	for _, ext := range args.Extensions {
+		extensions["."+strings.TrimPrefix(strings.ToLower(ext), ".")] = true
+	}
+
+	root, err := s.validator.ResolvePath(args.Directory)
+	if err != nil {
+		return nil, err
+	}
+	info, err := statExisting(root, args.Directory)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", common.ErrNotADirectory, args.Directory)
+	}
+	ignore, err := s.newIgnoreMatcher(root, args.Exclude, args.IncludeIgnored)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []FileQueryMatch
+	scanned := 0
+	truncated := false
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil || p == root {
+			return nil
+		}
+		if s.validator.ValidatePath(p) != nil || ignore.ignored(p, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if scanned++; scanned > maxFilesQueryScan {
+			truncated = true
+			return filepath.SkipAll
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if args.Pattern != "" && !matchName(args.Pattern, d.Name()) {
+			return nil
+		}
+		if len(extensions) > 0 && !extensions[strings.ToLower(filepath.Ext(d.Name()))] {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		size, mtime := info.Size(), info.ModTime()
+		if size < args.MinSize || (args.MaxSize > 0 && size > args.MaxSize) ||
+			(!after.IsZero() && !mtime.After(after)) || (!before.IsZero() && !mtime.Before(before)) {
+			return nil
+		}
+		matches = append(matches, FileQueryMatch{Path: p, SizeBytes: size, ModifiedAt: mtime})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]interface{}{
+		"directory": root,
+		"sort_by":   args.SortBy,
+		"matched":   len(matches),
+		"truncated": truncated,
+	}
+	if args.SortBy == "extension" {
+		groups := make(map[string]*ExtensionSummary)
+		for _, m := range matches {
+			ext := strings.ToLower(filepath.Ext(m.Path))
+			if groups[ext] == nil {
+				groups[ext] = &ExtensionSummary{Extension: ext}
+			}
+			groups[ext].Files++
+			groups[ext].TotalBytes += m.SizeBytes
+		}
+		list := make([]ExtensionSummary, 0, len(groups))
+		for _, g := range groups {
+			list = append(list, *g)
+		}
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].TotalBytes != list[j].TotalBytes {
+				return (list[i].TotalBytes > list[j].TotalBytes) == (args.Order == "desc")
+			}
+			return list[i].Extension < list[j].Extension
+		})
+		result["extensions"] = list[:min(len(list), args.Limit)]
+		return mcp.JSONResult(result)
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if args.SortBy == "modified" && !a.ModifiedAt.Equal(b.ModifiedAt) {
+			return a.ModifiedAt.After(b.ModifiedAt) == (args.Order == "desc")
+		}
+		if args.SortBy == "size" && a.SizeBytes != b.SizeBytes {
+			return (a.SizeBytes > b.SizeBytes) == (args.Order == "desc")
+		}
+		return a.Path < b.Path
+	})
+	if matches == nil {
+		matches = []FileQueryMatch{}
+	}
+	result["files"] = matches[:min(len(matches), args.Limit)]
+	return mcp.JSONResult(result)
+}
+
+// parseTimeBound reads an RFC 3339 time, or a duration before now such as
+// 90m, 2h or 7d.
+func parseTimeBound(name, value string) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.ParseFloat(days, 64); err == nil && n >= 0 {
+			return time.Now().Add(-time.Duration(n * 24 * float64(time.Hour))), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return time.Now().Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, &mcp.ParamError{Param: name, Message: fmt.Sprintf("parameter %s must be an RFC 3339 time such as 2024-01-02T15:04:05Z or a duration such as 1h or 7d", name)}
+}
@@ -0,0 +1,89 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilesQuery(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	now := time.Now()
+	for _, f := range []struct {
+		path string
+		size int
+		age  time.Duration
+	}{
+		{"build/app.bin", 5000, 48 * time.Hour},
+		{"build/app.map", 3000, 2 * time.Hour},
+		{"src/main.go", 800, 10 * time.Minute},
+		{"src/util.go", 200, 30 * time.Minute},
+		{"README.md", 100, 72 * time.Hour},
+	} {
+		full := filepath.Join(tempDir, f.path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(strings.Repeat("x", f.size)), 0644))
+		require.NoError(t, os.Chtimes(full, now.Add(-f.age), now.Add(-f.age)))
+	}
+
+	query := func(params map[string]interface{}) map[string]interface{} {
+		params["directory"] = tempDir
+		result, err := server.filesQueryTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out
+	}
+	names := func(out map[string]interface{}) []string {
+		var names []string
+		for _, f := range out["files"].([]interface{}) {
+			rel, _ := filepath.Rel(tempDir, f.(map[string]interface{})["path"].(string))
+			names = append(names, filepath.ToSlash(rel))
+		}
+		return names
+	}
+
+	t.Run("largest", func(t *testing.T) {
+		out := query(map[string]interface{}{"limit": 2})
+		assert.Equal(t, []string{"build/app.bin", "build/app.map"}, names(out))
+		assert.EqualValues(t, 5, out["matched"])
+	})
+
+	t.Run("newest and oldest", func(t *testing.T) {
+		assert.Equal(t, []string{"src/main.go", "src/util.go"}, names(query(map[string]interface{}{"sort_by": "modified", "limit": 2})))
+		assert.Equal(t, []string{"README.md"}, names(query(map[string]interface{}{"sort_by": "modified", "order": "asc", "limit": 1})))
+	})
+
+	t.Run("filters", func(t *testing.T) {
+		assert.Equal(t, []string{"src/main.go", "src/util.go"}, names(query(map[string]interface{}{"modified_after": "1h"})))
+		assert.Equal(t, []string{"build/app.bin", "README.md"}, names(query(map[string]interface{}{"modified_before": "1d"})))
+		assert.Equal(t, []string{"src/main.go"}, names(query(map[string]interface{}{"extensions": []interface{}{"go"}, "min_size": 500})))
+		assert.Equal(t, []string{"build/app.map"}, names(query(map[string]interface{}{"pattern": "*.map"})))
+		assert.Empty(t, names(query(map[string]interface{}{"max_size": 50})))
+	})
+
+	t.Run("by extension", func(t *testing.T) {
+		out := query(map[string]interface{}{"sort_by": "extension"})
+		groups := out["extensions"].([]interface{})
+		require.Len(t, groups, 4)
+		first := groups[0].(map[string]interface{})
+		assert.Equal(t, ".bin", first["extension"])
+		goGroup := groups[2].(map[string]interface{})
+		assert.Equal(t, ".go", goGroup["extension"])
+		assert.EqualValues(t, 2, goGroup["files"])
+		assert.EqualValues(t, 1000, goGroup["total_bytes"])
+	})
+
+	t.Run("bad time", func(t *testing.T) {
+		_, err := server.filesQueryTool().Handler(context.Background(), map[string]interface{}{"directory": tempDir, "modified_after": "yesterday"})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,298 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxFileStatsScan bounds how many files one file_stats call counts.
+const maxFileStatsScan = 50000
+
+// commentStyle is how a language writes comments.
+type commentStyle struct {
+	line       []string
+	blockStart string
+	blockEnd   string
+}
+
+var (
+	cStyleComments    = commentStyle{line: []string{"//"}, blockStart: "/*", blockEnd: "*/"}
+	hashComments      = commentStyle{line: []string{"#"}}
+	markupComments    = commentStyle{blockStart: "<!--", blockEnd: "-->"}
+	commentByLanguage = map[string]commentStyle{
+		"go": cStyleComments, "javascript": cStyleComments, "typescript": cStyleComments,
+		"java": cStyleComments, "kotlin": cStyleComments, "scala": cStyleComments,
+		"rust": cStyleComments, "c": cStyleComments, "cpp": cStyleComments,
+		"csharp": cStyleComments, "swift": cStyleComments, "objective-c": cStyleComments,
+		"dart": cStyleComments, "groovy": cStyleComments, "protobuf": cStyleComments,
+		"scss": cStyleComments, "less": cStyleComments, "zig": {line: []string{"//"}},
+		"css": {blockStart: "/*", blockEnd: "*/"}, "php": {line: []string{"//", "#"}, blockStart: "/*", blockEnd: "*/"},
+		"python": hashComments, "ruby": hashComments, "perl": hashComments, "shell": hashComments,
+		"fish": hashComments, "r": hashComments, "elixir": hashComments, "yaml": hashComments,
+		"toml": hashComments, "makefile": hashComments, "dockerfile": hashComments,
+		"starlark": hashComments, "cmake": hashComments, "graphql": hashComments, "nim": hashComments,
+		"powershell": {line: []string{"#"}, blockStart: "<#", blockEnd: "#>"},
+		"terraform":  {line: []string{"#", "//"}, blockStart: "/*", blockEnd: "*/"},
+		"ini":        {line: []string{";", "#"}},
+		"sql":        {line: []string{"--"}, blockStart: "/*", blockEnd: "*/"},
+		"lua":        {line: []string{"--"}, blockStart: "--[[", blockEnd: "]]"},
+		"haskell":    {line: []string{"--"}, blockStart: "{-", blockEnd: "-}"},
+		"erlang":     {line: []string{"%"}}, "latex": {line: []string{"%"}},
+		"clojure": {line: []string{";"}}, "ocaml": {blockStart: "(*", blockEnd: "*)"},
+		"fsharp": {line: []string{"//"}, blockStart: "(*", blockEnd: "*)"},
+		"html":   markupComments, "xml": markupComments, "vue": markupComments,
+		"svelte": markupComments, "markdown": markupComments,
+	}
+)
+
+// LineCounts are the counts of a file or a group of files. Lines are code,
+// comment or blank; a line with both code and a comment is code.
+type LineCounts struct {
+	Lines   int   `json:"lines"`
+	Code    int   `json:"code"`
+	Comment int   `json:"comment"`
+	Blank   int   `json:"blank"`
+	Words   int   `json:"words"`
+	Bytes   int64 `json:"bytes"`
+}
+
+func (c *LineCounts) add(o LineCounts) {
+	c.Lines += o.Lines
+	c.Code += o.Code
+	c.Comment += o.Comment
+	c.Blank += o.Blank
+	c.Words += o.Words
+	c.Bytes += o.Bytes
+}
+
+type ExtensionStats struct {
+	Extension string `json:"extension"`
+	Language  string `json:"language,omitempty"`
+	Files     int    `json:"files"`
+	LineCounts
+}
+
+func (s *Server) fileStatsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "file_stats",
+		Description: "Count lines, words and bytes of a file, splitting lines into code, comment and blank by the file's language. For a directory, total its text files by extension, for a quick answer to how big a codebase is and what it is written in. Ignored and binary files are skipped",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":            mcp.StringProperty("Absolute path to a file or directory"),
+				"exclude":         mcp.ArrayProperty("string", "Extra patterns to skip, in .gitignore syntax, e.g. *.min.js or vendor/"),
+				"include_ignored": mcp.BoolProperty("Also count ignored files and directories"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleFileStats),
+	}
+}
+
+type fileStatsArgs struct {
+	Path           string   `json:"path" validate:"required"`
+	Exclude        []string `json:"exclude"`
+	IncludeIgnored bool     `json:"include_ignored"`
+}
+
+func (s *Server) handleFileStats(ctx context.Context, args fileStatsArgs) (*mcp.ToolResult, error) {
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := statExisting(absPath, args.Path)
+	if err != nil {
+		return nil, err
+	}
+	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
+
+	if !info.IsDir() {
+		if info.Size() > maxSize {
+			return nil, fmt.Errorf("%w: %s is %d bytes, max is %d bytes", common.ErrFileTooLarge, args.Path, info.Size(), maxSize)
+		}
+		counts, language, ok, err := countFile(absPath)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is a binary file", common.ErrInvalidInput, args.Path)
+		}
+		return mcp.JSONResult(map[string]interface{}{
+			"path":     absPath,
+			"language": language,
+			"lines":    counts.Lines,
+			"code":     counts.Code,
+			"comment":  counts.Comment,
+			"blank":    counts.Blank,
+			"words":    counts.Words,
+			"bytes":    counts.Bytes,
+		})
+	}
+
+	ignore, err := s.newIgnoreMatcher(absPath, args.Exclude, args.IncludeIgnored)
+	if err != nil {
+		return nil, err
+	}
+	var total LineCounts
+	groups := make(map[string]*ExtensionStats)
+	files, binary, skipped := 0, 0, 0
+	truncated := false
+	err = filepath.WalkDir(absPath, func(p string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil || p == absPath {
+			return nil
+		}
+		if s.validator.ValidatePath(p) != nil || ignore.ignored(p, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if files+binary+skipped >= maxFileStatsScan {
+			truncated = true
+			return filepath.SkipAll
+		}
+		if info, err := d.Info(); err != nil || info.Size() > maxSize {
+			skipped++
+			return nil
+		}
+		counts, language, ok, err := countFile(p)
+		if err != nil {
+			skipped++
+			return nil
+		}
+		if !ok {
+			binary++
+			return nil
+		}
+		files++
+		total.add(counts)
+		ext := strings.ToLower(filepath.Ext(d.Name()))
+		group := groups[ext]
+		if group == nil {
+			group = &ExtensionStats{Extension: ext, Language: language}
+			groups[ext] = group
+		}
+		group.Files++
+		group.add(counts)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	byExtension := make([]ExtensionStats, 0, len(groups))
+	for _, g := range groups {
+		byExtension = append(byExtension, *g)
+	}
+	sort.Slice(byExtension, func(i, j int) bool {
+		a, b := byExtension[i], byExtension[j]
+		if a.Code != b.Code {
+			return a.Code > b.Code
+		}
+		if a.Lines != b.Lines {
+			return a.Lines > b.Lines
+		}
+		return a.Extension < b.Extension
+	})
+	return mcp.JSONResult(map[string]interface{}{
+		"path":          absPath,
+		"files":         files,
+		"binary_files":  binary,
+		"skipped_files": skipped,
+		"total":         total,
+		"by_extension":  byExtension,
+		"truncated":     truncated,
+	})
+}
+
+// countFile counts the lines of a text file; ok is false for a binary
+// file.
+func countFile(path string) (counts LineCounts, language string, ok bool, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return counts, "", false, err
+	}
+	encoding := detectEncoding(data)
+	if encoding == "" {
+		return counts, "", false, nil
+	}
+	text, err := decodeText(data, encoding)
+	if err != nil {
+		return counts, "", false, err
+	}
+	language = detectLanguage(path, data[:min(len(data), sniffSize)])
+	counts = classifyLines(text, commentByLanguage[language])
+	counts.Bytes = int64(len(data))
+	return counts, language, true, nil
+}
+
+func classifyLines(text string, style commentStyle) LineCounts {
+	var counts LineCounts
+	counts.Words = len(strings.Fields(text))
+	text = strings.TrimSuffix(text, "\n")
+	if text == "" {
+		return counts
+	}
+	inBlock := false
+	for _, line := range strings.Split(text, "\n") {
+		counts.Lines++
+		line = strings.TrimSpace(line)
+		switch {
+		case inBlock:
+			counts.Comment++
+			if _, rest, found := strings.Cut(line, style.blockEnd); found {
+				inBlock = false
+				if strings.TrimSpace(rest) != "" {
+					counts.Comment--
+					counts.Code++
+				}
+			}
+		case line == "":
+			counts.Blank++
+		case style.blockStart != "" && strings.HasPrefix(line, style.blockStart):
+			_, rest, found := strings.Cut(line[len(style.blockStart):], style.blockEnd)
+			inBlock = !found
+			if found && strings.TrimSpace(rest) != "" && !hasAnyPrefix(strings.TrimSpace(rest), style.line) {
+				counts.Code++
+			} else {
+				counts.Comment++
+			}
+		case hasAnyPrefix(line, style.line):
+			counts.Comment++
+		default:
+			counts.Code++
+			// A block comment that starts after code on the line. Requiring
+			// a space before it, outside quotes, keeps "dir/*" from
+			// counting.
+			if style.blockStart != "" {
+				if i := strings.LastIndex(line, " "+style.blockStart); i >= 0 && strings.Count(line[:i], `"`)%2 == 0 {
+					inBlock = !strings.Contains(line[i+1+len(style.blockStart):], style.blockEnd)
+				}
+			}
+		}
+	}
+	return counts
+}
+
+func hasAnyPrefix(s string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileStats(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	server.config.DefaultExcludes = []string{"node_modules/"}
+	for path, content := range map[string]string{
+		"main.go": `package main
+
+// main runs the server.
+func main() {
+	/* a block
+	   comment */
+	files, _ := filepath.Glob("dir/*")
+	run(files) // trailing
+}
+`,
+		"util.go":               "package main\n\nfunc run(files []string) {}",
+		"scripts/build.sh":      "#!/bin/sh\n# build it\ngo build ./...\n",
+		"README.md":             "# Title\n\nSome words here.\n",
+		"logo.png":              "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR",
+		"node_modules/dep/x.js": "module.exports = 1\n",
+	} {
+		full := filepath.Join(tempDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	stats := func(params map[string]interface{}) map[string]interface{} {
+		result, err := server.fileStatsTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out
+	}
+
+	t.Run("file", func(t *testing.T) {
+		out := stats(map[string]interface{}{"path": filepath.Join(tempDir, "main.go")})
+		assert.Equal(t, "go", out["language"])
+		assert.EqualValues(t, 9, out["lines"])
+		assert.EqualValues(t, 5, out["code"])
+		assert.EqualValues(t, 3, out["comment"])
+		assert.EqualValues(t, 1, out["blank"])
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		out := stats(map[string]interface{}{"path": tempDir})
+		assert.EqualValues(t, 4, out["files"])
+		assert.EqualValues(t, 1, out["binary_files"])
+		total := out["total"].(map[string]interface{})
+		assert.EqualValues(t, 9+3+3+3, total["lines"])
+
+		groups := make(map[string]map[string]interface{})
+		var order []string
+		for _, g := range out["by_extension"].([]interface{}) {
+			group := g.(map[string]interface{})
+			groups[group["extension"].(string)] = group
+			order = append(order, group["extension"].(string))
+		}
+		assert.Equal(t, []string{".go", ".md", ".sh"}, order, "most code first")
+		golang := groups[".go"]
+		assert.Equal(t, "go", golang["language"])
+		assert.EqualValues(t, 2, golang["files"])
+		assert.EqualValues(t, 7, golang["code"])
+		shell := groups[".sh"]
+		assert.Equal(t, "shell", shell["language"])
+		assert.EqualValues(t, 2, shell["comment"], "the #! line counts as a comment")
+	})
+
+	t.Run("include ignored", func(t *testing.T) {
+		out := stats(map[string]interface{}{"path": tempDir, "include_ignored": true, "exclude": []interface{}{"*.md"}})
+		assert.EqualValues(t, 4, out["files"])
+	})
+}
+
+func TestClassifyLines(t *testing.T) {
+	lua := classifyLines("--[[ long\ncomment ]]\nprint(1) -- hi\n-- note\n", commentByLanguage["lua"])
+	assert.Equal(t, LineCounts{Lines: 4, Code: 1, Comment: 3, Words: 9}, lua)
+
+	html := classifyLines("<!-- head -->\n<p>hi</p>\n\n", commentByLanguage["html"])
+	assert.Equal(t, LineCounts{Lines: 3, Code: 1, Comment: 1, Blank: 1, Words: 4}, html)
+
+	plain := classifyLines("one two\n\nthree", commentStyle{})
+	assert.Equal(t, LineCounts{Lines: 3, Code: 2, Blank: 1, Words: 3}, plain)
+}
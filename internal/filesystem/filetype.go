@@ -0,0 +1,169 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// sniffSize is how much of a file detect_file_type reads.
+const sniffSize = 8192
+
+var languageByExtension = map[string]string{
+	".go": "go", ".py": "python", ".pyi": "python", ".js": "javascript", ".mjs": "javascript",
+	".cjs": "javascript", ".jsx": "javascript", ".ts": "typescript", ".tsx": "typescript",
+	".java": "java", ".kt": "kotlin", ".kts": "kotlin", ".scala": "scala", ".rs": "rust",
+	".c": "c", ".h": "c", ".cc": "cpp", ".cpp": "cpp", ".cxx": "cpp", ".hpp": "cpp",
+	".cs": "csharp", ".swift": "swift", ".m": "objective-c", ".rb": "ruby", ".php": "php",
+	".pl": "perl", ".lua": "lua", ".r": "r", ".dart": "dart", ".ex": "elixir", ".exs": "elixir",
+	".erl": "erlang", ".hs": "haskell", ".clj": "clojure", ".sh": "shell", ".bash": "shell",
+	".zsh": "shell", ".fish": "fish", ".ps1": "powershell", ".sql": "sql", ".html": "html",
+	".htm": "html", ".css": "css", ".scss": "scss", ".less": "less", ".vue": "vue",
+	".svelte": "svelte", ".json": "json", ".yaml": "yaml", ".yml": "yaml", ".toml": "toml",
+	".xml": "xml", ".ini": "ini", ".md": "markdown", ".rst": "restructuredtext",
+	".tex": "latex", ".proto": "protobuf", ".graphql": "graphql", ".tf": "terraform",
+	".zig": "zig", ".nim": "nim", ".ml": "ocaml", ".fs": "fsharp", ".groovy": "groovy",
+}
+
+var languageByName = map[string]string{
+	"Makefile": "makefile", "GNUmakefile": "makefile", "Dockerfile": "dockerfile",
+	"Containerfile": "dockerfile", "CMakeLists.txt": "cmake", "Jenkinsfile": "groovy",
+	"Gemfile": "ruby", "Rakefile": "ruby", "go.mod": "go-module", "BUILD": "starlark",
+	"BUILD.bazel": "starlark", "WORKSPACE": "starlark",
+}
+
+// languageByInterpreter maps the program a #! line runs to its language.
+var languageByInterpreter = map[string]string{
+	"sh": "shell", "bash": "shell", "zsh": "shell", "dash": "shell", "ksh": "shell",
+	"python": "python", "python3": "python", "python2": "python", "node": "javascript",
+	"ruby": "ruby", "perl": "perl", "php": "php", "lua": "lua", "fish": "fish",
+	"Rscript": "r", "deno": "typescript",
+}
+
+func (s *Server) detectFileTypeTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "detect_file_type",
+		Description: "Identify a file from its content (magic bytes) and name: MIME type, whether it is text or binary, its text encoding and programming language. Reads only the start of the file; use it before deciding to read or grep a file",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path": mcp.StringProperty("Absolute path to the file"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleDetectFileType),
+	}
+}
+
+type detectFileTypeArgs struct {
+	Path string `json:"path" validate:"required"`
+}
+
+func (s *Server) handleDetectFileType(ctx context.Context, args detectFileTypeArgs) (*mcp.ToolResult, error) {
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := statExisting(absPath, args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", common.ErrNotAFile, args.Path)
+	}
+
+	file, err := os.Open(absPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	head := make([]byte, sniffSize)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+
+	result := map[string]interface{}{
+		"path":       absPath,
+		"size_bytes": info.Size(),
+	}
+	for k, v := range detectFileType(absPath, head, int64(n) < info.Size()) {
+		result[k] = v
+	}
+	return mcp.JSONResult(result)
+}
+
+// detectFileType describes a file from its name and its first bytes, head.
+// truncated says head is not the whole file.
+func detectFileType(path string, head []byte, truncated bool) map[string]interface{} {
+	encoding := detectEncoding(head)
+	// A character cut off at the end of head does not make it Latin-1.
+	if truncated && encoding == "latin-1" && detectEncoding(trimPartialRunes(head)) == "utf-8" {
+		encoding = "utf-8"
+	}
+
+	sniffed := http.DetectContentType(head)
+	byExtension := mime.TypeByExtension(filepath.Ext(path))
+	mimeType := sniffed
+	// The sniffer only tells text from binary for most formats; the
+	// extension is more specific.
+	if byExtension != "" && (strings.HasPrefix(sniffed, "text/plain") || sniffed == "application/octet-stream") {
+		mimeType = byExtension
+	}
+	if strings.HasPrefix(encoding, "utf-16") && strings.HasPrefix(mimeType, "text/plain") {
+		mimeType = "text/plain; charset=" + encoding
+	}
+
+	result := map[string]interface{}{
+		"mime_type":           mimeType,
+		"sniffed_mime_type":   sniffed,
+		"extension_mime_type": byExtension,
+		"is_binary":           encoding == "",
+	}
+	if encoding != "" {
+		result["encoding"] = encoding
+		if language := detectLanguage(path, head); language != "" {
+			result["language"] = language
+		}
+	}
+	return result
+}
+
+func detectLanguage(path string, head []byte) string {
+	name := filepath.Base(path)
+	if language, ok := languageByName[name]; ok {
+		return language
+	}
+	if language, ok := languageByExtension[strings.ToLower(filepath.Ext(name))]; ok {
+		return language
+	}
+	if !bytes.HasPrefix(head, []byte("#!")) {
+		return ""
+	}
+	line, _, _ := bytes.Cut(head[2:], []byte("\n"))
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return ""
+	}
+	interpreter := filepath.Base(fields[0])
+	if interpreter == "env" {
+		// #!/usr/bin/env [-S] python3
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") {
+				interpreter = f
+				break
+			}
+		}
+	}
+	return languageByInterpreter[interpreter]
+}
@@ -0,0 +1,74 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectFileType(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+
+	detect := func(name string, content []byte) map[string]interface{} {
+		path := filepath.Join(tempDir, name)
+		require.NoError(t, os.WriteFile(path, content, 0644))
+		result, err := server.detectFileTypeTool().Handler(context.Background(), map[string]interface{}{"path": path})
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out
+	}
+
+	t.Run("binary by magic bytes", func(t *testing.T) {
+		out := detect("picture.dat", []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"))
+		assert.Equal(t, "image/png", out["mime_type"])
+		assert.Equal(t, true, out["is_binary"])
+		assert.Nil(t, out["encoding"])
+		assert.Nil(t, out["language"])
+
+		out = detect("archive.bin", []byte("PK\x03\x04\x14\x00\x00\x00\x08\x00"))
+		assert.Equal(t, "application/zip", out["mime_type"])
+	})
+
+	t.Run("source code", func(t *testing.T) {
+		out := detect("main.go", []byte("package main\n\nfunc main() {}\n"))
+		assert.Equal(t, false, out["is_binary"])
+		assert.Equal(t, "utf-8", out["encoding"])
+		assert.Equal(t, "go", out["language"])
+		assert.True(t, strings.HasPrefix(out["mime_type"].(string), "text/"))
+
+		assert.Equal(t, "dockerfile", detect("Dockerfile", []byte("FROM alpine\n"))["language"])
+	})
+
+	t.Run("shebang", func(t *testing.T) {
+		assert.Equal(t, "shell", detect("run", []byte("#!/bin/bash\necho hi\n"))["language"])
+		assert.Equal(t, "python", detect("tool", []byte("#!/usr/bin/env -S python3 -u\nprint(1)\n"))["language"])
+		assert.Nil(t, detect("notes", []byte("just text\n"))["language"])
+	})
+
+	t.Run("utf-16", func(t *testing.T) {
+		data, err := encodeText("hello\n", "utf-16le")
+		require.NoError(t, err)
+		out := detect("wide.txt", data)
+		assert.Equal(t, false, out["is_binary"])
+		assert.Equal(t, "utf-16le", out["encoding"])
+	})
+
+	t.Run("cut character", func(t *testing.T) {
+		content := strings.Repeat("a", sniffSize-1) + "é and more"
+		out := detect("long.txt", []byte(content))
+		assert.Equal(t, "utf-8", out["encoding"])
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		_, err := server.detectFileTypeTool().Handler(context.Background(), map[string]interface{}{"path": tempDir})
+		assert.Error(t, err)
+	})
+}
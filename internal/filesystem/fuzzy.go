@@ -0,0 +1,244 @@
+package filesystem
+
+import (
+	"context"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxFuzzyEntries bounds how many paths one fuzzy_find considers.
+const maxFuzzyEntries = 200000
+
+// Scores follow fzf: every matched character scores, gaps cost, and
+// characters at the start of a word or path segment earn a bonus.
+const (
+	fuzzyScoreMatch        = 16
+	fuzzyScoreGapStart     = -3
+	fuzzyScoreGapExtension = -1
+	fuzzyBonusBoundary     = 8
+	fuzzyBonusCamel        = 7
+	fuzzyBonusConsecutive  = 4
+	// fuzzyBonusBasename rewards a term matched within the file name
+	// rather than spread over its directories.
+	fuzzyBonusBasename = 12
+)
+
+type FuzzyMatch struct {
+	Path        string `json:"path"`
+	Score       int    `json:"score"`
+	IsDirectory bool   `json:"is_directory"`
+}
+
+func (s *Server) fuzzyFindTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "fuzzy_find",
+		Description: "Find files by fuzzy name, like fzf: each space-separated term of the query must appear in order, not necessarily adjacent, in the path, e.g. \"fs srv tools\" finds internal/filesystem/server/tools.go. Returns the best matches first. Searches all allowed paths unless a directory is given",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"query":           mcp.StringProperty("Terms to match; a term with an uppercase letter matches case-sensitively"),
+				"directory":       mcp.StringProperty("Directory to search in (default: every allowed path)"),
+				"type":            mcp.StringProperty("file, directory or any (default: file)"),
+				"limit":           mcp.IntProperty("Number of matches to return (default: 20, max: 200)"),
+				"exclude":         mcp.ArrayProperty("string", "Extra patterns to skip, in .gitignore syntax, e.g. *.min.js or docs/"),
+				"include_ignored": mcp.BoolProperty("Also search ignored files and directories"),
+			},
+			[]string{"query"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleFuzzyFind),
+	}
+}
+
+type fuzzyFindArgs struct {
+	Query          string   `json:"query" validate:"required"`
+	Directory      string   `json:"directory"`
+	Type           string   `json:"type" default:"file" validate:"oneof=file directory any"`
+	Limit          int      `json:"limit" default:"20" validate:"min=1,max=200"`
+	Exclude        []string `json:"exclude"`
+	IncludeIgnored bool     `json:"include_ignored"`
+}
+
+func (s *Server) handleFuzzyFind(ctx context.Context, args fuzzyFindArgs) (*mcp.ToolResult, error) {
+	terms := strings.Fields(args.Query)
+	if len(terms) == 0 {
+		return nil, &mcp.ParamError{Param: "query", Message: "query has no terms"}
+	}
+
+	roots := s.config.AllowedPaths
+	if args.Directory != "" {
+		absDir, err := s.validator.ResolvePath(args.Directory)
+		if err != nil {
+			return nil, err
+		}
+		roots = []string{absDir}
+	}
+
+	var matches []FuzzyMatch
+	scanned := 0
+	truncated := false
+	for _, root := range roots {
+		ignore, err := s.newIgnoreMatcher(root, args.Exclude, args.IncludeIgnored)
+		if err != nil {
+			return nil, err
+		}
+		err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err != nil || path == root {
+				return nil
+			}
+			if s.validator.ValidatePath(path) != nil || ignore.ignored(path, d.IsDir()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			if scanned++; scanned > maxFuzzyEntries {
+				truncated = true
+				return filepath.SkipAll
+			}
+			if (args.Type == "file" && d.IsDir()) || (args.Type == "directory" && !d.IsDir()) {
+				return nil
+			}
+			rel, _ := filepath.Rel(root, path)
+			if score, ok := fuzzyScore(filepath.ToSlash(rel), terms); ok {
+				matches = append(matches, FuzzyMatch{Path: path, Score: score, IsDirectory: d.IsDir()})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if truncated {
+			break
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		if len(matches[i].Path) != len(matches[j].Path) {
+			return len(matches[i].Path) < len(matches[j].Path)
+		}
+		return matches[i].Path < matches[j].Path
+	})
+	total := len(matches)
+	if len(matches) > args.Limit {
+		matches = matches[:args.Limit]
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"query":     args.Query,
+		"matches":   matches,
+		"total":     total,
+		"truncated": truncated,
+	})
+}
+
+// fuzzyScore scores path against every term; ok is false unless each term
+// matches.
+func fuzzyScore(path string, terms []string) (int, bool) {
+	text := []rune(path)
+	lower := []rune(strings.ToLower(path))
+	base := strings.LastIndexByte(path, '/') + 1
+	base = len([]rune(path[:base]))
+
+	total := 0
+	for _, term := range terms {
+		haystack := lower
+		pattern := []rune(strings.ToLower(term))
+		if strings.ToLower(term) != term {
+			haystack, pattern = text, []rune(term)
+		}
+		start, end, ok := fuzzyMatch(haystack, pattern)
+		if !ok {
+			return 0, false
+		}
+		total += scoreMatch(text, haystack, pattern, start, end)
+		if start >= base {
+			total += fuzzyBonusBasename
+		}
+	}
+	return total, true
+}
+
+// fuzzyMatch finds pattern as a subsequence of text, as fzf's v1
+// algorithm does: the first match scanning forward from the start, then
+// shortened by scanning backward from its end. It returns the span of the
+// match.
+func fuzzyMatch(text, pattern []rune) (int, int, bool) {
+	p, end := 0, -1
+	for i := 0; i < len(text); i++ {
+		if text[i] == pattern[p] {
+			if p++; p == len(pattern) {
+				end = i + 1
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, 0, false
+	}
+	p = len(pattern) - 1
+	start := end - 1
+	for ; start >= 0; start-- {
+		if text[start] == pattern[p] {
+			if p--; p < 0 {
+				break
+			}
+		}
+	}
+	return start, end, true
+}
+
+// scoreMatch scores the leftmost match of pattern within text[start:end].
+// original is text before case folding, for spotting camelCase words.
+func scoreMatch(original, text, pattern []rune, start, end int) int {
+	score, p := 0, 0
+	inGap, consecutive := false, 0
+	for i := start; i < end && p < len(pattern); i++ {
+		if text[i] != pattern[p] {
+			if inGap {
+				score += fuzzyScoreGapExtension
+			} else {
+				score += fuzzyScoreGapStart
+			}
+			inGap, consecutive = true, 0
+			continue
+		}
+		score += fuzzyScoreMatch
+		bonus := charBonus(original, i)
+		if consecutive > 0 {
+			bonus = max(bonus, fuzzyBonusConsecutive)
+		}
+		if p == 0 {
+			bonus *= 2
+		}
+		score += bonus
+		inGap = false
+		consecutive++
+		p++
+	}
+	return score
+}
+
+// charBonus is the bonus for matching the character at i of text.
+func charBonus(text []rune, i int) int {
+	if i == 0 {
+		return fuzzyBonusBoundary
+	}
+	prev, c := text[i-1], text[i]
+	switch {
+	case strings.ContainsRune("/_-. ", prev) && !strings.ContainsRune("/_-. ", c):
+		return fuzzyBonusBoundary
+	case unicode.IsLower(prev) && unicode.IsUpper(c), !unicode.IsDigit(prev) && unicode.IsDigit(c):
+		return fuzzyBonusCamel
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzyFind(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	for _, name := range []string{
+		"internal/filesystem/server/tools.go",
+		"internal/filesystem/server.go",
+		"internal/fs/tools_test.go",
+		"docs/server-tools.md",
+		"cmd/main.go",
+		"pkg/HTTPServer.go",
+	} {
+		path := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+	}
+
+	find := func(params map[string]interface{}) []FuzzyMatch {
+		result, err := server.fuzzyFindTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		var out struct {
+			Matches []FuzzyMatch `json:"matches"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out.Matches
+	}
+	rel := func(m FuzzyMatch) string {
+		r, _ := filepath.Rel(tempDir, m.Path)
+		return filepath.ToSlash(r)
+	}
+
+	matches := find(map[string]interface{}{"query": "fs srv tools"})
+	require.NotEmpty(t, matches)
+	assert.Equal(t, "internal/filesystem/server/tools.go", rel(matches[0]))
+	for _, m := range matches {
+		assert.NotEqual(t, "cmd/main.go", rel(m))
+	}
+
+	matches = find(map[string]interface{}{"query": "main", "directory": filepath.Join(tempDir, "cmd")})
+	require.Len(t, matches, 1)
+	assert.Equal(t, "cmd/main.go", rel(matches[0]))
+
+	t.Run("case", func(t *testing.T) {
+		assert.Len(t, find(map[string]interface{}{"query": "httpserver"}), 1)
+		assert.Len(t, find(map[string]interface{}{"query": "HTTPServer"}), 1)
+		assert.Empty(t, find(map[string]interface{}{"query": "HttpServer"}))
+	})
+
+	t.Run("type and limit", func(t *testing.T) {
+		matches := find(map[string]interface{}{"query": "server", "type": "directory"})
+		require.Len(t, matches, 1)
+		assert.True(t, matches[0].IsDirectory)
+		assert.Len(t, find(map[string]interface{}{"query": "go", "limit": 2}), 2)
+	})
+
+	t.Run("ranking", func(t *testing.T) {
+		score := func(path, query string) int {
+			s, ok := fuzzyScore(path, []string{query})
+			require.True(t, ok)
+			return s
+		}
+		assert.Greater(t, score("src/server.go", "srv"), score("src/sxrxv.go", "srv"))
+		assert.Greater(t, score("a/b/tools.go", "tools"), score("tools/a/b.go", "tools"))
+		assert.Greater(t, score("fooBar.go", "b"), score("foobar.go", "b"))
+		_, ok := fuzzyScore("abc", []string{"cb"})
+		assert.False(t, ok)
+	})
+}
@@ -0,0 +1,103 @@
+package filesystem
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+const maxGrepContext = 50
+
+type grepOptions struct {
+	before, after int
+	// maxPerFile is 0 for no limit.
+	maxPerFile int
+	maxMatches int
+}
+
+func getGrepOptions(args grepArgs) (grepOptions, error) {
+	context := args.Context
+	opts := grepOptions{before: context, after: context, maxPerFile: args.MaxPerFile, maxMatches: args.MaxMatches}
+	if args.BeforeContext != nil {
+		opts.before = *args.BeforeContext
+	}
+	if args.AfterContext != nil {
+		opts.after = *args.AfterContext
+	}
+	for name, v := range map[string]int{"context": context, "before_context": opts.before, "after_context": opts.after} {
+		if v < 0 || v > maxGrepContext {
+			return opts, fmt.Errorf("%w: %s must be between 0 and %d", common.ErrInvalidInput, name, maxGrepContext)
+		}
+	}
+	if opts.maxPerFile < 0 {
+		return opts, fmt.Errorf("%w: max_per_file must not be negative", common.ErrInvalidInput)
+	}
+	if opts.maxMatches < 1 || opts.maxMatches > 10000 {
+		return opts, fmt.Errorf("%w: max_matches must be between 1 and 10000", common.ErrInvalidInput)
+	}
+	return opts, nil
+}
+
+// grepFile returns up to limit matches of re in r, with the context lines
+// opts asks for.
+func grepFile(r io.Reader, path string, re *regexp.Regexp, opts grepOptions, limit int) []GrepMatch {
+	if opts.maxPerFile > 0 {
+		limit = min(limit, opts.maxPerFile)
+	}
+
+	// Track where each line starts; ScanLines drops the line ending.
+	var offset, next int64
+	scanner := bufio.NewScanner(r)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		next += int64(advance)
+		return advance, token, err
+	})
+
+	var matches []GrepMatch
+	var before []string
+	// pending are the matches still collecting after-context lines.
+	var pending []int
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := scanner.Text()
+		lineStart := offset
+		offset = next
+
+		for _, i := range pending {
+			matches[i].After = append(matches[i].After, line)
+		}
+		for len(pending) > 0 && len(matches[pending[0]].After) >= opts.after {
+			pending = pending[1:]
+		}
+		if len(matches) >= limit {
+			if len(pending) == 0 {
+				break
+			}
+			continue
+		}
+
+		if loc := re.FindStringIndex(line); loc != nil {
+			matches = append(matches, GrepMatch{
+				File:       path,
+				LineNumber: lineNum,
+				Line:       line,
+				Column:     loc[0] + 1,
+				ByteOffset: lineStart + int64(loc[0]),
+				Before:     append([]string(nil), before...),
+			})
+			if opts.after > 0 {
+				pending = append(pending, len(matches)-1)
+			}
+		}
+		if opts.before > 0 {
+			if len(before) == opts.before {
+				before = before[1:]
+			}
+			before = append(before, line)
+		}
+	}
+	return matches
+}
@@ -0,0 +1,78 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGrep(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	content := "one\r\ntwo match\nthree\nfour match\nfive\nsix\nseven match\n"
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "a.txt"), []byte(content), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "b.txt"), []byte("match\nmatch\nmatch\n"), 0644))
+
+	grep := func(params map[string]interface{}) ([]GrepMatch, bool) {
+		params["directory"] = tempDir
+		params["pattern"] = "match"
+		result, err := server.grepTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		var out struct {
+			Matches   []GrepMatch `json:"matches"`
+			Truncated bool        `json:"truncated"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out.Matches, out.Truncated
+	}
+
+	t.Run("columns and offsets", func(t *testing.T) {
+		matches, _ := grep(map[string]interface{}{"file_pattern": "a.txt"})
+		require.Len(t, matches, 3)
+		assert.Equal(t, 2, matches[0].LineNumber)
+		assert.Equal(t, 5, matches[0].Column)
+		assert.EqualValues(t, 9, matches[0].ByteOffset)
+		assert.EqualValues(t, len("one\r\ntwo match\nthree\nfour "), matches[1].ByteOffset)
+		assert.Nil(t, matches[0].Before)
+	})
+
+	t.Run("context", func(t *testing.T) {
+		matches, _ := grep(map[string]interface{}{"file_pattern": "a.txt", "context": 1})
+		require.Len(t, matches, 3)
+		assert.Equal(t, []string{"one"}, matches[0].Before)
+		assert.Equal(t, []string{"three"}, matches[0].After)
+		assert.Equal(t, []string{"six"}, matches[2].Before)
+		assert.Nil(t, matches[2].After)
+
+		matches, _ = grep(map[string]interface{}{"file_pattern": "a.txt", "before_context": 2, "after_context": 0})
+		assert.Equal(t, []string{"two match", "three"}, matches[1].Before)
+		assert.Nil(t, matches[1].After)
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		matches, _ := grep(map[string]interface{}{"max_per_file": 1, "after_context": 2})
+		require.Len(t, matches, 2)
+		for _, m := range matches {
+			assert.Len(t, m.After, 2, m.File)
+		}
+
+		matches, truncated := grep(map[string]interface{}{"max_matches": 4})
+		assert.Len(t, matches, 4)
+		assert.True(t, truncated)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, params := range []map[string]interface{}{
+			{"context": -1}, {"after_context": 51}, {"max_per_file": -1}, {"max_matches": 0},
+		} {
+			params["directory"], params["pattern"] = tempDir, "match"
+			_, err := server.grepTool().Handler(context.Background(), params)
+			assert.Error(t, err, params)
+		}
+	})
+}
@@ -0,0 +1,230 @@
+package filesystem
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// ignoreFiles are read in every directory a search walks; later files
+// override earlier ones, as ripgrep does.
+var ignoreFiles = []string{".gitignore", ".ignore"}
+
+// ignoreRule is one line of a .gitignore file, or an exclude pattern.
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// parseIgnoreRule compiles a pattern in .gitignore syntax. ok is false for
+// blank lines and comments.
+func parseIgnoreRule(line string) (rule ignoreRule, ok bool, err error) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule, false, nil
+	}
+	if strings.HasPrefix(line, "!") {
+		rule.negate = true
+		line = line[1:]
+	} else if strings.HasPrefix(line, `\`) {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	if line == "" {
+		return rule, false, nil
+	}
+	if _, err := filepath.Match(line, ""); err != nil {
+		return rule, false, fmt.Errorf("invalid pattern %q: %v", line, err)
+	}
+
+	// A slash anywhere but the end anchors the pattern to its directory;
+	// otherwise it matches a name at any depth.
+	var re strings.Builder
+	re.WriteString("^")
+	if strings.Contains(line, "/") {
+		line = strings.TrimPrefix(line, "/")
+	} else {
+		re.WriteString("(?:.*/)?")
+	}
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case strings.HasPrefix(line[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(line[i:], "/**") && i+3 == len(line):
+			re.WriteString("/.*")
+			i += 2
+		case strings.HasPrefix(line[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		case c == '[' && strings.IndexByte(line[i:], ']') > 1:
+			end := i + strings.IndexByte(line[i:], ']')
+			class := line[i+1 : end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			re.WriteString("[" + class + "]")
+			i = end
+		case c == '\\' && i+1 < len(line):
+			i++
+			re.WriteString(regexp.QuoteMeta(line[i : i+1]))
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	re.WriteString("$")
+	rule.re, err = regexp.Compile(re.String())
+	if err != nil {
+		return rule, false, fmt.Errorf("invalid pattern %q: %v", line, err)
+	}
+	return rule, true, nil
+}
+
+// match reports whether rel, slash-separated and relative to the rule's
+// directory, is matched.
+func (r ignoreRule) match(rel string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	return r.re.MatchString(rel)
+}
+
+// ignoreMatcher decides which paths a search under root skips: the exclude
+// patterns, relative to root, and the rules of the ignore files in root,
+// its subdirectories and the directories above it up to the repository
+// root.
+type ignoreMatcher struct {
+	root      string
+	excludes  []ignoreRule
+	gitignore bool
+	// rules caches the ignore-file rules of each directory read so far.
+	rules map[string][]ignoreRule
+	// parents are the directories above root whose rules apply, outermost
+	// first.
+	parents []string
+}
+
+// newIgnoreMatcher builds the matcher for a search under root, with the
+// configured default excludes and the exclude patterns given. With
+// includeIgnored, only the given patterns apply.
+func (s *Server) newIgnoreMatcher(root string, exclude []string, includeIgnored bool) (*ignoreMatcher, error) {
+	m := &ignoreMatcher{
+		root:      root,
+		gitignore: s.config.RespectGitignore && !includeIgnored,
+		rules:     make(map[string][]ignoreRule),
+	}
+	patterns := exclude
+	if !includeIgnored {
+		patterns = append(append([]string{}, s.config.DefaultExcludes...), exclude...)
+	}
+	for _, p := range patterns {
+		rule, ok, err := parseIgnoreRule(p)
+		if err != nil {
+			return nil, &mcp.ParamError{Param: "exclude", Message: err.Error()}
+		}
+		if ok {
+			m.excludes = append(m.excludes, rule)
+		}
+	}
+
+	if m.gitignore {
+		// Rules above root only apply within the same repository.
+		for dir := root; ; {
+			if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+				break
+			}
+			parent := filepath.Dir(dir)
+			if parent == dir || s.validator.ValidatePath(parent) != nil {
+				m.parents = nil
+				break
+			}
+			m.parents = append([]string{parent}, m.parents...)
+			dir = parent
+		}
+	}
+	return m, nil
+}
+
+// ignored reports whether the search skips path. Directories skipped
+// should not be descended into.
+func (m *ignoreMatcher) ignored(path string, isDir bool) bool {
+	if path == m.root {
+		return false
+	}
+	rel, err := filepath.Rel(m.root, path)
+	if err != nil {
+		return false
+	}
+	ignored := false
+	for _, r := range m.excludes {
+		if r.match(filepath.ToSlash(rel), isDir) {
+			ignored = !r.negate
+		}
+	}
+	if ignored || !m.gitignore {
+		return ignored
+	}
+
+	// The last rule to match wins, and deeper files come later.
+	var below []string
+	for dir := filepath.Dir(rel); dir != "."; dir = filepath.Dir(dir) {
+		below = append([]string{filepath.Join(m.root, dir)}, below...)
+	}
+	dirs := append(append(append([]string{}, m.parents...), m.root), below...)
+	for _, dir := range dirs {
+		r, err := filepath.Rel(dir, path)
+		if err != nil {
+			continue
+		}
+		r = filepath.ToSlash(r)
+		for _, rule := range m.dirRules(dir) {
+			if rule.match(r, isDir) {
+				ignored = !rule.negate
+			}
+		}
+	}
+	return ignored
+}
+
+func (m *ignoreMatcher) dirRules(dir string) []ignoreRule {
+	if rules, ok := m.rules[dir]; ok {
+		return rules
+	}
+	var rules []ignoreRule
+	for _, name := range ignoreFiles {
+		rules = append(rules, readIgnoreFile(filepath.Join(dir, name))...)
+	}
+	m.rules[dir] = rules
+	return rules
+}
+
+// readIgnoreFile returns the rules of an ignore file, skipping any that do
+// not parse, as git does.
+func readIgnoreFile(path string) []ignoreRule {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer file.Close()
+	var rules []ignoreRule
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if rule, ok, err := parseIgnoreRule(scanner.Text()); ok && err == nil {
+			rules = append(rules, rule)
+		}
+	}
+	return rules
+}
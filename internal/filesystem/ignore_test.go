@@ -0,0 +1,141 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseIgnoreRule(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		isDir   bool
+		want    bool
+	}{
+		{"*.log", "debug.log", false, true},
+		{"*.log", "a/b/debug.log", false, true},
+		{"*.log", "debug.log.txt", false, false},
+		{"build/", "build", true, true},
+		{"build/", "build", false, false},
+		{"build/", "src/build", true, true},
+		{"/build", "src/build", true, false},
+		{"/build", "build", false, true},
+		{"doc/*.txt", "doc/notes.txt", false, true},
+		{"doc/*.txt", "doc/sub/notes.txt", false, false},
+		{"doc/**/*.txt", "doc/sub/notes.txt", false, true},
+		{"doc/**/*.txt", "doc/notes.txt", false, true},
+		{"**/foo", "a/b/foo", false, true},
+		{"foo/**", "foo/a/b", false, true},
+		{"foo/**", "foo", true, false},
+		{"file[0-9].c", "file3.c", false, true},
+		{"file[!0-9].c", "file3.c", false, false},
+		{"?.go", "a.go", false, true},
+		{`\#hash`, "#hash", false, true},
+	}
+	for _, tt := range tests {
+		rule, ok, err := parseIgnoreRule(tt.pattern)
+		require.NoError(t, err, tt.pattern)
+		require.True(t, ok, tt.pattern)
+		assert.Equal(t, tt.want, rule.match(tt.path, tt.isDir), "%s against %s", tt.pattern, tt.path)
+	}
+
+	for _, line := range []string{"", "   ", "# comment", "/"} {
+		_, ok, err := parseIgnoreRule(line)
+		assert.NoError(t, err)
+		assert.False(t, ok, line)
+	}
+	rule, _, _ := parseIgnoreRule("!keep.log")
+	assert.True(t, rule.negate)
+	_, _, err := parseIgnoreRule("[abc")
+	assert.Error(t, err)
+}
+
+func TestSearchIgnores(t *testing.T) {
+	tempDir := t.TempDir()
+	server := NewServer(&config.FilesystemConfig{
+		AllowedPaths:     []string{tempDir},
+		MaxFileSizeMB:    10,
+		DefaultExcludes:  []string{".git/", "node_modules/"},
+		RespectGitignore: true,
+	})
+
+	repo := filepath.Join(tempDir, "repo")
+	for path, content := range map[string]string{
+		".git/config":               "needle",
+		".gitignore":                "*.log\n/out/\n!keep.log\n",
+		"main.go":                   "needle",
+		"debug.log":                 "needle",
+		"keep.log":                  "needle",
+		"out/gen.go":                "needle",
+		"node_modules/pkg/index.js": "needle",
+		"src/out/real.go":           "needle",
+		"src/.ignore":               "secret.go\n",
+		"src/secret.go":             "needle",
+		"src/app.min.js":            "needle",
+		"src/nested/.gitignore":     "!important.log\n",
+		"src/nested/important.log":  "needle",
+	} {
+		full := filepath.Join(repo, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+
+	relPaths := func(tool string, params map[string]interface{}) []string {
+		handler := server.searchFilesTool().Handler
+		if tool == "grep" {
+			handler = server.grepTool().Handler
+		}
+		result, err := handler(context.Background(), params)
+		require.NoError(t, err)
+		var out struct {
+			Matches []json.RawMessage `json:"matches"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		var paths []string
+		for _, m := range out.Matches {
+			var path string
+			if json.Unmarshal(m, &path) != nil {
+				var match GrepMatch
+				require.NoError(t, json.Unmarshal(m, &match))
+				path = match.File
+			}
+			rel, _ := filepath.Rel(repo, path)
+			paths = append(paths, filepath.ToSlash(rel))
+		}
+		sort.Strings(paths)
+		return paths
+	}
+
+	want := []string{"keep.log", "main.go", "src/app.min.js", "src/nested/important.log", "src/out/real.go"}
+	assert.Equal(t, want, relPaths("grep", map[string]interface{}{"directory": repo, "pattern": "needle"}))
+
+	files := relPaths("search_files", map[string]interface{}{"directory": repo, "pattern": "*.*"})
+	assert.NotContains(t, files, "debug.log")
+	assert.NotContains(t, files, "out/gen.go")
+	assert.Contains(t, files, "src/out/real.go")
+
+	assert.Equal(t, []string{"keep.log", "main.go", "src/nested/important.log", "src/out/real.go"},
+		relPaths("grep", map[string]interface{}{"directory": repo, "pattern": "needle", "exclude": []interface{}{"*.min.js"}}))
+
+	all := relPaths("grep", map[string]interface{}{"directory": repo, "pattern": "needle", "include_ignored": true})
+	assert.Len(t, all, 10)
+
+	t.Run("rules above the search root", func(t *testing.T) {
+		assert.Equal(t, []string{"src/app.min.js", "src/nested/important.log", "src/out/real.go"},
+			relPaths("grep", map[string]interface{}{"directory": filepath.Join(repo, "src"), "pattern": "needle"}))
+	})
+
+	t.Run("invalid exclude", func(t *testing.T) {
+		_, err := server.grepTool().Handler(context.Background(), map[string]interface{}{
+			"directory": repo, "pattern": "needle", "exclude": []interface{}{"[oops"}})
+		assert.Error(t, err)
+	})
+}
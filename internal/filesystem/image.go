@@ -0,0 +1,336 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"os"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxExifScan bounds how far into a JPEG image_info looks for EXIF data.
+const maxExifScan = 256 * 1024
+
+func (s *Server) imageInfoTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "image_info",
+		Description: "Get an image's format and dimensions (PNG, JPEG, GIF, WebP, BMP and SVG) and, for JPEG, its EXIF data such as camera, date taken, orientation and GPS position, without reading the whole file",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path": mcp.StringProperty("Absolute path to the image"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleImageInfo),
+	}
+}
+
+type imageInfoArgs struct {
+	Path string `json:"path" validate:"required"`
+}
+
+func (s *Server) handleImageInfo(ctx context.Context, args imageInfoArgs) (*mcp.ToolResult, error) {
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := statExisting(absPath, args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", common.ErrNotAFile, args.Path)
+	}
+	file, err := os.Open(absPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := map[string]interface{}{
+		"path":       absPath,
+		"size_bytes": info.Size(),
+	}
+	if config, format, err := image.DecodeConfig(file); err == nil {
+		result["format"] = format
+		result["width"] = config.Width
+		result["height"] = config.Height
+		if format == "jpeg" {
+			if _, err := file.Seek(0, io.SeekStart); err != nil {
+				return nil, err
+			}
+			if exif := readJPEGExif(io.LimitReader(file, maxExifScan)); len(exif) > 0 {
+				result["exif"] = exif
+			}
+		}
+		return mcp.JSONResult(result)
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	head := make([]byte, 4096)
+	n, _ := io.ReadFull(file, head)
+	head = head[:n]
+	switch {
+	case bytes.HasPrefix(head, []byte("BM")) && len(head) >= 30:
+		result["format"] = "bmp"
+		result["width"] = int(int32(binary.LittleEndian.Uint32(head[18:])))
+		// Height is negative for images stored top-down.
+		result["height"] = int(math.Abs(float64(int32(binary.LittleEndian.Uint32(head[22:])))))
+		result["bits_per_pixel"] = binary.LittleEndian.Uint16(head[28:])
+	case len(head) >= 30 && bytes.Equal(head[:4], []byte("RIFF")) && bytes.Equal(head[8:12], []byte("WEBP")):
+		width, height, ok := webpSize(head)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is a WebP image of an unknown kind", common.ErrInvalidInput, args.Path)
+		}
+		result["format"] = "webp"
+		result["width"] = width
+		result["height"] = height
+	case bytes.Contains(head, []byte("<svg")):
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		attrs, ok := svgAttributes(file)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s is not a valid SVG image", common.ErrInvalidInput, args.Path)
+		}
+		result["format"] = "svg"
+		for name, value := range attrs {
+			result[name] = value
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s is not an image in a supported format", common.ErrInvalidInput, args.Path)
+	}
+	return mcp.JSONResult(result)
+}
+
+// webpSize reads the dimensions from the first chunk of a WebP file.
+func webpSize(head []byte) (int, int, bool) {
+	chunk := head[12:]
+	switch string(chunk[:4]) {
+	case "VP8 ":
+		// Lossy: a keyframe header, then 14-bit width and height.
+		return int(binary.LittleEndian.Uint16(chunk[14:]) & 0x3FFF), int(binary.LittleEndian.Uint16(chunk[16:]) & 0x3FFF), true
+	case "VP8L":
+		// Lossless: 14 bits each of width-1 and height-1 after a signature.
+		b := chunk[9:13]
+		width := 1 + (int(b[0]) | int(b[1]&0x3F)<<8)
+		height := 1 + (int(b[1])>>6 | int(b[2])<<2 | int(b[3]&0x0F)<<10)
+		return width, height, true
+	case "VP8X":
+		// Extended: 24-bit canvas width-1 and height-1.
+		width := 1 + (int(chunk[12]) | int(chunk[13])<<8 | int(chunk[14])<<16)
+		height := 1 + (int(chunk[15]) | int(chunk[16])<<8 | int(chunk[17])<<16)
+		return width, height, true
+	}
+	return 0, 0, false
+}
+
+// svgAttributes returns the size attributes of the root svg element; ok is
+// false if there is none.
+func svgAttributes(r io.Reader) (map[string]interface{}, bool) {
+	decoder := xml.NewDecoder(r)
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if start.Name.Local != "svg" {
+			return nil, false
+		}
+		attrs := make(map[string]interface{})
+		for _, a := range start.Attr {
+			switch a.Name.Local {
+			case "width", "height":
+				attrs[a.Name.Local] = a.Value
+			case "viewBox":
+				attrs["view_box"] = a.Value
+			}
+		}
+		return attrs, true
+	}
+}
+
+// readJPEGExif returns the EXIF tags of a JPEG image that image_info
+// reports, or nil if it has none.
+func readJPEGExif(r io.Reader) map[string]interface{} {
+	data, err := io.ReadAll(r)
+	if err != nil || !bytes.HasPrefix(data, []byte{0xFF, 0xD8}) {
+		return nil
+	}
+	for i := 2; i+4 <= len(data); {
+		if data[i] != 0xFF {
+			return nil
+		}
+		marker := data[i+1]
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		if marker == 0xDA || i+2+length > len(data) {
+			// Image data starts; metadata comes before it.
+			return nil
+		}
+		segment := data[i+4 : i+2+length]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			return parseExif(segment[6:])
+		}
+		i += 2 + length
+	}
+	return nil
+}
+
+var exifTags = map[uint16]string{
+	0x010F: "make",
+	0x0110: "model",
+	0x0112: "orientation",
+	0x0131: "software",
+	0x0132: "date_time",
+	0x829A: "exposure_time",
+	0x829D: "f_number",
+	0x8827: "iso",
+	0x9003: "date_time_original",
+	0x920A: "focal_length",
+	0xA002: "pixel_width",
+	0xA003: "pixel_height",
+	0xA434: "lens_model",
+}
+
+const (
+	exifIFDPointer = 0x8769
+	gpsIFDPointer  = 0x8825
+)
+
+// parseExif reads the tags named in exifTags, and the GPS position, from
+// TIFF-structured EXIF data.
+func parseExif(tiff []byte) map[string]interface{} {
+	if len(tiff) < 8 {
+		return nil
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil
+	}
+	exif := make(map[string]interface{})
+	ifd0 := readIFD(tiff, order, order.Uint32(tiff[4:]))
+	for _, ifd := range []map[uint16]interface{}{ifd0, readIFD(tiff, order, pointer(ifd0[exifIFDPointer]))} {
+		for tag, value := range ifd {
+			if name, ok := exifTags[tag]; ok {
+				exif[name] = value
+			}
+		}
+	}
+	if ifd0[gpsIFDPointer] != nil {
+		gps := readIFD(tiff, order, pointer(ifd0[gpsIFDPointer]))
+		lat, latOK := gpsCoordinate(gps[2], gps[1], "S")
+		lon, lonOK := gpsCoordinate(gps[4], gps[3], "W")
+		if latOK && lonOK {
+			exif["gps_latitude"] = lat
+			exif["gps_longitude"] = lon
+		}
+	}
+	return exif
+}
+
+func pointer(value interface{}) uint32 {
+	if v, ok := value.(uint32); ok {
+		return v
+	}
+	return 0
+}
+
+// gpsCoordinate converts degrees, minutes and seconds to signed decimal
+// degrees, negative if ref is negativeRef.
+func gpsCoordinate(dms, ref interface{}, negativeRef string) (float64, bool) {
+	parts, ok := dms.([]float64)
+	if !ok || len(parts) != 3 {
+		return 0, false
+	}
+	degrees := parts[0] + parts[1]/60 + parts[2]/3600
+	if r, _ := ref.(string); r == negativeRef {
+		degrees = -degrees
+	}
+	return math.Round(degrees*1e6) / 1e6, true
+}
+
+// readIFD reads the entries of the image file directory at offset. Single
+// numbers come back as uint32, int32 or float64; several as a slice of
+// float64; text as a string.
+func readIFD(tiff []byte, order binary.ByteOrder, offset uint32) map[uint16]interface{} {
+	entries := make(map[uint16]interface{})
+	if offset == 0 || int(offset)+2 > len(tiff) {
+		return entries
+	}
+	count := int(order.Uint16(tiff[offset:]))
+	typeSizes := map[uint16]int{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 7: 1, 9: 4, 10: 8}
+	for i := 0; i < count; i++ {
+		entry := int(offset) + 2 + 12*i
+		if entry+12 > len(tiff) {
+			break
+		}
+		tag := order.Uint16(tiff[entry:])
+		typ := order.Uint16(tiff[entry+2:])
+		n := int(order.Uint32(tiff[entry+4:]))
+		size, ok := typeSizes[typ]
+		if !ok || n <= 0 || n > 1024 {
+			continue
+		}
+		value := tiff[entry+8 : entry+12]
+		if size*n > 4 {
+			at := int(order.Uint32(value))
+			if at+size*n > len(tiff) {
+				continue
+			}
+			value = tiff[at : at+size*n]
+		}
+
+		switch typ {
+		case 2:
+			entries[tag] = strings.TrimRight(string(value[:n]), "\x00 ")
+		case 3:
+			entries[tag] = uint32(order.Uint16(value))
+		case 4:
+			entries[tag] = order.Uint32(value)
+		case 9:
+			entries[tag] = int32(order.Uint32(value))
+		case 5, 10:
+			values := make([]float64, n)
+			for j := range values {
+				num, den := order.Uint32(value[8*j:]), order.Uint32(value[8*j+4:])
+				if den != 0 {
+					if typ == 10 {
+						values[j] = float64(int32(num)) / float64(int32(den))
+					} else {
+						values[j] = float64(num) / float64(den)
+					}
+				}
+			}
+			if n == 1 {
+				entries[tag] = values[0]
+			} else {
+				entries[tag] = values
+			}
+		}
+	}
+	return entries
+}
@@ -0,0 +1,166 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// tiffEntry is an IFD entry for buildExif; value is the raw value bytes.
+type tiffEntry struct {
+	tag, typ uint16
+	count    uint32
+	value    []byte
+}
+
+// buildExif lays out little-endian TIFF data with the given directories,
+// pointing entries with tag 0x8769 or 0x8825 and no value at the next ones.
+func buildExif(ifds ...[]tiffEntry) []byte {
+	le := binary.LittleEndian
+	size := func(ifd []tiffEntry) int { return 2 + 12*len(ifd) + 4 }
+	offsets := []int{8}
+	for _, ifd := range ifds[:len(ifds)-1] {
+		offsets = append(offsets, offsets[len(offsets)-1]+size(ifd))
+	}
+	data := []byte("II*\x00\x08\x00\x00\x00")
+	var extra []byte
+	extraStart := offsets[len(offsets)-1] + size(ifds[len(ifds)-1])
+	next := 1
+	for _, ifd := range ifds {
+		data = le.AppendUint16(data, uint16(len(ifd)))
+		for _, e := range ifd {
+			data = le.AppendUint16(data, e.tag)
+			data = le.AppendUint16(data, e.typ)
+			data = le.AppendUint32(data, e.count)
+			switch {
+			case e.value == nil:
+				data = le.AppendUint32(data, uint32(offsets[next]))
+				next++
+			case len(e.value) <= 4:
+				data = append(data, append(e.value, make([]byte, 4-len(e.value))...)...)
+			default:
+				data = le.AppendUint32(data, uint32(extraStart+len(extra)))
+				extra = append(extra, e.value...)
+			}
+		}
+		data = le.AppendUint32(data, 0)
+	}
+	return append(data, extra...)
+}
+
+func rationals(values ...uint32) []byte {
+	var b []byte
+	for _, v := range values {
+		b = binary.LittleEndian.AppendUint32(b, v)
+	}
+	return b
+}
+
+func TestImageInfo(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+
+	info := func(name string, content []byte) map[string]interface{} {
+		path := filepath.Join(tempDir, name)
+		require.NoError(t, os.WriteFile(path, content, 0644))
+		result, err := server.imageInfoTool().Handler(context.Background(), map[string]interface{}{"path": path})
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out
+	}
+
+	t.Run("png", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 12, 7))))
+		out := info("a.png", buf.Bytes())
+		assert.Equal(t, "png", out["format"])
+		assert.EqualValues(t, 12, out["width"])
+		assert.EqualValues(t, 7, out["height"])
+		assert.Nil(t, out["exif"])
+	})
+
+	t.Run("jpeg with exif", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, jpeg.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 20, 10)), nil))
+		exif := buildExif(
+			[]tiffEntry{
+				{0x010F, 2, 6, []byte("Canon\x00")},
+				{0x0112, 3, 1, []byte{6, 0}},
+				{0x8769, 4, 1, nil},
+				{0x8825, 4, 1, nil},
+			},
+			[]tiffEntry{{0x829D, 5, 1, rationals(28, 10)}},
+			[]tiffEntry{
+				{0x0001, 2, 2, []byte("N\x00")},
+				{0x0002, 5, 3, rationals(51, 1, 30, 1, 0, 1)},
+				{0x0003, 2, 2, []byte("W\x00")},
+				{0x0004, 5, 3, rationals(0, 1, 7, 1, 30, 1)},
+			},
+		)
+		segment := append([]byte("Exif\x00\x00"), exif...)
+		app1 := append([]byte{0xFF, 0xE1}, binary.BigEndian.AppendUint16(nil, uint16(len(segment)+2))...)
+		jpg := append(append(append([]byte{}, buf.Bytes()[:2]...), append(app1, segment...)...), buf.Bytes()[2:]...)
+
+		out := info("photo.jpg", jpg)
+		assert.Equal(t, "jpeg", out["format"])
+		assert.EqualValues(t, 20, out["width"])
+		tags := out["exif"].(map[string]interface{})
+		assert.Equal(t, "Canon", tags["make"])
+		assert.EqualValues(t, 6, tags["orientation"])
+		assert.InDelta(t, 2.8, tags["f_number"], 1e-9)
+		assert.InDelta(t, 51.5, tags["gps_latitude"], 1e-9)
+		assert.InDelta(t, -0.125, tags["gps_longitude"], 1e-9)
+	})
+
+	t.Run("headers", func(t *testing.T) {
+		bmp := make([]byte, 54)
+		copy(bmp, "BM")
+		binary.LittleEndian.PutUint32(bmp[18:], 640)
+		binary.LittleEndian.PutUint32(bmp[22:], uint32(0xFFFFFFFF-479)) // -480, top-down
+		binary.LittleEndian.PutUint16(bmp[28:], 24)
+		out := info("a.bmp", bmp)
+		assert.Equal(t, "bmp", out["format"])
+		assert.EqualValues(t, 640, out["width"])
+		assert.EqualValues(t, 480, out["height"])
+
+		webp := []byte("RIFF\x00\x00\x00\x00WEBPVP8X\x0a\x00\x00\x00\x00\x00\x00\x00")
+		webp = append(webp, 0x1F, 0x03, 0x00, 0xDF, 0x01, 0x00) // 800x480
+		webp = append(webp, make([]byte, 8)...)
+		out = info("a.webp", webp)
+		assert.Equal(t, "webp", out["format"])
+		assert.EqualValues(t, 800, out["width"])
+		assert.EqualValues(t, 480, out["height"])
+
+		lossless := []byte("RIFF\x00\x00\x00\x00WEBPVP8L\x00\x00\x00\x00\x2f")
+		// width-1 = 99 and height-1 = 49 packed in 14-bit fields.
+		bits := uint32(99) | uint32(49)<<14
+		lossless = binary.LittleEndian.AppendUint32(lossless, bits)
+		lossless = append(lossless, make([]byte, 8)...)
+		out = info("b.webp", lossless)
+		assert.EqualValues(t, 100, out["width"])
+		assert.EqualValues(t, 50, out["height"])
+
+		out = info("icon.svg", []byte(`<?xml version="1.0"?><svg xmlns="http://www.w3.org/2000/svg" width="24" height="24px" viewBox="0 0 24 24"></svg>`))
+		assert.Equal(t, "svg", out["format"])
+		assert.Equal(t, "24px", out["height"])
+		assert.Equal(t, "0 0 24 24", out["view_box"])
+	})
+
+	t.Run("not an image", func(t *testing.T) {
+		path := filepath.Join(tempDir, "notes.txt")
+		require.NoError(t, os.WriteFile(path, []byte("hello"), 0644))
+		_, err := server.imageInfoTool().Handler(context.Background(), map[string]interface{}{"path": path})
+		assert.Error(t, err)
+	})
+}
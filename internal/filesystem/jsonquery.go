@@ -0,0 +1,570 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) queryJSONTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name: "query_json",
+		Description: "Run a jq-like query against a JSON or YAML file and return only the values it selects. " +
+			"Paths: . (the document; $ also works), .key, .\"odd key\", .[\"key\"], .[0], .[-1], .[2:5], .[] or .[*] (every element or value), .. (every value at any depth; ..name finds name keys at any depth). " +
+			"Stages joined with | run on each result of the one before: a path, keys, length, or select(PATH OP VALUE) with OP one of == != < <= > >= and VALUE a JSON literal, e.g. .packages[] | select(.version == \"1.2.0\") | .name. " +
+			"A key missing from an object gives null; paths that do not apply to a value select nothing",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":        mcp.StringProperty("Absolute path to the file"),
+				"query":       mcp.StringProperty("The query, e.g. .dependencies or .items[] | select(.kind == \"Service\") | .metadata.name"),
+				"format":      mcp.StringProperty("auto, json or yaml (default: auto, from the file extension)"),
+				"max_results": mcp.IntProperty("Maximum number of results to return (default: 100, max: 10000)"),
+			},
+			[]string{"path", "query"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleQueryJSON),
+	}
+}
+
+type queryJSONArgs struct {
+	Path       string `json:"path" validate:"required"`
+	Query      string `json:"query" validate:"required"`
+	Format     string `json:"format" default:"auto" validate:"oneof=auto json yaml"`
+	MaxResults int    `json:"max_results" default:"100" validate:"min=1,max=10000"`
+}
+
+func (s *Server) handleQueryJSON(ctx context.Context, args queryJSONArgs) (*mcp.ToolResult, error) {
+	query, err := parseJSONQuery(args.Query)
+	if err != nil {
+		return nil, &mcp.ParamError{Param: "query", Message: err.Error()}
+	}
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := statExisting(absPath, args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", common.ErrNotAFile, args.Path)
+	}
+	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("%w: file size %d exceeds limit %d", common.ErrFileTooLarge, info.Size(), maxSize)
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	format := args.Format
+	if format == "auto" {
+		format = "json"
+		if ext := strings.ToLower(filepath.Ext(absPath)); ext == ".yaml" || ext == ".yml" {
+			format = "yaml"
+		}
+	}
+	doc, err := parseStructured(bytes.TrimPrefix(data, bomUTF8), format)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := query.run(doc)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrInvalidInput, err)
+	}
+	count := len(results)
+	if len(results) > args.MaxResults {
+		results = results[:args.MaxResults]
+	}
+	if results == nil {
+		results = []interface{}{}
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"path":      absPath,
+		"query":     args.Query,
+		"results":   results,
+		"count":     count,
+		"truncated": count > len(results),
+	})
+}
+
+// jsonQuery is a parsed query: stages, each run on every result of the
+// stage before.
+type jsonQuery []jsonStage
+
+type jsonStage func(v interface{}) ([]interface{}, error)
+
+func (q jsonQuery) run(doc interface{}) ([]interface{}, error) {
+	values := []interface{}{doc}
+	for _, stage := range q {
+		var next []interface{}
+		for _, v := range values {
+			out, err := stage(v)
+			if err != nil {
+				return nil, err
+			}
+			next = append(next, out...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func parseJSONQuery(query string) (jsonQuery, error) {
+	var q jsonQuery
+	for _, part := range splitTopLevel(query, "|") {
+		part = strings.TrimSpace(part)
+		switch {
+		case part == "":
+			return nil, fmt.Errorf("empty stage in %q", query)
+		case part == "keys":
+			q = append(q, jsonKeys)
+		case part == "length":
+			q = append(q, jsonLength)
+		case strings.HasPrefix(part, "select(") && strings.HasSuffix(part, ")"):
+			stage, err := parseSelect(part[len("select(") : len(part)-1])
+			if err != nil {
+				return nil, err
+			}
+			q = append(q, stage)
+		default:
+			path, err := parseJSONPath(part)
+			if err != nil {
+				return nil, err
+			}
+			q = append(q, path.apply)
+		}
+	}
+	return q, nil
+}
+
+// jsonPath is a sequence of steps, each mapping a value to any number of
+// values.
+type jsonPath []func(v interface{}) []interface{}
+
+func (p jsonPath) apply(v interface{}) ([]interface{}, error) {
+	values := []interface{}{v}
+	for _, step := range p {
+		var next []interface{}
+		for _, v := range values {
+			next = append(next, step(v)...)
+		}
+		values = next
+	}
+	return values, nil
+}
+
+func parseJSONPath(s string) (jsonPath, error) {
+	var path jsonPath
+	i := 0
+	if strings.HasPrefix(s, "$") {
+		i = 1
+	} else if !strings.HasPrefix(s, ".") {
+		return nil, fmt.Errorf("path %q must start with . or $", s)
+	}
+	for i < len(s) {
+		switch {
+		case strings.HasPrefix(s[i:], ".."):
+			path = append(path, jsonDescendants)
+			i += 2
+			// ..key selects key wherever it is, as in JSONPath.
+			if i < len(s) && (isKeyChar(s[i]) || s[i] == '"') {
+				key, n, err := parseKey(s[i:])
+				if err != nil {
+					return nil, err
+				}
+				path = append(path, jsonPresentField(key))
+				i += n
+			}
+		case s[i] == '.':
+			i++
+			if i == len(s) || s[i] == '[' {
+				continue
+			}
+			if s[i] == '*' {
+				path = append(path, jsonIterate)
+				i++
+				continue
+			}
+			key, n, err := parseKey(s[i:])
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, jsonField(key))
+			i += n
+		case s[i] == '[':
+			end := strings.IndexByte(s[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [ in %q", s)
+			}
+			step, err := parseBracket(strings.TrimSpace(s[i+1 : i+end]))
+			if err != nil {
+				return nil, err
+			}
+			path = append(path, step)
+			i += end + 1
+		default:
+			return nil, fmt.Errorf("unexpected %q in path %q", s[i:], s)
+		}
+	}
+	return path, nil
+}
+
+func isKeyChar(c byte) bool {
+	return c == '_' || c == '-' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// parseKey reads a bare or double-quoted key from the start of s and
+// returns it with the number of bytes it took.
+func parseKey(s string) (string, int, error) {
+	if strings.HasPrefix(s, "\"") {
+		quoted, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return "", 0, fmt.Errorf("bad quoted key in %q", s)
+		}
+		key, _ := strconv.Unquote(quoted)
+		return key, len(quoted), nil
+	}
+	n := 0
+	for n < len(s) && isKeyChar(s[n]) {
+		n++
+	}
+	if n == 0 {
+		return "", 0, fmt.Errorf("expected a key at %q", s)
+	}
+	return s[:n], n, nil
+}
+
+// parseBracket parses what is inside [...]: nothing or * to iterate, a
+// quoted key, an index or a slice.
+func parseBracket(inner string) (func(v interface{}) []interface{}, error) {
+	switch {
+	case inner == "" || inner == "*":
+		return jsonIterate, nil
+	case strings.HasPrefix(inner, "\""):
+		key, n, err := parseKey(inner)
+		if err != nil || n != len(inner) {
+			return nil, fmt.Errorf("bad key [%s]", inner)
+		}
+		return jsonField(key), nil
+	case strings.Contains(inner, ":"):
+		from, to, _ := strings.Cut(inner, ":")
+		var bounds [2]*int
+		for j, b := range []string{from, to} {
+			if b = strings.TrimSpace(b); b == "" {
+				continue
+			}
+			n, err := strconv.Atoi(b)
+			if err != nil {
+				return nil, fmt.Errorf("bad slice [%s]", inner)
+			}
+			bounds[j] = &n
+		}
+		return jsonSlice(bounds[0], bounds[1]), nil
+	}
+	n, err := strconv.Atoi(inner)
+	if err != nil {
+		return nil, fmt.Errorf("bad index [%s]", inner)
+	}
+	return jsonIndex(n), nil
+}
+
+func jsonField(key string) func(v interface{}) []interface{} {
+	return func(v interface{}) []interface{} {
+		switch v := v.(type) {
+		case map[string]interface{}:
+			return []interface{}{v[key]}
+		case nil:
+			return []interface{}{nil}
+		}
+		return nil
+	}
+}
+
+// jsonPresentField is jsonField for objects that have key only.
+func jsonPresentField(key string) func(v interface{}) []interface{} {
+	return func(v interface{}) []interface{} {
+		if m, ok := v.(map[string]interface{}); ok {
+			if value, ok := m[key]; ok {
+				return []interface{}{value}
+			}
+		}
+		return nil
+	}
+}
+
+func jsonIndex(n int) func(v interface{}) []interface{} {
+	return func(v interface{}) []interface{} {
+		switch v := v.(type) {
+		case []interface{}:
+			i := n
+			if i < 0 {
+				i += len(v)
+			}
+			if i < 0 || i >= len(v) {
+				return []interface{}{nil}
+			}
+			return []interface{}{v[i]}
+		case nil:
+			return []interface{}{nil}
+		}
+		return nil
+	}
+}
+
+func jsonSlice(from, to *int) func(v interface{}) []interface{} {
+	return func(v interface{}) []interface{} {
+		array, ok := v.([]interface{})
+		if !ok {
+			return nil
+		}
+		bound := func(b *int, def int) int {
+			if b == nil {
+				return def
+			}
+			n := *b
+			if n < 0 {
+				n += len(array)
+			}
+			return max(0, min(n, len(array)))
+		}
+		start, end := bound(from, 0), bound(to, len(array))
+		if start >= end {
+			return []interface{}{[]interface{}{}}
+		}
+		return []interface{}{array[start:end]}
+	}
+}
+
+// jsonIterate yields the elements of an array, or the values of an object
+// in key order.
+func jsonIterate(v interface{}) []interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		return v
+	case map[string]interface{}:
+		keys := sortedKeys(v)
+		values := make([]interface{}, len(keys))
+		for i, k := range keys {
+			values[i] = v[k]
+		}
+		return values
+	}
+	return nil
+}
+
+// jsonDescendants yields v and every value inside it, depth first.
+func jsonDescendants(v interface{}) []interface{} {
+	out := []interface{}{v}
+	for _, child := range jsonIterate(v) {
+		out = append(out, jsonDescendants(child)...)
+	}
+	return out
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func jsonKeys(v interface{}) ([]interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := sortedKeys(v)
+		out := make([]interface{}, len(keys))
+		for i, k := range keys {
+			out[i] = k
+		}
+		return []interface{}{out}, nil
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i := range v {
+			out[i] = i
+		}
+		return []interface{}{out}, nil
+	}
+	return nil, fmt.Errorf("%s has no keys", jsonTypeName(v))
+}
+
+func jsonLength(v interface{}) ([]interface{}, error) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return []interface{}{len(v)}, nil
+	case []interface{}:
+		return []interface{}{len(v)}, nil
+	case string:
+		return []interface{}{len([]rune(v))}, nil
+	case nil:
+		return []interface{}{0}, nil
+	}
+	return nil, fmt.Errorf("%s has no length", jsonTypeName(v))
+}
+
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case nil:
+		return "null"
+	}
+	if _, ok := jsonNumber(v); ok {
+		return "number"
+	}
+	return fmt.Sprintf("%T", v)
+}
+
+var selectOperators = []string{"==", "!=", "<=", ">=", "<", ">"}
+
+// parseSelect parses the condition of select(...): a path, optionally
+// compared with a JSON literal.
+func parseSelect(cond string) (jsonStage, error) {
+	var op, left, right string
+	for _, candidate := range selectOperators {
+		parts := splitTopLevel(cond, candidate)
+		if len(parts) == 2 {
+			op, left, right = candidate, strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+			break
+		}
+	}
+	if op == "" {
+		left = strings.TrimSpace(cond)
+	}
+	path, err := parseJSONPath(left)
+	if err != nil {
+		return nil, err
+	}
+	var literal interface{}
+	if op != "" {
+		decoder := json.NewDecoder(strings.NewReader(right))
+		decoder.UseNumber()
+		if err := decoder.Decode(&literal); err != nil || decoder.More() {
+			return nil, fmt.Errorf("select compares with a JSON literal such as \"text\", 3 or null, not %s", right)
+		}
+	}
+	return func(v interface{}) ([]interface{}, error) {
+		values, err := path.apply(v)
+		if err != nil {
+			return nil, err
+		}
+		for _, got := range values {
+			if op == "" && got != nil && got != false {
+				return []interface{}{v}, nil
+			}
+			if op != "" && compareJSON(got, op, literal) {
+				return []interface{}{v}, nil
+			}
+		}
+		return nil, nil
+	}, nil
+}
+
+// splitTopLevel splits s at each sep outside quotes, brackets and
+// parentheses. A one-character sep followed by = is part of a longer
+// operator and does not split.
+func splitTopLevel(s, sep string) []string {
+	var parts []string
+	depth, start := 0, 0
+	inString := false
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case inString:
+			if c == '\\' {
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+		case c == '"':
+			inString = true
+		case c == '(' || c == '[':
+			depth++
+		case c == ')' || c == ']':
+			depth--
+		case depth == 0 && strings.HasPrefix(s[i:], sep):
+			if len(sep) == 1 && i+1 < len(s) && s[i+1] == '=' {
+				continue
+			}
+			parts = append(parts, s[start:i])
+			start = i + len(sep)
+			i += len(sep) - 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+func jsonNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func compareJSON(a interface{}, op string, b interface{}) bool {
+	var cmp int
+	x, aNum := jsonNumber(a)
+	y, bNum := jsonNumber(b)
+	as, aStr := a.(string)
+	bs, bStr := b.(string)
+	switch {
+	case aNum && bNum:
+		switch {
+		case x < y:
+			cmp = -1
+		case x > y:
+			cmp = 1
+		}
+	case aStr && bStr:
+		cmp = strings.Compare(as, bs)
+	default:
+		equal := reflect.DeepEqual(a, b)
+		switch op {
+		case "==":
+			return equal
+		case "!=":
+			return !equal
+		}
+		return false
+	}
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	}
+	return cmp >= 0
+}
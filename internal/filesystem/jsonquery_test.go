@@ -0,0 +1,92 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryJSON(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+
+	lock := filepath.Join(tempDir, "lock.json")
+	require.NoError(t, os.WriteFile(lock, []byte(`{
+		"name": "app",
+		"packages": [
+			{"name": "left-pad", "version": "1.3.0", "size": 12, "deps": {}},
+			{"name": "react", "version": "18.2.0", "size": 300, "deps": {"loose-envify": "^1.1.0"}},
+			{"name": "loose-envify", "version": "1.4.0", "size": 4}
+		],
+		"dev dependencies": {"jest": "29.0.0"}
+	}`), 0644))
+	manifest := filepath.Join(tempDir, "deploy.yaml")
+	require.NoError(t, os.WriteFile(manifest, []byte("items:\n  - kind: Service\n    metadata: {name: web}\n  - kind: Deployment\n    metadata: {name: web}\n  - kind: Service\n    metadata: {name: db}\n"), 0644))
+
+	query := func(path, q string, extra ...interface{}) ([]interface{}, map[string]interface{}, error) {
+		params := map[string]interface{}{"path": path, "query": q}
+		for i := 0; i+1 < len(extra); i += 2 {
+			params[extra[i].(string)] = extra[i+1]
+		}
+		result, err := server.queryJSONTool().Handler(context.Background(), params)
+		if err != nil {
+			return nil, nil, err
+		}
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out["results"].([]interface{}), out, nil
+	}
+
+	tests := []struct {
+		query string
+		want  []interface{}
+	}{
+		{".name", []interface{}{"app"}},
+		{"$.packages[1].version", []interface{}{"18.2.0"}},
+		{".packages[-1].name", []interface{}{"loose-envify"}},
+		{".packages[].name", []interface{}{"left-pad", "react", "loose-envify"}},
+		{".packages[1:].size", []interface{}{}},
+		{".packages[1:] | .[].size", []interface{}{float64(300), float64(4)}},
+		{".\"dev dependencies\".jest", []interface{}{"29.0.0"}},
+		{".[\"dev dependencies\"] | keys", []interface{}{[]interface{}{"jest"}}},
+		{".packages | length", []interface{}{float64(3)}},
+		{".packages[] | select(.size > 10) | .name", []interface{}{"left-pad", "react"}},
+		{".packages[] | select(.version == \"1.4.0\") | .name", []interface{}{"loose-envify"}},
+		{".packages[] | select(.deps) | .name", []interface{}{"left-pad", "react"}},
+		{"..version", []interface{}{"1.3.0", "18.2.0", "1.4.0"}},
+		{".missing", []interface{}{nil}},
+		{".name.first", []interface{}{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, _, err := query(lock, tt.query)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, results)
+		})
+	}
+
+	t.Run("yaml", func(t *testing.T) {
+		results, _, err := query(manifest, `.items[] | select(.kind == "Service") | .metadata.name`)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"web", "db"}, results)
+	})
+
+	t.Run("max results", func(t *testing.T) {
+		results, out, err := query(lock, "..", "max_results", 2)
+		require.NoError(t, err)
+		assert.Len(t, results, 2)
+		assert.Equal(t, true, out["truncated"])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, q := range []string{"name", ".packages[", ".a | | .b", ".packages[] | select(.size > big)", ".name | keys"} {
+			_, _, err := query(lock, q)
+			assert.Error(t, err, q)
+		}
+	})
+}
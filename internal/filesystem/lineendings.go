@@ -0,0 +1,149 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// countLineEndings counts each kind of line ending in text.
+func countLineEndings(text string) (lf, crlf, cr int) {
+	for i := 0; i < len(text); i++ {
+		switch {
+		case text[i] == '\r' && i+1 < len(text) && text[i+1] == '\n':
+			crlf++
+			i++
+		case text[i] == '\r':
+			cr++
+		case text[i] == '\n':
+			lf++
+		}
+	}
+	return lf, crlf, cr
+}
+
+// lineEndingStyle names the line endings of text: lf, crlf or cr, mixed
+// if it has more than one kind, or none.
+func lineEndingStyle(text string) string {
+	lf, crlf, cr := countLineEndings(text)
+	styles := 0
+	style := "none"
+	for name, n := range map[string]int{"lf": lf, "crlf": crlf, "cr": cr} {
+		if n > 0 {
+			styles++
+			style = name
+		}
+	}
+	if styles > 1 {
+		return "mixed"
+	}
+	return style
+}
+
+// convertLineEndings rewrites every line ending in text as style, lf or
+// crlf.
+func convertLineEndings(text, style string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	if style == "crlf" {
+		text = strings.ReplaceAll(text, "\n", "\r\n")
+	}
+	return text
+}
+
+// usesCRLF reports whether most line endings in text are CRLF.
+func usesCRLF(text string) bool {
+	lf, crlf, cr := countLineEndings(text)
+	return crlf > lf+cr
+}
+
+// applyEditsKeepingEndings applies edits to a file that uses CRLF as if
+// it used LF, so edits written with LF match, and writes the result back
+// with CRLF.
+func applyEditsKeepingEndings(content string, edits []textEdit, patch string) (string, int, error) {
+	if !usesCRLF(content) {
+		return applyEdits(content, edits, patch)
+	}
+	after, replacements, err := applyEdits(convertLineEndings(content, "lf"), edits, patch)
+	if err != nil {
+		return "", 0, err
+	}
+	return convertLineEndings(after, "crlf"), replacements, nil
+}
+
+func (s *Server) lineEndingsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "line_endings",
+		Description: "Report a text file's line endings (lf, crlf, cr, mixed or none) with a count of each, and optionally convert them all to lf or crlf",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":    mcp.StringProperty("Absolute path to the file"),
+				"convert": mcp.StringProperty("Rewrite every line ending as lf or crlf; omit to only report"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleLineEndings),
+	}
+}
+
+type lineEndingsArgs struct {
+	Path    string `json:"path" validate:"required"`
+	Convert string `json:"convert" validate:"oneof=lf crlf"`
+}
+
+func (s *Server) handleLineEndings(ctx context.Context, args lineEndingsArgs) (*mcp.ToolResult, error) {
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	content, info, err := s.readForEdit(absPath, args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if enc := detectEncoding([]byte(content)); enc == "" || strings.HasPrefix(enc, "utf-16") {
+		return nil, fmt.Errorf("%w: %s is binary or UTF-16; convert it to UTF-8 first", common.ErrInvalidInput, args.Path)
+	}
+
+	lf, crlf, cr := countLineEndings(content)
+	result := map[string]interface{}{
+		"path":          absPath,
+		"style":         lineEndingStyle(content),
+		"lf":            lf,
+		"crlf":          crlf,
+		"cr":            cr,
+		"final_newline": strings.HasSuffix(content, "\n") || strings.HasSuffix(content, "\r"),
+	}
+	if args.Convert != "" {
+		converted := convertLineEndings(content, args.Convert)
+		if converted != content {
+			if err := writeFileAtomic(absPath, []byte(converted), info.Mode().Perm()); err != nil {
+				return nil, err
+			}
+		}
+		result["converted_to"] = args.Convert
+		result["changed"] = converted != content
+	}
+	return mcp.JSONResult(result)
+}
+
+// existingLineEnding returns crlf if the file at path mostly uses CRLF
+// line endings, lf if it uses others, or "" if it has none or does not
+// exist.
+func existingLineEnding(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	switch lf, crlf, cr := countLineEndings(string(data)); {
+	case crlf > lf+cr:
+		return "crlf"
+	case lf+cr > 0:
+		return "lf"
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLineEndings(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+
+	lineEndings := func(params map[string]interface{}) map[string]interface{} {
+		result, err := server.lineEndingsTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out
+	}
+
+	t.Run("report", func(t *testing.T) {
+		for content, style := range map[string]string{
+			"a\nb\n":     "lf",
+			"a\r\nb\r\n": "crlf",
+			"a\rb\r":     "cr",
+			"a\r\nb\n":   "mixed",
+			"a":          "none",
+		} {
+			path := filepath.Join(tempDir, "report.txt")
+			require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			assert.Equal(t, style, lineEndings(map[string]interface{}{"path": path})["style"], "%q", content)
+		}
+
+		path := filepath.Join(tempDir, "counts.txt")
+		require.NoError(t, os.WriteFile(path, []byte("a\r\nb\nc\r\nd"), 0644))
+		out := lineEndings(map[string]interface{}{"path": path})
+		assert.EqualValues(t, 1, out["lf"])
+		assert.EqualValues(t, 2, out["crlf"])
+		assert.Equal(t, false, out["final_newline"])
+	})
+
+	t.Run("convert", func(t *testing.T) {
+		path := filepath.Join(tempDir, "convert.txt")
+		require.NoError(t, os.WriteFile(path, []byte("a\r\nb\nc\r"), 0644))
+		out := lineEndings(map[string]interface{}{"path": path, "convert": "crlf"})
+		assert.Equal(t, true, out["changed"])
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, "a\r\nb\r\nc\r\n", string(data))
+
+		lineEndings(map[string]interface{}{"path": path, "convert": "lf"})
+		data, _ = os.ReadFile(path)
+		assert.Equal(t, "a\nb\nc\n", string(data))
+
+		_, err := server.lineEndingsTool().Handler(context.Background(), map[string]interface{}{"path": path, "convert": "cr"})
+		assert.Error(t, err)
+	})
+
+	t.Run("write preserves", func(t *testing.T) {
+		path := filepath.Join(tempDir, "windows.txt")
+		require.NoError(t, os.WriteFile(path, []byte("old\r\nfile\r\n"), 0644))
+		_, err := server.writeFileTool().Handler(context.Background(), map[string]interface{}{
+			"path": path, "content": "new\ncontent\n", "preserve_line_endings": true,
+		})
+		require.NoError(t, err)
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, "new\r\ncontent\r\n", string(data))
+
+		fresh := filepath.Join(tempDir, "fresh.txt")
+		_, err = server.writeFileTool().Handler(context.Background(), map[string]interface{}{
+			"path": fresh, "content": "as\r\ngiven\n", "preserve_line_endings": true,
+		})
+		require.NoError(t, err)
+		data, _ = os.ReadFile(fresh)
+		assert.Equal(t, "as\r\ngiven\n", string(data))
+	})
+
+	t.Run("edit preserves", func(t *testing.T) {
+		path := filepath.Join(tempDir, "edit.txt")
+		require.NoError(t, os.WriteFile(path, []byte("one\r\ntwo\r\nthree\r\n"), 0644))
+		edit := map[string]interface{}{"path": path, "edits": []interface{}{
+			map[string]interface{}{"old_text": "one\ntwo", "new_text": "one\n2\nextra"},
+		}}
+		_, err := server.editFileTool().Handler(context.Background(), edit)
+		assert.Error(t, err, "LF text does not match CRLF")
+
+		edit["preserve_line_endings"] = true
+		_, err = server.editFileTool().Handler(context.Background(), edit)
+		require.NoError(t, err)
+		data, _ := os.ReadFile(path)
+		assert.Equal(t, "one\r\n2\r\nextra\r\nthree\r\n", string(data))
+
+		_, err = server.multiEditTool().Handler(context.Background(), map[string]interface{}{
+			"preserve_line_endings": true,
+			"files": []interface{}{map[string]interface{}{"path": path, "edits": []interface{}{
+				map[string]interface{}{"old_text": "extra\nthree\n", "new_text": "three\n"},
+			}}},
+		})
+		require.NoError(t, err)
+		data, _ = os.ReadFile(path)
+		assert.Equal(t, "one\r\n2\r\nthree\r\n", string(data))
+	})
+}
@@ -0,0 +1,299 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+const (
+	// lockWaitInterval is how often lock_file retries while it waits.
+	lockWaitInterval = 50 * time.Millisecond
+	// maxLeases bounds the leases held at once.
+	maxLeases = 256
+)
+
+// lease is an advisory lock taken with lock_file. Writes to its path, or
+// anything under it, by calls that do not give its id fail until it is
+// released or expires.
+type lease struct {
+	id      string
+	path    string
+	expires time.Time
+}
+
+// lockManager serializes writes to the same paths and keeps the leases.
+// Two paths conflict if they are the same or one is inside the other.
+type lockManager struct {
+	mu   sync.Mutex
+	cond *sync.Cond
+	// writing counts the tool calls writing each path.
+	writing map[string]int
+	leases  map[string]*lease
+}
+
+func newLockManager() *lockManager {
+	m := &lockManager{writing: make(map[string]int), leases: make(map[string]*lease)}
+	m.cond = sync.NewCond(&m.mu)
+	return m
+}
+
+func pathsOverlap(a, b string) bool {
+	return a == b || strings.HasPrefix(b, a+string(filepath.Separator)) || strings.HasPrefix(a, b+string(filepath.Separator))
+}
+
+// leaseBlocking returns a live lease on a path overlapping path that is
+// not the lease id, or nil. m.mu must be held.
+func (m *lockManager) leaseBlocking(path, id string) *lease {
+	now := time.Now()
+	for _, l := range m.leases {
+		if now.After(l.expires) {
+			delete(m.leases, l.id)
+			continue
+		}
+		if l.id != id && pathsOverlap(l.path, path) {
+			return l
+		}
+	}
+	return nil
+}
+
+// acquire waits until no other call is writing paths, and fails if one is
+// leased by someone other than lockID. The returned release must be
+// called when the write is done.
+func (m *lockManager) acquire(paths []string, lockID string) (func(), error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for {
+		busy := false
+		for _, p := range paths {
+			if l := m.leaseBlocking(p, lockID); l != nil {
+				return nil, fmt.Errorf("%w: %s is locked by lease %s until %s; give its lock_id or wait for unlock_file",
+					common.ErrOperationFailed, p, l.id, l.expires.UTC().Format(time.RFC3339))
+			}
+			for w := range m.writing {
+				busy = busy || pathsOverlap(w, p)
+			}
+		}
+		if !busy {
+			break
+		}
+		m.cond.Wait()
+	}
+	for _, p := range paths {
+		m.writing[p]++
+	}
+	return func() {
+		m.mu.Lock()
+		for _, p := range paths {
+			if m.writing[p]--; m.writing[p] == 0 {
+				delete(m.writing, p)
+			}
+		}
+		m.mu.Unlock()
+		m.cond.Broadcast()
+	}, nil
+}
+
+// lock takes or renews a lease on path. A renewal gives the id of the
+// lease to extend.
+func (m *lockManager) lock(path, id string, ttl time.Duration) (*lease, *lease) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if blocking := m.leaseBlocking(path, id); blocking != nil {
+		return nil, blocking
+	}
+	if l, ok := m.leases[id]; ok && l.path == path {
+		l.expires = time.Now().Add(ttl)
+		copied := *l
+		return &copied, nil
+	}
+	l := &lease{id: uuid.NewString(), path: path, expires: time.Now().Add(ttl)}
+	m.leases[l.id] = l
+	copied := *l
+	return &copied, nil
+}
+
+func (m *lockManager) unlock(id string) (*lease, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	l, ok := m.leases[id]
+	if !ok || time.Now().After(l.expires) {
+		delete(m.leases, id)
+		return nil, false
+	}
+	delete(m.leases, id)
+	return l, true
+}
+
+func (m *lockManager) count() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	for id, l := range m.leases {
+		if now.After(l.expires) {
+			delete(m.leases, id)
+		}
+	}
+	return len(m.leases)
+}
+
+// writeLocked wraps a tool that writes the paths named by keys so that
+// calls writing overlapping paths run one at a time and respect leases.
+// A key like "files[].path" names a field of each element of an array
+// parameter. The tool gains a lock_id parameter for writing leased paths.
+func (s *Server) writeLocked(tool *mcp.Tool, keys ...string) *mcp.Tool {
+	handler := tool.Handler
+	locked := *tool
+	properties := make(map[string]interface{})
+	if schema, ok := tool.InputSchema["properties"].(map[string]interface{}); ok {
+		for k, v := range schema {
+			properties[k] = v
+		}
+	}
+	properties["lock_id"] = mcp.StringProperty("Id of a lease from lock_file on the paths this call writes")
+	locked.InputSchema = make(map[string]interface{})
+	for k, v := range tool.InputSchema {
+		locked.InputSchema[k] = v
+	}
+	locked.InputSchema["properties"] = properties
+
+	locked.Handler = func(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
+		lockID, _ := params["lock_id"].(string)
+		args := make(map[string]interface{}, len(params))
+		for k, v := range params {
+			if k != "lock_id" {
+				args[k] = v
+			}
+		}
+		release, err := s.locks.acquire(lockPaths(params, keys), lockID)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return handler(ctx, args)
+	}
+	return &locked
+}
+
+// lockPaths collects the absolute paths named by keys in params.
+func lockPaths(params map[string]interface{}, keys []string) []string {
+	var paths []string
+	add := func(v interface{}) {
+		if p, ok := v.(string); ok && p != "" {
+			if abs, err := filepath.Abs(p); err == nil {
+				paths = append(paths, abs)
+			}
+		}
+	}
+	for _, key := range keys {
+		array, field, nested := strings.Cut(key, "[].")
+		if !nested {
+			add(params[key])
+			continue
+		}
+		items, _ := params[array].([]interface{})
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				add(m[field])
+			}
+		}
+	}
+	return paths
+}
+
+func (s *Server) lockFileTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "lock_file",
+		Description: "Take an advisory lock (a lease) on a file or directory so that no other tool call can write it, or anything under it, until unlock_file or the lease expires. Writing tools take the lease's lock_id to write locked paths. Give lock_id again before the lease expires to renew it. Leases are held by this server only; other programs can still write the file",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":         mcp.StringProperty("Absolute path to lock; it need not exist yet"),
+				"ttl_seconds":  mcp.IntProperty("How long the lease lasts (default: 60, max: 3600)"),
+				"wait_seconds": mcp.IntProperty("How long to wait for another lease on the path to end (default: 0, fail at once; max: 300)"),
+				"lock_id":      mcp.StringProperty("Id of a lease on the same path to renew"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleLockFile),
+	}
+}
+
+type lockFileArgs struct {
+	Path        string `json:"path" validate:"required"`
+	TTLSeconds  int    `json:"ttl_seconds" default:"60" validate:"min=1,max=3600"`
+	WaitSeconds int    `json:"wait_seconds" validate:"min=0,max=300"`
+	LockID      string `json:"lock_id"`
+}
+
+func (s *Server) handleLockFile(ctx context.Context, args lockFileArgs) (*mcp.ToolResult, error) {
+	absPath, err := filepath.Abs(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.ValidatePath(absPath); err != nil {
+		return nil, err
+	}
+	if args.LockID == "" && s.locks.count() >= maxLeases {
+		return nil, fmt.Errorf("%w: %d leases are already held; release one with unlock_file", common.ErrOperationFailed, maxLeases)
+	}
+
+	deadline := time.Now().Add(time.Duration(args.WaitSeconds) * time.Second)
+	for {
+		l, blocking := s.locks.lock(absPath, args.LockID, time.Duration(args.TTLSeconds)*time.Second)
+		if l != nil {
+			return mcp.JSONResult(map[string]interface{}{
+				"lock_id":    l.id,
+				"path":       l.path,
+				"expires_at": l.expires.UTC().Format(time.RFC3339),
+			})
+		}
+		if !time.Now().Before(deadline) {
+			return nil, fmt.Errorf("%w: %s is locked by lease %s until %s", common.ErrOperationFailed, args.Path, blocking.id, blocking.expires.UTC().Format(time.RFC3339))
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockWaitInterval):
+		}
+	}
+}
+
+func (s *Server) unlockFileTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "unlock_file",
+		Description: "Release a lease taken with lock_file",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"lock_id": mcp.StringProperty("Id lock_file returned"),
+			},
+			[]string{"lock_id"},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleUnlockFile),
+	}
+}
+
+type unlockFileArgs struct {
+	LockID string `json:"lock_id" validate:"required"`
+}
+
+func (s *Server) handleUnlockFile(ctx context.Context, args unlockFileArgs) (*mcp.ToolResult, error) {
+	l, ok := s.locks.unlock(args.LockID)
+	if !ok {
+		return nil, fmt.Errorf("%w: no lease %s; it may have expired", common.ErrNotFound, args.LockID)
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"lock_id":  l.id,
+		"path":     l.path,
+		"released": true,
+	})
+}
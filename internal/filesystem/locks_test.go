@@ -0,0 +1,118 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLockFile(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	writeFile := server.writeLocked(server.writeFileTool(), "path")
+	path := filepath.Join(tempDir, "shared.txt")
+
+	lock := func(params map[string]interface{}) (map[string]interface{}, error) {
+		result, err := server.lockFileTool().Handler(context.Background(), params)
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out, nil
+	}
+	write := func(path, lockID string) error {
+		params := map[string]interface{}{"path": path, "content": "x"}
+		if lockID != "" {
+			params["lock_id"] = lockID
+		}
+		_, err := writeFile.Handler(context.Background(), params)
+		return err
+	}
+
+	t.Run("lease blocks other writers", func(t *testing.T) {
+		out, err := lock(map[string]interface{}{"path": path})
+		require.NoError(t, err)
+		id := out["lock_id"].(string)
+
+		assert.ErrorContains(t, write(path, ""), "is locked by lease")
+		require.NoError(t, write(path, id))
+
+		_, err = lock(map[string]interface{}{"path": path})
+		assert.Error(t, err, "a second lease on the same path")
+
+		renewed, err := lock(map[string]interface{}{"path": path, "lock_id": id, "ttl_seconds": 120})
+		require.NoError(t, err)
+		assert.Equal(t, id, renewed["lock_id"])
+
+		_, err = server.unlockFileTool().Handler(context.Background(), map[string]interface{}{"lock_id": id})
+		require.NoError(t, err)
+		require.NoError(t, write(path, ""))
+
+		_, err = server.unlockFileTool().Handler(context.Background(), map[string]interface{}{"lock_id": id})
+		assert.Error(t, err)
+	})
+
+	t.Run("directory lease covers its files", func(t *testing.T) {
+		dir := filepath.Join(tempDir, "locked")
+		require.NoError(t, os.Mkdir(dir, 0755))
+		out, err := lock(map[string]interface{}{"path": dir})
+		require.NoError(t, err)
+		defer server.locks.unlock(out["lock_id"].(string))
+
+		assert.Error(t, write(filepath.Join(dir, "a.txt"), ""))
+		require.NoError(t, write(filepath.Join(tempDir, "lockedness.txt"), ""))
+	})
+
+	t.Run("wait for expiry", func(t *testing.T) {
+		other := filepath.Join(tempDir, "other.txt")
+		_, err := lock(map[string]interface{}{"path": other, "ttl_seconds": 1})
+		require.NoError(t, err)
+		start := time.Now()
+		_, err = lock(map[string]interface{}{"path": other, "wait_seconds": 5})
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, time.Since(start), 500*time.Millisecond)
+	})
+
+	t.Run("outside allowed paths", func(t *testing.T) {
+		_, err := lock(map[string]interface{}{"path": "/etc/passwd"})
+		assert.Error(t, err)
+	})
+}
+
+func TestLockManagerSerializesWrites(t *testing.T) {
+	m := newLockManager()
+	release, err := m.acquire([]string{"/work/dir"}, "")
+	require.NoError(t, err)
+
+	acquired := make(chan struct{})
+	go func() {
+		release, err := m.acquire([]string{"/work/dir/file.txt"}, "")
+		assert.NoError(t, err)
+		close(acquired)
+		release()
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("overlapping write did not wait")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Unrelated paths do not wait.
+	other, err := m.acquire([]string{"/work/directory"}, "")
+	require.NoError(t, err)
+	other()
+
+	release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("write still waiting after release")
+	}
+}
@@ -0,0 +1,155 @@
+package filesystem
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) multiEditTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "multi_edit",
+		Description: "Apply edits to one or more files as a single transaction: every edit is checked before any file is written, and if one fails no file changes. Each entry takes the same edits and patch as edit_file; entries for the same file apply in order",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"files": mcp.ObjectArrayProperty("Files to edit", map[string]interface{}{
+					"path": mcp.StringProperty("Absolute path to the file"),
+					"edits": mcp.ObjectArrayProperty("Replacements applied in order", map[string]interface{}{
+						"old_text":             mcp.StringProperty("Exact text to replace"),
+						"new_text":             mcp.StringProperty("Replacement text"),
+						"expected_occurrences": mcp.IntProperty("How many times old_text must occur; all of them are replaced (default: 1)"),
+					}),
+					"patch": mcp.StringProperty("Unified diff to apply to this file"),
+				}),
+				"dry_run":               mcp.BoolProperty("Report the hunks without writing any file"),
+				"preserve_line_endings": mcp.BoolProperty("For files that use CRLF line endings, match edits and patches written with LF against them and keep CRLF in the result"),
+			},
+			[]string{"files"},
+		),
+		Annotations: mcp.Destructive(),
+		Handler:     mcp.TypedHandler(s.handleMultiEdit),
+	}
+}
+
+type fileEdit struct {
+	Path  string     `json:"path"`
+	Edits []textEdit `json:"edits"`
+	Patch string     `json:"patch"`
+}
+
+type multiEditArgs struct {
+	Files               []fileEdit `json:"files" validate:"required,min=1"`
+	DryRun              bool       `json:"dry_run"`
+	PreserveLineEndings bool       `json:"preserve_line_endings"`
+}
+
+// pendingEdit is a file's content before and after a multi_edit.
+type pendingEdit struct {
+	path          string
+	before, after string
+	perm          os.FileMode
+	replacements  int
+}
+
+func (s *Server) handleMultiEdit(ctx context.Context, args multiEditArgs) (*mcp.ToolResult, error) {
+	var order []*pendingEdit
+	pending := make(map[string]*pendingEdit)
+	for i, f := range args.Files {
+		if f.Path == "" {
+			return nil, fmt.Errorf("%w: files[%d]: path is required", common.ErrInvalidInput, i)
+		}
+		if len(f.Edits) == 0 && f.Patch == "" {
+			return nil, fmt.Errorf("%w: files[%d]: give edits, a patch or both", common.ErrInvalidInput, i)
+		}
+		absPath, err := s.validator.ResolvePath(f.Path)
+		if err != nil {
+			return nil, err
+		}
+		p, ok := pending[absPath]
+		if !ok {
+			content, info, err := s.readForEdit(absPath, f.Path)
+			if err != nil {
+				return nil, err
+			}
+			p = &pendingEdit{path: absPath, before: content, after: content, perm: info.Mode().Perm()}
+			pending[absPath] = p
+			order = append(order, p)
+		}
+		apply := applyEdits
+		if args.PreserveLineEndings {
+			apply = applyEditsKeepingEndings
+		}
+		after, replacements, err := apply(p.after, f.Edits, f.Patch)
+		if err != nil {
+			return nil, fmt.Errorf("files[%d] (%s): %w", i, f.Path, err)
+		}
+		p.after = after
+		p.replacements += replacements
+	}
+
+	if !args.DryRun {
+		if err := commitEdits(order); err != nil {
+			return nil, err
+		}
+	}
+
+	files := make([]map[string]interface{}, 0, len(order))
+	for _, p := range order {
+		hunks := makeHunks(diffLines(splitLines(p.before), splitLines(p.after)), diffContext)
+		files = append(files, map[string]interface{}{
+			"path":         p.path,
+			"replacements": p.replacements,
+			"hunks":        nonNilHunks(hunks),
+			"diff":         formatUnified(p.path, p.path, hunks),
+		})
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"files":   files,
+		"dry_run": args.DryRun,
+	})
+}
+
+// commitEdits writes every changed file, or none: all new contents are
+// staged in temporary files first, and if renaming one into place fails the
+// files already replaced get their old contents back.
+func commitEdits(edits []*pendingEdit) error {
+	var changed []*pendingEdit
+	var staged []string
+	for _, p := range edits {
+		if p.after == p.before {
+			continue
+		}
+		tmp, err := stageFile(p.path, []byte(p.after), p.perm)
+		if err != nil {
+			for _, t := range staged {
+				os.Remove(t)
+			}
+			return fmt.Errorf("%w: staging %s: %v; no file was changed", common.ErrOperationFailed, p.path, err)
+		}
+		changed = append(changed, p)
+		staged = append(staged, tmp)
+	}
+
+	for i, p := range changed {
+		if err := os.Rename(staged[i], p.path); err != nil {
+			for _, t := range staged[i:] {
+				os.Remove(t)
+			}
+			var rollbackErr error
+			for _, done := range changed[:i] {
+				if err := writeFileAtomic(done.path, []byte(done.before), done.perm); err != nil {
+					rollbackErr = errors.Join(rollbackErr, fmt.Errorf("restoring %s: %w", done.path, err))
+				}
+			}
+			if rollbackErr != nil {
+				return fmt.Errorf("%w: writing %s: %v; rollback failed: %v", common.ErrOperationFailed, p.path, err, rollbackErr)
+			}
+			return fmt.Errorf("%w: writing %s: %v; changes were rolled back", common.ErrOperationFailed, p.path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,785 @@
+package filesystem
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+// This is a minimal PDF reader, enough to pull the text out of the pages of
+// ordinary documents. It finds objects by scanning for "obj" rather than
+// trusting the cross-reference table, reads compressed object streams, and
+// decodes FlateDecode streams only. Encrypted documents are rejected.
+
+type (
+	pdfName    string
+	pdfKeyword string
+	pdfDelim   string
+	pdfDict    map[string]interface{}
+	pdfRef     struct{ num, gen int }
+	pdfStream  struct {
+		dict pdfDict
+		raw  []byte
+	}
+)
+
+type pdfLexer struct {
+	data []byte
+	pos  int
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\n' || c == '\r' || c == '\t' || c == '\f' || c == 0
+}
+
+func isPDFDelim(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func (l *pdfLexer) skipSpace() {
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		switch {
+		case isPDFSpace(c):
+			l.pos++
+		case c == '%':
+			for l.pos < len(l.data) && l.data[l.pos] != '\n' && l.data[l.pos] != '\r' {
+				l.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// token reads the next token: a number, name, string, keyword or delimiter.
+// It returns io.EOF at the end of the data.
+func (l *pdfLexer) token() (interface{}, error) {
+	l.skipSpace()
+	if l.pos >= len(l.data) {
+		return nil, io.EOF
+	}
+	c := l.data[l.pos]
+	switch {
+	case c == '/':
+		l.pos++
+		return pdfName(l.regular(true)), nil
+	case c == '(':
+		l.pos++
+		return l.literalString(), nil
+	case c == '<' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '<':
+		l.pos += 2
+		return pdfDelim("<<"), nil
+	case c == '>' && l.pos+1 < len(l.data) && l.data[l.pos+1] == '>':
+		l.pos += 2
+		return pdfDelim(">>"), nil
+	case c == '<':
+		l.pos++
+		return l.hexString(), nil
+	case c == '[' || c == ']' || c == '{' || c == '}':
+		l.pos++
+		return pdfDelim([]byte{c}), nil
+	case isPDFDelim(c):
+		// A stray ) or >.
+		l.pos++
+		return l.token()
+	}
+	word := l.regular(false)
+	if strings.ContainsAny(word[:1], "+-.0123456789") {
+		if n, err := strconv.ParseFloat(word, 64); err == nil {
+			return n, nil
+		}
+	}
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	return pdfKeyword(word), nil
+}
+
+// regular reads a run of regular characters, decoding #xx escapes in names.
+func (l *pdfLexer) regular(name bool) string {
+	start := l.pos
+	for l.pos < len(l.data) && !isPDFSpace(l.data[l.pos]) && !isPDFDelim(l.data[l.pos]) {
+		l.pos++
+	}
+	word := string(l.data[start:l.pos])
+	if name && strings.Contains(word, "#") {
+		var b strings.Builder
+		for i := 0; i < len(word); i++ {
+			if word[i] == '#' && i+2 < len(word) {
+				if n, err := strconv.ParseUint(word[i+1:i+3], 16, 8); err == nil {
+					b.WriteByte(byte(n))
+					i += 2
+					continue
+				}
+			}
+			b.WriteByte(word[i])
+		}
+		word = b.String()
+	}
+	if word == "" && !name {
+		// Never return an empty keyword, which would loop forever.
+		l.pos++
+		return string(l.data[start:l.pos])
+	}
+	return word
+}
+
+func (l *pdfLexer) literalString() []byte {
+	var out []byte
+	depth := 1
+	for l.pos < len(l.data) {
+		c := l.data[l.pos]
+		l.pos++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return out
+			}
+		case '\\':
+			if l.pos >= len(l.data) {
+				return out
+			}
+			c = l.data[l.pos]
+			l.pos++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r':
+				if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+					l.pos++
+				}
+				continue
+			case '\n':
+				continue
+			}
+			if c >= '0' && c <= '7' {
+				n := int(c - '0')
+				for i := 0; i < 2 && l.pos < len(l.data) && l.data[l.pos] >= '0' && l.data[l.pos] <= '7'; i++ {
+					n = n*8 + int(l.data[l.pos]-'0')
+					l.pos++
+				}
+				c = byte(n)
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+func (l *pdfLexer) hexString() []byte {
+	var digits []byte
+	for l.pos < len(l.data) && l.data[l.pos] != '>' {
+		if c := l.data[l.pos]; strings.IndexByte("0123456789abcdefABCDEF", c) >= 0 {
+			digits = append(digits, c)
+		}
+		l.pos++
+	}
+	l.pos++
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+	out := make([]byte, len(digits)/2)
+	for i := range out {
+		n, _ := strconv.ParseUint(string(digits[2*i:2*i+2]), 16, 8)
+		out[i] = byte(n)
+	}
+	return out
+}
+
+// object reads a whole object: a dictionary or array with everything in
+// it, an indirect reference, or a single token.
+func (l *pdfLexer) object() (interface{}, error) {
+	tok, err := l.token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case pdfDelim("<<"):
+		dict := pdfDict{}
+		for {
+			key, err := l.object()
+			if err != nil || key == pdfDelim(">>") {
+				return dict, err
+			}
+			value, err := l.object()
+			if err != nil {
+				return dict, err
+			}
+			if name, ok := key.(pdfName); ok {
+				dict[string(name)] = value
+			}
+		}
+	case pdfDelim("["):
+		var array []interface{}
+		for {
+			item, err := l.object()
+			if err != nil || item == pdfDelim("]") {
+				return array, err
+			}
+			array = append(array, item)
+		}
+	}
+	if n, ok := tok.(float64); ok && n == float64(int(n)) {
+		// Look ahead for "gen R".
+		save := l.pos
+		if gen, err := l.token(); err == nil {
+			if g, ok := gen.(float64); ok {
+				if r, err := l.token(); err == nil && r == pdfKeyword("R") {
+					return pdfRef{int(n), int(g)}, nil
+				}
+			}
+		}
+		l.pos = save
+	}
+	return tok, nil
+}
+
+// skipInlineImage moves past the data of an inline image, from just after
+// its BI operator to after EI.
+func (l *pdfLexer) skipInlineImage() {
+	id := bytes.Index(l.data[l.pos:], []byte("ID"))
+	if id < 0 {
+		l.pos = len(l.data)
+		return
+	}
+	l.pos += id + 2
+	if loc := inlineImageEnd.FindIndex(l.data[l.pos:]); loc != nil {
+		l.pos += loc[1]
+		return
+	}
+	l.pos = len(l.data)
+}
+
+var (
+	inlineImageEnd = regexp.MustCompile(`\sEI(\s|$)`)
+	pdfObjectStart = regexp.MustCompile(`(\d+)\s+(\d+)\s+obj\b`)
+)
+
+type pdfDocument struct {
+	objects map[int]interface{}
+	trailer pdfDict
+	// budget is how many more bytes decoding streams may produce.
+	budget int64
+	cmaps  map[pdfRef]*pdfCMap
+}
+
+// parsePDF reads every object in data. maxDecoded bounds the total size of
+// the streams decoded from it.
+func parsePDF(data []byte, maxDecoded int64) (*pdfDocument, error) {
+	if !bytes.Contains(data[:min(len(data), 1024)], []byte("%PDF-")) {
+		return nil, fmt.Errorf("%w: not a PDF file", common.ErrInvalidInput)
+	}
+	doc := &pdfDocument{objects: make(map[int]interface{}), trailer: pdfDict{}, budget: maxDecoded, cmaps: make(map[pdfRef]*pdfCMap)}
+
+	// Later definitions replace earlier ones, as incremental updates do.
+	streamEnd := 0
+	for _, m := range pdfObjectStart.FindAllSubmatchIndex(data, -1) {
+		if m[0] < streamEnd {
+			// Stream data that happens to look like an object.
+			continue
+		}
+		num, _ := strconv.Atoi(string(data[m[2]:m[3]]))
+		l := &pdfLexer{data: data, pos: m[1]}
+		value, err := l.object()
+		if err != nil {
+			continue
+		}
+		if dict, ok := value.(pdfDict); ok {
+			if raw, ok := streamData(l, dict); ok {
+				value = &pdfStream{dict: dict, raw: raw}
+				streamEnd = l.pos + len(raw)
+				if dict["Type"] == pdfName("XRef") {
+					doc.addTrailer(dict)
+				}
+			}
+		}
+		doc.objects[num] = value
+	}
+	for _, at := range trailerStart.FindAllIndex(data, -1) {
+		l := &pdfLexer{data: data, pos: at[1]}
+		if dict, err := l.object(); err == nil {
+			if dict, ok := dict.(pdfDict); ok {
+				doc.addTrailer(dict)
+			}
+		}
+	}
+	if doc.trailer["Encrypt"] != nil {
+		return nil, fmt.Errorf("%w: encrypted PDFs are not supported", common.ErrInvalidInput)
+	}
+
+	// Objects may also be packed into compressed object streams.
+	nums := make([]int, 0, len(doc.objects))
+	for num := range doc.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		stream, ok := doc.objects[num].(*pdfStream)
+		if !ok || stream.dict["Type"] != pdfName("ObjStm") {
+			continue
+		}
+		if err := doc.readObjectStream(stream); err != nil {
+			return nil, err
+		}
+	}
+	return doc, nil
+}
+
+var trailerStart = regexp.MustCompile(`\btrailer\b`)
+
+func (doc *pdfDocument) addTrailer(dict pdfDict) {
+	for _, key := range []string{"Root", "Encrypt"} {
+		if dict[key] != nil {
+			doc.trailer[key] = dict[key]
+		}
+	}
+}
+
+// streamData returns the raw bytes of the stream that follows dict, if one
+// does.
+func streamData(l *pdfLexer, dict pdfDict) ([]byte, bool) {
+	save := l.pos
+	if tok, err := l.token(); err != nil || tok != pdfKeyword("stream") {
+		l.pos = save
+		return nil, false
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\r' {
+		l.pos++
+	}
+	if l.pos < len(l.data) && l.data[l.pos] == '\n' {
+		l.pos++
+	}
+	rest := l.data[l.pos:]
+	if n, ok := dict["Length"].(float64); ok && n >= 0 && int(n) <= len(rest) {
+		if after := bytes.TrimLeft(rest[int(n):], "\r\n \t"); bytes.HasPrefix(after, []byte("endstream")) {
+			return rest[:int(n)], true
+		}
+	}
+	// The length is indirect or wrong; use the end marker.
+	end := bytes.Index(rest, []byte("endstream"))
+	if end < 0 {
+		return nil, false
+	}
+	return bytes.TrimRight(rest[:end], "\r\n"), true
+}
+
+func (doc *pdfDocument) readObjectStream(stream *pdfStream) error {
+	data, err := doc.decode(stream)
+	if err != nil {
+		return err
+	}
+	n, _ := doc.resolve(stream.dict["N"]).(float64)
+	first, _ := doc.resolve(stream.dict["First"]).(float64)
+	l := &pdfLexer{data: data}
+	type entry struct{ num, offset int }
+	var entries []entry
+	for i := 0; i < int(n); i++ {
+		num, err1 := l.token()
+		offset, err2 := l.token()
+		if err1 != nil || err2 != nil {
+			break
+		}
+		a, _ := num.(float64)
+		b, _ := offset.(float64)
+		entries = append(entries, entry{int(a), int(b)})
+	}
+	for _, e := range entries {
+		if _, ok := doc.objects[e.num]; ok {
+			continue
+		}
+		l.pos = int(first) + e.offset
+		if l.pos < 0 || l.pos >= len(data) {
+			continue
+		}
+		if value, err := l.object(); err == nil {
+			doc.objects[e.num] = value
+		}
+	}
+	return nil
+}
+
+func (doc *pdfDocument) resolve(v interface{}) interface{} {
+	for i := 0; i < 8; i++ {
+		ref, ok := v.(pdfRef)
+		if !ok {
+			return v
+		}
+		v = doc.objects[ref.num]
+	}
+	return nil
+}
+
+func (doc *pdfDocument) dict(v interface{}) pdfDict {
+	switch v := doc.resolve(v).(type) {
+	case pdfDict:
+		return v
+	case *pdfStream:
+		return v.dict
+	}
+	return nil
+}
+
+// decode returns the decoded content of a stream.
+func (doc *pdfDocument) decode(stream *pdfStream) ([]byte, error) {
+	var filters []interface{}
+	switch f := doc.resolve(stream.dict["Filter"]).(type) {
+	case pdfName:
+		filters = []interface{}{f}
+	case []interface{}:
+		filters = f
+	}
+	data := stream.raw
+	for _, f := range filters {
+		switch doc.resolve(f) {
+		case pdfName("FlateDecode"), pdfName("Fl"):
+			zr, err := zlib.NewReader(bytes.NewReader(data))
+			if err != nil {
+				return nil, fmt.Errorf("%w: bad compressed stream: %v", common.ErrInvalidInput, err)
+			}
+			// Keep what decodes from a truncated stream.
+			decoded, err := io.ReadAll(io.LimitReader(zr, doc.budget+1))
+			if err != nil && len(decoded) == 0 {
+				return nil, fmt.Errorf("%w: bad compressed stream: %v", common.ErrInvalidInput, err)
+			}
+			data = decoded
+		default:
+			return nil, fmt.Errorf("%w: unsupported stream filter %v", common.ErrInvalidInput, f)
+		}
+	}
+	if doc.budget -= int64(len(data)); doc.budget < 0 {
+		return nil, fmt.Errorf("%w: decoded content exceeds the limit", common.ErrFileTooLarge)
+	}
+	return data, nil
+}
+
+// pdfPage is a page with the resources it inherits from its parents.
+type pdfPage struct {
+	dict      pdfDict
+	resources pdfDict
+}
+
+// pages lists the document's pages in order.
+func (doc *pdfDocument) pages() []pdfPage {
+	var pages []pdfPage
+	visited := make(map[int]bool)
+	var walk func(node interface{}, resources pdfDict, depth int)
+	walk = func(node interface{}, resources pdfDict, depth int) {
+		if ref, ok := node.(pdfRef); ok {
+			if visited[ref.num] {
+				return
+			}
+			visited[ref.num] = true
+		}
+		dict := doc.dict(node)
+		if dict == nil || depth > 64 {
+			return
+		}
+		if r := doc.dict(dict["Resources"]); r != nil {
+			resources = r
+		}
+		if kids, ok := doc.resolve(dict["Kids"]).([]interface{}); ok {
+			for _, kid := range kids {
+				walk(kid, resources, depth+1)
+			}
+			return
+		}
+		if dict["Type"] == pdfName("Page") {
+			pages = append(pages, pdfPage{dict, resources})
+		}
+	}
+	if catalog := doc.dict(doc.trailer["Root"]); catalog != nil {
+		walk(catalog["Pages"], nil, 0)
+	}
+	if len(pages) > 0 {
+		return pages
+	}
+
+	// No usable page tree: take the page objects in object order.
+	nums := make([]int, 0, len(doc.objects))
+	for num := range doc.objects {
+		nums = append(nums, num)
+	}
+	sort.Ints(nums)
+	for _, num := range nums {
+		if dict, ok := doc.objects[num].(pdfDict); ok && dict["Type"] == pdfName("Page") {
+			pages = append(pages, pdfPage{dict, doc.dict(dict["Resources"])})
+		}
+	}
+	return pages
+}
+
+// pageText extracts the text drawn on a page.
+func (doc *pdfDocument) pageText(page pdfPage) (string, error) {
+	var streams []interface{}
+	switch c := doc.resolve(page.dict["Contents"]).(type) {
+	case *pdfStream:
+		streams = []interface{}{c}
+	case []interface{}:
+		streams = c
+	}
+	var content []byte
+	for _, s := range streams {
+		stream, ok := doc.resolve(s).(*pdfStream)
+		if !ok {
+			continue
+		}
+		data, err := doc.decode(stream)
+		if err != nil {
+			return "", err
+		}
+		content = append(append(content, data...), '\n')
+	}
+
+	fonts := doc.dict(page.resources["Font"])
+	var text strings.Builder
+	newline := func() {
+		if s := text.String(); s != "" && !strings.HasSuffix(s, "\n") {
+			text.WriteByte('\n')
+		}
+	}
+	var font *pdfFont
+	var operands []interface{}
+	lastY, haveY := 0.0, false
+	l := &pdfLexer{data: content}
+	for {
+		tok, err := l.object()
+		if err != nil {
+			break
+		}
+		op, ok := tok.(pdfKeyword)
+		if !ok {
+			operands = append(operands, tok)
+			continue
+		}
+		switch op {
+		case "BI":
+			l.skipInlineImage()
+		case "Tf":
+			if len(operands) >= 2 {
+				if name, ok := operands[0].(pdfName); ok {
+					font = doc.font(fonts[string(name)])
+				}
+			}
+		case "Tj":
+			if len(operands) >= 1 {
+				text.WriteString(font.decode(operands[0]))
+			}
+		case "'", "\"":
+			newline()
+			if len(operands) >= 1 {
+				text.WriteString(font.decode(operands[len(operands)-1]))
+			}
+		case "TJ":
+			if len(operands) >= 1 {
+				items, _ := operands[0].([]interface{})
+				for _, item := range items {
+					// A large negative adjustment is a gap between words.
+					if n, ok := item.(float64); ok && n < -200 && !strings.HasSuffix(text.String(), " ") {
+						text.WriteByte(' ')
+					}
+					text.WriteString(font.decode(item))
+				}
+			}
+		case "Td", "TD":
+			if len(operands) >= 2 {
+				if dy, ok := operands[1].(float64); ok && dy != 0 {
+					newline()
+				}
+			}
+		case "T*":
+			newline()
+		case "Tm":
+			if len(operands) >= 6 {
+				if y, ok := operands[5].(float64); ok {
+					if haveY && y != lastY {
+						newline()
+					}
+					lastY, haveY = y, true
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+	lines := strings.Split(text.String(), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
+
+// pdfFont decodes the strings shown in a font.
+type pdfFont struct {
+	cmap *pdfCMap
+	// composite fonts use multi-byte codes that mean nothing without a
+	// ToUnicode map.
+	composite bool
+}
+
+func (doc *pdfDocument) font(v interface{}) *pdfFont {
+	dict := doc.dict(v)
+	if dict == nil {
+		return nil
+	}
+	font := &pdfFont{composite: dict["Subtype"] == pdfName("Type0")}
+	ref, isRef := dict["ToUnicode"].(pdfRef)
+	if cmap, ok := doc.cmaps[ref]; isRef && ok {
+		font.cmap = cmap
+		return font
+	}
+	if stream, ok := doc.resolve(dict["ToUnicode"]).(*pdfStream); ok {
+		if data, err := doc.decode(stream); err == nil {
+			font.cmap = parseCMap(data)
+		}
+	}
+	if isRef {
+		doc.cmaps[ref] = font.cmap
+	}
+	return font
+}
+
+func (f *pdfFont) decode(v interface{}) string {
+	s, ok := v.([]byte)
+	if !ok {
+		return ""
+	}
+	if f != nil && f.cmap != nil {
+		return f.cmap.decode(s)
+	}
+	if f != nil && f.composite {
+		return ""
+	}
+	// Simple fonts mostly use a Latin-1 compatible encoding.
+	runes := make([]rune, len(s))
+	for i, b := range s {
+		runes[i] = rune(b)
+	}
+	return string(runes)
+}
+
+// pdfCMap is a ToUnicode map from character codes to text.
+type pdfCMap struct {
+	width int
+	codes map[uint32]string
+}
+
+// maxCMapRange bounds the codes one bfrange line may map.
+const maxCMapRange = 65536
+
+func parseCMap(data []byte) *pdfCMap {
+	cmap := &pdfCMap{codes: make(map[uint32]string)}
+	code := func(b []byte) uint32 {
+		if cmap.width == 0 {
+			cmap.width = len(b)
+		}
+		var n uint32
+		for _, c := range b {
+			n = n<<8 | uint32(c)
+		}
+		return n
+	}
+	l := &pdfLexer{data: data}
+	var operands []interface{}
+	for {
+		tok, err := l.object()
+		if err != nil {
+			break
+		}
+		op, ok := tok.(pdfKeyword)
+		if !ok {
+			operands = append(operands, tok)
+			continue
+		}
+		switch op {
+		case "endbfchar":
+			for i := 0; i+1 < len(operands); i += 2 {
+				src, ok1 := operands[i].([]byte)
+				dst, ok2 := operands[i+1].([]byte)
+				if ok1 && ok2 {
+					cmap.codes[code(src)] = utf16BE(dst)
+				}
+			}
+		case "endbfrange":
+			for i := 0; i+2 < len(operands); i += 3 {
+				lo, ok1 := operands[i].([]byte)
+				hi, ok2 := operands[i+1].([]byte)
+				if !ok1 || !ok2 {
+					continue
+				}
+				start, end := code(lo), code(hi)
+				if end < start || end-start >= maxCMapRange {
+					continue
+				}
+				switch dst := operands[i+2].(type) {
+				case []byte:
+					if len(dst) == 0 {
+						continue
+					}
+					for c := start; c <= end; c++ {
+						next := append([]byte{}, dst...)
+						next[len(next)-1] += byte(c - start)
+						cmap.codes[c] = utf16BE(next)
+					}
+				case []interface{}:
+					for j, d := range dst {
+						if b, ok := d.([]byte); ok && start+uint32(j) <= end {
+							cmap.codes[start+uint32(j)] = utf16BE(b)
+						}
+					}
+				}
+			}
+		}
+		operands = operands[:0]
+	}
+	if cmap.width == 0 {
+		cmap.width = 1
+	}
+	return cmap
+}
+
+func (m *pdfCMap) decode(s []byte) string {
+	var b strings.Builder
+	for i := 0; i+m.width <= len(s); i += m.width {
+		var c uint32
+		for _, x := range s[i : i+m.width] {
+			c = c<<8 | uint32(x)
+		}
+		if text, ok := m.codes[c]; ok {
+			b.WriteString(text)
+		} else if m.width == 1 {
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
+
+func utf16BE(b []byte) string {
+	units := make([]uint16, len(b)/2)
+	for i := range units {
+		units[i] = uint16(b[2*i])<<8 | uint16(b[2*i+1])
+	}
+	return string(utf16.Decode(units))
+}
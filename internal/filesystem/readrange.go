@@ -0,0 +1,109 @@
+package filesystem
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// readRange serves read_file's offset and length, either of which may be
+// nil: at most maxSize bytes of the file, wherever it starts.
+func (s *Server) readRange(absPath string, size int64, offsetArg, lengthArg *int, maxSize int64) ([]byte, error) {
+	offset, length := 0, 0
+	if offsetArg != nil {
+		offset = *offsetArg
+	}
+	if lengthArg != nil {
+		length = *lengthArg
+	}
+	if length < 0 {
+		return nil, fmt.Errorf("%w: length must not be negative", common.ErrInvalidInput)
+	}
+
+	start := int64(offset)
+	if start < 0 {
+		start = max(size+start, 0)
+	}
+	if start > size {
+		return nil, fmt.Errorf("%w: offset %d is past the end of the file (%d bytes)", common.ErrInvalidInput, offset, size)
+	}
+	n := size - start
+	if length > 0 {
+		n = min(n, int64(length))
+	}
+	if n > maxSize {
+		return nil, fmt.Errorf("%w: range of %d bytes exceeds limit %d", common.ErrFileTooLarge, n, maxSize)
+	}
+
+	file, err := os.Open(absPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	data := make([]byte, n)
+	read, err := file.ReadAt(data, start)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	return data[:read], nil
+}
+
+// readHead serves read_file's head: the first lines of the file, stopping
+// at maxSize bytes.
+func (s *Server) readHead(absPath string, lines int, maxSize int64) ([]byte, error) {
+	file, err := os.Open(absPath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	r := bufio.NewReader(io.LimitReader(file, maxSize))
+	var data []byte
+	for i := 0; i < lines; i++ {
+		line, err := r.ReadBytes('\n')
+		data = append(data, line...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return data, nil
+}
+
+// partialResult returns part of a file as text, or as a binary resource if
+// it is not UTF-8. A character split by the ends of the range does not make
+// it binary; its bytes are dropped.
+func partialResult(absPath string, data []byte) *mcp.ToolResult {
+	text := trimPartialRunes(data)
+	if !utf8.Valid(text) {
+		return &mcp.ToolResult{Content: []mcp.ContentBlock{
+			mcp.ResourceBlock(resourceContents(fileURI(absPath), absPath, data)),
+		}}
+	}
+	return mcp.TextResult(string(text))
+}
+
+func trimPartialRunes(data []byte) []byte {
+	for i := 0; i < utf8.UTFMax-1 && len(data) > 0 && !utf8.RuneStart(data[0]); i++ {
+		data = data[1:]
+	}
+	for i := 1; i < utf8.UTFMax && i <= len(data); i++ {
+		b := data[len(data)-i]
+		if !utf8.RuneStart(b) {
+			continue
+		}
+		// b starts the last character; drop it if it is cut short.
+		if !utf8.FullRune(data[len(data)-i:]) {
+			data = data[:len(data)-i]
+		}
+		break
+	}
+	return data
+}
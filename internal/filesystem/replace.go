@@ -0,0 +1,168 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"unicode/utf8"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) replaceInFilesTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "replace_in_files",
+		Description: "Find and replace across every file under a directory whose name matches a glob, like sed -i. Nothing is written unless every changed file can be; use dry_run to preview the affected lines first. Binary, oversized and ignored files are skipped",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"directory":       mcp.StringProperty("Directory to search in"),
+				"pattern":         mcp.StringProperty("Regex to find, or exact text with literal"),
+				"replacement":     mcp.StringProperty("Replacement text; with a regex, $1 or ${name} insert groups"),
+				"file_pattern":    mcp.StringProperty("Only change files whose name matches this glob, e.g. *.go (default: all files)"),
+				"literal":         mcp.BoolProperty("Treat pattern and replacement as plain text"),
+				"case_sensitive":  mcp.BoolProperty("Case sensitive matching (default: true)"),
+				"dry_run":         mcp.BoolProperty("Report the changes as diffs without writing any file"),
+				"max_files":       mcp.IntProperty("Fail without writing if more files than this would change (default: 100, max: 10000)"),
+				"exclude":         mcp.ArrayProperty("string", "Extra patterns to skip, in .gitignore syntax, e.g. *.min.js or docs/"),
+				"include_ignored": mcp.BoolProperty("Also change ignored files and directories"),
+			},
+			[]string{"directory", "pattern", "replacement"},
+		),
+		Annotations: mcp.Destructive(),
+		Handler:     mcp.TypedHandler(s.handleReplaceInFiles),
+	}
+}
+
+type replaceInFilesArgs struct {
+	Directory      string   `json:"directory" validate:"required"`
+	Pattern        string   `json:"pattern" validate:"required"`
+	Replacement    string   `json:"replacement"`
+	FilePattern    string   `json:"file_pattern"`
+	Literal        bool     `json:"literal"`
+	CaseSensitive  bool     `json:"case_sensitive" default:"true"`
+	DryRun         bool     `json:"dry_run"`
+	MaxFiles       int      `json:"max_files" default:"100" validate:"min=1,max=10000"`
+	Exclude        []string `json:"exclude"`
+	IncludeIgnored bool     `json:"include_ignored"`
+}
+
+func (s *Server) handleReplaceInFiles(ctx context.Context, args replaceInFilesArgs) (*mcp.ToolResult, error) {
+	absDir, err := s.validator.ResolvePath(args.Directory)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := filepath.Match(args.FilePattern, ""); err != nil {
+		return nil, &mcp.ParamError{Param: "file_pattern", Message: fmt.Sprintf("invalid pattern: %v", err)}
+	}
+	pattern := args.Pattern
+	if args.Literal {
+		pattern = regexp.QuoteMeta(pattern)
+	}
+	if !args.CaseSensitive {
+		pattern = "(?i)" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, &mcp.ParamError{Param: "pattern", Message: fmt.Sprintf("invalid regex: %v", err)}
+	}
+	ignore, err := s.newIgnoreMatcher(absDir, args.Exclude, args.IncludeIgnored)
+	if err != nil {
+		return nil, err
+	}
+
+	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
+	var changed []*pendingEdit
+	skipped := 0
+	tooMany := false
+	err = filepath.WalkDir(absDir, func(path string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil {
+			return nil
+		}
+		if s.validator.ValidatePath(path) != nil || ignore.ignored(path, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if args.FilePattern != "" && !matchName(args.FilePattern, d.Name()) {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || info.Size() > maxSize {
+			skipped++
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil || !utf8.Valid(data) {
+			skipped++
+			return nil
+		}
+
+		before := string(data)
+		count := len(re.FindAllStringIndex(before, -1))
+		if count == 0 {
+			return nil
+		}
+		var after string
+		if args.Literal {
+			after = re.ReplaceAllLiteralString(before, args.Replacement)
+		} else {
+			after = re.ReplaceAllString(before, args.Replacement)
+		}
+		if after == before {
+			return nil
+		}
+		if len(changed) >= args.MaxFiles {
+			tooMany = true
+			return filepath.SkipAll
+		}
+		changed = append(changed, &pendingEdit{path: path, before: before, after: after, perm: info.Mode().Perm(), replacements: count})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if tooMany && !args.DryRun {
+		return nil, fmt.Errorf("%w: more than %d files would change and none was written; narrow file_pattern or raise max_files", common.ErrInvalidInput, args.MaxFiles)
+	}
+	if !args.DryRun {
+		if err := commitEdits(changed); err != nil {
+			return nil, err
+		}
+	}
+
+	files := make([]map[string]interface{}, 0, len(changed))
+	total := 0
+	for _, p := range changed {
+		file := map[string]interface{}{
+			"path":         p.path,
+			"replacements": p.replacements,
+		}
+		if args.DryRun {
+			hunks := makeHunks(diffLines(splitLines(p.before), splitLines(p.after)), 0)
+			file["diff"] = formatUnified(p.path, p.path, hunks)
+		}
+		files = append(files, file)
+		total += p.replacements
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"directory":          absDir,
+		"files":              files,
+		"files_changed":      len(changed),
+		"total_replacements": total,
+		"skipped":            skipped,
+		"dry_run":            args.DryRun,
+		"truncated":          tooMany,
+	})
+}
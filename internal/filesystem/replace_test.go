@@ -0,0 +1,91 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplaceInFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	write := func(name, content string) string {
+		path := filepath.Join(tempDir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+	read := func(path string) string {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+	a := write("a.go", "oldName()\nkeep\noldName(1)\n")
+	b := write("sub/b.go", "x := oldName\n")
+	txt := write("notes.txt", "oldName\n")
+	bin := write("sub/blob.go", "oldName\xff\xfe")
+
+	replace := func(params map[string]interface{}) map[string]interface{} {
+		params["directory"] = tempDir
+		result, err := server.replaceInFilesTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		out := replace(map[string]interface{}{"pattern": "oldName", "replacement": "newName", "file_pattern": "*.go", "dry_run": true})
+		assert.EqualValues(t, 2, out["files_changed"])
+		assert.EqualValues(t, 3, out["total_replacements"])
+		assert.EqualValues(t, 1, out["skipped"])
+		files := out["files"].([]interface{})
+		first := files[0].(map[string]interface{})
+		assert.Equal(t, a, first["path"])
+		assert.Contains(t, first["diff"], "-oldName()\n+newName()\n")
+		assert.NotContains(t, first["diff"], "keep")
+		assert.Equal(t, "oldName()\nkeep\noldName(1)\n", read(a))
+	})
+
+	t.Run("regex with groups", func(t *testing.T) {
+		out := replace(map[string]interface{}{"pattern": `oldName\((\d)\)`, "replacement": "newName($1, 0)", "file_pattern": "*.go"})
+		assert.EqualValues(t, 1, out["files_changed"])
+		assert.Nil(t, out["files"].([]interface{})[0].(map[string]interface{})["diff"])
+		assert.Equal(t, "oldName()\nkeep\nnewName(1, 0)\n", read(a))
+	})
+
+	t.Run("literal and case insensitive", func(t *testing.T) {
+		out := replace(map[string]interface{}{"pattern": "OLDNAME()", "replacement": "$new()", "literal": true, "case_sensitive": false})
+		assert.EqualValues(t, 1, out["files_changed"])
+		assert.Equal(t, "$new()\nkeep\nnewName(1, 0)\n", read(a))
+		assert.Equal(t, "x := oldName\n", read(b))
+		assert.Equal(t, "oldName\xff\xfe", read(bin))
+	})
+
+	t.Run("max files", func(t *testing.T) {
+		_, err := server.replaceInFilesTool().Handler(context.Background(), map[string]interface{}{
+			"directory": tempDir, "pattern": "oldName", "replacement": "x", "max_files": 1})
+		assert.Error(t, err)
+		assert.Equal(t, "oldName\n", read(txt))
+
+		out := replace(map[string]interface{}{"pattern": "oldName", "replacement": "x", "max_files": 1, "dry_run": true})
+		assert.Equal(t, true, out["truncated"])
+		assert.EqualValues(t, 1, out["files_changed"])
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, params := range []map[string]interface{}{
+			{"pattern": "(", "replacement": "x"},
+			{"pattern": "a", "replacement": "x", "file_pattern": "["},
+		} {
+			params["directory"] = tempDir
+			_, err := server.replaceInFilesTool().Handler(context.Background(), params)
+			assert.Error(t, err)
+		}
+	})
+}
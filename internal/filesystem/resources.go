@@ -0,0 +1,145 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxListedResources bounds resources/list, which walks the allowed paths;
+// other files are still readable through the file:///{path} template.
+const maxListedResources = 1000
+
+// Resources exposes the files under the allowed paths as file:// resources.
+func (s *Server) Resources() *mcp.ResourceSource {
+	return &mcp.ResourceSource{
+		Name:   "filesystem",
+		Prefix: "file://",
+		Templates: []mcp.ResourceTemplate{{
+			URITemplate: "file:///{path}",
+			Name:        "File",
+			Description: "A file under the filesystem server's allowed paths",
+		}},
+		List: s.listResources,
+		Read: s.readResource,
+	}
+}
+
+func fileURI(path string) string {
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
+
+// listResources walks the allowed paths, skipping hidden files and
+// directories, until maxListedResources files are found.
+func (s *Server) listResources(ctx context.Context) ([]mcp.Resource, error) {
+	resources := []mcp.Resource{}
+	for _, root := range s.config.AllowedPaths {
+		err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return ctxErr
+			}
+			if err != nil {
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if s.validator.ValidatePath(path) != nil {
+				if d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			resources = append(resources, mcp.Resource{
+				URI:      fileURI(path),
+				Name:     d.Name(),
+				MimeType: mime.TypeByExtension(filepath.Ext(path)),
+				Size:     info.Size(),
+			})
+			if len(resources) >= maxListedResources {
+				return fs.SkipAll
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+		if len(resources) >= maxListedResources {
+			break
+		}
+	}
+	return resources, nil
+}
+
+func (s *Server) readResource(ctx context.Context, uri string) (*mcp.ResourceContents, error) {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" || (u.Host != "" && u.Host != "localhost") || u.Path == "" {
+		return nil, fmt.Errorf("%w: %s is not a local file URI", mcp.ErrResourceNotFound, uri)
+	}
+
+	absPath, err := s.validator.ResolvePath(filepath.FromSlash(u.Path))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", mcp.ErrResourceNotFound, err)
+	}
+	info, err := os.Stat(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %w: %s", mcp.ErrResourceNotFound, common.ErrNotFound, u.Path)
+		}
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%w: %w: %s", mcp.ErrResourceNotFound, common.ErrNotAFile, u.Path)
+	}
+	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("%w: file size %d exceeds limit %d", common.ErrFileTooLarge, info.Size(), maxSize)
+	}
+
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, err
+	}
+	return resourceContents(uri, absPath, content), nil
+}
+
+// resourceContents returns a file's content as text, or as base64 if it is
+// not valid UTF-8.
+func resourceContents(uri, path string, content []byte) *mcp.ResourceContents {
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		mimeType = http.DetectContentType(content)
+	}
+	contents := &mcp.ResourceContents{URI: uri, MimeType: mimeType}
+	if utf8.Valid(content) {
+		contents.Text = string(content)
+	} else {
+		contents.Blob = base64.StdEncoding.EncodeToString(content)
+	}
+	return contents
+}
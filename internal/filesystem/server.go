@@ -1,6 +1,8 @@
 package filesystem
 
 import (
+	"context"
+
 	"github.com/local-mcps/dev-mcps/config"
 	"github.com/local-mcps/dev-mcps/internal/common"
 	"github.com/local-mcps/dev-mcps/pkg/mcp"
@@ -10,28 +12,97 @@ type Server struct {
 	config    *config.FilesystemConfig
 	validator *common.PathValidator
 	logger    *common.Logger
+	watches   *watchManager
+	locks     *lockManager
+	temps     *tempManager
 }
 
 func NewServer(cfg *config.FilesystemConfig) *Server {
-	return &Server{
+	s := &Server{
 		config:    cfg,
 		validator: common.NewPathValidator(cfg.AllowedPaths, cfg.DeniedPaths, cfg.FollowSymlinks),
-		logger:    common.NewLogger(common.LogLevelInfo, common.LogFormatJSON, nil, "filesystem"),
+		logger:    common.NewServerLogger("filesystem"),
+		watches:   newWatchManager(),
+		locks:     newLockManager(),
+		temps:     newTempManager(),
 	}
+	common.RegisterConfigSection("filesystem", s.configSection)
+	return s
+}
+
+func (s *Server) Reload() {
+	s.validator = common.NewPathValidator(s.config.AllowedPaths, s.config.DeniedPaths, s.config.FollowSymlinks)
+}
+
+// Shutdown stops the watches still running and removes the temp files.
+func (s *Server) Shutdown(ctx context.Context) {
+	s.watches.stopAll()
+	s.temps.removeAll()
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		s.readFileTool(),
+		s.readFileLinesTool(),
+		s.tailFileTool(),
+		common.Audited("filesystem", s.writeLocked(s.writeFileTool(), "path")),
+		common.Audited("filesystem", s.writeLocked(s.editFileTool(), "path")),
+		common.Audited("filesystem", s.writeLocked(s.multiEditTool(), "files[].path")),
+		common.Audited("filesystem", s.writeLocked(s.replaceInFilesTool(), "directory")),
+		common.Audited("filesystem", s.writeLocked(s.appendFileTool(), "path")),
+		common.Audited("filesystem", s.writeLocked(s.deleteFileTool(), "path")),
+		common.Audited("filesystem", s.writeLocked(s.moveFileTool(), "source", "destination")),
+		common.Audited("filesystem", s.writeLocked(s.copyFileTool(), "destination")),
+		common.Audited("filesystem", s.writeLocked(s.copyDirectoryTool(), "destination")),
+		common.Audited("filesystem", s.createTempFileTool()),
+		common.Audited("filesystem", s.createTempDirTool()),
+		common.Audited("filesystem", s.writeLocked(s.renderTemplateTool(), "destination")),
+		common.Audited("filesystem", s.writeLocked(s.batchTool(), "operations[].path", "operations[].destination")),
+		common.Audited("filesystem", s.lockFileTool()),
+		common.Audited("filesystem", s.unlockFileTool()),
+		s.listDirectoryTool(),
+		common.Audited("filesystem", s.writeLocked(s.createDirectoryTool(), "path")),
+		common.Audited("filesystem", s.writeLocked(s.deleteDirectoryTool(), "path")),
+		common.Audited("filesystem", s.writeLocked(s.createArchiveTool(), "destination")),
+		common.Audited("filesystem", s.writeLocked(s.extractArchiveTool(), "destination")),
+		s.detectFileTypeTool(),
+		s.imageInfoTool(),
+		s.extractDocumentTextTool(),
+		s.readStructuredTool(),
+		s.queryJSONTool(),
+		common.Audited("filesystem", s.writeLocked(s.convertEncodingTool(), "path")),
+		common.Audited("filesystem", s.writeLocked(s.lineEndingsTool(), "path")),
+		s.fileInfoTool(),
+		s.getXattrsTool(),
+		common.Audited("filesystem", s.writeLocked(s.setXattrTool(), "path")),
+		common.Audited("filesystem", s.writeLocked(s.touchFileTool(), "path")),
+		common.Audited("filesystem", s.writeLocked(s.createSymlinkTool(), "path")),
+		s.readSymlinkTool(),
+		s.fileChecksumTool(),
+		s.compareFilesTool(),
+		s.diskUsageTool(),
+		s.treeTool(),
+		s.filesQueryTool(),
+		s.fileStatsTool(),
+		s.searchFilesTool(),
+		s.fuzzyFindTool(),
+		s.grepTool(),
+		s.watchPathTool(),
+		s.getWatchEventsTool(),
+		s.stopWatchTool(),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
+		common.AuditLogTool(),
+	}
+	return common.Instrument("filesystem", tools)
 }
 
 func (s *Server) RegisterTools(server *mcp.Server) {
-	server.RegisterTool(s.readFileTool())
-	server.RegisterTool(s.readFileLinesTool())
-	server.RegisterTool(s.writeFileTool())
-	server.RegisterTool(s.appendFileTool())
-	server.RegisterTool(s.deleteFileTool())
-	server.RegisterTool(s.moveFileTool())
-	server.RegisterTool(s.copyFileTool())
-	server.RegisterTool(s.listDirectoryTool())
-	server.RegisterTool(s.createDirectoryTool())
-	server.RegisterTool(s.deleteDirectoryTool())
-	server.RegisterTool(s.fileInfoTool())
-	server.RegisterTool(s.searchFilesTool())
-	server.RegisterTool(s.grepTool())
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
 }
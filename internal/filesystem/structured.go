@@ -0,0 +1,378 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// maxStructuredRows bounds the rows one read_structured call returns;
+// has_more and next_start_row page through the rest.
+const maxStructuredRows = 1000
+
+var structuredFormats = map[string]string{
+	".csv": "csv", ".tsv": "tsv", ".json": "json", ".jsonl": "jsonl", ".ndjson": "jsonl",
+	".yaml": "yaml", ".yml": "yaml",
+}
+
+func (s *Server) readStructuredTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "read_structured",
+		Description: "Parse a CSV, TSV, JSON, JSON Lines or YAML file and return its data. CSV rows come back as arrays under their column names, with the header row and delimiter detected. Rows of CSV and JSON Lines files, and elements of a top-level JSON or YAML array, can be read by range and reduced to some columns (object keys). Returns at most 1000 rows; continue from next_start_row",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":      mcp.StringProperty("Absolute path to the file"),
+				"format":    mcp.StringProperty("auto, csv, tsv, json, jsonl or yaml (default: auto, from the file extension)"),
+				"header":    mcp.StringProperty("Whether the first CSV row names the columns: auto, yes or no (default: auto, detected)"),
+				"columns":   mcp.ArrayProperty("string", "Columns (or object keys) to return, in order (default: all)"),
+				"start_row": mcp.IntProperty("First row to return (1-indexed, not counting the header; negative counts from the end; default: 1)"),
+				"end_row":   mcp.IntProperty("Last row to return, inclusive (negative counts from the end; default: -1)"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleReadStructured),
+	}
+}
+
+type readStructuredArgs struct {
+	Path     string   `json:"path" validate:"required"`
+	Format   string   `json:"format" default:"auto" validate:"oneof=auto csv tsv json jsonl yaml"`
+	Header   string   `json:"header" default:"auto" validate:"oneof=auto yes no"`
+	Columns  []string `json:"columns"`
+	StartRow int      `json:"start_row" default:"1"`
+	EndRow   int      `json:"end_row" default:"-1"`
+}
+
+func (s *Server) handleReadStructured(ctx context.Context, args readStructuredArgs) (*mcp.ToolResult, error) {
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := statExisting(absPath, args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", common.ErrNotAFile, args.Path)
+	}
+	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("%w: file size %d exceeds limit %d", common.ErrFileTooLarge, info.Size(), maxSize)
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, bomUTF8)
+
+	format := args.Format
+	if format == "auto" {
+		var ok bool
+		if format, ok = structuredFormats[strings.ToLower(filepath.Ext(absPath))]; !ok {
+			return nil, &mcp.ParamError{Param: "format", Message: fmt.Sprintf("cannot tell the format of %s from its extension; give format", filepath.Base(absPath))}
+		}
+	}
+
+	result := map[string]interface{}{
+		"path":   absPath,
+		"format": format,
+	}
+	var rows []interface{}
+	switch format {
+	case "csv", "tsv":
+		columns, records, err := parseCSV(data, format, args.Header)
+		if err != nil {
+			return nil, err
+		}
+		if args.Columns != nil {
+			if columns, records, err = selectCSVColumns(columns, records, args.Columns); err != nil {
+				return nil, err
+			}
+		}
+		result["columns"] = columns
+		rows = make([]interface{}, len(records))
+		for i, r := range records {
+			rows[i] = r
+		}
+	case "jsonl":
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		for line := 1; ; line++ {
+			var v interface{}
+			if err := decoder.Decode(&v); err == io.EOF {
+				break
+			} else if err != nil {
+				return nil, fmt.Errorf("%w: invalid JSON in record %d: %v", common.ErrInvalidInput, line, err)
+			}
+			rows = append(rows, v)
+		}
+	default:
+		v, err := parseStructured(data, format)
+		if err != nil {
+			return nil, err
+		}
+		array, ok := v.([]interface{})
+		if !ok {
+			if args.Columns != nil {
+				return nil, &mcp.ParamError{Param: "columns", Message: "columns applies only to rows; the file does not hold a top-level array"}
+			}
+			result["data"] = v
+			return mcp.JSONResult(result)
+		}
+		rows = array
+	}
+	if format == "json" || format == "jsonl" || format == "yaml" {
+		if args.Columns != nil {
+			for i, row := range rows {
+				rows[i] = selectKeys(row, args.Columns)
+			}
+		}
+	}
+
+	// Row ranges work as read_file_lines' line ranges do.
+	total := len(rows)
+	start, end := args.StartRow, args.EndRow
+	if start < 0 {
+		start = total + start + 1
+	}
+	if end < 0 {
+		end = total + end + 1
+	}
+	start = max(start, 1)
+	if end < start && total != 0 {
+		return nil, fmt.Errorf("%w: end_row must be >= start_row", common.ErrInvalidInput)
+	}
+	end = min(end, total, start+maxStructuredRows-1)
+	selected := []interface{}{}
+	if start <= end {
+		selected = rows[start-1 : end]
+	}
+
+	result["rows"] = selected
+	result["start_row"] = start
+	result["end_row"] = start + len(selected) - 1
+	result["total_rows"] = total
+	result["has_more"] = start+len(selected)-1 < total
+	if start+len(selected)-1 < total {
+		result["next_start_row"] = start + len(selected)
+	}
+	return mcp.JSONResult(result)
+}
+
+// parseStructured parses a JSON or YAML document. A YAML file with several
+// documents gives an array of them.
+func parseStructured(data []byte, format string) (interface{}, error) {
+	if format == "json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.UseNumber()
+		var v interface{}
+		if err := decoder.Decode(&v); err != nil {
+			return nil, fmt.Errorf("%w: invalid JSON: %v", common.ErrInvalidInput, err)
+		}
+		if _, err := decoder.Token(); err != io.EOF {
+			return nil, fmt.Errorf("%w: invalid JSON: data after the top-level value", common.ErrInvalidInput)
+		}
+		return v, nil
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	var docs []interface{}
+	for {
+		var v interface{}
+		if err := decoder.Decode(&v); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("%w: invalid YAML: %v", common.ErrInvalidInput, err)
+		}
+		docs = append(docs, jsonCompatible(v))
+	}
+	switch len(docs) {
+	case 0:
+		return nil, nil
+	case 1:
+		return docs[0], nil
+	}
+	return docs, nil
+}
+
+// jsonCompatible converts the maps YAML decodes with non-string keys to
+// ones JSON can encode.
+func jsonCompatible(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = jsonCompatible(e)
+		}
+		return v
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for k, e := range v {
+			m[fmt.Sprint(k)] = jsonCompatible(e)
+		}
+		return m
+	case []interface{}:
+		for i, e := range v {
+			v[i] = jsonCompatible(e)
+		}
+		return v
+	}
+	return v
+}
+
+// selectKeys reduces an object to the given keys; other values pass
+// through.
+func selectKeys(v interface{}, keys []string) interface{} {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return v
+	}
+	selected := make(map[string]interface{}, len(keys))
+	for _, k := range keys {
+		if e, ok := m[k]; ok {
+			selected[k] = e
+		}
+	}
+	return selected
+}
+
+// parseCSV reads delimited data. Without a header row, columns are named
+// column_1, column_2 and so on.
+func parseCSV(data []byte, format, header string) ([]string, [][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.Comma = '\t'
+	if format == "csv" {
+		reader.Comma = sniffDelimiter(data)
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: invalid %s: %v", common.ErrInvalidInput, strings.ToUpper(format), err)
+	}
+
+	width := 0
+	for _, r := range records {
+		width = max(width, len(r))
+	}
+	hasHeader := header == "yes" || (header == "auto" && detectHeader(records))
+	var columns []string
+	if hasHeader && len(records) > 0 {
+		columns, records = records[0], records[1:]
+	}
+	for i := len(columns); i < width; i++ {
+		columns = append(columns, "column_"+strconv.Itoa(i+1))
+	}
+	return columns, records, nil
+}
+
+// sniffDelimiter picks the delimiter that splits the first records into
+// the same number of fields, more than one.
+func sniffDelimiter(data []byte) rune {
+	sample := data[:min(len(data), 16*1024)]
+	if len(sample) < len(data) {
+		// The last line may be cut off.
+		sample = sample[:bytes.LastIndexByte(sample, '\n')+1]
+	}
+	best, bestFields := ',', 1
+	for _, d := range []rune{',', ';', '\t', '|'} {
+		reader := csv.NewReader(bytes.NewReader(sample))
+		reader.Comma = d
+		reader.LazyQuotes = true
+		var fields int
+		for i := 0; i < 20; i++ {
+			record, err := reader.Read()
+			if err != nil {
+				// A different field count is an error too.
+				if err != io.EOF {
+					fields = 0
+				}
+				break
+			}
+			fields = len(record)
+		}
+		if fields > bestFields {
+			best, bestFields = d, fields
+		}
+	}
+	return best
+}
+
+// detectHeader guesses whether the first record names the columns: its
+// fields are distinct and not numbers, and some column below it is all
+// numbers or never repeats its first field.
+func detectHeader(records [][]string) bool {
+	if len(records) < 2 {
+		return false
+	}
+	first := records[0]
+	seen := make(map[string]bool)
+	for _, f := range first {
+		if f == "" || seen[f] || isNumber(f) {
+			return false
+		}
+		seen[f] = true
+	}
+	sample := records[1:min(len(records), 50)]
+	for col, name := range first {
+		numeric, repeated := true, false
+		for _, r := range sample {
+			if col >= len(r) {
+				continue
+			}
+			numeric = numeric && isNumber(r[col])
+			repeated = repeated || r[col] == name
+		}
+		if numeric {
+			return true
+		}
+		if repeated {
+			return false
+		}
+	}
+	return true
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return err == nil
+}
+
+// selectCSVColumns keeps the named columns of records, in the order given.
+func selectCSVColumns(columns []string, records [][]string, names []string) ([]string, [][]string, error) {
+	indexes := make([]int, len(names))
+	for i, name := range names {
+		indexes[i] = -1
+		for j, c := range columns {
+			if c == name {
+				indexes[i] = j
+				break
+			}
+		}
+		if indexes[i] < 0 {
+			return nil, nil, &mcp.ParamError{Param: "columns", Message: fmt.Sprintf("no column %q; columns are %s", name, strings.Join(columns, ", "))}
+		}
+	}
+	selected := make([][]string, len(records))
+	for i, r := range records {
+		row := make([]string, len(indexes))
+		for j, index := range indexes {
+			if index < len(r) {
+				row[j] = r[index]
+			}
+		}
+		selected[i] = row
+	}
+	return names, selected, nil
+}
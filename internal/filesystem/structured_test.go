@@ -0,0 +1,111 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadStructured(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+
+	read := func(name, content string, params map[string]interface{}) (map[string]interface{}, error) {
+		path := filepath.Join(tempDir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		if params == nil {
+			params = map[string]interface{}{}
+		}
+		params["path"] = path
+		result, err := server.readStructuredTool().Handler(context.Background(), params)
+		if err != nil {
+			return nil, err
+		}
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out, nil
+	}
+
+	t.Run("csv with header", func(t *testing.T) {
+		out, err := read("people.csv", "name;age;city\nada;36;London\nalan;41;\"Wilmslow; UK\"\ngrace;85;Arlington\n", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"name", "age", "city"}, out["columns"])
+		assert.Len(t, out["rows"], 3)
+		assert.Equal(t, []interface{}{"alan", "41", "Wilmslow; UK"}, out["rows"].([]interface{})[1])
+		assert.EqualValues(t, 3, out["total_rows"])
+		assert.Equal(t, false, out["has_more"])
+	})
+
+	t.Run("csv without header", func(t *testing.T) {
+		out, err := read("points.csv", "1,2\n3,4\n", nil)
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"column_1", "column_2"}, out["columns"])
+		assert.Len(t, out["rows"], 2)
+
+		out, err = read("points.csv", "1,2\n3,4\n", map[string]interface{}{"header": "yes"})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"1", "2"}, out["columns"])
+	})
+
+	t.Run("columns and rows", func(t *testing.T) {
+		out, err := read("people.tsv", "name\tage\tcity\nada\t36\tLondon\nalan\t41\tWilmslow\ngrace\t85\tArlington\n",
+			map[string]interface{}{"columns": []interface{}{"city", "name"}, "start_row": 2, "end_row": 2})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"city", "name"}, out["columns"])
+		assert.Equal(t, []interface{}{[]interface{}{"Wilmslow", "alan"}}, out["rows"])
+		assert.Equal(t, true, out["has_more"])
+		assert.EqualValues(t, 3, out["next_start_row"])
+
+		out, err = read("people.tsv", "name\tage\nada\t36\nalan\t41\n", map[string]interface{}{"start_row": -1})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{[]interface{}{"alan", "41"}}, out["rows"])
+
+		_, err = read("people.tsv", "name\tage\nada\t36\n", map[string]interface{}{"columns": []interface{}{"email"}})
+		assert.Error(t, err)
+	})
+
+	t.Run("json", func(t *testing.T) {
+		out, err := read("items.json", `[{"id": 1, "name": "a", "tags": ["x"]}, {"id": 2, "name": "b"}]`,
+			map[string]interface{}{"columns": []interface{}{"id"}})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{map[string]interface{}{"id": float64(1)}, map[string]interface{}{"id": float64(2)}}, out["rows"])
+
+		out, err = read("config.json", `{"port": 8080, "debug": true}`, nil)
+		require.NoError(t, err)
+		assert.Equal(t, map[string]interface{}{"port": float64(8080), "debug": true}, out["data"])
+		assert.Nil(t, out["rows"])
+
+		_, err = read("bad.json", `{"port": }`, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		out, err := read("events.jsonl", "{\"n\": 1}\n{\"n\": 2}\n{\"n\": 3}\n", map[string]interface{}{"start_row": 3})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{map[string]interface{}{"n": float64(3)}}, out["rows"])
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		out, err := read("config.yaml", "server:\n  port: 8080\n  1: one\nnames: [a, b]\n", nil)
+		require.NoError(t, err)
+		data := out["data"].(map[string]interface{})
+		assert.Equal(t, map[string]interface{}{"port": float64(8080), "1": "one"}, data["server"])
+
+		out, err = read("docs.yml", "a: 1\n---\nb: 2\n", nil)
+		require.NoError(t, err)
+		assert.Len(t, out["rows"], 2)
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		_, err := read("data.txt", "a,b\n", nil)
+		assert.Error(t, err)
+		out, err := read("data.txt", "a,b\n1,2\n", map[string]interface{}{"format": "csv"})
+		require.NoError(t, err)
+		assert.Equal(t, []interface{}{"a", "b"}, out["columns"])
+	})
+}
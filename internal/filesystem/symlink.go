@@ -0,0 +1,133 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) createSymlinkTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "create_symlink",
+		Description: "Create a symbolic link. Both the link and what it points to must be inside the allowed paths, and filesystem.follow_symlinks must be enabled",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"target": mcp.StringProperty("What the link points to; a relative target is relative to the link's directory and is stored as given"),
+				"path":   mcp.StringProperty("Absolute path of the link to create"),
+			},
+			[]string{"target", "path"},
+		),
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleCreateSymlink),
+	}
+}
+
+type createSymlinkArgs struct {
+	Target string `json:"target" validate:"required"`
+	Path   string `json:"path" validate:"required"`
+}
+
+func (s *Server) handleCreateSymlink(ctx context.Context, args createSymlinkArgs) (*mcp.ToolResult, error) {
+	if !s.config.FollowSymlinks {
+		return nil, fmt.Errorf("%w: symlinks are disabled by filesystem.follow_symlinks", common.ErrPathNotAllowed)
+	}
+	linkPath, err := filepath.Abs(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.ValidatePath(linkPath); err != nil {
+		return nil, err
+	}
+	if _, err := os.Lstat(linkPath); err == nil {
+		return nil, fmt.Errorf("%w: %s", common.ErrAlreadyExists, args.Path)
+	}
+
+	target := args.Target
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(linkPath), target)
+	}
+	if err := s.validator.ValidatePath(target); err != nil {
+		return nil, err
+	}
+
+	if err := os.Symlink(args.Target, linkPath); err != nil {
+		return nil, err
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"path":          linkPath,
+		"target":        args.Target,
+		"resolved_path": filepath.Clean(target),
+	})
+}
+
+func (s *Server) readSymlinkTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "read_symlink",
+		Description: "Read where a symbolic link points, without following it, and where it resolves to",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path": mcp.StringProperty("Absolute path of the link"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleReadSymlink),
+	}
+}
+
+type readSymlinkArgs struct {
+	Path string `json:"path" validate:"required"`
+}
+
+func (s *Server) handleReadSymlink(ctx context.Context, args readSymlinkArgs) (*mcp.ToolResult, error) {
+	linkPath, err := filepath.Abs(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	linkPath = filepath.Clean(linkPath)
+	// Reading a link does not follow it, so only its directory is checked:
+	// this works when follow_symlinks is off too.
+	if err := s.validator.ValidatePath(filepath.Dir(linkPath)); err != nil {
+		return nil, err
+	}
+	info, err := os.Lstat(linkPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", common.ErrNotFound, args.Path)
+		}
+		return nil, err
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		return nil, fmt.Errorf("%w: %s is not a symbolic link", common.ErrInvalidInput, args.Path)
+	}
+
+	target, resolved := s.readLink(linkPath)
+	result := map[string]interface{}{
+		"path":   linkPath,
+		"target": target,
+	}
+	if resolved != "" {
+		result["resolved_path"] = resolved
+	}
+	_, err = os.Stat(linkPath)
+	result["dangling"] = os.IsNotExist(err)
+	return mcp.JSONResult(result)
+}
+
+// readLink returns the target stored in the link at path, and the path it
+// finally resolves to if that exists and is allowed.
+func (s *Server) readLink(path string) (string, string) {
+	target, err := os.Readlink(path)
+	if err != nil {
+		return "", ""
+	}
+	resolved, err := filepath.EvalSymlinks(path)
+	if err != nil || s.validator.ValidatePath(resolved) != nil {
+		return target, ""
+	}
+	return target, resolved
+}
@@ -0,0 +1,106 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+func TestSymlinks(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	target := filepath.Join(tempDir, "target.txt")
+	require.NoError(t, os.WriteFile(target, []byte("data"), 0644))
+	link := filepath.Join(tempDir, "link")
+
+	decode := func(text string) map[string]interface{} {
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(text), &out))
+		return out
+	}
+
+	t.Run("create and read", func(t *testing.T) {
+		result, err := server.createSymlinkTool().Handler(context.Background(), map[string]interface{}{
+			"target": "target.txt", "path": link,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, target, decode(result.Content[0].Text)["resolved_path"])
+
+		stored, err := os.Readlink(link)
+		require.NoError(t, err)
+		assert.Equal(t, "target.txt", stored)
+
+		result, err = server.readSymlinkTool().Handler(context.Background(), map[string]interface{}{"path": link})
+		require.NoError(t, err)
+		out := decode(result.Content[0].Text)
+		assert.Equal(t, "target.txt", out["target"])
+		assert.Equal(t, false, out["dangling"])
+
+		result, err = server.fileInfoTool().Handler(context.Background(), map[string]interface{}{"path": link})
+		require.NoError(t, err)
+		out = decode(result.Content[0].Text)
+		assert.Equal(t, true, out["is_symlink"])
+		assert.Equal(t, "target.txt", out["symlink_target"])
+		assert.Equal(t, target, out["resolved_path"])
+	})
+
+	t.Run("link exists", func(t *testing.T) {
+		_, err := server.createSymlinkTool().Handler(context.Background(), map[string]interface{}{
+			"target": "target.txt", "path": link,
+		})
+		assert.ErrorIs(t, err, common.ErrAlreadyExists)
+	})
+
+	t.Run("target outside allowed paths", func(t *testing.T) {
+		_, err := server.createSymlinkTool().Handler(context.Background(), map[string]interface{}{
+			"target": "../../etc/passwd", "path": filepath.Join(tempDir, "escape"),
+		})
+		assert.ErrorIs(t, err, common.ErrPathNotAllowed)
+		_, err = os.Lstat(filepath.Join(tempDir, "escape"))
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("resolution outside allowed paths is withheld", func(t *testing.T) {
+		outside := filepath.Join(tempDir, "outside")
+		require.NoError(t, os.Symlink("/", outside))
+		result, err := server.readSymlinkTool().Handler(context.Background(), map[string]interface{}{"path": outside})
+		require.NoError(t, err)
+		out := decode(result.Content[0].Text)
+		assert.Equal(t, "/", out["target"])
+		assert.NotContains(t, out, "resolved_path")
+	})
+
+	t.Run("denied link path", func(t *testing.T) {
+		server.validator.DeniedPaths = []string{"**/.env"}
+		defer func() { server.validator.DeniedPaths = nil }()
+		envLink := filepath.Join(tempDir, "sub", ".env")
+		require.NoError(t, os.MkdirAll(filepath.Dir(envLink), 0755))
+
+		_, err := server.createSymlinkTool().Handler(context.Background(), map[string]interface{}{
+			"target": target, "path": envLink,
+		})
+		assert.ErrorIs(t, err, common.ErrPathNotAllowed)
+		_, err = os.Lstat(envLink)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("symlinks disabled", func(t *testing.T) {
+		strict := NewServer(&config.FilesystemConfig{AllowedPaths: []string{tempDir}, MaxFileSizeMB: 10})
+		_, err := strict.createSymlinkTool().Handler(context.Background(), map[string]interface{}{
+			"target": "target.txt", "path": filepath.Join(tempDir, "other"),
+		})
+		assert.ErrorIs(t, err, common.ErrPathNotAllowed)
+
+		result, err := strict.readSymlinkTool().Handler(context.Background(), map[string]interface{}{"path": link})
+		require.NoError(t, err)
+		assert.Equal(t, "target.txt", decode(result.Content[0].Text)["target"])
+	})
+}
@@ -0,0 +1,175 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+const (
+	tailChunkSize    = 64 * 1024
+	tailPollInterval = 250 * time.Millisecond
+	// maxFollowLines bounds what follow mode collects into the result.
+	maxFollowLines = 10000
+)
+
+func (s *Server) tailFileTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "tail_file",
+		Description: "Return the last lines of a file, reading from its end so large logs are cheap. With follow, keep watching for new lines for up to follow_seconds, sending them as progress notifications as they arrive and returning them at the end",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":           mcp.StringProperty("Absolute path to the file"),
+				"lines":          mcp.IntProperty("Number of lines to return (default: 10, max: 10000)"),
+				"follow":         mcp.BoolProperty("Keep reading lines appended to the file"),
+				"follow_seconds": mcp.IntProperty("How long to follow the file (default: 10, max: 300)"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleTailFile),
+	}
+}
+
+type tailFileArgs struct {
+	Path          string `json:"path" validate:"required"`
+	Lines         int    `json:"lines" default:"10" validate:"min=1,max=10000"`
+	Follow        bool   `json:"follow"`
+	FollowSeconds int    `json:"follow_seconds" default:"10" validate:"min=1,max=300"`
+}
+
+func (s *Server) handleTailFile(ctx context.Context, args tailFileArgs) (*mcp.ToolResult, error) {
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(absPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("%w: %s", common.ErrNotFound, args.Path)
+		}
+		return nil, err
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", common.ErrNotAFile, args.Path)
+	}
+
+	lines, err := tailLines(file, info.Size(), args.Lines)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]interface{}{
+		"path":  absPath,
+		"lines": lines,
+	}
+	if args.Follow {
+		followed, err := followFile(ctx, file, info.Size(), time.Duration(args.FollowSeconds)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		result["new_lines"] = followed
+	}
+	return mcp.JSONResult(result)
+}
+
+// tailLines returns the last n lines of the first size bytes of f, reading
+// backwards a chunk at a time.
+func tailLines(f io.ReaderAt, size int64, n int) ([]string, error) {
+	if size == 0 {
+		return []string{}, nil
+	}
+	end := size
+	var data []byte
+	for end > 0 {
+		start := max(end-tailChunkSize, 0)
+		chunk := make([]byte, end-start)
+		if _, err := f.ReadAt(chunk, start); err != nil && err != io.EOF {
+			return nil, err
+		}
+		data = append(chunk, data...)
+		end = start
+		// n lines need n newlines before them, plus one ending the last.
+		if bytes.Count(data, []byte("\n")) > n {
+			break
+		}
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines, nil
+}
+
+// followFile polls f from offset for complete lines until d passes or ctx
+// ends, reporting each batch as progress. A file that shrinks is read again
+// from the start, as after log rotation by truncation.
+func followFile(ctx context.Context, f *os.File, offset int64, d time.Duration) ([]string, error) {
+	deadline := time.NewTimer(d)
+	defer deadline.Stop()
+	ticker := time.NewTicker(tailPollInterval)
+	defer ticker.Stop()
+
+	lines := []string{}
+	var partial []byte
+	for {
+		select {
+		case <-ctx.Done():
+			return lines, nil
+		case <-deadline.C:
+			return lines, nil
+		case <-ticker.C:
+		}
+
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+		if info.Size() < offset {
+			offset, partial = 0, nil
+		}
+		if info.Size() == offset {
+			continue
+		}
+		buf := make([]byte, min(info.Size()-offset, 16*tailChunkSize))
+		n, err := f.ReadAt(buf, offset)
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		offset += int64(n)
+		partial = append(partial, buf[:n]...)
+
+		cut := bytes.LastIndexByte(partial, '\n')
+		if cut < 0 {
+			continue
+		}
+		batch := strings.Split(string(partial[:cut]), "\n")
+		partial = append([]byte(nil), partial[cut+1:]...)
+		for i, line := range batch {
+			batch[i] = strings.TrimSuffix(line, "\r")
+		}
+		if room := maxFollowLines - len(lines); len(batch) > room {
+			batch = batch[:room]
+		}
+		lines = append(lines, batch...)
+		mcp.ReportProgress(ctx, float64(len(lines)), 0, strings.Join(batch, "\n"))
+		if len(lines) >= maxFollowLines {
+			return lines, nil
+		}
+	}
+}
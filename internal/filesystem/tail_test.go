@@ -0,0 +1,86 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTailFile(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+
+	tail := func(params map[string]interface{}) (lines, newLines []string) {
+		result, err := server.tailFileTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		var out struct {
+			Lines    []string `json:"lines"`
+			NewLines []string `json:"new_lines"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out.Lines, out.NewLines
+	}
+
+	t.Run("last lines of a large file", func(t *testing.T) {
+		path := filepath.Join(tempDir, "big.log")
+		var sb strings.Builder
+		for i := 1; i <= 20000; i++ {
+			fmt.Fprintf(&sb, "line %d\r\n", i)
+		}
+		require.NoError(t, os.WriteFile(path, []byte(sb.String()), 0644))
+
+		lines, _ := tail(map[string]interface{}{"path": path, "lines": 3})
+		assert.Equal(t, []string{"line 19998", "line 19999", "line 20000"}, lines)
+
+		lines, _ = tail(map[string]interface{}{"path": path, "lines": 10000})
+		assert.Len(t, lines, 10000)
+		assert.Equal(t, "line 10001", lines[0])
+	})
+
+	t.Run("short file without final newline", func(t *testing.T) {
+		path := filepath.Join(tempDir, "short.txt")
+		require.NoError(t, os.WriteFile(path, []byte("a\nb"), 0644))
+		lines, _ := tail(map[string]interface{}{"path": path})
+		assert.Equal(t, []string{"a", "b"}, lines)
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		path := filepath.Join(tempDir, "empty.txt")
+		require.NoError(t, os.WriteFile(path, nil, 0644))
+		lines, _ := tail(map[string]interface{}{"path": path})
+		assert.Empty(t, lines)
+	})
+
+	t.Run("follow", func(t *testing.T) {
+		path := filepath.Join(tempDir, "follow.log")
+		require.NoError(t, os.WriteFile(path, []byte("old\n"), 0644))
+		go func() {
+			time.Sleep(300 * time.Millisecond)
+			f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0644)
+			if err != nil {
+				return
+			}
+			defer f.Close()
+			f.WriteString("first\nsecond\npart")
+		}()
+
+		lines, newLines := tail(map[string]interface{}{"path": path, "follow": true, "follow_seconds": 1})
+		assert.Equal(t, []string{"old"}, lines)
+		assert.Equal(t, []string{"first", "second"}, newLines)
+	})
+
+	t.Run("rejects too many lines", func(t *testing.T) {
+		_, err := server.tailFileTool().Handler(context.Background(), map[string]interface{}{
+			"path": filepath.Join(tempDir, "short.txt"), "lines": 20000,
+		})
+		assert.Error(t, err)
+	})
+}
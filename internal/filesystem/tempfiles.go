@@ -0,0 +1,174 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// tempManager keeps a directory under filesystem.temp_dir for each client,
+// holding the temp files it creates, and removes it when the client
+// disconnects or the server shuts down.
+type tempManager struct {
+	mu sync.Mutex
+	// dirs is keyed by the HTTP client; nil over stdio.
+	dirs map[*mcp.Session]string
+}
+
+func newTempManager() *tempManager {
+	return &tempManager{dirs: make(map[*mcp.Session]string)}
+}
+
+// dir returns the temp directory of session, creating it under root.
+func (m *tempManager) dir(root string, session *mcp.Session) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if dir, ok := m.dirs[session]; ok {
+		if _, err := os.Stat(dir); err == nil {
+			return dir, nil
+		}
+	}
+	if err := os.MkdirAll(root, 0700); err != nil {
+		return "", err
+	}
+	dir, err := os.MkdirTemp(root, "session-")
+	if err != nil {
+		return "", err
+	}
+	_, existed := m.dirs[session]
+	m.dirs[session] = dir
+	if session != nil && !existed {
+		session.OnClose(func() { m.remove(session) })
+	}
+	return dir, nil
+}
+
+func (m *tempManager) remove(session *mcp.Session) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if dir, ok := m.dirs[session]; ok {
+		os.RemoveAll(dir)
+		delete(m.dirs, session)
+	}
+}
+
+func (m *tempManager) removeAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for session, dir := range m.dirs {
+		os.RemoveAll(dir)
+		delete(m.dirs, session)
+	}
+}
+
+// tempDir returns the calling client's temp directory.
+func (s *Server) tempDir(ctx context.Context) (string, error) {
+	root := s.config.TempDir
+	if root == "" {
+		return "", fmt.Errorf("%w: temp files are disabled; set filesystem.temp_dir", common.ErrNotImplemented)
+	}
+	if err := s.validator.ValidatePath(root); err != nil {
+		return "", fmt.Errorf("filesystem.temp_dir must be inside the allowed paths: %w", err)
+	}
+	return s.temps.dir(root, mcp.SessionFromContext(ctx))
+}
+
+// checkTempPattern rejects patterns that would place a temp file outside
+// the client's temp directory.
+func checkTempPattern(pattern string) error {
+	if strings.ContainsAny(pattern, `/\`) || strings.Contains(pattern, "..") {
+		return &mcp.ParamError{Param: "pattern", Message: "pattern must be a file name, without path separators"}
+	}
+	return nil
+}
+
+func (s *Server) createTempFileTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "create_temp_file",
+		Description: "Create a new file with a unique name in this client's temp directory, for scratch output such as a patch or a test fixture. The directory and everything in it are deleted when the client disconnects or the server stops",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"pattern": mcp.StringProperty("File name, where the last * is replaced by a random string, e.g. fixture-*.json (default: tmp-*)"),
+				"content": mcp.StringProperty("Content to write to the file (default: empty)"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleCreateTempFile),
+	}
+}
+
+type createTempFileArgs struct {
+	Pattern string `json:"pattern" default:"tmp-*"`
+	Content string `json:"content"`
+}
+
+func (s *Server) handleCreateTempFile(ctx context.Context, args createTempFileArgs) (*mcp.ToolResult, error) {
+	if err := checkTempPattern(args.Pattern); err != nil {
+		return nil, err
+	}
+	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
+	if int64(len(args.Content)) > maxSize {
+		return nil, fmt.Errorf("%w: content is %d bytes, max is %d bytes", common.ErrFileTooLarge, len(args.Content), maxSize)
+	}
+	dir, err := s.tempDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(dir, args.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	_, err = f.WriteString(args.Content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, err
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"path":       f.Name(),
+		"size_bytes": len(args.Content),
+	})
+}
+
+func (s *Server) createTempDirTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "create_temp_dir",
+		Description: "Create a new directory with a unique name in this client's temp directory, e.g. to unpack an archive or build into. It is deleted, with everything in it, when the client disconnects or the server stops",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"pattern": mcp.StringProperty("Directory name, where the last * is replaced by a random string, e.g. build-* (default: tmp-*)"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleCreateTempDir),
+	}
+}
+
+type createTempDirArgs struct {
+	Pattern string `json:"pattern" default:"tmp-*"`
+}
+
+func (s *Server) handleCreateTempDir(ctx context.Context, args createTempDirArgs) (*mcp.ToolResult, error) {
+	if err := checkTempPattern(args.Pattern); err != nil {
+		return nil, err
+	}
+	dir, err := s.tempDir(ctx)
+	if err != nil {
+		return nil, err
+	}
+	path, err := os.MkdirTemp(dir, args.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.JSONResult(map[string]interface{}{"path": path})
+}
@@ -0,0 +1,75 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTempFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	server.config.TempDir = filepath.Join(tempDir, "tmp")
+
+	create := func(tool string, params map[string]interface{}) (string, error) {
+		handler := server.createTempFileTool().Handler
+		if tool == "dir" {
+			handler = server.createTempDirTool().Handler
+		}
+		result, err := handler(context.Background(), params)
+		if err != nil {
+			return "", err
+		}
+		var out struct {
+			Path string `json:"path"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out.Path, nil
+	}
+
+	file, err := create("file", map[string]interface{}{"pattern": "fixture-*.json", "content": `{"a": 1}`})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(file, server.config.TempDir+string(filepath.Separator)))
+	assert.True(t, strings.HasPrefix(filepath.Base(file), "fixture-"))
+	assert.True(t, strings.HasSuffix(file, ".json"))
+	content, err := os.ReadFile(file)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a": 1}`, string(content))
+
+	other, err := create("file", map[string]interface{}{"pattern": "fixture-*.json"})
+	require.NoError(t, err)
+	assert.NotEqual(t, file, other)
+	assert.Equal(t, filepath.Dir(file), filepath.Dir(other), "one directory per client")
+
+	dir, err := create("dir", map[string]interface{}{})
+	require.NoError(t, err)
+	assert.DirExists(t, dir)
+	assert.True(t, strings.HasPrefix(filepath.Base(dir), "tmp-"))
+
+	_, err = create("file", map[string]interface{}{"pattern": "../escape-*"})
+	assert.Error(t, err)
+
+	server.Shutdown(context.Background())
+	assert.NoFileExists(t, file)
+	assert.NoDirExists(t, dir)
+	assert.DirExists(t, server.config.TempDir)
+
+	t.Run("outside allowed paths", func(t *testing.T) {
+		server.config.TempDir = filepath.Join(t.TempDir(), "tmp")
+		_, err := create("dir", map[string]interface{}{})
+		assert.ErrorIs(t, err, common.ErrPathNotAllowed)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		server.config.TempDir = ""
+		_, err := create("file", map[string]interface{}{})
+		assert.ErrorIs(t, err, common.ErrNotImplemented)
+	})
+}
@@ -0,0 +1,213 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+	"gopkg.in/yaml.v3"
+)
+
+// errTemplateOutputTooLarge stops a template whose output passes the file
+// size limit.
+var errTemplateOutputTooLarge = errors.New("template output too large")
+
+// templateFuncs are the functions templates may call besides the builtins.
+var templateFuncs = template.FuncMap{
+	"upper":     strings.ToUpper,
+	"lower":     strings.ToLower,
+	"title":     titleCase,
+	"trim":      strings.TrimSpace,
+	"replace":   func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+	"split":     func(sep, s string) []string { return strings.Split(s, sep) },
+	"join":      joinValues,
+	"contains":  func(sub, s string) bool { return strings.Contains(s, sub) },
+	"hasPrefix": func(prefix, s string) bool { return strings.HasPrefix(s, prefix) },
+	"hasSuffix": func(suffix, s string) bool { return strings.HasSuffix(s, suffix) },
+	"indent":    indentLines,
+	"quote":     func(v interface{}) string { return fmt.Sprintf("%q", fmt.Sprint(v)) },
+	"default":   defaultValue,
+	"toJSON":    toJSON,
+	"toYAML":    toYAML,
+}
+
+func (s *Server) renderTemplateTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name: "render_template",
+		Description: "Render a Go text/template with variables and write the result to a file, e.g. to scaffold configs or boilerplate. " +
+			"Variables are referenced as {{.name}}; a variable the template uses but the call does not give is an error, so write optional ones as {{index . \"name\" | default VALUE}}. " +
+			"Besides the builtins, templates can call upper, lower, title, trim, replace OLD NEW, split SEP, join SEP, contains, hasPrefix, hasSuffix, indent N, quote, default VALUE, toJSON and toYAML. " +
+			"Without destination, the rendered text is returned instead",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"template":      mcp.StringProperty("The template text"),
+				"template_path": mcp.StringProperty("Absolute path to a template file, instead of template"),
+				"variables":     mcp.ObjectProperty("Values for the template, of any JSON type"),
+				"destination":   mcp.StringProperty("Absolute path to write the result to"),
+				"overwrite":     mcp.BoolProperty("Replace destination if it exists"),
+			},
+			[]string{},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleRenderTemplate),
+	}
+}
+
+type renderTemplateArgs struct {
+	Template     string                 `json:"template"`
+	TemplatePath string                 `json:"template_path"`
+	Variables    map[string]interface{} `json:"variables"`
+	Destination  string                 `json:"destination"`
+	Overwrite    bool                   `json:"overwrite"`
+}
+
+func (s *Server) handleRenderTemplate(ctx context.Context, args renderTemplateArgs) (*mcp.ToolResult, error) {
+	if (args.Template == "") == (args.TemplatePath == "") {
+		return nil, &mcp.ParamError{Param: "template", Message: "give exactly one of template and template_path"}
+	}
+	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
+
+	text, name, param := args.Template, "template", "template"
+	if args.TemplatePath != "" {
+		absPath, err := s.validator.ResolvePath(args.TemplatePath)
+		if err != nil {
+			return nil, err
+		}
+		info, err := statExisting(absPath, args.TemplatePath)
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("%w: %s", common.ErrNotAFile, args.TemplatePath)
+		}
+		if info.Size() > maxSize {
+			return nil, fmt.Errorf("%w: %s is %d bytes, max is %d bytes", common.ErrFileTooLarge, args.TemplatePath, info.Size(), maxSize)
+		}
+		data, err := os.ReadFile(absPath)
+		if err != nil {
+			return nil, err
+		}
+		text, name, param = string(data), filepath.Base(absPath), "template_path"
+	}
+
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, &mcp.ParamError{Param: param, Message: err.Error()}
+	}
+	var out bytes.Buffer
+	if err := tmpl.Execute(&limitedBuffer{buf: &out, limit: maxSize}, args.Variables); err != nil {
+		if errors.Is(err, errTemplateOutputTooLarge) {
+			return nil, fmt.Errorf("%w: output passes %d bytes", common.ErrFileTooLarge, maxSize)
+		}
+		return nil, fmt.Errorf("%w: %v", common.ErrInvalidInput, err)
+	}
+
+	if args.Destination == "" {
+		return mcp.TextResult(out.String()), nil
+	}
+	dstPath, err := filepath.Abs(args.Destination)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.ValidatePath(dstPath); err != nil {
+		return nil, err
+	}
+	perm := os.FileMode(0644)
+	if info, err := os.Stat(dstPath); err == nil {
+		if !args.Overwrite {
+			return nil, fmt.Errorf("%w: %s; set overwrite to replace it", common.ErrAlreadyExists, args.Destination)
+		}
+		if !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("%w: %s", common.ErrNotAFile, args.Destination)
+		}
+		perm = info.Mode().Perm()
+	}
+	if err := os.MkdirAll(filepath.Dir(dstPath), 0755); err != nil {
+		return nil, err
+	}
+	if err := writeFileAtomic(dstPath, out.Bytes(), perm); err != nil {
+		return nil, err
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"path":       dstPath,
+		"size_bytes": out.Len(),
+		"lines":      strings.Count(out.String(), "\n"),
+	})
+}
+
+// limitedBuffer fails writes past limit bytes.
+type limitedBuffer struct {
+	buf   *bytes.Buffer
+	limit int64
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if int64(b.buf.Len()+len(p)) > b.limit {
+		return 0, errTemplateOutputTooLarge
+	}
+	return b.buf.Write(p)
+}
+
+func titleCase(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		r, size := utf8.DecodeRuneInString(w)
+		words[i] = string(unicode.ToTitle(r)) + w[size:]
+	}
+	return strings.Join(words, " ")
+}
+
+func joinValues(sep string, values interface{}) string {
+	switch v := values.(type) {
+	case []string:
+		return strings.Join(v, sep)
+	case []interface{}:
+		parts := make([]string, len(v))
+		for i, item := range v {
+			parts[i] = fmt.Sprint(item)
+		}
+		return strings.Join(parts, sep)
+	}
+	return fmt.Sprint(values)
+}
+
+// indentLines indents each non-empty line of s by n spaces.
+func indentLines(n int, s string) string {
+	pad := strings.Repeat(" ", n)
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		if line != "" {
+			lines[i] = pad + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// defaultValue returns value, or def if value is missing or empty; it is
+// meant for pipelines like {{index . "port" | default 8080}}.
+func defaultValue(def interface{}, value ...interface{}) interface{} {
+	if len(value) == 0 || value[0] == nil || value[0] == "" || value[0] == false {
+		return def
+	}
+	return value[0]
+}
+
+func toJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
+
+func toYAML(v interface{}) (string, error) {
+	data, err := yaml.Marshal(v)
+	return strings.TrimSuffix(string(data), "\n"), err
+}
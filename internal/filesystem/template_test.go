@@ -0,0 +1,103 @@
+package filesystem
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	render := func(params map[string]interface{}) (string, error) {
+		result, err := server.renderTemplateTool().Handler(context.Background(), params)
+		if err != nil {
+			return "", err
+		}
+		return result.Content[0].Text, nil
+	}
+	variables := map[string]interface{}{
+		"name":     "billing api",
+		"port":     8080.0,
+		"features": []interface{}{"auth", "metrics"},
+		"env":      map[string]interface{}{"LOG_LEVEL": "debug", "DB_HOST": "db"},
+	}
+
+	t.Run("inline", func(t *testing.T) {
+		out, err := render(map[string]interface{}{
+			"template": `service: {{.name | replace " " "-"}}
+title: {{title .name}}
+port: {{.port}}
+replicas: {{index . "replicas" | default 1}}
+features: {{join ", " .features}}
+env:
+{{- range $k, $v := .env}}
+  {{$k}}: {{quote $v}}
+{{- end}}
+`,
+			"variables": variables,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, `service: billing-api
+title: Billing Api
+port: 8080
+replicas: 1
+features: auth, metrics
+env:
+  DB_HOST: "db"
+  LOG_LEVEL: "debug"
+`, out)
+	})
+
+	t.Run("file to destination", func(t *testing.T) {
+		tmplPath := filepath.Join(tempDir, "Dockerfile.tmpl")
+		require.NoError(t, os.WriteFile(tmplPath, []byte("FROM golang\nEXPOSE {{.port}}\n"), 0644))
+		dst := filepath.Join(tempDir, "out", "Dockerfile")
+
+		_, err := render(map[string]interface{}{"template_path": tmplPath, "variables": variables, "destination": dst})
+		require.NoError(t, err)
+		content, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		assert.Equal(t, "FROM golang\nEXPOSE 8080\n", string(content))
+
+		_, err = render(map[string]interface{}{"template_path": tmplPath, "variables": variables, "destination": dst})
+		assert.ErrorIs(t, err, common.ErrAlreadyExists)
+
+		variables := map[string]interface{}{"port": 9090}
+		_, err = render(map[string]interface{}{"template_path": tmplPath, "variables": variables, "destination": dst, "overwrite": true})
+		require.NoError(t, err)
+		content, _ = os.ReadFile(dst)
+		assert.Equal(t, "FROM golang\nEXPOSE 9090\n", string(content))
+	})
+
+	t.Run("missing variable", func(t *testing.T) {
+		dst := filepath.Join(tempDir, "missing.txt")
+		_, err := render(map[string]interface{}{"template": "{{.nmae}}", "variables": variables, "destination": dst})
+		assert.ErrorIs(t, err, common.ErrInvalidInput)
+		assert.NoFileExists(t, dst)
+	})
+
+	t.Run("denied destination", func(t *testing.T) {
+		secret := filepath.Join(tempDir, "secret.txt")
+		require.NoError(t, os.WriteFile(secret, []byte("secret"), 0644))
+		server.validator.DeniedPaths = []string{secret}
+		defer func() { server.validator.DeniedPaths = nil }()
+
+		_, err := render(map[string]interface{}{"template": "pwned", "destination": secret, "overwrite": true})
+		assert.ErrorIs(t, err, common.ErrPathNotAllowed)
+		content, _ := os.ReadFile(secret)
+		assert.Equal(t, "secret", string(content))
+	})
+
+	t.Run("bad template", func(t *testing.T) {
+		_, err := render(map[string]interface{}{"template": "{{.name"})
+		assert.Error(t, err)
+		_, err = render(map[string]interface{}{})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,249 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// textEncodings are the character encodings files can be read and written
+// in. UTF-16 is always written with a byte order mark, UTF-8 without.
+var textEncodings = []string{"utf-8", "utf-16le", "utf-16be", "latin-1"}
+
+var encodingAliases = map[string]string{
+	"utf8":       "utf-8",
+	"latin1":     "latin-1",
+	"iso-8859-1": "latin-1",
+}
+
+var (
+	bomUTF8    = []byte{0xEF, 0xBB, 0xBF}
+	bomUTF16LE = []byte{0xFF, 0xFE}
+	bomUTF16BE = []byte{0xFE, 0xFF}
+)
+
+// detectEncoding guesses the text encoding of data from its byte order
+// mark or, failing that, its bytes. It returns "" for binary data.
+func detectEncoding(data []byte) string {
+	// UTF-16 comes in whole code units.
+	even := len(data)%2 == 0
+	switch {
+	case bytes.HasPrefix(data, bomUTF8):
+		return "utf-8"
+	case bytes.HasPrefix(data, bomUTF16LE) && even:
+		return "utf-16le"
+	case bytes.HasPrefix(data, bomUTF16BE) && even:
+		return "utf-16be"
+	}
+
+	// Mostly-ASCII UTF-16 has a zero in every other byte, which is also
+	// valid UTF-8, so look for that first.
+	sample := data[:min(len(data), 4096)]
+	if pairs := len(sample) / 2; pairs > 0 && even {
+		var evenZeros, oddZeros int
+		for i := 0; i+1 < len(sample); i += 2 {
+			if sample[i] == 0 {
+				evenZeros++
+			}
+			if sample[i+1] == 0 {
+				oddZeros++
+			}
+		}
+		switch {
+		case oddZeros*10 > pairs*3 && evenZeros*20 < pairs:
+			return "utf-16le"
+		case evenZeros*10 > pairs*3 && oddZeros*20 < pairs:
+			return "utf-16be"
+		}
+	}
+
+	if bytes.IndexByte(data, 0) >= 0 {
+		return ""
+	}
+	if utf8.Valid(data) {
+		return "utf-8"
+	}
+	// Any byte is Latin-1, so call it binary if it has many control
+	// characters text would not.
+	controls := 0
+	for _, b := range sample {
+		if b < 0x20 && !strings.ContainsRune("\t\n\v\f\r\x1b", rune(b)) {
+			controls++
+		}
+	}
+	if controls*100 > len(sample) {
+		return ""
+	}
+	return "latin-1"
+}
+
+// decodeText decodes data in the given encoding, dropping its byte order
+// mark.
+func decodeText(data []byte, encoding string) (string, error) {
+	switch encoding {
+	case "utf-8":
+		data = bytes.TrimPrefix(data, bomUTF8)
+		if !utf8.Valid(data) {
+			return "", fmt.Errorf("%w: content is not valid UTF-8", common.ErrInvalidInput)
+		}
+		return string(data), nil
+	case "utf-16le", "utf-16be":
+		var order binary.ByteOrder = binary.LittleEndian
+		data = bytes.TrimPrefix(data, bomUTF16LE)
+		if encoding == "utf-16be" {
+			order = binary.BigEndian
+			data = bytes.TrimPrefix(data, bomUTF16BE)
+		}
+		units := make([]uint16, len(data)/2)
+		for i := range units {
+			units[i] = order.Uint16(data[2*i:])
+		}
+		return string(utf16.Decode(units)), nil
+	case "latin-1":
+		runes := make([]rune, len(data))
+		for i, b := range data {
+			runes[i] = rune(b)
+		}
+		return string(runes), nil
+	}
+	return "", fmt.Errorf("%w: unknown encoding %s", common.ErrInvalidInput, encoding)
+}
+
+func encodeText(text, encoding string) ([]byte, error) {
+	switch encoding {
+	case "utf-8":
+		return []byte(text), nil
+	case "utf-16le", "utf-16be":
+		var order binary.AppendByteOrder = binary.LittleEndian
+		data := append([]byte{}, bomUTF16LE...)
+		if encoding == "utf-16be" {
+			order = binary.BigEndian
+			data = append([]byte{}, bomUTF16BE...)
+		}
+		for _, u := range utf16.Encode([]rune(text)) {
+			data = order.AppendUint16(data, u)
+		}
+		return data, nil
+	case "latin-1":
+		data := make([]byte, 0, len(text))
+		for _, r := range text {
+			if r > 0xFF {
+				return nil, fmt.Errorf("%w: %q cannot be written in Latin-1", common.ErrInvalidInput, r)
+			}
+			data = append(data, byte(r))
+		}
+		return data, nil
+	}
+	return nil, fmt.Errorf("%w: unknown encoding %s", common.ErrInvalidInput, encoding)
+}
+
+func isTextEncoding(encoding string) bool {
+	for _, e := range textEncodings {
+		if e == encoding {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) convertEncodingTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "convert_encoding",
+		Description: "Convert a text file between character encodings (utf-8, utf-16le, utf-16be, latin-1) in place. The current encoding is detected from the byte order mark or content unless given",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path": mcp.StringProperty("Absolute path to the file"),
+				"to":   mcp.StringProperty("Encoding to convert to: utf-8, utf-16le, utf-16be or latin-1"),
+				"from": mcp.StringProperty("Current encoding (default: auto, detected)"),
+			},
+			[]string{"path", "to"},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleConvertEncoding),
+	}
+}
+
+type convertEncodingArgs struct {
+	Path string `json:"path" validate:"required"`
+	To   string `json:"to" validate:"required"`
+	From string `json:"from" default:"auto"`
+}
+
+func (s *Server) handleConvertEncoding(ctx context.Context, args convertEncodingArgs) (*mcp.ToolResult, error) {
+	to, err := normalizeEncoding("to", args.To)
+	if err != nil {
+		return nil, err
+	}
+	from := args.From
+	if from != "auto" {
+		if from, err = normalizeEncoding("from", from); err != nil {
+			return nil, err
+		}
+	}
+
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := statExisting(absPath, args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", common.ErrNotAFile, args.Path)
+	}
+	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
+	if info.Size() > maxSize {
+		return nil, fmt.Errorf("%w: file size %d exceeds limit %d", common.ErrFileTooLarge, info.Size(), maxSize)
+	}
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if from == "auto" {
+		if from = detectEncoding(data); from == "" {
+			return nil, fmt.Errorf("%w: %s looks binary; give its encoding as from", common.ErrInvalidInput, args.Path)
+		}
+	}
+	text, err := decodeText(data, from)
+	if err != nil {
+		return nil, err
+	}
+	converted, err := encodeText(text, to)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(converted, data) {
+		if err := writeFileAtomic(absPath, converted, info.Mode().Perm()); err != nil {
+			return nil, err
+		}
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"path":         absPath,
+		"from":         from,
+		"to":           to,
+		"bytes_before": len(data),
+		"bytes_after":  len(converted),
+	})
+}
+
+// normalizeEncoding resolves an alias of one of textEncodings.
+func normalizeEncoding(param, encoding string) (string, error) {
+	encoding = strings.ToLower(encoding)
+	if alias, ok := encodingAliases[encoding]; ok {
+		encoding = alias
+	}
+	if !isTextEncoding(encoding) {
+		return "", &mcp.ParamError{Param: param, Message: fmt.Sprintf("parameter %s must be one of %s", param, strings.Join(textEncodings, ", "))}
+	}
+	return encoding, nil
+}
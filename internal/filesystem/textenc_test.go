@@ -0,0 +1,106 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	utf16le, err := encodeText("héllo wörld\n", "utf-16le")
+	require.NoError(t, err)
+	utf16be, err := encodeText("héllo wörld\n", "utf-16be")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{"ascii", []byte("hello\n"), "utf-8"},
+		{"utf-8 bom", []byte("\xEF\xBB\xBFhello"), "utf-8"},
+		{"utf-16le bom", utf16le, "utf-16le"},
+		{"utf-16be bom", utf16be, "utf-16be"},
+		{"utf-16le without bom", utf16le[2:], "utf-16le"},
+		{"utf-16be without bom", utf16be[2:], "utf-16be"},
+		{"latin-1", []byte("caf\xe9 cr\xe8me\n"), "latin-1"},
+		{"binary", []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a, 0x00, 0x00, 0x00, 0x0d}, ""},
+		{"controls", []byte("\x01\x02\x03\x04\xff"), ""},
+		{"empty", nil, "utf-8"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, detectEncoding(tt.data), tt.name)
+	}
+}
+
+func TestTextEncodings(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	text := "naïve café\nline two\n"
+
+	t.Run("round trip", func(t *testing.T) {
+		for _, encoding := range textEncodings {
+			path := filepath.Join(tempDir, encoding+".txt")
+			_, err := server.writeFileTool().Handler(context.Background(), map[string]interface{}{
+				"path": path, "content": text, "encoding": encoding,
+			})
+			require.NoError(t, err, encoding)
+
+			result, err := server.readFileTool().Handler(context.Background(), map[string]interface{}{"path": path})
+			require.NoError(t, err, encoding)
+			assert.Equal(t, text, result.Content[0].Text, "detected %s", encoding)
+
+			result, err = server.readFileTool().Handler(context.Background(), map[string]interface{}{"path": path, "encoding": encoding})
+			require.NoError(t, err, encoding)
+			assert.Equal(t, text, result.Content[0].Text, encoding)
+		}
+
+		data, _ := os.ReadFile(filepath.Join(tempDir, "utf-16le.txt"))
+		assert.Equal(t, []byte{0xFF, 0xFE, 'n', 0}, data[:4])
+		data, _ = os.ReadFile(filepath.Join(tempDir, "latin-1.txt"))
+		assert.Len(t, data, len([]rune(text)))
+	})
+
+	t.Run("unencodable", func(t *testing.T) {
+		_, err := server.writeFileTool().Handler(context.Background(), map[string]interface{}{
+			"path": filepath.Join(tempDir, "x.txt"), "content": "€", "encoding": "latin1",
+		})
+		assert.Error(t, err)
+		_, err = server.writeFileTool().Handler(context.Background(), map[string]interface{}{
+			"path": filepath.Join(tempDir, "x.txt"), "content": "x", "encoding": "ebcdic",
+		})
+		assert.Error(t, err)
+	})
+
+	t.Run("convert", func(t *testing.T) {
+		path := filepath.Join(tempDir, "convert.txt")
+		data, _ := encodeText(text, "utf-16be")
+		require.NoError(t, os.WriteFile(path, data, 0600))
+
+		result, err := server.convertEncodingTool().Handler(context.Background(), map[string]interface{}{"path": path, "to": "UTF8"})
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		assert.Equal(t, "utf-16be", out["from"])
+		assert.Equal(t, "utf-8", out["to"])
+		data, _ = os.ReadFile(path)
+		assert.Equal(t, text, string(data))
+		info, _ := os.Stat(path)
+		assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+		_, err = server.convertEncodingTool().Handler(context.Background(), map[string]interface{}{"path": path, "to": "latin-1", "from": "utf-8"})
+		require.NoError(t, err)
+		data, _ = os.ReadFile(path)
+		assert.Equal(t, "na\xefve caf\xe9\nline two\n", string(data))
+
+		binFile := filepath.Join(tempDir, "data.bin")
+		require.NoError(t, os.WriteFile(binFile, []byte{0x00, 0x01, 0x02}, 0644))
+		_, err = server.convertEncodingTool().Handler(context.Background(), map[string]interface{}{"path": binFile, "to": "utf-8"})
+		assert.Error(t, err)
+	})
+}
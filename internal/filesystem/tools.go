@@ -2,9 +2,12 @@ package filesystem
 
 import (
 	"bufio"
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
 	"io"
+	"mime"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -23,6 +26,14 @@ type FileInfo struct {
 	IsDirectory bool      `json:"is_directory"`
 	IsSymlink   bool      `json:"is_symlink"`
 	ModifiedAt  time.Time `json:"modified_at"`
+	// SymlinkTarget is what a symlink points to, as stored; ResolvedPath is
+	// the file it ends up at, given only when that is an allowed path.
+	SymlinkTarget string `json:"symlink_target,omitempty"`
+	ResolvedPath  string `json:"resolved_path,omitempty"`
+	// Xattrs holds the extended attributes; binary values are in base64
+	// after a "base64:" prefix.
+	Xattrs      map[string]string `json:"xattrs,omitempty"`
+	Quarantined bool              `json:"quarantined,omitempty"`
 }
 
 type DirectoryEntry struct {
@@ -36,27 +47,45 @@ type GrepMatch struct {
 	File       string `json:"file"`
 	LineNumber int    `json:"line_number"`
 	Line       string `json:"line"`
+	// Column is the 1-based byte column of the first match in the line, and
+	// ByteOffset its offset from the start of the file.
+	Column     int      `json:"column"`
+	ByteOffset int64    `json:"byte_offset"`
+	Before     []string `json:"before,omitempty"`
+	After      []string `json:"after,omitempty"`
 }
 
 func (s *Server) readFileTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "read_file",
-		Description: "Read the contents of a file",
+		Description: "Read the contents of a file. Text in UTF-16 or Latin-1 is detected and returned decoded; binary files are returned as an embedded resource with base64 content. Files over the size limit can be read in parts with offset and length, or head",
 		InputSchema: mcp.BuildInputSchema(
 			map[string]interface{}{
-				"path": mcp.StringProperty("Absolute path to the file"),
+				"path":        mcp.StringProperty("Absolute path to the file"),
+				"as_resource": mcp.BoolProperty("Return a link to the file's file:// resource instead of its content, for large files the client can read with resources/read"),
+				"offset":      mcp.IntProperty("Byte offset to start reading at; a negative offset counts from the end of the file"),
+				"length":      mcp.IntProperty("Number of bytes to read from offset (default: to the end of the file, up to the size limit)"),
+				"head":        mcp.IntProperty("Read only the first this many lines"),
+				"encoding":    mcp.StringProperty("text, base64, utf-8, utf-16le, utf-16be or latin-1. text detects the character encoding; base64 returns the raw bytes encoded, for binary files (default: text)"),
 			},
 			[]string{"path"},
 		),
-		Handler: s.handleReadFile,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleReadFile),
 	}
 }
 
-func (s *Server) handleReadFile(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	path, err := mcp.GetStringParam(params, "path", true)
-	if err != nil {
-		return nil, err
-	}
+type readFileArgs struct {
+	Path       string `json:"path" validate:"required"`
+	AsResource bool   `json:"as_resource"`
+	Offset     *int   `json:"offset"`
+	Length     *int   `json:"length"`
+	Head       int    `json:"head"`
+	Encoding   string `json:"encoding"`
+}
+
+func (s *Server) handleReadFile(ctx context.Context, args readFileArgs) (*mcp.ToolResult, error) {
+	path := args.Path
 
 	absPath, err := s.validator.ResolvePath(path)
 	if err != nil {
@@ -75,68 +104,143 @@ func (s *Server) handleReadFile(ctx context.Context, params map[string]interface
 		return nil, fmt.Errorf("%w: %s", common.ErrNotAFile, path)
 	}
 
+	if args.AsResource {
+		return &mcp.ToolResult{Content: []mcp.ContentBlock{mcp.ResourceLinkBlock(mcp.Resource{
+			URI:      fileURI(absPath),
+			Name:     info.Name(),
+			MimeType: mime.TypeByExtension(filepath.Ext(absPath)),
+			Size:     info.Size(),
+		})}}, nil
+	}
+
+	encoding, err := contentEncoding(args.Encoding)
+	if err != nil {
+		return nil, err
+	}
+
 	maxSize := int64(s.config.MaxFileSizeMB) * 1024 * 1024
-	if info.Size() > maxSize {
-		return nil, fmt.Errorf("%w: file size %d exceeds limit %d", common.ErrFileTooLarge, info.Size(), maxSize)
+
+	var content []byte
+	partial := true
+	switch {
+	case args.Head > 0:
+		content, err = s.readHead(absPath, args.Head, maxSize)
+	case args.Offset != nil || args.Length != nil:
+		content, err = s.readRange(absPath, info.Size(), args.Offset, args.Length, maxSize)
+	default:
+		if info.Size() > maxSize {
+			return nil, fmt.Errorf("%w: file size %d exceeds limit %d", common.ErrFileTooLarge, info.Size(), maxSize)
+		}
+		content, err = os.ReadFile(absPath)
+		partial = false
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	content, err := os.ReadFile(absPath)
+	switch {
+	case encoding == "base64":
+		return mcp.TextResult(base64.StdEncoding.EncodeToString(content)), nil
+	case encoding != "text":
+		text, err := decodeText(content, encoding)
+		if err != nil {
+			return nil, err
+		}
+		return mcp.TextResult(text), nil
+	case partial:
+		return partialResult(absPath, content), nil
+	}
+	detected := detectEncoding(content)
+	if detected == "" {
+		return &mcp.ToolResult{Content: []mcp.ContentBlock{
+			mcp.ResourceBlock(resourceContents(fileURI(absPath), absPath, content)),
+		}}, nil
+	}
+	text, err := decodeText(content, detected)
 	if err != nil {
 		return nil, err
 	}
+	return mcp.TextResult(text), nil
+}
 
-	return mcp.TextResult(string(content)), nil
+// contentEncoding checks the encoding of read_file and write_file content.
+func contentEncoding(encoding string) (string, error) {
+	switch encoding {
+	case "", "text":
+		return "text", nil
+	case "base64":
+		return encoding, nil
+	}
+	if encoding, err := normalizeEncoding("encoding", encoding); err == nil {
+		return encoding, nil
+	}
+	return "", &mcp.ParamError{Param: "encoding", Message: "parameter encoding must be one of text, base64, " + strings.Join(textEncodings, ", ")}
 }
 
+// maxReadLines bounds the lines one read_file_lines call returns; has_more
+// and next_start_line page through the rest.
+const maxReadLines = 5000
+
 func (s *Server) readFileLinesTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "read_file_lines",
-		Description: "Read specific line range from a file",
+		Description: "Read a range of lines from a file, with the file's total line count and whether more lines follow. Negative line numbers count from the end: start_line -20 reads the last 20 lines. Returns at most 5000 lines; continue from next_start_line",
 		InputSchema: mcp.BuildInputSchema(
 			map[string]interface{}{
 				"path":       mcp.StringProperty("Absolute path to the file"),
-				"start_line": mcp.IntProperty("Starting line number (1-indexed)"),
-				"end_line":   mcp.IntProperty("Ending line number (inclusive)"),
+				"start_line": mcp.IntProperty("Starting line number (1-indexed; negative counts from the end, -1 is the last line; default: 1)"),
+				"end_line":   mcp.IntProperty("Ending line number, inclusive (negative counts from the end; default: -1)"),
 			},
-			[]string{"path", "start_line", "end_line"},
+			[]string{"path"},
 		),
-		Handler: s.handleReadFileLines,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleReadFileLines),
 	}
 }
 
-func (s *Server) handleReadFileLines(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	path, err := mcp.GetStringParam(params, "path", true)
-	if err != nil {
-		return nil, err
-	}
+type readFileLinesArgs struct {
+	Path      string `json:"path" validate:"required"`
+	StartLine int    `json:"start_line" default:"1"`
+	EndLine   int    `json:"end_line" default:"-1"`
+}
+
+func (s *Server) handleReadFileLines(ctx context.Context, args readFileLinesArgs) (*mcp.ToolResult, error) {
+	path, startLine, endLine := args.Path, args.StartLine, args.EndLine
 
-	startLine, err := mcp.GetIntParam(params, "start_line", true, 1)
+	absPath, err := s.validator.ResolvePath(path)
 	if err != nil {
 		return nil, err
 	}
 
-	endLine, err := mcp.GetIntParam(params, "end_line", true, 0)
+	file, err := os.Open(absPath)
 	if err != nil {
 		return nil, err
 	}
+	defer file.Close()
 
+	// Negative line numbers need the line count before the range is known.
+	total := -1
+	if startLine < 0 || endLine < 0 {
+		if total, err = countLines(file); err != nil {
+			return nil, err
+		}
+		if _, err := file.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		if startLine < 0 {
+			startLine = total + startLine + 1
+		}
+		if endLine < 0 {
+			endLine = total + endLine + 1
+		}
+	}
 	if startLine < 1 {
 		startLine = 1
 	}
-	if endLine < startLine {
-		return nil, fmt.Errorf("end_line must be >= start_line")
-	}
-
-	absPath, err := s.validator.ResolvePath(path)
-	if err != nil {
-		return nil, err
-	}
-
-	file, err := os.Open(absPath)
-	if err != nil {
-		return nil, err
+	if endLine < startLine && total != 0 {
+		return nil, fmt.Errorf("%w: end_line must be >= start_line", common.ErrInvalidInput)
 	}
-	defer file.Close()
+	endLine = min(endLine, startLine+maxReadLines-1)
 
 	var lines []string
 	scanner := bufio.NewScanner(file)
@@ -147,7 +251,7 @@ func (s *Server) handleReadFileLines(ctx context.Context, params map[string]inte
 		if lineNum >= startLine && lineNum <= endLine {
 			lines = append(lines, scanner.Text())
 		}
-		if lineNum > endLine {
+		if lineNum > endLine && total >= 0 {
 			break
 		}
 	}
@@ -155,8 +259,47 @@ func (s *Server) handleReadFileLines(ctx context.Context, params map[string]inte
 	if err := scanner.Err(); err != nil {
 		return nil, err
 	}
+	if total < 0 {
+		total = lineNum
+	}
 
-	return mcp.TextResult(strings.Join(lines, "\n")), nil
+	result := map[string]interface{}{
+		"path":        absPath,
+		"content":     strings.Join(lines, "\n"),
+		"start_line":  startLine,
+		"end_line":    startLine + len(lines) - 1,
+		"total_lines": total,
+		"has_more":    startLine+len(lines)-1 < total,
+	}
+	if startLine+len(lines)-1 < total {
+		result["next_start_line"] = startLine + len(lines)
+	}
+	return mcp.JSONResult(result)
+}
+
+// countLines counts lines as bufio.ScanLines splits them: a final line
+// without a newline counts.
+func countLines(r io.Reader) (int, error) {
+	buf := make([]byte, 64*1024)
+	count := 0
+	last := byte('\n')
+	for {
+		n, err := r.Read(buf)
+		count += bytes.Count(buf[:n], []byte{'\n'})
+		if n > 0 {
+			last = buf[n-1]
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	if last != '\n' {
+		count++
+	}
+	return count, nil
 }
 
 func (s *Server) writeFileTool() *mcp.Tool {
@@ -165,45 +308,70 @@ func (s *Server) writeFileTool() *mcp.Tool {
 		Description: "Write content to a file (create or overwrite)",
 		InputSchema: mcp.BuildInputSchema(
 			map[string]interface{}{
-				"path":    mcp.StringProperty("Absolute path to the file"),
-				"content": mcp.StringProperty("Content to write"),
+				"path":                  mcp.StringProperty("Absolute path to the file"),
+				"content":               mcp.StringProperty("Content to write"),
+				"encoding":              mcp.StringProperty("text, base64, utf-8, utf-16le, utf-16be or latin-1. With base64, content is decoded and the bytes written, for binary files; text is UTF-8 (default: text)"),
+				"preserve_line_endings": mcp.BoolProperty("When overwriting, convert content's line endings to the file's existing style, lf or crlf"),
 			},
 			[]string{"path", "content"},
 		),
-		Handler: s.handleWriteFile,
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleWriteFile),
 	}
 }
 
-func (s *Server) handleWriteFile(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	path, err := mcp.GetStringParam(params, "path", true)
-	if err != nil {
-		return nil, err
-	}
+type writeFileArgs struct {
+	Path                string `json:"path" validate:"required"`
+	Content             string `json:"content" validate:"required"`
+	Encoding            string `json:"encoding"`
+	PreserveLineEndings bool   `json:"preserve_line_endings"`
+}
+
+func (s *Server) handleWriteFile(ctx context.Context, args writeFileArgs) (*mcp.ToolResult, error) {
+	path, content := args.Path, args.Content
 
-	content, err := mcp.GetStringParam(params, "content", true)
+	encoding, err := contentEncoding(args.Encoding)
 	if err != nil {
 		return nil, err
 	}
-
 	absPath, err := filepath.Abs(path)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.validator.ValidatePath(filepath.Dir(absPath)); err != nil {
+	if err := s.validator.ValidatePath(absPath); err != nil {
 		return nil, err
 	}
 
+	if args.PreserveLineEndings && encoding != "base64" {
+		if style := existingLineEnding(absPath); style != "" {
+			content = convertLineEndings(content, style)
+		}
+	}
+
+	data := []byte(content)
+	switch encoding {
+	case "text":
+	case "base64":
+		if data, err = base64.StdEncoding.DecodeString(content); err != nil {
+			return nil, &mcp.ParamError{Param: "content", Message: fmt.Sprintf("content is not valid base64: %v", err)}
+		}
+	default:
+		if data, err = encodeText(content, encoding); err != nil {
+			return nil, err
+		}
+	}
+
 	dir := filepath.Dir(absPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return nil, err
 	}
 
-	if err := os.WriteFile(absPath, []byte(content), 0644); err != nil {
+	if err := os.WriteFile(absPath, data, 0644); err != nil {
 		return nil, err
 	}
 
-	return mcp.TextResult(fmt.Sprintf("Successfully wrote %d bytes to %s", len(content), absPath)), nil
+	return mcp.TextResult(fmt.Sprintf("Successfully wrote %d bytes to %s", len(data), absPath)), nil
 }
 
 func (s *Server) appendFileTool() *mcp.Tool {
@@ -217,31 +385,22 @@ func (s *Server) appendFileTool() *mcp.Tool {
 			},
 			[]string{"path", "content"},
 		),
-		Handler: s.handleAppendFile,
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleAppendFile),
 	}
 }
 
-func (s *Server) handleAppendFile(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	path, err := mcp.GetStringParam(params, "path", true)
-	if err != nil {
-		return nil, err
-	}
+type appendFileArgs struct {
+	Path    string `json:"path" validate:"required"`
+	Content string `json:"content" validate:"required"`
+}
 
-	content, err := mcp.GetStringParam(params, "content", true)
-	if err != nil {
-		return nil, err
-	}
+func (s *Server) handleAppendFile(ctx context.Context, args appendFileArgs) (*mcp.ToolResult, error) {
+	path, content := args.Path, args.Content
 
 	absPath, err := s.validator.ResolvePath(path)
 	if err != nil {
-		if !common.IsPathNotAllowed(err) {
-			absPath, _ = filepath.Abs(path)
-			if err := s.validator.ValidatePath(filepath.Dir(absPath)); err != nil {
-				return nil, err
-			}
-		} else {
-			return nil, err
-		}
+		return nil, err
 	}
 
 	file, err := os.OpenFile(absPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
@@ -267,17 +426,17 @@ func (s *Server) deleteFileTool() *mcp.Tool {
 			},
 			[]string{"path"},
 		),
-		Handler: s.handleDeleteFile,
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleDeleteFile),
 	}
 }
 
-func (s *Server) handleDeleteFile(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	path, err := mcp.GetStringParam(params, "path", true)
-	if err != nil {
-		return nil, err
-	}
+type pathArgs struct {
+	Path string `json:"path" validate:"required"`
+}
 
-	absPath, err := s.validator.ResolvePath(path)
+func (s *Server) handleDeleteFile(ctx context.Context, args pathArgs) (*mcp.ToolResult, error) {
+	absPath, err := s.validator.ResolvePath(args.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -309,32 +468,28 @@ func (s *Server) moveFileTool() *mcp.Tool {
 			},
 			[]string{"source", "destination"},
 		),
-		Handler: s.handleMoveFile,
+		Annotations: mcp.Destructive(),
+		Handler:     mcp.TypedHandler(s.handleMoveFile),
 	}
 }
 
-func (s *Server) handleMoveFile(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	source, err := mcp.GetStringParam(params, "source", true)
-	if err != nil {
-		return nil, err
-	}
-
-	destination, err := mcp.GetStringParam(params, "destination", true)
-	if err != nil {
-		return nil, err
-	}
+type sourceDestinationArgs struct {
+	Source      string `json:"source" validate:"required"`
+	Destination string `json:"destination" validate:"required"`
+}
 
-	srcPath, err := s.validator.ResolvePath(source)
+func (s *Server) handleMoveFile(ctx context.Context, args sourceDestinationArgs) (*mcp.ToolResult, error) {
+	srcPath, err := s.validator.ResolvePath(args.Source)
 	if err != nil {
 		return nil, err
 	}
 
-	dstPath, err := filepath.Abs(destination)
+	dstPath, err := filepath.Abs(args.Destination)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.validator.ValidatePath(filepath.Dir(dstPath)); err != nil {
+	if err := s.validator.ValidatePath(dstPath); err != nil {
 		return nil, err
 	}
 
@@ -360,32 +515,23 @@ func (s *Server) copyFileTool() *mcp.Tool {
 			},
 			[]string{"source", "destination"},
 		),
-		Handler: s.handleCopyFile,
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleCopyFile),
 	}
 }
 
-func (s *Server) handleCopyFile(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	source, err := mcp.GetStringParam(params, "source", true)
+func (s *Server) handleCopyFile(ctx context.Context, args sourceDestinationArgs) (*mcp.ToolResult, error) {
+	srcPath, err := s.validator.ResolvePath(args.Source)
 	if err != nil {
 		return nil, err
 	}
 
-	destination, err := mcp.GetStringParam(params, "destination", true)
+	dstPath, err := filepath.Abs(args.Destination)
 	if err != nil {
 		return nil, err
 	}
 
-	srcPath, err := s.validator.ResolvePath(source)
-	if err != nil {
-		return nil, err
-	}
-
-	dstPath, err := filepath.Abs(destination)
-	if err != nil {
-		return nil, err
-	}
-
-	if err := s.validator.ValidatePath(filepath.Dir(dstPath)); err != nil {
+	if err := s.validator.ValidatePath(dstPath); err != nil {
 		return nil, err
 	}
 
@@ -430,18 +576,33 @@ func (s *Server) listDirectoryTool() *mcp.Tool {
 			},
 			[]string{"path"},
 		),
-		Handler: s.handleListDirectory,
+		OutputSchema: mcp.BuildOutputSchema(
+			map[string]interface{}{
+				"path": mcp.StringProperty("Absolute path of the directory"),
+				"entries": mcp.ObjectArrayProperty("Directory entries", map[string]interface{}{
+					"name":         mcp.StringProperty("File name"),
+					"path":         mcp.StringProperty("Absolute path"),
+					"is_directory": mcp.BoolProperty("Whether the entry is a directory"),
+					"size_bytes":   mcp.IntProperty("Size in bytes"),
+				}),
+				"count":     mcp.IntProperty("Number of entries"),
+				"truncated": mcp.BoolProperty("Whether a recursive listing stopped early at the walk limits"),
+			},
+			[]string{"path", "entries", "count"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleListDirectory),
 	}
 }
 
-func (s *Server) handleListDirectory(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	path, err := mcp.GetStringParam(params, "path", true)
-	if err != nil {
-		return nil, err
-	}
+type listDirectoryArgs struct {
+	Path          string `json:"path" validate:"required"`
+	Recursive     bool   `json:"recursive"`
+	IncludeHidden bool   `json:"include_hidden"`
+}
 
-	recursive, _ := mcp.GetBoolParam(params, "recursive", false)
-	includeHidden, _ := mcp.GetBoolParam(params, "include_hidden", false)
+func (s *Server) handleListDirectory(ctx context.Context, args listDirectoryArgs) (*mcp.ToolResult, error) {
+	path, recursive, includeHidden := args.Path, args.Recursive, args.IncludeHidden
 
 	absPath, err := s.validator.ResolvePath(path)
 	if err != nil {
@@ -457,7 +618,8 @@ func (s *Server) handleListDirectory(ctx context.Context, params map[string]inte
 		return nil, fmt.Errorf("%w: %s", common.ErrNotADirectory, path)
 	}
 
-	var entries []DirectoryEntry
+	entries := []DirectoryEntry{}
+	budget := s.newWalkBudget(ctx)
 
 	if recursive {
 		err = filepath.Walk(absPath, func(p string, info os.FileInfo, err error) error {
@@ -467,6 +629,9 @@ func (s *Server) handleListDirectory(ctx context.Context, params map[string]inte
 			if p == absPath {
 				return nil
 			}
+			if err := budget.spend(); err != nil {
+				return err
+			}
 
 			name := info.Name()
 			if !includeHidden && strings.HasPrefix(name, ".") {
@@ -515,9 +680,10 @@ func (s *Server) handleListDirectory(ctx context.Context, params map[string]inte
 	}
 
 	return mcp.JSONResult(map[string]interface{}{
-		"path":    absPath,
-		"entries": entries,
-		"count":   len(entries),
+		"path":      absPath,
+		"entries":   entries,
+		"count":     len(entries),
+		"truncated": budget.truncated,
 	})
 }
 
@@ -531,22 +697,18 @@ func (s *Server) createDirectoryTool() *mcp.Tool {
 			},
 			[]string{"path"},
 		),
-		Handler: s.handleCreateDirectory,
+		Annotations: mcp.Additive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleCreateDirectory),
 	}
 }
 
-func (s *Server) handleCreateDirectory(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	path, err := mcp.GetStringParam(params, "path", true)
-	if err != nil {
-		return nil, err
-	}
-
-	absPath, err := filepath.Abs(path)
+func (s *Server) handleCreateDirectory(ctx context.Context, args pathArgs) (*mcp.ToolResult, error) {
+	absPath, err := filepath.Abs(args.Path)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := s.validator.ValidatePath(filepath.Dir(absPath)); err != nil {
+	if err := s.validator.ValidatePath(absPath); err != nil {
 		return nil, err
 	}
 
@@ -568,17 +730,18 @@ func (s *Server) deleteDirectoryTool() *mcp.Tool {
 			},
 			[]string{"path"},
 		),
-		Handler: s.handleDeleteDirectory,
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleDeleteDirectory),
 	}
 }
 
-func (s *Server) handleDeleteDirectory(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	path, err := mcp.GetStringParam(params, "path", true)
-	if err != nil {
-		return nil, err
-	}
+type deleteDirectoryArgs struct {
+	Path      string `json:"path" validate:"required"`
+	Recursive bool   `json:"recursive"`
+}
 
-	recursive, _ := mcp.GetBoolParam(params, "recursive", false)
+func (s *Server) handleDeleteDirectory(ctx context.Context, args deleteDirectoryArgs) (*mcp.ToolResult, error) {
+	path, recursive := args.Path, args.Recursive
 
 	absPath, err := s.validator.ResolvePath(path)
 	if err != nil {
@@ -595,6 +758,9 @@ func (s *Server) handleDeleteDirectory(ctx context.Context, params map[string]in
 	}
 
 	if recursive {
+		if err := common.Confirm(ctx, fmt.Sprintf("Delete the directory %s and everything in it?", absPath)); err != nil {
+			return nil, err
+		}
 		if err := os.RemoveAll(absPath); err != nil {
 			return nil, err
 		}
@@ -610,24 +776,36 @@ func (s *Server) handleDeleteDirectory(ctx context.Context, params map[string]in
 func (s *Server) fileInfoTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "file_info",
-		Description: "Get file metadata (size, permissions, timestamps)",
+		Description: "Get file metadata (size, permissions, timestamps, extended attributes)",
 		InputSchema: mcp.BuildInputSchema(
 			map[string]interface{}{
 				"path": mcp.StringProperty("Absolute path to file"),
 			},
 			[]string{"path"},
 		),
-		Handler: s.handleFileInfo,
+		OutputSchema: mcp.BuildOutputSchema(
+			map[string]interface{}{
+				"name":           mcp.StringProperty("File name"),
+				"path":           mcp.StringProperty("Absolute path"),
+				"size_bytes":     mcp.IntProperty("Size in bytes"),
+				"permissions":    mcp.StringProperty("Permission bits in octal"),
+				"is_directory":   mcp.BoolProperty("Whether the path is a directory"),
+				"is_symlink":     mcp.BoolProperty("Whether the path is a symbolic link"),
+				"modified_at":    mcp.StringProperty("Modification time (RFC 3339)"),
+				"symlink_target": mcp.StringProperty("For a symbolic link, the target as stored in the link"),
+				"resolved_path":  mcp.StringProperty("For a symbolic link, the absolute path it resolves to, if that exists and is allowed"),
+				"xattrs":         mcp.MapProperty("Extended attributes; binary values are base64 after a \"base64:\" prefix"),
+				"quarantined":    mcp.BoolProperty("Whether macOS has quarantined the file (com.apple.quarantine), so Gatekeeper checks or blocks it before it runs"),
+			},
+			[]string{"name", "path", "size_bytes", "permissions", "is_directory", "is_symlink", "modified_at"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleFileInfo),
 	}
 }
 
-func (s *Server) handleFileInfo(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	path, err := mcp.GetStringParam(params, "path", true)
-	if err != nil {
-		return nil, err
-	}
-
-	absPath, err := s.validator.ResolvePath(path)
+func (s *Server) handleFileInfo(ctx context.Context, args pathArgs) (*mcp.ToolResult, error) {
+	absPath, err := s.validator.ResolvePath(args.Path)
 	if err != nil {
 		return nil, err
 	}
@@ -646,6 +824,12 @@ func (s *Server) handleFileInfo(ctx context.Context, params map[string]interface
 		IsSymlink:   info.Mode()&os.ModeSymlink != 0,
 		ModifiedAt:  info.ModTime(),
 	}
+	if fileInfo.IsSymlink {
+		fileInfo.SymlinkTarget, fileInfo.ResolvedPath = s.readLink(absPath)
+	} else {
+		fileInfo.Xattrs = fileXattrs(absPath)
+		fileInfo.Quarantined = isQuarantined(fileInfo.Xattrs)
+	}
 
 	return mcp.JSONResult(fileInfo)
 }
@@ -653,44 +837,62 @@ func (s *Server) handleFileInfo(ctx context.Context, params map[string]interface
 func (s *Server) searchFilesTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "search_files",
-		Description: "Search for files by name pattern",
+		Description: "Search for files by name pattern. Skips what .gitignore files and the default excludes (.git, node_modules, build output) ignore. A search that reaches the walk limits returns what it found with truncated set",
 		InputSchema: mcp.BuildInputSchema(
 			map[string]interface{}{
-				"directory": mcp.StringProperty("Directory to search in"),
-				"pattern":   mcp.StringProperty("Glob pattern to match"),
-				"max_depth": mcp.IntProperty("Maximum depth to search"),
+				"directory":       mcp.StringProperty("Directory to search in"),
+				"pattern":         mcp.StringProperty("Glob pattern to match"),
+				"max_depth":       mcp.IntProperty("Maximum depth to search"),
+				"exclude":         mcp.ArrayProperty("string", "Extra patterns to skip, in .gitignore syntax, e.g. *.min.js or docs/"),
+				"include_ignored": mcp.BoolProperty("Also search ignored files and directories"),
 			},
 			[]string{"directory", "pattern"},
 		),
-		Handler: s.handleSearchFiles,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleSearchFiles),
 	}
 }
 
-func (s *Server) handleSearchFiles(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	directory, err := mcp.GetStringParam(params, "directory", true)
-	if err != nil {
-		return nil, err
-	}
+type searchFilesArgs struct {
+	Directory      string   `json:"directory" validate:"required"`
+	Pattern        string   `json:"pattern" validate:"required"`
+	MaxDepth       int      `json:"max_depth" default:"10"`
+	Exclude        []string `json:"exclude"`
+	IncludeIgnored bool     `json:"include_ignored"`
+}
+
+func (s *Server) handleSearchFiles(ctx context.Context, args searchFilesArgs) (*mcp.ToolResult, error) {
+	pattern, maxDepth := args.Pattern, args.MaxDepth
 
-	pattern, err := mcp.GetStringParam(params, "pattern", true)
+	absDir, err := s.validator.ResolvePath(args.Directory)
 	if err != nil {
 		return nil, err
 	}
 
-	maxDepth, _ := mcp.GetIntParam(params, "max_depth", false, 10)
-
-	absDir, err := s.validator.ResolvePath(directory)
+	ignore, err := s.newIgnoreMatcher(absDir, args.Exclude, args.IncludeIgnored)
 	if err != nil {
 		return nil, err
 	}
 
 	var matches []string
 	baseDepth := strings.Count(absDir, string(os.PathSeparator))
+	budget := s.newWalkBudget(ctx)
+	truncated := false
 
 	err = filepath.Walk(absDir, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return nil
 		}
+		if err := budget.spend(); err != nil {
+			return err
+		}
+
+		if ignore.ignored(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
 
 		currentDepth := strings.Count(path, string(os.PathSeparator)) - baseDepth
 		if currentDepth > maxDepth {
@@ -710,6 +912,7 @@ func (s *Server) handleSearchFiles(ctx context.Context, params map[string]interf
 		}
 
 		if len(matches) >= 1000 {
+			truncated = true
 			return filepath.SkipAll
 		}
 
@@ -725,46 +928,63 @@ func (s *Server) handleSearchFiles(ctx context.Context, params map[string]interf
 		"pattern":   pattern,
 		"matches":   matches,
 		"count":     len(matches),
+		"truncated": truncated || budget.truncated,
 	})
 }
 
 func (s *Server) grepTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "grep",
-		Description: "Search for content within files",
+		Description: "Search for content within files. Skips what .gitignore files and the default excludes (.git, node_modules, build output) ignore. A search that reaches the walk limits returns what it found with truncated set",
 		InputSchema: mcp.BuildInputSchema(
 			map[string]interface{}{
-				"directory":      mcp.StringProperty("Directory to search in"),
-				"pattern":        mcp.StringProperty("Regex pattern to search"),
-				"file_pattern":   mcp.StringProperty("File name pattern filter"),
-				"case_sensitive": mcp.BoolProperty("Case sensitive search"),
+				"directory":       mcp.StringProperty("Directory to search in"),
+				"pattern":         mcp.StringProperty("Regex pattern to search"),
+				"file_pattern":    mcp.StringProperty("File name pattern filter"),
+				"case_sensitive":  mcp.BoolProperty("Case sensitive search"),
+				"exclude":         mcp.ArrayProperty("string", "Extra patterns to skip, in .gitignore syntax, e.g. *.min.js or docs/"),
+				"include_ignored": mcp.BoolProperty("Also search ignored files and directories"),
+				"context":         mcp.IntProperty("Lines of context to show before and after each match (default: 0, max: 50)"),
+				"before_context":  mcp.IntProperty("Lines of context before each match; overrides context"),
+				"after_context":   mcp.IntProperty("Lines of context after each match; overrides context"),
+				"max_per_file":    mcp.IntProperty("Most matches to report from one file (default: no limit)"),
+				"max_matches":     mcp.IntProperty("Most matches to report in all (default: 500, max: 10000)"),
 			},
 			[]string{"directory", "pattern"},
 		),
-		Handler: s.handleGrep,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGrep),
 	}
 }
 
-func (s *Server) handleGrep(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	directory, err := mcp.GetStringParam(params, "directory", true)
-	if err != nil {
-		return nil, err
-	}
+type grepArgs struct {
+	Directory      string   `json:"directory" validate:"required"`
+	Pattern        string   `json:"pattern" validate:"required"`
+	FilePattern    string   `json:"file_pattern"`
+	CaseSensitive  bool     `json:"case_sensitive" default:"true"`
+	Exclude        []string `json:"exclude"`
+	IncludeIgnored bool     `json:"include_ignored"`
+	Context        int      `json:"context"`
+	BeforeContext  *int     `json:"before_context"`
+	AfterContext   *int     `json:"after_context"`
+	MaxPerFile     int      `json:"max_per_file"`
+	MaxMatches     int      `json:"max_matches" default:"500"`
+}
+
+func (s *Server) handleGrep(ctx context.Context, args grepArgs) (*mcp.ToolResult, error) {
+	pattern, filePattern := args.Pattern, args.FilePattern
 
-	pattern, err := mcp.GetStringParam(params, "pattern", true)
+	absDir, err := s.validator.ResolvePath(args.Directory)
 	if err != nil {
 		return nil, err
 	}
 
-	filePattern, _ := mcp.GetStringParam(params, "file_pattern", false)
-	caseSensitive, _ := mcp.GetBoolParam(params, "case_sensitive", true)
-
-	absDir, err := s.validator.ResolvePath(directory)
+	ignore, err := s.newIgnoreMatcher(absDir, args.Exclude, args.IncludeIgnored)
 	if err != nil {
 		return nil, err
 	}
 
-	if !caseSensitive {
+	if !args.CaseSensitive {
 		pattern = "(?i)" + pattern
 	}
 
@@ -773,11 +993,27 @@ func (s *Server) handleGrep(ctx context.Context, params map[string]interface{})
 		return nil, fmt.Errorf("invalid regex pattern: %w", err)
 	}
 
+	opts, err := getGrepOptions(args)
+	if err != nil {
+		return nil, err
+	}
 	var matches []GrepMatch
-	maxMatches := 500
+	budget := s.newWalkBudget(ctx)
 
 	err = filepath.Walk(absDir, func(path string, info os.FileInfo, err error) error {
-		if err != nil || info.IsDir() {
+		if err != nil {
+			return nil
+		}
+		if err := budget.spend(); err != nil {
+			return err
+		}
+		if ignore.ignored(path, info.IsDir()) {
+			if info.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.IsDir() {
 			return nil
 		}
 
@@ -798,26 +1034,10 @@ func (s *Server) handleGrep(ctx context.Context, params map[string]interface{})
 		}
 		defer file.Close()
 
-		scanner := bufio.NewScanner(file)
-		lineNum := 0
-
-		for scanner.Scan() {
-			lineNum++
-			line := scanner.Text()
-
-			if re.MatchString(line) {
-				matches = append(matches, GrepMatch{
-					File:       path,
-					LineNumber: lineNum,
-					Line:       line,
-				})
-
-				if len(matches) >= maxMatches {
-					return filepath.SkipAll
-				}
-			}
+		matches = append(matches, grepFile(file, path, re, opts, opts.maxMatches-len(matches))...)
+		if len(matches) >= opts.maxMatches {
+			return filepath.SkipAll
 		}
-
 		return nil
 	})
 
@@ -830,6 +1050,6 @@ func (s *Server) handleGrep(ctx context.Context, params map[string]interface{})
 		"pattern":   pattern,
 		"matches":   matches,
 		"count":     len(matches),
-		"truncated": len(matches) >= maxMatches,
+		"truncated": len(matches) >= opts.maxMatches || budget.truncated,
 	})
 }
@@ -2,22 +2,31 @@ package filesystem
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
 )
 
 func newTestServer(t *testing.T, tempDir string) *Server {
 	cfg := &config.FilesystemConfig{
-		AllowedPaths:   []string{tempDir},
-		DeniedPaths:    []string{},
-		MaxFileSizeMB:  10,
-		FollowSymlinks: true,
+		AllowedPaths:     []string{tempDir},
+		DeniedPaths:      []string{},
+		MaxFileSizeMB:    10,
+		FollowSymlinks:   true,
+		MaxArchiveSizeMB: 10,
 	}
 	return NewServer(cfg)
 }
@@ -31,7 +40,7 @@ func TestReadFile(t *testing.T) {
 	require.NoError(t, os.WriteFile(testFile, []byte(content), 0644))
 
 	t.Run("read existing file", func(t *testing.T) {
-		result, err := server.handleReadFile(context.Background(), map[string]interface{}{
+		result, err := server.readFileTool().Handler(context.Background(), map[string]interface{}{
 			"path": testFile,
 		})
 		require.NoError(t, err)
@@ -39,14 +48,151 @@ func TestReadFile(t *testing.T) {
 	})
 
 	t.Run("read nonexistent file", func(t *testing.T) {
-		_, err := server.handleReadFile(context.Background(), map[string]interface{}{
+		_, err := server.readFileTool().Handler(context.Background(), map[string]interface{}{
 			"path": filepath.Join(tempDir, "nonexistent.txt"),
 		})
 		assert.Error(t, err)
 	})
 
 	t.Run("missing path parameter", func(t *testing.T) {
-		_, err := server.handleReadFile(context.Background(), map[string]interface{}{})
+		_, err := server.readFileTool().Handler(context.Background(), map[string]interface{}{})
+		assert.Error(t, err)
+	})
+
+	t.Run("binary file", func(t *testing.T) {
+		binFile := filepath.Join(tempDir, "data.bin")
+		require.NoError(t, os.WriteFile(binFile, []byte{0xff, 0x00, 0xfe}, 0644))
+		result, err := server.readFileTool().Handler(context.Background(), map[string]interface{}{"path": binFile})
+		require.NoError(t, err)
+		require.Equal(t, "resource", result.Content[0].Type)
+		assert.Equal(t, "/wD+", result.Content[0].Resource.Blob)
+		assert.Equal(t, fileURI(binFile), result.Content[0].Resource.URI)
+	})
+
+	t.Run("as resource link", func(t *testing.T) {
+		result, err := server.readFileTool().Handler(context.Background(), map[string]interface{}{
+			"path":        testFile,
+			"as_resource": true,
+		})
+		require.NoError(t, err)
+		link := result.Content[0]
+		assert.Equal(t, "resource_link", link.Type)
+		assert.Equal(t, fileURI(testFile), link.URI)
+		assert.Equal(t, int64(len(content)), link.Size)
+		assert.Empty(t, link.Text)
+	})
+
+	t.Run("byte range", func(t *testing.T) {
+		result, err := server.readFileTool().Handler(context.Background(), map[string]interface{}{
+			"path": testFile, "offset": 7, "length": 5,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "World", result.Content[0].Text)
+
+		result, err = server.readFileTool().Handler(context.Background(), map[string]interface{}{
+			"path": testFile, "offset": -6,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "World!", result.Content[0].Text)
+
+		_, err = server.readFileTool().Handler(context.Background(), map[string]interface{}{
+			"path": testFile, "offset": 100,
+		})
+		assert.ErrorIs(t, err, common.ErrInvalidInput)
+	})
+
+	t.Run("byte range splitting a character", func(t *testing.T) {
+		utf8File := filepath.Join(tempDir, "utf8.txt")
+		require.NoError(t, os.WriteFile(utf8File, []byte("héllo wörld"), 0644))
+		result, err := server.readFileTool().Handler(context.Background(), map[string]interface{}{
+			"path": utf8File, "offset": 2, "length": 7,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "llo w", result.Content[0].Text)
+	})
+
+	t.Run("head", func(t *testing.T) {
+		linesFile := filepath.Join(tempDir, "lines.txt")
+		require.NoError(t, os.WriteFile(linesFile, []byte("one\ntwo\nthree\n"), 0644))
+		result, err := server.readFileTool().Handler(context.Background(), map[string]interface{}{
+			"path": linesFile, "head": 2,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "one\ntwo\n", result.Content[0].Text)
+	})
+
+	t.Run("parts of a file over the size limit", func(t *testing.T) {
+		small := NewServer(&config.FilesystemConfig{AllowedPaths: []string{tempDir}, MaxFileSizeMB: 1})
+		bigFile := filepath.Join(tempDir, "big.log")
+		require.NoError(t, os.WriteFile(bigFile, []byte("start\n"+strings.Repeat("x", 2*1024*1024)+"\nend"), 0644))
+
+		_, err := small.readFileTool().Handler(context.Background(), map[string]interface{}{"path": bigFile})
+		assert.ErrorIs(t, err, common.ErrFileTooLarge)
+
+		result, err := small.readFileTool().Handler(context.Background(), map[string]interface{}{"path": bigFile, "head": 1})
+		require.NoError(t, err)
+		assert.Equal(t, "start\n", result.Content[0].Text)
+
+		result, err = small.readFileTool().Handler(context.Background(), map[string]interface{}{"path": bigFile, "offset": -4})
+		require.NoError(t, err)
+		assert.Equal(t, "\nend", result.Content[0].Text)
+
+		_, err = small.readFileTool().Handler(context.Background(), map[string]interface{}{"path": bigFile, "offset": 0})
+		assert.ErrorIs(t, err, common.ErrFileTooLarge)
+	})
+}
+
+func TestReadFileLines(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+
+	testFile := filepath.Join(tempDir, "lines.txt")
+	require.NoError(t, os.WriteFile(testFile, []byte("one\ntwo\nthree\nfour\nfive"), 0644))
+
+	readLines := func(params map[string]interface{}) map[string]interface{} {
+		params["path"] = testFile
+		result, err := server.readFileLinesTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out
+	}
+
+	t.Run("range", func(t *testing.T) {
+		out := readLines(map[string]interface{}{"start_line": 2, "end_line": 3})
+		assert.Equal(t, "two\nthree", out["content"])
+		assert.EqualValues(t, 5, out["total_lines"])
+		assert.Equal(t, true, out["has_more"])
+		assert.EqualValues(t, 4, out["next_start_line"])
+	})
+
+	t.Run("to the end", func(t *testing.T) {
+		out := readLines(map[string]interface{}{"start_line": 4})
+		assert.Equal(t, "four\nfive", out["content"])
+		assert.EqualValues(t, 5, out["end_line"])
+		assert.Equal(t, false, out["has_more"])
+		assert.Nil(t, out["next_start_line"])
+	})
+
+	t.Run("negative", func(t *testing.T) {
+		out := readLines(map[string]interface{}{"start_line": -2})
+		assert.Equal(t, "four\nfive", out["content"])
+		assert.EqualValues(t, 4, out["start_line"])
+
+		out = readLines(map[string]interface{}{"start_line": -10, "end_line": -4})
+		assert.Equal(t, "one\ntwo", out["content"])
+	})
+
+	t.Run("past the end", func(t *testing.T) {
+		out := readLines(map[string]interface{}{"start_line": 9, "end_line": 12})
+		assert.Equal(t, "", out["content"])
+		assert.Equal(t, false, out["has_more"])
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		_, err := server.readFileLinesTool().Handler(context.Background(), map[string]interface{}{
+			"path": testFile, "start_line": 3, "end_line": 2,
+		})
 		assert.Error(t, err)
 	})
 }
@@ -59,7 +205,7 @@ func TestWriteFile(t *testing.T) {
 		testFile := filepath.Join(tempDir, "new.txt")
 		content := "New content"
 
-		result, err := server.handleWriteFile(context.Background(), map[string]interface{}{
+		result, err := server.writeFileTool().Handler(context.Background(), map[string]interface{}{
 			"path":    testFile,
 			"content": content,
 		})
@@ -76,7 +222,7 @@ func TestWriteFile(t *testing.T) {
 		require.NoError(t, os.WriteFile(testFile, []byte("old"), 0644))
 
 		newContent := "new content"
-		_, err := server.handleWriteFile(context.Background(), map[string]interface{}{
+		_, err := server.writeFileTool().Handler(context.Background(), map[string]interface{}{
 			"path":    testFile,
 			"content": newContent,
 		})
@@ -91,7 +237,7 @@ func TestWriteFile(t *testing.T) {
 		testFile := filepath.Join(tempDir, "subdir", "deep", "file.txt")
 		content := "nested content"
 
-		_, err := server.handleWriteFile(context.Background(), map[string]interface{}{
+		_, err := server.writeFileTool().Handler(context.Background(), map[string]interface{}{
 			"path":    testFile,
 			"content": content,
 		})
@@ -113,7 +259,7 @@ func TestListDirectory(t *testing.T) {
 	require.NoError(t, os.Mkdir(filepath.Join(tempDir, "subdir"), 0755))
 
 	t.Run("list directory without hidden", func(t *testing.T) {
-		result, err := server.handleListDirectory(context.Background(), map[string]interface{}{
+		result, err := server.listDirectoryTool().Handler(context.Background(), map[string]interface{}{
 			"path":           tempDir,
 			"include_hidden": false,
 		})
@@ -125,7 +271,7 @@ func TestListDirectory(t *testing.T) {
 	})
 
 	t.Run("list directory with hidden", func(t *testing.T) {
-		result, err := server.handleListDirectory(context.Background(), map[string]interface{}{
+		result, err := server.listDirectoryTool().Handler(context.Background(), map[string]interface{}{
 			"path":           tempDir,
 			"include_hidden": true,
 		})
@@ -140,7 +286,7 @@ func TestCreateDirectory(t *testing.T) {
 
 	t.Run("create new directory", func(t *testing.T) {
 		newDir := filepath.Join(tempDir, "newdir")
-		_, err := server.handleCreateDirectory(context.Background(), map[string]interface{}{
+		_, err := server.createDirectoryTool().Handler(context.Background(), map[string]interface{}{
 			"path": newDir,
 		})
 		require.NoError(t, err)
@@ -152,7 +298,7 @@ func TestCreateDirectory(t *testing.T) {
 
 	t.Run("create nested directories", func(t *testing.T) {
 		nestedDir := filepath.Join(tempDir, "a", "b", "c")
-		_, err := server.handleCreateDirectory(context.Background(), map[string]interface{}{
+		_, err := server.createDirectoryTool().Handler(context.Background(), map[string]interface{}{
 			"path": nestedDir,
 		})
 		require.NoError(t, err)
@@ -171,7 +317,7 @@ func TestDeleteFile(t *testing.T) {
 		testFile := filepath.Join(tempDir, "todelete.txt")
 		require.NoError(t, os.WriteFile(testFile, []byte("delete me"), 0644))
 
-		_, err := server.handleDeleteFile(context.Background(), map[string]interface{}{
+		_, err := server.deleteFileTool().Handler(context.Background(), map[string]interface{}{
 			"path": testFile,
 		})
 		require.NoError(t, err)
@@ -181,7 +327,7 @@ func TestDeleteFile(t *testing.T) {
 	})
 
 	t.Run("delete nonexistent file", func(t *testing.T) {
-		_, err := server.handleDeleteFile(context.Background(), map[string]interface{}{
+		_, err := server.deleteFileTool().Handler(context.Background(), map[string]interface{}{
 			"path": filepath.Join(tempDir, "nonexistent.txt"),
 		})
 		assert.Error(t, err)
@@ -195,7 +341,7 @@ func TestFileInfo(t *testing.T) {
 	testFile := filepath.Join(tempDir, "info.txt")
 	require.NoError(t, os.WriteFile(testFile, []byte("test content"), 0644))
 
-	result, err := server.handleFileInfo(context.Background(), map[string]interface{}{
+	result, err := server.fileInfoTool().Handler(context.Background(), map[string]interface{}{
 		"path": testFile,
 	})
 	require.NoError(t, err)
@@ -213,7 +359,7 @@ func TestCopyFile(t *testing.T) {
 
 	require.NoError(t, os.WriteFile(srcFile, []byte(content), 0644))
 
-	_, err := server.handleCopyFile(context.Background(), map[string]interface{}{
+	_, err := server.copyFileTool().Handler(context.Background(), map[string]interface{}{
 		"source":      srcFile,
 		"destination": dstFile,
 	})
@@ -237,7 +383,7 @@ func TestMoveFile(t *testing.T) {
 
 	require.NoError(t, os.WriteFile(srcFile, []byte(content), 0644))
 
-	_, err := server.handleMoveFile(context.Background(), map[string]interface{}{
+	_, err := server.moveFileTool().Handler(context.Background(), map[string]interface{}{
 		"source":      srcFile,
 		"destination": dstFile,
 	})
@@ -250,3 +396,257 @@ func TestMoveFile(t *testing.T) {
 	_, err = os.Stat(srcFile)
 	assert.True(t, os.IsNotExist(err))
 }
+
+func TestWritesThroughSymlink(t *testing.T) {
+	root := t.TempDir()
+	allowed := filepath.Join(root, "fs")
+	require.NoError(t, os.Mkdir(allowed, 0755))
+	outside := filepath.Join(root, "outside.txt")
+	link := filepath.Join(allowed, "out")
+	require.NoError(t, os.Symlink(outside, link))
+	src := filepath.Join(allowed, "src.txt")
+
+	for _, follow := range []bool{false, true} {
+		server := NewServer(&config.FilesystemConfig{
+			AllowedPaths:   []string{allowed},
+			MaxFileSizeMB:  10,
+			FollowSymlinks: follow,
+		})
+		calls := map[string]func() error{
+			"write_file": func() error {
+				_, err := server.writeFileTool().Handler(context.Background(), map[string]interface{}{"path": link, "content": "pwned"})
+				return err
+			},
+			"append_file": func() error {
+				_, err := server.appendFileTool().Handler(context.Background(), map[string]interface{}{"path": link, "content": "pwned"})
+				return err
+			},
+			"copy_file": func() error {
+				_, err := server.copyFileTool().Handler(context.Background(), map[string]interface{}{"source": src, "destination": link})
+				return err
+			},
+			"move_file": func() error {
+				_, err := server.moveFileTool().Handler(context.Background(), map[string]interface{}{"source": src, "destination": link})
+				return err
+			},
+			"batch": func() error {
+				result, err := server.batchTool().Handler(context.Background(), map[string]interface{}{
+					"operations": []interface{}{map[string]interface{}{"op": "write", "path": link, "content": "pwned"}},
+				})
+				if err != nil {
+					return err
+				}
+				var out struct{ Results []BatchResult }
+				require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+				return errors.New(out.Results[0].Error)
+			},
+		}
+		for name, call := range calls {
+			t.Run(fmt.Sprintf("%s follow_symlinks=%v", name, follow), func(t *testing.T) {
+				require.NoError(t, os.WriteFile(outside, []byte("original"), 0644))
+				require.NoError(t, os.WriteFile(src, []byte("pwned"), 0644))
+
+				assert.ErrorContains(t, call(), common.ErrPathNotAllowed.Error())
+
+				data, err := os.ReadFile(outside)
+				require.NoError(t, err)
+				assert.Equal(t, "original", string(data))
+			})
+		}
+	}
+}
+
+func TestDeniedGlobOnWrites(t *testing.T) {
+	tempDir := t.TempDir()
+	server := NewServer(&config.FilesystemConfig{
+		AllowedPaths:   []string{tempDir},
+		DeniedPaths:    []string{"**/.env", "*.pem"},
+		MaxFileSizeMB:  10,
+		FollowSymlinks: true,
+	})
+	src := filepath.Join(tempDir, "src.txt")
+	require.NoError(t, os.WriteFile(src, []byte("data"), 0644))
+
+	writes := map[string]func(path string) error{
+		"write_file": func(path string) error {
+			_, err := server.writeFileTool().Handler(context.Background(), map[string]interface{}{"path": path, "content": "x"})
+			return err
+		},
+		"append_file": func(path string) error {
+			_, err := server.appendFileTool().Handler(context.Background(), map[string]interface{}{"path": path, "content": "x"})
+			return err
+		},
+		"copy_file": func(path string) error {
+			_, err := server.copyFileTool().Handler(context.Background(), map[string]interface{}{"source": src, "destination": path})
+			return err
+		},
+		"move_file": func(path string) error {
+			_, err := server.moveFileTool().Handler(context.Background(), map[string]interface{}{"source": src, "destination": path})
+			return err
+		},
+		"create_directory": func(path string) error {
+			_, err := server.createDirectoryTool().Handler(context.Background(), map[string]interface{}{"path": path})
+			return err
+		},
+	}
+	for name, write := range writes {
+		for _, path := range []string{filepath.Join(tempDir, ".env"), filepath.Join(tempDir, "sub", "key.pem")} {
+			t.Run(name+" "+filepath.Base(path), func(t *testing.T) {
+				assert.ErrorIs(t, write(path), common.ErrPathNotAllowed)
+				assert.NoFileExists(t, path)
+			})
+		}
+	}
+
+	t.Run("copy_directory", func(t *testing.T) {
+		dir := filepath.Join(tempDir, "project")
+		require.NoError(t, os.Mkdir(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main"), 0644))
+		_, err := server.copyDirectoryTool().Handler(context.Background(), map[string]interface{}{
+			"source": dir, "destination": filepath.Join(tempDir, "copy"),
+		})
+		require.NoError(t, err)
+		assert.FileExists(t, filepath.Join(tempDir, "copy", "main.go"))
+
+		// The source is allowed; only the destination name is denied.
+		server.validator.DeniedPaths = append(server.validator.DeniedPaths, filepath.Join(tempDir, "copy2", "main.go"))
+		_, err = server.copyDirectoryTool().Handler(context.Background(), map[string]interface{}{
+			"source": dir, "destination": filepath.Join(tempDir, "copy2"),
+		})
+		require.NoError(t, err)
+		assert.NoFileExists(t, filepath.Join(tempDir, "copy2", "main.go"))
+	})
+}
+
+func TestResources(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "notes.txt"), []byte("hello"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "image.bin"), []byte{0xff, 0x00, 0xfe}, 0644))
+	require.NoError(t, os.Mkdir(filepath.Join(tempDir, ".git"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, ".git", "HEAD"), []byte("ref"), 0644))
+
+	source := server.Resources()
+	resources, err := source.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, resources, 2, "hidden directories are not listed")
+
+	t.Run("read text file", func(t *testing.T) {
+		contents, err := source.Read(context.Background(), fileURI(filepath.Join(tempDir, "notes.txt")))
+		require.NoError(t, err)
+		assert.Equal(t, "hello", contents.Text)
+		assert.Equal(t, "text/plain; charset=utf-8", contents.MimeType)
+	})
+
+	t.Run("read binary file", func(t *testing.T) {
+		contents, err := source.Read(context.Background(), fileURI(filepath.Join(tempDir, "image.bin")))
+		require.NoError(t, err)
+		assert.Equal(t, "/wD+", contents.Blob)
+	})
+
+	t.Run("outside allowed paths", func(t *testing.T) {
+		_, err := source.Read(context.Background(), "file:///etc/passwd")
+		assert.ErrorIs(t, err, mcp.ErrResourceNotFound)
+		assert.True(t, common.IsPathNotAllowed(err))
+	})
+
+	t.Run("missing file", func(t *testing.T) {
+		_, err := source.Read(context.Background(), fileURI(filepath.Join(tempDir, "missing.txt")))
+		assert.ErrorIs(t, err, mcp.ErrResourceNotFound)
+	})
+}
+
+func TestListDirectoryStructuredContent(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "a.txt"), []byte("a"), 0644))
+
+	result, err := server.listDirectoryTool().Handler(context.Background(), map[string]interface{}{"path": tempDir})
+	require.NoError(t, err)
+
+	data, err := json.Marshal(result.StructuredContent)
+	require.NoError(t, err)
+	var structured map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &structured))
+	assert.Empty(t, mcp.ValidateArguments(server.listDirectoryTool().OutputSchema, structured))
+	assert.Equal(t, float64(1), structured["count"])
+}
+
+func TestTouchFile(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	path := filepath.Join(tempDir, "stamp")
+
+	t.Run("creates the file", func(t *testing.T) {
+		_, err := server.touchFileTool().Handler(context.Background(), map[string]interface{}{"path": path})
+		require.NoError(t, err)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Zero(t, info.Size())
+	})
+
+	t.Run("sets the times", func(t *testing.T) {
+		_, err := server.touchFileTool().Handler(context.Background(), map[string]interface{}{
+			"path": path, "mtime": "2020-05-01T12:00:00Z",
+		})
+		require.NoError(t, err)
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.True(t, info.ModTime().Equal(time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)))
+
+		other := filepath.Join(tempDir, "other")
+		_, err = server.touchFileTool().Handler(context.Background(), map[string]interface{}{"path": other, "reference": path})
+		require.NoError(t, err)
+		otherInfo, err := os.Stat(other)
+		require.NoError(t, err)
+		assert.True(t, otherInfo.ModTime().Equal(info.ModTime()))
+	})
+
+	t.Run("bad timestamp", func(t *testing.T) {
+		_, err := server.touchFileTool().Handler(context.Background(), map[string]interface{}{"path": path, "mtime": "yesterday"})
+		var paramErr *mcp.ParamError
+		assert.ErrorAs(t, err, &paramErr)
+	})
+
+	t.Run("no_create", func(t *testing.T) {
+		_, err := server.touchFileTool().Handler(context.Background(), map[string]interface{}{
+			"path": filepath.Join(tempDir, "missing"), "no_create": true,
+		})
+		assert.ErrorIs(t, err, common.ErrNotFound)
+	})
+}
+
+func TestBase64Encoding(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	path := filepath.Join(tempDir, "image.png")
+	data := []byte{0x89, 'P', 'N', 'G', 0x00, 0xff, 0x10}
+
+	_, err := server.writeFileTool().Handler(context.Background(), map[string]interface{}{
+		"path": path, "content": base64.StdEncoding.EncodeToString(data), "encoding": "base64",
+	})
+	require.NoError(t, err)
+	written, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, data, written)
+
+	result, err := server.readFileTool().Handler(context.Background(), map[string]interface{}{"path": path, "encoding": "base64"})
+	require.NoError(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(data), result.Content[0].Text)
+
+	result, err = server.readFileTool().Handler(context.Background(), map[string]interface{}{
+		"path": path, "encoding": "base64", "offset": 4, "length": 2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, base64.StdEncoding.EncodeToString(data[4:6]), result.Content[0].Text)
+
+	_, err = server.writeFileTool().Handler(context.Background(), map[string]interface{}{
+		"path": path, "content": "not base64!", "encoding": "base64",
+	})
+	var paramErr *mcp.ParamError
+	assert.ErrorAs(t, err, &paramErr)
+
+	_, err = server.readFileTool().Handler(context.Background(), map[string]interface{}{"path": path, "encoding": "hex"})
+	assert.ErrorAs(t, err, &paramErr)
+}
@@ -0,0 +1,106 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) touchFileTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "touch_file",
+		Description: "Create an empty file if it does not exist and set its access and modification times, to now by default",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":      mcp.StringProperty("Absolute path to the file"),
+				"mtime":     mcp.StringProperty("Modification time, RFC 3339 (default: now)"),
+				"atime":     mcp.StringProperty("Access time, RFC 3339 (default: the modification time)"),
+				"reference": mcp.StringProperty("Absolute path of a file whose modification time to use instead"),
+				"no_create": mcp.BoolProperty("Fail instead of creating a missing file"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleTouchFile),
+	}
+}
+
+type touchFileArgs struct {
+	Path      string `json:"path" validate:"required"`
+	Mtime     string `json:"mtime"`
+	Atime     string `json:"atime"`
+	Reference string `json:"reference"`
+	NoCreate  bool   `json:"no_create"`
+}
+
+func (s *Server) handleTouchFile(ctx context.Context, args touchFileArgs) (*mcp.ToolResult, error) {
+	absPath, err := filepath.Abs(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.ValidatePath(absPath); err != nil {
+		return nil, err
+	}
+
+	mtime := time.Now()
+	switch {
+	case args.Reference != "" && args.Mtime != "":
+		return nil, fmt.Errorf("%w: give mtime or reference, not both", common.ErrInvalidInput)
+	case args.Reference != "":
+		refPath, err := s.validator.ResolvePath(args.Reference)
+		if err != nil {
+			return nil, err
+		}
+		info, err := statExisting(refPath, args.Reference)
+		if err != nil {
+			return nil, err
+		}
+		mtime = info.ModTime()
+	case args.Mtime != "":
+		if mtime, err = parseTimestamp("mtime", args.Mtime); err != nil {
+			return nil, err
+		}
+	}
+	atime := mtime
+	if args.Atime != "" {
+		if atime, err = parseTimestamp("atime", args.Atime); err != nil {
+			return nil, err
+		}
+	}
+
+	created := false
+	if _, err := os.Stat(absPath); os.IsNotExist(err) {
+		if args.NoCreate {
+			return nil, fmt.Errorf("%w: %s", common.ErrNotFound, args.Path)
+		}
+		f, err := os.OpenFile(absPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return nil, err
+		}
+		f.Close()
+		created = true
+	}
+	if err := os.Chtimes(absPath, atime, mtime); err != nil {
+		return nil, err
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"path":    absPath,
+		"created": created,
+		"atime":   atime.Format(time.RFC3339Nano),
+		"mtime":   mtime.Format(time.RFC3339Nano),
+	})
+}
+
+func parseTimestamp(name, value string) (time.Time, error) {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}, &mcp.ParamError{Param: name, Message: fmt.Sprintf("parameter %s must be an RFC 3339 time such as 2024-01-02T15:04:05Z", name)}
+	}
+	return t, nil
+}
@@ -0,0 +1,228 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxTreeScan bounds how many entries one tree call walks; sizes and
+// counts cover only those.
+const maxTreeScan = 200000
+
+// TreeNode is an entry of a tree. For a directory, SizeBytes and Files
+// total everything under it, shown or not, and Omitted counts the children
+// left out by the depth or entry limit.
+type TreeNode struct {
+	Name        string      `json:"name"`
+	IsDirectory bool        `json:"is_directory"`
+	SizeBytes   int64       `json:"size_bytes"`
+	Files       int         `json:"files,omitempty"`
+	Children    []*TreeNode `json:"children,omitempty"`
+	Omitted     int         `json:"omitted,omitempty"`
+}
+
+func (s *Server) treeTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "tree",
+		Description: "Show a directory as a tree, like the tree command, with each directory's file count and total size. Entries ignored by .gitignore or the default excludes are left out. Use it to get oriented in a project; deeper levels are summarized rather than listed",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":            mcp.StringProperty("Absolute path to the directory"),
+				"max_depth":       mcp.IntProperty("Deepest level to show; 1 is the directory's own children (default: 3, max: 20)"),
+				"max_entries":     mcp.IntProperty("Maximum number of entries to show (default: 500, max: 5000)"),
+				"format":          mcp.StringProperty("text for an indented tree or json for nested objects (default: text)"),
+				"dirs_only":       mcp.BoolProperty("Show only directories"),
+				"exclude":         mcp.ArrayProperty("string", "Extra patterns to skip, in .gitignore syntax, e.g. *.min.js or docs/"),
+				"include_ignored": mcp.BoolProperty("Also show ignored files and directories"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleTree),
+	}
+}
+
+type treeArgs struct {
+	Path           string   `json:"path" validate:"required"`
+	MaxDepth       int      `json:"max_depth" default:"3" validate:"min=1,max=20"`
+	MaxEntries     int      `json:"max_entries" default:"500" validate:"min=1,max=5000"`
+	Format         string   `json:"format" default:"text" validate:"oneof=text json"`
+	DirsOnly       bool     `json:"dirs_only"`
+	Exclude        []string `json:"exclude"`
+	IncludeIgnored bool     `json:"include_ignored"`
+}
+
+func (s *Server) handleTree(ctx context.Context, args treeArgs) (*mcp.ToolResult, error) {
+	root, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := statExisting(root, args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%w: %s", common.ErrNotADirectory, args.Path)
+	}
+	ignore, err := s.newIgnoreMatcher(root, args.Exclude, args.IncludeIgnored)
+	if err != nil {
+		return nil, err
+	}
+
+	top := &TreeNode{Name: filepath.Base(root) + "/", IsDirectory: true}
+	nodes := map[string]*TreeNode{root: top}
+	dirs, scanned := 0, 0
+	truncated := false
+	err = filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if err != nil || p == root {
+			return nil
+		}
+		if s.validator.ValidatePath(p) != nil || ignore.ignored(p, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if scanned++; scanned > maxTreeScan {
+			truncated = true
+			return filepath.SkipAll
+		}
+
+		rel, _ := filepath.Rel(root, p)
+		depth := strings.Count(rel, string(filepath.Separator)) + 1
+		if depth <= args.MaxDepth {
+			node := &TreeNode{Name: d.Name(), IsDirectory: d.IsDir()}
+			if d.IsDir() {
+				node.Name += "/"
+				dirs++
+			}
+			nodes[p] = node
+			parent := nodes[filepath.Dir(p)]
+			if !args.DirsOnly || d.IsDir() {
+				parent.Children = append(parent.Children, node)
+			}
+		} else if depth == args.MaxDepth+1 && (!args.DirsOnly || d.IsDir()) {
+			nodes[filepath.Dir(p)].Omitted++
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		// Add the file to each directory above it that is in the tree.
+		for dir := filepath.Dir(p); ; dir = filepath.Dir(dir) {
+			if node, ok := nodes[dir]; ok {
+				node.SizeBytes += info.Size()
+				node.Files++
+			}
+			if dir == root {
+				break
+			}
+		}
+		if node := nodes[p]; node != nil {
+			node.SizeBytes = info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	shown := 0
+	limitTree(top, &shown, args.MaxEntries)
+	if args.Format == "json" {
+		return mcp.JSONResult(map[string]interface{}{
+			"path":        root,
+			"tree":        top,
+			"directories": dirs,
+			"files":       top.Files,
+			"total_bytes": top.SizeBytes,
+			"truncated":   truncated,
+		})
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s (%s)\n", root, describeTreeNode(top))
+	renderTree(&b, top, "")
+	fmt.Fprintf(&b, "\n%d directories, %d files, %s", dirs, top.Files, formatSize(top.SizeBytes))
+	if truncated {
+		fmt.Fprintf(&b, "\n(stopped after %d entries; totals are partial)", maxTreeScan)
+	}
+	return mcp.TextResult(b.String()), nil
+}
+
+// limitTree sorts the tree, directories first, and cuts it to limit
+// entries in breadth-first order, so the top levels are always shown.
+func limitTree(top *TreeNode, shown *int, limit int) {
+	queue := []*TreeNode{top}
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		sort.Slice(node.Children, func(i, j int) bool {
+			a, b := node.Children[i], node.Children[j]
+			if a.IsDirectory != b.IsDirectory {
+				return a.IsDirectory
+			}
+			return a.Name < b.Name
+		})
+		if room := limit - *shown; len(node.Children) > room {
+			node.Omitted += len(node.Children) - max(room, 0)
+			node.Children = node.Children[:max(room, 0)]
+		}
+		*shown += len(node.Children)
+		queue = append(queue, node.Children...)
+	}
+}
+
+func renderTree(b *strings.Builder, node *TreeNode, prefix string) {
+	for i, child := range node.Children {
+		branch, indent := "├── ", "│   "
+		if i == len(node.Children)-1 && node.Omitted == 0 {
+			branch, indent = "└── ", "    "
+		}
+		fmt.Fprintf(b, "%s%s%s (%s)\n", prefix, branch, child.Name, describeTreeNode(child))
+		renderTree(b, child, prefix+indent)
+	}
+	if node.Omitted > 0 {
+		fmt.Fprintf(b, "%s└── … %d more\n", prefix, node.Omitted)
+	}
+}
+
+func describeTreeNode(n *TreeNode) string {
+	if !n.IsDirectory {
+		return formatSize(n.SizeBytes)
+	}
+	files := "files"
+	if n.Files == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%d %s, %s", n.Files, files, formatSize(n.SizeBytes))
+}
+
+// formatSize renders a byte count in binary units, e.g. 1.5 KiB.
+func formatSize(n int64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d B", n)
+	}
+	size := float64(n)
+	for _, unit := range []string{"KiB", "MiB", "GiB", "TiB"} {
+		size /= 1024
+		if size < 1024 || unit == "TiB" {
+			return fmt.Sprintf("%.1f %s", size, unit)
+		}
+	}
+	return ""
+}
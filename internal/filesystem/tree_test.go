@@ -0,0 +1,75 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTree(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	for path, content := range map[string]string{
+		"go.mod":               "module x\n",
+		"cmd/app/main.go":      "package main\n",
+		"cmd/app/deep/x.go":    "package deep\n",
+		"internal/a.go":        strings.Repeat("a", 2048),
+		"internal/b.go":        "b",
+		"node_modules/dep.js":  "x",
+		".gitignore":           "*.log\n",
+		"debug.log":            "noise",
+		"internal/c/README.md": "c",
+	} {
+		full := filepath.Join(tempDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0755))
+		require.NoError(t, os.WriteFile(full, []byte(content), 0644))
+	}
+	server.config.DefaultExcludes = []string{"node_modules/"}
+	server.config.RespectGitignore = true
+
+	run := func(params map[string]interface{}) string {
+		params["path"] = tempDir
+		result, err := server.treeTool().Handler(context.Background(), params)
+		require.NoError(t, err)
+		return result.Content[0].Text
+	}
+
+	t.Run("text", func(t *testing.T) {
+		out := run(map[string]interface{}{"max_depth": 2})
+		assert.Contains(t, out, "├── cmd/ (2 files, 26 B)\n│   └── app/ (2 files, 26 B)\n│       └── … 2 more\n")
+		assert.Contains(t, out, "│   ├── c/ (1 file, 1 B)\n│   │   └── … 1 more\n│   ├── a.go (2.0 KiB)\n│   └── b.go (1 B)\n")
+		assert.NotContains(t, out, "node_modules")
+		assert.NotContains(t, out, "debug.log")
+		assert.Contains(t, out, "4 directories, 7 files")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(run(map[string]interface{}{"format": "json", "max_depth": 1, "dirs_only": true})), &out))
+		tree := out["tree"].(map[string]interface{})
+		var names []string
+		for _, c := range tree["children"].([]interface{}) {
+			names = append(names, c.(map[string]interface{})["name"].(string))
+		}
+		assert.Equal(t, []string{"cmd/", "internal/"}, names)
+		assert.EqualValues(t, 7, out["files"])
+	})
+
+	t.Run("entry limit", func(t *testing.T) {
+		out := run(map[string]interface{}{"max_entries": 2})
+		assert.Contains(t, out, "├── cmd/")
+		assert.Contains(t, out, "├── internal/")
+		assert.Contains(t, out, "└── … 3 more\n")
+	})
+
+	t.Run("not a directory", func(t *testing.T) {
+		_, err := server.treeTool().Handler(context.Background(), map[string]interface{}{"path": filepath.Join(tempDir, "go.mod")})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,41 @@
+package filesystem
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+)
+
+// walkBudget bounds a directory walk by filesystem.max_walk_files and
+// filesystem.max_walk_seconds.
+type walkBudget struct {
+	ctx      context.Context
+	maxFiles int
+	deadline time.Time
+	visited  int
+	// truncated is set once the walk ran out of budget.
+	truncated bool
+}
+
+func (s *Server) newWalkBudget(ctx context.Context) *walkBudget {
+	b := &walkBudget{ctx: ctx, maxFiles: s.config.MaxWalkFiles}
+	if s.config.MaxWalkSeconds > 0 {
+		b.deadline = time.Now().Add(time.Duration(s.config.MaxWalkSeconds) * time.Second)
+	}
+	return b
+}
+
+// spend counts an entry of the walk. It returns the context's error if the
+// call was cancelled, or filepath.SkipAll once the budget is used up, for
+// the walk function to return.
+func (b *walkBudget) spend() error {
+	if err := b.ctx.Err(); err != nil {
+		return err
+	}
+	b.visited++
+	if (b.maxFiles > 0 && b.visited > b.maxFiles) || (!b.deadline.IsZero() && time.Now().After(b.deadline)) {
+		b.truncated = true
+		return filepath.SkipAll
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWalkLimits(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	for i := 0; i < 20; i++ {
+		dir := filepath.Join(tempDir, fmt.Sprintf("pkg%02d", i))
+		require.NoError(t, os.MkdirAll(dir, 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "main.go"), []byte("package main // TODO\n"), 0644))
+	}
+
+	tools := map[string]struct {
+		tool   *mcp.Tool
+		params map[string]interface{}
+	}{
+		"list_directory": {server.listDirectoryTool(), map[string]interface{}{"path": tempDir, "recursive": true}},
+		"search_files":   {server.searchFilesTool(), map[string]interface{}{"directory": tempDir, "pattern": "*.go"}},
+		"grep":           {server.grepTool(), map[string]interface{}{"directory": tempDir, "pattern": "TODO"}},
+	}
+	for name, tc := range tools {
+		t.Run(name, func(t *testing.T) {
+			call := func(ctx context.Context) (map[string]interface{}, error) {
+				result, err := tc.tool.Handler(ctx, tc.params)
+				if err != nil {
+					return nil, err
+				}
+				var out map[string]interface{}
+				require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+				return out, nil
+			}
+
+			server.config.MaxWalkFiles = 0
+			out, err := call(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, false, out["truncated"])
+			full := out["count"].(float64)
+
+			server.config.MaxWalkFiles = 10
+			out, err = call(context.Background())
+			require.NoError(t, err)
+			assert.Equal(t, true, out["truncated"])
+			assert.Less(t, out["count"].(float64), full)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			_, err = call(ctx)
+			assert.ErrorIs(t, err, context.Canceled)
+		})
+	}
+}
@@ -0,0 +1,398 @@
+package filesystem
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+const (
+	watchPollInterval = 250 * time.Millisecond
+	// maxWatches bounds the watches running at once.
+	maxWatches = 32
+	// maxWatchedFiles bounds how many paths one watch tracks.
+	maxWatchedFiles = 10000
+	// maxWatchEvents bounds the events a watch buffers; the oldest are
+	// dropped first.
+	maxWatchEvents = 1000
+)
+
+type WatchEvent struct {
+	Path    string    `json:"path"`
+	Op      string    `json:"op"` // create, modify, delete, rename
+	OldPath string    `json:"old_path,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// watcher polls a path for changes. The filesystem tree is compared
+// against the previous scan, so it needs no OS notification support and no
+// fsnotify dependency, and behaves the same on every platform and on
+// network mounts. The cost is latency of up to watchPollInterval, a stat of
+// every watched path per poll, and missing changes beyond maxWatchedFiles or
+// ones undone within a single interval.
+type watcher struct {
+	id        string
+	path      string
+	recursive bool
+	pattern   string
+	validator *common.PathValidator
+	// session is the HTTP client that started the watch, which alone can
+	// see it; nil over stdio.
+	session *mcp.Session
+	cancel  context.CancelFunc
+
+	mu        sync.Mutex
+	events    []WatchEvent
+	dropped   int
+	truncated bool
+	// changed is closed and replaced whenever events are added.
+	changed chan struct{}
+}
+
+type watchManager struct {
+	mu      sync.Mutex
+	watches map[string]*watcher
+}
+
+func newWatchManager() *watchManager {
+	return &watchManager{watches: make(map[string]*watcher)}
+}
+
+func (m *watchManager) start(w *watcher) error {
+	m.mu.Lock()
+	if len(m.watches) >= maxWatches {
+		m.mu.Unlock()
+		return fmt.Errorf("%w: %d watches are already running; stop one with stop_watch", common.ErrOperationFailed, maxWatches)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	w.cancel = cancel
+	w.changed = make(chan struct{})
+	m.watches[w.id] = w
+	m.mu.Unlock()
+
+	if w.session != nil {
+		w.session.OnClose(func() { m.stop(w.session, w.id) })
+	}
+	go w.run(ctx, w.scan())
+	return nil
+}
+
+// get returns the watch id if session started it.
+func (m *watchManager) get(session *mcp.Session, id string) (*watcher, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.watches[id]
+	if !ok || w.session != session {
+		return nil, false
+	}
+	return w, true
+}
+
+func (m *watchManager) stop(session *mcp.Session, id string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	w, ok := m.watches[id]
+	if !ok || w.session != session {
+		return false
+	}
+	w.cancel()
+	delete(m.watches, id)
+	return true
+}
+
+func (m *watchManager) stopAll() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for id, w := range m.watches {
+		w.cancel()
+		delete(m.watches, id)
+	}
+}
+
+func (w *watcher) run(ctx context.Context, snapshot map[string]os.FileInfo) {
+	ticker := time.NewTicker(watchPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		next := w.scan()
+		w.record(diffSnapshots(snapshot, next))
+		snapshot = next
+	}
+}
+
+// scan maps each watched path to its current state.
+func (w *watcher) scan() map[string]os.FileInfo {
+	files := make(map[string]os.FileInfo)
+	root, err := os.Lstat(w.path)
+	if err != nil {
+		return files
+	}
+	if !root.IsDir() {
+		files[w.path] = root
+		return files
+	}
+	truncated := false
+	filepath.WalkDir(w.path, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || p == w.path {
+			return nil
+		}
+		if w.validator.ValidatePath(p) != nil {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if w.pattern == "" || matchName(w.pattern, d.Name()) {
+			info, err := d.Info()
+			if err != nil {
+				return nil
+			}
+			if len(files) >= maxWatchedFiles {
+				truncated = true
+				return filepath.SkipAll
+			}
+			files[p] = info
+		}
+		if d.IsDir() && !w.recursive {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	w.mu.Lock()
+	w.truncated = truncated
+	w.mu.Unlock()
+	return files
+}
+
+func matchName(pattern, name string) bool {
+	matched, _ := filepath.Match(pattern, name)
+	return matched
+}
+
+// diffSnapshots returns the changes from before to after. A path deleted
+// and another created for the same file in one poll is reported as a rename.
+func diffSnapshots(before, after map[string]os.FileInfo) []WatchEvent {
+	now := time.Now()
+	var created, deleted, events []WatchEvent
+	for p, info := range after {
+		old, ok := before[p]
+		switch {
+		case !ok:
+			created = append(created, WatchEvent{Path: p, Op: "create", Time: now})
+		case !info.IsDir() && (!info.ModTime().Equal(old.ModTime()) || info.Size() != old.Size() || info.Mode() != old.Mode()):
+			events = append(events, WatchEvent{Path: p, Op: "modify", Time: now})
+		}
+	}
+	for p := range before {
+		if _, ok := after[p]; !ok {
+			deleted = append(deleted, WatchEvent{Path: p, Op: "delete", Time: now})
+		}
+	}
+
+	for i := range created {
+		for j := range deleted {
+			if deleted[j].Op == "delete" && os.SameFile(after[created[i].Path], before[deleted[j].Path]) {
+				created[i].Op, created[i].OldPath = "rename", deleted[j].Path
+				deleted[j].Op = ""
+				break
+			}
+		}
+	}
+	events = append(events, created...)
+	for _, e := range deleted {
+		if e.Op != "" {
+			events = append(events, e)
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Path < events[j].Path })
+	return events
+}
+
+func (w *watcher) record(events []WatchEvent) {
+	if len(events) == 0 {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.events = append(w.events, events...)
+	if over := len(w.events) - maxWatchEvents; over > 0 {
+		w.events = append([]WatchEvent(nil), w.events[over:]...)
+		w.dropped += over
+	}
+	close(w.changed)
+	w.changed = make(chan struct{})
+}
+
+// take removes and returns up to limit buffered events, waiting up to wait
+// for the first if there are none, and reporting them as progress when
+// they arrive.
+func (w *watcher) take(ctx context.Context, limit int, wait time.Duration) []WatchEvent {
+	deadline := time.NewTimer(wait)
+	defer deadline.Stop()
+	for {
+		w.mu.Lock()
+		if len(w.events) > 0 || wait <= 0 {
+			n := min(limit, len(w.events))
+			events := append([]WatchEvent{}, w.events[:n]...)
+			w.events = w.events[n:]
+			w.mu.Unlock()
+			return events
+		}
+		changed := w.changed
+		w.mu.Unlock()
+
+		select {
+		case <-changed:
+			w.mu.Lock()
+			var lines []string
+			for _, e := range w.events {
+				lines = append(lines, e.Op+" "+e.Path)
+			}
+			w.mu.Unlock()
+			mcp.ReportProgress(ctx, float64(len(lines)), 0, strings.Join(lines, "\n"))
+		case <-deadline.C:
+			wait = 0
+		case <-ctx.Done():
+			wait = 0
+		}
+	}
+}
+
+func (s *Server) watchPathTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name: "watch_path",
+		Description: "Start watching a file or directory for changes. Changes (create, modify, delete, rename) are buffered until read with get_watch_events; stop the watch with stop_watch. " +
+			"The watch polls every 250ms rather than using OS notifications: changes show up to 250ms late, a change undone within one poll is missed, " +
+			"and only the first 10000 matching paths are tracked (get_watch_events reports truncated), so narrow large trees with pattern or recursive: false",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":      mcp.StringProperty("Absolute path to the file or directory"),
+				"recursive": mcp.BoolProperty("Watch subdirectories too (default: true)"),
+				"pattern":   mcp.StringProperty("Only report files whose name matches this glob, e.g. *.log"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleWatchPath),
+	}
+}
+
+type watchPathArgs struct {
+	Path      string `json:"path" validate:"required"`
+	Recursive bool   `json:"recursive" default:"true"`
+	Pattern   string `json:"pattern"`
+}
+
+func (s *Server) handleWatchPath(ctx context.Context, args watchPathArgs) (*mcp.ToolResult, error) {
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := statExisting(absPath, args.Path); err != nil {
+		return nil, err
+	}
+	if _, err := filepath.Match(args.Pattern, ""); err != nil {
+		return nil, &mcp.ParamError{Param: "pattern", Message: fmt.Sprintf("invalid pattern: %v", err)}
+	}
+
+	w := &watcher{
+		id:        uuid.New().String(),
+		path:      absPath,
+		recursive: args.Recursive,
+		pattern:   args.Pattern,
+		validator: s.validator,
+		session:   mcp.SessionFromContext(ctx),
+	}
+	if err := s.watches.start(w); err != nil {
+		return nil, err
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"watch_id":  w.id,
+		"path":      absPath,
+		"recursive": args.Recursive,
+	})
+}
+
+func (s *Server) getWatchEventsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "get_watch_events",
+		Description: "Return and clear the changes a watch has seen. With wait_seconds, wait for the first change if there is none yet, sending progress notifications as changes arrive",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"watch_id":     mcp.StringProperty("ID returned by watch_path"),
+				"wait_seconds": mcp.IntProperty("How long to wait for a change (default: 0, max: 300)"),
+				"max_events":   mcp.IntProperty("Most events to return (default: 100, max: 1000)"),
+			},
+			[]string{"watch_id"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGetWatchEvents),
+	}
+}
+
+type getWatchEventsArgs struct {
+	WatchID     string `json:"watch_id" validate:"required"`
+	WaitSeconds int    `json:"wait_seconds" validate:"min=0,max=300"`
+	MaxEvents   int    `json:"max_events" default:"100" validate:"min=1,max=1000"`
+}
+
+func (s *Server) handleGetWatchEvents(ctx context.Context, args getWatchEventsArgs) (*mcp.ToolResult, error) {
+	w, ok := s.watches.get(mcp.SessionFromContext(ctx), args.WatchID)
+	if !ok {
+		return nil, fmt.Errorf("%w: watch %s", common.ErrNotFound, args.WatchID)
+	}
+	events := w.take(ctx, args.MaxEvents, time.Duration(args.WaitSeconds)*time.Second)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	result := map[string]interface{}{
+		"watch_id":  w.id,
+		"path":      w.path,
+		"events":    events,
+		"remaining": len(w.events),
+		"dropped":   w.dropped,
+		"truncated": w.truncated,
+	}
+	w.dropped = 0
+	return mcp.JSONResult(result)
+}
+
+func (s *Server) stopWatchTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "stop_watch",
+		Description: "Stop a watch started with watch_path, discarding its unread events",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"watch_id": mcp.StringProperty("ID returned by watch_path"),
+			},
+			[]string{"watch_id"},
+		),
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleStopWatch),
+	}
+}
+
+type stopWatchArgs struct {
+	WatchID string `json:"watch_id" validate:"required"`
+}
+
+func (s *Server) handleStopWatch(ctx context.Context, args stopWatchArgs) (*mcp.ToolResult, error) {
+	if !s.watches.stop(mcp.SessionFromContext(ctx), args.WatchID) {
+		return nil, fmt.Errorf("%w: watch %s", common.ErrNotFound, args.WatchID)
+	}
+	return mcp.TextResult(fmt.Sprintf("Stopped watch %s", args.WatchID)), nil
+}
@@ -0,0 +1,102 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+func TestWatchPath(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	defer server.Shutdown(context.Background())
+	existing := filepath.Join(tempDir, "existing.txt")
+	require.NoError(t, os.WriteFile(existing, []byte("v1"), 0644))
+	doomed := filepath.Join(tempDir, "doomed.txt")
+	require.NoError(t, os.WriteFile(doomed, []byte("x"), 0644))
+	moved := filepath.Join(tempDir, "moved.txt")
+	require.NoError(t, os.WriteFile(moved, []byte("m"), 0644))
+
+	result, err := server.watchPathTool().Handler(context.Background(), map[string]interface{}{"path": tempDir})
+	require.NoError(t, err)
+	var started struct {
+		WatchID string `json:"watch_id"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &started))
+
+	events := func(wait int) []WatchEvent {
+		result, err := server.getWatchEventsTool().Handler(context.Background(), map[string]interface{}{
+			"watch_id": started.WatchID, "wait_seconds": wait,
+		})
+		require.NoError(t, err)
+		var out struct {
+			Events []WatchEvent `json:"events"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out.Events
+	}
+	assert.Empty(t, events(0))
+
+	require.NoError(t, os.MkdirAll(filepath.Join(tempDir, "build"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "build", "out.bin"), []byte("built"), 0644))
+	require.NoError(t, os.WriteFile(existing, []byte("version 2"), 0644))
+	require.NoError(t, os.Remove(doomed))
+	require.NoError(t, os.Rename(moved, filepath.Join(tempDir, "renamed.txt")))
+
+	got := map[string]WatchEvent{}
+	deadline := time.Now().Add(5 * time.Second)
+	for len(got) < 5 && time.Now().Before(deadline) {
+		for _, e := range events(2) {
+			got[e.Path] = e
+		}
+	}
+	assert.Equal(t, "create", got[filepath.Join(tempDir, "build")].Op)
+	assert.Equal(t, "create", got[filepath.Join(tempDir, "build", "out.bin")].Op)
+	assert.Equal(t, "modify", got[existing].Op)
+	assert.Equal(t, "delete", got[doomed].Op)
+	renamed := got[filepath.Join(tempDir, "renamed.txt")]
+	assert.Equal(t, "rename", renamed.Op)
+	assert.Equal(t, moved, renamed.OldPath)
+
+	_, err = server.stopWatchTool().Handler(context.Background(), map[string]interface{}{"watch_id": started.WatchID})
+	require.NoError(t, err)
+	_, err = server.getWatchEventsTool().Handler(context.Background(), map[string]interface{}{"watch_id": started.WatchID})
+	assert.ErrorIs(t, err, common.ErrNotFound)
+}
+
+func TestWatchPathPattern(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	defer server.Shutdown(context.Background())
+
+	result, err := server.watchPathTool().Handler(context.Background(), map[string]interface{}{
+		"path": tempDir, "pattern": "*.log",
+	})
+	require.NoError(t, err)
+	var started struct {
+		WatchID string `json:"watch_id"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &started))
+
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "ignored.txt"), nil, 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tempDir, "server.log"), nil, 0644))
+
+	result, err = server.getWatchEventsTool().Handler(context.Background(), map[string]interface{}{
+		"watch_id": started.WatchID, "wait_seconds": 5,
+	})
+	require.NoError(t, err)
+	var out struct {
+		Events []WatchEvent `json:"events"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+	require.Len(t, out.Events, 1)
+	assert.Equal(t, filepath.Join(tempDir, "server.log"), out.Events[0].Path)
+}
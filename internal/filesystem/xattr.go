@@ -0,0 +1,191 @@
+package filesystem
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// quarantineXattr is set by macOS on downloaded files; Gatekeeper checks
+// or blocks them before they first run.
+const quarantineXattr = "com.apple.quarantine"
+
+var (
+	errXattrMissing     = fmt.Errorf("%w: no such extended attribute", common.ErrNotFound)
+	errXattrUnsupported = fmt.Errorf("%w: extended attributes are not supported on this file system or platform", common.ErrNotImplemented)
+)
+
+type Xattr struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	// Encoding is text, or base64 for values that are not text.
+	Encoding  string `json:"encoding"`
+	SizeBytes int    `json:"size_bytes"`
+}
+
+func newXattr(name string, value []byte) Xattr {
+	text, encoding := xattrText(value)
+	return Xattr{Name: name, Value: text, Encoding: encoding, SizeBytes: len(value)}
+}
+
+// xattrText renders an attribute value as text if it is printable, or in
+// base64.
+func xattrText(value []byte) (string, string) {
+	// C strings are often stored with their terminating NUL.
+	text := bytes.TrimSuffix(value, []byte{0})
+	if utf8.Valid(text) && !bytes.ContainsFunc(text, func(r rune) bool { return unicode.IsControl(r) && r != '\n' && r != '\t' }) {
+		return string(text), "text"
+	}
+	return base64.StdEncoding.EncodeToString(value), "base64"
+}
+
+func (s *Server) getXattrsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "get_xattrs",
+		Description: "List a file's extended attributes and their values, or get one by name. On macOS these include com.apple.quarantine, which makes Gatekeeper check or block a downloaded program, often why a binary will not run",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path": mcp.StringProperty("Absolute path to the file"),
+				"name": mcp.StringProperty("Attribute to get (default: all)"),
+			},
+			[]string{"path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGetXattrs),
+	}
+}
+
+type getXattrsArgs struct {
+	Path string `json:"path" validate:"required"`
+	Name string `json:"name"`
+}
+
+func (s *Server) handleGetXattrs(ctx context.Context, args getXattrsArgs) (*mcp.ToolResult, error) {
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := statExisting(absPath, args.Path); err != nil {
+		return nil, err
+	}
+
+	attrs := []Xattr{}
+	if args.Name != "" {
+		value, err := getXattr(absPath, args.Name)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s on %s", err, args.Name, args.Path)
+		}
+		attrs = append(attrs, newXattr(args.Name, value))
+	} else {
+		values, err := listXattrs(absPath)
+		if err != nil {
+			return nil, err
+		}
+		for name, value := range values {
+			attrs = append(attrs, newXattr(name, value))
+		}
+		sort.Slice(attrs, func(i, j int) bool { return attrs[i].Name < attrs[j].Name })
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"path":       absPath,
+		"attributes": attrs,
+	})
+}
+
+func (s *Server) setXattrTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "set_xattr",
+		Description: "Set or remove an extended attribute of a file, e.g. remove com.apple.quarantine from a program macOS will not run. On Linux, unprivileged users can set only user.* attributes",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"path":     mcp.StringProperty("Absolute path to the file"),
+				"name":     mcp.StringProperty("Attribute name, e.g. user.origin"),
+				"value":    mcp.StringProperty("Value to set"),
+				"encoding": mcp.StringProperty("text or base64, for binary values (default: text)"),
+				"remove":   mcp.BoolProperty("Remove the attribute instead of setting it"),
+			},
+			[]string{"path", "name"},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleSetXattr),
+	}
+}
+
+type setXattrArgs struct {
+	Path     string `json:"path" validate:"required"`
+	Name     string `json:"name" validate:"required"`
+	Value    string `json:"value"`
+	Encoding string `json:"encoding" default:"text" validate:"oneof=text base64"`
+	Remove   bool   `json:"remove"`
+}
+
+func (s *Server) handleSetXattr(ctx context.Context, args setXattrArgs) (*mcp.ToolResult, error) {
+	absPath, err := s.validator.ResolvePath(args.Path)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := statExisting(absPath, args.Path); err != nil {
+		return nil, err
+	}
+
+	if args.Remove {
+		if err := removeXattr(absPath, args.Name); err != nil {
+			return nil, fmt.Errorf("%w: %s on %s", err, args.Name, args.Path)
+		}
+		return mcp.JSONResult(map[string]interface{}{"path": absPath, "name": args.Name, "removed": true})
+	}
+
+	value := []byte(args.Value)
+	if args.Encoding == "base64" {
+		if value, err = base64.StdEncoding.DecodeString(args.Value); err != nil {
+			return nil, &mcp.ParamError{Param: "value", Message: "value is not valid base64"}
+		}
+	}
+	if err := setXattr(absPath, args.Name, value); err != nil {
+		return nil, fmt.Errorf("setting %s on %s: %w", args.Name, args.Path, err)
+	}
+	return mcp.JSONResult(map[string]interface{}{"path": absPath, "name": args.Name, "size_bytes": len(value)})
+}
+
+// fileXattrs returns the extended attributes file_info shows, with binary
+// values in base64 after a "base64:" prefix, or nil if there are none or
+// they cannot be read.
+func fileXattrs(path string) map[string]string {
+	values, err := listXattrs(path)
+	if err != nil || len(values) == 0 {
+		return nil
+	}
+	attrs := make(map[string]string, len(values))
+	for name, value := range values {
+		text, encoding := xattrText(value)
+		if encoding != "text" {
+			text = encoding + ":" + text
+		}
+		attrs[name] = text
+	}
+	return attrs
+}
+
+// isQuarantined reports whether macOS has quarantined a file: it has the
+// attribute and Gatekeeper has not yet approved it, which sets the 0x40
+// bit of its leading flags.
+func isQuarantined(attrs map[string]string) bool {
+	value, ok := attrs[quarantineXattr]
+	if !ok {
+		return false
+	}
+	flags, _, _ := strings.Cut(value, ";")
+	var n int
+	if _, err := fmt.Sscanf(flags, "%x", &n); err != nil {
+		return true
+	}
+	return n&0x40 == 0
+}
@@ -0,0 +1,19 @@
+//go:build !linux && !darwin
+
+package filesystem
+
+func listXattrs(path string) (map[string][]byte, error) {
+	return nil, errXattrUnsupported
+}
+
+func getXattr(path, name string) ([]byte, error) {
+	return nil, errXattrUnsupported
+}
+
+func setXattr(path, name string, value []byte) error {
+	return errXattrUnsupported
+}
+
+func removeXattr(path, name string) error {
+	return errXattrUnsupported
+}
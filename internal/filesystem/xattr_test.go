@@ -0,0 +1,99 @@
+package filesystem
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXattrs(t *testing.T) {
+	tempDir := t.TempDir()
+	server := newTestServer(t, tempDir)
+	file := filepath.Join(tempDir, "app")
+	require.NoError(t, os.WriteFile(file, []byte("#!/bin/sh\n"), 0755))
+	if err := setXattr(file, "user.probe", []byte("x")); errors.Is(err, common.ErrNotImplemented) {
+		t.Skip("extended attributes are not supported here")
+	}
+	require.NoError(t, removeXattr(file, "user.probe"))
+
+	set := func(params map[string]interface{}) error {
+		params["path"] = file
+		_, err := server.setXattrTool().Handler(context.Background(), params)
+		return err
+	}
+	get := func(params map[string]interface{}) ([]map[string]interface{}, error) {
+		params["path"] = file
+		result, err := server.getXattrsTool().Handler(context.Background(), params)
+		if err != nil {
+			return nil, err
+		}
+		var out struct {
+			Attributes []map[string]interface{} `json:"attributes"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out.Attributes, nil
+	}
+
+	t.Run("set and list", func(t *testing.T) {
+		require.NoError(t, set(map[string]interface{}{"name": "user.origin", "value": "https://example.com/app"}))
+		require.NoError(t, set(map[string]interface{}{"name": "user.blob", "value": "AAH/", "encoding": "base64"}))
+
+		attrs, err := get(map[string]interface{}{})
+		require.NoError(t, err)
+		require.Len(t, attrs, 2)
+		assert.Equal(t, "user.blob", attrs[0]["name"])
+		assert.Equal(t, "base64", attrs[0]["encoding"])
+		assert.Equal(t, "AAH/", attrs[0]["value"])
+		assert.EqualValues(t, 3, attrs[0]["size_bytes"])
+		assert.Equal(t, "user.origin", attrs[1]["name"])
+		assert.Equal(t, "text", attrs[1]["encoding"])
+		assert.Equal(t, "https://example.com/app", attrs[1]["value"])
+	})
+
+	t.Run("get one", func(t *testing.T) {
+		attrs, err := get(map[string]interface{}{"name": "user.origin"})
+		require.NoError(t, err)
+		require.Len(t, attrs, 1)
+		assert.Equal(t, "https://example.com/app", attrs[0]["value"])
+
+		_, err = get(map[string]interface{}{"name": "user.missing"})
+		assert.ErrorIs(t, err, common.ErrNotFound)
+	})
+
+	t.Run("file_info", func(t *testing.T) {
+		result, err := server.fileInfoTool().Handler(context.Background(), map[string]interface{}{"path": file})
+		require.NoError(t, err)
+		var info FileInfo
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &info))
+		assert.Equal(t, map[string]string{"user.origin": "https://example.com/app", "user.blob": "base64:AAH/"}, info.Xattrs)
+		assert.False(t, info.Quarantined)
+	})
+
+	t.Run("remove", func(t *testing.T) {
+		require.NoError(t, set(map[string]interface{}{"name": "user.blob", "remove": true}))
+		attrs, err := get(map[string]interface{}{})
+		require.NoError(t, err)
+		assert.Len(t, attrs, 1)
+
+		err = set(map[string]interface{}{"name": "user.blob", "remove": true})
+		assert.ErrorIs(t, err, common.ErrNotFound)
+	})
+
+	t.Run("invalid base64", func(t *testing.T) {
+		err := set(map[string]interface{}{"name": "user.blob", "value": "not base64!", "encoding": "base64"})
+		assert.Error(t, err)
+	})
+}
+
+func TestIsQuarantined(t *testing.T) {
+	assert.False(t, isQuarantined(nil))
+	assert.True(t, isQuarantined(map[string]string{quarantineXattr: "0081;65a1b2c3;Safari;"}))
+	assert.False(t, isQuarantined(map[string]string{quarantineXattr: "00c1;65a1b2c3;Safari;"}))
+}
@@ -0,0 +1,67 @@
+//go:build linux || darwin
+
+package filesystem
+
+import (
+	"bytes"
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// listXattrs returns the extended attributes of path with their values.
+func listXattrs(path string) (map[string][]byte, error) {
+	size, err := unix.Listxattr(path, nil)
+	if err != nil || size == 0 {
+		return map[string][]byte{}, xattrError(err)
+	}
+	buf := make([]byte, size)
+	if size, err = unix.Listxattr(path, buf); err != nil {
+		return nil, xattrError(err)
+	}
+	attrs := make(map[string][]byte)
+	for _, name := range bytes.Split(buf[:size], []byte{0}) {
+		if len(name) == 0 {
+			continue
+		}
+		value, err := getXattr(path, string(name))
+		if err != nil {
+			// Removed since the list was read, or not readable.
+			continue
+		}
+		attrs[string(name)] = value
+	}
+	return attrs, nil
+}
+
+func getXattr(path, name string) ([]byte, error) {
+	size, err := unix.Getxattr(path, name, nil)
+	if err != nil {
+		return nil, xattrError(err)
+	}
+	buf := make([]byte, size)
+	if size, err = unix.Getxattr(path, name, buf); err != nil {
+		return nil, xattrError(err)
+	}
+	return buf[:size], nil
+}
+
+func setXattr(path, name string, value []byte) error {
+	return xattrError(unix.Setxattr(path, name, value, 0))
+}
+
+func removeXattr(path, name string) error {
+	return xattrError(unix.Removexattr(path, name))
+}
+
+func xattrError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, errNoXattr):
+		return errXattrMissing
+	case errors.Is(err, unix.ENOTSUP):
+		return errXattrUnsupported
+	}
+	return err
+}
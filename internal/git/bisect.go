@@ -0,0 +1,314 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxBisectSteps stops git_bisect_run should a test keep git bisect from
+// converging; halving even a million commits takes 20 steps.
+const maxBisectSteps = 100
+
+var (
+	bisectRemainingRegex = regexp.MustCompile(`Bisecting: (\d+) revisions? left to test after this \(roughly (\d+) steps?\)`)
+	bisectCurrentRegex   = regexp.MustCompile(`(?m)^\[([0-9a-f]+)\] (.*)$`)
+	bisectFoundRegex     = regexp.MustCompile(`(?m)^([0-9a-f]+) is the first bad commit$`)
+)
+
+// bisectState describes where a bisect stands after git bisect start, good,
+// bad or skip printed output.
+func (s *Server) bisectState(repoPath, output string) map[string]interface{} {
+	if m := bisectFoundRegex.FindStringSubmatch(output); m != nil {
+		state := map[string]interface{}{"status": "found"}
+		if details, err := s.runGit(repoPath, "show", "-s", "--format=%H|%h|%an <%ae>|%aI|%s", m[1]); err == nil {
+			if parts := strings.SplitN(details, "|", 5); len(parts) == 5 {
+				state["first_bad_commit"] = map[string]interface{}{
+					"hash":       parts[0],
+					"short_hash": parts[1],
+					"author":     parts[2],
+					"date":       parts[3],
+					"message":    parts[4],
+				}
+			}
+		}
+		return state
+	}
+	if strings.Contains(output, "only 'skip'ped commits left") {
+		return map[string]interface{}{"status": "inconclusive", "output": output}
+	}
+	state := map[string]interface{}{"status": "in_progress"}
+	if m := bisectRemainingRegex.FindStringSubmatch(output); m != nil {
+		state["revisions_left"], _ = strconv.Atoi(m[1])
+		state["steps_left"], _ = strconv.Atoi(m[2])
+	}
+	if m := bisectCurrentRegex.FindStringSubmatch(output); m != nil {
+		state["current_commit"] = m[1]
+		state["current_message"] = m[2]
+	} else {
+		state["output"] = output
+	}
+	return state
+}
+
+// bisecting reports whether a bisect is in progress in the repository.
+func (s *Server) bisecting(repoPath string) bool {
+	path, err := s.runGit(repoPath, "rev-parse", "--git-path", "BISECT_START")
+	if err != nil {
+		return false
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(repoPath, path)
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+func (s *Server) gitBisectStartTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "git_bisect_start",
+		Description: "Start a bisect to find the commit that introduced a bug, between a bad commit and one or more good ones. Checks out the first commit to test; mark it with git_bisect_good or git_bisect_bad, or let git_bisect_run test each step",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"repo_path": mcp.StringProperty("Path to repository"),
+				"bad":       mcp.StringProperty("A commit with the bug (default: HEAD)"),
+				"good":      mcp.ArrayProperty("string", "Commits without the bug"),
+				"paths":     mcp.ArrayProperty("string", "Only consider commits that change these paths"),
+			},
+			[]string{"repo_path", "good"},
+		),
+		Annotations: mcp.Destructive(),
+		Handler:     mcp.TypedHandler(s.handleGitBisectStart),
+	}
+}
+
+type gitBisectStartArgs struct {
+	RepoPath string   `json:"repo_path" validate:"required"`
+	Bad      string   `json:"bad"`
+	Good     []string `json:"good" validate:"required"`
+	Paths    []string `json:"paths"`
+}
+
+func (s *Server) handleGitBisectStart(ctx context.Context, args gitBisectStartArgs) (*mcp.ToolResult, error) {
+	repoPath, bad, good := args.RepoPath, args.Bad, args.Good
+
+	if err := s.validator.ValidatePath(repoPath); err != nil {
+		return nil, err
+	}
+
+	if bad == "" {
+		bad = "HEAD"
+	}
+	for _, ref := range append([]string{bad}, good...) {
+		if strings.HasPrefix(ref, "-") {
+			return nil, fmt.Errorf("%w: refs must not start with -", common.ErrInvalidInput)
+		}
+	}
+	if s.bisecting(repoPath) {
+		return nil, fmt.Errorf("%w: a bisect is already in progress; finish it with git_bisect_reset first", common.ErrInvalidInput)
+	}
+
+	gitArgs := append([]string{"bisect", "start", bad}, good...)
+	gitArgs = append(append(gitArgs, "--"), args.Paths...)
+	output, err := s.runGit(repoPath, gitArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.JSONResult(s.bisectState(repoPath, output))
+}
+
+func (s *Server) gitBisectGoodTool() *mcp.Tool {
+	return s.bisectMarkTool("good", "Mark a commit of the bisect in progress as good, without the bug, and check out the next one to test")
+}
+
+func (s *Server) gitBisectBadTool() *mcp.Tool {
+	return s.bisectMarkTool("bad", "Mark a commit of the bisect in progress as bad, with the bug, and check out the next one to test")
+}
+
+func (s *Server) bisectMarkTool(verdict, description string) *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "git_bisect_" + verdict,
+		Description: description + ". The result names the first bad commit once it is found",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"repo_path": mcp.StringProperty("Path to repository"),
+				"commit":    mcp.StringProperty("Commit to mark (default: the one checked out)"),
+			},
+			[]string{"repo_path"},
+		),
+		Annotations: mcp.Destructive(),
+		Handler: mcp.TypedHandler(func(ctx context.Context, args gitBisectMarkArgs) (*mcp.ToolResult, error) {
+			return s.handleGitBisectMark(ctx, args, verdict)
+		}),
+	}
+}
+
+type gitBisectMarkArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+	Commit   string `json:"commit"`
+}
+
+func (s *Server) handleGitBisectMark(ctx context.Context, args gitBisectMarkArgs, verdict string) (*mcp.ToolResult, error) {
+	repoPath, commit := args.RepoPath, args.Commit
+
+	if err := s.validator.ValidatePath(repoPath); err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(commit, "-") {
+		return nil, fmt.Errorf("%w: commit must not start with -", common.ErrInvalidInput)
+	}
+	if !s.bisecting(repoPath) {
+		return nil, fmt.Errorf("%w: no bisect in progress; start one with git_bisect_start", common.ErrInvalidInput)
+	}
+
+	gitArgs := []string{"bisect", verdict}
+	if commit != "" {
+		gitArgs = append(gitArgs, commit)
+	}
+	output, err := s.runGit(repoPath, gitArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	return mcp.JSONResult(s.bisectState(repoPath, output))
+}
+
+func (s *Server) gitBisectResetTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "git_bisect_reset",
+		Description: "End the bisect in progress and check out the branch it started from",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"repo_path": mcp.StringProperty("Path to repository"),
+			},
+			[]string{"repo_path"},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleGitBisectReset),
+	}
+}
+
+func (s *Server) handleGitBisectReset(ctx context.Context, args repoArgs) (*mcp.ToolResult, error) {
+	repoPath := args.RepoPath
+
+	if err := s.validator.ValidatePath(repoPath); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.runGit(repoPath, "bisect", "reset"); err != nil {
+		return nil, err
+	}
+
+	branch, _ := s.runGit(repoPath, "rev-parse", "--abbrev-ref", "HEAD")
+	return mcp.TextResult(fmt.Sprintf("Bisect ended, checked out %s", branch)), nil
+}
+
+func (s *Server) gitBisectRunTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "git_bisect_run",
+		Description: "Finish the bisect in progress automatically by running a test command at each step, like git bisect run: exit code 0 marks the commit good, 125 skips it, and 1 to 127 mark it bad. Other codes and timeouts stop the run. The command is subject to the command server's policies",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"repo_path":       mcp.StringProperty("Path to repository"),
+				"command":         mcp.StringProperty("Test command, run in the repository"),
+				"args":            mcp.ArrayProperty("string", "Command arguments"),
+				"timeout_seconds": mcp.IntProperty("Timeout of each run of the command"),
+			},
+			[]string{"repo_path", "command"},
+		),
+		Annotations: mcp.Destructive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleGitBisectRun),
+	}
+}
+
+type gitBisectRunArgs struct {
+	RepoPath       string   `json:"repo_path" validate:"required"`
+	Command        string   `json:"command" validate:"required"`
+	Args           []string `json:"args"`
+	TimeoutSeconds int      `json:"timeout_seconds"`
+}
+
+func (s *Server) handleGitBisectRun(ctx context.Context, args gitBisectRunArgs) (*mcp.ToolResult, error) {
+	repoPath, cmd := args.RepoPath, args.Command
+
+	if err := s.validator.ValidatePath(repoPath); err != nil {
+		return nil, err
+	}
+
+	if !s.commandConfig.Enabled {
+		return nil, fmt.Errorf("%w: running commands is disabled (set command.enabled)", common.ErrPermissionDenied)
+	}
+	if err := s.commandValidator.ValidateCommand(cmd, args.Args); err != nil {
+		return nil, err
+	}
+	if !s.bisecting(repoPath) {
+		return nil, fmt.Errorf("%w: no bisect in progress; start one with git_bisect_start", common.ErrInvalidInput)
+	}
+
+	steps := []map[string]interface{}{}
+	for len(steps) < maxBisectSteps {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		commit, err := s.runGit(repoPath, "rev-parse", "--short", "HEAD")
+		if err != nil {
+			return nil, err
+		}
+
+		result, err := s.executor.RunSync(ctx, cmd, args.Args, repoPath, s.env(ctx).Merge(nil), args.TimeoutSeconds)
+		if err != nil {
+			return nil, err
+		}
+
+		verdict := ""
+		switch {
+		case result.ExitCode == 0:
+			verdict = "good"
+		case result.ExitCode == 125:
+			verdict = "skip"
+		case result.ExitCode >= 1 && result.ExitCode <= 127:
+			verdict = "bad"
+		}
+		steps = append(steps, map[string]interface{}{
+			"commit":    commit,
+			"exit_code": result.ExitCode,
+			"verdict":   verdict,
+		})
+		if verdict == "" {
+			return mcp.JSONResult(map[string]interface{}{
+				"status": "stopped",
+				"reason": fmt.Sprintf("the command exited with %d at %s; the bisect is still in progress", result.ExitCode, commit),
+				"stderr": result.Stderr,
+				"steps":  steps,
+			})
+		}
+
+		output, err := s.runGit(repoPath, "bisect", verdict)
+		if err != nil {
+			return nil, err
+		}
+		state := s.bisectState(repoPath, output)
+		if state["status"] != "in_progress" {
+			state["steps"] = steps
+			return mcp.JSONResult(state)
+		}
+		if left, ok := state["steps_left"].(int); ok {
+			mcp.ReportProgress(ctx, float64(len(steps)), float64(len(steps)+left+1), fmt.Sprintf("tested %s: %s", commit, verdict))
+		}
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"status": "stopped",
+		"reason": fmt.Sprintf("gave up after %d steps; the bisect is still in progress", maxBisectSteps),
+		"steps":  steps,
+	})
+}
@@ -0,0 +1,86 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+func TestBisectState(t *testing.T) {
+	server, repo := newTestServer(t)
+
+	t.Run("in progress", func(t *testing.T) {
+		state := server.bisectState(repo, "Bisecting: 3 revisions left to test after this (roughly 2 steps)\n[0123abc] Add parser")
+		assert.Equal(t, map[string]interface{}{
+			"status":          "in_progress",
+			"revisions_left":  3,
+			"steps_left":      2,
+			"current_commit":  "0123abc",
+			"current_message": "Add parser",
+		}, state)
+	})
+
+	t.Run("singular counts", func(t *testing.T) {
+		state := server.bisectState(repo, "Bisecting: 1 revision left to test after this (roughly 1 step)\n[0123abc] Fix")
+		assert.Equal(t, 1, state["revisions_left"])
+		assert.Equal(t, 1, state["steps_left"])
+	})
+
+	t.Run("unrecognized output is passed on", func(t *testing.T) {
+		state := server.bisectState(repo, "status: waiting for both good and bad commits")
+		assert.Equal(t, "in_progress", state["status"])
+		assert.Equal(t, "status: waiting for both good and bad commits", state["output"])
+	})
+
+	t.Run("inconclusive", func(t *testing.T) {
+		output := "There are only 'skip'ped commits left to test.\nThe first bad commit could be any of:\n0123abc"
+		state := server.bisectState(repo, output)
+		assert.Equal(t, "inconclusive", state["status"])
+		assert.Equal(t, output, state["output"])
+	})
+
+	t.Run("found", func(t *testing.T) {
+		newTestRepo(t, repo, map[string]string{"a.txt": "ok\n"}, map[string]string{"a.txt": "bug\n"})
+		hash, err := server.runGit(repo, "rev-parse", "HEAD")
+		require.NoError(t, err)
+
+		state := server.bisectState(repo, hash+" is the first bad commit\ncommit "+hash)
+		assert.Equal(t, "found", state["status"])
+		commit, ok := state["first_bad_commit"].(map[string]interface{})
+		require.True(t, ok)
+		assert.Equal(t, hash, commit["hash"])
+		assert.Equal(t, "Test <test@example.com>", commit["author"])
+		assert.Equal(t, "commit 2", commit["message"])
+	})
+}
+
+func TestGitBisectRunUsesSessionEnv(t *testing.T) {
+	server, repo := newTestServer(t)
+	newTestRepo(t, repo,
+		map[string]string{"a.txt": "ok\n"},
+		map[string]string{"a.txt": "ok\n", "b.txt": "2\n"},
+		map[string]string{"a.txt": "bug\n"})
+	server.sessionEnv = common.NewSessionEnv()
+	server.sessionEnv.Set("BISECT_EXPECT", "ok")
+
+	_, err := server.gitBisectStartTool().Handler(context.Background(), map[string]interface{}{
+		"repo_path": repo, "good": []interface{}{"HEAD~2"},
+	})
+	require.NoError(t, err)
+	defer server.runGit(repo, "bisect", "reset")
+
+	// Without the session variable every commit would test bad.
+	_, err = server.gitBisectRunTool().Handler(context.Background(), map[string]interface{}{
+		"repo_path": repo, "command": "sh", "args": []interface{}{"-c", `grep -qx "$BISECT_EXPECT" a.txt`},
+	})
+	require.NoError(t, err)
+	firstBad, err := server.runGit(repo, "rev-parse", "refs/bisect/bad")
+	require.NoError(t, err)
+	head, err := server.runGit(repo, "rev-parse", "main")
+	require.NoError(t, err)
+	assert.Equal(t, head, firstBad)
+}
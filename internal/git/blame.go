@@ -0,0 +1,89 @@
+package git
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxBlameLines bounds the lines one git_blame call covers when no
+// end_line is given.
+const maxBlameLines = 500
+
+// BlameRange is a run of consecutive lines last changed by the same commit.
+type BlameRange struct {
+	StartLine   int      `json:"start_line"`
+	EndLine     int      `json:"end_line"`
+	Commit      string   `json:"commit"`
+	Author      string   `json:"author"`
+	AuthorEmail string   `json:"author_email"`
+	Date        string   `json:"date"`
+	Summary     string   `json:"summary"`
+	Filename    string   `json:"filename,omitempty"`
+	Lines       []string `json:"lines"`
+}
+
+// parseBlame parses git blame --line-porcelain output, merging consecutive
+// lines of the same commit into one range.
+func parseBlame(output, path string) []BlameRange {
+	ranges := []BlameRange{}
+	var current BlameRange
+	var timestamp int64
+	var tz string
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "\t") {
+			current.Lines = []string{line[1:]}
+			current.EndLine = current.StartLine
+			current.Date = blameDate(timestamp, tz)
+			if current.Filename == path {
+				current.Filename = ""
+			}
+			if n := len(ranges); n > 0 && ranges[n-1].Commit == current.Commit && ranges[n-1].EndLine+1 == current.StartLine && ranges[n-1].Filename == current.Filename {
+				ranges[n-1].EndLine = current.StartLine
+				ranges[n-1].Lines = append(ranges[n-1].Lines, current.Lines[0])
+			} else {
+				ranges = append(ranges, current)
+			}
+			current = BlameRange{}
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-mail":
+			current.AuthorEmail = strings.Trim(value, "<>")
+		case "author-time":
+			timestamp, _ = strconv.ParseInt(value, 10, 64)
+		case "author-tz":
+			tz = value
+		case "summary":
+			current.Summary = value
+		case "filename":
+			current.Filename = value
+		default:
+			// The first line of each entry: commit, original line, final line.
+			if fields := strings.Fields(line); len(fields) >= 3 && (len(fields[0]) == 40 || len(fields[0]) == 64) {
+				current.Commit = fields[0]
+				current.StartLine, _ = strconv.Atoi(fields[2])
+			}
+		}
+	}
+	return ranges
+}
+
+// blameDate formats an author-time and author-tz pair, such as 1700000000
+// and +0100, as RFC 3339.
+func blameDate(timestamp int64, tz string) string {
+	t := time.Unix(timestamp, 0).UTC()
+	if len(tz) == 5 {
+		hours, _ := strconv.Atoi(tz[1:3])
+		minutes, _ := strconv.Atoi(tz[3:5])
+		offset := hours*3600 + minutes*60
+		if tz[0] == '-' {
+			offset = -offset
+		}
+		t = t.In(time.FixedZone(tz, offset))
+	}
+	return t.Format(time.RFC3339)
+}
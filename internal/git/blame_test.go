@@ -0,0 +1,63 @@
+package git
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	blameCommitA = "aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+	blameCommitB = "bbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+)
+
+func blameEntry(commit string, line int, filename, text string) string {
+	return commit + " " + strconv.Itoa(line) + " " + strconv.Itoa(line) + "\n" +
+		"author Ada\n" +
+		"author-mail <ada@example.com>\n" +
+		"author-time 1700000000\n" +
+		"author-tz +0100\n" +
+		"summary Change " + commit[:1] + "\n" +
+		"filename " + filename + "\n" +
+		"\t" + text + "\n"
+}
+
+func TestParseBlame(t *testing.T) {
+	output := blameEntry(blameCommitA, 1, "main.go", "package main") +
+		blameEntry(blameCommitA, 2, "main.go", "") +
+		blameEntry(blameCommitB, 3, "main.go", "func main() {}") +
+		blameEntry(blameCommitA, 4, "old.go", "// moved")
+
+	ranges := parseBlame(output, "main.go")
+	require.Len(t, ranges, 3)
+
+	assert.Equal(t, BlameRange{
+		StartLine:   1,
+		EndLine:     2,
+		Commit:      blameCommitA,
+		Author:      "Ada",
+		AuthorEmail: "ada@example.com",
+		Date:        "2023-11-14T23:13:20+01:00",
+		Summary:     "Change a",
+		Lines:       []string{"package main", ""},
+	}, ranges[0])
+
+	assert.Equal(t, 3, ranges[1].StartLine)
+	assert.Equal(t, 3, ranges[1].EndLine)
+	assert.Equal(t, blameCommitB, ranges[1].Commit)
+
+	// A line from another file is a range of its own, named by filename.
+	assert.Equal(t, 4, ranges[2].StartLine)
+	assert.Equal(t, "old.go", ranges[2].Filename)
+}
+
+func TestParseBlameEmpty(t *testing.T) {
+	assert.Empty(t, parseBlame("", "main.go"))
+}
+
+func TestBlameDate(t *testing.T) {
+	assert.Equal(t, "2023-11-14T22:13:20Z", blameDate(1700000000, ""))
+	assert.Equal(t, "2023-11-14T17:43:20-04:30", blameDate(1700000000, "-0430"))
+}
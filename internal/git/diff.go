@@ -0,0 +1,209 @@
+package git
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+const (
+	// defaultDiffFiles is how many files a page of a structured diff holds.
+	defaultDiffFiles = 20
+	// maxDiffPageBytes bounds the hunk text of a page; a page always holds
+	// at least one file.
+	maxDiffPageBytes = 100000
+)
+
+var hunkHeaderRegex = regexp.MustCompile(`^@@ -(\d+)(?:,(\d+))? \+(\d+)(?:,(\d+))? @@ ?(.*)$`)
+
+type DiffHunk struct {
+	OldStart int      `json:"old_start"`
+	OldLines int      `json:"old_lines"`
+	NewStart int      `json:"new_start"`
+	NewLines int      `json:"new_lines"`
+	Section  string   `json:"section,omitempty"`
+	Lines    []string `json:"lines"`
+}
+
+type DiffFile struct {
+	Path      string     `json:"path"`
+	OldPath   string     `json:"old_path,omitempty"`
+	Status    string     `json:"status"`
+	Binary    bool       `json:"binary,omitempty"`
+	Additions int        `json:"additions"`
+	Deletions int        `json:"deletions"`
+	Hunks     []DiffHunk `json:"hunks"`
+
+	size int
+}
+
+// parseDiff parses unified diff output, such as that of git diff or git
+// show, into files. Anything before the first diff header, like the commit
+// message of git show, is skipped.
+func parseDiff(output string) []*DiffFile {
+	var files []*DiffFile
+	var file *DiffFile
+	var hunk *DiffHunk
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			file = &DiffFile{Status: "modified", Hunks: []DiffHunk{}}
+			file.OldPath, file.Path = splitDiffHeader(strings.TrimPrefix(line, "diff --git "))
+			files = append(files, file)
+			hunk = nil
+			continue
+		}
+		if file == nil {
+			continue
+		}
+		if hunk != nil {
+			switch {
+			case strings.HasPrefix(line, "+"):
+				file.Additions++
+			case strings.HasPrefix(line, "-"):
+				file.Deletions++
+			case strings.HasPrefix(line, " "), strings.HasPrefix(line, `\`):
+			default:
+				hunk = nil
+			}
+			if hunk != nil {
+				hunk.Lines = append(hunk.Lines, line)
+				file.size += len(line) + 1
+				continue
+			}
+		}
+		if m := hunkHeaderRegex.FindStringSubmatch(line); m != nil {
+			file.Hunks = append(file.Hunks, DiffHunk{
+				OldStart: atoi(m[1]),
+				OldLines: hunkLength(m[2]),
+				NewStart: atoi(m[3]),
+				NewLines: hunkLength(m[4]),
+				Section:  m[5],
+				Lines:    []string{},
+			})
+			hunk = &file.Hunks[len(file.Hunks)-1]
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "new file mode"):
+			file.Status = "added"
+		case strings.HasPrefix(line, "deleted file mode"):
+			file.Status = "deleted"
+		case strings.HasPrefix(line, "rename from "):
+			file.Status = "renamed"
+			file.OldPath = strings.TrimPrefix(line, "rename from ")
+		case strings.HasPrefix(line, "rename to "):
+			file.Path = strings.TrimPrefix(line, "rename to ")
+		case strings.HasPrefix(line, "copy from "):
+			file.Status = "copied"
+			file.OldPath = strings.TrimPrefix(line, "copy from ")
+		case strings.HasPrefix(line, "copy to "):
+			file.Path = strings.TrimPrefix(line, "copy to ")
+		case strings.HasPrefix(line, "--- a/"):
+			file.OldPath = trimDiffPath(line, "--- a/")
+		case strings.HasPrefix(line, "+++ b/"):
+			file.Path = trimDiffPath(line, "+++ b/")
+		case strings.HasPrefix(line, "Binary files "):
+			file.Binary = true
+		}
+	}
+	for _, f := range files {
+		if f.OldPath == f.Path || f.Status == "added" {
+			f.OldPath = ""
+		}
+	}
+	return files
+}
+
+// splitDiffHeader splits the "a/old b/new" of a diff header. Paths with
+// spaces are ambiguous there, so the ---, +++ and rename lines that follow
+// take precedence; the header only names binary and mode-only changes.
+func splitDiffHeader(header string) (string, string) {
+	if i := strings.Index(header, " b/"); i >= 0 && strings.HasPrefix(header, "a/") {
+		return header[2:i], header[i+3:]
+	}
+	return header, header
+}
+
+// trimDiffPath returns the path of a --- or +++ line, which git ends with a
+// tab when the path contains a space.
+func trimDiffPath(line, prefix string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(line, prefix), "\t")
+}
+
+func hunkLength(s string) int {
+	if s == "" {
+		return 1
+	}
+	return atoi(s)
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
+
+// diffRange builds the revision range of a diff between refs. base may
+// already be a range, in which case head must be empty.
+func diffRange(base, head string, mergeBase bool) (string, error) {
+	if strings.HasPrefix(base, "-") || strings.HasPrefix(head, "-") {
+		return "", fmt.Errorf("%w: refs must not start with -", common.ErrInvalidInput)
+	}
+	if strings.Contains(base, "..") {
+		if head != "" {
+			return "", fmt.Errorf("%w: head cannot be given when base is a range", common.ErrInvalidInput)
+		}
+		return base, nil
+	}
+	if head == "" {
+		head = "HEAD"
+	}
+	if mergeBase {
+		return base + "..." + head, nil
+	}
+	return base + ".." + head, nil
+}
+
+// structuredDiff parses the output of a diff command and returns the page of
+// files from offset.
+func (s *Server) structuredDiff(repoPath string, args []string, offset, maxFiles int) (*mcp.ToolResult, error) {
+	args = append([]string{"-c", "core.quotePath=false", args[0], "--no-color", "--no-ext-diff", "-M"}, args[1:]...)
+	output, err := s.runGit(repoPath, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	files := parseDiff(output)
+	additions, deletions := 0, 0
+	for _, f := range files {
+		additions += f.Additions
+		deletions += f.Deletions
+	}
+
+	if maxFiles <= 0 {
+		maxFiles = defaultDiffFiles
+	}
+	page := []*DiffFile{}
+	size := 0
+	for i := offset; i < len(files) && len(page) < maxFiles; i++ {
+		if len(page) > 0 && size+files[i].size > maxDiffPageBytes {
+			break
+		}
+		page = append(page, files[i])
+		size += files[i].size
+	}
+
+	result := map[string]interface{}{
+		"files":       page,
+		"total_files": len(files),
+		"additions":   additions,
+		"deletions":   deletions,
+	}
+	if next := offset + len(page); next < len(files) {
+		result["next_offset"] = next
+	}
+	return mcp.JSONResult(result)
+}
@@ -0,0 +1,155 @@
+package git
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+const sampleDiff = `commit 0123456789abcdef0123456789abcdef01234567
+Author: Test <test@example.com>
+
+    Change things
+
+diff --git a/main.go b/main.go
+index 1111111..2222222 100644
+--- a/main.go
++++ b/main.go
+@@ -1,4 +1,5 @@ package main
+ package main
+ 
+-func old() {}
++func new() {}
++func extra() {}
+ // end
+\ No newline at end of file
+@@ -10 +11 @@ func tail() {
+-	return 1
++	return 2
+diff --git a/added.txt b/added.txt
+new file mode 100644
+index 0000000..3333333
+--- /dev/null
++++ b/added.txt
+@@ -0,0 +1 @@
++hello
+diff --git a/gone.txt b/gone.txt
+deleted file mode 100644
+index 4444444..0000000
+--- a/gone.txt
++++ /dev/null
+@@ -1 +0,0 @@
+-bye
+diff --git a/old name.txt b/new name.txt
+similarity 90%
+rename from old name.txt
+rename to new name.txt
+diff --git a/logo.png b/logo.png
+index 5555555..6666666 100644
+Binary files a/logo.png and b/logo.png differ
+`
+
+func TestParseDiff(t *testing.T) {
+	files := parseDiff(sampleDiff)
+	require.Len(t, files, 5)
+
+	mainGo := files[0]
+	assert.Equal(t, "main.go", mainGo.Path)
+	assert.Empty(t, mainGo.OldPath)
+	assert.Equal(t, "modified", mainGo.Status)
+	assert.Equal(t, 3, mainGo.Additions)
+	assert.Equal(t, 2, mainGo.Deletions)
+	require.Len(t, mainGo.Hunks, 2)
+	assert.Equal(t, DiffHunk{
+		OldStart: 1, OldLines: 4, NewStart: 1, NewLines: 5, Section: "package main",
+		Lines: []string{" package main", " ", "-func old() {}", "+func new() {}", "+func extra() {}", " // end", `\ No newline at end of file`},
+	}, mainGo.Hunks[0])
+	assert.Equal(t, 10, mainGo.Hunks[1].OldStart)
+	assert.Equal(t, 1, mainGo.Hunks[1].OldLines, "a missing length means one line")
+	assert.Equal(t, 11, mainGo.Hunks[1].NewStart)
+	assert.Equal(t, "func tail() {", mainGo.Hunks[1].Section)
+
+	assert.Equal(t, "added", files[1].Status)
+	assert.Equal(t, "added.txt", files[1].Path)
+	assert.Empty(t, files[1].OldPath)
+	assert.Equal(t, 1, files[1].Additions)
+
+	assert.Equal(t, "deleted", files[2].Status)
+	assert.Equal(t, "gone.txt", files[2].Path)
+	assert.Equal(t, 1, files[2].Deletions)
+
+	assert.Equal(t, "renamed", files[3].Status)
+	assert.Equal(t, "new name.txt", files[3].Path)
+	assert.Equal(t, "old name.txt", files[3].OldPath)
+	assert.Empty(t, files[3].Hunks)
+
+	assert.True(t, files[4].Binary)
+	assert.Equal(t, "logo.png", files[4].Path)
+}
+
+func TestParseDiffEmpty(t *testing.T) {
+	assert.Empty(t, parseDiff(""))
+	assert.Empty(t, parseDiff("commit abc\n\n    message only\n"))
+}
+
+func TestDiffRange(t *testing.T) {
+	tests := []struct {
+		base, head string
+		mergeBase  bool
+		want       string
+		wantErr    bool
+	}{
+		{base: "main", mergeBase: true, want: "main...HEAD"},
+		{base: "main", head: "feature", want: "main..feature"},
+		{base: "main...feature", want: "main...feature"},
+		{base: "main..feature", head: "other", wantErr: true},
+		{base: "--output=x", wantErr: true},
+		{base: "main", head: "-p", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := diffRange(tt.base, tt.head, tt.mergeBase)
+		if tt.wantErr {
+			assert.ErrorIs(t, err, common.ErrInvalidInput, "%s %s", tt.base, tt.head)
+			continue
+		}
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, got)
+	}
+}
+
+func TestStructuredDiffPages(t *testing.T) {
+	server, repo := newTestServer(t)
+	newTestRepo(t, repo, map[string]string{"a.txt": "a\n", "b.txt": "b\n", "c.txt": "c\n"})
+	for _, name := range []string{"a.txt", "b.txt", "c.txt"} {
+		require.NoError(t, os.WriteFile(filepath.Join(repo, name), []byte(name+" changed\n"), 0644))
+	}
+
+	page := func(offset int) map[string]interface{} {
+		result, err := server.gitDiffTool().Handler(context.Background(), map[string]interface{}{
+			"repo_path": repo, "format": "structured", "offset": float64(offset), "max_files": 2.0,
+		})
+		require.NoError(t, err)
+		var out map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(result.Content[0].Text), &out))
+		return out
+	}
+
+	first := page(0)
+	assert.Len(t, first["files"], 2)
+	assert.Equal(t, 3.0, first["total_files"])
+	assert.Equal(t, 3.0, first["additions"])
+	assert.Equal(t, 2.0, first["next_offset"])
+
+	last := page(2)
+	assert.Len(t, last["files"], 1)
+	assert.NotContains(t, last, "next_offset")
+
+	assert.Empty(t, page(10)["files"])
+}
@@ -0,0 +1,184 @@
+package git
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxGrepLineLength bounds each matched line git_grep returns.
+const maxGrepLineLength = 500
+
+func (s *Server) gitGrepTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "git_grep",
+		Description: "Search tracked files, in the working tree or at a ref, for a pattern. The pickaxe modes search history instead, for the commits that added or removed a string (pickaxe, git log -S) or whose changed lines match a regex (pickaxe_regex, git log -G)",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"repo_path":     mcp.StringProperty("Path to repository"),
+				"pattern":       mcp.StringProperty("Regular expression, or a plain string with fixed_strings"),
+				"mode":          mcp.StringProperty("Mode: content (default), pickaxe, pickaxe_regex"),
+				"ref":           mcp.StringProperty("Branch, tag or commit to search (default: the working tree for content, HEAD for pickaxe)"),
+				"paths":         mcp.ArrayProperty("string", "Only search these files or directories"),
+				"ignore_case":   mcp.BoolProperty("Case-insensitive search"),
+				"fixed_strings": mcp.BoolProperty("Content mode: treat pattern as a plain string"),
+				"max_results":   mcp.IntProperty("Maximum matching lines, or commits in pickaxe modes (default: 200)"),
+			},
+			[]string{"repo_path", "pattern"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGitGrep),
+	}
+}
+
+type gitGrepArgs struct {
+	RepoPath     string   `json:"repo_path" validate:"required"`
+	Pattern      string   `json:"pattern" validate:"required"`
+	Mode         string   `json:"mode" default:"content" validate:"oneof=content pickaxe pickaxe_regex"`
+	Ref          string   `json:"ref"`
+	Paths        []string `json:"paths"`
+	IgnoreCase   bool     `json:"ignore_case"`
+	FixedStrings bool     `json:"fixed_strings"`
+	MaxResults   int      `json:"max_results" default:"200"`
+}
+
+func (s *Server) handleGitGrep(ctx context.Context, args gitGrepArgs) (*mcp.ToolResult, error) {
+	repoPath, pattern, mode, ref, paths := args.RepoPath, args.Pattern, args.Mode, args.Ref, args.Paths
+	ignoreCase, fixedStrings, maxResults := args.IgnoreCase, args.FixedStrings, args.MaxResults
+
+	if err := s.validator.ValidatePath(repoPath); err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(ref, "-") {
+		return nil, &mcp.ParamError{Param: "ref", Message: "ref must not start with -"}
+	}
+	if maxResults <= 0 {
+		maxResults = 200
+	}
+
+	if mode == "content" {
+		return s.grepContent(repoPath, pattern, ref, paths, ignoreCase, fixedStrings, maxResults)
+	}
+	return s.grepHistory(repoPath, pattern, mode == "pickaxe_regex", ref, paths, ignoreCase, maxResults)
+}
+
+func (s *Server) grepContent(repoPath, pattern, ref string, paths []string, ignoreCase, fixedStrings bool, maxResults int) (*mcp.ToolResult, error) {
+	args := []string{"-c", "core.quotePath=false", "grep", "-n", "-I", "-z", "--no-color", "-E"}
+	if ignoreCase {
+		args = append(args, "-i")
+	}
+	if fixedStrings {
+		args = append(args, "-F")
+	}
+	args = append(args, "-e", pattern)
+	if ref != "" {
+		args = append(args, ref)
+	}
+	args = append(append(args, "--"), paths...)
+
+	output, err := s.runGit(repoPath, args...)
+	if err != nil && !noMatches(err) {
+		return nil, err
+	}
+
+	matches := []map[string]interface{}{}
+	files := make(map[string]bool)
+	total := 0
+	for _, line := range strings.Split(output, "\n") {
+		// file NUL line NUL text; with a ref, the file is prefixed by "ref:".
+		parts := strings.SplitN(line, "\x00", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		total++
+		file := strings.TrimPrefix(parts[0], ref+":")
+		files[file] = true
+		if len(matches) >= maxResults {
+			continue
+		}
+		lineNum, _ := strconv.Atoi(parts[1])
+		text := parts[2]
+		if len(text) > maxGrepLineLength {
+			text = text[:maxGrepLineLength] + "..."
+		}
+		matches = append(matches, map[string]interface{}{
+			"file": file,
+			"line": lineNum,
+			"text": text,
+		})
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"matches":     matches,
+		"total_count": total,
+		"file_count":  len(files),
+		"truncated":   total > len(matches),
+	})
+}
+
+func (s *Server) grepHistory(repoPath, pattern string, regex bool, ref string, paths []string, ignoreCase bool, maxResults int) (*mcp.ToolResult, error) {
+	args := []string{"-c", "core.quotePath=false", "log", historyFormat, "--name-only", fmt.Sprintf("-n%d", maxResults+1)}
+	if ignoreCase {
+		args = append(args, "--regexp-ignore-case")
+	}
+	if regex {
+		args = append(args, "-G", pattern)
+	} else {
+		args = append(args, "-S", pattern)
+	}
+	if ref != "" {
+		args = append(args, ref)
+	}
+	args = append(append(args, "--"), paths...)
+
+	output, err := s.runGit(repoPath, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := []map[string]interface{}{}
+	truncated := false
+	for _, record := range strings.Split(output, "\x1e")[1:] {
+		if len(commits) == maxResults {
+			truncated = true
+			break
+		}
+		header, body, _ := strings.Cut(record, "\n")
+		parts := strings.SplitN(header, "\x1f", 5)
+		if len(parts) != 5 {
+			continue
+		}
+		files := []string{}
+		for _, f := range strings.Split(strings.TrimSpace(body), "\n") {
+			if f != "" {
+				files = append(files, f)
+			}
+		}
+		commits = append(commits, map[string]interface{}{
+			"hash":       parts[0],
+			"short_hash": parts[1],
+			"author":     parts[2],
+			"date":       parts[3],
+			"message":    parts[4],
+			"files":      files,
+		})
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"commits":   commits,
+		"truncated": truncated,
+	})
+}
+
+// noMatches reports whether err is git grep finding nothing, which it
+// signals by exiting with status 1.
+func noMatches(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr) && exitErr.ExitCode() == 1
+}
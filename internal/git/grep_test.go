@@ -0,0 +1,32 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func TestGitGrepParamErrors(t *testing.T) {
+	server, repo := newTestServer(t)
+	newTestRepo(t, repo, map[string]string{"a.txt": "needle\n"})
+
+	for param, params := range map[string]map[string]interface{}{
+		"ref":  {"repo_path": repo, "pattern": "needle", "ref": "--output=x"},
+		"mode": {"repo_path": repo, "pattern": "needle", "mode": "blame"},
+	} {
+		_, err := server.gitGrepTool().Handler(context.Background(), params)
+		var paramErr *mcp.ParamError
+		require.ErrorAs(t, err, &paramErr, param)
+		assert.Equal(t, param, paramErr.Param)
+	}
+
+	result, err := server.gitGrepTool().Handler(context.Background(), map[string]interface{}{
+		"repo_path": repo, "pattern": "needle",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].Text, "a.txt")
+}
@@ -0,0 +1,166 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// maxHistoryPatchBytes bounds the patch of each commit git_file_history
+// returns.
+const maxHistoryPatchBytes = 20000
+
+// historyFormat starts each commit with a record separator so its patch or
+// file status can follow it.
+const historyFormat = "--format=\x1e%H\x1f%h\x1f%an <%ae>\x1f%aI\x1f%s"
+
+func (s *Server) gitFileHistoryTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "git_file_history",
+		Description: "List the commits that changed a file, newest first, following it across renames. Optionally include each commit's patch to the file",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"repo_path":     mcp.StringProperty("Path to repository"),
+				"path":          mcp.StringProperty("File path, relative to the repository"),
+				"ref":           mcp.StringProperty("Branch or commit to start from (default: HEAD)"),
+				"follow":        mcp.BoolProperty("Follow the file across renames (default: true)"),
+				"include_patch": mcp.BoolProperty("Include the change each commit made to the file"),
+				"max_commits":   mcp.IntProperty("Maximum commits to return (default: 20)"),
+				"skip":          mcp.IntProperty("Commits to skip, the next_skip of the previous page"),
+			},
+			[]string{"repo_path", "path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGitFileHistory),
+	}
+}
+
+type gitFileHistoryArgs struct {
+	RepoPath     string `json:"repo_path" validate:"required"`
+	Path         string `json:"path" validate:"required"`
+	Ref          string `json:"ref"`
+	Follow       bool   `json:"follow" default:"true"`
+	IncludePatch bool   `json:"include_patch"`
+	MaxCommits   int    `json:"max_commits" default:"20"`
+	Skip         int    `json:"skip"`
+}
+
+func (s *Server) handleGitFileHistory(ctx context.Context, args gitFileHistoryArgs) (*mcp.ToolResult, error) {
+	repoPath, path, ref, follow := args.RepoPath, args.Path, args.Ref, args.Follow
+	includePatch, maxCommits, skip := args.IncludePatch, args.MaxCommits, args.Skip
+
+	if err := s.validator.ValidatePath(repoPath); err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(ref, "-") {
+		return nil, &mcp.ParamError{Param: "ref", Message: "ref must not start with -"}
+	}
+	if maxCommits <= 0 {
+		maxCommits = 20
+	}
+
+	if skip < 0 {
+		skip = 0
+	}
+
+	// git applies --skip before --follow filters commits, so skip them here.
+	// One more commit than the page holds tells whether there are more.
+	gitArgs := []string{"-c", "core.quotePath=false", "log", historyFormat, "-M", fmt.Sprintf("-n%d", skip+maxCommits+1)}
+	if follow {
+		gitArgs = append(gitArgs, "--follow")
+	}
+	if includePatch {
+		gitArgs = append(gitArgs, "-p", "--no-color", "--no-ext-diff")
+	} else {
+		gitArgs = append(gitArgs, "--name-status")
+	}
+	if ref != "" {
+		gitArgs = append(gitArgs, ref)
+	}
+	gitArgs = append(gitArgs, "--", path)
+
+	output, err := s.runGit(repoPath, gitArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := []map[string]interface{}{}
+	more := false
+	records := strings.Split(output, "\x1e")[1:]
+	if skip > len(records) {
+		skip = len(records)
+	}
+	for _, record := range records[skip:] {
+		if len(commits) == maxCommits {
+			more = true
+			break
+		}
+		header, body, _ := strings.Cut(record, "\n")
+		parts := strings.SplitN(header, "\x1f", 5)
+		if len(parts) != 5 {
+			continue
+		}
+		commit := map[string]interface{}{
+			"hash":       parts[0],
+			"short_hash": parts[1],
+			"author":     parts[2],
+			"date":       parts[3],
+			"message":    parts[4],
+		}
+		body = strings.TrimSpace(body)
+		if includePatch {
+			if files := parseDiff(body); len(files) > 0 {
+				commit["path"] = files[0].Path
+				commit["status"] = files[0].Status
+				if files[0].OldPath != "" {
+					commit["old_path"] = files[0].OldPath
+				}
+			}
+			if len(body) > maxHistoryPatchBytes {
+				body = body[:maxHistoryPatchBytes] + "\n... (truncated)"
+			}
+			commit["patch"] = body
+		} else if fields := strings.Split(body, "\t"); len(fields) >= 2 {
+			commit["path"] = fields[len(fields)-1]
+			commit["status"] = nameStatus(fields[0])
+			if len(fields) == 3 {
+				commit["old_path"] = fields[1]
+			}
+		}
+		commits = append(commits, commit)
+	}
+
+	result := map[string]interface{}{
+		"path":    path,
+		"commits": commits,
+	}
+	if more {
+		result["next_skip"] = skip + len(commits)
+	}
+	return mcp.JSONResult(result)
+}
+
+// nameStatus names the change of a --name-status letter, which for renames
+// and copies is followed by a similarity score.
+func nameStatus(code string) string {
+	if code == "" {
+		return "modified"
+	}
+	switch code[:1] {
+	case "A":
+		return "added"
+	case "D":
+		return "deleted"
+	case "R":
+		return "renamed"
+	case "C":
+		return "copied"
+	case "T":
+		return "type_changed"
+	default:
+		return "modified"
+	}
+}
@@ -0,0 +1,23 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func TestGitFileHistoryRejectsOptionRef(t *testing.T) {
+	server, repo := newTestServer(t)
+	newTestRepo(t, repo, map[string]string{"a.txt": "1\n"})
+
+	_, err := server.gitFileHistoryTool().Handler(context.Background(), map[string]interface{}{
+		"repo_path": repo, "path": "a.txt", "ref": "--output=x",
+	})
+	var paramErr *mcp.ParamError
+	require.ErrorAs(t, err, &paramErr)
+	assert.Equal(t, "ref", paramErr.Param)
+}
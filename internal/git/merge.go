@@ -0,0 +1,226 @@
+package git
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+const (
+	// maxConflictHunks and maxConflictHunkLines bound the preview of each
+	// conflicted file.
+	maxConflictHunks     = 10
+	maxConflictHunkLines = 20
+)
+
+// unmergedStates describes the two-letter porcelain status of a conflicted
+// path.
+var unmergedStates = map[string]string{
+	"UU": "both modified",
+	"AA": "both added",
+	"DD": "both deleted",
+	"AU": "added by us",
+	"UA": "added by them",
+	"DU": "deleted by us",
+	"UD": "deleted by them",
+}
+
+type conflictHunk struct {
+	StartLine int      `json:"start_line"`
+	Ours      []string `json:"ours"`
+	Theirs    []string `json:"theirs"`
+	Truncated bool     `json:"truncated,omitempty"`
+}
+
+type conflictFile struct {
+	Path   string         `json:"path"`
+	State  string         `json:"state"`
+	Hunks  []conflictHunk `json:"hunks"`
+	Hidden int            `json:"hidden_hunks,omitempty"`
+}
+
+func (s *Server) gitMergeTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "git_merge",
+		Description: "Merge a branch into the current branch. On conflict the merge is left in progress and the result lists the conflicted files with previews of each conflict; resolve them and commit, or call again with abort",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"repo_path": mcp.StringProperty("Path to repository"),
+				"branch":    mcp.StringProperty("Branch, tag or commit to merge"),
+				"mode":      mcp.StringProperty("Mode: ff (default, fast-forward when possible), ff-only, no-ff, squash"),
+				"message":   mcp.StringProperty("Merge commit message"),
+				"abort":     mcp.BoolProperty("Abort the merge in progress instead of starting one"),
+			},
+			[]string{"repo_path"},
+		),
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleGitMerge),
+	}
+}
+
+type gitMergeArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+	Branch   string `json:"branch"`
+	Mode     string `json:"mode"`
+	Message  string `json:"message"`
+	Abort    bool   `json:"abort"`
+}
+
+func (s *Server) handleGitMerge(ctx context.Context, args gitMergeArgs) (*mcp.ToolResult, error) {
+	repoPath, branch, mode, message, abort := args.RepoPath, args.Branch, args.Mode, args.Message, args.Abort
+
+	if err := s.validator.ValidatePath(repoPath); err != nil {
+		return nil, err
+	}
+
+	if abort {
+		if _, err := s.runGit(repoPath, "merge", "--abort"); err != nil {
+			return nil, err
+		}
+		return mcp.JSONResult(map[string]interface{}{"status": "aborted"})
+	}
+
+	if branch == "" {
+		return nil, &mcp.ParamError{Param: "branch", Message: "branch is required unless abort is set"}
+	}
+	if strings.HasPrefix(branch, "-") {
+		return nil, &mcp.ParamError{Param: "branch", Message: "branch must not start with -"}
+	}
+
+	gitArgs := []string{"merge"}
+	switch mode {
+	case "", "ff":
+	case "ff-only":
+		gitArgs = append(gitArgs, "--ff-only")
+	case "no-ff":
+		gitArgs = append(gitArgs, "--no-ff")
+	case "squash":
+		gitArgs = append(gitArgs, "--squash")
+	default:
+		return nil, fmt.Errorf("%w: invalid mode: %s (must be ff, ff-only, no-ff, or squash)", common.ErrInvalidInput, mode)
+	}
+	if message != "" {
+		gitArgs = append(gitArgs, "-m", message)
+	} else {
+		gitArgs = append(gitArgs, "--no-edit")
+	}
+	gitArgs = append(gitArgs, branch)
+
+	output, mergeErr := s.runGit(repoPath, gitArgs...)
+	if mergeErr != nil {
+		conflicts, err := s.mergeConflicts(repoPath)
+		if err != nil || len(conflicts) == 0 {
+			return nil, mergeErr
+		}
+		return mcp.JSONResult(map[string]interface{}{
+			"status":    "conflict",
+			"conflicts": conflicts,
+			"message":   fmt.Sprintf("%d file(s) have conflicts; resolve them, git_add the files and git_commit, or call git_merge with abort", len(conflicts)),
+		})
+	}
+
+	status := "merged"
+	switch {
+	case strings.Contains(output, "Already up to date"):
+		status = "up_to_date"
+	case mode == "squash":
+		status = "squashed"
+	case strings.Contains(output, "Fast-forward"):
+		status = "fast_forward"
+	}
+
+	head, _ := s.runGit(repoPath, "rev-parse", "--short", "HEAD")
+
+	return mcp.JSONResult(map[string]interface{}{
+		"status": status,
+		"head":   head,
+		"output": output,
+	})
+}
+
+// mergeConflicts lists the unmerged paths of the work tree with previews of
+// their conflict markers.
+func (s *Server) mergeConflicts(repoPath string) ([]conflictFile, error) {
+	// -z leaves paths unquoted, so non-ASCII names match the files.
+	status, err := s.runGit(repoPath, "status", "--porcelain", "-z")
+	if err != nil {
+		return nil, err
+	}
+
+	conflicts := []conflictFile{}
+	entries := strings.Split(status, "\x00")
+	for i := 0; i < len(entries); i++ {
+		line := entries[i]
+		if len(line) < 4 {
+			continue
+		}
+		// A rename or copy is followed by its source path.
+		if line[0] == 'R' || line[0] == 'C' {
+			i++
+		}
+		state, ok := unmergedStates[line[:2]]
+		if !ok {
+			continue
+		}
+		file := conflictFile{Path: line[3:], State: state, Hunks: []conflictHunk{}}
+		if hunks, err := conflictHunks(filepath.Join(repoPath, file.Path)); err == nil {
+			if len(hunks) > maxConflictHunks {
+				file.Hidden = len(hunks) - maxConflictHunks
+				hunks = hunks[:maxConflictHunks]
+			}
+			file.Hunks = hunks
+		}
+		conflicts = append(conflicts, file)
+	}
+	return conflicts, nil
+}
+
+// conflictHunks parses the conflict markers of a file. The base section of
+// diff3-style conflicts is skipped.
+func conflictHunks(path string) ([]conflictHunk, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var hunks []conflictHunk
+	var current *conflictHunk
+	side := ""
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "<<<<<<<"):
+			current = &conflictHunk{StartLine: n, Ours: []string{}, Theirs: []string{}}
+			side = "ours"
+		case current == nil:
+		case strings.HasPrefix(line, "|||||||"):
+			side = "base"
+		case strings.HasPrefix(line, "======="):
+			side = "theirs"
+		case strings.HasPrefix(line, ">>>>>>>"):
+			hunks = append(hunks, *current)
+			current = nil
+		case side == "ours":
+			current.Ours, current.Truncated = appendPreview(current.Ours, line, current.Truncated)
+		case side == "theirs":
+			current.Theirs, current.Truncated = appendPreview(current.Theirs, line, current.Truncated)
+		}
+	}
+	return hunks, scanner.Err()
+}
+
+func appendPreview(lines []string, line string, truncated bool) ([]string, bool) {
+	if len(lines) >= maxConflictHunkLines {
+		return lines, true
+	}
+	return append(lines, line), truncated
+}
@@ -0,0 +1,56 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func TestGitMergeRejectsOptions(t *testing.T) {
+	server, repo := newTestServer(t)
+	newTestRepo(t, repo, map[string]string{"a.txt": "1\n"})
+
+	for _, branch := range []string{"--abort", "-s", "--into-name=x"} {
+		_, err := server.gitMergeTool().Handler(context.Background(), map[string]interface{}{
+			"repo_path": repo, "branch": branch,
+		})
+		var paramErr *mcp.ParamError
+		require.ErrorAs(t, err, &paramErr, branch)
+		assert.Equal(t, "branch", paramErr.Param)
+	}
+}
+
+func TestMergeConflictsNonASCIIPath(t *testing.T) {
+	server, repo := newTestServer(t)
+	newTestRepo(t, repo, map[string]string{"café.txt": "base\n"})
+	for _, name := range []string{"AUTHOR", "COMMITTER"} {
+		t.Setenv("GIT_"+name+"_NAME", "Test")
+		t.Setenv("GIT_"+name+"_EMAIL", "test@example.com")
+	}
+	git := func(args ...string) error {
+		_, err := server.runGit(repo, args...)
+		return err
+	}
+	commit := func(content string) {
+		require.NoError(t, os.WriteFile(filepath.Join(repo, "café.txt"), []byte(content), 0644))
+		require.NoError(t, git("commit", "-q", "-am", content))
+	}
+
+	require.NoError(t, git("checkout", "-q", "-b", "other"))
+	commit("theirs\n")
+	require.NoError(t, git("checkout", "-q", "main"))
+	commit("ours\n")
+	require.Error(t, git("merge", "other"))
+
+	conflicts, err := server.mergeConflicts(repo)
+	require.NoError(t, err)
+	require.Len(t, conflicts, 1)
+	assert.Equal(t, "café.txt", conflicts[0].Path)
+	assert.NotEmpty(t, conflicts[0].Hunks)
+}
@@ -0,0 +1,102 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func (s *Server) gitResetTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "git_reset",
+		Description: "Move the current branch to a commit. soft keeps changes staged, mixed (default) keeps them unstaged, and hard discards them; hard requires git.allow_destructive. The result lists the commits moved off the branch and, for hard, the files whose changes were discarded. Use dry_run to see them first",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"repo_path": mcp.StringProperty("Path to repository"),
+				"target":    mcp.StringProperty("Commit to reset to (default: HEAD)"),
+				"mode":      mcp.StringProperty("Mode: soft, mixed, hard (default: mixed)"),
+				"dry_run":   mcp.BoolProperty("Only report what the reset would do"),
+			},
+			[]string{"repo_path"},
+		),
+		Annotations: mcp.Destructive(),
+		Handler:     mcp.TypedHandler(s.handleGitReset),
+	}
+}
+
+type gitResetArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+	Target   string `json:"target"`
+	Mode     string `json:"mode"`
+	DryRun   bool   `json:"dry_run"`
+}
+
+func (s *Server) handleGitReset(ctx context.Context, args gitResetArgs) (*mcp.ToolResult, error) {
+	repoPath, target, mode, dryRun := args.RepoPath, args.Target, args.Mode, args.DryRun
+
+	if err := s.validator.ValidatePath(repoPath); err != nil {
+		return nil, err
+	}
+
+	if target == "" {
+		target = "HEAD"
+	}
+	if mode == "" {
+		mode = "mixed"
+	}
+	validModes := map[string]bool{"soft": true, "mixed": true, "hard": true}
+	if !validModes[mode] {
+		return nil, fmt.Errorf("%w: invalid mode: %s (must be soft, mixed, or hard)", common.ErrInvalidInput, mode)
+	}
+	if mode == "hard" && !s.config.AllowDestructive {
+		return nil, fmt.Errorf("%w: hard reset is disabled (set git.allow_destructive)", common.ErrPermissionDenied)
+	}
+
+	commit, err := s.runGit(repoPath, "rev-parse", "--verify", "--quiet", target+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("%w: commit %s", common.ErrNotFound, target)
+	}
+
+	// Commits reachable from HEAD but not from the target leave the branch.
+	dropped := []string{}
+	if output, err := s.runGit(repoPath, "log", "--format=%h %s", commit+"..HEAD"); err == nil && output != "" {
+		dropped = strings.Split(output, "\n")
+	}
+
+	discarded := []string{}
+	if mode == "hard" {
+		output, err := s.runGit(repoPath, "diff", "--name-only", "HEAD")
+		if err != nil {
+			return nil, err
+		}
+		if output != "" {
+			discarded = strings.Split(output, "\n")
+		}
+	}
+
+	result := map[string]interface{}{
+		"mode":              mode,
+		"target":            commit,
+		"dropped_commits":   dropped,
+		"discarded_changes": discarded,
+		"dry_run":           dryRun,
+	}
+	if dryRun {
+		return mcp.JSONResult(result)
+	}
+
+	if mode == "hard" && len(discarded) > 0 {
+		if err := common.Confirm(ctx, fmt.Sprintf("Hard reset %s to %s? Uncommitted changes to %d file(s) will be lost.", repoPath, target, len(discarded))); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.runGit(repoPath, "reset", "--"+mode, commit); err != nil {
+		return nil, err
+	}
+
+	return mcp.JSONResult(result)
+}
@@ -1,7 +1,10 @@
 package git
 
 import (
+	"context"
+
 	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/command"
 	"github.com/local-mcps/dev-mcps/internal/common"
 	"github.com/local-mcps/dev-mcps/pkg/mcp"
 )
@@ -10,29 +13,87 @@ type Server struct {
 	config    *config.GitConfig
 	validator *common.PathValidator
 	logger    *common.Logger
+
+	// git_bisect_run runs its test command under the command server's
+	// configuration and policies, and with its session environment.
+	commandConfig    *config.CommandConfig
+	commandValidator *common.CommandValidator
+	executor         *command.Executor
+	sessionEnv       *common.SessionEnv
 }
 
-func NewServer(cfg *config.GitConfig) *Server {
-	return &Server{
-		config:    cfg,
-		validator: common.NewPathValidator(cfg.AllowedRepositories, nil, true),
-		logger:    common.NewLogger(common.LogLevelInfo, common.LogFormatJSON, nil, "git"),
+func NewServer(cfg *config.GitConfig, commandCfg *config.CommandConfig) *Server {
+	s := &Server{
+		config:        cfg,
+		logger:        common.NewServerLogger("git"),
+		commandConfig: commandCfg,
+		executor:      command.NewExecutor(commandCfg),
+		sessionEnv:    common.DefaultSessionEnv(),
 	}
+	s.Reload()
+	common.RegisterConfigSection("git", s.configSection)
+	return s
+}
+
+// Reload rebuilds the repository validator and the command policies. An
+// invalid policy denies every command rather than silently dropping the
+// rule.
+func (s *Server) Reload() {
+	s.validator = common.NewPathValidator(s.config.AllowedRepositories, nil, true)
+	validator, err := common.NewCommandValidatorFromConfig(s.commandConfig)
+	if err != nil {
+		s.logger.Errorf("invalid command policy, denying all bisect commands: %v", err)
+		validator = &common.CommandValidator{DefaultDeny: true}
+	}
+	s.commandValidator = validator
+}
+
+// env returns the session environment of the client calling a tool.
+func (s *Server) env(ctx context.Context) *common.SessionEnv {
+	return common.SessionEnvFor(ctx, s.sessionEnv)
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		s.gitStatusTool(),
+		s.gitLogTool(),
+		s.gitDiffTool(),
+		s.gitBranchListTool(),
+		common.Audited("git", s.gitBranchCreateTool()),
+		common.Audited("git", s.gitCheckoutTool()),
+		common.Audited("git", s.gitAddTool()),
+		common.Audited("git", s.gitCommitTool()),
+		common.Audited("git", s.gitPushTool()),
+		common.Audited("git", s.gitPullTool()),
+		common.Audited("git", s.gitCloneTool()),
+		common.Audited("git", s.gitStashTool()),
+		common.Audited("git", s.gitMergeTool()),
+		common.Audited("git", s.gitResetTool()),
+		common.Audited("git", s.gitBisectStartTool()),
+		common.Audited("git", s.gitBisectGoodTool()),
+		common.Audited("git", s.gitBisectBadTool()),
+		common.Audited("git", s.gitBisectResetTool()),
+		common.Audited("git", s.gitBisectRunTool()),
+		s.gitBlameTool(),
+		s.gitShowTool(),
+		s.gitFileHistoryTool(),
+		s.gitGrepTool(),
+		s.gitTagListTool(),
+		common.Audited("git", s.gitTagCreateTool()),
+		common.Audited("git", s.gitTagDeleteTool()),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
+		common.AuditLogTool(),
+	}
+	return common.Instrument("git", tools)
 }
 
 func (s *Server) RegisterTools(server *mcp.Server) {
-	server.RegisterTool(s.gitStatusTool())
-	server.RegisterTool(s.gitLogTool())
-	server.RegisterTool(s.gitDiffTool())
-	server.RegisterTool(s.gitBranchListTool())
-	server.RegisterTool(s.gitBranchCreateTool())
-	server.RegisterTool(s.gitCheckoutTool())
-	server.RegisterTool(s.gitAddTool())
-	server.RegisterTool(s.gitCommitTool())
-	server.RegisterTool(s.gitPushTool())
-	server.RegisterTool(s.gitPullTool())
-	server.RegisterTool(s.gitCloneTool())
-	server.RegisterTool(s.gitStashTool())
-	server.RegisterTool(s.gitBlameTool())
-	server.RegisterTool(s.gitShowTool())
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
 }
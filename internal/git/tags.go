@@ -0,0 +1,221 @@
+package git
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// tagListFormat separates the fields of a tag with the unit separator, as
+// taggers and subjects may contain any printable character.
+const tagListFormat = "%(refname:short)\x1f%(objecttype)\x1f%(objectname)\x1f%(*objectname)\x1f%(creatordate:iso-strict)\x1f%(taggername) %(taggeremail)\x1f%(contents:subject)"
+
+func (s *Server) gitTagListTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "git_tag_list",
+		Description: "List tags, newest first, with the commit each points to",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"repo_path": mcp.StringProperty("Path to repository"),
+				"pattern":   mcp.StringProperty("Only tags matching this glob, e.g. v1.*"),
+				"max_tags":  mcp.IntProperty("Maximum tags to return (default: 100)"),
+			},
+			[]string{"repo_path"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGitTagList),
+	}
+}
+
+type gitTagListArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+	Pattern  string `json:"pattern"`
+	MaxTags  int    `json:"max_tags" default:"100"`
+}
+
+func (s *Server) handleGitTagList(ctx context.Context, args gitTagListArgs) (*mcp.ToolResult, error) {
+	repoPath, pattern, maxTags := args.RepoPath, args.Pattern, args.MaxTags
+
+	if err := s.validator.ValidatePath(repoPath); err != nil {
+		return nil, err
+	}
+
+	gitArgs := []string{"for-each-ref", "--sort=-creatordate", "--format=" + tagListFormat}
+	if pattern != "" {
+		gitArgs = append(gitArgs, "refs/tags/"+pattern)
+	} else {
+		gitArgs = append(gitArgs, "refs/tags")
+	}
+
+	output, err := s.runGit(repoPath, gitArgs...)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []map[string]interface{}{}
+	total := 0
+	for _, line := range strings.Split(output, "\n") {
+		parts := strings.Split(line, "\x1f")
+		if len(parts) != 7 {
+			continue
+		}
+		total++
+		if len(tags) >= maxTags {
+			continue
+		}
+		tag := map[string]interface{}{
+			"name":   parts[0],
+			"type":   "lightweight",
+			"commit": parts[2],
+			"date":   parts[4],
+		}
+		if parts[1] == "tag" {
+			tag["type"] = "annotated"
+			tag["commit"] = parts[3]
+			tag["tagger"] = strings.TrimSpace(parts[5])
+			tag["message"] = parts[6]
+		}
+		tags = append(tags, tag)
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"tags":        tags,
+		"total_count": total,
+		"truncated":   total > len(tags),
+	})
+}
+
+func (s *Server) gitTagCreateTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "git_tag_create",
+		Description: "Create a tag. A message makes it an annotated tag; without one the tag is lightweight",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"repo_path": mcp.StringProperty("Path to repository"),
+				"tag_name":  mcp.StringProperty("Name for new tag, e.g. v1.2.0"),
+				"target":    mcp.StringProperty("Commit, branch or tag to tag (default: HEAD)"),
+				"message":   mcp.StringProperty("Tag message; required for annotated and signed tags"),
+				"annotated": mcp.BoolProperty("Create an annotated tag (default: true when a message is given)"),
+				"sign":      mcp.BoolProperty("Sign the tag with the configured GPG key (default: git.sign_commits for annotated tags)"),
+			},
+			[]string{"repo_path", "tag_name"},
+		),
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleGitTagCreate),
+	}
+}
+
+type gitTagCreateArgs struct {
+	RepoPath  string `json:"repo_path" validate:"required"`
+	TagName   string `json:"tag_name" validate:"required"`
+	Target    string `json:"target"`
+	Message   string `json:"message"`
+	Annotated *bool  `json:"annotated"`
+	Sign      *bool  `json:"sign"`
+}
+
+func (s *Server) handleGitTagCreate(ctx context.Context, args gitTagCreateArgs) (*mcp.ToolResult, error) {
+	repoPath, tagName, target, message := args.RepoPath, args.TagName, args.Target, args.Message
+	annotated := message != ""
+	if args.Annotated != nil {
+		annotated = *args.Annotated
+	}
+	// git.sign_commits only applies to tags that are annotated anyway, so
+	// it does not turn a plain lightweight tag into an error.
+	sign := s.config.SignCommits && annotated
+	if args.Sign != nil {
+		sign = *args.Sign
+	}
+
+	if err := s.validator.ValidatePath(repoPath); err != nil {
+		return nil, err
+	}
+
+	if strings.HasPrefix(tagName, "-") {
+		return nil, &mcp.ParamError{Param: "tag_name", Message: "tag_name must not start with -"}
+	}
+	if strings.HasPrefix(target, "-") {
+		return nil, &mcp.ParamError{Param: "target", Message: "target must not start with -"}
+	}
+	if (annotated || sign) && message == "" {
+		return nil, fmt.Errorf("%w: message is required for annotated and signed tags", common.ErrInvalidInput)
+	}
+	if !annotated && !sign && message != "" {
+		return nil, fmt.Errorf("%w: a lightweight tag cannot have a message", common.ErrInvalidInput)
+	}
+
+	gitArgs := []string{"tag"}
+	switch {
+	case sign:
+		gitArgs = append(gitArgs, "-s", "-m", message)
+	case annotated:
+		gitArgs = append(gitArgs, "-a", "-m", message)
+	}
+	gitArgs = append(gitArgs, tagName)
+	if target != "" {
+		gitArgs = append(gitArgs, target)
+	}
+
+	if _, err := s.runGit(repoPath, gitArgs...); err != nil {
+		return nil, err
+	}
+
+	commit, _ := s.runGit(repoPath, "rev-parse", tagName+"^{commit}")
+	tagType := "lightweight"
+	if annotated || sign {
+		tagType = "annotated"
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"name":   tagName,
+		"type":   tagType,
+		"signed": sign,
+		"commit": commit,
+	})
+}
+
+func (s *Server) gitTagDeleteTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "git_tag_delete",
+		Description: "Delete a local tag. The result names the commit it pointed to, so it can be recreated",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"repo_path": mcp.StringProperty("Path to repository"),
+				"tag_name":  mcp.StringProperty("Tag to delete"),
+			},
+			[]string{"repo_path", "tag_name"},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleGitTagDelete),
+	}
+}
+
+type gitTagDeleteArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+	TagName  string `json:"tag_name" validate:"required"`
+}
+
+func (s *Server) handleGitTagDelete(ctx context.Context, args gitTagDeleteArgs) (*mcp.ToolResult, error) {
+	repoPath, tagName := args.RepoPath, args.TagName
+
+	if err := s.validator.ValidatePath(repoPath); err != nil {
+		return nil, err
+	}
+
+	commit, err := s.runGit(repoPath, "rev-parse", "--verify", "--quiet", "refs/tags/"+tagName+"^{commit}")
+	if err != nil {
+		return nil, fmt.Errorf("%w: tag %s", common.ErrNotFound, tagName)
+	}
+
+	if _, err := s.runGit(repoPath, "tag", "-d", tagName); err != nil {
+		return nil, err
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"name":   tagName,
+		"commit": commit,
+	})
+}
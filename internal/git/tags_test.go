@@ -0,0 +1,48 @@
+package git
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func TestGitTagCreateRejectsOptions(t *testing.T) {
+	server, repo := newTestServer(t)
+	newTestRepo(t, repo, map[string]string{"a.txt": "1\n"}, map[string]string{"a.txt": "2\n"})
+
+	_, err := server.gitTagCreateTool().Handler(context.Background(), map[string]interface{}{
+		"repo_path": repo, "tag_name": "v1", "target": "HEAD~1",
+	})
+	require.NoError(t, err)
+	tagged, err := server.runGit(repo, "rev-parse", "v1^{commit}")
+	require.NoError(t, err)
+
+	// -f would silently move the existing tag to HEAD.
+	_, err = server.gitTagCreateTool().Handler(context.Background(), map[string]interface{}{
+		"repo_path": repo, "tag_name": "v1", "target": "-f",
+	})
+	var paramErr *mcp.ParamError
+	require.ErrorAs(t, err, &paramErr)
+	assert.Equal(t, "target", paramErr.Param)
+
+	still, err := server.runGit(repo, "rev-parse", "v1^{commit}")
+	require.NoError(t, err)
+	assert.Equal(t, tagged, still)
+}
+
+func TestGitTagCreateLightweightWithSignCommits(t *testing.T) {
+	server, repo := newTestServer(t)
+	newTestRepo(t, repo, map[string]string{"a.txt": "1\n"})
+	server.config.SignCommits = true
+
+	result, err := server.gitTagCreateTool().Handler(context.Background(), map[string]interface{}{
+		"repo_path": repo, "tag_name": "v1",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, result.Content[0].Text, `"type": "lightweight"`)
+	assert.Contains(t, result.Content[0].Text, `"signed": false`)
+}
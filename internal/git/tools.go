@@ -3,11 +3,15 @@ package git
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strconv"
 	"strings"
 
+	"github.com/local-mcps/dev-mcps/internal/common"
 	"github.com/local-mcps/dev-mcps/pkg/mcp"
 )
 
@@ -20,7 +24,7 @@ func (s *Server) runGit(repoPath string, args ...string) (string, error) {
 	cmd.Stderr = &stderr
 
 	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("%s: %s", err.Error(), stderr.String())
+		return "", fmt.Errorf("%w: %s", err, stderr.String())
 	}
 
 	return strings.TrimSpace(stdout.String()), nil
@@ -36,15 +40,30 @@ func (s *Server) gitStatusTool() *mcp.Tool {
 			},
 			[]string{"repo_path"},
 		),
-		Handler: s.handleGitStatus,
+		OutputSchema: mcp.BuildOutputSchema(
+			map[string]interface{}{
+				"branch":          mcp.StringProperty("Current branch, or HEAD when detached"),
+				"is_clean":        mcp.BoolProperty("No staged, modified or untracked files"),
+				"staged_files":    mcp.ArrayProperty("string", "Files with staged changes"),
+				"modified_files":  mcp.ArrayProperty("string", "Files modified in the working tree"),
+				"untracked_files": mcp.ArrayProperty("string", "Untracked files"),
+				"deleted_files":   mcp.ArrayProperty("string", "Files deleted in the working tree"),
+				"ahead":           mcp.IntProperty("Commits ahead of the upstream branch"),
+				"behind":          mcp.IntProperty("Commits behind the upstream branch"),
+			},
+			[]string{"branch", "is_clean", "staged_files", "modified_files", "untracked_files", "deleted_files", "ahead", "behind"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGitStatus),
 	}
 }
 
-func (s *Server) handleGitStatus(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
-		return nil, err
-	}
+type repoArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+}
+
+func (s *Server) handleGitStatus(ctx context.Context, args repoArgs) (*mcp.ToolResult, error) {
+	repoPath := args.RepoPath
 
 	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
@@ -57,7 +76,7 @@ func (s *Server) handleGitStatus(ctx context.Context, params map[string]interfac
 		return nil, err
 	}
 
-	var staged, modified, untracked, deleted []string
+	staged, modified, untracked, deleted := []string{}, []string{}, []string{}, []string{}
 	for _, line := range strings.Split(status, "\n") {
 		if len(line) < 3 {
 			continue
@@ -113,29 +132,30 @@ func (s *Server) gitLogTool() *mcp.Tool {
 			},
 			[]string{"repo_path"},
 		),
-		Handler: s.handleGitLog,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGitLog),
 	}
 }
 
-func (s *Server) handleGitLog(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
-		return nil, err
-	}
+type gitLogArgs struct {
+	RepoPath   string `json:"repo_path" validate:"required"`
+	MaxCommits int    `json:"max_commits" default:"20"`
+	Branch     string `json:"branch"`
+}
 
-	maxCommits, _ := mcp.GetIntParam(params, "max_commits", false, 20)
-	branch, _ := mcp.GetStringParam(params, "branch", false)
+func (s *Server) handleGitLog(ctx context.Context, args gitLogArgs) (*mcp.ToolResult, error) {
+	repoPath := args.RepoPath
 
 	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
 	}
 
-	args := []string{"log", fmt.Sprintf("-n%d", maxCommits), "--format=%H|%h|%an <%ae>|%aI|%s"}
-	if branch != "" {
-		args = append(args, branch)
+	gitArgs := []string{"log", fmt.Sprintf("-n%d", args.MaxCommits), "--format=%H|%h|%an <%ae>|%aI|%s"}
+	if args.Branch != "" {
+		gitArgs = append(gitArgs, args.Branch)
 	}
 
-	output, err := s.runGit(repoPath, args...)
+	output, err := s.runGit(repoPath, gitArgs...)
 	if err != nil {
 		return nil, err
 	}
@@ -166,51 +186,80 @@ func (s *Server) handleGitLog(ctx context.Context, params map[string]interface{}
 func (s *Server) gitDiffTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "git_diff",
-		Description: "Get diff of changes",
+		Description: "Get diff of changes. The structured format returns each file with its additions, deletions and parsed hunks, a page of files at a time",
 		InputSchema: mcp.BuildInputSchema(
 			map[string]interface{}{
-				"repo_path": mcp.StringProperty("Path to repository"),
-				"staged":    mcp.BoolProperty("Show staged changes only"),
-				"commit":    mcp.StringProperty("Show diff for specific commit"),
+				"repo_path":  mcp.StringProperty("Path to repository"),
+				"staged":     mcp.BoolProperty("Show staged changes only"),
+				"commit":     mcp.StringProperty("Show diff for specific commit"),
+				"base":       mcp.StringProperty("Compare refs instead of the working tree: the base branch or commit, e.g. main, or a range such as main...feature"),
+				"head":       mcp.StringProperty("Branch or commit compared with base (default: HEAD)"),
+				"merge_base": mcp.BoolProperty("Compare head with the point it forked from base (base...head, default true) rather than with base itself (base..head)"),
+				"paths":      mcp.ArrayProperty("string", "Only changes to these files or directories"),
+				"format":     mcp.StringProperty("Output format: text (default) or structured"),
+				"offset":     mcp.IntProperty("Structured format: file to start from, the next_offset of the previous page"),
+				"max_files":  mcp.IntProperty("Structured format: maximum files per page (default: 20)"),
 			},
 			[]string{"repo_path"},
 		),
-		Handler: s.handleGitDiff,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGitDiff),
 	}
 }
 
-func (s *Server) handleGitDiff(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
-		return nil, err
-	}
+type gitDiffArgs struct {
+	RepoPath  string   `json:"repo_path" validate:"required"`
+	Staged    bool     `json:"staged"`
+	Commit    string   `json:"commit"`
+	Base      string   `json:"base"`
+	Head      string   `json:"head"`
+	MergeBase bool     `json:"merge_base" default:"true"`
+	Paths     []string `json:"paths"`
+	Format    string   `json:"format" default:"text" validate:"oneof=text structured"`
+	Offset    int      `json:"offset" validate:"min=0"`
+	MaxFiles  int      `json:"max_files"`
+}
 
-	staged, _ := mcp.GetBoolParam(params, "staged", false)
-	commit, _ := mcp.GetStringParam(params, "commit", false)
+func (s *Server) handleGitDiff(ctx context.Context, args gitDiffArgs) (*mcp.ToolResult, error) {
+	repoPath, commit := args.RepoPath, args.Commit
 
 	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
 	}
 
-	args := []string{"diff", "--stat"}
-	if staged {
-		args = append(args, "--cached")
+	gitArgs := []string{"diff"}
+	if args.Staged {
+		gitArgs = append(gitArgs, "--cached")
 	}
 	if commit != "" {
-		args = []string{"show", "--stat", commit}
+		if strings.HasPrefix(commit, "-") {
+			return nil, &mcp.ParamError{Param: "commit", Message: "commit must not start with -"}
+		}
+		gitArgs = []string{"show", commit}
 	}
-
-	statOutput, _ := s.runGit(repoPath, args...)
-
-	args = []string{"diff"}
-	if staged {
-		args = append(args, "--cached")
+	if args.Base != "" {
+		revRange, err := diffRange(args.Base, args.Head, args.MergeBase)
+		if err != nil {
+			return nil, err
+		}
+		if args.Staged || commit != "" {
+			return nil, fmt.Errorf("%w: base cannot be combined with staged or commit", common.ErrInvalidInput)
+		}
+		gitArgs = []string{"diff", revRange}
+	} else if args.Head != "" {
+		return nil, &mcp.ParamError{Param: "base", Message: "base is required with head"}
 	}
-	if commit != "" {
-		args = []string{"show", commit}
+	if len(args.Paths) > 0 {
+		gitArgs = append(append(gitArgs, "--"), args.Paths...)
+	}
+
+	if args.Format == "structured" {
+		return s.structuredDiff(repoPath, gitArgs, args.Offset, args.MaxFiles)
 	}
 
-	diffOutput, err := s.runGit(repoPath, args...)
+	statOutput, _ := s.runGit(repoPath, append([]string{gitArgs[0], "--stat"}, gitArgs[1:]...)...)
+
+	diffOutput, err := s.runGit(repoPath, gitArgs...)
 	if err != nil {
 		return nil, err
 	}
@@ -236,17 +285,18 @@ func (s *Server) gitBranchListTool() *mcp.Tool {
 			},
 			[]string{"repo_path"},
 		),
-		Handler: s.handleGitBranchList,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGitBranchList),
 	}
 }
 
-func (s *Server) handleGitBranchList(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
-		return nil, err
-	}
+type gitBranchListArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+	Remote   bool   `json:"remote"`
+}
 
-	includeRemote, _ := mcp.GetBoolParam(params, "remote", false)
+func (s *Server) handleGitBranchList(ctx context.Context, args gitBranchListArgs) (*mcp.ToolResult, error) {
+	repoPath, includeRemote := args.RepoPath, args.Remote
 
 	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
@@ -289,33 +339,30 @@ func (s *Server) gitBranchCreateTool() *mcp.Tool {
 			},
 			[]string{"repo_path", "branch_name"},
 		),
-		Handler: s.handleGitBranchCreate,
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleGitBranchCreate),
 	}
 }
 
-func (s *Server) handleGitBranchCreate(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
-		return nil, err
-	}
-
-	branchName, err := mcp.GetStringParam(params, "branch_name", true)
-	if err != nil {
-		return nil, err
-	}
+type gitBranchCreateArgs struct {
+	RepoPath   string `json:"repo_path" validate:"required"`
+	BranchName string `json:"branch_name" validate:"required"`
+	StartPoint string `json:"start_point"`
+}
 
-	startPoint, _ := mcp.GetStringParam(params, "start_point", false)
+func (s *Server) handleGitBranchCreate(ctx context.Context, args gitBranchCreateArgs) (*mcp.ToolResult, error) {
+	repoPath, branchName := args.RepoPath, args.BranchName
 
 	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
 	}
 
-	args := []string{"branch", branchName}
-	if startPoint != "" {
-		args = append(args, startPoint)
+	gitArgs := []string{"branch", branchName}
+	if args.StartPoint != "" {
+		gitArgs = append(gitArgs, args.StartPoint)
 	}
 
-	if _, err := s.runGit(repoPath, args...); err != nil {
+	if _, err := s.runGit(repoPath, gitArgs...); err != nil {
 		return nil, err
 	}
 
@@ -333,20 +380,18 @@ func (s *Server) gitCheckoutTool() *mcp.Tool {
 			},
 			[]string{"repo_path", "ref"},
 		),
-		Handler: s.handleGitCheckout,
+		Annotations: mcp.Destructive(),
+		Handler:     mcp.TypedHandler(s.handleGitCheckout),
 	}
 }
 
-func (s *Server) handleGitCheckout(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
-		return nil, err
-	}
+type gitCheckoutArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+	Ref      string `json:"ref" validate:"required"`
+}
 
-	ref, err := mcp.GetStringParam(params, "ref", true)
-	if err != nil {
-		return nil, err
-	}
+func (s *Server) handleGitCheckout(ctx context.Context, args gitCheckoutArgs) (*mcp.ToolResult, error) {
+	repoPath, ref := args.RepoPath, args.Ref
 
 	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
@@ -370,27 +415,25 @@ func (s *Server) gitAddTool() *mcp.Tool {
 			},
 			[]string{"repo_path", "paths"},
 		),
-		Handler: s.handleGitAdd,
+		Annotations: mcp.Additive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleGitAdd),
 	}
 }
 
-func (s *Server) handleGitAdd(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
-		return nil, err
-	}
+type gitAddArgs struct {
+	RepoPath string   `json:"repo_path" validate:"required"`
+	Paths    []string `json:"paths" validate:"required"`
+}
 
-	paths, err := mcp.GetStringArrayParam(params, "paths", true)
-	if err != nil {
-		return nil, err
-	}
+func (s *Server) handleGitAdd(ctx context.Context, args gitAddArgs) (*mcp.ToolResult, error) {
+	repoPath, paths := args.RepoPath, args.Paths
 
 	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
 	}
 
-	args := append([]string{"add"}, paths...)
-	if _, err := s.runGit(repoPath, args...); err != nil {
+	gitArgs := append([]string{"add"}, paths...)
+	if _, err := s.runGit(repoPath, gitArgs...); err != nil {
 		return nil, err
 	}
 
@@ -404,38 +447,43 @@ func (s *Server) gitCommitTool() *mcp.Tool {
 		InputSchema: mcp.BuildInputSchema(
 			map[string]interface{}{
 				"repo_path": mcp.StringProperty("Path to repository"),
-				"message":   mcp.StringProperty("Commit message"),
+				"message":   mcp.StringProperty("Commit message; if omitted, the client's model drafts one from the staged diff"),
 				"author":    mcp.StringProperty("Author override (Name <email>)"),
 			},
-			[]string{"repo_path", "message"},
+			[]string{"repo_path"},
 		),
-		Handler: s.handleGitCommit,
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleGitCommit),
 	}
 }
 
-func (s *Server) handleGitCommit(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
-		return nil, err
-	}
-
-	message, err := mcp.GetStringParam(params, "message", true)
-	if err != nil {
-		return nil, err
-	}
+type gitCommitArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+	Message  string `json:"message"`
+	Author   string `json:"author"`
+}
 
-	author, _ := mcp.GetStringParam(params, "author", false)
+func (s *Server) handleGitCommit(ctx context.Context, args gitCommitArgs) (*mcp.ToolResult, error) {
+	repoPath, message := args.RepoPath, args.Message
 
 	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
 	}
 
-	args := []string{"commit", "-m", message}
-	if author != "" {
-		args = append(args, "--author", author)
+	drafted := message == ""
+	if drafted {
+		var err error
+		if message, err = s.draftCommitMessage(ctx, repoPath); err != nil {
+			return nil, err
+		}
+	}
+
+	gitArgs := []string{"commit", "-m", message}
+	if args.Author != "" {
+		gitArgs = append(gitArgs, "--author", args.Author)
 	}
 
-	output, err := s.runGit(repoPath, args...)
+	output, err := s.runGit(repoPath, gitArgs...)
 	if err != nil {
 		return nil, err
 	}
@@ -445,10 +493,46 @@ func (s *Server) handleGitCommit(ctx context.Context, params map[string]interfac
 	return mcp.JSONResult(map[string]interface{}{
 		"hash":    hash,
 		"message": message,
+		"drafted": drafted,
 		"output":  output,
 	})
 }
 
+// maxDraftDiffBytes bounds the staged diff sent to the client's model.
+const maxDraftDiffBytes = 32 * 1024
+
+// draftCommitMessage asks the client's model for a message describing the
+// staged changes.
+func (s *Server) draftCommitMessage(ctx context.Context, repoPath string) (string, error) {
+	diff, err := s.runGit(repoPath, "diff", "--cached", "--stat", "--patch")
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(diff) == "" {
+		return "", fmt.Errorf("%w: nothing is staged to commit", common.ErrInvalidInput)
+	}
+	if len(diff) > maxDraftDiffBytes {
+		diff = diff[:maxDraftDiffBytes] + "\n... (truncated)"
+	}
+
+	result, err := mcp.CreateMessage(ctx, &mcp.SamplingRequest{
+		SystemPrompt: "You write git commit messages: a summary line of at most 72 characters in the imperative mood, then, if the change needs it, a blank line and a short body. Reply with the message only.",
+		Messages:     []mcp.SamplingMessage{mcp.UserMessage("Write the commit message for this staged diff:\n\n" + diff)},
+		MaxTokens:    500,
+	})
+	if errors.Is(err, mcp.ErrClientUnsupported) {
+		return "", fmt.Errorf("%w: message is required, as the client does not support sampling to draft one", common.ErrInvalidInput)
+	}
+	if err != nil {
+		return "", err
+	}
+	message := strings.TrimSpace(result.Content.Text)
+	if message == "" {
+		return "", fmt.Errorf("%w: the client returned an empty commit message", common.ErrOperationFailed)
+	}
+	return message, nil
+}
+
 func (s *Server) gitPushTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "git_push",
@@ -462,19 +546,20 @@ func (s *Server) gitPushTool() *mcp.Tool {
 			},
 			[]string{"repo_path"},
 		),
-		Handler: s.handleGitPush,
+		Annotations: mcp.Destructive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleGitPush),
 	}
 }
 
-func (s *Server) handleGitPush(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
-		return nil, err
-	}
+type gitPushArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+	Remote   string `json:"remote"`
+	Branch   string `json:"branch"`
+	Force    bool   `json:"force"`
+}
 
-	remote, _ := mcp.GetStringParam(params, "remote", false)
-	branch, _ := mcp.GetStringParam(params, "branch", false)
-	force, _ := mcp.GetBoolParam(params, "force", false)
+func (s *Server) handleGitPush(ctx context.Context, args gitPushArgs) (*mcp.ToolResult, error) {
+	repoPath, remote, branch, force := args.RepoPath, args.Remote, args.Branch, args.Force
 
 	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
@@ -488,18 +573,28 @@ func (s *Server) handleGitPush(ctx context.Context, params map[string]interface{
 		return nil, fmt.Errorf("force push is disabled in configuration")
 	}
 
-	args := []string{"push"}
 	if force {
-		args = append(args, "--force")
+		target := strings.TrimSpace(remote + " " + branch)
+		if target == "" {
+			target = "the upstream branch"
+		}
+		if err := common.Confirm(ctx, fmt.Sprintf("Force-push %s in %s? Commits on the remote that are not in the local branch will be lost.", target, repoPath)); err != nil {
+			return nil, err
+		}
+	}
+
+	gitArgs := []string{"push"}
+	if force {
+		gitArgs = append(gitArgs, "--force")
 	}
 	if remote != "" {
-		args = append(args, remote)
+		gitArgs = append(gitArgs, remote)
 	}
 	if branch != "" {
-		args = append(args, branch)
+		gitArgs = append(gitArgs, branch)
 	}
 
-	output, err := s.runGit(repoPath, args...)
+	output, err := s.runGit(repoPath, gitArgs...)
 	if err != nil {
 		return nil, err
 	}
@@ -519,32 +614,33 @@ func (s *Server) gitPullTool() *mcp.Tool {
 			},
 			[]string{"repo_path"},
 		),
-		Handler: s.handleGitPull,
+		Annotations: mcp.Additive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleGitPull),
 	}
 }
 
-func (s *Server) handleGitPull(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
-		return nil, err
-	}
+type gitPullArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+	Remote   string `json:"remote"`
+	Branch   string `json:"branch"`
+}
 
-	remote, _ := mcp.GetStringParam(params, "remote", false)
-	branch, _ := mcp.GetStringParam(params, "branch", false)
+func (s *Server) handleGitPull(ctx context.Context, args gitPullArgs) (*mcp.ToolResult, error) {
+	repoPath := args.RepoPath
 
 	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
 	}
 
-	args := []string{"pull"}
-	if remote != "" {
-		args = append(args, remote)
+	gitArgs := []string{"pull"}
+	if args.Remote != "" {
+		gitArgs = append(gitArgs, args.Remote)
 	}
-	if branch != "" {
-		args = append(args, branch)
+	if args.Branch != "" {
+		gitArgs = append(gitArgs, args.Branch)
 	}
 
-	output, err := s.runGit(repoPath, args...)
+	output, err := s.runGit(repoPath, gitArgs...)
 	if err != nil {
 		return nil, err
 	}
@@ -565,34 +661,31 @@ func (s *Server) gitCloneTool() *mcp.Tool {
 			},
 			[]string{"url", "destination"},
 		),
-		Handler: s.handleGitClone,
+		Annotations: mcp.Additive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleGitClone),
 	}
 }
 
-func (s *Server) handleGitClone(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	url, err := mcp.GetStringParam(params, "url", true)
-	if err != nil {
-		return nil, err
-	}
-
-	destination, err := mcp.GetStringParam(params, "destination", true)
-	if err != nil {
-		return nil, err
-	}
+type gitCloneArgs struct {
+	URL         string `json:"url" validate:"required"`
+	Destination string `json:"destination" validate:"required"`
+	Branch      string `json:"branch"`
+	Depth       int    `json:"depth"`
+}
 
-	branch, _ := mcp.GetStringParam(params, "branch", false)
-	depth, _ := mcp.GetIntParam(params, "depth", false, 0)
+func (s *Server) handleGitClone(ctx context.Context, args gitCloneArgs) (*mcp.ToolResult, error) {
+	url, destination := args.URL, args.Destination
 
-	args := []string{"clone"}
-	if branch != "" {
-		args = append(args, "-b", branch)
+	gitArgs := []string{"clone"}
+	if args.Branch != "" {
+		gitArgs = append(gitArgs, "-b", args.Branch)
 	}
-	if depth > 0 {
-		args = append(args, "--depth", strconv.Itoa(depth))
+	if args.Depth > 0 {
+		gitArgs = append(gitArgs, "--depth", strconv.Itoa(args.Depth))
 	}
-	args = append(args, url, destination)
+	gitArgs = append(gitArgs, url, destination)
 
-	cmd := exec.Command("git", args...)
+	cmd := exec.Command("git", gitArgs...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -614,20 +707,18 @@ func (s *Server) gitStashTool() *mcp.Tool {
 			},
 			[]string{"repo_path", "action"},
 		),
-		Handler: s.handleGitStash,
+		Annotations: mcp.Destructive(),
+		Handler:     mcp.TypedHandler(s.handleGitStash),
 	}
 }
 
-func (s *Server) handleGitStash(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
-		return nil, err
-	}
+type gitStashArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+	Action   string `json:"action" validate:"required"`
+}
 
-	action, err := mcp.GetStringParam(params, "action", true)
-	if err != nil {
-		return nil, err
-	}
+func (s *Server) handleGitStash(ctx context.Context, args gitStashArgs) (*mcp.ToolResult, error) {
+	repoPath, action := args.RepoPath, args.Action
 
 	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
@@ -649,43 +740,76 @@ func (s *Server) handleGitStash(ctx context.Context, params map[string]interface
 func (s *Server) gitBlameTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "git_blame",
-		Description: "Show who changed each line",
+		Description: "Show who changed each line, as ranges of consecutive lines with the commit, author, date and summary that last changed them",
 		InputSchema: mcp.BuildInputSchema(
 			map[string]interface{}{
-				"repo_path": mcp.StringProperty("Path to repository"),
-				"file_path": mcp.StringProperty("File to blame"),
+				"repo_path":  mcp.StringProperty("Path to repository"),
+				"file_path":  mcp.StringProperty("File to blame"),
+				"start_line": mcp.IntProperty("First line to blame (default: 1)"),
+				"end_line":   mcp.IntProperty("Last line to blame (default: 500 lines from start_line)"),
 			},
 			[]string{"repo_path", "file_path"},
 		),
-		Handler: s.handleGitBlame,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGitBlame),
 	}
 }
 
-func (s *Server) handleGitBlame(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
+type gitBlameArgs struct {
+	RepoPath  string `json:"repo_path" validate:"required"`
+	FilePath  string `json:"file_path" validate:"required"`
+	StartLine int    `json:"start_line" default:"1"`
+	EndLine   int    `json:"end_line"`
+}
+
+func (s *Server) handleGitBlame(ctx context.Context, args gitBlameArgs) (*mcp.ToolResult, error) {
+	repoPath, filePath, startLine, endLine := args.RepoPath, args.FilePath, args.StartLine, args.EndLine
+
+	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
 	}
 
-	filePath, err := mcp.GetStringParam(params, "file_path", true)
-	if err != nil {
-		return nil, err
+	if startLine < 1 {
+		return nil, &mcp.ParamError{Param: "start_line", Message: "start_line must be at least 1"}
+	}
+	if endLine != 0 && endLine < startLine {
+		return nil, &mcp.ParamError{Param: "end_line", Message: "end_line must not be before start_line"}
 	}
 
-	if err := s.validator.ValidatePath(repoPath); err != nil {
+	// git blame fails on a range past the end of the file, so clamp it to
+	// the length of the working copy.
+	fullPath := filepath.Join(repoPath, filePath)
+	if err := s.validator.ValidatePath(fullPath); err != nil {
 		return nil, err
 	}
-
-	output, err := s.runGit(repoPath, "blame", "--line-porcelain", filePath)
+	content, err := os.ReadFile(fullPath)
 	if err != nil {
 		return nil, err
 	}
+	totalLines := strings.Count(string(content), "\n")
+	if len(content) > 0 && !strings.HasSuffix(string(content), "\n") {
+		totalLines++
+	}
+	if startLine > totalLines {
+		return nil, &mcp.ParamError{Param: "start_line", Message: fmt.Sprintf("start_line is past the end of the file, which has %d lines", totalLines)}
+	}
+	if endLine == 0 {
+		endLine = startLine + maxBlameLines - 1
+	}
+	endLine = min(endLine, totalLines)
 
-	if len(output) > 100000 {
-		output = output[:100000] + "\n... (truncated)"
+	output, err := s.runGit(repoPath, "blame", "--line-porcelain", "-L", fmt.Sprintf("%d,%d", startLine, endLine), "--", filePath)
+	if err != nil {
+		return nil, err
 	}
 
-	return mcp.TextResult(output), nil
+	return mcp.JSONResult(map[string]interface{}{
+		"file_path":   filePath,
+		"start_line":  startLine,
+		"end_line":    endLine,
+		"total_lines": totalLines,
+		"ranges":      parseBlame(output, filePath),
+	})
 }
 
 func (s *Server) gitShowTool() *mcp.Tool {
@@ -699,20 +823,18 @@ func (s *Server) gitShowTool() *mcp.Tool {
 			},
 			[]string{"repo_path", "commit"},
 		),
-		Handler: s.handleGitShow,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGitShow),
 	}
 }
 
-func (s *Server) handleGitShow(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	repoPath, err := mcp.GetStringParam(params, "repo_path", true)
-	if err != nil {
-		return nil, err
-	}
+type gitShowArgs struct {
+	RepoPath string `json:"repo_path" validate:"required"`
+	Commit   string `json:"commit" validate:"required"`
+}
 
-	commit, err := mcp.GetStringParam(params, "commit", true)
-	if err != nil {
-		return nil, err
-	}
+func (s *Server) handleGitShow(ctx context.Context, args gitShowArgs) (*mcp.ToolResult, error) {
+	repoPath, commit := args.RepoPath, args.Commit
 
 	if err := s.validator.ValidatePath(repoPath); err != nil {
 		return nil, err
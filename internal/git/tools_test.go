@@ -0,0 +1,70 @@
+package git
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+func newTestServer(t *testing.T) (*Server, string) {
+	repo := t.TempDir()
+	cfg := config.DefaultConfig()
+	cfg.Git.AllowedRepositories = []string{repo}
+	return NewServer(&cfg.Git, &cfg.Command), repo
+}
+
+// newTestRepo initializes a repository in dir and commits files in order,
+// one commit per entry.
+func newTestRepo(t *testing.T, dir string, commits ...map[string]string) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com", "GIT_AUTHOR_DATE=1700000000 +0100",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com", "GIT_COMMITTER_DATE=1700000000 +0100")
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	git("init", "-q", "-b", "main")
+	for i, files := range commits {
+		for name, content := range files {
+			require.NoError(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), 0755))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+		}
+		git("add", "-A")
+		git("commit", "-q", "-m", "commit "+string(rune('1'+i)))
+	}
+}
+
+func TestGitDiffRejectsBadParams(t *testing.T) {
+	server, repo := newTestServer(t)
+	newTestRepo(t, repo, map[string]string{"a.txt": "a\n"})
+
+	_, err := server.gitDiffTool().Handler(context.Background(), map[string]interface{}{
+		"repo_path": repo, "format": "structured", "offset": -1.0,
+	})
+	var paramErr *mcp.ParamError
+	require.ErrorAs(t, err, &paramErr)
+	assert.Equal(t, "offset", paramErr.Param)
+
+	output := filepath.Join(t.TempDir(), "pwned")
+	_, err = server.gitDiffTool().Handler(context.Background(), map[string]interface{}{
+		"repo_path": repo, "commit": "--output=" + output,
+	})
+	require.ErrorAs(t, err, &paramErr)
+	assert.Equal(t, "commit", paramErr.Param)
+	assert.NoFileExists(t, output)
+}
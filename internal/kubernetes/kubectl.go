@@ -0,0 +1,127 @@
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+// scope is the kubectl context and namespace a call runs against.
+type scope struct {
+	Context   string `json:"context,omitempty"`
+	Namespace string `json:"namespace"`
+}
+
+// scopeArgs are the arguments for scopeProperties.
+type scopeArgs struct {
+	Context   string `json:"context"`
+	Namespace string `json:"namespace"`
+}
+
+// resolveScope applies the defaults and allowlists to the context and
+// namespace parameters.
+func (s *Server) resolveScope(args scopeArgs) (scope, error) {
+	kubeContext, namespace := args.Context, args.Namespace
+
+	if kubeContext == "" && len(s.config.AllowedContexts) > 0 {
+		kubeContext = s.config.AllowedContexts[0]
+	}
+	if kubeContext != "" && len(s.config.AllowedContexts) > 0 && !contains(s.config.AllowedContexts, kubeContext) {
+		return scope{}, common.WithDetails(
+			fmt.Errorf("%w: context %s is not in allowed_contexts", common.ErrPermissionDenied, kubeContext),
+			"context", kubeContext)
+	}
+
+	if namespace == "" {
+		namespace = "default"
+		if len(s.config.AllowedNamespaces) > 0 {
+			namespace = s.config.AllowedNamespaces[0]
+		}
+	}
+	if len(s.config.AllowedNamespaces) > 0 && !contains(s.config.AllowedNamespaces, namespace) {
+		return scope{}, common.WithDetails(
+			fmt.Errorf("%w: namespace %s is not in allowed_namespaces", common.ErrPermissionDenied, namespace),
+			"namespace", namespace)
+	}
+
+	return scope{Context: kubeContext, Namespace: namespace}, nil
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// errNoKubectl is returned by every tool when the kubectl binary, which
+// the server runs instead of talking to the API server itself, is missing.
+var errNoKubectl = fmt.Errorf("%w: kubectl is not installed or not on PATH; the kubernetes server needs it to reach the cluster", common.ErrNotFound)
+
+// args prefixes kubectl arguments with the kubeconfig, context and
+// namespace.
+func (s *Server) args(sc scope, args ...string) []string {
+	var out []string
+	if s.config.Kubeconfig != "" {
+		out = append(out, "--kubeconfig", s.config.Kubeconfig)
+	}
+	if sc.Context != "" {
+		out = append(out, "--context", sc.Context)
+	}
+	out = append(out, "--namespace", sc.Namespace)
+	return append(out, args...)
+}
+
+func (s *Server) kubectl(ctx context.Context, sc scope, args ...string) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.config.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "kubectl", s.args(sc, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("%w: kubectl %s", common.ErrTimeout, args[0])
+		}
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, errNoKubectl
+		}
+		var execErr *exec.Error
+		if errors.As(err, &execErr) {
+			return nil, fmt.Errorf("%w: running kubectl: %v", common.ErrOperationFailed, err)
+		}
+		return nil, kubectlError(strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// kubectlError maps the API error kubectl printed to an error code.
+func kubectlError(msg string) error {
+	switch {
+	case strings.Contains(msg, "(NotFound)"):
+		return fmt.Errorf("%w: %s", common.ErrNotFound, msg)
+	case strings.Contains(msg, "(Forbidden)"), strings.Contains(msg, "(Unauthorized)"):
+		return fmt.Errorf("%w: %s", common.ErrPermissionDenied, msg)
+	default:
+		return fmt.Errorf("%w: kubectl: %s", common.ErrOperationFailed, msg)
+	}
+}
+
+func (s *Server) getJSON(ctx context.Context, sc scope, out interface{}, args ...string) error {
+	data, err := s.kubectl(ctx, sc, append(append([]string{"get"}, args...), "--output", "json")...)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,260 @@
+package kubernetes
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// PortForward is a `kubectl port-forward` process started by
+// port_forward_start. It runs until stopped or until the server exits.
+type PortForward struct {
+	ID         string    `json:"id"`
+	Scope      scope     `json:"scope"`
+	Target     string    `json:"target"`
+	Ports      []string  `json:"ports"`
+	Forwarding []string  `json:"forwarding"`
+	StartTime  time.Time `json:"start_time"`
+	Status     string    `json:"status"`
+	Error      string    `json:"error,omitempty"`
+
+	mu     sync.Mutex
+	cmd    *exec.Cmd
+	stderr bytes.Buffer
+}
+
+func (pf *PortForward) snapshot() PortForward {
+	pf.mu.Lock()
+	defer pf.mu.Unlock()
+	return PortForward{
+		ID:         pf.ID,
+		Scope:      pf.Scope,
+		Target:     pf.Target,
+		Ports:      pf.Ports,
+		Forwarding: append([]string{}, pf.Forwarding...),
+		StartTime:  pf.StartTime,
+		Status:     pf.Status,
+		Error:      pf.Error,
+	}
+}
+
+// stop kills kubectl if it is still forwarding and reports whether it was.
+func (pf *PortForward) stop() bool {
+	pf.mu.Lock()
+	running := pf.Status == "running"
+	pf.Status = "stopped"
+	pf.mu.Unlock()
+	if running {
+		pf.cmd.Process.Kill()
+	}
+	return running
+}
+
+// Shutdown stops every port forward, so no kubectl outlives the server.
+func (s *Server) Shutdown(ctx context.Context) {
+	s.portForwards.Range(func(id, v interface{}) bool {
+		s.portForwards.Delete(id)
+		v.(*PortForward).stop()
+		return true
+	})
+}
+
+var portSpecRe = regexp.MustCompile(`^(\d{1,5})?(:\d{1,5})?$`)
+
+func (s *Server) portForwardStartTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "port_forward_start",
+		Description: "Forward local ports to a pod, deployment or service in the background (requires kubernetes.allow_port_forward)",
+		InputSchema: mcp.BuildInputSchema(
+			scopeProperties(map[string]interface{}{
+				"kind":  kindProperty(),
+				"name":  mcp.StringProperty("Resource name"),
+				"ports": mcp.ArrayProperty("string", "Ports as LOCAL:REMOTE, e.g. [\"8080:80\"]; \":80\" picks a free local port"),
+			}),
+			[]string{"kind", "name", "ports"},
+		),
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handlePortForwardStart),
+	}
+}
+
+type portForwardStartArgs struct {
+	resourceArgs
+	Ports []string `json:"ports" validate:"required"`
+}
+
+func (s *Server) handlePortForwardStart(ctx context.Context, args portForwardStartArgs) (*mcp.ToolResult, error) {
+	if !s.config.AllowPortForward {
+		return nil, fmt.Errorf("%w: port forwarding is disabled (set kubernetes.allow_port_forward)", common.ErrPermissionDenied)
+	}
+	kind, err := getKind(args.Kind)
+	if err != nil {
+		return nil, err
+	}
+	name, ports := args.Name, args.Ports
+	if err := checkName("name", name); err != nil {
+		return nil, err
+	}
+	if len(ports) == 0 {
+		return nil, fmt.Errorf("%w: at least one port is required", common.ErrInvalidInput)
+	}
+	for _, p := range ports {
+		if p == "" || !portSpecRe.MatchString(p) {
+			return nil, fmt.Errorf("%w: invalid port %q", common.ErrInvalidInput, p)
+		}
+	}
+	sc, err := s.resolveScope(args.scopeArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	target := kind + "/" + name
+	pf := &PortForward{
+		ID:        uuid.New().String(),
+		Scope:     sc,
+		Target:    target,
+		Ports:     ports,
+		StartTime: time.Now(),
+		Status:    "starting",
+	}
+	// Only bind to localhost, kubectl's default, whatever the caller asks.
+	kubectlArgs := append([]string{"port-forward", "--address", "127.0.0.1", target}, ports...)
+	pf.cmd = exec.Command("kubectl", s.args(sc, kubectlArgs...)...)
+	pf.cmd.Stderr = &pf.stderr
+	stdout, err := pf.cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := pf.cmd.Start(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, errNoKubectl
+		}
+		return nil, fmt.Errorf("%w: starting kubectl: %v", common.ErrOperationFailed, err)
+	}
+
+	ready := make(chan struct{})
+	go func() {
+		scanner := bufio.NewScanner(stdout)
+		signalled := false
+		for scanner.Scan() {
+			line := scanner.Text()
+			if !strings.HasPrefix(line, "Forwarding from") {
+				continue
+			}
+			pf.mu.Lock()
+			pf.Forwarding = append(pf.Forwarding, strings.TrimPrefix(line, "Forwarding from "))
+			pf.Status = "running"
+			pf.mu.Unlock()
+			if !signalled {
+				signalled = true
+				close(ready)
+			}
+		}
+	}()
+	exited := make(chan struct{})
+	go func() {
+		err := pf.cmd.Wait()
+		pf.mu.Lock()
+		if pf.Status != "stopped" {
+			pf.Status = "exited"
+			pf.Error = strings.TrimSpace(pf.stderr.String())
+			if pf.Error == "" && err != nil {
+				pf.Error = err.Error()
+			}
+		}
+		pf.mu.Unlock()
+		close(exited)
+	}()
+
+	select {
+	case <-ready:
+	case <-exited:
+		return nil, kubectlError(pf.snapshot().Error)
+	case <-time.After(time.Duration(s.config.TimeoutSeconds) * time.Second):
+		pf.cmd.Process.Kill()
+		return nil, fmt.Errorf("%w: port-forward to %s did not start within %ds", common.ErrTimeout, target, s.config.TimeoutSeconds)
+	case <-ctx.Done():
+		pf.cmd.Process.Kill()
+		return nil, ctx.Err()
+	}
+
+	// kubectl prints one line per address family; give the rest a moment.
+	time.Sleep(100 * time.Millisecond)
+	s.portForwards.Store(pf.ID, pf)
+	s.logger.WithFields(map[string]interface{}{"id": pf.ID, "target": target}).Info("Port forward started")
+	return mcp.JSONResult(pf.snapshot())
+}
+
+func (s *Server) portForwardListTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "port_forward_list",
+		Description: "List the port forwards started by port_forward_start",
+		InputSchema: mcp.BuildInputSchema(map[string]interface{}{}, []string{}),
+		Annotations: mcp.ReadOnly(),
+		Handler:     s.handlePortForwardList,
+	}
+}
+
+func (s *Server) handlePortForwardList(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
+	forwards := []PortForward{}
+	s.portForwards.Range(func(_, v interface{}) bool {
+		forwards = append(forwards, v.(*PortForward).snapshot())
+		return true
+	})
+	sort.Slice(forwards, func(i, j int) bool {
+		return forwards[i].StartTime.Before(forwards[j].StartTime)
+	})
+	return mcp.JSONResult(map[string]interface{}{
+		"port_forwards": forwards,
+		"count":         len(forwards),
+	})
+}
+
+func (s *Server) portForwardStopTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "port_forward_stop",
+		Description: "Stop a port forward",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"id": mcp.StringProperty("Port forward ID from port_forward_start"),
+			},
+			[]string{"id"},
+		),
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handlePortForwardStop),
+	}
+}
+
+type portForwardStopArgs struct {
+	ID string `json:"id" validate:"required"`
+}
+
+func (s *Server) handlePortForwardStop(ctx context.Context, args portForwardStopArgs) (*mcp.ToolResult, error) {
+	id := args.ID
+	v, ok := s.portForwards.LoadAndDelete(id)
+	if !ok {
+		return nil, fmt.Errorf("%w: port forward %s", common.ErrNotFound, id)
+	}
+
+	pf := v.(*PortForward)
+	running := pf.stop()
+
+	return mcp.JSONResult(map[string]interface{}{
+		"id":      id,
+		"target":  pf.Target,
+		"stopped": running,
+	})
+}
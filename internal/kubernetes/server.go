@@ -0,0 +1,52 @@
+package kubernetes
+
+import (
+	"sync"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type Server struct {
+	config       *config.KubernetesConfig
+	logger       *common.Logger
+	portForwards sync.Map
+}
+
+func NewServer(cfg *config.KubernetesConfig) *Server {
+	s := &Server{
+		config: cfg,
+		logger: common.NewServerLogger("kubernetes"),
+	}
+	common.RegisterConfigSection("kubernetes", s.configSection)
+	return s
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		s.listPodsTool(),
+		s.listDeploymentsTool(),
+		s.listServicesTool(),
+		s.getResourceTool(),
+		s.describeResourceTool(),
+		s.podLogsTool(),
+		common.Audited("kubernetes", s.portForwardStartTool()),
+		s.portForwardListTool(),
+		common.Audited("kubernetes", s.portForwardStopTool()),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
+		common.AuditLogTool(),
+	}
+	return common.Instrument("kubernetes", tools)
+}
+
+func (s *Server) RegisterTools(server *mcp.Server) {
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
+}
@@ -0,0 +1,475 @@
+package kubernetes
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// resourceKinds are the kinds get_resource and describe_resource accept.
+var resourceKinds = []string{"pod", "deployment", "service"}
+
+func scopeProperties(props map[string]interface{}) map[string]interface{} {
+	props["namespace"] = mcp.StringProperty("Namespace (default: first of allowed_namespaces, or \"default\")")
+	props["context"] = mcp.StringProperty("kubectl context (default: first of allowed_contexts, or the current context)")
+	return props
+}
+
+func kindProperty() map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": "Resource kind",
+		"enum":        resourceKinds,
+	}
+}
+
+func getKind(kind string) (string, error) {
+	kind = strings.TrimSuffix(strings.ToLower(kind), "s")
+	if !contains(resourceKinds, kind) {
+		return "", fmt.Errorf("%w: kind must be one of %s", common.ErrInvalidInput, strings.Join(resourceKinds, ", "))
+	}
+	return kind, nil
+}
+
+// nameRegex matches the DNS subdomain names Kubernetes gives pods,
+// deployments and services. Anything else, in particular a value starting
+// with -, would reach kubectl as an option such as --namespace and escape
+// the allowlists.
+var nameRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9.]{0,251}[a-z0-9])?$`)
+
+func checkName(param, name string) error {
+	if !nameRegex.MatchString(name) {
+		return &mcp.ParamError{Param: param, Message: fmt.Sprintf("invalid resource name %q", name)}
+	}
+	return nil
+}
+
+// resourceArgs are the arguments of the tools that act on one resource.
+type resourceArgs struct {
+	scopeArgs
+	Kind string `json:"kind" validate:"required"`
+	Name string `json:"name" validate:"required"`
+}
+
+type metadata struct {
+	Name              string            `json:"name"`
+	CreationTimestamp time.Time         `json:"creationTimestamp"`
+	Labels            map[string]string `json:"labels"`
+}
+
+// age formats how long ago t was the way kubectl does.
+func age(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d >= 48*time.Hour:
+		return strconv.Itoa(int(d.Hours()/24)) + "d"
+	case d >= time.Hour:
+		return strconv.Itoa(int(d.Hours())) + "h"
+	case d >= time.Minute:
+		return strconv.Itoa(int(d.Minutes())) + "m"
+	default:
+		return strconv.Itoa(int(d.Seconds())) + "s"
+	}
+}
+
+func (s *Server) listPodsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_pods",
+		Description: "List pods in a namespace",
+		InputSchema: mcp.BuildInputSchema(
+			scopeProperties(map[string]interface{}{
+				"label_selector": mcp.StringProperty("Label selector, e.g. app=web"),
+			}),
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleListPods),
+	}
+}
+
+// listArgs are the arguments of the list_* tools.
+type listArgs struct {
+	scopeArgs
+	LabelSelector string `json:"label_selector"`
+}
+
+func (s *Server) handleListPods(ctx context.Context, args listArgs) (*mcp.ToolResult, error) {
+	sc, err := s.resolveScope(args.scopeArgs)
+	if err != nil {
+		return nil, err
+	}
+	kubectlArgs := []string{"pods"}
+	if args.LabelSelector != "" {
+		kubectlArgs = append(kubectlArgs, "--selector", args.LabelSelector)
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata metadata `json:"metadata"`
+			Spec     struct {
+				NodeName string `json:"nodeName"`
+			} `json:"spec"`
+			Status struct {
+				Phase             string `json:"phase"`
+				PodIP             string `json:"podIP"`
+				ContainerStatuses []struct {
+					Ready        bool `json:"ready"`
+					RestartCount int  `json:"restartCount"`
+					State        struct {
+						Waiting *struct {
+							Reason string `json:"reason"`
+						} `json:"waiting"`
+					} `json:"state"`
+				} `json:"containerStatuses"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := s.getJSON(ctx, sc, &list, kubectlArgs...); err != nil {
+		return nil, err
+	}
+
+	pods := make([]map[string]interface{}, 0, len(list.Items))
+	for _, p := range list.Items {
+		ready, restarts := 0, 0
+		status := p.Status.Phase
+		for _, c := range p.Status.ContainerStatuses {
+			if c.Ready {
+				ready++
+			}
+			restarts += c.RestartCount
+			if c.State.Waiting != nil && c.State.Waiting.Reason != "" {
+				status = c.State.Waiting.Reason
+			}
+		}
+		pods = append(pods, map[string]interface{}{
+			"name":     p.Metadata.Name,
+			"ready":    fmt.Sprintf("%d/%d", ready, len(p.Status.ContainerStatuses)),
+			"status":   status,
+			"restarts": restarts,
+			"node":     p.Spec.NodeName,
+			"ip":       p.Status.PodIP,
+			"age":      age(p.Metadata.CreationTimestamp),
+		})
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"scope": sc,
+		"pods":  pods,
+		"count": len(pods),
+	})
+}
+
+func (s *Server) listDeploymentsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_deployments",
+		Description: "List deployments in a namespace",
+		InputSchema: mcp.BuildInputSchema(
+			scopeProperties(map[string]interface{}{
+				"label_selector": mcp.StringProperty("Label selector, e.g. app=web"),
+			}),
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleListDeployments),
+	}
+}
+
+func (s *Server) handleListDeployments(ctx context.Context, args listArgs) (*mcp.ToolResult, error) {
+	sc, err := s.resolveScope(args.scopeArgs)
+	if err != nil {
+		return nil, err
+	}
+	kubectlArgs := []string{"deployments"}
+	if args.LabelSelector != "" {
+		kubectlArgs = append(kubectlArgs, "--selector", args.LabelSelector)
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata metadata `json:"metadata"`
+			Spec     struct {
+				Replicas int `json:"replicas"`
+				Template struct {
+					Spec struct {
+						Containers []struct {
+							Image string `json:"image"`
+						} `json:"containers"`
+					} `json:"spec"`
+				} `json:"template"`
+			} `json:"spec"`
+			Status struct {
+				ReadyReplicas     int `json:"readyReplicas"`
+				UpdatedReplicas   int `json:"updatedReplicas"`
+				AvailableReplicas int `json:"availableReplicas"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := s.getJSON(ctx, sc, &list, kubectlArgs...); err != nil {
+		return nil, err
+	}
+
+	deployments := make([]map[string]interface{}, 0, len(list.Items))
+	for _, d := range list.Items {
+		var images []string
+		for _, c := range d.Spec.Template.Spec.Containers {
+			images = append(images, c.Image)
+		}
+		deployments = append(deployments, map[string]interface{}{
+			"name":       d.Metadata.Name,
+			"ready":      fmt.Sprintf("%d/%d", d.Status.ReadyReplicas, d.Spec.Replicas),
+			"up_to_date": d.Status.UpdatedReplicas,
+			"available":  d.Status.AvailableReplicas,
+			"images":     images,
+			"age":        age(d.Metadata.CreationTimestamp),
+		})
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"scope":       sc,
+		"deployments": deployments,
+		"count":       len(deployments),
+	})
+}
+
+func (s *Server) listServicesTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_services",
+		Description: "List services in a namespace",
+		InputSchema: mcp.BuildInputSchema(
+			scopeProperties(map[string]interface{}{
+				"label_selector": mcp.StringProperty("Label selector, e.g. app=web"),
+			}),
+			[]string{},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleListServices),
+	}
+}
+
+func (s *Server) handleListServices(ctx context.Context, args listArgs) (*mcp.ToolResult, error) {
+	sc, err := s.resolveScope(args.scopeArgs)
+	if err != nil {
+		return nil, err
+	}
+	kubectlArgs := []string{"services"}
+	if args.LabelSelector != "" {
+		kubectlArgs = append(kubectlArgs, "--selector", args.LabelSelector)
+	}
+
+	var list struct {
+		Items []struct {
+			Metadata metadata `json:"metadata"`
+			Spec     struct {
+				Type        string            `json:"type"`
+				ClusterIP   string            `json:"clusterIP"`
+				ExternalIPs []string          `json:"externalIPs"`
+				Selector    map[string]string `json:"selector"`
+				Ports       []struct {
+					Port       int         `json:"port"`
+					NodePort   int         `json:"nodePort"`
+					Protocol   string      `json:"protocol"`
+					TargetPort interface{} `json:"targetPort"`
+				} `json:"ports"`
+			} `json:"spec"`
+			Status struct {
+				LoadBalancer struct {
+					Ingress []struct {
+						IP       string `json:"ip"`
+						Hostname string `json:"hostname"`
+					} `json:"ingress"`
+				} `json:"loadBalancer"`
+			} `json:"status"`
+		} `json:"items"`
+	}
+	if err := s.getJSON(ctx, sc, &list, kubectlArgs...); err != nil {
+		return nil, err
+	}
+
+	services := make([]map[string]interface{}, 0, len(list.Items))
+	for _, svc := range list.Items {
+		external := append([]string{}, svc.Spec.ExternalIPs...)
+		for _, in := range svc.Status.LoadBalancer.Ingress {
+			if in.IP != "" {
+				external = append(external, in.IP)
+			} else if in.Hostname != "" {
+				external = append(external, in.Hostname)
+			}
+		}
+		var ports []string
+		for _, p := range svc.Spec.Ports {
+			port := strconv.Itoa(p.Port)
+			if p.NodePort != 0 {
+				port += ":" + strconv.Itoa(p.NodePort)
+			}
+			ports = append(ports, fmt.Sprintf("%s/%s -> %v", port, p.Protocol, p.TargetPort))
+		}
+		services = append(services, map[string]interface{}{
+			"name":         svc.Metadata.Name,
+			"type":         svc.Spec.Type,
+			"cluster_ip":   svc.Spec.ClusterIP,
+			"external_ips": external,
+			"ports":        ports,
+			"selector":     svc.Spec.Selector,
+			"age":          age(svc.Metadata.CreationTimestamp),
+		})
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"scope":    sc,
+		"services": services,
+		"count":    len(services),
+	})
+}
+
+func (s *Server) getResourceTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "get_resource",
+		Description: "Get the full definition and status of a pod, deployment or service",
+		InputSchema: mcp.BuildInputSchema(
+			scopeProperties(map[string]interface{}{
+				"kind": kindProperty(),
+				"name": mcp.StringProperty("Resource name"),
+			}),
+			[]string{"kind", "name"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGetResource),
+	}
+}
+
+func (s *Server) handleGetResource(ctx context.Context, args resourceArgs) (*mcp.ToolResult, error) {
+	kind, err := getKind(args.Kind)
+	if err != nil {
+		return nil, err
+	}
+	name := args.Name
+	if err := checkName("name", name); err != nil {
+		return nil, err
+	}
+	sc, err := s.resolveScope(args.scopeArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	var obj map[string]interface{}
+	if err := s.getJSON(ctx, sc, &obj, kind, name); err != nil {
+		return nil, err
+	}
+	// Bookkeeping fields that only add noise.
+	if meta, ok := obj["metadata"].(map[string]interface{}); ok {
+		delete(meta, "managedFields")
+		if annotations, ok := meta["annotations"].(map[string]interface{}); ok {
+			delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+		}
+	}
+
+	return mcp.JSONResult(obj)
+}
+
+func (s *Server) describeResourceTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "describe_resource",
+		Description: "Describe a pod, deployment or service, including recent events (kubectl describe)",
+		InputSchema: mcp.BuildInputSchema(
+			scopeProperties(map[string]interface{}{
+				"kind": kindProperty(),
+				"name": mcp.StringProperty("Resource name"),
+			}),
+			[]string{"kind", "name"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleDescribeResource),
+	}
+}
+
+func (s *Server) handleDescribeResource(ctx context.Context, args resourceArgs) (*mcp.ToolResult, error) {
+	kind, err := getKind(args.Kind)
+	if err != nil {
+		return nil, err
+	}
+	name := args.Name
+	if err := checkName("name", name); err != nil {
+		return nil, err
+	}
+	sc, err := s.resolveScope(args.scopeArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := s.kubectl(ctx, sc, "describe", kind, name)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.TextResult(string(out)), nil
+}
+
+func (s *Server) podLogsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "pod_logs",
+		Description: "Get the logs of a pod's container",
+		InputSchema: mcp.BuildInputSchema(
+			scopeProperties(map[string]interface{}{
+				"pod":       mcp.StringProperty("Pod name"),
+				"container": mcp.StringProperty("Container name (required for pods with several containers)"),
+				"tail":      mcp.IntProperty("Number of lines from the end (default: 100)"),
+				"since":     mcp.StringProperty("Only logs since this time: RFC 3339 timestamp or duration such as \"10m\""),
+				"previous":  mcp.BoolProperty("Logs of the previous, terminated container instance"),
+			}),
+			[]string{"pod"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handlePodLogs),
+	}
+}
+
+type podLogsArgs struct {
+	scopeArgs
+	Pod       string `json:"pod" validate:"required"`
+	Container string `json:"container"`
+	Tail      int    `json:"tail" default:"100"`
+	Since     string `json:"since"`
+	Previous  bool   `json:"previous"`
+}
+
+func (s *Server) handlePodLogs(ctx context.Context, args podLogsArgs) (*mcp.ToolResult, error) {
+	pod, container, tail, since, previous := args.Pod, args.Container, args.Tail, args.Since, args.Previous
+	if err := checkName("pod", pod); err != nil {
+		return nil, err
+	}
+	sc, err := s.resolveScope(args.scopeArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	if tail <= 0 || tail > s.config.MaxLogLines {
+		tail = s.config.MaxLogLines
+	}
+	kubectlArgs := []string{"logs", pod, "--tail", strconv.Itoa(tail)}
+	if container != "" {
+		kubectlArgs = append(kubectlArgs, "--container", container)
+	}
+	if since != "" {
+		if _, err := time.ParseDuration(since); err == nil {
+			kubectlArgs = append(kubectlArgs, "--since", since)
+		} else if _, err := time.Parse(time.RFC3339, since); err == nil {
+			kubectlArgs = append(kubectlArgs, "--since-time", since)
+		} else {
+			return nil, fmt.Errorf("%w: since must be a duration or RFC 3339 time", common.ErrInvalidInput)
+		}
+	}
+	if previous {
+		kubectlArgs = append(kubectlArgs, "--previous")
+	}
+
+	out, err := s.kubectl(ctx, sc, kubectlArgs...)
+	if err != nil {
+		return nil, err
+	}
+	return mcp.TextResult(string(out)), nil
+}
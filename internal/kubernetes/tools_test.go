@@ -0,0 +1,178 @@
+package kubernetes
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// fakeKubectl puts a kubectl on PATH that records its arguments and runs
+// script.
+func fakeKubectl(t *testing.T, script string) (argsFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+	body := "#!/bin/sh\necho \"$@\" > " + argsFile + "\n" + script + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "kubectl"), []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argsFile
+}
+
+func newTestServer() *Server {
+	cfg := config.DefaultConfig().Kubernetes
+	cfg.AllowedContexts = []string{"dev", "staging"}
+	cfg.AllowedNamespaces = []string{"app", "default"}
+	cfg.TimeoutSeconds = 5
+	return NewServer(&cfg)
+}
+
+func TestResolveScope(t *testing.T) {
+	s := newTestServer()
+
+	sc, err := s.resolveScope(scopeArgs{})
+	if err != nil || sc.Context != "dev" || sc.Namespace != "app" {
+		t.Errorf("defaults: got %+v, %v", sc, err)
+	}
+	if _, err := s.resolveScope(scopeArgs{Namespace: "kube-system"}); !common.IsPermissionDenied(err) {
+		t.Errorf("namespace outside allowlist: got %v", err)
+	}
+	if _, err := s.resolveScope(scopeArgs{Context: "prod"}); !common.IsPermissionDenied(err) {
+		t.Errorf("context outside allowlist: got %v", err)
+	}
+}
+
+func TestListPods(t *testing.T) {
+	argsFile := fakeKubectl(t, `cat <<'EOF'
+{"items":[{"metadata":{"name":"web-1","creationTimestamp":"2024-01-01T00:00:00Z"},
+"spec":{"nodeName":"node-a"},
+"status":{"phase":"Running","containerStatuses":[
+{"ready":true,"restartCount":1,"state":{}},
+{"ready":false,"restartCount":4,"state":{"waiting":{"reason":"CrashLoopBackOff"}}}]}}]}
+EOF`)
+	s := newTestServer()
+
+	result, err := s.listPodsTool().Handler(context.Background(), map[string]interface{}{
+		"namespace":      "default",
+		"label_selector": "app=web",
+	})
+	if err != nil {
+		t.Fatalf("handleListPods: %v", err)
+	}
+	args, _ := os.ReadFile(argsFile)
+	if got := strings.TrimSpace(string(args)); got != "--context dev --namespace default get pods --selector app=web --output json" {
+		t.Errorf("kubectl args: %s", got)
+	}
+
+	var out struct {
+		Pods []map[string]interface{} `json:"pods"`
+	}
+	json.Unmarshal([]byte(result.Content[0].Text), &out)
+	if len(out.Pods) != 1 {
+		t.Fatalf("got %s", result.Content[0].Text)
+	}
+	pod := out.Pods[0]
+	if pod["ready"] != "1/2" || pod["status"] != "CrashLoopBackOff" || pod["restarts"] != float64(5) {
+		t.Errorf("got %v", pod)
+	}
+}
+
+func TestKubectlErrors(t *testing.T) {
+	fakeKubectl(t, `echo 'Error from server (NotFound): pods "nope" not found' >&2; exit 1`)
+	s := newTestServer()
+
+	_, err := s.describeResourceTool().Handler(context.Background(), map[string]interface{}{"kind": "pods", "name": "nope"})
+	if !common.IsNotFound(err) {
+		t.Errorf("got %v, want not found", err)
+	}
+	_, err = s.getResourceTool().Handler(context.Background(), map[string]interface{}{"kind": "secret", "name": "x"})
+	if err == nil || !strings.Contains(err.Error(), "kind must be one of") {
+		t.Errorf("secret kind: got %v", err)
+	}
+}
+
+func TestMissingKubectl(t *testing.T) {
+	t.Setenv("PATH", t.TempDir())
+	s := newTestServer()
+	s.config.AllowPortForward = true
+
+	if _, err := s.listPodsTool().Handler(context.Background(), map[string]interface{}{}); !errors.Is(err, errNoKubectl) {
+		t.Errorf("list_pods: got %v", err)
+	}
+	if _, err := s.portForwardStartTool().Handler(context.Background(), map[string]interface{}{
+		"kind": "service", "name": "web", "ports": []interface{}{"8080:80"},
+	}); !errors.Is(err, errNoKubectl) {
+		t.Errorf("port_forward_start: got %v", err)
+	}
+}
+
+func TestRejectsOptionNames(t *testing.T) {
+	argsFile := fakeKubectl(t, `echo '{}'`)
+	s := newTestServer()
+
+	calls := map[string]func(name string) error{
+		"get_resource": func(name string) error {
+			_, err := s.getResourceTool().Handler(context.Background(), map[string]interface{}{"kind": "pod", "name": name})
+			return err
+		},
+		"describe_resource": func(name string) error {
+			_, err := s.describeResourceTool().Handler(context.Background(), map[string]interface{}{"kind": "pod", "name": name})
+			return err
+		},
+		"pod_logs": func(name string) error {
+			_, err := s.podLogsTool().Handler(context.Background(), map[string]interface{}{"pod": name})
+			return err
+		},
+	}
+	for tool, call := range calls {
+		for _, name := range []string{"--namespace=kube-system", "--context=prod", "-A", "web 1", "Web"} {
+			var paramErr *mcp.ParamError
+			if err := call(name); !errors.As(err, &paramErr) {
+				t.Errorf("%s with name %q: got %v, want a parameter error", tool, name, err)
+			}
+		}
+	}
+	if _, err := os.Stat(argsFile); !os.IsNotExist(err) {
+		t.Errorf("kubectl ran for an invalid name")
+	}
+	if err := calls["get_resource"]("web-1.v2"); err != nil {
+		t.Errorf("valid name: %v", err)
+	}
+}
+
+func TestPortForward(t *testing.T) {
+	fakeKubectl(t, `echo "Forwarding from 127.0.0.1:8080 -> 80"; exec sleep 30`)
+	s := newTestServer()
+	params := map[string]interface{}{"kind": "service", "name": "web", "ports": []interface{}{"8080:80"}}
+
+	if _, err := s.portForwardStartTool().Handler(context.Background(), params); !common.IsPermissionDenied(err) {
+		t.Fatalf("port forward allowed without allow_port_forward: %v", err)
+	}
+	s.config.AllowPortForward = true
+
+	result, err := s.portForwardStartTool().Handler(context.Background(), params)
+	if err != nil {
+		t.Fatalf("handlePortForwardStart: %v", err)
+	}
+	var pf PortForward
+	json.Unmarshal([]byte(result.Content[0].Text), &pf)
+	if pf.Status != "running" || pf.Target != "service/web" || len(pf.Forwarding) != 1 {
+		t.Fatalf("got %s", result.Content[0].Text)
+	}
+
+	if _, err := s.portForwardStopTool().Handler(context.Background(), map[string]interface{}{"id": pf.ID}); err != nil {
+		t.Fatalf("handlePortForwardStop: %v", err)
+	}
+	if _, err := s.portForwardStopTool().Handler(context.Background(), map[string]interface{}{"id": pf.ID}); !common.IsNotFound(err) {
+		t.Errorf("second stop: got %v", err)
+	}
+}
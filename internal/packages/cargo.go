@@ -0,0 +1,151 @@
+package packages
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+type cargo struct{}
+
+var cargoKinds = map[string]string{
+	"":      "prod",
+	"dev":   "dev",
+	"build": "build",
+}
+
+// lockedVersions reads package versions from Cargo.lock. A crate locked at
+// several versions keeps the first.
+func lockedVersions(dir string) map[string]string {
+	versions := map[string]string{}
+	data, err := os.ReadFile(filepath.Join(dir, "Cargo.lock"))
+	if err != nil {
+		return versions
+	}
+	var name string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "[[package]]":
+			name = ""
+		case strings.HasPrefix(line, "name = "):
+			name = strings.Trim(strings.TrimPrefix(line, "name = "), `"`)
+		case strings.HasPrefix(line, "version = ") && name != "":
+			if _, ok := versions[name]; !ok {
+				versions[name] = strings.Trim(strings.TrimPrefix(line, "version = "), `"`)
+			}
+		}
+	}
+	return versions
+}
+
+// direct returns the dependencies declared by the workspace members.
+func (cargo) direct(ctx context.Context, p *project) ([]Dependency, error) {
+	stdout, _, err := p.run(ctx, []string{"cargo", "metadata", "--format-version", "1", "--no-deps"})
+	if err != nil {
+		return nil, err
+	}
+	var metadata struct {
+		Packages []struct {
+			Dependencies []struct {
+				Name string `json:"name"`
+				Req  string `json:"req"`
+				Kind string `json:"kind"`
+			} `json:"dependencies"`
+		} `json:"packages"`
+	}
+	if err := parseJSON(stdout, &metadata, "cargo metadata"); err != nil {
+		return nil, err
+	}
+
+	locked := lockedVersions(p.dir)
+	seen := map[string]bool{}
+	var deps []Dependency
+	for _, pkg := range metadata.Packages {
+		for _, d := range pkg.Dependencies {
+			depType := cargoKinds[d.Kind]
+			if seen[d.Name+"/"+depType] {
+				continue
+			}
+			seen[d.Name+"/"+depType] = true
+			deps = append(deps, Dependency{Name: d.Name, Version: locked[d.Name], Constraint: d.Req, Type: depType})
+		}
+	}
+	return deps, nil
+}
+
+func (c cargo) list(ctx context.Context, p *project) ([]Dependency, error) {
+	return c.direct(ctx, p)
+}
+
+// cargoUpdateRe matches the lines of `cargo update --dry-run --verbose`,
+// e.g. "Updating serde v1.0.1 -> v1.0.2" or
+// "Unchanged rand v0.7.3 (latest: v0.8.5)".
+var cargoUpdateRe = regexp.MustCompile(`^\s*(?:Updating|Unchanged)\s+(\S+)\s+v(\S+)(?:\s+->\s+v(\S+))?(?:\s+\(latest:\s+v([^)]+)\))?`)
+
+// outdated reports what `cargo update` would change, which stays within
+// the declared requirements, plus the newer releases cargo mentions.
+func (c cargo) outdated(ctx context.Context, p *project) ([]Dependency, error) {
+	direct, err := c.direct(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	// cargo reports the plan on stderr.
+	_, stderr, err := p.run(ctx, []string{"cargo", "update", "--dry-run", "--verbose"})
+	if err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	scanner := bufio.NewScanner(bytes.NewReader(stderr))
+	for scanner.Scan() {
+		m := cargoUpdateRe.FindStringSubmatch(scanner.Text())
+		if m == nil || (m[3] == "" && m[4] == "") {
+			continue
+		}
+		d := Dependency{Name: m[1], Version: m[2], Wanted: m[3], Latest: m[4], Indirect: true}
+		if declared, ok := p.find(direct, d.Name); ok {
+			d.Constraint = declared.Constraint
+			d.Type = declared.Type
+			d.Indirect = false
+		}
+		deps = append(deps, d)
+	}
+	return deps, nil
+}
+
+func (cargo) add(ctx context.Context, p *project, name, version string, dev bool) error {
+	return cargoAdd(ctx, p, name, version, typeFor(dev))
+}
+
+// upgrade rewrites the requirement with cargo add. cargo add keeps an
+// existing requirement when given no version, so without one this updates
+// the lockfile within the requirement instead.
+func (cargo) upgrade(ctx context.Context, p *project, dep Dependency, version string) error {
+	if version == "" {
+		_, _, err := p.run(ctx, []string{"cargo", "update", "--package", dep.Name})
+		return err
+	}
+	return cargoAdd(ctx, p, dep.Name, version, dep.Type)
+}
+
+func cargoAdd(ctx context.Context, p *project, name, version, depType string) error {
+	spec := name
+	if version != "" {
+		spec += "@" + version
+	}
+	args := []string{"cargo", "add", spec}
+	switch depType {
+	case "dev":
+		args = append(args, "--dev")
+	case "build":
+		args = append(args, "--build")
+	}
+	_, _, err := p.run(ctx, args)
+	return err
+}
@@ -0,0 +1,90 @@
+package packages
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+type goModules struct{}
+
+type goModule struct {
+	Path     string
+	Version  string
+	Main     bool
+	Indirect bool
+	Update   *struct {
+		Version string
+	}
+}
+
+func (goModules) modules(ctx context.Context, p *project, args ...string) ([]goModule, error) {
+	stdout, _, err := p.run(ctx, append([]string{"go", "list", "-m", "-json"}, args...))
+	if err != nil {
+		return nil, err
+	}
+	// go list prints a stream of objects, not an array.
+	var mods []goModule
+	dec := json.NewDecoder(bytes.NewReader(stdout))
+	for dec.More() {
+		var m goModule
+		if err := dec.Decode(&m); err != nil {
+			return nil, fmt.Errorf("%w: parsing go list output: %v", common.ErrOperationFailed, err)
+		}
+		if !m.Main {
+			mods = append(mods, m)
+		}
+	}
+	return mods, nil
+}
+
+func (g goModules) list(ctx context.Context, p *project) ([]Dependency, error) {
+	mods, err := g.modules(ctx, p, "all")
+	if err != nil {
+		return nil, err
+	}
+	var deps []Dependency
+	for _, m := range mods {
+		deps = append(deps, Dependency{Name: m.Path, Version: m.Version, Type: "prod", Indirect: m.Indirect})
+	}
+	return deps, nil
+}
+
+func (g goModules) outdated(ctx context.Context, p *project) ([]Dependency, error) {
+	mods, err := g.modules(ctx, p, "-u", "all")
+	if err != nil {
+		return nil, err
+	}
+	var deps []Dependency
+	for _, m := range mods {
+		if m.Update == nil {
+			continue
+		}
+		deps = append(deps, Dependency{
+			Name:     m.Path,
+			Version:  m.Version,
+			Latest:   m.Update.Version,
+			Type:     "prod",
+			Indirect: m.Indirect,
+		})
+	}
+	return deps, nil
+}
+
+func (goModules) add(ctx context.Context, p *project, name, version string, dev bool) error {
+	if dev {
+		return fmt.Errorf("%w: go modules have no dev dependencies", common.ErrInvalidInput)
+	}
+	if version == "" {
+		version = "latest"
+	}
+	_, _, err := p.run(ctx, []string{"go", "get", name + "@" + version})
+	return err
+}
+
+func (g goModules) upgrade(ctx context.Context, p *project, dep Dependency, version string) error {
+	return g.add(ctx, p, dep.Name, version, false)
+}
@@ -0,0 +1,239 @@
+package packages
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+// packageJSON holds the declared dependencies of a package.json, which
+// npm ls does not split by type.
+type packageJSON struct {
+	Dependencies         map[string]string `json:"dependencies"`
+	DevDependencies      map[string]string `json:"devDependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+}
+
+func readPackageJSON(dir string) (*packageJSON, error) {
+	data, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", common.ErrNotFound, err)
+	}
+	var pkg packageJSON
+	if err := json.Unmarshal(data, &pkg); err != nil {
+		return nil, fmt.Errorf("%w: parsing package.json: %v", common.ErrInvalidInput, err)
+	}
+	return &pkg, nil
+}
+
+// declared returns the dependency type and constraint for name.
+func (pkg *packageJSON) declared(name string) (depType, constraint string) {
+	if c, ok := pkg.DevDependencies[name]; ok {
+		return "dev", c
+	}
+	if c, ok := pkg.OptionalDependencies[name]; ok {
+		return "optional", c
+	}
+	if c, ok := pkg.Dependencies[name]; ok {
+		return "prod", c
+	}
+	return "", ""
+}
+
+func parseJSON(data []byte, v interface{}, command string) error {
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("%w: parsing %s output: %v", common.ErrOperationFailed, command, err)
+	}
+	return nil
+}
+
+type npm struct{}
+
+func (npm) list(ctx context.Context, p *project) ([]Dependency, error) {
+	pkg, err := readPackageJSON(p.dir)
+	if err != nil {
+		return nil, err
+	}
+	// npm ls exits with 1 for missing or invalid packages but still
+	// prints the tree.
+	stdout, _, err := p.run(ctx, []string{"npm", "ls", "--json", "--depth=0"}, 1)
+	if err != nil {
+		return nil, err
+	}
+	var tree struct {
+		Dependencies map[string]struct {
+			Version string `json:"version"`
+		} `json:"dependencies"`
+	}
+	if err := parseJSON(stdout, &tree, "npm ls"); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	for name, d := range tree.Dependencies {
+		depType, constraint := pkg.declared(name)
+		deps = append(deps, Dependency{Name: name, Version: d.Version, Constraint: constraint, Type: depType})
+	}
+	return deps, nil
+}
+
+func (npm) outdated(ctx context.Context, p *project) ([]Dependency, error) {
+	pkg, err := readPackageJSON(p.dir)
+	if err != nil {
+		return nil, err
+	}
+	stdout, _, err := p.run(ctx, []string{"npm", "outdated", "--json"}, 1)
+	if err != nil {
+		return nil, err
+	}
+	var report map[string]struct {
+		Current string `json:"current"`
+		Wanted  string `json:"wanted"`
+		Latest  string `json:"latest"`
+	}
+	if len(stdout) > 0 {
+		if err := parseJSON(stdout, &report, "npm outdated"); err != nil {
+			return nil, err
+		}
+	}
+
+	var deps []Dependency
+	for name, d := range report {
+		depType, constraint := pkg.declared(name)
+		deps = append(deps, Dependency{
+			Name:       name,
+			Version:    d.Current,
+			Constraint: constraint,
+			Wanted:     d.Wanted,
+			Latest:     d.Latest,
+			Type:       depType,
+		})
+	}
+	return deps, nil
+}
+
+func (npm) add(ctx context.Context, p *project, name, version string, dev bool) error {
+	return nodeInstall(ctx, p, "npm", "install", name, version, typeFor(dev))
+}
+
+func (npm) upgrade(ctx context.Context, p *project, dep Dependency, version string) error {
+	return nodeInstall(ctx, p, "npm", "install", dep.Name, version, dep.Type)
+}
+
+// nodeInstall installs name@version (the latest release by default) into
+// the section for depType. npm and pnpm share the flags.
+func nodeInstall(ctx context.Context, p *project, command, subcommand, name, version, depType string) error {
+	if version == "" {
+		version = "latest"
+	}
+	args := []string{command, subcommand, name + "@" + version}
+	switch depType {
+	case "dev":
+		args = append(args, "--save-dev")
+	case "optional":
+		args = append(args, "--save-optional")
+	}
+	_, _, err := p.run(ctx, args)
+	return err
+}
+
+func typeFor(dev bool) string {
+	if dev {
+		return "dev"
+	}
+	return "prod"
+}
+
+type pnpm struct{}
+
+var pnpmTypes = map[string]string{
+	"dependencies":         "prod",
+	"devDependencies":      "dev",
+	"optionalDependencies": "optional",
+}
+
+func (pnpm) list(ctx context.Context, p *project) ([]Dependency, error) {
+	pkg, err := readPackageJSON(p.dir)
+	if err != nil {
+		return nil, err
+	}
+	stdout, _, err := p.run(ctx, []string{"pnpm", "ls", "--json", "--depth=0"})
+	if err != nil {
+		return nil, err
+	}
+	type entry struct {
+		Version string `json:"version"`
+	}
+	// One element per workspace project; outside a workspace there is one.
+	var projects []struct {
+		Dependencies         map[string]entry `json:"dependencies"`
+		DevDependencies      map[string]entry `json:"devDependencies"`
+		OptionalDependencies map[string]entry `json:"optionalDependencies"`
+	}
+	if err := parseJSON(stdout, &projects, "pnpm ls"); err != nil {
+		return nil, err
+	}
+
+	var deps []Dependency
+	for _, proj := range projects {
+		for depType, entries := range map[string]map[string]entry{
+			"prod":     proj.Dependencies,
+			"dev":      proj.DevDependencies,
+			"optional": proj.OptionalDependencies,
+		} {
+			for name, e := range entries {
+				_, constraint := pkg.declared(name)
+				deps = append(deps, Dependency{Name: name, Version: e.Version, Constraint: constraint, Type: depType})
+			}
+		}
+	}
+	return deps, nil
+}
+
+func (pnpm) outdated(ctx context.Context, p *project) ([]Dependency, error) {
+	pkg, err := readPackageJSON(p.dir)
+	if err != nil {
+		return nil, err
+	}
+	stdout, _, err := p.run(ctx, []string{"pnpm", "outdated", "--format", "json"}, 1)
+	if err != nil {
+		return nil, err
+	}
+	var report map[string]struct {
+		Current        string `json:"current"`
+		Wanted         string `json:"wanted"`
+		Latest         string `json:"latest"`
+		DependencyType string `json:"dependencyType"`
+	}
+	if len(stdout) > 0 {
+		if err := parseJSON(stdout, &report, "pnpm outdated"); err != nil {
+			return nil, err
+		}
+	}
+
+	var deps []Dependency
+	for name, d := range report {
+		_, constraint := pkg.declared(name)
+		deps = append(deps, Dependency{
+			Name:       name,
+			Version:    d.Current,
+			Constraint: constraint,
+			Wanted:     d.Wanted,
+			Latest:     d.Latest,
+			Type:       pnpmTypes[d.DependencyType],
+		})
+	}
+	return deps, nil
+}
+
+func (pnpm) add(ctx context.Context, p *project, name, version string, dev bool) error {
+	return nodeInstall(ctx, p, "pnpm", "add", name, version, typeFor(dev))
+}
+
+func (pnpm) upgrade(ctx context.Context, p *project, dep Dependency, version string) error {
+	return nodeInstall(ctx, p, "pnpm", "add", dep.Name, version, dep.Type)
+}
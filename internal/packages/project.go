@@ -0,0 +1,209 @@
+package packages
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// Dependency is one entry in a project's dependency list. Which fields are
+// set depends on the manager and the tool: Constraint is the requirement
+// declared in the manifest, Version the installed or locked version, Wanted
+// the newest version the constraint allows and Latest the newest release.
+type Dependency struct {
+	Name       string `json:"name"`
+	Version    string `json:"version,omitempty"`
+	Constraint string `json:"constraint,omitempty"`
+	Wanted     string `json:"wanted,omitempty"`
+	Latest     string `json:"latest,omitempty"`
+	// Type is prod, dev, optional or build.
+	Type     string `json:"type,omitempty"`
+	Indirect bool   `json:"indirect,omitempty"`
+}
+
+// manager adapts one package manager's CLI. upgrade is given the current
+// entry so that it can keep the dependency in its section.
+type manager interface {
+	list(ctx context.Context, p *project) ([]Dependency, error)
+	outdated(ctx context.Context, p *project) ([]Dependency, error)
+	add(ctx context.Context, p *project, name, version string, dev bool) error
+	upgrade(ctx context.Context, p *project, dep Dependency, version string) error
+}
+
+var managers = map[string]manager{
+	"go":     goModules{},
+	"npm":    npm{},
+	"pnpm":   pnpm{},
+	"pip":    pip{},
+	"poetry": poetry{},
+	"cargo":  cargo{},
+}
+
+// detection maps marker files to managers, most specific first: a pnpm
+// lockfile wins over package.json, a poetry project over plain pip.
+var detection = []struct {
+	file    string
+	manager string
+	// contains, if set, must appear in the file.
+	contains string
+}{
+	{"go.mod", "go", ""},
+	{"pnpm-lock.yaml", "pnpm", ""},
+	{"package.json", "npm", ""},
+	{"poetry.lock", "poetry", ""},
+	{"pyproject.toml", "poetry", "[tool.poetry]"},
+	{"requirements.txt", "pip", ""},
+	{"pyproject.toml", "pip", ""},
+	{"setup.py", "pip", ""},
+	{"Cargo.toml", "cargo", ""},
+}
+
+func detectManager(dir string) (string, error) {
+	for _, d := range detection {
+		data, err := os.ReadFile(filepath.Join(dir, d.file))
+		if err != nil {
+			continue
+		}
+		if d.contains == "" || bytes.Contains(data, []byte(d.contains)) {
+			return d.manager, nil
+		}
+	}
+	return "", fmt.Errorf("%w: no go.mod, package.json, pyproject.toml, requirements.txt or Cargo.toml in %s; pass manager explicitly",
+		common.ErrNotFound, dir)
+}
+
+// project is a resolved project directory and the manager that runs in it.
+type project struct {
+	dir     string
+	name    string
+	manager manager
+	timeout time.Duration
+}
+
+func managerNames() []string {
+	names := make([]string, 0, len(managers))
+	for name := range managers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func projectProperties(props map[string]interface{}) map[string]interface{} {
+	props["project_dir"] = mcp.StringProperty("Project directory")
+	props["manager"] = mcp.StringProperty("One of " + strings.Join(managerNames(), ", ") + "; detected from the project files if omitted")
+	return props
+}
+
+// projectArgs are the arguments for projectProperties.
+type projectArgs struct {
+	ProjectDir string `json:"project_dir" validate:"required"`
+	Manager    string `json:"manager"`
+}
+
+func (s *Server) project(args projectArgs) (*project, error) {
+	name := args.Manager
+	dir, err := s.validator.ResolvePath(args.ProjectDir)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		if name, err = detectManager(dir); err != nil {
+			return nil, err
+		}
+	}
+	m, ok := managers[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: manager must be one of %s", common.ErrInvalidInput, strings.Join(managerNames(), ", "))
+	}
+	return &project{
+		dir:     dir,
+		name:    name,
+		manager: m,
+		timeout: time.Duration(s.config.TimeoutSeconds) * time.Second,
+	}, nil
+}
+
+// run runs a command in the project directory. A non-zero exit is an
+// error unless its code is in okCodes; npm and pnpm, for example, exit
+// with 1 when they report outdated packages.
+func (p *project) run(ctx context.Context, cmdline []string, okCodes ...int) (stdout, stderr []byte, err error) {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, cmdline[0], cmdline[1:]...)
+	cmd.Dir = p.dir
+	var out, errOut bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &errOut
+
+	err = cmd.Run()
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, nil, fmt.Errorf("%w: %s did not finish within %s", common.ErrTimeout, strings.Join(cmdline[:2], " "), p.timeout)
+	}
+	if errors.Is(err, exec.ErrNotFound) {
+		return nil, nil, fmt.Errorf("%w: %s is not installed", common.ErrNotFound, cmdline[0])
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, nil, fmt.Errorf("%w: running %s: %v", common.ErrOperationFailed, cmdline[0], err)
+		}
+		if !containsInt(okCodes, exitErr.ExitCode()) {
+			msg := strings.TrimSpace(errOut.String())
+			if msg == "" {
+				msg = strings.TrimSpace(out.String())
+			}
+			return nil, nil, common.WithDetails(
+				fmt.Errorf("%w: %s: %s", common.ErrOperationFailed, strings.Join(cmdline, " "), msg),
+				"exit_code", exitErr.ExitCode())
+		}
+	}
+	return out.Bytes(), errOut.Bytes(), nil
+}
+
+func containsInt(list []int, n int) bool {
+	for _, v := range list {
+		if v == n {
+			return true
+		}
+	}
+	return false
+}
+
+// find looks up a dependency by name. Python package names compare
+// case-insensitively with -, _ and . equivalent (PEP 503).
+func (p *project) find(deps []Dependency, name string) (Dependency, bool) {
+	normalize := func(n string) string { return n }
+	if p.name == "pip" || p.name == "poetry" {
+		normalize = func(n string) string {
+			return strings.NewReplacer("_", "-", ".", "-").Replace(strings.ToLower(n))
+		}
+	}
+	for _, d := range deps {
+		if normalize(d.Name) == normalize(name) {
+			return d, true
+		}
+	}
+	return Dependency{}, false
+}
+
+func sortDependencies(deps []Dependency) []Dependency {
+	if deps == nil {
+		deps = []Dependency{}
+	}
+	sort.Slice(deps, func(i, j int) bool {
+		return deps[i].Name < deps[j].Name
+	})
+	return deps
+}
@@ -0,0 +1,159 @@
+package packages
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+// python returns the project's virtualenv interpreter if it has one, so
+// pip reports and changes the project's packages rather than the system's.
+func python(dir string) string {
+	for _, venv := range []string{".venv", "venv"} {
+		path := filepath.Join(dir, venv, "bin", "python")
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return "python3"
+}
+
+// pip manages the packages installed in the environment; it does not edit
+// requirements files.
+type pip struct{}
+
+func (pip) packages(ctx context.Context, p *project, args ...string) ([]Dependency, error) {
+	cmdline := append([]string{python(p.dir), "-m", "pip", "list", "--format", "json", "--disable-pip-version-check"}, args...)
+	stdout, _, err := p.run(ctx, cmdline)
+	if err != nil {
+		return nil, err
+	}
+	var report []struct {
+		Name          string `json:"name"`
+		Version       string `json:"version"`
+		LatestVersion string `json:"latest_version"`
+	}
+	if err := parseJSON(stdout, &report, "pip list"); err != nil {
+		return nil, err
+	}
+
+	deps := make([]Dependency, 0, len(report))
+	for _, r := range report {
+		deps = append(deps, Dependency{Name: r.Name, Version: r.Version, Latest: r.LatestVersion})
+	}
+	return deps, nil
+}
+
+func (pp pip) list(ctx context.Context, p *project) ([]Dependency, error) {
+	return pp.packages(ctx, p)
+}
+
+func (pp pip) outdated(ctx context.Context, p *project) ([]Dependency, error) {
+	return pp.packages(ctx, p, "--outdated")
+}
+
+// pipSpec is name==version; versions that start with a comparison operator
+// are passed through as specifiers, e.g. ">=2,<3".
+func pipSpec(name, version string) string {
+	if version == "" || strings.ContainsAny(version[:1], "=<>!~") {
+		return name + version
+	}
+	return name + "==" + version
+}
+
+func (pip) add(ctx context.Context, p *project, name, version string, dev bool) error {
+	if dev {
+		return fmt.Errorf("%w: pip has no dev dependencies; use poetry or a separate requirements file", common.ErrInvalidInput)
+	}
+	_, _, err := p.run(ctx, []string{python(p.dir), "-m", "pip", "install", "--disable-pip-version-check", pipSpec(name, version)})
+	return err
+}
+
+func (pip) upgrade(ctx context.Context, p *project, dep Dependency, version string) error {
+	_, _, err := p.run(ctx, []string{python(p.dir), "-m", "pip", "install", "--disable-pip-version-check", "--upgrade", pipSpec(dep.Name, version)})
+	return err
+}
+
+type poetry struct{}
+
+// show parses the columns of `poetry show`, which has no JSON output:
+// name, an optional "(!)" marking packages that are not installed, the
+// version and, with --outdated, the latest version, then the description.
+func (poetry) show(ctx context.Context, p *project, args ...string) ([]Dependency, error) {
+	stdout, _, err := p.run(ctx, append([]string{"poetry", "show", "--no-ansi"}, args...))
+	if err != nil {
+		return nil, err
+	}
+	outdated := len(args) > 0 && args[0] == "--outdated"
+
+	var deps []Dependency
+	scanner := bufio.NewScanner(bytes.NewReader(stdout))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) > 1 && fields[1] == "(!)" {
+			fields = append(fields[:1], fields[2:]...)
+		}
+		if len(fields) < 2 || (outdated && len(fields) < 3) {
+			continue
+		}
+		d := Dependency{Name: fields[0], Version: fields[1], Type: "prod"}
+		if outdated {
+			d.Latest = fields[2]
+		}
+		deps = append(deps, d)
+	}
+	return deps, nil
+}
+
+// markDev sets the type of the packages in poetry's dev group. Older
+// poetry versions have no groups; their packages all stay prod.
+func (pt poetry) markDev(ctx context.Context, p *project, deps []Dependency) []Dependency {
+	dev, err := pt.show(ctx, p, "--only", "dev")
+	if err != nil {
+		return deps
+	}
+	for i := range deps {
+		if _, ok := p.find(dev, deps[i].Name); ok {
+			deps[i].Type = "dev"
+		}
+	}
+	return deps
+}
+
+func (pt poetry) list(ctx context.Context, p *project) ([]Dependency, error) {
+	deps, err := pt.show(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	return pt.markDev(ctx, p, deps), nil
+}
+
+func (pt poetry) outdated(ctx context.Context, p *project) ([]Dependency, error) {
+	deps, err := pt.show(ctx, p, "--outdated")
+	if err != nil {
+		return nil, err
+	}
+	return pt.markDev(ctx, p, deps), nil
+}
+
+func (poetry) add(ctx context.Context, p *project, name, version string, dev bool) error {
+	if version == "" {
+		version = "latest"
+	}
+	args := []string{"poetry", "add", "--no-ansi", name + "@" + version}
+	if dev {
+		args = append(args, "--group", "dev")
+	}
+	_, _, err := p.run(ctx, args)
+	return err
+}
+
+func (pt poetry) upgrade(ctx context.Context, p *project, dep Dependency, version string) error {
+	return pt.add(ctx, p, dep.Name, version, dep.Type == "dev")
+}
@@ -0,0 +1,50 @@
+package packages
+
+import (
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type Server struct {
+	config    *config.PackagesConfig
+	validator *common.PathValidator
+	logger    *common.Logger
+}
+
+func NewServer(cfg *config.PackagesConfig) *Server {
+	s := &Server{
+		config: cfg,
+		logger: common.NewServerLogger("packages"),
+	}
+	s.Reload()
+	common.RegisterConfigSection("packages", s.configSection)
+	return s
+}
+
+func (s *Server) Reload() {
+	s.validator = common.NewPathValidator(s.config.AllowedProjectPaths, nil, true)
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		s.listDependenciesTool(),
+		s.checkOutdatedTool(),
+		common.Audited("packages", s.addDependencyTool()),
+		common.Audited("packages", s.upgradeDependencyTool()),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
+		common.AuditLogTool(),
+	}
+	return common.Instrument("packages", tools)
+}
+
+func (s *Server) RegisterTools(server *mcp.Server) {
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
+}
@@ -0,0 +1,198 @@
+package packages
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// validateSpec rejects package names and versions that a manager could
+// read as an option or that could not be a single argument. Names may not
+// contain @ after the first character (npm scopes start with one), since
+// the managers use it to separate the version.
+func validateSpec(what, v string) error {
+	if strings.HasPrefix(v, "-") || strings.ContainsFunc(v, func(r rune) bool { return unicode.IsSpace(r) || unicode.IsControl(r) }) {
+		return fmt.Errorf("%w: invalid %s %q", common.ErrInvalidInput, what, v)
+	}
+	if what == "package" && strings.Contains(strings.TrimPrefix(v, "@"), "@") {
+		return fmt.Errorf("%w: pass the version separately from package %q", common.ErrInvalidInput, v)
+	}
+	return nil
+}
+
+func (s *Server) listDependenciesTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "list_dependencies",
+		Description: "List a project's dependencies with their installed versions (go, npm, pnpm, pip, poetry or cargo). For pip this is the packages in the project's virtualenv, or the system's",
+		InputSchema: mcp.BuildInputSchema(
+			projectProperties(map[string]interface{}{}),
+			[]string{"project_dir"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleListDependencies),
+	}
+}
+
+func (s *Server) handleListDependencies(ctx context.Context, args projectArgs) (*mcp.ToolResult, error) {
+	p, err := s.project(args)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := p.manager.list(ctx, p)
+	if err != nil {
+		return nil, common.WithDetails(err, "manager", p.name)
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"project_dir":  p.dir,
+		"manager":      p.name,
+		"dependencies": sortDependencies(deps),
+		"count":        len(deps),
+	})
+}
+
+func (s *Server) checkOutdatedTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "check_outdated",
+		Description: "List a project's dependencies that have newer versions, with the newest version the constraint allows (wanted) and the newest release (latest) where the manager reports them",
+		InputSchema: mcp.BuildInputSchema(
+			projectProperties(map[string]interface{}{}),
+			[]string{"project_dir"},
+		),
+		Annotations: mcp.ReadOnly().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleCheckOutdated),
+	}
+}
+
+func (s *Server) handleCheckOutdated(ctx context.Context, args projectArgs) (*mcp.ToolResult, error) {
+	p, err := s.project(args)
+	if err != nil {
+		return nil, err
+	}
+	deps, err := p.manager.outdated(ctx, p)
+	if err != nil {
+		return nil, common.WithDetails(err, "manager", p.name)
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"project_dir": p.dir,
+		"manager":     p.name,
+		"outdated":    sortDependencies(deps),
+		"count":       len(deps),
+	})
+}
+
+func (s *Server) addDependencyTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "add_dependency",
+		Description: "Add a dependency to a project (requires packages.allow_modify)",
+		InputSchema: mcp.BuildInputSchema(
+			projectProperties(map[string]interface{}{
+				"package": mcp.StringProperty("Package name or Go module path"),
+				"version": mcp.StringProperty("Version or constraint; the latest release if omitted"),
+				"dev":     mcp.BoolProperty("Add as a development dependency (npm, pnpm, poetry, cargo)"),
+			}),
+			[]string{"project_dir", "package"},
+		),
+		Annotations: mcp.Additive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleAddDependency),
+	}
+}
+
+type modifyArgs struct {
+	projectArgs
+	Package string `json:"package" validate:"required"`
+	Version string `json:"version"`
+	Dev     bool   `json:"dev"`
+}
+
+func (s *Server) handleAddDependency(ctx context.Context, args modifyArgs) (*mcp.ToolResult, error) {
+	return s.modify(ctx, args, false)
+}
+
+func (s *Server) upgradeDependencyTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "upgrade_dependency",
+		Description: "Upgrade an existing dependency to a version, or to the latest release (for cargo, the newest version its requirement allows). Requires packages.allow_modify",
+		InputSchema: mcp.BuildInputSchema(
+			projectProperties(map[string]interface{}{
+				"package": mcp.StringProperty("Package name or Go module path"),
+				"version": mcp.StringProperty("Target version or constraint"),
+			}),
+			[]string{"project_dir", "package"},
+		),
+		Annotations: mcp.Destructive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleUpgradeDependency),
+	}
+}
+
+func (s *Server) handleUpgradeDependency(ctx context.Context, args modifyArgs) (*mcp.ToolResult, error) {
+	return s.modify(ctx, args, true)
+}
+
+// modify adds or upgrades a dependency, then lists the dependencies again
+// to report the version the manager settled on.
+func (s *Server) modify(ctx context.Context, args modifyArgs, upgrade bool) (*mcp.ToolResult, error) {
+	if !s.config.AllowModify {
+		return nil, fmt.Errorf("%w: changing dependencies is disabled (set packages.allow_modify)", common.ErrPermissionDenied)
+	}
+	name, version, dev := args.Package, args.Version, args.Dev
+	if err := validateSpec("package", name); err != nil {
+		return nil, err
+	}
+	if err := validateSpec("version", version); err != nil {
+		return nil, err
+	}
+	p, err := s.project(args.projectArgs)
+	if err != nil {
+		return nil, err
+	}
+
+	before, err := p.manager.list(ctx, p)
+	if err != nil {
+		return nil, common.WithDetails(err, "manager", p.name)
+	}
+	previous, exists := p.find(before, name)
+
+	start := time.Now()
+	if upgrade {
+		if !exists {
+			return nil, common.WithDetails(
+				fmt.Errorf("%w: %s is not a dependency of %s", common.ErrNotFound, name, p.dir),
+				"manager", p.name)
+		}
+		err = p.manager.upgrade(ctx, p, previous, version)
+	} else {
+		err = p.manager.add(ctx, p, name, version, dev)
+	}
+	if err != nil {
+		return nil, common.WithDetails(err, "manager", p.name, "package", name)
+	}
+	s.logger.WithFields(map[string]interface{}{
+		"manager":     p.name,
+		"project_dir": p.dir,
+		"package":     name,
+		"version":     version,
+	}).Info("Dependency changed")
+
+	after, err := p.manager.list(ctx, p)
+	if err != nil {
+		return nil, common.WithDetails(err, "manager", p.name)
+	}
+	result := map[string]interface{}{
+		"project_dir": p.dir,
+		"manager":     p.name,
+		"package":     name,
+		"duration_ms": time.Since(start).Milliseconds(),
+	}
+	if exists {
+		result["previous_version"] = previous.Version
+	}
+	if dep, ok := p.find(after, name); ok {
+		result["dependency"] = dep
+	}
+	return mcp.JSONResult(result)
+}
@@ -0,0 +1,180 @@
+package packages
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+// fakeCLI puts a command on PATH that appends its arguments to a log and
+// runs script.
+func fakeCLI(t *testing.T, name, script string) (argsFile string) {
+	t.Helper()
+	dir := t.TempDir()
+	argsFile = filepath.Join(dir, "args")
+	body := "#!/bin/sh\necho \"$@\" >> " + argsFile + "\n" + script + "\n"
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return argsFile
+}
+
+func newTestServer(t *testing.T) (*Server, string) {
+	cfg := config.DefaultConfig().Packages
+	cfg.AllowedProjectPaths = []string{t.TempDir()}
+	cfg.TimeoutSeconds = 5
+	return NewServer(&cfg), cfg.AllowedProjectPaths[0]
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetectManager(t *testing.T) {
+	tests := []struct {
+		files map[string]string
+		want  string
+	}{
+		{map[string]string{"go.mod": "module x"}, "go"},
+		{map[string]string{"package.json": "{}", "pnpm-lock.yaml": ""}, "pnpm"},
+		{map[string]string{"package.json": "{}"}, "npm"},
+		{map[string]string{"pyproject.toml": "[tool.poetry]\nname = \"x\""}, "poetry"},
+		{map[string]string{"pyproject.toml": "[project]\nname = \"x\""}, "pip"},
+		{map[string]string{"Cargo.toml": "[package]"}, "cargo"},
+	}
+	for _, tt := range tests {
+		dir := t.TempDir()
+		for name, content := range tt.files {
+			writeFile(t, filepath.Join(dir, name), content)
+		}
+		if got, err := detectManager(dir); err != nil || got != tt.want {
+			t.Errorf("%v: got %q, %v; want %q", tt.files, got, err, tt.want)
+		}
+	}
+	if _, err := detectManager(t.TempDir()); !common.IsNotFound(err) {
+		t.Errorf("empty dir: got %v", err)
+	}
+}
+
+func TestNpmOutdated(t *testing.T) {
+	// npm outdated exits with 1 when it finds something.
+	fakeCLI(t, "npm", `cat <<'EOF'
+{"react":{"current":"18.2.0","wanted":"18.3.1","latest":"19.0.0"},
+ "eslint":{"current":"8.0.0","wanted":"8.57.0","latest":"9.1.0"}}
+EOF
+exit 1`)
+	s, dir := newTestServer(t)
+	writeFile(t, filepath.Join(dir, "package.json"),
+		`{"dependencies":{"react":"^18.2.0"},"devDependencies":{"eslint":"^8.0.0"}}`)
+
+	result, err := s.checkOutdatedTool().Handler(context.Background(), map[string]interface{}{"project_dir": dir})
+	if err != nil {
+		t.Fatalf("handleCheckOutdated: %v", err)
+	}
+	var out struct {
+		Manager  string       `json:"manager"`
+		Outdated []Dependency `json:"outdated"`
+	}
+	json.Unmarshal([]byte(result.Content[0].Text), &out)
+	want := []Dependency{
+		{Name: "eslint", Version: "8.0.0", Constraint: "^8.0.0", Wanted: "8.57.0", Latest: "9.1.0", Type: "dev"},
+		{Name: "react", Version: "18.2.0", Constraint: "^18.2.0", Wanted: "18.3.1", Latest: "19.0.0", Type: "prod"},
+	}
+	if out.Manager != "npm" || len(out.Outdated) != 2 || out.Outdated[0] != want[0] || out.Outdated[1] != want[1] {
+		t.Errorf("got %s", result.Content[0].Text)
+	}
+}
+
+func TestCargoOutdated(t *testing.T) {
+	fakeCLI(t, "cargo", `case "$1" in
+metadata) echo '{"packages":[{"dependencies":[{"name":"serde","req":"^1.0","kind":null},{"name":"rand","req":"^0.7","kind":"dev"}]}]}' ;;
+update) cat >&2 <<'EOF'
+    Updating crates.io index
+     Locking 1 package to latest compatible version
+    Updating serde v1.0.100 -> v1.0.203
+    Updating itoa v1.0.1 -> v1.0.11
+   Unchanged rand v0.7.3 (latest: v0.8.5)
+   Unchanged libc v0.2.150
+EOF
+;;
+esac`)
+	s, dir := newTestServer(t)
+
+	result, err := s.checkOutdatedTool().Handler(context.Background(), map[string]interface{}{"project_dir": dir, "manager": "cargo"})
+	if err != nil {
+		t.Fatalf("handleCheckOutdated: %v", err)
+	}
+	var out struct {
+		Outdated []Dependency `json:"outdated"`
+	}
+	json.Unmarshal([]byte(result.Content[0].Text), &out)
+	want := []Dependency{
+		{Name: "itoa", Version: "1.0.1", Wanted: "1.0.11", Indirect: true},
+		{Name: "rand", Version: "0.7.3", Constraint: "^0.7", Latest: "0.8.5", Type: "dev"},
+		{Name: "serde", Version: "1.0.100", Constraint: "^1.0", Wanted: "1.0.203", Type: "prod"},
+	}
+	if len(out.Outdated) != len(want) {
+		t.Fatalf("got %s", result.Content[0].Text)
+	}
+	for i := range want {
+		if out.Outdated[i] != want[i] {
+			t.Errorf("got %+v, want %+v", out.Outdated[i], want[i])
+		}
+	}
+}
+
+func TestModifyDependency(t *testing.T) {
+	argsFile := fakeCLI(t, "go", `case "$2" in
+-m) echo '{"Path":"example.com/app","Main":true}{"Path":"golang.org/x/text","Version":"v0.14.0"}' ;;
+esac`)
+	s, dir := newTestServer(t)
+	writeFile(t, filepath.Join(dir, "go.mod"), "module example.com/app\n")
+	params := map[string]interface{}{"project_dir": dir, "package": "golang.org/x/text", "version": "v0.15.0"}
+
+	if _, err := s.upgradeDependencyTool().Handler(context.Background(), params); !common.IsPermissionDenied(err) {
+		t.Fatalf("upgrade allowed without allow_modify: %v", err)
+	}
+	s.config.AllowModify = true
+
+	for _, bad := range []map[string]interface{}{
+		{"project_dir": dir, "package": "--insecure"},
+		{"project_dir": dir, "package": "golang.org/x/text@v0.15.0"},
+		{"project_dir": dir, "package": "golang.org/x/text", "version": "v1 -x"},
+	} {
+		if _, err := s.addDependencyTool().Handler(context.Background(), bad); err == nil {
+			t.Errorf("%v: accepted", bad)
+		}
+	}
+	if _, err := s.upgradeDependencyTool().Handler(context.Background(), map[string]interface{}{
+		"project_dir": dir, "package": "golang.org/x/net",
+	}); !common.IsNotFound(err) {
+		t.Errorf("upgrading a missing dependency: got %v", err)
+	}
+
+	result, err := s.upgradeDependencyTool().Handler(context.Background(), params)
+	if err != nil {
+		t.Fatalf("handleUpgradeDependency: %v", err)
+	}
+	args, _ := os.ReadFile(argsFile)
+	if !strings.Contains(string(args), "get golang.org/x/text@v0.15.0\n") {
+		t.Errorf("go args:\n%s", args)
+	}
+	var out struct {
+		PreviousVersion string     `json:"previous_version"`
+		Dependency      Dependency `json:"dependency"`
+	}
+	json.Unmarshal([]byte(result.Content[0].Text), &out)
+	if out.PreviousVersion != "v0.14.0" || out.Dependency.Name != "golang.org/x/text" {
+		t.Errorf("got %s", result.Content[0].Text)
+	}
+}
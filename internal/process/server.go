@@ -1,29 +1,56 @@
 package process
 
 import (
+	"context"
+
 	"github.com/local-mcps/dev-mcps/config"
 	"github.com/local-mcps/dev-mcps/internal/common"
 	"github.com/local-mcps/dev-mcps/pkg/mcp"
 )
 
 type Server struct {
-	config *config.ProcessConfig
-	logger *common.Logger
+	config     *config.ProcessConfig
+	logger     *common.Logger
+	sessionEnv *common.SessionEnv
 }
 
 func NewServer(cfg *config.ProcessConfig) *Server {
-	return &Server{
-		config: cfg,
-		logger: common.NewLogger(common.LogLevelInfo, common.LogFormatJSON, nil, "process"),
+	s := &Server{
+		config:     cfg,
+		logger:     common.NewServerLogger("process"),
+		sessionEnv: common.DefaultSessionEnv(),
 	}
+	common.RegisterConfigSection("process", s.configSection)
+	return s
+}
+
+// env returns the session environment of the client calling a tool.
+func (s *Server) env(ctx context.Context) *common.SessionEnv {
+	return common.SessionEnvFor(ctx, s.sessionEnv)
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		s.listProcessesTool(),
+		s.getProcessInfoTool(),
+		common.Audited("process", s.killProcessTool()),
+		s.findProcessByPortTool(),
+		s.getResourceUsageTool(),
+		s.waitForProcessTool(),
+		common.Audited("process", s.startProcessTool()),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
+		common.AuditLogTool(),
+	}
+	return common.Instrument("process", tools)
 }
 
 func (s *Server) RegisterTools(server *mcp.Server) {
-	server.RegisterTool(s.listProcessesTool())
-	server.RegisterTool(s.getProcessInfoTool())
-	server.RegisterTool(s.killProcessTool())
-	server.RegisterTool(s.findProcessByPortTool())
-	server.RegisterTool(s.getResourceUsageTool())
-	server.RegisterTool(s.waitForProcessTool())
-	server.RegisterTool(s.startProcessTool())
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
 }
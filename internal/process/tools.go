@@ -41,20 +41,41 @@ func (s *Server) listProcessesTool() *mcp.Tool {
 			},
 			[]string{},
 		),
-		Handler: s.handleListProcesses,
+		OutputSchema: mcp.BuildOutputSchema(
+			map[string]interface{}{
+				"processes": mcp.ObjectArrayProperty("Matching processes", map[string]interface{}{
+					"pid":         mcp.IntProperty("Process ID"),
+					"name":        mcp.StringProperty("Process name"),
+					"command":     mcp.StringProperty("Command line"),
+					"user":        mcp.StringProperty("Owner"),
+					"cpu_percent": mcp.NumberProperty("CPU usage in percent"),
+					"memory_mb":   mcp.NumberProperty("Resident memory in MB"),
+					"status":      mcp.StringProperty("Process status"),
+					"start_time":  mcp.StringProperty("Start time"),
+				}),
+				"total_count": mcp.IntProperty("Number of processes"),
+			},
+			[]string{"processes", "total_count"},
+		),
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleListProcesses),
 	}
 }
 
-func (s *Server) handleListProcesses(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	filterName, _ := mcp.GetStringParam(params, "filter_name", false)
-	filterUser, _ := mcp.GetStringParam(params, "filter_user", false)
+type listProcessesArgs struct {
+	FilterName string `json:"filter_name"`
+	FilterUser string `json:"filter_user"`
+}
+
+func (s *Server) handleListProcesses(ctx context.Context, args listProcessesArgs) (*mcp.ToolResult, error) {
+	filterName, filterUser := args.FilterName, args.FilterUser
 
 	processes, err := gopsProcess.Processes()
 	if err != nil {
 		return nil, err
 	}
 
-	var result []ProcessInfo
+	result := []ProcessInfo{}
 	maxResults := s.config.MaxListResults
 	if maxResults <= 0 {
 		maxResults = 1000
@@ -119,15 +140,17 @@ func (s *Server) getProcessInfoTool() *mcp.Tool {
 			},
 			[]string{"pid"},
 		),
-		Handler: s.handleGetProcessInfo,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleGetProcessInfo),
 	}
 }
 
-func (s *Server) handleGetProcessInfo(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	pid, err := mcp.GetIntParam(params, "pid", true, 0)
-	if err != nil {
-		return nil, err
-	}
+type pidArgs struct {
+	PID int `json:"pid" validate:"required"`
+}
+
+func (s *Server) handleGetProcessInfo(ctx context.Context, args pidArgs) (*mcp.ToolResult, error) {
+	pid := args.PID
 
 	if err := common.ValidatePID(pid); err != nil {
 		return nil, err
@@ -191,17 +214,18 @@ func (s *Server) killProcessTool() *mcp.Tool {
 			},
 			[]string{"pid"},
 		),
-		Handler: s.handleKillProcess,
+		Annotations: mcp.Destructive().Idempotent(),
+		Handler:     mcp.TypedHandler(s.handleKillProcess),
 	}
 }
 
-func (s *Server) handleKillProcess(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	pid, err := mcp.GetIntParam(params, "pid", true, 0)
-	if err != nil {
-		return nil, err
-	}
+type killProcessArgs struct {
+	PID    int    `json:"pid" validate:"required"`
+	Signal string `json:"signal"`
+}
 
-	signalName, _ := mcp.GetStringParam(params, "signal", false)
+func (s *Server) handleKillProcess(ctx context.Context, args killProcessArgs) (*mcp.ToolResult, error) {
+	pid, signalName := args.PID, args.Signal
 	if signalName == "" {
 		signalName = "SIGTERM"
 	}
@@ -258,6 +282,10 @@ func (s *Server) handleKillProcess(ctx context.Context, params map[string]interf
 		sig = syscall.SIGTERM
 	}
 
+	if err := common.Confirm(ctx, fmt.Sprintf("Send %s to process %d (%s, owned by %s)?", signalName, pid, name, username)); err != nil {
+		return nil, err
+	}
+
 	process, err := os.FindProcess(pid)
 	if err != nil {
 		return nil, err
@@ -280,15 +308,17 @@ func (s *Server) findProcessByPortTool() *mcp.Tool {
 			},
 			[]string{"port"},
 		),
-		Handler: s.handleFindProcessByPort,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleFindProcessByPort),
 	}
 }
 
-func (s *Server) handleFindProcessByPort(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	port, err := mcp.GetIntParam(params, "port", true, 0)
-	if err != nil {
-		return nil, err
-	}
+type findProcessByPortArgs struct {
+	Port int `json:"port" validate:"required"`
+}
+
+func (s *Server) handleFindProcessByPort(ctx context.Context, args findProcessByPortArgs) (*mcp.ToolResult, error) {
+	port := args.Port
 
 	if err := common.ValidatePort(port); err != nil {
 		return nil, err
@@ -337,7 +367,8 @@ func (s *Server) getResourceUsageTool() *mcp.Tool {
 			map[string]interface{}{},
 			[]string{},
 		),
-		Handler: s.handleGetResourceUsage,
+		Annotations: mcp.ReadOnly(),
+		Handler:     s.handleGetResourceUsage,
 	}
 }
 
@@ -390,17 +421,18 @@ func (s *Server) waitForProcessTool() *mcp.Tool {
 			},
 			[]string{"pid"},
 		),
-		Handler: s.handleWaitForProcess,
+		Annotations: mcp.ReadOnly(),
+		Handler:     mcp.TypedHandler(s.handleWaitForProcess),
 	}
 }
 
-func (s *Server) handleWaitForProcess(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	pid, err := mcp.GetIntParam(params, "pid", true, 0)
-	if err != nil {
-		return nil, err
-	}
+type waitForProcessArgs struct {
+	PID            int `json:"pid" validate:"required"`
+	TimeoutSeconds int `json:"timeout_seconds" default:"60"`
+}
 
-	timeout, _ := mcp.GetIntParam(params, "timeout_seconds", false, 60)
+func (s *Server) handleWaitForProcess(ctx context.Context, args waitForProcessArgs) (*mcp.ToolResult, error) {
+	pid, timeout := args.PID, args.TimeoutSeconds
 
 	if err := common.ValidatePID(pid); err != nil {
 		return nil, err
@@ -450,22 +482,24 @@ func (s *Server) startProcessTool() *mcp.Tool {
 			},
 			[]string{"command"},
 		),
-		Handler: s.handleStartProcess,
+		Annotations: mcp.Additive(),
+		Handler:     mcp.TypedHandler(s.handleStartProcess),
 	}
 }
 
-func (s *Server) handleStartProcess(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	command, err := mcp.GetStringParam(params, "command", true)
-	if err != nil {
-		return nil, err
-	}
-
-	args, _ := mcp.GetStringArrayParam(params, "args", false)
-	cwd, _ := mcp.GetStringParam(params, "cwd", false)
+type startProcessArgs struct {
+	Command string   `json:"command" validate:"required"`
+	Args    []string `json:"args"`
+	Cwd     string   `json:"cwd"`
+}
 
-	cmd := exec.Command(command, args...)
-	if cwd != "" {
-		cmd.Dir = cwd
+func (s *Server) handleStartProcess(ctx context.Context, args startProcessArgs) (*mcp.ToolResult, error) {
+	cmd := exec.Command(args.Command, args.Args...)
+	if args.Cwd != "" {
+		cmd.Dir = args.Cwd
+	}
+	if env := s.env(ctx); env.Len() > 0 {
+		cmd.Env = env.Environ(os.Environ())
 	}
 
 	cmd.Stdout = nil
@@ -482,7 +516,7 @@ func (s *Server) handleStartProcess(ctx context.Context, params map[string]inter
 
 	return mcp.JSONResult(map[string]interface{}{
 		"pid":        cmd.Process.Pid,
-		"command":    command,
+		"command":    args.Command,
 		"started":    true,
 		"start_time": time.Now().Format(time.RFC3339),
 	})
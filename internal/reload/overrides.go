@@ -0,0 +1,180 @@
+package reload
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/local-mcps/dev-mcps/config"
+)
+
+// Override is a runtime change to one setting, applied on top of the config
+// file on every reload until it expires or is cleared.
+type Override struct {
+	Setting string      `yaml:"setting" json:"setting"`
+	Op      string      `yaml:"op" json:"op"`
+	Value   interface{} `yaml:"value" json:"value"`
+	Expires time.Time   `yaml:"expires,omitempty" json:"expires,omitempty"`
+	Persist bool        `yaml:"-" json:"persist"`
+}
+
+func (o Override) expired(now time.Time) bool {
+	return !o.Expires.IsZero() && !now.Before(o.Expires)
+}
+
+// AddOverride checks o against the live config and queues a reload that
+// applies it once the calling tool has returned. It returns the setting's
+// value before and after the change.
+func (r *Reloader) AddOverride(o Override) (interface{}, interface{}, error) {
+	trial := *r.cfg
+	before, err := trial.Setting(o.Setting)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := trial.ApplySetting(o.Setting, o.Op, o.Value); err != nil {
+		return nil, nil, err
+	}
+	trial.ExpandPaths()
+	if err := trial.Validate(); err != nil {
+		return nil, nil, err
+	}
+	after, _ := trial.Setting(o.Setting)
+
+	r.overridesMu.Lock()
+	r.overrides = append(r.overrides, o)
+	err = r.saveOverridesLocked()
+	r.overridesMu.Unlock()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r.server.AfterCall(func() { r.Reload() })
+	return before, after, nil
+}
+
+// ClearOverrides drops the overrides for setting, or all of them when setting
+// is empty, and reports how many were removed.
+func (r *Reloader) ClearOverrides(setting string) (int, error) {
+	r.overridesMu.Lock()
+	kept := r.overrides[:0:0]
+	for _, o := range r.overrides {
+		if setting != "" && o.Setting != setting {
+			kept = append(kept, o)
+		}
+	}
+	removed := len(r.overrides) - len(kept)
+	r.overrides = kept
+	err := r.saveOverridesLocked()
+	r.overridesMu.Unlock()
+
+	if removed > 0 {
+		r.server.AfterCall(func() { r.Reload() })
+	}
+	return removed, err
+}
+
+func (r *Reloader) Overrides() []Override {
+	r.overridesMu.Lock()
+	defer r.overridesMu.Unlock()
+	return append([]Override{}, r.overrides...)
+}
+
+func (r *Reloader) applyOverrides(cfg *config.Config) {
+	now := time.Now()
+	for _, o := range r.Overrides() {
+		if o.expired(now) {
+			continue
+		}
+		if err := cfg.ApplySetting(o.Setting, o.Op, o.Value); err != nil {
+			r.logger.Warnf("skipping override of %s: %v", o.Setting, err)
+		}
+	}
+	cfg.ExpandPaths()
+}
+
+func (r *Reloader) expireOverrides() bool {
+	r.overridesMu.Lock()
+	defer r.overridesMu.Unlock()
+
+	now := time.Now()
+	kept := r.overrides[:0:0]
+	for _, o := range r.overrides {
+		if o.expired(now) {
+			r.logger.WithFields(map[string]interface{}{
+				"setting": o.Setting,
+				"op":      o.Op,
+			}).Info("config override expired")
+			continue
+		}
+		kept = append(kept, o)
+	}
+	if len(kept) == len(r.overrides) {
+		return false
+	}
+	r.overrides = kept
+	if err := r.saveOverridesLocked(); err != nil {
+		r.logger.Warnf("failed to save config overrides: %v", err)
+	}
+	return true
+}
+
+func (r *Reloader) loadOverrides() {
+	if r.overridesFile == "" {
+		return
+	}
+	data, err := os.ReadFile(r.overridesFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			r.logger.Warnf("failed to read config overrides: %v", err)
+		}
+		return
+	}
+
+	var overrides []Override
+	if err := yaml.Unmarshal(data, &overrides); err != nil {
+		r.logger.Warnf("ignoring corrupt overrides file %s: %v", r.overridesFile, err)
+		return
+	}
+	for i := range overrides {
+		overrides[i].Persist = true
+	}
+
+	r.overridesMu.Lock()
+	r.overrides = append(r.overrides, overrides...)
+	r.overridesMu.Unlock()
+}
+
+func (r *Reloader) saveOverridesLocked() error {
+	if r.overridesFile == "" {
+		return nil
+	}
+
+	var persisted []Override
+	for _, o := range r.overrides {
+		if o.Persist {
+			persisted = append(persisted, o)
+		}
+	}
+
+	if len(persisted) == 0 {
+		if err := os.Remove(r.overridesFile); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	data, err := yaml.Marshal(persisted)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.overridesFile), 0700); err != nil {
+		return err
+	}
+	if err := os.WriteFile(r.overridesFile, data, 0600); err != nil {
+		return fmt.Errorf("failed to save config overrides: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,311 @@
+package reload
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+const pollInterval = 2 * time.Second
+
+type Component interface {
+	Tools() []*mcp.Tool
+}
+
+// Reloadable components rebuild state derived from their config section,
+// such as validators, after a reload.
+type Reloadable interface {
+	Reload()
+}
+
+// ResourceProviders also serve MCP resources, registered and unregistered
+// with their tools.
+type ResourceProvider interface {
+	Resources() *mcp.ResourceSource
+}
+
+// Shutdowners release what they started, such as background processes,
+// when the server shuts down.
+type Shutdowner interface {
+	Shutdown(ctx context.Context)
+}
+
+type component struct {
+	name    string
+	impl    Component
+	enabled func(*config.Config) bool
+	tools   int
+}
+
+type Reloader struct {
+	server     *mcp.Server
+	cfg        *config.Config
+	path       string
+	logger     *common.Logger
+	components []*component
+	registered map[string]bool
+	resources  map[string]bool
+	// owners maps each registered tool to the component providing it.
+	owners     map[string]string
+	namespaces map[string]string
+
+	// mu serializes reloads and guards the watch state read by Run.
+	mu      sync.Mutex
+	watch   bool
+	sources []string
+
+	overridesMu   sync.Mutex
+	overrides     []Override
+	overridesFile string
+}
+
+func New(server *mcp.Server, cfg *config.Config, path string) *Reloader {
+	r := &Reloader{
+		server:        server,
+		cfg:           cfg,
+		path:          config.ResolvePath(path),
+		logger:        common.NewServerLogger("reload"),
+		registered:    make(map[string]bool),
+		resources:     make(map[string]bool),
+		watch:         cfg.Global.WatchConfig,
+		sources:       cfg.Sources,
+		overridesFile: cfg.Admin.OverridesFile,
+	}
+	if cfg.Admin.Enabled {
+		r.loadOverrides()
+		r.applyOverrides(cfg)
+	}
+	return r
+}
+
+// Add registers impl's tools when enabled reports true for the current
+// config, and tracks it so later reloads can add or remove them.
+func (r *Reloader) Add(name string, impl Component, enabled func(*config.Config) bool) {
+	r.components = append(r.components, &component{name: name, impl: impl, enabled: enabled})
+	// Disabled components may still hold what they started while enabled.
+	if s, ok := impl.(Shutdowner); ok {
+		r.server.OnShutdown(s.Shutdown)
+	}
+	r.sync()
+	r.syncResources()
+}
+
+// sync registers the tools of every enabled component and unregisters the
+// rest. Tools shared between components (get_server_config) stay registered
+// while any enabled component provides them. It reports whether the set of
+// tool names changed.
+func (r *Reloader) sync() bool {
+	provided := make([][]*mcp.Tool, len(r.components))
+	providers := make(map[string]int)
+	for i, c := range r.components {
+		if c.enabled(r.cfg) {
+			provided[i] = c.impl.Tools()
+		}
+		for _, tool := range provided[i] {
+			providers[tool.Name]++
+		}
+		if len(provided[i]) != c.tools {
+			r.logger.WithFields(map[string]interface{}{
+				"component": c.name,
+				"tools":     len(provided[i]),
+			}).Info("registered tools")
+			c.tools = len(provided[i])
+		}
+	}
+
+	wanted := make(map[string]*mcp.Tool)
+	owners := make(map[string]string)
+	for i, c := range r.components {
+		for _, tool := range provided[i] {
+			name := ToolName(r.cfg, r.namespaces[c.name], tool.Name, providers[tool.Name] > 1)
+			if name != tool.Name {
+				namespaced := *tool
+				namespaced.Name = name
+				tool = &namespaced
+			}
+			wanted[name] = tool
+			if _, ok := owners[name]; !ok {
+				owners[name] = c.name
+			}
+		}
+	}
+
+	r.owners = owners
+
+	changed := false
+	for name := range r.registered {
+		if _, ok := wanted[name]; !ok {
+			r.server.UnregisterTool(name)
+			delete(r.registered, name)
+			changed = true
+		}
+	}
+	for name, tool := range wanted {
+		r.server.RegisterTool(tool)
+		if !r.registered[name] {
+			r.registered[name] = true
+			changed = true
+		}
+	}
+	return changed
+}
+
+// SetNamespaces sets the prefix of each component's tools, by component
+// name, for global.namespace_tools. Call it before Add.
+func (r *Reloader) SetNamespaces(namespaces map[string]string) {
+	r.namespaces = namespaces
+}
+
+// ToolName is the name a component's tool is registered under: with
+// global.namespace_tools, namespaced like mcp.RegisterToolGroup does, except
+// for tools shared by several components.
+func ToolName(cfg *config.Config, namespace, name string, shared bool) string {
+	if !cfg.Global.NamespaceTools || shared || namespace == "" {
+		return name
+	}
+	return mcp.NamespacedName(namespace, name)
+}
+
+// Owner returns the name of the component providing tool. It only changes
+// inside mcp.Server.Update, so tool handlers may call it.
+func (r *Reloader) Owner(tool string) string {
+	return r.owners[tool]
+}
+
+// syncResources registers the resource sources of the enabled components
+// and unregisters the rest, reporting whether any were added or removed.
+func (r *Reloader) syncResources() bool {
+	changed := false
+	for _, c := range r.components {
+		provider, ok := c.impl.(ResourceProvider)
+		if !ok {
+			continue
+		}
+		source := provider.Resources()
+		if c.enabled(r.cfg) {
+			r.server.RegisterResources(source)
+			if !r.resources[source.Name] {
+				r.resources[source.Name] = true
+				changed = true
+			}
+		} else if r.resources[source.Name] {
+			r.server.UnregisterResources(source.Name)
+			delete(r.resources, source.Name)
+			changed = true
+		}
+	}
+	return changed
+}
+
+// Reload re-reads the config file and applies it in place. The previous
+// configuration stays active if the file cannot be loaded.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := config.LoadConfig(r.path)
+	if err != nil {
+		r.logger.Errorf("config reload failed, keeping previous config: %v", err)
+		return err
+	}
+	r.applyOverrides(next)
+
+	changed, resourcesChanged := false, false
+	r.server.Update(func() {
+		*r.cfg = *next
+		common.SetLogLevel(common.ParseLogLevel(next.Global.LogLevel))
+		for _, c := range r.components {
+			if reloadable, ok := c.impl.(Reloadable); ok {
+				reloadable.Reload()
+			}
+		}
+		changed = r.sync()
+		resourcesChanged = r.syncResources()
+	})
+
+	r.watch = next.Global.WatchConfig
+	r.sources = next.Sources
+	r.overridesMu.Lock()
+	r.overridesFile = next.Admin.OverridesFile
+	r.overridesMu.Unlock()
+
+	r.logger.WithField("path", r.path).Info("config reloaded")
+	if changed {
+		r.server.NotifyToolsListChanged()
+	}
+	if resourcesChanged {
+		r.server.NotifyResourcesListChanged()
+	}
+	return nil
+}
+
+// Run reloads on SIGHUP and, when global.watch_config is set, whenever the
+// config file or one of its includes changes.
+func (r *Reloader) Run(ctx context.Context) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	last := r.stat()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			r.Reload()
+			last = r.stat()
+		case <-ticker.C:
+			if r.expireOverrides() {
+				r.Reload()
+			}
+			if !r.watching() {
+				continue
+			}
+			if current := r.stat(); current != last {
+				last = current
+				r.Reload()
+			}
+		}
+	}
+}
+
+func (r *Reloader) watching() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.watch
+}
+
+// stat fingerprints the config file and everything it includes so a change
+// to any layer triggers a reload.
+func (r *Reloader) stat() string {
+	if r.path == "" {
+		return ""
+	}
+
+	r.mu.Lock()
+	paths := append([]string{r.path}, r.sources...)
+	r.mu.Unlock()
+
+	var b strings.Builder
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			fmt.Fprintf(&b, "%s:missing;", path)
+			continue
+		}
+		fmt.Fprintf(&b, "%s:%d:%d;", path, info.ModTime().UnixNano(), info.Size())
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package ssh
+
+import (
+	"fmt"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+type Server struct {
+	config    *config.SSHConfig
+	validator *common.CommandValidator
+	paths     *common.PathValidator
+	logger    *common.Logger
+}
+
+func NewServer(cfg *config.SSHConfig) *Server {
+	s := &Server{
+		config: cfg,
+		logger: common.NewServerLogger("ssh"),
+	}
+	s.Reload()
+	common.RegisterConfigSection("ssh", s.configSection)
+	return s
+}
+
+// Reload rebuilds the command policies and local path validator. Like the
+// command server, an invalid policy denies every remote command.
+func (s *Server) Reload() {
+	validator, err := common.NewPolicyValidator("ssh", s.config.Policies, s.config.DefaultAction)
+	if err != nil {
+		s.logger.Errorf("invalid ssh policy, denying all commands: %v", err)
+		validator = &common.CommandValidator{DefaultDeny: true}
+	}
+	s.validator = validator
+	s.paths = common.NewPathValidator(s.config.AllowedLocalPaths, nil, true)
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) host(name string) (*config.SSHHost, error) {
+	names := make([]string, 0, len(s.config.Hosts))
+	for i := range s.config.Hosts {
+		h := &s.config.Hosts[i]
+		if h.Name == name {
+			return h, nil
+		}
+		names = append(names, h.Name)
+	}
+	return nil, fmt.Errorf("%w: host %s (configured: %v)", common.ErrNotFound, name, names)
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		s.listHostsTool(),
+		common.Audited("ssh", s.execTool()),
+		common.Audited("ssh", s.uploadTool()),
+		common.Audited("ssh", s.downloadTool()),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
+		common.AuditLogTool(),
+	}
+	return common.Instrument("ssh", tools)
+}
+
+func (s *Server) RegisterTools(server *mcp.Server) {
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
+}
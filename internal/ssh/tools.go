@@ -0,0 +1,301 @@
+package ssh
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+	"github.com/local-mcps/dev-mcps/pkg/mcp"
+)
+
+// exitConnectionFailed is what ssh and scp exit with when they could not
+// connect or authenticate.
+const exitConnectionFailed = 255
+
+// options returns the client options shared by ssh and scp; portFlag is -p
+// for ssh and -P for scp.
+func (s *Server) options(h *config.SSHHost, portFlag string) []string {
+	opts := []string{
+		"-o", "BatchMode=yes",
+		"-o", "ConnectTimeout=" + strconv.Itoa(s.config.ConnectTimeoutSeconds),
+		"-o", "StrictHostKeyChecking=" + s.config.StrictHostKeyChecking,
+	}
+	if h.Port != 0 {
+		opts = append(opts, portFlag, strconv.Itoa(h.Port))
+	}
+	if h.KeyPath != "" {
+		opts = append(opts, "-i", h.KeyPath, "-o", "IdentitiesOnly=yes")
+	}
+	return opts
+}
+
+func destination(h *config.SSHHost) string {
+	if h.User != "" {
+		return h.User + "@" + h.Hostname
+	}
+	return h.Hostname
+}
+
+// shellQuote quotes s for the remote POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+type runResult struct {
+	ExitCode   int    `json:"exit_code"`
+	Stdout     string `json:"stdout"`
+	Stderr     string `json:"stderr"`
+	DurationMs int64  `json:"duration_ms"`
+}
+
+func (s *Server) run(ctx context.Context, timeoutSeconds int, name string, args ...string) (*runResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(timeoutSeconds)*time.Second)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, name, args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	start := time.Now()
+	err := cmd.Run()
+	result := &runResult{
+		Stdout:     stdout.String(),
+		Stderr:     stderr.String(),
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if ctx.Err() == context.DeadlineExceeded {
+		return nil, fmt.Errorf("%w: %s did not finish within %ds", common.ErrTimeout, name, timeoutSeconds)
+	}
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("%w: running %s: %v", common.ErrOperationFailed, name, err)
+		}
+		result.ExitCode = exitErr.ExitCode()
+	}
+	if result.ExitCode == exitConnectionFailed {
+		return nil, fmt.Errorf("%w: %s", common.ErrOperationFailed, strings.TrimSpace(result.Stderr))
+	}
+
+	if len(result.Stdout) > s.config.MaxOutputSizeBytes {
+		result.Stdout = result.Stdout[:s.config.MaxOutputSizeBytes] + "\n... (truncated)"
+	}
+	if len(result.Stderr) > s.config.MaxOutputSizeBytes {
+		result.Stderr = result.Stderr[:s.config.MaxOutputSizeBytes] + "\n... (truncated)"
+	}
+	return result, nil
+}
+
+func (s *Server) listHostsTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "ssh_list_hosts",
+		Description: "List the SSH hosts defined in the configuration",
+		InputSchema: mcp.BuildInputSchema(map[string]interface{}{}, []string{}),
+		Annotations: mcp.ReadOnly(),
+		Handler:     s.handleListHosts,
+	}
+}
+
+func (s *Server) handleListHosts(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
+	hosts := make([]map[string]interface{}, 0, len(s.config.Hosts))
+	for _, h := range s.config.Hosts {
+		hosts = append(hosts, map[string]interface{}{
+			"name":     h.Name,
+			"hostname": h.Hostname,
+			"user":     h.User,
+			"port":     h.Port,
+		})
+	}
+	return mcp.JSONResult(map[string]interface{}{
+		"hosts": hosts,
+		"count": len(hosts),
+	})
+}
+
+func (s *Server) execTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "ssh_exec",
+		Description: "Run a command on a configured SSH host",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"host":            mcp.StringProperty("Host name from ssh_list_hosts"),
+				"command":         mcp.StringProperty("Command to run"),
+				"args":            mcp.ArrayProperty("string", "Command arguments"),
+				"working_dir":     mcp.StringProperty("Remote working directory"),
+				"env":             mcp.MapProperty("Environment variables for the command"),
+				"timeout_seconds": mcp.IntProperty("Timeout in seconds"),
+			},
+			[]string{"host", "command"},
+		),
+		Annotations: mcp.Destructive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleExec),
+	}
+}
+
+type execArgs struct {
+	Host           string            `json:"host" validate:"required"`
+	Command        string            `json:"command" validate:"required"`
+	Args           []string          `json:"args"`
+	WorkingDir     string            `json:"working_dir"`
+	Env            map[string]string `json:"env"`
+	TimeoutSeconds int               `json:"timeout_seconds"`
+}
+
+func (s *Server) handleExec(ctx context.Context, args execArgs) (*mcp.ToolResult, error) {
+	command, workingDir, env, timeout := args.Command, args.WorkingDir, args.Env, args.TimeoutSeconds
+	if timeout <= 0 {
+		timeout = s.config.DefaultTimeoutSeconds
+	}
+
+	h, err := s.host(args.Host)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.validator.ValidateCommand(command, args.Args); err != nil {
+		return nil, common.WithDetails(err, "host", h.Name)
+	}
+
+	// Every word is quoted, so the remote shell runs exactly the command
+	// the policies saw.
+	var remote []string
+	if workingDir != "" {
+		remote = append(remote, "cd", shellQuote(workingDir), "&&")
+	}
+	keys := make([]string, 0, len(env))
+	for k := range env {
+		if err := common.ValidateEnvVarName(k); err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	if len(keys) > 0 {
+		remote = append(remote, "env")
+		for _, k := range keys {
+			remote = append(remote, k+"="+shellQuote(env[k]))
+		}
+	}
+	remote = append(remote, shellQuote(command))
+	for _, arg := range args.Args {
+		remote = append(remote, shellQuote(arg))
+	}
+
+	sshArgs := append(s.options(h, "-p"), "--", destination(h), strings.Join(remote, " "))
+	result, err := s.run(ctx, timeout, "ssh", sshArgs...)
+	if err != nil {
+		return nil, common.WithDetails(err, "host", h.Name)
+	}
+	return mcp.JSONResult(result)
+}
+
+func (s *Server) uploadTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "ssh_upload",
+		Description: "Copy a local file or directory to a configured SSH host",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"host":        mcp.StringProperty("Host name from ssh_list_hosts"),
+				"local_path":  mcp.StringProperty("Local file or directory"),
+				"remote_path": mcp.StringProperty("Destination path on the host"),
+				"recursive":   mcp.BoolProperty("Copy directories recursively"),
+			},
+			[]string{"host", "local_path", "remote_path"},
+		),
+		Annotations: mcp.Destructive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleUpload),
+	}
+}
+
+type transferArgs struct {
+	Host       string `json:"host" validate:"required"`
+	LocalPath  string `json:"local_path" validate:"required"`
+	RemotePath string `json:"remote_path" validate:"required"`
+	Recursive  bool   `json:"recursive"`
+}
+
+func (s *Server) handleUpload(ctx context.Context, args transferArgs) (*mcp.ToolResult, error) {
+	return s.transfer(ctx, args, true)
+}
+
+func (s *Server) downloadTool() *mcp.Tool {
+	return &mcp.Tool{
+		Name:        "ssh_download",
+		Description: "Copy a file or directory from a configured SSH host to the local machine",
+		InputSchema: mcp.BuildInputSchema(
+			map[string]interface{}{
+				"host":        mcp.StringProperty("Host name from ssh_list_hosts"),
+				"remote_path": mcp.StringProperty("Path on the host"),
+				"local_path":  mcp.StringProperty("Local destination"),
+				"recursive":   mcp.BoolProperty("Copy directories recursively"),
+			},
+			[]string{"host", "remote_path", "local_path"},
+		),
+		Annotations: mcp.Destructive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleDownload),
+	}
+}
+
+func (s *Server) handleDownload(ctx context.Context, args transferArgs) (*mcp.ToolResult, error) {
+	return s.transfer(ctx, args, false)
+}
+
+// unsafeRemoteChars are rejected in remote paths: older scp versions pass
+// the path through the remote shell.
+const unsafeRemoteChars = "`$;&|<>\\\"'*?(){}[]!\n"
+
+func (s *Server) transfer(ctx context.Context, args transferArgs, upload bool) (*mcp.ToolResult, error) {
+	remotePath := args.RemotePath
+
+	h, err := s.host(args.Host)
+	if err != nil {
+		return nil, err
+	}
+	local, err := s.paths.ResolvePath(args.LocalPath)
+	if err != nil {
+		return nil, err
+	}
+	if remotePath == "" || strings.HasPrefix(remotePath, "-") || strings.ContainsAny(remotePath, unsafeRemoteChars) {
+		return nil, fmt.Errorf("%w: unsupported characters in remote path %q", common.ErrInvalidInput, remotePath)
+	}
+	remote := destination(h) + ":" + remotePath
+
+	scpArgs := s.options(h, "-P")
+	if args.Recursive {
+		scpArgs = append(scpArgs, "-r")
+	}
+	scpArgs = append(scpArgs, "--")
+	direction := "upload"
+	if upload {
+		scpArgs = append(scpArgs, local, remote)
+	} else {
+		direction = "download"
+		scpArgs = append(scpArgs, remote, local)
+	}
+
+	result, err := s.run(ctx, s.config.DefaultTimeoutSeconds, "scp", scpArgs...)
+	if err != nil {
+		return nil, common.WithDetails(err, "host", h.Name)
+	}
+	if result.ExitCode != 0 {
+		return nil, common.WithDetails(
+			fmt.Errorf("%w: scp: %s", common.ErrOperationFailed, strings.TrimSpace(result.Stderr)),
+			"host", h.Name)
+	}
+
+	return mcp.JSONResult(map[string]interface{}{
+		"host":        h.Name,
+		"local_path":  local,
+		"remote_path": remotePath,
+		"direction":   direction,
+		"duration_ms": result.DurationMs,
+	})
+}
@@ -0,0 +1,103 @@
+package ssh
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/local-mcps/dev-mcps/config"
+	"github.com/local-mcps/dev-mcps/internal/common"
+)
+
+// fakeClients puts ssh and scp on PATH that print their arguments, one per
+// line, and exit with 0.
+func fakeClients(t *testing.T) {
+	t.Helper()
+	dir := t.TempDir()
+	script := "#!/bin/sh\nfor a in \"$@\"; do echo \"$a\"; done\n"
+	for _, name := range []string{"ssh", "scp"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(script), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("PATH", dir+string(os.PathListSeparator)+os.Getenv("PATH"))
+}
+
+func newTestServer(t *testing.T) *Server {
+	cfg := config.DefaultConfig().SSH
+	cfg.Hosts = []config.SSHHost{{Name: "devbox", Hostname: "10.0.0.5", User: "dev", Port: 2222}}
+	cfg.Policies = []config.CommandPolicy{{Name: "no-sudo", Action: "deny", Binary: "sudo"}}
+	cfg.AllowedLocalPaths = []string{t.TempDir()}
+	return NewServer(&cfg)
+}
+
+func TestExecQuotesRemoteCommand(t *testing.T) {
+	fakeClients(t)
+	s := newTestServer(t)
+
+	result, err := s.execTool().Handler(context.Background(), map[string]interface{}{
+		"host":        "devbox",
+		"command":     "echo",
+		"args":        []interface{}{"it's", "$(reboot)"},
+		"working_dir": "/srv/app",
+		"env":         map[string]interface{}{"MODE": "dev"},
+	})
+	if err != nil {
+		t.Fatalf("handleExec: %v", err)
+	}
+	var out runResult
+	json.Unmarshal([]byte(result.Content[0].Text), &out)
+	lines := strings.Split(strings.TrimSpace(out.Stdout), "\n")
+
+	if got := strings.Join(lines[len(lines)-3:len(lines)-1], " "); got != "-- dev@10.0.0.5" {
+		t.Errorf("destination: %q", got)
+	}
+	want := `cd '/srv/app' && env MODE='dev' 'echo' 'it'\''s' '$(reboot)'`
+	if got := lines[len(lines)-1]; got != want {
+		t.Errorf("remote command:\n got %s\nwant %s", got, want)
+	}
+	if !strings.Contains(out.Stdout, "-p\n2222\n") {
+		t.Errorf("port not passed: %s", out.Stdout)
+	}
+}
+
+func TestExecAppliesPolicies(t *testing.T) {
+	fakeClients(t)
+	s := newTestServer(t)
+
+	_, err := s.execTool().Handler(context.Background(), map[string]interface{}{
+		"host":    "devbox",
+		"command": "sudo",
+		"args":    []interface{}{"reboot"},
+	})
+	var denied *common.CommandDeniedError
+	if !errors.As(err, &denied) || denied.Rule != "no-sudo" {
+		t.Fatalf("got %v, want denial by no-sudo", err)
+	}
+}
+
+func TestTransferPaths(t *testing.T) {
+	fakeClients(t)
+	s := newTestServer(t)
+	local := filepath.Join(s.config.AllowedLocalPaths[0], "build.tar")
+
+	if _, err := s.uploadTool().Handler(context.Background(), map[string]interface{}{
+		"host": "devbox", "local_path": local, "remote_path": "/tmp/build.tar",
+	}); err != nil {
+		t.Fatalf("handleUpload: %v", err)
+	}
+	if _, err := s.uploadTool().Handler(context.Background(), map[string]interface{}{
+		"host": "devbox", "local_path": "/etc/passwd", "remote_path": "/tmp/x",
+	}); !common.IsPathNotAllowed(err) {
+		t.Errorf("local path outside allowed_local_paths: got %v", err)
+	}
+	if _, err := s.downloadTool().Handler(context.Background(), map[string]interface{}{
+		"host": "devbox", "local_path": local, "remote_path": "/tmp/$(id)",
+	}); !errors.Is(err, common.ErrInvalidInput) {
+		t.Errorf("remote path with shell syntax: got %v", err)
+	}
+}
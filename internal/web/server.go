@@ -12,17 +12,34 @@ type Server struct {
 }
 
 func NewServer(cfg *config.WebConfig) *Server {
-	return &Server{
+	s := &Server{
 		config: cfg,
-		logger: common.NewLogger(common.LogLevelInfo, common.LogFormatJSON, nil, "web"),
+		logger: common.NewServerLogger("web"),
 	}
+	common.RegisterConfigSection("web", s.configSection)
+	return s
+}
+
+func (s *Server) configSection() interface{} {
+	return s.config
+}
+
+func (s *Server) Tools() []*mcp.Tool {
+	tools := []*mcp.Tool{
+		s.fetchURLTool(),
+		s.fetchHTMLTool(),
+		s.fetchTextTool(),
+		s.fetchMarkdownTool(),
+		s.fetchJSONTool(),
+		s.extractLinksTool(),
+		common.ServerConfigTool(),
+		common.ServerStatsTool(),
+	}
+	return common.Instrument("web", tools)
 }
 
 func (s *Server) RegisterTools(server *mcp.Server) {
-	server.RegisterTool(s.fetchURLTool())
-	server.RegisterTool(s.fetchHTMLTool())
-	server.RegisterTool(s.fetchTextTool())
-	server.RegisterTool(s.fetchMarkdownTool())
-	server.RegisterTool(s.fetchJSONTool())
-	server.RegisterTool(s.extractLinksTool())
+	for _, tool := range s.Tools() {
+		server.RegisterTool(tool)
+	}
 }
@@ -2,6 +2,7 @@ package web
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -11,6 +12,7 @@ import (
 	"regexp"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"golang.org/x/net/html"
 
@@ -101,7 +103,7 @@ func isInternalIP(ip net.IP) bool {
 func (s *Server) fetchURLTool() *mcp.Tool {
 	return &mcp.Tool{
 		Name:        "fetch_url",
-		Description: "Fetch the raw content of a URL",
+		Description: "Fetch the raw content of a URL. Binary bodies are returned as an embedded resource with base64 content",
 		InputSchema: mcp.BuildInputSchema(
 			map[string]interface{}{
 				"url":             mcp.StringProperty("URL to fetch"),
@@ -109,28 +111,30 @@ func (s *Server) fetchURLTool() *mcp.Tool {
 				"headers":         mcp.MapProperty("Custom headers"),
 				"body":            mcp.StringProperty("Request body"),
 				"timeout_seconds": mcp.IntProperty("Request timeout"),
+				"as_resource":     mcp.BoolProperty("Return the body as an embedded resource next to the response metadata instead of inside the JSON text"),
 			},
 			[]string{"url"},
 		),
-		Handler: s.handleFetchURL,
+		Annotations: mcp.Destructive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleFetchURL),
 	}
 }
 
-func (s *Server) handleFetchURL(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	rawURL, err := mcp.GetStringParam(params, "url", true)
-	if err != nil {
-		return nil, err
-	}
+type fetchURLArgs struct {
+	URL            string            `json:"url" validate:"required"`
+	Method         string            `json:"method"`
+	Headers        map[string]string `json:"headers"`
+	Body           string            `json:"body"`
+	TimeoutSeconds int               `json:"timeout_seconds"`
+	AsResource     bool              `json:"as_resource"`
+}
 
-	method, _ := mcp.GetStringParam(params, "method", false)
+func (s *Server) handleFetchURL(ctx context.Context, args fetchURLArgs) (*mcp.ToolResult, error) {
+	rawURL, method, body := args.URL, args.Method, args.Body
 	if method == "" {
 		method = "GET"
 	}
 
-	headers, _ := mcp.GetMapParam(params, "headers", false)
-	body, _ := mcp.GetStringParam(params, "body", false)
-	timeout, _ := mcp.GetIntParam(params, "timeout_seconds", false, s.config.DefaultTimeoutSeconds)
-
 	if err := s.validateURL(rawURL); err != nil {
 		return nil, err
 	}
@@ -146,11 +150,11 @@ func (s *Server) handleFetchURL(ctx context.Context, params map[string]interface
 	}
 
 	req.Header.Set("User-Agent", s.config.UserAgent)
-	for k, v := range headers {
+	for k, v := range args.Headers {
 		req.Header.Set(k, v)
 	}
 
-	client := s.createClient(timeout)
+	client := s.createClient(args.TimeoutSeconds)
 	startTime := time.Now()
 
 	resp, err := client.Do(req)
@@ -172,14 +176,33 @@ func (s *Server) handleFetchURL(ctx context.Context, params map[string]interface
 		respHeaders[k] = resp.Header.Get(k)
 	}
 
-	return mcp.JSONResult(map[string]interface{}{
+	result := map[string]interface{}{
 		"url":            rawURL,
 		"status_code":    resp.StatusCode,
 		"headers":        respHeaders,
-		"content":        string(content),
 		"content_length": len(content),
 		"fetch_time_ms":  fetchTime.Milliseconds(),
-	})
+	}
+	if !args.AsResource && utf8.Valid(content) {
+		result["content"] = string(content)
+		return mcp.JSONResult(result)
+	}
+
+	toolResult, err := mcp.JSONResult(result)
+	if err != nil {
+		return nil, err
+	}
+	embedded := &mcp.ResourceContents{URI: resp.Request.URL.String(), MimeType: resp.Header.Get("Content-Type")}
+	if embedded.MimeType == "" {
+		embedded.MimeType = http.DetectContentType(content)
+	}
+	if utf8.Valid(content) {
+		embedded.Text = string(content)
+	} else {
+		embedded.Blob = base64.StdEncoding.EncodeToString(content)
+	}
+	toolResult.Content = append(toolResult.Content, mcp.ResourceBlock(embedded))
+	return toolResult, nil
 }
 
 func (s *Server) fetchHTMLTool() *mcp.Tool {
@@ -193,17 +216,18 @@ func (s *Server) fetchHTMLTool() *mcp.Tool {
 			},
 			[]string{"url"},
 		),
-		Handler: s.handleFetchHTML,
+		Annotations: mcp.ReadOnly().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleFetchHTML),
 	}
 }
 
-func (s *Server) handleFetchHTML(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	rawURL, err := mcp.GetStringParam(params, "url", true)
-	if err != nil {
-		return nil, err
-	}
+type fetchPageArgs struct {
+	URL            string `json:"url" validate:"required"`
+	TimeoutSeconds int    `json:"timeout_seconds"`
+}
 
-	timeout, _ := mcp.GetIntParam(params, "timeout_seconds", false, s.config.DefaultTimeoutSeconds)
+func (s *Server) handleFetchHTML(ctx context.Context, args fetchPageArgs) (*mcp.ToolResult, error) {
+	rawURL, timeout := args.URL, args.TimeoutSeconds
 
 	if err := s.validateURL(rawURL); err != nil {
 		return nil, err
@@ -253,17 +277,13 @@ func (s *Server) fetchTextTool() *mcp.Tool {
 			},
 			[]string{"url"},
 		),
-		Handler: s.handleFetchText,
+		Annotations: mcp.ReadOnly().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleFetchText),
 	}
 }
 
-func (s *Server) handleFetchText(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	rawURL, err := mcp.GetStringParam(params, "url", true)
-	if err != nil {
-		return nil, err
-	}
-
-	timeout, _ := mcp.GetIntParam(params, "timeout_seconds", false, s.config.DefaultTimeoutSeconds)
+func (s *Server) handleFetchText(ctx context.Context, args fetchPageArgs) (*mcp.ToolResult, error) {
+	rawURL, timeout := args.URL, args.TimeoutSeconds
 
 	if err := s.validateURL(rawURL); err != nil {
 		return nil, err
@@ -315,17 +335,13 @@ func (s *Server) fetchMarkdownTool() *mcp.Tool {
 			},
 			[]string{"url"},
 		),
-		Handler: s.handleFetchMarkdown,
+		Annotations: mcp.ReadOnly().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleFetchMarkdown),
 	}
 }
 
-func (s *Server) handleFetchMarkdown(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	rawURL, err := mcp.GetStringParam(params, "url", true)
-	if err != nil {
-		return nil, err
-	}
-
-	timeout, _ := mcp.GetIntParam(params, "timeout_seconds", false, s.config.DefaultTimeoutSeconds)
+func (s *Server) handleFetchMarkdown(ctx context.Context, args fetchPageArgs) (*mcp.ToolResult, error) {
+	rawURL, timeout := args.URL, args.TimeoutSeconds
 
 	if err := s.validateURL(rawURL); err != nil {
 		return nil, err
@@ -377,24 +393,24 @@ func (s *Server) fetchJSONTool() *mcp.Tool {
 			},
 			[]string{"url"},
 		),
-		Handler: s.handleFetchJSON,
+		Annotations: mcp.Destructive().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleFetchJSON),
 	}
 }
 
-func (s *Server) handleFetchJSON(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	rawURL, err := mcp.GetStringParam(params, "url", true)
-	if err != nil {
-		return nil, err
-	}
+type fetchJSONArgs struct {
+	URL     string            `json:"url" validate:"required"`
+	Method  string            `json:"method"`
+	Headers map[string]string `json:"headers"`
+	Body    string            `json:"body"`
+}
 
-	method, _ := mcp.GetStringParam(params, "method", false)
+func (s *Server) handleFetchJSON(ctx context.Context, args fetchJSONArgs) (*mcp.ToolResult, error) {
+	rawURL, method, body := args.URL, args.Method, args.Body
 	if method == "" {
 		method = "GET"
 	}
 
-	headers, _ := mcp.GetMapParam(params, "headers", false)
-	body, _ := mcp.GetStringParam(params, "body", false)
-
 	if err := s.validateURL(rawURL); err != nil {
 		return nil, err
 	}
@@ -411,7 +427,7 @@ func (s *Server) handleFetchJSON(ctx context.Context, params map[string]interfac
 
 	req.Header.Set("User-Agent", s.config.UserAgent)
 	req.Header.Set("Accept", "application/json")
-	for k, v := range headers {
+	for k, v := range args.Headers {
 		req.Header.Set(k, v)
 	}
 
@@ -456,17 +472,18 @@ func (s *Server) extractLinksTool() *mcp.Tool {
 			},
 			[]string{"url"},
 		),
-		Handler: s.handleExtractLinks,
+		Annotations: mcp.ReadOnly().OpenWorld(),
+		Handler:     mcp.TypedHandler(s.handleExtractLinks),
 	}
 }
 
-func (s *Server) handleExtractLinks(ctx context.Context, params map[string]interface{}) (*mcp.ToolResult, error) {
-	rawURL, err := mcp.GetStringParam(params, "url", true)
-	if err != nil {
-		return nil, err
-	}
+type extractLinksArgs struct {
+	URL           string `json:"url" validate:"required"`
+	FilterPattern string `json:"filter_pattern"`
+}
 
-	filterPattern, _ := mcp.GetStringParam(params, "filter_pattern", false)
+func (s *Server) handleExtractLinks(ctx context.Context, args extractLinksArgs) (*mcp.ToolResult, error) {
+	rawURL, filterPattern := args.URL, args.FilterPattern
 
 	if err := s.validateURL(rawURL); err != nil {
 		return nil, err
@@ -0,0 +1,45 @@
+package mcp
+
+// ToolAnnotations are hints about a tool's behavior that clients may use to
+// decide when to ask for confirmation. They are not guarantees.
+type ToolAnnotations struct {
+	Title           string `json:"title,omitempty"`
+	ReadOnlyHint    *bool  `json:"readOnlyHint,omitempty"`
+	DestructiveHint *bool  `json:"destructiveHint,omitempty"`
+	IdempotentHint  *bool  `json:"idempotentHint,omitempty"`
+	OpenWorldHint   *bool  `json:"openWorldHint,omitempty"`
+}
+
+func hint(v bool) *bool { return &v }
+
+// ReadOnly annotates a tool that does not modify its environment.
+func ReadOnly() *ToolAnnotations {
+	return &ToolAnnotations{ReadOnlyHint: hint(true), OpenWorldHint: hint(false)}
+}
+
+// Additive annotates a tool that creates or changes things without
+// deleting or overwriting existing data.
+func Additive() *ToolAnnotations {
+	return &ToolAnnotations{ReadOnlyHint: hint(false), DestructiveHint: hint(false), OpenWorldHint: hint(false)}
+}
+
+// Destructive annotates a tool that may delete or overwrite data.
+func Destructive() *ToolAnnotations {
+	return &ToolAnnotations{ReadOnlyHint: hint(false), DestructiveHint: hint(true), OpenWorldHint: hint(false)}
+}
+
+// Idempotent marks that repeating a call with the same arguments has no
+// further effect.
+func (a *ToolAnnotations) Idempotent() *ToolAnnotations {
+	c := *a
+	c.IdempotentHint = hint(true)
+	return &c
+}
+
+// OpenWorld marks a tool that talks to hosts or services beyond this
+// machine, such as web servers or remote repositories.
+func (a *ToolAnnotations) OpenWorld() *ToolAnnotations {
+	c := *a
+	c.OpenWorldHint = hint(true)
+	return &c
+}
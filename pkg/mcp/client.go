@@ -0,0 +1,130 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// requestIDPrefix marks the IDs of requests the server sends the client,
+// so their responses can be told apart from pings'.
+const requestIDPrefix = "srv-"
+
+// ErrClientUnsupported is returned when a tool asks the client for something
+// it did not declare the capability for in initialize.
+var ErrClientUnsupported = errors.New("not supported by the client")
+
+var requestSeq atomic.Int64
+
+// clientState is what the server knows of its client: the capabilities it
+// declared and the requests sent to it that are awaiting a response. Clients
+// of HTTP transports declare their capabilities in their Session instead.
+type clientState struct {
+	mu           sync.Mutex
+	capabilities map[string]json.RawMessage
+	pending      map[string]chan *Request
+}
+
+func (e *RPCError) Error() string {
+	return fmt.Sprintf("%s (%d)", e.Message, e.Code)
+}
+
+type clientKey struct{}
+
+// withClient lets the handler of a call under ctx send requests to the
+// client the call came from.
+func (s *Server) withClient(ctx context.Context) context.Context {
+	return context.WithValue(ctx, clientKey{}, s)
+}
+
+func (s *Server) setClientCapabilities(ctx context.Context, capabilities map[string]json.RawMessage) {
+	if sess := SessionFromContext(ctx); sess != nil {
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		sess.capabilities = capabilities
+		return
+	}
+	s.client.mu.Lock()
+	defer s.client.mu.Unlock()
+	s.client.capabilities = capabilities
+}
+
+func (s *Server) clientSupports(ctx context.Context, capability string) bool {
+	if sess := SessionFromContext(ctx); sess != nil {
+		sess.mu.Lock()
+		defer sess.mu.Unlock()
+		_, ok := sess.capabilities[capability]
+		return ok
+	}
+	s.client.mu.Lock()
+	defer s.client.mu.Unlock()
+	_, ok := s.client.capabilities[capability]
+	return ok
+}
+
+// requestClient sends method to the client of the tool call running under
+// ctx, which must have declared capability, and decodes the result of its
+// response into result. If ctx ends first the client is told to cancel.
+func requestClient(ctx context.Context, capability, method string, params, result interface{}) error {
+	s, ok := ctx.Value(clientKey{}).(*Server)
+	if !ok || !s.clientSupports(ctx, capability) {
+		return fmt.Errorf("%w: %s", ErrClientUnsupported, method)
+	}
+
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	id := fmt.Sprintf("%s%d", requestIDPrefix, requestSeq.Add(1))
+	data, _ := json.Marshal(Request{JSONRPC: "2.0", ID: id, Method: method, Params: raw})
+
+	responses := make(chan *Request, 1)
+	s.client.mu.Lock()
+	if s.client.pending == nil {
+		s.client.pending = make(map[string]chan *Request)
+	}
+	s.client.pending[id] = responses
+	s.client.mu.Unlock()
+	defer func() {
+		s.client.mu.Lock()
+		delete(s.client.pending, id)
+		s.client.mu.Unlock()
+	}()
+
+	s.reply(ctx, data)
+	select {
+	case resp := <-responses:
+		if resp.Error != nil {
+			return fmt.Errorf("%s: %w", method, resp.Error)
+		}
+		return json.Unmarshal(resp.Result, result)
+	case <-ctx.Done():
+		s.notify(ctx, "notifications/cancelled", map[string]interface{}{
+			"requestId": id,
+			"reason":    ctx.Err().Error(),
+		})
+		return ctx.Err()
+	}
+}
+
+// handleResponse passes a response from the client to the request awaiting
+// it. Responses to pings, and to requests given up on, are dropped.
+func (s *Server) handleResponse(resp *Request) {
+	id, ok := resp.ID.(string)
+	if !ok || !strings.HasPrefix(id, requestIDPrefix) {
+		return
+	}
+	s.client.mu.Lock()
+	responses, ok := s.client.pending[id]
+	s.client.mu.Unlock()
+	if ok {
+		select {
+		case responses <- resp:
+		default: // a duplicate
+		}
+	}
+}
@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// pipeClient talks to a server running Run over pipes, as a stdio client.
+type pipeClient struct {
+	t     *testing.T
+	out   io.WriteCloser
+	lines *bufio.Scanner
+}
+
+func newPipeClient(t *testing.T, server *Server) *pipeClient {
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+	server.SetIO(serverIn, serverOut)
+	go server.Run(context.Background())
+	t.Cleanup(func() { clientOut.Close() })
+	return &pipeClient{t: t, out: clientOut, lines: bufio.NewScanner(clientIn)}
+}
+
+func (c *pipeClient) read() map[string]interface{} {
+	c.t.Helper()
+	require.True(c.t, c.lines.Scan())
+	var msg map[string]interface{}
+	require.NoError(c.t, json.Unmarshal(c.lines.Bytes(), &msg))
+	return msg
+}
+
+func (c *pipeClient) send(format string, args ...interface{}) {
+	fmt.Fprintf(c.out, format+"\n", args...)
+}
+
+// initialize declares capabilities, a JSON object, and reads the reply.
+func (c *pipeClient) initialize(capabilities string) {
+	c.t.Helper()
+	c.send(`{"jsonrpc":"2.0","id":0,"method":"initialize","params":{"capabilities":%s}}`, capabilities)
+	c.read()
+}
@@ -0,0 +1,38 @@
+package mcp
+
+import "context"
+
+// ElicitResult is the user's answer to an elicitation/create request.
+// Action is "accept", with Content set, "decline" or "cancel".
+type ElicitResult struct {
+	Action  string                 `json:"action"`
+	Content map[string]interface{} `json:"content,omitempty"`
+}
+
+// Elicit asks the user of the client of the tool call running under ctx for
+// input. requestedSchema is an object schema of primitive properties, as
+// BuildInputSchema makes. It fails with ErrClientUnsupported if the client
+// did not declare elicitation.
+func Elicit(ctx context.Context, message string, requestedSchema map[string]interface{}) (*ElicitResult, error) {
+	var result ElicitResult
+	params := map[string]interface{}{"message": message, "requestedSchema": requestedSchema}
+	if err := requestClient(ctx, "elicitation", "elicitation/create", params, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Confirm asks the user to confirm an action, reporting whether they did.
+func Confirm(ctx context.Context, message string) (bool, error) {
+	result, err := Elicit(ctx, message, BuildInputSchema(
+		map[string]interface{}{
+			"confirm": BoolProperty("Go ahead"),
+		},
+		[]string{"confirm"},
+	))
+	if err != nil {
+		return false, err
+	}
+	confirmed, _ := result.Content["confirm"].(bool)
+	return result.Action == "accept" && confirmed, nil
+}
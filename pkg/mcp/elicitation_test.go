@@ -0,0 +1,53 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfirm(t *testing.T) {
+	server := NewServer("test-server", "1.0.0")
+	server.RegisterTool(&Tool{
+		Name: "delete",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			confirmed, err := Confirm(ctx, "Delete everything?")
+			if err != nil {
+				return nil, err
+			}
+			return TextResult(fmt.Sprint("confirmed=", confirmed)), nil
+		},
+	})
+	c := newPipeClient(t, server)
+	c.initialize(`{"elicitation":{}}`)
+
+	for _, tc := range []struct {
+		answer string
+		want   string
+	}{
+		{`{"action":"accept","content":{"confirm":true}}`, "confirmed=true"},
+		{`{"action":"accept","content":{"confirm":false}}`, "confirmed=false"},
+		{`{"action":"decline"}`, "confirmed=false"},
+	} {
+		c.send(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"delete"}}`)
+		req := c.read()
+		require.Equal(t, "elicitation/create", req["method"])
+		params := req["params"].(map[string]interface{})
+		assert.Equal(t, "Delete everything?", params["message"])
+		assert.Contains(t, fmt.Sprint(params["requestedSchema"]), "confirm")
+
+		c.send(`{"jsonrpc":"2.0","id":%q,"result":%s}`, req["id"], tc.answer)
+		assert.Contains(t, fmt.Sprint(c.read()["result"]), tc.want, tc.answer)
+	}
+
+	// An error response fails the call rather than confirming it.
+	c.send(`{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"delete"}}`)
+	req := c.read()
+	c.send(`{"jsonrpc":"2.0","id":%q,"error":{"code":-32603,"message":"no UI"}}`, req["id"])
+	resp := c.read()
+	assert.Contains(t, fmt.Sprint(resp["result"]), "no UI")
+	assert.Contains(t, fmt.Sprint(resp["result"]), "isError:true")
+}
@@ -0,0 +1,146 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Framing is how messages are delimited on stdio.
+type Framing string
+
+const (
+	// FramingAuto detects the framing from the client's first message
+	// and answers in kind.
+	FramingAuto Framing = "auto"
+	// FramingNewline is newline-delimited JSON, as the MCP spec asks.
+	FramingNewline Framing = "newline"
+	// FramingContentLength prefixes each message with LSP-style
+	// Content-Length headers.
+	FramingContentLength Framing = "content-length"
+)
+
+// maxStdioMessage is the largest message Run reads.
+const maxStdioMessage = 10 * 1024 * 1024
+
+// SetFraming sets the stdio framing Run uses. The default is FramingAuto.
+func (s *Server) SetFraming(framing Framing) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	s.framing = framing
+}
+
+// frameReader reads the messages of a stdio client.
+type frameReader struct {
+	r *bufio.Reader
+	// detect is called with the framing once the first message shows it.
+	detect  func(Framing)
+	framing Framing
+}
+
+func newFrameReader(r io.Reader, framing Framing, detect func(Framing)) *frameReader {
+	return &frameReader{r: bufio.NewReaderSize(r, 64*1024), framing: framing, detect: detect}
+}
+
+// next returns the next message, which may be empty for blank lines.
+func (fr *frameReader) next() ([]byte, error) {
+	if fr.framing != FramingNewline && fr.framing != FramingContentLength {
+		framing, err := fr.sniff()
+		if err != nil {
+			return nil, err
+		}
+		fr.framing = framing
+		fr.detect(framing)
+	}
+	if fr.framing == FramingContentLength {
+		return fr.readContentLength()
+	}
+	return fr.readLine()
+}
+
+// sniff skips leading whitespace and reports the framing of what follows:
+// JSON starts with { or [, anything else is a header.
+func (fr *frameReader) sniff() (Framing, error) {
+	for {
+		b, err := fr.r.Peek(1)
+		if err != nil {
+			return "", err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n':
+			fr.r.ReadByte()
+		case '{', '[':
+			return FramingNewline, nil
+		default:
+			return FramingContentLength, nil
+		}
+	}
+}
+
+func (fr *frameReader) readLine() ([]byte, error) {
+	var line []byte
+	for {
+		chunk, err := fr.r.ReadSlice('\n')
+		line = append(line, chunk...)
+		if len(line) > maxStdioMessage {
+			return nil, fmt.Errorf("message exceeds %d bytes", maxStdioMessage)
+		}
+		switch {
+		case err == nil:
+			return bytes.TrimRight(line, "\r\n"), nil
+		case errors.Is(err, bufio.ErrBufferFull):
+			continue
+		case err == io.EOF && len(line) > 0:
+			return line, nil
+		default:
+			return nil, err
+		}
+	}
+}
+
+func (fr *frameReader) readContentLength() ([]byte, error) {
+	length := -1
+	for {
+		header, err := fr.readLine()
+		if err != nil {
+			return nil, err
+		}
+		if len(header) == 0 {
+			if length >= 0 {
+				break
+			}
+			continue // blank lines between messages
+		}
+		name, value, ok := strings.Cut(string(header), ":")
+		if !ok {
+			return nil, fmt.Errorf("malformed header %q", header)
+		}
+		if strings.EqualFold(strings.TrimSpace(name), "Content-Length") {
+			length, err = strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || length < 0 {
+				return nil, fmt.Errorf("invalid Content-Length %q", value)
+			}
+		}
+	}
+	if length > maxStdioMessage {
+		return nil, fmt.Errorf("message exceeds %d bytes", maxStdioMessage)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(fr.r, body); err != nil {
+		return nil, err
+	}
+	return body, nil
+}
+
+// writeFrame writes one message to w in framing.
+func writeFrame(w io.Writer, framing Framing, data []byte) {
+	if framing == FramingContentLength {
+		fmt.Fprintf(w, "Content-Length: %d\r\n\r\n%s", len(data), data)
+		return
+	}
+	fmt.Fprintln(w, string(data))
+}
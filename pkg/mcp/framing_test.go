@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFrameReader(t *testing.T) {
+	t.Run("detects newline-delimited JSON", func(t *testing.T) {
+		var detected Framing
+		fr := newFrameReader(strings.NewReader("\n{\"id\":1}\r\n{\"id\":2}"), FramingAuto, func(f Framing) { detected = f })
+		msg, err := fr.next()
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":1}`, string(msg))
+		assert.Equal(t, FramingNewline, detected)
+		msg, err = fr.next()
+		require.NoError(t, err)
+		assert.Equal(t, `{"id":2}`, string(msg))
+		_, err = fr.next()
+		assert.Equal(t, io.EOF, err)
+	})
+
+	t.Run("detects Content-Length headers", func(t *testing.T) {
+		var detected Framing
+		input := "Content-Length: 8\r\nContent-Type: application/json\r\n\r\n{\"id\":1}" +
+			"content-length:8\r\n\r\n{\"id\":2}"
+		fr := newFrameReader(strings.NewReader(input), FramingAuto, func(f Framing) { detected = f })
+		for _, want := range []string{`{"id":1}`, `{"id":2}`} {
+			msg, err := fr.next()
+			require.NoError(t, err)
+			assert.Equal(t, want, string(msg))
+		}
+		assert.Equal(t, FramingContentLength, detected)
+	})
+
+	t.Run("rejects bad headers", func(t *testing.T) {
+		fr := newFrameReader(strings.NewReader("Content-Length: x\r\n\r\n{}"), FramingContentLength, nil)
+		_, err := fr.next()
+		assert.ErrorContains(t, err, "invalid Content-Length")
+	})
+}
+
+func TestRunContentLengthFraming(t *testing.T) {
+	server := NewServer("test-server", "1.0.0")
+	clientIn, serverOut := io.Pipe()
+	serverIn, clientOut := io.Pipe()
+	server.SetIO(serverIn, serverOut)
+	go server.Run(context.Background())
+	defer clientOut.Close()
+
+	msg := `{"jsonrpc":"2.0","id":1,"method":"ping"}`
+	fmt.Fprintf(clientOut, "Content-Length: %d\r\n\r\n%s", len(msg), msg)
+
+	// The reply comes back framed the same way.
+	fr := newFrameReader(bufio.NewReader(clientIn), FramingContentLength, nil)
+	reply, err := fr.next()
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":1,"result":{}}`, string(reply))
+}
@@ -4,18 +4,37 @@ import (
 	"fmt"
 )
 
+// ParamError is a tool argument that is missing or has the wrong type. Its
+// details carry the code "invalid_params", as schema validation failures do.
+type ParamError struct {
+	Param   string
+	Message string
+}
+
+func (e *ParamError) Error() string {
+	return e.Message
+}
+
+func (e *ParamError) ErrorDetails() map[string]interface{} {
+	return map[string]interface{}{"code": "invalid_params", "param": e.Param}
+}
+
+func paramError(param, format string, args ...interface{}) error {
+	return &ParamError{Param: param, Message: fmt.Sprintf(format, args...)}
+}
+
 func GetStringParam(params map[string]interface{}, key string, required bool) (string, error) {
 	v, ok := params[key]
 	if !ok {
 		if required {
-			return "", fmt.Errorf("missing required parameter: %s", key)
+			return "", paramError(key, "missing required parameter: %s", key)
 		}
 		return "", nil
 	}
 
 	s, ok := v.(string)
 	if !ok {
-		return "", fmt.Errorf("parameter %s must be a string", key)
+		return "", paramError(key, "parameter %s must be a string", key)
 	}
 	return s, nil
 }
@@ -24,7 +43,7 @@ func GetIntParam(params map[string]interface{}, key string, required bool, defau
 	v, ok := params[key]
 	if !ok {
 		if required {
-			return 0, fmt.Errorf("missing required parameter: %s", key)
+			return 0, paramError(key, "missing required parameter: %s", key)
 		}
 		return defaultValue, nil
 	}
@@ -37,7 +56,7 @@ func GetIntParam(params map[string]interface{}, key string, required bool, defau
 	case int64:
 		return int(n), nil
 	default:
-		return 0, fmt.Errorf("parameter %s must be an integer", key)
+		return 0, paramError(key, "parameter %s must be an integer", key)
 	}
 }
 
@@ -49,7 +68,7 @@ func GetBoolParam(params map[string]interface{}, key string, defaultValue bool)
 
 	b, ok := v.(bool)
 	if !ok {
-		return false, fmt.Errorf("parameter %s must be a boolean", key)
+		return false, paramError(key, "parameter %s must be a boolean", key)
 	}
 	return b, nil
 }
@@ -58,21 +77,21 @@ func GetStringArrayParam(params map[string]interface{}, key string, required boo
 	v, ok := params[key]
 	if !ok {
 		if required {
-			return nil, fmt.Errorf("missing required parameter: %s", key)
+			return nil, paramError(key, "missing required parameter: %s", key)
 		}
 		return nil, nil
 	}
 
 	arr, ok := v.([]interface{})
 	if !ok {
-		return nil, fmt.Errorf("parameter %s must be an array", key)
+		return nil, paramError(key, "parameter %s must be an array", key)
 	}
 
 	result := make([]string, len(arr))
 	for i, item := range arr {
 		s, ok := item.(string)
 		if !ok {
-			return nil, fmt.Errorf("parameter %s[%d] must be a string", key, i)
+			return nil, paramError(key, "parameter %s[%d] must be a string", key, i)
 		}
 		result[i] = s
 	}
@@ -83,21 +102,21 @@ func GetMapParam(params map[string]interface{}, key string, required bool) (map[
 	v, ok := params[key]
 	if !ok {
 		if required {
-			return nil, fmt.Errorf("missing required parameter: %s", key)
+			return nil, paramError(key, "missing required parameter: %s", key)
 		}
 		return nil, nil
 	}
 
 	m, ok := v.(map[string]interface{})
 	if !ok {
-		return nil, fmt.Errorf("parameter %s must be an object", key)
+		return nil, paramError(key, "parameter %s must be an object", key)
 	}
 
 	result := make(map[string]string)
 	for k, val := range m {
 		s, ok := val.(string)
 		if !ok {
-			return nil, fmt.Errorf("parameter %s.%s must be a string", key, k)
+			return nil, paramError(key, "parameter %s.%s must be a string", key, k)
 		}
 		result[k] = s
 	}
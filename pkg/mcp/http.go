@@ -0,0 +1,106 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxMessageSize bounds a POSTed message, like the line limit of Run.
+const maxMessageSize = 10 * 1024 * 1024
+
+// HTTPHandler returns the HTTP transports: the Streamable HTTP transport of
+// protocol revision 2025-03-26 under /mcp, and the older HTTP+SSE transport
+// under /sse and /message. Once it is in use, notifications go to the HTTP
+// clients instead of the stdio output.
+func (s *Server) HTTPHandler() http.Handler {
+	sse := newSSETransport(s)
+	streamable := newStreamableTransport(s)
+	s.writeMu.Lock()
+	s.broadcast = func(data []byte) {
+		sse.broadcast(data)
+		streamable.broadcast(data)
+	}
+	s.writeMu.Unlock()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/mcp", streamable.handle)
+	mux.HandleFunc("/sse", sse.handleStream)
+	mux.HandleFunc("/message", sse.handleMessage)
+	return checkOrigin(mux, s.allowedHosts)
+}
+
+// defaultAllowedHosts are the host names the HTTP transports always accept.
+var defaultAllowedHosts = []string{"localhost", "127.0.0.1", "::1"}
+
+// SetAllowedHosts adds host names, besides localhost and the loopback
+// addresses, that requests to the HTTP transports may name in their Host
+// and Origin headers.
+func (s *Server) SetAllowedHosts(hosts []string) {
+	s.allowedHosts = hosts
+}
+
+// RunHTTP serves HTTPHandler on addr until ctx is cancelled or Shutdown
+// finishes.
+func (s *Server) RunHTTP(ctx context.Context, addr string) error {
+	httpServer := &http.Server{
+		Addr:    addr,
+		Handler: s.HTTPHandler(),
+		// Event streams and the requests they carry end with ctx.
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+	errc := make(chan error, 1)
+	go func() { errc <- httpServer.ListenAndServe() }()
+
+	select {
+	case err := <-errc:
+		return err
+	case <-ctx.Done():
+	case <-s.shutdown.done:
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return ctx.Err()
+}
+
+// checkOrigin rejects requests whose Host or Origin header names a host
+// that is not allowed. Comparing the two headers with each other is not
+// enough: after DNS rebinding, a page on another site reaches a server bound
+// to localhost with both naming the attacker's domain. Clients that send no
+// Origin header are not browsers, but their Host is still checked.
+func checkOrigin(next http.Handler, extra []string) http.Handler {
+	allowed := make(map[string]bool)
+	for _, host := range append(append([]string{}, defaultAllowedHosts...), extra...) {
+		allowed[strings.ToLower(hostname(host))] = true
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowed[strings.ToLower(hostname(r.Host))] {
+			http.Error(w, "host not allowed", http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || !allowed[strings.ToLower(u.Hostname())] {
+				http.Error(w, "origin not allowed", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hostname strips the port from a Host header value, and the brackets from
+// an IPv6 address.
+func hostname(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(hostport, "["), "]")
+}
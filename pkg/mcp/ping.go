@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// pingIDPrefix marks the IDs of the server's own ping requests, so that the
+// transports can recognize the replies.
+const pingIDPrefix = "ping-"
+
+// maxMissedPings is how many pings in a row a client may leave unanswered
+// before its event stream is closed.
+const maxMissedPings = 3
+
+var pingSeq atomic.Int64
+
+// SetPingInterval makes the HTTP transports ping every client holding an
+// event stream open at interval, and close the stream once it has missed
+// maxMissedPings replies. Zero, the default, disables pings.
+func (s *Server) SetPingInterval(interval time.Duration) {
+	s.pingInterval = interval
+}
+
+// pingTicker returns a channel that fires every ping interval, or never if
+// pings are disabled, and a function to stop it.
+func (s *Server) pingTicker() (<-chan time.Time, func()) {
+	if s.pingInterval <= 0 {
+		return nil, func() {}
+	}
+	ticker := time.NewTicker(s.pingInterval)
+	return ticker.C, ticker.Stop
+}
+
+// pinger tracks the pings one client has not answered yet.
+type pinger struct {
+	missed atomic.Int32
+}
+
+// next returns the ping request to send, or false if the client has
+// missed too many pings already.
+func (p *pinger) next() ([]byte, bool) {
+	if p.missed.Add(1) > maxMissedPings {
+		return nil, false
+	}
+	data, _ := json.Marshal(Request{
+		JSONRPC: "2.0",
+		ID:      fmt.Sprintf("%s%d", pingIDPrefix, pingSeq.Add(1)),
+		Method:  "ping",
+	})
+	return data, true
+}
+
+// answered records a message from the client, returning true if it is the
+// reply to a ping and needs no further handling.
+func (p *pinger) answered(msg *Request) bool {
+	id, ok := msg.ID.(string)
+	if msg.Method != "" || !ok || !strings.HasPrefix(id, pingIDPrefix) {
+		return false
+	}
+	p.missed.Store(0)
+	return true
+}
@@ -0,0 +1,40 @@
+package mcp
+
+import "context"
+
+type progressKey struct{}
+
+type progressReporter struct {
+	server *Server
+	token  interface{}
+}
+
+// withProgress makes ReportProgress send notifications for token, the
+// progressToken the client put in the call's _meta.
+func (s *Server) withProgress(ctx context.Context, token interface{}) context.Context {
+	if token == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressKey{}, &progressReporter{server: s, token: token})
+}
+
+// ReportProgress sends a notifications/progress message for the tool call
+// running under ctx. It does nothing when the client did not ask for
+// progress. total may be 0 when unknown.
+func ReportProgress(ctx context.Context, progress, total float64, message string) {
+	r, ok := ctx.Value(progressKey{}).(*progressReporter)
+	if !ok {
+		return
+	}
+	params := map[string]interface{}{
+		"progressToken": r.token,
+		"progress":      progress,
+	}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	r.server.notify(ctx, "notifications/progress", params)
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// RateLimit allows PerMinute calls a minute on average, in bursts of up to
+// Burst calls.
+type RateLimit struct {
+	PerMinute int
+	Burst     int
+}
+
+// RateLimitFunc returns the limit applying to tool and the key of the bucket
+// it draws from, which tools limited together share. ok is false for tools
+// without a limit.
+type RateLimitFunc func(tool string) (key string, limit RateLimit, ok bool)
+
+// RateLimitError is the error result of a tool call refused by its limit.
+type RateLimitError struct {
+	Tool       string
+	Key        string
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("rate limit for %s exceeded, retry in %s", e.Key, e.RetryAfter.Round(time.Millisecond))
+}
+
+func (e *RateLimitError) ErrorDetails() map[string]interface{} {
+	return map[string]interface{}{
+		"code":                "rate_limited",
+		"tool":                e.Tool,
+		"limit":               e.Key,
+		"retry_after_seconds": math.Ceil(e.RetryAfter.Seconds()),
+		"remediation":         "wait before calling again, or raise global.rate_limits",
+	}
+}
+
+// bucket is a token bucket holding up to limit.Burst tokens, refilled at
+// limit.PerMinute tokens a minute.
+type bucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+}
+
+type rateLimiter struct {
+	mu      sync.Mutex
+	limit   RateLimitFunc
+	buckets map[string]*bucket
+}
+
+// SetRateLimit sets the function giving each tool's rate limit. Like
+// SetToolTimeout's, it is called while Update is locked out.
+func (s *Server) SetRateLimit(limit RateLimitFunc) {
+	s.limiter.mu.Lock()
+	defer s.limiter.mu.Unlock()
+	s.limiter.limit = limit
+	s.limiter.buckets = make(map[string]*bucket)
+}
+
+// allow takes a token for a call of tool, or returns why it cannot.
+func (l *rateLimiter) allow(tool string, now time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.limit == nil {
+		return nil
+	}
+	key, limit, ok := l.limit(tool)
+	if !ok || limit.PerMinute <= 0 {
+		return nil
+	}
+	if limit.Burst < 1 {
+		limit.Burst = 1
+	}
+
+	b := l.buckets[key]
+	if b == nil || b.limit != limit {
+		// New and reconfigured limits start full.
+		b = &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		l.buckets[key] = b
+	}
+	perSecond := float64(limit.PerMinute) / 60
+	b.tokens = math.Min(float64(limit.Burst), b.tokens+now.Sub(b.last).Seconds()*perSecond)
+	b.last = now
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / perSecond * float64(time.Second))
+		return &RateLimitError{Tool: tool, Key: key, RetryAfter: wait}
+	}
+	b.tokens--
+	return nil
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiter(t *testing.T) {
+	var l rateLimiter
+	l.buckets = make(map[string]*bucket)
+	l.limit = func(tool string) (string, RateLimit, bool) {
+		switch tool {
+		case "read_a", "read_b":
+			return "reads", RateLimit{PerMinute: 60, Burst: 2}, true
+		case "write":
+			return "write", RateLimit{PerMinute: 6}, true
+		}
+		return "", RateLimit{}, false
+	}
+
+	now := time.Now()
+	assert.NoError(t, l.allow("read_a", now))
+	assert.NoError(t, l.allow("read_b", now))
+	err := l.allow("read_a", now)
+	var limited *RateLimitError
+	require.ErrorAs(t, err, &limited, "tools sharing a key share a bucket")
+	assert.Equal(t, "reads", limited.Key)
+	assert.Equal(t, time.Second, limited.RetryAfter)
+	assert.NoError(t, l.allow("read_a", now.Add(time.Second)))
+
+	assert.NoError(t, l.allow("write", now))
+	assert.Error(t, l.allow("write", now.Add(5*time.Second)), "burst defaults to 1")
+	assert.NoError(t, l.allow("write", now.Add(10*time.Second)))
+
+	for i := 0; i < 10; i++ {
+		assert.NoError(t, l.allow("unlimited", now))
+	}
+}
+
+func TestRateLimitedToolCall(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(strings.NewReader(""), &output)
+	calls := 0
+	server.RegisterTool(&Tool{
+		Name: "fetch",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			calls++
+			return TextResult("ok"), nil
+		},
+	})
+	server.SetRateLimit(func(tool string) (string, RateLimit, bool) {
+		return tool, RateLimit{PerMinute: 1}, true
+	})
+
+	call(t, server, &output, "tools/call", map[string]interface{}{"name": "fetch"})
+	assert.NotContains(t, output.String(), `"isError"`)
+	call(t, server, &output, "tools/call", map[string]interface{}{"name": "fetch"})
+	assert.Contains(t, output.String(), `"code":"rate_limited"`)
+	assert.Contains(t, output.String(), `"retry_after_seconds":60`)
+	assert.Equal(t, 1, calls, "refused calls do not reach the handler")
+}
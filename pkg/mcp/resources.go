@@ -0,0 +1,196 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// resourcePageSize is how many resources one resources/list page holds.
+const resourcePageSize = 100
+
+// ErrResourceNotFound is returned by ResourceSource.Read for URIs it does
+// not serve, and is sent to the client as JSON-RPC error -32002.
+var ErrResourceNotFound = errors.New("resource not found")
+
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+	Size        int64  `json:"size,omitempty"`
+}
+
+// ResourceTemplate describes URIs a source can read without listing them,
+// as an RFC 6570 template such as "file:///{path}".
+type ResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ResourceContents holds either Text or, for binary data, base64 in Blob.
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// ResourceSource serves the resources whose URIs start with Prefix.
+type ResourceSource struct {
+	Name      string
+	Prefix    string
+	Templates []ResourceTemplate
+	List      func(ctx context.Context) ([]Resource, error)
+	Read      func(ctx context.Context, uri string) (*ResourceContents, error)
+}
+
+// ResourceBlock embeds contents in a tool result, so that clients can
+// handle large or binary data apart from the text of the result.
+func ResourceBlock(contents *ResourceContents) ContentBlock {
+	return ContentBlock{Type: "resource", Resource: contents}
+}
+
+// ResourceLinkBlock refers to a resource instead of including it.
+func ResourceLinkBlock(r Resource) ContentBlock {
+	return ContentBlock{Type: "resource_link", URI: r.URI, Name: r.Name, MimeType: r.MimeType, Size: r.Size}
+}
+
+func (s *Server) RegisterResources(source *ResourceSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[source.Name] = source
+}
+
+func (s *Server) UnregisterResources(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.resources[name]; !ok {
+		return false
+	}
+	delete(s.resources, name)
+	return true
+}
+
+func (s *Server) NotifyResourcesListChanged() {
+	s.Notify("notifications/resources/list_changed", nil)
+}
+
+// resourceSources returns the registered sources ordered by name.
+func (s *Server) resourceSources() []*ResourceSource {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sources := make([]*ResourceSource, 0, len(s.resources))
+	for _, source := range s.resources {
+		sources = append(sources, source)
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Name < sources[j].Name })
+	return sources
+}
+
+func (s *Server) handleResourcesList(ctx context.Context, req *Request) {
+	var params struct {
+		Cursor string `json:"cursor"`
+	}
+	json.Unmarshal(req.Params, &params)
+	offset := 0
+	if params.Cursor != "" {
+		n, err := strconv.Atoi(params.Cursor)
+		if err != nil || n < 0 {
+			s.sendError(ctx, req.ID, -32602, "Invalid params", errorData("invalid_params", "invalid cursor"))
+			return
+		}
+		offset = n
+	}
+
+	s.execMu.RLock()
+	defer s.execMu.RUnlock()
+
+	resources := []Resource{}
+	for _, source := range s.resourceSources() {
+		if source.List == nil {
+			continue
+		}
+		listed, err := source.List(ctx)
+		if err != nil {
+			s.sendError(ctx, req.ID, -32603, "Internal error", errorData("internal_error", err.Error()))
+			return
+		}
+		resources = append(resources, listed...)
+	}
+
+	result := map[string]interface{}{}
+	if offset > len(resources) {
+		offset = len(resources)
+	}
+	end := offset + resourcePageSize
+	if end < len(resources) {
+		result["nextCursor"] = strconv.Itoa(end)
+	} else {
+		end = len(resources)
+	}
+	result["resources"] = resources[offset:end]
+	s.sendResult(ctx, req.ID, result)
+}
+
+func (s *Server) handleResourceTemplatesList(ctx context.Context, req *Request) {
+	templates := []ResourceTemplate{}
+	for _, source := range s.resourceSources() {
+		templates = append(templates, source.Templates...)
+	}
+	s.sendResult(ctx, req.ID, map[string]interface{}{"resourceTemplates": templates})
+}
+
+func (s *Server) handleResourcesRead(ctx context.Context, req *Request) {
+	var params struct {
+		URI string `json:"uri"`
+	}
+	if err := json.Unmarshal(req.Params, &params); err != nil || params.URI == "" {
+		s.sendError(ctx, req.ID, -32602, "Invalid params", errorData("invalid_params", "uri is required"))
+		return
+	}
+
+	s.execMu.RLock()
+	defer s.execMu.RUnlock()
+
+	var source *ResourceSource
+	for _, candidate := range s.resourceSources() {
+		if strings.HasPrefix(params.URI, candidate.Prefix) && candidate.Read != nil {
+			source = candidate
+			break
+		}
+	}
+	if source == nil {
+		s.sendError(ctx, req.ID, -32002, "Resource not found", map[string]interface{}{"code": "not_found", "uri": params.URI})
+		return
+	}
+
+	contents, err := source.Read(ctx, params.URI)
+	if err != nil {
+		data := map[string]interface{}{"uri": params.URI, "message": err.Error()}
+		var detailed DetailedError
+		if errors.As(err, &detailed) {
+			for k, v := range detailed.ErrorDetails() {
+				data[k] = v
+			}
+		}
+		if errors.Is(err, ErrResourceNotFound) {
+			if _, ok := data["code"]; !ok {
+				data["code"] = "not_found"
+			}
+			s.sendError(ctx, req.ID, -32002, "Resource not found", data)
+		} else {
+			if _, ok := data["code"]; !ok {
+				data["code"] = "internal_error"
+			}
+			s.sendError(ctx, req.ID, -32603, "Internal error", data)
+		}
+		return
+	}
+	s.sendResult(ctx, req.ID, map[string]interface{}{"contents": []*ResourceContents{contents}})
+}
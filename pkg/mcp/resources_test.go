@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func call(t *testing.T, server *Server, output *bytes.Buffer, method string, params interface{}) Response {
+	t.Helper()
+	output.Reset()
+	raw, _ := json.Marshal(params)
+	server.handleRequest(context.Background(), &Request{JSONRPC: "2.0", ID: 1, Method: method, Params: raw})
+	var resp Response
+	require.NoError(t, json.Unmarshal(output.Bytes(), &resp))
+	return resp
+}
+
+func TestResources(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(strings.NewReader(""), &output)
+
+	var listed []Resource
+	for i := 0; i < 150; i++ {
+		listed = append(listed, Resource{URI: fmt.Sprintf("mem://%d", i), Name: fmt.Sprint(i)})
+	}
+	server.RegisterResources(&ResourceSource{
+		Name:      "memory",
+		Prefix:    "mem://",
+		Templates: []ResourceTemplate{{URITemplate: "mem://{n}", Name: "Item"}},
+		List:      func(ctx context.Context) ([]Resource, error) { return listed, nil },
+		Read: func(ctx context.Context, uri string) (*ResourceContents, error) {
+			if uri == "mem://missing" {
+				return nil, fmt.Errorf("%w: %s", ErrResourceNotFound, uri)
+			}
+			return &ResourceContents{URI: uri, MimeType: "text/plain", Text: "item " + uri}, nil
+		},
+	})
+
+	resp := call(t, server, &output, "resources/list", nil)
+	require.Nil(t, resp.Error)
+	page := resp.Result.(map[string]interface{})
+	assert.Len(t, page["resources"], resourcePageSize)
+	assert.Equal(t, "100", page["nextCursor"])
+
+	resp = call(t, server, &output, "resources/list", map[string]interface{}{"cursor": "100"})
+	page = resp.Result.(map[string]interface{})
+	assert.Len(t, page["resources"], 50)
+	assert.NotContains(t, page, "nextCursor")
+
+	resp = call(t, server, &output, "resources/templates/list", nil)
+	assert.Contains(t, output.String(), `"uriTemplate":"mem://{n}"`)
+
+	resp = call(t, server, &output, "resources/read", map[string]interface{}{"uri": "mem://7"})
+	require.Nil(t, resp.Error)
+	assert.Contains(t, output.String(), `"text":"item mem://7"`)
+
+	resp = call(t, server, &output, "resources/read", map[string]interface{}{"uri": "mem://missing"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32002, resp.Error.Code)
+
+	resp = call(t, server, &output, "resources/read", map[string]interface{}{"uri": "other://x"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32002, resp.Error.Code)
+
+	assert.True(t, server.UnregisterResources("memory"))
+	resp = call(t, server, &output, "resources/list", nil)
+	assert.Empty(t, resp.Result.(map[string]interface{})["resources"])
+}
+
+func TestResourceBlocks(t *testing.T) {
+	embedded, _ := json.Marshal(ResourceBlock(&ResourceContents{URI: "mem://1", MimeType: "text/plain", Text: "one"}))
+	assert.JSONEq(t, `{"type":"resource","resource":{"uri":"mem://1","mimeType":"text/plain","text":"one"}}`, string(embedded))
+
+	link, _ := json.Marshal(ResourceLinkBlock(Resource{URI: "mem://1", Name: "one", Size: 3}))
+	assert.JSONEq(t, `{"type":"resource_link","uri":"mem://1","name":"one","size":3}`, string(link))
+}
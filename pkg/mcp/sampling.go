@@ -0,0 +1,55 @@
+package mcp
+
+import "context"
+
+type SamplingMessage struct {
+	Role    string       `json:"role"`
+	Content ContentBlock `json:"content"`
+}
+
+// ModelHint names a model, or a family such as "claude", the client may
+// prefer.
+type ModelHint struct {
+	Name string `json:"name"`
+}
+
+// ModelPreferences are advisory; priorities range from 0 to 1.
+type ModelPreferences struct {
+	Hints                []ModelHint `json:"hints,omitempty"`
+	CostPriority         float64     `json:"costPriority,omitempty"`
+	SpeedPriority        float64     `json:"speedPriority,omitempty"`
+	IntelligencePriority float64     `json:"intelligencePriority,omitempty"`
+}
+
+// SamplingRequest is the params of a sampling/createMessage request.
+type SamplingRequest struct {
+	Messages         []SamplingMessage `json:"messages"`
+	SystemPrompt     string            `json:"systemPrompt,omitempty"`
+	MaxTokens        int               `json:"maxTokens"`
+	StopSequences    []string          `json:"stopSequences,omitempty"`
+	ModelPreferences *ModelPreferences `json:"modelPreferences,omitempty"`
+}
+
+type SamplingResult struct {
+	Role       string       `json:"role"`
+	Content    ContentBlock `json:"content"`
+	Model      string       `json:"model"`
+	StopReason string       `json:"stopReason,omitempty"`
+}
+
+// UserMessage is a text message from the user role, the usual prompt of a
+// SamplingRequest.
+func UserMessage(text string) SamplingMessage {
+	return SamplingMessage{Role: "user", Content: ContentBlock{Type: "text", Text: text}}
+}
+
+// CreateMessage asks the client of the tool call running under ctx to sample
+// its model, which the client may show the user first. It fails with
+// ErrClientUnsupported if the client did not declare sampling.
+func CreateMessage(ctx context.Context, req *SamplingRequest) (*SamplingResult, error) {
+	var result SamplingResult
+	if err := requestClient(ctx, "sampling", "sampling/createMessage", req, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
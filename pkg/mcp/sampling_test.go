@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateMessage(t *testing.T) {
+	server := NewServer("test-server", "1.0.0")
+	server.RegisterTool(&Tool{
+		Name: "draft",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			result, err := CreateMessage(ctx, &SamplingRequest{
+				Messages:  []SamplingMessage{UserMessage("Summarize the diff")},
+				MaxTokens: 100,
+			})
+			if err != nil {
+				return nil, err
+			}
+			return TextResult(result.Content.Text + " (" + result.Model + ")"), nil
+		},
+	})
+	c := newPipeClient(t, server)
+
+	c.initialize(`{"sampling":{}}`)
+	c.send(`{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"draft"}}`)
+
+	req := c.read()
+	require.Equal(t, "sampling/createMessage", req["method"])
+	params := req["params"].(map[string]interface{})
+	assert.Equal(t, float64(100), params["maxTokens"])
+	c.send(`{"jsonrpc":"2.0","id":%q,"result":{"role":"assistant","content":{"type":"text","text":"Fix typo"},"model":"m1"}}`, req["id"])
+
+	resp := c.read()
+	assert.Equal(t, float64(2), resp["id"])
+	assert.Contains(t, fmt.Sprint(resp["result"]), "Fix typo (m1)")
+
+	// A client that did not declare sampling is not asked.
+	c.initialize(`{}`)
+	c.send(`{"jsonrpc":"2.0","id":4,"method":"tools/call","params":{"name":"draft"}}`)
+	resp = c.read()
+	assert.Equal(t, float64(4), resp["id"])
+	assert.Contains(t, fmt.Sprint(resp["result"]), "not supported by the client")
+}
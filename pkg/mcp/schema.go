@@ -0,0 +1,232 @@
+package mcp
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// SchemaError describes one argument that does not match a tool's input
+// schema.
+type SchemaError struct {
+	Param   string `json:"param"`
+	Message string `json:"message"`
+}
+
+func (e SchemaError) Error() string {
+	return e.Param + " " + e.Message
+}
+
+// ValidateArguments checks a tool call's arguments against its input
+// schema. It understands the subset of JSON Schema that BuildInputSchema and
+// the property helpers produce: type (or a list of types), properties,
+// required, items, additionalProperties and enum. Unknown arguments are
+// allowed, and null counts as omitted for optional ones.
+func ValidateArguments(schema map[string]interface{}, args map[string]interface{}) []SchemaError {
+	return validateArguments(schema, args, false)
+}
+
+// ValidateArgumentsStrict is ValidateArguments, except that arguments an
+// object schema does not declare are errors, unless it has
+// additionalProperties.
+func ValidateArgumentsStrict(schema map[string]interface{}, args map[string]interface{}) []SchemaError {
+	return validateArguments(schema, args, true)
+}
+
+type validation struct {
+	strict bool
+	errs   []SchemaError
+}
+
+func (v *validation) fail(path, message string) {
+	v.errs = append(v.errs, SchemaError{Param: path, Message: message})
+}
+
+func validateArguments(schema map[string]interface{}, args map[string]interface{}, strict bool) []SchemaError {
+	if schema == nil {
+		return nil
+	}
+	if args == nil {
+		args = map[string]interface{}{}
+	}
+	v := &validation{strict: strict}
+	v.object(schema, "", args)
+	sort.Slice(v.errs, func(i, j int) bool { return v.errs[i].Param < v.errs[j].Param })
+	return v.errs
+}
+
+func (v *validation) object(schema map[string]interface{}, path string, obj map[string]interface{}) {
+	for _, name := range stringList(schema["required"]) {
+		if val, ok := obj[name]; !ok || val == nil {
+			v.fail(joinParam(path, name), "is required")
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	additional, _ := schema["additionalProperties"].(map[string]interface{})
+	for name, val := range obj {
+		prop, ok := properties[name].(map[string]interface{})
+		if !ok {
+			prop = additional
+		}
+		if prop == nil && v.strict && properties != nil {
+			v.fail(joinParam(path, name), unknownParam(name, properties))
+			continue
+		}
+		if prop != nil && val != nil {
+			v.value(prop, joinParam(path, name), val)
+		}
+	}
+}
+
+func (v *validation) value(schema map[string]interface{}, path string, val interface{}) {
+	if types := stringList(schema["type"]); len(types) > 0 {
+		matched := false
+		for _, t := range types {
+			if hasType(val, t) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			v.fail(path, "must be "+article(types))
+			return
+		}
+	}
+
+	if enum, ok := schema["enum"]; ok {
+		allowed := stringList(enum)
+		found := false
+		for _, e := range allowed {
+			if s, ok := val.(string); ok && s == e {
+				found = true
+				break
+			}
+		}
+		if !found {
+			v.fail(path, "must be one of "+strings.Join(allowed, ", "))
+			return
+		}
+	}
+
+	switch val := val.(type) {
+	case map[string]interface{}:
+		v.object(schema, path, val)
+	case []interface{}:
+		if items, ok := schema["items"].(map[string]interface{}); ok {
+			for i, item := range val {
+				v.value(items, fmt.Sprintf("%s[%d]", path, i), item)
+			}
+		}
+	}
+}
+
+// unknownParam describes an undeclared argument, suggesting the declared
+// one it is most likely a typo of.
+func unknownParam(name string, properties map[string]interface{}) string {
+	best, bestDist := "", 3
+	for prop := range properties {
+		if d := editDistance(name, prop); d < bestDist || d == bestDist && prop < best {
+			best, bestDist = prop, d
+		}
+	}
+	if best == "" {
+		return "is not a parameter of this tool"
+	}
+	return "is not a parameter of this tool; did you mean " + best + "?"
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func hasType(v interface{}, t string) bool {
+	switch t {
+	case "string":
+		_, ok := v.(string)
+		return ok
+	case "integer":
+		switch n := v.(type) {
+		case float64:
+			return n == math.Trunc(n) && !math.IsInf(n, 0)
+		case int, int64:
+			return true
+		}
+		return false
+	case "number":
+		switch v.(type) {
+		case float64, int, int64:
+			return true
+		}
+		return false
+	case "boolean":
+		_, ok := v.(bool)
+		return ok
+	case "array":
+		_, ok := v.([]interface{})
+		return ok
+	case "object":
+		_, ok := v.(map[string]interface{})
+		return ok
+	case "null":
+		return v == nil
+	}
+	return true
+}
+
+// stringList reads a schema keyword that holds a string or a list of them.
+func stringList(v interface{}) []string {
+	switch list := v.(type) {
+	case string:
+		return []string{list}
+	case []string:
+		return list
+	case []interface{}:
+		out := make([]string, 0, len(list))
+		for _, item := range list {
+			out = append(out, fmt.Sprint(item))
+		}
+		return out
+	}
+	return nil
+}
+
+func article(types []string) string {
+	words := make([]string, len(types))
+	for i, t := range types {
+		switch t {
+		case "integer", "array", "object":
+			words[i] = "an " + t
+		case "null":
+			words[i] = "null"
+		default:
+			words[i] = "a " + t
+		}
+	}
+	return strings.Join(words, " or ")
+}
+
+func joinParam(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
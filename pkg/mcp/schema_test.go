@@ -0,0 +1,132 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateArguments(t *testing.T) {
+	schema := BuildInputSchema(map[string]interface{}{
+		"path":  StringProperty("Path"),
+		"count": IntProperty("Count"),
+		"force": BoolProperty("Force"),
+		"files": ArrayProperty("string", "Files"),
+		"env":   MapProperty("Environment"),
+		"mode":  map[string]interface{}{"type": "string", "enum": []string{"fast", "slow"}},
+		"param": map[string]interface{}{"type": []string{"string", "number", "null"}},
+	}, []string{"path"})
+
+	valid := map[string]interface{}{
+		"path":  "/tmp",
+		"count": float64(3),
+		"force": nil,
+		"files": []interface{}{"a", "b"},
+		"env":   map[string]interface{}{"A": "1"},
+		"mode":  "fast",
+		"param": 1.5,
+		"extra": "ignored",
+	}
+	assert.Empty(t, ValidateArguments(schema, valid))
+
+	errs := ValidateArguments(schema, map[string]interface{}{
+		"count": 2.5,
+		"force": "yes",
+		"files": []interface{}{"a", float64(1)},
+		"env":   map[string]interface{}{"A": true},
+		"mode":  "medium",
+		"param": false,
+	})
+	assert.Equal(t, []SchemaError{
+		{Param: "count", Message: "must be an integer"},
+		{Param: "env.A", Message: "must be a string"},
+		{Param: "files[1]", Message: "must be a string"},
+		{Param: "force", Message: "must be a boolean"},
+		{Param: "mode", Message: "must be one of fast, slow"},
+		{Param: "param", Message: "must be a string or a number or null"},
+		{Param: "path", Message: "is required"},
+	}, errs)
+}
+
+func TestToolsCallValidatesArguments(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(strings.NewReader(""), &output)
+	called := false
+	server.RegisterTool(&Tool{
+		Name:        "echo",
+		InputSchema: BuildInputSchema(map[string]interface{}{"message": StringProperty("Message")}, []string{"message"}),
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			called = true
+			return TextResult("ok"), nil
+		},
+	})
+
+	params, _ := json.Marshal(map[string]interface{}{"name": "echo", "arguments": map[string]interface{}{"message": 42}})
+	server.handleRequest(context.Background(), &Request{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: params})
+
+	var resp Response
+	require.NoError(t, json.Unmarshal(output.Bytes(), &resp))
+	require.NotNil(t, resp.Error)
+	assert.False(t, called)
+	assert.Equal(t, -32602, resp.Error.Code)
+	assert.Equal(t, "Invalid params: message must be a string", resp.Error.Message)
+	assert.Equal(t, "invalid_params", resp.Error.Data.(map[string]interface{})["code"])
+}
+
+func TestValidateArgumentsStrict(t *testing.T) {
+	schema := BuildInputSchema(map[string]interface{}{
+		"path":      StringProperty("Path"),
+		"recursive": BoolProperty("Recursive"),
+		"env":       MapProperty("Environment"),
+	}, []string{"path"})
+
+	args := map[string]interface{}{
+		"path":     "/tmp",
+		"recursve": true,
+		"colour":   "red",
+		"env":      map[string]interface{}{"ANY": "1"},
+	}
+	assert.Empty(t, ValidateArguments(schema, args))
+	assert.Equal(t, []SchemaError{
+		{Param: "colour", Message: "is not a parameter of this tool"},
+		{Param: "recursve", Message: "is not a parameter of this tool; did you mean recursive?"},
+	}, ValidateArgumentsStrict(schema, args))
+}
+
+func TestToolsCallStrictParams(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(strings.NewReader(""), &output)
+	server.RegisterTool(&Tool{
+		Name:        "echo",
+		InputSchema: BuildInputSchema(map[string]interface{}{"message": StringProperty("Message")}, nil),
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			return TextResult("ok"), nil
+		},
+	})
+	strict := false
+	server.SetStrictParams(func() bool { return strict })
+
+	call := func() Response {
+		output.Reset()
+		params, _ := json.Marshal(map[string]interface{}{"name": "echo", "arguments": map[string]interface{}{"mesage": "hi"}})
+		server.handleRequest(context.Background(), &Request{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: params})
+		var resp Response
+		require.NoError(t, json.Unmarshal(output.Bytes(), &resp))
+		return resp
+	}
+
+	assert.Nil(t, call().Error)
+
+	strict = true
+	resp := call()
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32602, resp.Error.Code)
+	assert.Equal(t, "Invalid params: mesage is not a parameter of this tool; did you mean message?", resp.Error.Message)
+}
@@ -1,48 +1,96 @@
 package mcp
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"io"
 	"os"
+	"strings"
 	"sync"
+	"time"
 )
 
 type Server struct {
 	name    string
 	version string
 	tools   map[string]*Tool
-	mu      sync.RWMutex
-	input   io.Reader
-	output  io.Writer
+	// resources are the registered resource sources by name.
+	resources map[string]*ResourceSource
+	mu        sync.RWMutex
+	execMu    sync.RWMutex
+	writeMu   sync.Mutex
+	framing   Framing
+	after     []func()
+	input     io.Reader
+	output    io.Writer
+	// broadcast, when set by an HTTP transport, replaces output for
+	// messages that are not a reply to a request.
+	broadcast func(data []byte)
+	// pingInterval is how often the HTTP transports ping their clients.
+	pingInterval time.Duration
+	// allowedHosts are the host names the HTTP transports accept besides
+	// localhost.
+	allowedHosts []string
+	toolTimeout  func(tool string) time.Duration
+	strictParams func() bool
+	limiter      rateLimiter
+	shutdown     shutdownState
+	client       clientState
 }
 
 type Tool struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
-	Handler     ToolHandler            `json:"-"`
+	// OutputSchema, if set, describes the structuredContent of the tool's
+	// successful results.
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
+	Annotations  *ToolAnnotations       `json:"annotations,omitempty"`
+	Handler      ToolHandler            `json:"-"`
 }
 
 type ToolHandler func(ctx context.Context, params map[string]interface{}) (*ToolResult, error)
 
 type ToolResult struct {
 	Content []ContentBlock `json:"content"`
-	IsError bool           `json:"isError,omitempty"`
+	// StructuredContent is the result as a JSON object, for clients that
+	// consume typed data. Content still carries it as text.
+	StructuredContent interface{}            `json:"structuredContent,omitempty"`
+	IsError           bool                   `json:"isError,omitempty"`
+	Meta              map[string]interface{} `json:"_meta,omitempty"`
+}
+
+// DetailedError is implemented by errors that carry machine-readable fields
+// such as an error code or the offending path. Tool errors implementing it
+// (anywhere in their chain) are sent with the details under _meta.error.
+type DetailedError interface {
+	error
+	ErrorDetails() map[string]interface{}
 }
 
 type ContentBlock struct {
 	Type string `json:"type"`
 	Text string `json:"text,omitempty"`
+	// Resource is the embedded resource of a "resource" block.
+	Resource *ResourceContents `json:"resource,omitempty"`
+	// The fields of a "resource_link" block, which names a resource for
+	// the client to read with resources/read.
+	URI      string `json:"uri,omitempty"`
+	Name     string `json:"name,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+	Size     int64  `json:"size,omitempty"`
 }
 
+// Request is any message from the client. Responses to the server's own
+// requests have no Method but a Result or Error.
 type Request struct {
 	JSONRPC string          `json:"jsonrpc"`
 	ID      interface{}     `json:"id"`
 	Method  string          `json:"method"`
 	Params  json.RawMessage `json:"params,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+	Error   *RPCError       `json:"error,omitempty"`
 }
 
 type Response struct {
@@ -52,6 +100,12 @@ type Response struct {
 	Error   *RPCError   `json:"error,omitempty"`
 }
 
+type Notification struct {
+	JSONRPC string      `json:"jsonrpc"`
+	Method  string      `json:"method"`
+	Params  interface{} `json:"params,omitempty"`
+}
+
 type RPCError struct {
 	Code    int         `json:"code"`
 	Message string      `json:"message"`
@@ -60,11 +114,13 @@ type RPCError struct {
 
 func NewServer(name, version string) *Server {
 	return &Server{
-		name:    name,
-		version: version,
-		tools:   make(map[string]*Tool),
-		input:   os.Stdin,
-		output:  os.Stdout,
+		name:      name,
+		version:   version,
+		tools:     make(map[string]*Tool),
+		resources: make(map[string]*ResourceSource),
+		input:     os.Stdin,
+		output:    os.Stdout,
+		shutdown:  newShutdownState(),
 	}
 }
 
@@ -79,121 +135,365 @@ func (s *Server) RegisterTool(tool *Tool) {
 	s.tools[tool.Name] = tool
 }
 
+// NamespacedName is name in the tool group prefix: "fs.read_file", or
+// "git.status" for git_status, which already carries the prefix.
+func NamespacedName(prefix, name string) string {
+	return prefix + "." + strings.TrimPrefix(name, prefix+"_")
+}
+
+// RegisterToolGroup registers tools under NamespacedName(prefix, name), so
+// tools of different groups cannot collide and clients can tell the groups
+// apart.
+func (s *Server) RegisterToolGroup(prefix string, tools []*Tool) {
+	for _, tool := range tools {
+		namespaced := *tool
+		namespaced.Name = NamespacedName(prefix, tool.Name)
+		s.RegisterTool(&namespaced)
+	}
+}
+
+// SetStrictParams sets the function reporting whether tools/call rejects
+// arguments the tool does not declare. Like the SetToolTimeout function, it
+// may read configuration that Update swaps.
+func (s *Server) SetStrictParams(strict func() bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.strictParams = strict
+}
+
+func (s *Server) UnregisterTool(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.tools[name]; !ok {
+		return false
+	}
+	delete(s.tools, name)
+	return true
+}
+
+// Update runs fn while no tool handler is executing, so configuration and
+// tool registrations can be swapped without racing in-flight calls.
+func (s *Server) Update(fn func()) {
+	s.execMu.Lock()
+	defer s.execMu.Unlock()
+	fn()
+}
+
+// AfterCall queues fn to run once the current tool call has been answered
+// and no longer blocks Update. Handlers use it for work that needs Update.
+func (s *Server) AfterCall(fn func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.after = append(s.after, fn)
+}
+
+func (s *Server) runAfterCall() {
+	s.mu.Lock()
+	pending := s.after
+	s.after = nil
+	s.mu.Unlock()
+
+	for _, fn := range pending {
+		fn()
+	}
+}
+
+// Notify sends a notification to every connected client.
+func (s *Server) Notify(method string, params interface{}) {
+	s.notify(context.Background(), method, params)
+}
+
+// notify sends a notification to the client whose request ctx belongs to,
+// or to every client outside a request.
+func (s *Server) notify(ctx context.Context, method string, params interface{}) {
+	data, err := json.Marshal(Notification{
+		JSONRPC: "2.0",
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		return
+	}
+	s.reply(ctx, data)
+}
+
+func (s *Server) NotifyToolsListChanged() {
+	s.Notify("notifications/tools/list_changed", nil)
+}
+
+// Run serves stdin until it ends, ctx is cancelled or Shutdown finishes.
 func (s *Server) Run(ctx context.Context) error {
-	scanner := bufio.NewScanner(s.input)
-	scanner.Buffer(make([]byte, 1024*1024), 10*1024*1024)
+	lines := make(chan []byte)
+	errc := make(chan error, 1)
+	s.writeMu.Lock()
+	framing := s.framing
+	s.writeMu.Unlock()
+	reader := newFrameReader(s.input, framing, s.SetFraming)
+	go func() {
+		for {
+			line, err := reader.next()
+			if err != nil {
+				if err == io.EOF {
+					err = nil
+				}
+				errc <- err
+				return
+			}
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			case <-s.shutdown.done:
+				return
+			}
+		}
+	}()
 
-	for scanner.Scan() {
+	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		default:
-		}
-
-		line := scanner.Bytes()
-		if len(line) == 0 {
-			continue
+		case <-s.shutdown.done:
+			return nil
+		case err := <-errc:
+			return err
+		case line := <-lines:
+			if len(line) == 0 {
+				continue
+			}
+
+			var req Request
+			if err := json.Unmarshal(line, &req); err != nil {
+				s.sendError(ctx, nil, -32700, "Parse error", errorData("parse_error", err.Error()))
+				continue
+			}
+
+			// Tool calls may wait on requests to the client, whose
+			// responses this loop has to read. They count as in flight
+			// before Run can return, so shutting down waits for them.
+			if req.Method == "tools/call" {
+				callCtx, end, ok := s.admit(ctx, &req)
+				if ok {
+					go func() {
+						defer end()
+						s.dispatch(callCtx, &req)
+					}()
+				}
+			} else {
+				s.handleRequest(ctx, &req)
+			}
 		}
+	}
+}
 
-		var req Request
-		if err := json.Unmarshal(line, &req); err != nil {
-			s.sendError(nil, -32700, "Parse error", err.Error())
-			continue
+func (s *Server) handleRequest(ctx context.Context, req *Request) {
+	// Responses have no method and nothing to drain.
+	if req.Method != "" {
+		var end func()
+		var ok bool
+		if ctx, end, ok = s.admit(ctx, req); !ok {
+			return
 		}
-
-		s.handleRequest(ctx, &req)
+		defer end()
 	}
+	s.dispatch(ctx, req)
+}
 
-	return scanner.Err()
+// admit counts req as in flight, as begin does, or refuses it once the
+// server is shutting down.
+func (s *Server) admit(ctx context.Context, req *Request) (context.Context, func(), bool) {
+	ctx, end, ok := s.begin(ctx)
+	if !ok && req.ID != nil {
+		s.sendError(ctx, req.ID, -32000, "Server is shutting down", errorData("shutting_down", ""))
+	}
+	return ctx, end, ok
 }
 
-func (s *Server) handleRequest(ctx context.Context, req *Request) {
+func (s *Server) dispatch(ctx context.Context, req *Request) {
 	switch req.Method {
 	case "initialize":
-		s.handleInitialize(req)
+		s.handleInitialize(ctx, req)
 	case "tools/list":
-		s.handleToolsList(req)
+		s.handleToolsList(ctx, req)
 	case "tools/call":
 		s.handleToolsCall(ctx, req)
+	case "resources/list":
+		s.handleResourcesList(ctx, req)
+	case "resources/templates/list":
+		s.handleResourceTemplatesList(ctx, req)
+	case "resources/read":
+		s.handleResourcesRead(ctx, req)
+	case "ping":
+		s.sendResult(ctx, req.ID, map[string]interface{}{})
 	case "notifications/initialized":
 		// Acknowledged, no response needed
+	case "":
+		s.handleResponse(req)
 	default:
-		s.sendError(req.ID, -32601, "Method not found", req.Method)
+		// Notifications get no response, not even for unknown methods.
+		if req.ID != nil {
+			s.sendError(ctx, req.ID, -32601, "Method not found", map[string]interface{}{
+				"code":   "method_not_found",
+				"method": req.Method,
+			})
+		}
 	}
 }
 
-func (s *Server) handleInitialize(req *Request) {
+// protocolVersions are the protocol revisions the server speaks, oldest
+// first. Newer revisions only add transports and optional result fields.
+var protocolVersions = []string{"2024-11-05", "2025-03-26", "2025-06-18"}
+
+func (s *Server) handleInitialize(ctx context.Context, req *Request) {
+	var params struct {
+		ProtocolVersion string                     `json:"protocolVersion"`
+		Capabilities    map[string]json.RawMessage `json:"capabilities"`
+	}
+	json.Unmarshal(req.Params, &params)
+	s.setClientCapabilities(ctx, params.Capabilities)
+	// Answer with the client's version if supported, as the spec asks,
+	// and otherwise with the oldest so older clients keep working.
+	version := protocolVersions[0]
+	for _, v := range protocolVersions {
+		if v == params.ProtocolVersion {
+			version = v
+		}
+	}
+
 	result := map[string]interface{}{
-		"protocolVersion": "2024-11-05",
+		"protocolVersion": version,
 		"capabilities": map[string]interface{}{
-			"tools": map[string]interface{}{},
+			"tools": map[string]interface{}{
+				"listChanged": true,
+			},
+			"resources": map[string]interface{}{
+				"listChanged": true,
+			},
 		},
 		"serverInfo": map[string]interface{}{
 			"name":    s.name,
 			"version": s.version,
 		},
 	}
-	s.sendResult(req.ID, result)
+	s.sendResult(ctx, req.ID, result)
 }
 
-func (s *Server) handleToolsList(req *Request) {
+func (s *Server) handleToolsList(ctx context.Context, req *Request) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	tools := make([]map[string]interface{}, 0, len(s.tools))
 	for _, tool := range s.tools {
-		tools = append(tools, map[string]interface{}{
+		entry := map[string]interface{}{
 			"name":        tool.Name,
 			"description": tool.Description,
 			"inputSchema": tool.InputSchema,
-		})
+		}
+		if tool.OutputSchema != nil {
+			entry["outputSchema"] = tool.OutputSchema
+		}
+		if tool.Annotations != nil {
+			entry["annotations"] = tool.Annotations
+		}
+		tools = append(tools, entry)
 	}
 
-	s.sendResult(req.ID, map[string]interface{}{"tools": tools})
+	s.sendResult(ctx, req.ID, map[string]interface{}{"tools": tools})
 }
 
 func (s *Server) handleToolsCall(ctx context.Context, req *Request) {
 	var params struct {
 		Name      string                 `json:"name"`
 		Arguments map[string]interface{} `json:"arguments"`
+		Meta      struct {
+			ProgressToken interface{} `json:"progressToken"`
+		} `json:"_meta"`
 	}
 
 	if err := json.Unmarshal(req.Params, &params); err != nil {
-		s.sendError(req.ID, -32602, "Invalid params", err.Error())
+		s.sendError(ctx, req.ID, -32602, "Invalid params", errorData("invalid_params", err.Error()))
 		return
 	}
 
+	s.execMu.RLock()
+	release := func() {
+		s.execMu.RUnlock()
+		s.runAfterCall()
+	}
+	detached := false
+	defer func() {
+		if !detached {
+			release()
+		}
+	}()
+
 	s.mu.RLock()
 	tool, ok := s.tools[params.Name]
 	s.mu.RUnlock()
 
 	if !ok {
-		s.sendError(req.ID, -32602, "Unknown tool", params.Name)
+		s.sendError(ctx, req.ID, -32602, "Unknown tool", map[string]interface{}{
+			"code": "unknown_tool",
+			"tool": params.Name,
+		})
 		return
 	}
 
-	result, err := tool.Handler(ctx, params.Arguments)
-	if err != nil {
-		s.sendResult(req.ID, &ToolResult{
-			Content: []ContentBlock{{Type: "text", Text: err.Error()}},
-			IsError: true,
+	s.mu.RLock()
+	strict := s.strictParams != nil && s.strictParams()
+	s.mu.RUnlock()
+	validate := ValidateArguments
+	if strict {
+		validate = ValidateArgumentsStrict
+	}
+	if errs := validate(tool.InputSchema, params.Arguments); len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, e := range errs {
+			messages[i] = e.Error()
+		}
+		s.sendError(ctx, req.ID, -32602, "Invalid params: "+strings.Join(messages, "; "), map[string]interface{}{
+			"code":   "invalid_params",
+			"tool":   params.Name,
+			"errors": errs,
 		})
 		return
 	}
 
-	s.sendResult(req.ID, result)
+	if err := s.limiter.allow(tool.Name, time.Now()); err != nil {
+		s.sendResult(ctx, req.ID, ErrorResult(err))
+		return
+	}
+
+	ctx = s.withClient(s.withProgress(ctx, params.Meta.ProgressToken))
+	result, detached, err := s.callTool(ctx, tool, params.Arguments, release)
+	if err != nil {
+		s.sendResult(ctx, req.ID, ErrorResult(err))
+		return
+	}
+
+	s.sendResult(ctx, req.ID, result)
 }
 
-func (s *Server) sendResult(id interface{}, result interface{}) {
+func (s *Server) sendResult(ctx context.Context, id interface{}, result interface{}) {
 	resp := Response{
 		JSONRPC: "2.0",
 		ID:      id,
 		Result:  result,
 	}
-	s.send(resp)
+	s.send(ctx, resp)
 }
 
-func (s *Server) sendError(id interface{}, code int, message, data string) {
+// errorData is the data of a JSON-RPC error: a machine-readable code, like
+// the one in tool error details, and an optional message.
+func errorData(code, message string) map[string]interface{} {
+	data := map[string]interface{}{"code": code}
+	if message != "" {
+		data["message"] = message
+	}
+	return data
+}
+
+func (s *Server) sendError(ctx context.Context, id interface{}, code int, message string, data interface{}) {
 	resp := Response{
 		JSONRPC: "2.0",
 		ID:      id,
@@ -203,15 +503,41 @@ func (s *Server) sendError(id interface{}, code int, message, data string) {
 			Data:    data,
 		},
 	}
-	s.send(resp)
+	s.send(ctx, resp)
 }
 
-func (s *Server) send(resp Response) {
+func (s *Server) send(ctx context.Context, resp Response) {
 	data, err := json.Marshal(resp)
 	if err != nil {
 		return
 	}
-	fmt.Fprintln(s.output, string(data))
+	s.reply(ctx, data)
+}
+
+type replyKey struct{}
+
+// withReply routes the replies and notifications of the request handled
+// under ctx to send, the session the request came from.
+func withReply(ctx context.Context, send func(data []byte)) context.Context {
+	return context.WithValue(ctx, replyKey{}, send)
+}
+
+func (s *Server) reply(ctx context.Context, data []byte) {
+	if send, ok := ctx.Value(replyKey{}).(func(data []byte)); ok {
+		send(data)
+		return
+	}
+	s.write(data)
+}
+
+func (s *Server) write(data []byte) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if s.broadcast != nil {
+		s.broadcast(data)
+		return
+	}
+	writeFrame(s.output, s.framing, data)
 }
 
 func TextResult(text string) *ToolResult {
@@ -220,19 +546,30 @@ func TextResult(text string) *ToolResult {
 	}
 }
 
+// JSONResult returns v as indented JSON text and, when v encodes to an
+// object, also as structured content.
 func JSONResult(v interface{}) (*ToolResult, error) {
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
 		return nil, err
 	}
-	return TextResult(string(data)), nil
+	result := TextResult(string(data))
+	if len(data) > 0 && data[0] == '{' {
+		result.StructuredContent = json.RawMessage(data)
+	}
+	return result, nil
 }
 
 func ErrorResult(err error) *ToolResult {
-	return &ToolResult{
+	result := &ToolResult{
 		Content: []ContentBlock{{Type: "text", Text: err.Error()}},
 		IsError: true,
 	}
+	var detailed DetailedError
+	if errors.As(err, &detailed) {
+		result.Meta = map[string]interface{}{"error": detailed.ErrorDetails()}
+	}
+	return result
 }
 
 func BuildInputSchema(properties map[string]interface{}, required []string) map[string]interface{} {
@@ -243,6 +580,12 @@ func BuildInputSchema(properties map[string]interface{}, required []string) map[
 	}
 }
 
+// BuildOutputSchema describes the object a tool returns as structured
+// content.
+func BuildOutputSchema(properties map[string]interface{}, required []string) map[string]interface{} {
+	return BuildInputSchema(properties, required)
+}
+
 func StringProperty(description string) map[string]interface{} {
 	return map[string]interface{}{
 		"type":        "string",
@@ -272,6 +615,23 @@ func ArrayProperty(itemType, description string) map[string]interface{} {
 	}
 }
 
+func NumberProperty(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "number",
+		"description": description,
+	}
+}
+
+// ObjectArrayProperty is an array of objects with the given properties,
+// for output schemas.
+func ObjectArrayProperty(description string, properties map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "array",
+		"description": description,
+		"items":       map[string]interface{}{"type": "object", "properties": properties},
+	}
+}
+
 func MapProperty(description string) map[string]interface{} {
 	return map[string]interface{}{
 		"type":                 "object",
@@ -279,3 +639,11 @@ func MapProperty(description string) map[string]interface{} {
 		"additionalProperties": map[string]interface{}{"type": "string"},
 	}
 }
+
+// ObjectProperty is an object whose values may be of any type.
+func ObjectProperty(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "object",
+		"description": description,
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"strings"
 	"testing"
 
@@ -152,6 +154,66 @@ func TestHandleToolsCall(t *testing.T) {
 	assert.Nil(t, resp.Error)
 }
 
+type testDetailedError struct{}
+
+func (testDetailedError) Error() string { return "path not allowed" }
+
+func (testDetailedError) ErrorDetails() map[string]interface{} {
+	return map[string]interface{}{"code": "path_not_allowed", "path": "/etc"}
+}
+
+func TestErrorResultDetails(t *testing.T) {
+	result := ErrorResult(fmt.Errorf("reading: %w", testDetailedError{}))
+	assert.True(t, result.IsError)
+	assert.Equal(t, "reading: path not allowed", result.Content[0].Text)
+	assert.Equal(t, map[string]interface{}{"code": "path_not_allowed", "path": "/etc"}, result.Meta["error"])
+
+	plain := ErrorResult(errors.New("boom"))
+	assert.Nil(t, plain.Meta)
+}
+
+func TestReportProgress(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(strings.NewReader(""), &output)
+	server.RegisterTool(&Tool{
+		Name: "slow",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			ReportProgress(ctx, 1, 2, "halfway")
+			return TextResult("done"), nil
+		},
+	})
+
+	server.handleRequest(context.Background(), &Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"slow","_meta":{"progressToken":"tok"}}`),
+	})
+
+	lines := strings.Split(strings.TrimSpace(output.String()), "\n")
+	require.Len(t, lines, 2)
+	var note Notification
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &note))
+	assert.Equal(t, "notifications/progress", note.Method)
+	assert.Equal(t, map[string]interface{}{
+		"progressToken": "tok",
+		"progress":      float64(1),
+		"total":         float64(2),
+		"message":       "halfway",
+	}, note.Params)
+
+	// Without a token the handler runs the same but nothing is sent.
+	output.Reset()
+	server.handleRequest(context.Background(), &Request{
+		JSONRPC: "2.0",
+		ID:      2,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"slow"}`),
+	})
+	assert.NotContains(t, output.String(), "notifications/progress")
+}
+
 func TestTextResult(t *testing.T) {
 	result := TextResult("test message")
 	assert.Len(t, result.Content, 1)
@@ -171,6 +233,13 @@ func TestJSONResult(t *testing.T) {
 	assert.Len(t, result.Content, 1)
 	assert.Contains(t, result.Content[0].Text, "key")
 	assert.Contains(t, result.Content[0].Text, "value")
+
+	structured, _ := json.Marshal(result.StructuredContent)
+	assert.JSONEq(t, `{"key":"value","num":42}`, string(structured))
+
+	list, err := JSONResult([]string{"a"})
+	require.NoError(t, err)
+	assert.Nil(t, list.StructuredContent, "structured content must be an object")
 }
 
 func TestErrorResult(t *testing.T) {
@@ -196,3 +265,119 @@ func TestBuildInputSchema(t *testing.T) {
 	assert.Contains(t, props, "name")
 	assert.Contains(t, props, "age")
 }
+
+func TestRegisterToolGroup(t *testing.T) {
+	server := NewServer("test-server", "1.0.0")
+	tools := []*Tool{{Name: "read_file"}, {Name: "git_status"}}
+
+	server.RegisterToolGroup("fs", tools[:1])
+	server.RegisterToolGroup("git", tools[1:])
+
+	server.mu.RLock()
+	defer server.mu.RUnlock()
+	assert.Contains(t, server.tools, "fs.read_file")
+	assert.Contains(t, server.tools, "git.status")
+	assert.NotContains(t, server.tools, "read_file")
+	assert.Equal(t, "read_file", tools[0].Name)
+}
+
+func TestUnregisterTool(t *testing.T) {
+	server := NewServer("test-server", "1.0.0")
+	server.RegisterTool(&Tool{Name: "test_tool"})
+
+	assert.True(t, server.UnregisterTool("test_tool"))
+	assert.False(t, server.UnregisterTool("test_tool"))
+
+	server.mu.RLock()
+	defer server.mu.RUnlock()
+	assert.NotContains(t, server.tools, "test_tool")
+}
+
+func TestNotifyToolsListChanged(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(strings.NewReader(""), &output)
+
+	server.NotifyToolsListChanged()
+
+	var notification map[string]interface{}
+	err := json.Unmarshal(output.Bytes(), &notification)
+	require.NoError(t, err)
+
+	assert.Equal(t, "2.0", notification["jsonrpc"])
+	assert.Equal(t, "notifications/tools/list_changed", notification["method"])
+	assert.NotContains(t, notification, "id")
+}
+
+func TestAfterCallRunsOutsideToolLock(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(strings.NewReader(""), &output)
+
+	updated := false
+	server.RegisterTool(&Tool{
+		Name: "reconfigure",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			server.AfterCall(func() {
+				server.Update(func() { updated = true })
+			})
+			return TextResult("ok"), nil
+		},
+	})
+
+	server.handleRequest(context.Background(), &Request{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  "tools/call",
+		Params:  json.RawMessage(`{"name":"reconfigure","arguments":{}}`),
+	})
+
+	assert.True(t, updated)
+	assert.Contains(t, output.String(), `"ok"`)
+}
+
+func TestToolAnnotations(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(strings.NewReader(""), &output)
+	server.RegisterTool(&Tool{Name: "remove", Annotations: Destructive().Idempotent()})
+	server.RegisterTool(&Tool{Name: "plain"})
+
+	server.handleRequest(context.Background(), &Request{JSONRPC: "2.0", ID: 1, Method: "tools/list"})
+
+	var resp struct {
+		Result struct {
+			Tools []map[string]interface{} `json:"tools"`
+		} `json:"result"`
+	}
+	require.NoError(t, json.Unmarshal(output.Bytes(), &resp))
+	annotations := map[string]interface{}{}
+	for _, tool := range resp.Result.Tools {
+		annotations[tool["name"].(string)] = tool["annotations"]
+	}
+	assert.Equal(t, map[string]interface{}{
+		"readOnlyHint":    false,
+		"destructiveHint": true,
+		"idempotentHint":  true,
+		"openWorldHint":   false,
+	}, annotations["remove"])
+	assert.Nil(t, annotations["plain"])
+
+	base := ReadOnly()
+	base.OpenWorld()
+	assert.False(t, *base.OpenWorldHint, "OpenWorld must not modify its receiver")
+}
+
+func TestPing(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(strings.NewReader(""), &output)
+
+	server.handleRequest(context.Background(), &Request{JSONRPC: "2.0", ID: 7, Method: "ping"})
+	assert.JSONEq(t, `{"jsonrpc":"2.0","id":7,"result":{}}`, output.String())
+
+	// Replies to the server's own pings get no response.
+	output.Reset()
+	server.handleRequest(context.Background(), &Request{JSONRPC: "2.0", ID: "ping-1"})
+	assert.Empty(t, output.String())
+}
@@ -0,0 +1,89 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// Session is one client of an HTTP transport. Tools keep per-client state,
+// such as session environment variables, in it with Value, so clients
+// sharing the process do not see each other's. Over stdio the process has a
+// single client and there is no Session.
+type Session struct {
+	id string
+
+	mu           sync.Mutex
+	values       map[interface{}]interface{}
+	capabilities map[string]json.RawMessage
+
+	// closeMu is separate so that Value's create may call OnClose.
+	closeMu sync.Mutex
+	onClose []func()
+	closed  bool
+}
+
+func newSession(id string) *Session {
+	return &Session{id: id, values: make(map[interface{}]interface{})}
+}
+
+// ID returns the transport's session ID.
+func (sess *Session) ID() string {
+	return sess.id
+}
+
+// Value returns the state stored under key, which should be an unexported
+// type of the caller's package, storing create() on first use. create must
+// not call Value.
+func (sess *Session) Value(key interface{}, create func() interface{}) interface{} {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	v, ok := sess.values[key]
+	if !ok {
+		v = create()
+		sess.values[key] = v
+	}
+	return v
+}
+
+// OnClose registers fn to run when the client disconnects or its session
+// expires, to release what it left behind. fn runs at once if that has
+// already happened.
+func (sess *Session) OnClose(fn func()) {
+	sess.closeMu.Lock()
+	if !sess.closed {
+		sess.onClose = append(sess.onClose, fn)
+		sess.closeMu.Unlock()
+		return
+	}
+	sess.closeMu.Unlock()
+	fn()
+}
+
+func (sess *Session) close() {
+	sess.closeMu.Lock()
+	if sess.closed {
+		sess.closeMu.Unlock()
+		return
+	}
+	sess.closed = true
+	hooks := sess.onClose
+	sess.onClose = nil
+	sess.closeMu.Unlock()
+	for _, fn := range hooks {
+		fn()
+	}
+}
+
+type sessionKey struct{}
+
+func withSession(ctx context.Context, sess *Session) context.Context {
+	return context.WithValue(ctx, sessionKey{}, sess)
+}
+
+// SessionFromContext returns the session of the client whose request is
+// handled under ctx, or nil over stdio.
+func SessionFromContext(ctx context.Context) *Session {
+	sess, _ := ctx.Value(sessionKey{}).(*Session)
+	return sess
+}
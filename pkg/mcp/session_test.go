@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type counterKey struct{}
+
+func TestSessionsAreIsolated(t *testing.T) {
+	server := NewServer("test-server", "1.0.0")
+	closed := make(chan string, 2)
+	server.RegisterTool(&Tool{
+		Name: "count",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			sess := SessionFromContext(ctx)
+			n := sess.Value(counterKey{}, func() interface{} {
+				sess.OnClose(func() { closed <- sess.ID() })
+				return new(int)
+			}).(*int)
+			*n++
+			return TextResult(fmt.Sprint("count=", *n)), nil
+		},
+	})
+	ts := httptest.NewServer(server.HTTPHandler())
+	defer ts.Close()
+
+	connect := func() *streamableClient {
+		c := &streamableClient{t: t, url: ts.URL + "/mcp"}
+		resp := c.do(http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{}}`, nil)
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		c.session = resp.Header.Get(sessionHeader)
+		require.NotEmpty(t, c.session)
+		return c
+	}
+	count := func(c *streamableClient) string {
+		resp := c.do(http.MethodPost, `{"jsonrpc":"2.0","id":2,"method":"tools/call","params":{"name":"count"}}`, nil)
+		defer resp.Body.Close()
+		_, data := streamEvent(t, bufio.NewReader(resp.Body))
+		return data
+	}
+
+	a, b := connect(), connect()
+	assert.Contains(t, count(a), "count=1")
+	assert.Contains(t, count(a), "count=2")
+	assert.Contains(t, count(b), "count=1")
+
+	resp := a.do(http.MethodDelete, "", nil)
+	resp.Body.Close()
+	assert.Equal(t, a.session, <-closed)
+	assert.Contains(t, count(b), "count=2")
+}
+
+func TestSessionFromContextOverStdio(t *testing.T) {
+	assert.Nil(t, SessionFromContext(context.Background()))
+
+	sess := newSession("s1")
+	sess.close()
+	ran := false
+	sess.OnClose(func() { ran = true })
+	assert.True(t, ran)
+}
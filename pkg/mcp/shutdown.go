@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// abortWait is how long Shutdown waits for handlers to return once their
+// context has been cancelled at the end of the grace period.
+const abortWait = time.Second
+
+// shutdownState tracks the requests being handled so Shutdown can drain
+// them.
+type shutdownState struct {
+	mu       sync.Mutex
+	closing  bool
+	inflight sync.WaitGroup
+	hooks    []func(ctx context.Context)
+	// aborted is cancelled when the grace period is over, cancelling the
+	// handlers still running.
+	aborted context.Context
+	abort   context.CancelFunc
+	// done is closed once Shutdown has finished; it ends Run, RunHTTP and
+	// the event streams.
+	done chan struct{}
+}
+
+func newShutdownState() shutdownState {
+	aborted, abort := context.WithCancel(context.Background())
+	return shutdownState{aborted: aborted, abort: abort, done: make(chan struct{})}
+}
+
+// OnShutdown registers fn to run during Shutdown, after in-flight requests
+// have been drained. fn should stop by the time ctx ends.
+func (s *Server) OnShutdown(fn func(ctx context.Context)) {
+	s.shutdown.mu.Lock()
+	defer s.shutdown.mu.Unlock()
+	s.shutdown.hooks = append(s.shutdown.hooks, fn)
+}
+
+// Shutdown stops accepting requests, waits until the ones in flight have
+// been answered or ctx ends, runs the OnShutdown hooks and then ends Run
+// and RunHTTP. Handlers still running when ctx ends are cancelled, and
+// Shutdown returns ctx's error. Calling it again waits for the first call.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.shutdown.mu.Lock()
+	if s.shutdown.closing {
+		s.shutdown.mu.Unlock()
+		<-s.shutdown.done
+		return nil
+	}
+	s.shutdown.closing = true
+	hooks := s.shutdown.hooks
+	s.shutdown.mu.Unlock()
+
+	drained := make(chan struct{})
+	go func() {
+		s.shutdown.inflight.Wait()
+		close(drained)
+	}()
+	var err error
+	select {
+	case <-drained:
+	case <-ctx.Done():
+		err = ctx.Err()
+		s.shutdown.abort()
+		select {
+		case <-drained:
+		case <-time.After(abortWait):
+		}
+	}
+
+	s.writeMu.Lock()
+	if f, ok := s.output.(interface{ Flush() error }); ok {
+		f.Flush()
+	}
+	s.writeMu.Unlock()
+
+	for _, hook := range hooks {
+		hook(ctx)
+	}
+	s.shutdown.abort()
+	close(s.shutdown.done)
+	return err
+}
+
+// begin counts a request as in flight, unless the server is shutting down.
+// The returned context is cancelled if the request outlives the grace
+// period.
+func (s *Server) begin(ctx context.Context) (context.Context, func(), bool) {
+	s.shutdown.mu.Lock()
+	defer s.shutdown.mu.Unlock()
+	if s.shutdown.closing {
+		return ctx, nil, false
+	}
+	s.shutdown.inflight.Add(1)
+	ctx, cancel := context.WithCancel(ctx)
+	stop := context.AfterFunc(s.shutdown.aborted, cancel)
+	return ctx, func() {
+		stop()
+		cancel()
+		s.shutdown.inflight.Done()
+	}, true
+}
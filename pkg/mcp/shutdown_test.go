@@ -0,0 +1,93 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShutdownDrainsRequests(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(strings.NewReader(""), &output)
+
+	started := make(chan struct{})
+	server.RegisterTool(&Tool{
+		Name: "slow",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			close(started)
+			time.Sleep(50 * time.Millisecond)
+			return TextResult("finished"), nil
+		},
+	})
+	var hookSaw string
+	server.OnShutdown(func(ctx context.Context) { hookSaw = output.String() })
+
+	params, _ := json.Marshal(map[string]interface{}{"name": "slow"})
+	go server.handleRequest(context.Background(), &Request{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: params})
+	<-started
+
+	require.NoError(t, server.Shutdown(context.Background()))
+	assert.Contains(t, hookSaw, `"finished"`, "hooks run after in-flight calls are answered")
+
+	resp := call(t, server, &output, "tools/list", nil)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, -32000, resp.Error.Code)
+
+	assert.NoError(t, server.Shutdown(context.Background()), "a second call waits for the first")
+}
+
+func TestShutdownCancelsAfterGracePeriod(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(strings.NewReader(""), &output)
+
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	server.RegisterTool(&Tool{
+		Name: "stuck",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			close(started)
+			<-ctx.Done()
+			close(cancelled)
+			return nil, ctx.Err()
+		},
+	})
+
+	params, _ := json.Marshal(map[string]interface{}{"name": "stuck"})
+	go server.handleRequest(context.Background(), &Request{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: params})
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, server.Shutdown(ctx), context.DeadlineExceeded)
+	select {
+	case <-cancelled:
+	default:
+		t.Fatal("handler was not cancelled")
+	}
+}
+
+func TestRunEndsOnShutdown(t *testing.T) {
+	input, _ := io.Pipe()
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(input, io.Discard)
+
+	errc := make(chan error, 1)
+	go func() { errc <- server.Run(context.Background()) }()
+	require.NoError(t, server.Shutdown(context.Background()))
+
+	select {
+	case err := <-errc:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Run did not return while its input was still open")
+	}
+}
@@ -0,0 +1,159 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// sseKeepAlive is how often an idle event stream gets a comment, so that
+// proxies do not close it.
+const sseKeepAlive = 30 * time.Second
+
+// sseTransport implements the HTTP+SSE transport: a client opens an event
+// stream with GET /sse, is told the endpoint to POST its messages to, and
+// receives every response and notification on the stream.
+type sseTransport struct {
+	server   *Server
+	mu       sync.RWMutex
+	sessions map[string]*sseSession
+}
+
+type sseSession struct {
+	pinger
+	messages chan []byte
+	// ctx ends when the client closes the event stream, cancelling the
+	// requests still running for it.
+	ctx context.Context
+}
+
+func (sess *sseSession) send(data []byte) {
+	select {
+	case sess.messages <- data:
+	case <-sess.ctx.Done():
+	}
+}
+
+func newSSETransport(s *Server) *sseTransport {
+	return &sseTransport{server: s, sessions: make(map[string]*sseSession)}
+}
+
+func newSessionID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func (t *sseTransport) broadcast(data []byte) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	for _, sess := range t.sessions {
+		sess.send(data)
+	}
+}
+
+func (t *sseTransport) handleStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	id := newSessionID()
+	session := newSession(id)
+	sess := &sseSession{messages: make(chan []byte, 64), ctx: withSession(r.Context(), session)}
+	t.mu.Lock()
+	t.sessions[id] = sess
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.sessions, id)
+		t.mu.Unlock()
+		session.close()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprintf(w, "event: endpoint\ndata: /message?sessionId=%s\n\n", id)
+	flusher.Flush()
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	pings, stopPings := t.server.pingTicker()
+	defer stopPings()
+	for {
+		select {
+		case data := <-sess.messages:
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+		case <-pings:
+			ping, ok := sess.next()
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "event: message\ndata: %s\n\n", ping)
+		case <-r.Context().Done():
+			return
+		case <-t.server.shutdown.done:
+			// Deliver what was queued before the server shut down.
+			for {
+				select {
+				case data := <-sess.messages:
+					fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+				default:
+					flusher.Flush()
+					return
+				}
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func (t *sseTransport) handleMessage(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	t.mu.RLock()
+	sess, ok := t.sessions[r.URL.Query().Get("sessionId")]
+	t.mu.RUnlock()
+	if !ok {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	var req Request
+	if err := json.Unmarshal(body, &req); err != nil {
+		http.Error(w, "parse error: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// The reply goes out on the event stream, so a slow tool call does
+	// not hold the POST open.
+	w.WriteHeader(http.StatusAccepted)
+	if sess.answered(&req) {
+		return
+	}
+	go t.server.handleRequest(withReply(sess.ctx, sess.send), &req)
+}
@@ -0,0 +1,164 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sseEvent reads the next event from an event stream, skipping comments.
+func sseEvent(t *testing.T, r *bufio.Reader) (event, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && event != "":
+			return event, data
+		}
+	}
+}
+
+func TestSSETransport(t *testing.T) {
+	server := NewServer("test-server", "1.0.0")
+	server.RegisterTool(&Tool{
+		Name: "echo",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			ReportProgress(ctx, 1, 2, "halfway")
+			return TextResult(params["text"].(string)), nil
+		},
+	})
+	ts := httptest.NewServer(server.HTTPHandler())
+	defer ts.Close()
+
+	stream, err := http.Get(ts.URL + "/sse")
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	assert.Equal(t, "text/event-stream", stream.Header.Get("Content-Type"))
+	events := bufio.NewReader(stream.Body)
+
+	event, endpoint := sseEvent(t, events)
+	require.Equal(t, "endpoint", event)
+	require.True(t, strings.HasPrefix(endpoint, "/message?sessionId="))
+
+	post := func(body string) *http.Response {
+		resp, err := http.Post(ts.URL+endpoint, "application/json", strings.NewReader(body))
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+
+	assert.Equal(t, http.StatusAccepted, post(`{"jsonrpc":"2.0","id":1,"method":"tools/call",
+		"params":{"name":"echo","arguments":{"text":"hi"},"_meta":{"progressToken":"p1"}}}`).StatusCode)
+
+	_, data := sseEvent(t, events)
+	var progress Notification
+	require.NoError(t, json.Unmarshal([]byte(data), &progress))
+	assert.Equal(t, "notifications/progress", progress.Method)
+
+	_, data = sseEvent(t, events)
+	var resp Response
+	require.NoError(t, json.Unmarshal([]byte(data), &resp))
+	assert.Equal(t, float64(1), resp.ID)
+	assert.Contains(t, data, `"hi"`)
+
+	server.NotifyToolsListChanged()
+	_, data = sseEvent(t, events)
+	assert.Contains(t, data, "notifications/tools/list_changed")
+
+	assert.Equal(t, http.StatusBadRequest, post(`{not json`).StatusCode)
+
+	resp2, err := http.Post(ts.URL+"/message?sessionId=nope", "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp2.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp2.StatusCode)
+}
+
+func TestHTTPRejectsForeignOrigin(t *testing.T) {
+	ts := httptest.NewServer(NewServer("test-server", "1.0.0").HTTPHandler())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/sse", nil)
+	req.Header.Set("Origin", "http://evil.example")
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func TestHTTPRejectsReboundHost(t *testing.T) {
+	server := NewServer("test-server", "1.0.0")
+	server.SetAllowedHosts([]string{"dev.internal"})
+	ts := httptest.NewServer(server.HTTPHandler())
+	defer ts.Close()
+	_, port, _ := net.SplitHostPort(strings.TrimPrefix(ts.URL, "http://"))
+
+	post := func(host, origin string) int {
+		req, _ := http.NewRequest(http.MethodPost, ts.URL+"/mcp", strings.NewReader(
+			`{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`))
+		req.Host = host
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	// After DNS rebinding, Host and Origin both name the attacker's domain.
+	assert.Equal(t, http.StatusForbidden, post("evil.example:"+port, "http://evil.example:"+port))
+	assert.Equal(t, http.StatusForbidden, post("evil.example:"+port, ""))
+	assert.Equal(t, http.StatusForbidden, post("localhost:"+port, "http://evil.example:"+port))
+	assert.Equal(t, http.StatusOK, post("localhost:"+port, "http://localhost:"+port))
+	assert.Equal(t, http.StatusOK, post("[::1]:"+port, ""))
+	assert.Equal(t, http.StatusOK, post("dev.internal:"+port, "http://dev.internal:"+port))
+}
+
+func TestSSEPing(t *testing.T) {
+	server := NewServer("test-server", "1.0.0")
+	server.SetPingInterval(20 * time.Millisecond)
+	ts := httptest.NewServer(server.HTTPHandler())
+	defer ts.Close()
+
+	stream, err := http.Get(ts.URL + "/sse")
+	require.NoError(t, err)
+	defer stream.Body.Close()
+	events := bufio.NewReader(stream.Body)
+	_, endpoint := sseEvent(t, events)
+
+	// Answering a ping keeps the stream open past maxMissedPings pings.
+	for i := 0; i < maxMissedPings+2; i++ {
+		_, data := sseEvent(t, events)
+		var ping Request
+		require.NoError(t, json.Unmarshal([]byte(data), &ping))
+		require.Equal(t, "ping", ping.Method)
+		reply, _ := json.Marshal(Response{JSONRPC: "2.0", ID: ping.ID, Result: map[string]interface{}{}})
+		resp, err := http.Post(ts.URL+endpoint, "application/json", bytes.NewReader(reply))
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	// Without replies the server gives up on the client.
+	rest, err := io.ReadAll(events)
+	require.NoError(t, err)
+	assert.Equal(t, maxMissedPings, strings.Count(string(rest), `"method":"ping"`))
+}
@@ -0,0 +1,523 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// sessionHeader carries the session ID of the Streamable HTTP transport.
+	sessionHeader = "Mcp-Session-Id"
+	// sessionIdleTimeout is how long a session without requests or an open
+	// stream is kept before it is dropped.
+	sessionIdleTimeout = time.Hour
+	// maxStreamEvents bounds the events a stream keeps for resumption.
+	maxStreamEvents = 1000
+	// maxFinishedStreams is how many completed response streams a session
+	// keeps so that a client can resume one it lost.
+	maxFinishedStreams = 32
+)
+
+// streamableTransport implements the Streamable HTTP transport of protocol
+// revision 2025-03-26: clients POST messages to a single endpoint and get
+// the replies either as a JSON body or as an event stream, and may GET the
+// endpoint to receive notifications outside of a request.
+type streamableTransport struct {
+	server   *Server
+	mu       sync.Mutex
+	sessions map[string]*streamSession
+}
+
+type streamSession struct {
+	pinger
+	id      string
+	session *Session
+	// ctx ends when the session is deleted, cancelling its requests. It
+	// does not end with the HTTP request that started them, so a client
+	// can reconnect and resume a stream.
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu         sync.Mutex
+	streams    map[int]*eventStream
+	finished   []int
+	nextStream int
+	lastUsed   time.Time
+	// listener is closed to end the GET stream when a newer one replaces it.
+	listener chan struct{}
+}
+
+// eventStream is the sequence of messages sent on one event stream. Stream
+// 0 of a session is the standalone stream for notifications; the others
+// each carry the replies to one POST.
+type eventStream struct {
+	mu     sync.Mutex
+	events [][]byte
+	first  int // sequence number of events[0]
+	closed bool
+	wake   chan struct{}
+}
+
+func newEventStream() *eventStream {
+	return &eventStream{wake: make(chan struct{})}
+}
+
+func (es *eventStream) push(data []byte) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	es.events = append(es.events, data)
+	if len(es.events) > maxStreamEvents {
+		es.events = es.events[1:]
+		es.first++
+	}
+	close(es.wake)
+	es.wake = make(chan struct{})
+}
+
+func (es *eventStream) close() {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	if !es.closed {
+		es.closed = true
+		close(es.wake)
+	}
+}
+
+// end returns the sequence number the next event will get.
+func (es *eventStream) end() int {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	return es.first + len(es.events)
+}
+
+// since returns the events from sequence number from on, the number of the
+// first one returned, and a channel closed on the next change.
+func (es *eventStream) since(from int) (events [][]byte, start int, closed bool, wake <-chan struct{}) {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+	start = from
+	if start < es.first {
+		start = es.first
+	}
+	if i := start - es.first; i < len(es.events) {
+		events = append(events, es.events[i:]...)
+	}
+	return events, start, es.closed, es.wake
+}
+
+func newStreamableTransport(s *Server) *streamableTransport {
+	return &streamableTransport{server: s, sessions: make(map[string]*streamSession)}
+}
+
+func (t *streamableTransport) broadcast(data []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, sess := range t.sessions {
+		sess.stream(0).push(data)
+	}
+}
+
+func (t *streamableTransport) newSession() *streamSession {
+	id := newSessionID()
+	session := newSession(id)
+	ctx, cancel := context.WithCancel(withSession(context.Background(), session))
+	sess := &streamSession{
+		id:         id,
+		session:    session,
+		ctx:        ctx,
+		cancel:     cancel,
+		streams:    map[int]*eventStream{0: newEventStream()},
+		nextStream: 1,
+		lastUsed:   time.Now(),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, old := range t.sessions {
+		if old.idle() > sessionIdleTimeout {
+			old.close()
+			delete(t.sessions, id)
+		}
+	}
+	t.sessions[sess.id] = sess
+	return sess
+}
+
+// session looks up the session a request names, writing the error
+// response if there is none.
+func (t *streamableTransport) session(w http.ResponseWriter, r *http.Request) *streamSession {
+	id := r.Header.Get(sessionHeader)
+	if id == "" {
+		http.Error(w, "missing "+sessionHeader+" header", http.StatusBadRequest)
+		return nil
+	}
+	t.mu.Lock()
+	sess, ok := t.sessions[id]
+	t.mu.Unlock()
+	if !ok {
+		// 404 tells the client to start a new session.
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return nil
+	}
+	sess.touch()
+	return sess
+}
+
+func (sess *streamSession) touch() {
+	sess.mu.Lock()
+	sess.lastUsed = time.Now()
+	sess.mu.Unlock()
+}
+
+func (sess *streamSession) idle() time.Duration {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return time.Since(sess.lastUsed)
+}
+
+func (sess *streamSession) stream(id int) *eventStream {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	return sess.streams[id]
+}
+
+func (sess *streamSession) openStream() (int, *eventStream) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	id := sess.nextStream
+	sess.nextStream++
+	sess.streams[id] = newEventStream()
+	return id, sess.streams[id]
+}
+
+// finishStream closes a response stream, keeping it for resumption until
+// maxFinishedStreams newer ones have finished.
+func (sess *streamSession) finishStream(id int) {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	sess.streams[id].close()
+	sess.finished = append(sess.finished, id)
+	if len(sess.finished) > maxFinishedStreams {
+		delete(sess.streams, sess.finished[0])
+		sess.finished = sess.finished[1:]
+	}
+}
+
+// listen registers a GET on the standalone stream, returning a channel
+// that is closed when a newer GET takes over.
+func (sess *streamSession) listen() <-chan struct{} {
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	if sess.listener != nil {
+		close(sess.listener)
+	}
+	sess.listener = make(chan struct{})
+	return sess.listener
+}
+
+func (sess *streamSession) close() {
+	sess.cancel()
+	sess.session.close()
+	sess.mu.Lock()
+	defer sess.mu.Unlock()
+	for _, es := range sess.streams {
+		es.close()
+	}
+}
+
+func (t *streamableTransport) handle(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		t.handlePost(w, r)
+	case http.MethodGet:
+		t.handleGet(w, r)
+	case http.MethodDelete:
+		if sess := t.session(w, r); sess != nil {
+			t.mu.Lock()
+			delete(t.sessions, sess.id)
+			t.mu.Unlock()
+			sess.close()
+			w.WriteHeader(http.StatusOK)
+		}
+	default:
+		w.Header().Set("Allow", "GET, POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (t *streamableTransport) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+	messages, batch, err := parseMessages(body)
+	if err != nil {
+		writeRPCError(w, http.StatusBadRequest, -32700, "Parse error", err.Error())
+		return
+	}
+
+	var sess *streamSession
+	var requests []*Request
+	for _, msg := range messages {
+		if msg.Method == "initialize" && len(messages) > 1 {
+			writeRPCError(w, http.StatusBadRequest, -32600, "Invalid Request", "initialize must be sent on its own")
+			return
+		}
+		if msg.Method != "" && msg.ID != nil {
+			requests = append(requests, msg)
+		}
+	}
+	if len(requests) == 1 && requests[0].Method == "initialize" {
+		sess = t.newSession()
+	} else if sess = t.session(w, r); sess == nil {
+		return
+	}
+
+	standalone := sess.stream(0)
+	for _, msg := range messages {
+		// Notifications and responses are handled here.
+		if sess.answered(msg) {
+			continue
+		}
+		if msg.Method == "" || msg.ID == nil {
+			t.server.handleRequest(withReply(sess.ctx, standalone.push), msg)
+		}
+	}
+	if len(requests) == 0 {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	w.Header().Set(sessionHeader, sess.id)
+	if acceptsEventStream(r) && hasToolCall(requests) {
+		t.streamReplies(w, r, sess, requests)
+		return
+	}
+
+	// Short requests are answered with a JSON body; any notifications
+	// they send go to the standalone stream.
+	replies := make([]json.RawMessage, len(requests))
+	var wg sync.WaitGroup
+	for i, req := range requests {
+		wg.Add(1)
+		go func(i int, req *Request) {
+			defer wg.Done()
+			t.server.handleRequest(withReply(sess.ctx, func(data []byte) {
+				if isNotification(data) {
+					standalone.push(data)
+				} else {
+					replies[i] = data
+				}
+			}), req)
+		}(i, req)
+	}
+	wg.Wait()
+
+	w.Header().Set("Content-Type", "application/json")
+	if batch {
+		json.NewEncoder(w).Encode(replies)
+	} else {
+		w.Write(append(replies[0], '\n'))
+	}
+}
+
+// streamReplies answers requests on a new event stream of the session,
+// which also carries the progress notifications of their tool calls.
+func (t *streamableTransport) streamReplies(w http.ResponseWriter, r *http.Request, sess *streamSession, requests []*Request) {
+	if _, ok := w.(http.Flusher); !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	id, stream := sess.openStream()
+	var wg sync.WaitGroup
+	for _, req := range requests {
+		wg.Add(1)
+		go func(req *Request) {
+			defer wg.Done()
+			t.server.handleRequest(withReply(sess.ctx, stream.push), req)
+		}(req)
+	}
+	go func() {
+		wg.Wait()
+		sess.finishStream(id)
+	}()
+
+	startEventStream(w)
+	t.serveStream(w, r, sess, id, stream, 0, nil)
+}
+
+func (t *streamableTransport) handleGet(w http.ResponseWriter, r *http.Request) {
+	if !acceptsEventStream(r) {
+		http.Error(w, "GET must accept text/event-stream", http.StatusNotAcceptable)
+		return
+	}
+	if _, ok := w.(http.Flusher); !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+	sess := t.session(w, r)
+	if sess == nil {
+		return
+	}
+
+	id, from := 0, -1
+	if last := r.Header.Get("Last-Event-ID"); last != "" {
+		var ok bool
+		if id, from, ok = parseEventID(last); !ok {
+			http.Error(w, "invalid Last-Event-ID", http.StatusBadRequest)
+			return
+		}
+		from++
+	}
+	stream := sess.stream(id)
+	if stream == nil {
+		http.Error(w, "stream is no longer available", http.StatusNotFound)
+		return
+	}
+	if from < 0 {
+		from = stream.end()
+	}
+	var stop <-chan struct{}
+	if id == 0 {
+		stop = sess.listen()
+	}
+
+	startEventStream(w)
+	t.serveStream(w, r, sess, id, stream, from, stop)
+}
+
+func startEventStream(w http.ResponseWriter) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	w.(http.Flusher).Flush()
+}
+
+// serveStream writes the events of stream from sequence number from on,
+// until the stream is closed, the client goes away or the server shuts
+// down. Event IDs are "<stream>-<sequence>", which is what Last-Event-ID
+// resumes from. The standalone stream also carries pings, which have no ID
+// as they are not replayed.
+func (t *streamableTransport) serveStream(w http.ResponseWriter, r *http.Request, sess *streamSession, id int, stream *eventStream, from int, stop <-chan struct{}) {
+	flusher := w.(http.Flusher)
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+	var pings <-chan time.Time
+	if id == 0 {
+		var stopPings func()
+		pings, stopPings = t.server.pingTicker()
+		defer stopPings()
+	}
+	stopping := false
+	for {
+		events, start, closed, wake := stream.since(from)
+		for i, data := range events {
+			fmt.Fprintf(w, "id: %d-%d\ndata: %s\n\n", id, start+i, data)
+		}
+		from = start + len(events)
+		flusher.Flush()
+		if closed || stopping {
+			return
+		}
+
+		select {
+		case <-wake:
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keepalive\n\n")
+			sess.touch()
+		case <-pings:
+			ping, ok := sess.next()
+			if !ok {
+				return
+			}
+			fmt.Fprintf(w, "data: %s\n\n", ping)
+			flusher.Flush()
+		case <-stop:
+			return
+		case <-r.Context().Done():
+			return
+		case <-t.server.shutdown.done:
+			// Write the events pushed before the server shut down.
+			stopping = true
+		}
+	}
+}
+
+// parseMessages decodes a POST body holding one message or a batch.
+func parseMessages(body []byte) (messages []*Request, batch bool, err error) {
+	body = bytes.TrimSpace(body)
+	if len(body) > 0 && body[0] == '[' {
+		if err := json.Unmarshal(body, &messages); err != nil {
+			return nil, true, err
+		}
+		if len(messages) == 0 {
+			return nil, true, fmt.Errorf("empty batch")
+		}
+		for _, msg := range messages {
+			if msg == nil {
+				return nil, true, fmt.Errorf("null message in batch")
+			}
+		}
+		return messages, true, nil
+	}
+	var msg Request
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, false, err
+	}
+	return []*Request{&msg}, false, nil
+}
+
+func parseEventID(s string) (stream, seq int, ok bool) {
+	a, b, found := strings.Cut(s, "-")
+	if !found {
+		return 0, 0, false
+	}
+	stream, err1 := strconv.Atoi(a)
+	seq, err2 := strconv.Atoi(b)
+	return stream, seq, err1 == nil && err2 == nil && stream >= 0 && seq >= 0
+}
+
+func acceptsEventStream(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		if strings.Contains(accept, "text/event-stream") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasToolCall(requests []*Request) bool {
+	for _, req := range requests {
+		if req.Method == "tools/call" {
+			return true
+		}
+	}
+	return false
+}
+
+func isNotification(data []byte) bool {
+	var msg struct {
+		Method string `json:"method"`
+	}
+	json.Unmarshal(data, &msg)
+	return msg.Method != ""
+}
+
+func writeRPCError(w http.ResponseWriter, status, code int, message string, data interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(Response{
+		JSONRPC: "2.0",
+		Error:   &RPCError{Code: code, Message: message, Data: data},
+	})
+}
@@ -0,0 +1,180 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamEvent reads the next event with data from an event stream.
+func streamEvent(t *testing.T, r *bufio.Reader) (id, data string) {
+	t.Helper()
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimRight(line, "\n")
+		switch {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			data = strings.TrimPrefix(line, "data: ")
+		case line == "" && data != "":
+			return id, data
+		}
+	}
+}
+
+type streamableClient struct {
+	t       *testing.T
+	url     string
+	session string
+}
+
+func (c *streamableClient) do(method, body string, header map[string]string) *http.Response {
+	c.t.Helper()
+	req, err := http.NewRequest(method, c.url, strings.NewReader(body))
+	require.NoError(c.t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json, text/event-stream")
+	if c.session != "" {
+		req.Header.Set(sessionHeader, c.session)
+	}
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(c.t, err)
+	return resp
+}
+
+func newStreamableClient(t *testing.T) (*Server, *streamableClient) {
+	server := NewServer("test-server", "1.0.0")
+	server.RegisterTool(&Tool{
+		Name: "echo",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			ReportProgress(ctx, 1, 2, "halfway")
+			return TextResult(params["text"].(string)), nil
+		},
+	})
+	ts := httptest.NewServer(server.HTTPHandler())
+	t.Cleanup(ts.Close)
+	c := &streamableClient{t: t, url: ts.URL + "/mcp"}
+
+	resp := c.do(http.MethodPost, `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-03-26"}}`, nil)
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Contains(t, string(body), `"protocolVersion":"2025-03-26"`)
+	c.session = resp.Header.Get(sessionHeader)
+	require.NotEmpty(t, c.session)
+	return server, c
+}
+
+func TestStreamableHTTP(t *testing.T) {
+	server, c := newStreamableClient(t)
+
+	resp := c.do(http.MethodPost, `{"jsonrpc":"2.0","method":"notifications/initialized"}`, nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	// Requests without a tool call are answered with JSON, batches in kind.
+	resp = c.do(http.MethodPost, `[{"jsonrpc":"2.0","id":2,"method":"tools/list"},{"jsonrpc":"2.0","id":3,"method":"bogus"}]`, nil)
+	assert.Equal(t, "application/json", resp.Header.Get("Content-Type"))
+	var replies []Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&replies))
+	resp.Body.Close()
+	require.Len(t, replies, 2)
+	assert.Equal(t, float64(2), replies[0].ID)
+	assert.Equal(t, -32601, replies[1].Error.Code)
+
+	// A tool call streams its progress and then the response.
+	resp = c.do(http.MethodPost, `{"jsonrpc":"2.0","id":4,"method":"tools/call",
+		"params":{"name":"echo","arguments":{"text":"hi"},"_meta":{"progressToken":"p1"}}}`, nil)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	events := bufio.NewReader(resp.Body)
+	id, data := streamEvent(t, events)
+	assert.Equal(t, "1-0", id)
+	assert.Contains(t, data, "notifications/progress")
+	id, data = streamEvent(t, events)
+	assert.Equal(t, "1-1", id)
+	assert.Contains(t, data, `"hi"`)
+	_, err := events.ReadString('\n')
+	assert.ErrorIs(t, err, io.EOF, "stream should end after the response")
+	resp.Body.Close()
+
+	// A lost stream can be resumed after the last event seen.
+	resp = c.do(http.MethodGet, "", map[string]string{"Last-Event-ID": "1-0"})
+	_, data = streamEvent(t, bufio.NewReader(resp.Body))
+	assert.Contains(t, data, `"hi"`)
+	resp.Body.Close()
+
+	// Notifications outside a request go to the GET stream.
+	resp = c.do(http.MethodGet, "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	server.NotifyToolsListChanged()
+	_, data = streamEvent(t, bufio.NewReader(resp.Body))
+	assert.Contains(t, data, "notifications/tools/list_changed")
+	resp.Body.Close()
+
+	resp = c.do(http.MethodDelete, "", nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	resp = c.do(http.MethodPost, `{"jsonrpc":"2.0","id":5,"method":"tools/list"}`, nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestStreamableHTTPErrors(t *testing.T) {
+	_, c := newStreamableClient(t)
+
+	resp := c.do(http.MethodPost, `{not json`, nil)
+	var reply Response
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&reply))
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	assert.Equal(t, -32700, reply.Error.Code)
+
+	resp = c.do(http.MethodGet, "", map[string]string{"Accept": "application/json"})
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotAcceptable, resp.StatusCode)
+
+	resp = c.do(http.MethodGet, "", map[string]string{"Last-Event-ID": "9-0"})
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+
+	c.session = ""
+	resp = c.do(http.MethodPost, `{"jsonrpc":"2.0","id":2,"method":"tools/list"}`, nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = c.do(http.MethodPost, `[{"jsonrpc":"2.0","id":1,"method":"initialize"},{"jsonrpc":"2.0","id":2,"method":"tools/list"}]`, nil)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+func TestStreamableHTTPPing(t *testing.T) {
+	server, c := newStreamableClient(t)
+	server.SetPingInterval(20 * time.Millisecond)
+
+	resp := c.do(http.MethodGet, "", nil)
+	defer resp.Body.Close()
+	id, data := streamEvent(t, bufio.NewReader(resp.Body))
+	assert.Empty(t, id, "pings are not replayable")
+	var ping Request
+	require.NoError(t, json.Unmarshal([]byte(data), &ping))
+	assert.Equal(t, "ping", ping.Method)
+
+	reply, _ := json.Marshal(Response{JSONRPC: "2.0", ID: ping.ID, Result: map[string]interface{}{}})
+	post := c.do(http.MethodPost, string(reply), nil)
+	post.Body.Close()
+	assert.Equal(t, http.StatusAccepted, post.StatusCode)
+}
@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ToolTimeoutError is the error result of a tool call that ran past its
+// deadline.
+type ToolTimeoutError struct {
+	Tool    string
+	Timeout time.Duration
+}
+
+func (e *ToolTimeoutError) Error() string {
+	return fmt.Sprintf("tool %s timed out after %s", e.Tool, e.Timeout)
+}
+
+func (e *ToolTimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+func (e *ToolTimeoutError) ErrorDetails() map[string]interface{} {
+	return map[string]interface{}{
+		"code":            "timeout",
+		"tool":            e.Tool,
+		"timeout_seconds": e.Timeout.Seconds(),
+		"remediation":     "narrow the request, or raise global.tool_timeouts for this tool",
+	}
+}
+
+// SetToolTimeout sets the function giving each tool's deadline. It is called
+// while Update is locked out, so it may read configuration that Update swaps.
+// A zero or negative timeout means no deadline.
+func (s *Server) SetToolTimeout(timeout func(tool string) time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolTimeout = timeout
+}
+
+type toolOutcome struct {
+	result *ToolResult
+	err    error
+}
+
+// callTool runs tool under its deadline. If the deadline passes first it
+// returns a timeout error without waiting for the handler, and detached is
+// true: release then runs once the handler does return, so it keeps Update
+// locked out until it stops. Otherwise release is left to the caller.
+func (s *Server) callTool(ctx context.Context, tool *Tool, args map[string]interface{}, release func()) (result *ToolResult, detached bool, err error) {
+	s.mu.RLock()
+	timeoutFn := s.toolTimeout
+	s.mu.RUnlock()
+
+	var timeout time.Duration
+	if timeoutFn != nil {
+		timeout = timeoutFn(tool.Name)
+	}
+	if timeout <= 0 {
+		result, err = tool.Handler(ctx, args)
+		return result, false, err
+	}
+
+	callCtx, cancel := context.WithTimeout(ctx, timeout)
+	done := make(chan toolOutcome, 1)
+	go func() {
+		result, err := tool.Handler(callCtx, args)
+		done <- toolOutcome{result, err}
+	}()
+
+	select {
+	case out := <-done:
+		expired := errors.Is(callCtx.Err(), context.DeadlineExceeded) && ctx.Err() == nil
+		cancel()
+		if expired && errors.Is(out.err, context.DeadlineExceeded) {
+			return nil, false, &ToolTimeoutError{Tool: tool.Name, Timeout: timeout}
+		}
+		return out.result, false, out.err
+	case <-callCtx.Done():
+		if ctx.Err() != nil {
+			// The request itself was cancelled; let the handler wind down
+			// and report whatever it returns.
+			out := <-done
+			cancel()
+			return out.result, false, out.err
+		}
+		go func() {
+			<-done
+			cancel()
+			release()
+		}()
+		return nil, true, &ToolTimeoutError{Tool: tool.Name, Timeout: timeout}
+	}
+}
@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolTimeout(t *testing.T) {
+	var output bytes.Buffer
+	server := NewServer("test-server", "1.0.0")
+	server.SetIO(strings.NewReader(""), &output)
+
+	unblock := make(chan struct{})
+	server.RegisterTool(&Tool{
+		Name: "hang",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			<-unblock
+			return TextResult("too late"), nil
+		},
+	})
+	server.RegisterTool(&Tool{
+		Name: "wait",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	})
+	server.RegisterTool(&Tool{
+		Name: "quick",
+		Handler: func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+			return TextResult("done"), nil
+		},
+	})
+	server.SetToolTimeout(func(tool string) time.Duration {
+		if tool == "quick" {
+			return 0
+		}
+		return 20 * time.Millisecond
+	})
+
+	for _, name := range []string{"hang", "wait"} {
+		call(t, server, &output, "tools/call", map[string]interface{}{"name": name})
+		assert.Contains(t, output.String(), `"isError":true`, name)
+		assert.Contains(t, output.String(), `"code":"timeout"`, name)
+		assert.Contains(t, output.String(), `"tool":"`+name+`"`)
+	}
+
+	resp := call(t, server, &output, "tools/call", map[string]interface{}{"name": "quick"})
+	require.Nil(t, resp.Error)
+	assert.Contains(t, output.String(), `"done"`)
+
+	// The abandoned handler still holds off Update until it returns.
+	updated := make(chan struct{})
+	go server.Update(func() { close(updated) })
+	select {
+	case <-updated:
+		t.Fatal("Update ran while a timed-out handler was still running")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(unblock)
+	select {
+	case <-updated:
+	case <-time.After(time.Second):
+		t.Fatal("Update did not run after the handler returned")
+	}
+}
@@ -0,0 +1,186 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// TypedHandler adapts fn, which takes the tool's arguments as a struct, to a
+// ToolHandler. The arguments are decoded with DecodeParams.
+func TypedHandler[T any](fn func(ctx context.Context, args T) (*ToolResult, error)) ToolHandler {
+	return func(ctx context.Context, params map[string]interface{}) (*ToolResult, error) {
+		args, err := DecodeParams[T](params)
+		if err != nil {
+			return nil, err
+		}
+		return fn(ctx, args)
+	}
+}
+
+// DecodeParams decodes tool arguments into the struct T. Fields are matched
+// by their json tag, and may carry:
+//
+//	default:"10"                  value used when the argument is omitted or null
+//	validate:"required"           the argument must be given
+//	validate:"min=1,max=100"      bounds on a number, or on a string's or list's length
+//	validate:"oneof=text json"    allowed values
+//
+// The fields of an embedded struct are decoded as if they were T's own, so
+// tools can share arguments they have in common. Errors read like those of
+// GetStringParam and friends.
+func DecodeParams[T any](params map[string]interface{}) (T, error) {
+	var args T
+	v := reflect.ValueOf(&args).Elem()
+	if v.Kind() != reflect.Struct {
+		return args, fmt.Errorf("arguments type %s is not a struct", v.Type())
+	}
+	return args, decodeStruct(params, v)
+}
+
+func decodeStruct(params map[string]interface{}, v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := decodeStruct(params, v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+		name := paramName(field)
+		if name == "" {
+			continue
+		}
+		rules := parseRules(field.Tag.Get("validate"))
+		target := v.Field(i).Addr().Interface()
+
+		raw, ok := params[name]
+		if !ok || raw == nil {
+			if _, required := rules["required"]; required {
+				return paramError(name, "missing required parameter: %s", name)
+			}
+			if def, ok := field.Tag.Lookup("default"); ok {
+				if err := decodeDefault(def, v.Field(i)); err != nil {
+					return fmt.Errorf("parameter %s: bad default %q: %w", name, def, err)
+				}
+			}
+			continue
+		}
+
+		data, err := json.Marshal(raw)
+		if err != nil {
+			return fmt.Errorf("parameter %s: %w", name, err)
+		}
+		if err := json.Unmarshal(data, target); err != nil {
+			return paramError(name, "parameter %s must be %s", name, typeWord(field.Type))
+		}
+		if err := checkRules(name, rules, v.Field(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func paramName(field reflect.StructField) string {
+	if !field.IsExported() {
+		return ""
+	}
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	}
+	return name
+}
+
+func parseRules(tag string) map[string]string {
+	rules := make(map[string]string)
+	for _, rule := range strings.Split(tag, ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			key, value, _ := strings.Cut(rule, "=")
+			rules[key] = value
+		}
+	}
+	return rules
+}
+
+func decodeDefault(def string, v reflect.Value) error {
+	if v.Kind() == reflect.String {
+		v.SetString(def)
+		return nil
+	}
+	return json.Unmarshal([]byte(def), v.Addr().Interface())
+}
+
+func checkRules(name string, rules map[string]string, v reflect.Value) error {
+	if oneof, ok := rules["oneof"]; ok {
+		allowed := strings.Fields(oneof)
+		value := fmt.Sprint(v.Interface())
+		found := false
+		for _, a := range allowed {
+			if a == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return paramError(name, "parameter %s must be one of %s", name, strings.Join(allowed, ", "))
+		}
+	}
+
+	var size float64
+	what := ""
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		size = float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		size = float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		size = v.Float()
+	case reflect.String:
+		size, what = float64(len(v.String())), " characters"
+	case reflect.Slice, reflect.Map:
+		size, what = float64(v.Len()), " items"
+	default:
+		return nil
+	}
+	if min, ok := rules["min"]; ok {
+		if n, err := strconv.ParseFloat(min, 64); err == nil && size < n {
+			return paramError(name, "parameter %s must be at least %s%s", name, min, what)
+		}
+	}
+	if max, ok := rules["max"]; ok {
+		if n, err := strconv.ParseFloat(max, 64); err == nil && size > n {
+			return paramError(name, "parameter %s must be at most %s%s", name, max, what)
+		}
+	}
+	return nil
+}
+
+func typeWord(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Interface {
+			return "an array"
+		}
+		return "an array of " + strings.TrimPrefix(strings.TrimPrefix(typeWord(t.Elem()), "a "), "an ") + "s"
+	case reflect.Pointer:
+		return typeWord(t.Elem())
+	}
+	return "an object"
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type searchArgs struct {
+	Path     string   `json:"path" validate:"required"`
+	MaxDepth int      `json:"max_depth" default:"10" validate:"min=1,max=100"`
+	Format   string   `json:"format" default:"text" validate:"oneof=text json"`
+	Hidden   bool     `json:"hidden"`
+	Globs    []string `json:"globs" validate:"max=2"`
+	internal string
+}
+
+func TestDecodeParams(t *testing.T) {
+	args, err := DecodeParams[searchArgs](map[string]interface{}{
+		"path":   "/tmp",
+		"hidden": true,
+		"globs":  []interface{}{"*.go"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, searchArgs{Path: "/tmp", MaxDepth: 10, Format: "text", Hidden: true, Globs: []string{"*.go"}}, args)
+
+	args, err = DecodeParams[searchArgs](map[string]interface{}{"path": "/tmp", "max_depth": float64(3), "format": nil})
+	require.NoError(t, err)
+	assert.Equal(t, 3, args.MaxDepth)
+	assert.Equal(t, "text", args.Format)
+
+	for _, tc := range []struct {
+		params map[string]interface{}
+		want   string
+	}{
+		{map[string]interface{}{}, "missing required parameter: path"},
+		{map[string]interface{}{"path": 1.0}, "parameter path must be a string"},
+		{map[string]interface{}{"path": "/", "max_depth": 2.5}, "parameter max_depth must be an integer"},
+		{map[string]interface{}{"path": "/", "max_depth": 0.0}, "parameter max_depth must be at least 1"},
+		{map[string]interface{}{"path": "/", "format": "xml"}, "parameter format must be one of text, json"},
+		{map[string]interface{}{"path": "/", "globs": []interface{}{"a", 1.0}}, "parameter globs must be an array of strings"},
+		{map[string]interface{}{"path": "/", "globs": []interface{}{"a", "b", "c"}}, "parameter globs must be at most 2 items"},
+	} {
+		_, err := DecodeParams[searchArgs](tc.params)
+		assert.EqualError(t, err, tc.want)
+	}
+}
+
+type scopeArgs struct {
+	Namespace string `json:"namespace" default:"default"`
+}
+
+type podArgs struct {
+	scopeArgs
+	Name string `json:"name" validate:"required"`
+}
+
+func TestDecodeParamsEmbedded(t *testing.T) {
+	args, err := DecodeParams[podArgs](map[string]interface{}{"name": "web"})
+	require.NoError(t, err)
+	assert.Equal(t, podArgs{scopeArgs: scopeArgs{Namespace: "default"}, Name: "web"}, args)
+
+	args, err = DecodeParams[podArgs](map[string]interface{}{"name": "web", "namespace": "kube-system"})
+	require.NoError(t, err)
+	assert.Equal(t, "kube-system", args.Namespace)
+
+	_, err = DecodeParams[podArgs](map[string]interface{}{"name": "web", "namespace": 1.0})
+	assert.EqualError(t, err, "parameter namespace must be a string")
+}
+
+func TestTypedHandler(t *testing.T) {
+	handler := TypedHandler(func(ctx context.Context, args searchArgs) (*ToolResult, error) {
+		return TextResult(args.Path), nil
+	})
+
+	result, err := handler(context.Background(), map[string]interface{}{"path": "/tmp"})
+	require.NoError(t, err)
+	assert.Equal(t, "/tmp", result.Content[0].Text)
+
+	_, err = handler(context.Background(), map[string]interface{}{})
+	assert.EqualError(t, err, "missing required parameter: path")
+}